@@ -5,8 +5,6 @@
 package gi
 
 import (
-	"log"
-
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
@@ -266,10 +264,10 @@ func (ac *Action) ConfigPartsShortcut(scIdx int) {
 // ConfigPartsButton sets the label, icon etc for the button
 func (ac *Action) ConfigPartsButton() {
 	config := kit.TypeAndNameList{}
-	icIdx, lbIdx := ac.ConfigPartsIconLabel(&config, string(ac.Icon), ac.Text)
+	icIdx, lbIdx := ac.ConfigPartsIconLabel(&config, string(ac.Icon), ac.LabelText())
 	indIdx := ac.ConfigPartsAddIndicator(&config, false) // default off
 	mods, updt := ac.Parts.ConfigChildren(config)
-	ac.ConfigPartsSetIconLabel(string(ac.Icon), ac.Text, icIdx, lbIdx)
+	ac.ConfigPartsSetIconLabel(string(ac.Icon), ac.LabelText(), icIdx, lbIdx)
 	ac.ConfigPartsIndicator(indIdx)
 	if mods {
 		ac.UpdateEnd(updt)
@@ -279,16 +277,16 @@ func (ac *Action) ConfigPartsButton() {
 // ConfigPartsMenuItem sets the label, icon, etc for action menu item
 func (ac *Action) ConfigPartsMenuItem() {
 	config := kit.TypeAndNameList{}
-	icIdx, lbIdx := ac.ConfigPartsIconLabel(&config, string(ac.Icon), ac.Text)
+	icIdx, lbIdx := ac.ConfigPartsIconLabel(&config, string(ac.Icon), ac.LabelText())
 	indIdx := ac.ConfigPartsAddIndicator(&config, false) // default off
 	scIdx := -1
 	if indIdx < 0 && ac.Shortcut != "" {
 		scIdx = ac.ConfigPartsAddShortcut(&config)
 	} else if ac.Shortcut != "" {
-		log.Printf("gi.Action shortcut cannot be used on a sub-menu for action: %v\n", ac.Text)
+		Logf(LogWarn, "gi.Action", "shortcut cannot be used on a sub-menu for action: %v", ac.Text)
 	}
 	mods, updt := ac.Parts.ConfigChildren(config)
-	ac.ConfigPartsSetIconLabel(string(ac.Icon), ac.Text, icIdx, lbIdx)
+	ac.ConfigPartsSetIconLabel(string(ac.Icon), ac.LabelText(), icIdx, lbIdx)
 	ac.ConfigPartsIndicator(indIdx)
 	ac.ConfigPartsShortcut(scIdx)
 	if mods {
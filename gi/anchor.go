@@ -0,0 +1,252 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+// Widget geometry
+
+// WinBounds returns the full bounds of this node in window pixel
+// coordinates, as of the last layout, accounting for any scrolling of the
+// layouts it is in -- unlike VisibleWinBounds, it is not clipped to the
+// visible region of its parents, so it can extend outside of them, and is
+// not empty when the node is scrolled out of view.
+func (nb *Node2DBase) WinBounds() image.Rectangle {
+	nb.BBoxMu.RLock()
+	bb := nb.ObjBBox
+	nb.BBoxMu.RUnlock()
+	if vp := nb.ViewportSafe(); vp != nil {
+		vp.BBoxMu.RLock()
+		bb = bb.Add(vp.WinBBox.Min)
+		vp.BBoxMu.RUnlock()
+	}
+	return bb
+}
+
+// VisibleWinBounds returns the visible bounds of this node in window pixel
+// coordinates, as of the last layout: its WinBounds clipped to the visible
+// region of its parents -- empty if it is not visible.
+func (nb *Node2DBase) VisibleWinBounds() image.Rectangle {
+	nb.BBoxMu.RLock()
+	defer nb.BBoxMu.RUnlock()
+	return nb.WinBBox
+}
+
+// ScreenBounds returns the full bounds of this node (see WinBounds) in
+// screen coordinates, in the OS window manager units used for window
+// positions (see Window.WinToScreen) -- empty if it is not in a window.
+func (nb *Node2DBase) ScreenBounds() image.Rectangle {
+	win := nb.ParentWindow()
+	if win == nil {
+		return image.Rectangle{}
+	}
+	bb := nb.WinBounds()
+	return image.Rectangle{win.WinToScreen(bb.Min), win.WinToScreen(bb.Max)}
+}
+
+// WinToScreen converts given window pixel coordinates into screen
+// coordinates, in the OS window manager units used for window positions,
+// which do not include any high DPI factors (see oswin.Window.WinSize)
+func (w *Window) WinToScreen(pt image.Point) image.Point {
+	if w.IsClosed() {
+		return pt
+	}
+	pos := w.OSWin.Position()
+	sz := w.OSWin.Size()
+	wsz := w.OSWin.WinSize()
+	if sz.X > 0 && sz.Y > 0 {
+		pt.X = pt.X * wsz.X / sz.X
+		pt.Y = pt.Y * wsz.Y / sz.Y
+	}
+	return pos.Add(pt)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+// Anchor
+
+// AnchorSides are the sides of a widget where a popup anchored to it
+// is placed (see AnchorPopup)
+type AnchorSides int32
+
+const (
+	// AnchorBelow places the popup below the widget, left-aligned with it
+	AnchorBelow AnchorSides = iota
+
+	// AnchorAbove places the popup above the widget, left-aligned with it
+	AnchorAbove
+
+	// AnchorRight places the popup to the right of the widget, top-aligned with it
+	AnchorRight
+
+	// AnchorLeft places the popup to the left of the widget, top-aligned with it
+	AnchorLeft
+
+	AnchorSidesN
+)
+
+//go:generate stringer -type=AnchorSides
+
+var KiT_AnchorSides = kit.Enums.AddEnum(AnchorSidesN, kit.NotBitFlag, nil)
+
+func (ev AnchorSides) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *AnchorSides) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// Anchor keeps a popup (e.g., a popover) attached to a side of a widget,
+// moving it as the widget moves through scrolling or layout changes --
+// see AnchorPopup.
+type Anchor struct {
+	Popup  *Viewport2D `desc:"the popup that is kept attached to the widget"`
+	Widget Node2D      `desc:"the widget that the popup is attached to"`
+	Side   AnchorSides `desc:"side of the widget where the popup is placed -- it goes on the opposite side if it does not fit in the window there"`
+	Offset image.Point `desc:"offset of the popup from its position on the Side of the widget, in window pixels"`
+}
+
+// AnchorPopup attaches given popup to given side of given widget, in the
+// widget's window: the popup is positioned there now, and is moved each
+// time the window is updated while it stays open, so that it follows the
+// widget through scrolling and layout changes -- call after the popup is
+// configured and sized, e.g., just after SetNextPopup.  Returns the Anchor,
+// which can be modified (e.g., its Offset) -- returns nil if the widget is
+// not in a window.
+func AnchorPopup(pop *Viewport2D, widget Node2D, side AnchorSides) *Anchor {
+	win := widget.AsNode2D().ParentWindow()
+	if win == nil {
+		return nil
+	}
+	an := &Anchor{Popup: pop, Widget: widget, Side: side}
+	pop.Geom.Pos = an.PopupPos(win)
+	win.PopMu.Lock()
+	win.Anchors = append(win.Anchors, an)
+	win.PopMu.Unlock()
+	return an
+}
+
+// PopupPos returns the position of the popup in given window: on the Side
+// of the current WinBounds of the widget, or the opposite side if it does
+// not fit there, plus the Offset, and kept within the window.
+func (an *Anchor) PopupPos(win *Window) image.Point {
+	bb := an.Widget.AsNode2D().WinBounds()
+	psz := an.Popup.Geom.Size
+	if an.Popup.Pixels != nil {
+		psz = an.Popup.Pixels.Bounds().Size()
+	}
+	wsz := win.Viewport.Geom.Size
+	var pos image.Point
+	switch an.Side {
+	case AnchorBelow, AnchorAbove:
+		pos.X = bb.Min.X
+		below := bb.Max.Y + an.Offset.Y
+		above := bb.Min.Y - psz.Y - an.Offset.Y
+		if an.Side == AnchorBelow {
+			pos.Y = below
+			if below+psz.Y > wsz.Y && above >= 0 {
+				pos.Y = above
+			}
+		} else {
+			pos.Y = above
+			if above < 0 && below+psz.Y <= wsz.Y {
+				pos.Y = below
+			}
+		}
+		pos.X += an.Offset.X
+	default:
+		pos.Y = bb.Min.Y
+		right := bb.Max.X + an.Offset.X
+		left := bb.Min.X - psz.X - an.Offset.X
+		if an.Side == AnchorRight {
+			pos.X = right
+			if right+psz.X > wsz.X && left >= 0 {
+				pos.X = left
+			}
+		} else {
+			pos.X = left
+			if left < 0 && right+psz.X <= wsz.X {
+				pos.X = right
+			}
+		}
+		pos.Y += an.Offset.Y
+	}
+	pos.X = ints.MaxInt(0, ints.MinInt(pos.X, wsz.X-psz.X)) // fit
+	pos.Y = ints.MaxInt(0, ints.MinInt(pos.Y, wsz.Y-psz.Y))
+	return pos
+}
+
+// MovePopup moves the popup to given position in the window, updating
+// its window bounding box and those of all of its children, which are
+// used for event processing, and its drawing region in given window
+// -- must be called under the window UpMu lock
+func (an *Anchor) MovePopup(win *Window, pos image.Point) {
+	pvp := an.Popup
+	pvp.Geom.Pos = pos
+	pvp.BBoxMu.Lock()
+	pvp.WinBBox = pvp.Pixels.Bounds().Add(pos)
+	wbb := pvp.WinBBox
+	pvp.BBoxMu.Unlock()
+	pvp.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		if k == pvp.This() {
+			return ki.Continue
+		}
+		ni, _ := KiToNode2D(k)
+		if ni == nil {
+			return ki.Break
+		}
+		ni.AsNode2D().SetWinBBox()
+		return ki.Continue
+	})
+	if win.PopDraws.Nodes != nil {
+		if idx, has := win.PopDraws.Nodes.IdxByKey(pvp.AsGiNode()); has {
+			win.PopDraws.SetWinBBox(win.PopDraws.Idx(idx), wbb)
+		}
+	}
+}
+
+// isOpen returns true if the popup is open (or about to be) in given
+// window, and the widget still exists -- must be called under PopMu
+func (an *Anchor) isOpen(win *Window) bool {
+	if an.Popup.This() == nil || an.Widget.This() == nil || an.Widget.IsDeleted() {
+		return false
+	}
+	pop := an.Popup.This()
+	return win.NextPopup == pop || an.Popup.Par == win.This()
+}
+
+// UpdateAnchors moves the popups attached to widgets with AnchorPopup
+// to the current positions of the widgets, and removes the anchors of
+// popups that have closed -- called at the start of Publish.
+func (w *Window) UpdateAnchors() {
+	w.PopMu.Lock()
+	defer w.PopMu.Unlock()
+	if len(w.Anchors) == 0 {
+		return
+	}
+	w.UpMu.Lock()
+	defer w.UpMu.Unlock()
+	n := 0
+	for _, an := range w.Anchors {
+		if !an.isOpen(w) {
+			continue
+		}
+		w.Anchors[n] = an
+		n++
+		if an.Popup.Pixels == nil {
+			continue
+		}
+		pos := an.PopupPos(w)
+		if pos != an.Popup.Geom.Pos {
+			an.MovePopup(w, pos)
+		}
+	}
+	for i := n; i < len(w.Anchors); i++ {
+		w.Anchors[i] = nil
+	}
+	w.Anchors = w.Anchors[:n]
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// IconCacheOn determines whether rendered icons are cached in IconCache,
+// so that all the icons with the same name, size and colors (e.g., in tree
+// views and toolbars) are only rasterized once
+var IconCacheOn = true
+
+// IconCache is the cache of the rasterizations of icons, keyed by the icon
+// name, size and paint parameters they were rendered with -- see
+// IconCacheOn.  The cached pixels are copied into the viewport image of
+// each icon, which is uploaded to the window as usual.
+var IconCache = Atlas{PageSize: image.Point{512, 512}, MaxPages: 8}
+
+// AtlasItem is the location of an image within an Atlas
+type AtlasItem struct {
+	Page int             `desc:"index of the page image"`
+	Rect image.Rectangle `desc:"region within the page image"`
+}
+
+// Atlas packs many small images (e.g., rendered icons) into a few larger
+// page images, using simple shelf packing, and looks them up by key.  When
+// all MaxPages pages are full, the atlas is reset and refilled as images
+// are added again.  Images returned by Get remain valid after a reset.
+// The pages are ordinary in-memory images -- see IconCache.
+type Atlas struct {
+	PageSize image.Point          `desc:"size of each page image"`
+	MaxPages int                  `desc:"maximum number of pages -- the atlas is reset when they are all full"`
+	Pages    []*image.RGBA        `desc:"the page images"`
+	Items    map[string]AtlasItem `desc:"location of each image by key"`
+	ShelfPos image.Point          `desc:"starting position of the next image on the current shelf of the last page"`
+	ShelfHt  int                  `desc:"height of the current shelf"`
+	Mu       sync.Mutex           `desc:"mutex protecting access to the atlas"`
+}
+
+// Get returns the image for given key, and true if it is in the atlas
+func (at *Atlas) Get(key string) (*image.RGBA, bool) {
+	at.Mu.Lock()
+	defer at.Mu.Unlock()
+	it, ok := at.Items[key]
+	if !ok {
+		return nil, false
+	}
+	return at.Pages[it.Page].SubImage(it.Rect).(*image.RGBA), true
+}
+
+// Add copies the given region of given image into the atlas under given
+// key, returning false if the image is too large to fit on a page
+func (at *Atlas) Add(key string, img image.Image, r image.Rectangle) bool {
+	sz := r.Size()
+	if sz.X <= 0 || sz.Y <= 0 || sz.X > at.PageSize.X || sz.Y > at.PageSize.Y {
+		return false
+	}
+	at.Mu.Lock()
+	defer at.Mu.Unlock()
+	if _, has := at.Items[key]; has {
+		return true
+	}
+	pos, ok := at.alloc(sz)
+	if !ok {
+		at.reset()
+		if pos, ok = at.alloc(sz); !ok {
+			return false
+		}
+	}
+	pg := len(at.Pages) - 1
+	dr := image.Rectangle{Min: pos, Max: pos.Add(sz)}
+	draw.Draw(at.Pages[pg], dr, img, r.Min, draw.Src)
+	if at.Items == nil {
+		at.Items = make(map[string]AtlasItem)
+	}
+	at.Items[key] = AtlasItem{Page: pg, Rect: dr}
+	return true
+}
+
+// alloc returns the position for an image of given size on the last page,
+// starting a new shelf or page as needed -- returns false if all MaxPages
+// are full
+func (at *Atlas) alloc(sz image.Point) (image.Point, bool) {
+	if len(at.Pages) > 0 {
+		if at.ShelfPos.X+sz.X > at.PageSize.X { // next shelf
+			at.ShelfPos = image.Point{0, at.ShelfPos.Y + at.ShelfHt}
+			at.ShelfHt = 0
+		}
+		if at.ShelfPos.Y+sz.Y <= at.PageSize.Y {
+			pos := at.ShelfPos
+			at.ShelfPos.X += sz.X
+			if sz.Y > at.ShelfHt {
+				at.ShelfHt = sz.Y
+			}
+			return pos, true
+		}
+	}
+	if len(at.Pages) >= at.MaxPages {
+		return image.Point{}, false
+	}
+	at.Pages = append(at.Pages, image.NewRGBA(image.Rectangle{Max: at.PageSize}))
+	at.ShelfPos = image.Point{sz.X, 0}
+	at.ShelfHt = sz.Y
+	return image.Point{}, true
+}
+
+// Reset removes all the images from the atlas
+func (at *Atlas) Reset() {
+	at.Mu.Lock()
+	at.reset()
+	at.Mu.Unlock()
+}
+
+func (at *Atlas) reset() {
+	at.Pages = nil
+	at.Items = nil
+	at.ShelfPos = image.Point{}
+	at.ShelfHt = 0
+}
+
+// atlasCache is the index of an Atlas saved by SaveCache
+type atlasCache struct {
+	Key      string
+	NPages   int
+	Items    map[string]AtlasItem
+	ShelfPos image.Point
+	ShelfHt  int
+}
+
+// atlasCacheIndex is the name of the index file of a saved Atlas
+const atlasCacheIndex = "atlas.json"
+
+// SaveCache saves the atlas in given directory (which is created if needed),
+// as a png file for each page and an index, under given key -- OpenCache
+// only opens it with the same key, e.g., for the same version and DPI
+func (at *Atlas) SaveCache(dir, key string) error {
+	at.Mu.Lock()
+	defer at.Mu.Unlock()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i, pg := range at.Pages {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("page%d.png", i)))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, pg)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	ac := atlasCache{Key: key, NPages: len(at.Pages), Items: at.Items, ShelfPos: at.ShelfPos, ShelfHt: at.ShelfHt}
+	b, err := json.Marshal(&ac)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, atlasCacheIndex), b, 0644)
+}
+
+// OpenCache replaces the contents of the atlas with those saved by SaveCache
+// in given directory, if they were saved under the same key and page size
+// -- returns an error, leaving the atlas unchanged, otherwise
+func (at *Atlas) OpenCache(dir, key string) error {
+	b, err := os.ReadFile(filepath.Join(dir, atlasCacheIndex))
+	if err != nil {
+		return err
+	}
+	ac := atlasCache{}
+	if err := json.Unmarshal(b, &ac); err != nil {
+		return err
+	}
+	if ac.Key != key {
+		return fmt.Errorf("gi.Atlas: cache in %v is for: %v, not: %v", dir, ac.Key, key)
+	}
+	if ac.NPages > at.MaxPages {
+		return fmt.Errorf("gi.Atlas: cache in %v has too many pages: %v", dir, ac.NPages)
+	}
+	pages := make([]*image.RGBA, ac.NPages)
+	for i := range pages {
+		f, err := os.Open(filepath.Join(dir, fmt.Sprintf("page%d.png", i)))
+		if err != nil {
+			return err
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if img.Bounds() != (image.Rectangle{Max: at.PageSize}) {
+			return fmt.Errorf("gi.Atlas: cache in %v has different page size: %v", dir, img.Bounds().Size())
+		}
+		rgba, ok := img.(*image.RGBA)
+		if !ok {
+			rgba = image.NewRGBA(img.Bounds())
+			draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
+		}
+		pages[i] = rgba
+	}
+	at.Mu.Lock()
+	at.Pages = pages
+	at.Items = ac.Items
+	at.ShelfPos = ac.ShelfPos
+	at.ShelfHt = ac.ShelfHt
+	at.Mu.Unlock()
+	return nil
+}
@@ -0,0 +1,409 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/ki"
+)
+
+// AutomationTimeout is how long an automation command waits for the event
+// loop of a window to run it
+var AutomationTimeout = 5 * time.Second
+
+// AutomationRequest is a request to the automation endpoint
+type AutomationRequest struct {
+	Cmd  string `desc:"command: tree, click, or set-text"`
+	ID   string `desc:"automation ID (WidgetBase.AutoID) of the widget, for click and set-text"`
+	Text string `desc:"text or value to set, for set-text"`
+}
+
+// AutomationResponse is the response to an AutomationRequest
+type AutomationResponse struct {
+	Error   string              `json:",omitempty" desc:"error in performing the command, if any"`
+	Windows []*AutomationWindow `json:",omitempty" desc:"windows and their widget trees, for the tree command"`
+}
+
+// AutomationWindow is a window, with its widget tree, as dumped by the
+// tree command of the automation endpoint
+type AutomationWindow struct {
+	Name  string          `desc:"name of the window"`
+	Title string          `desc:"title of the window"`
+	Tree  *AutomationNode `desc:"widget tree of the main viewport of the window"`
+	Popup *AutomationNode `json:",omitempty" desc:"widget tree of the current popup (menu, completion, etc), if any"`
+}
+
+// AutomationNode is a node of the widget tree, as dumped by the tree
+// command of the automation endpoint
+type AutomationNode struct {
+	ID    string            `json:",omitempty" desc:"automation ID of the widget (WidgetBase.AutoID), if set"`
+	Name  string            `desc:"name of the node"`
+	Type  string            `desc:"type of the node"`
+	Path  string            `desc:"path of the node in the tree"`
+	Geom  image.Rectangle   `desc:"bounding box of the node in window coordinates, as rendered"`
+	Value string            `json:",omitempty" desc:"text or value of the widget, if any -- e.g., label text, text field text, slider value, button label"`
+	State []string          `json:",omitempty" desc:"state flags of the node: invisible, inactive, focus, selected, checked"`
+	Kids  []*AutomationNode `json:",omitempty" desc:"children of the node"`
+}
+
+// AutomationValuer is implemented by widgets that report their value in
+// the widget tree of the automation endpoint, and can have it set by the
+// set-text command -- the standard widgets in gi are supported directly
+type AutomationValuer interface {
+	// AutomationValue returns the current text or value of the widget
+	AutomationValue() string
+
+	// SetAutomationValue sets the text or value of the widget from given
+	// string, as if entered by the user, including sending signals
+	SetAutomationValue(val string) error
+}
+
+// automationListener is the listener of the automation endpoint, guarded
+// by automationMu
+var automationListener net.Listener
+
+// automationMu guards the automationListener
+var automationMu sync.Mutex
+
+// AutomationSocket returns the name of the socket used by the automation
+// endpoint of the app with given name, for the current user, in the
+// SocketDir
+func AutomationSocket(appName string) (string, error) {
+	return appSocket("auto-", appName)
+}
+
+// StartAutomation starts the automation endpoint, which lets external tools
+// -- test drivers, UI automation and accessibility tools -- inspect and
+// drive the GUI of the app, listening on given unix domain socket (also
+// supported on Windows), or on AutomationSocket(AppName()) if empty.  Each
+// request is a line of JSON (AutomationRequest), and gets a line of JSON
+// in response (AutomationResponse).  The commands are:
+//
+//   - tree: dumps the widget trees of all windows, with the automation IDs
+//     (WidgetBase.AutoID), names, types, geometry, values and state of the
+//     widgets (AutomationNode).
+//   - click: clicks the widget with given ID, by sending a mouse press and
+//     release at its center to its window.
+//   - set-text: sets the text or value of the widget with given ID, as if
+//     entered by the user -- supported by TextField, SpinBox, Slider,
+//     ComboBox (by the label of the item), and widgets that implement
+//     AutomationValuer.
+//
+// For example, with the socat tool:
+//
+//	echo '{"Cmd": "click", "ID": "save"}' | socat - UNIX-CONNECT:$XDG_RUNTIME_DIR/GoGi/auto-myapp.sock
+//
+// The default socket is in the private SocketDir of the user, and the
+// socket is made accessible only to the user once created, so a socket
+// given explicitly should also be in a directory that only the user has
+// access to.  The endpoint should
+// only be started when needed, e.g., by a command-line flag of the app for
+// testing, as any program of the user can then drive the app.
+func StartAutomation(sock string) error {
+	StopAutomation()
+	if sock == "" {
+		var err error
+		sock, err = AutomationSocket(AppName())
+		if err != nil {
+			Logf(LogError, "gi.StartAutomation", "no socket: %v", err)
+			return err
+		}
+	}
+	ln, err := listenSocket(sock)
+	if err != nil {
+		Logf(LogError, "gi.StartAutomation", "could not listen on socket: %v", err)
+		return err
+	}
+	automationMu.Lock()
+	if automationListener != nil { // started again meanwhile
+		automationListener.Close()
+	}
+	automationListener = ln
+	automationMu.Unlock()
+	go serveAutomation(ln)
+	return nil
+}
+
+// StopAutomation stops the automation endpoint, if started
+func StopAutomation() {
+	automationMu.Lock()
+	defer automationMu.Unlock()
+	if automationListener != nil {
+		automationListener.Close()
+		automationListener = nil
+	}
+}
+
+// serveAutomation accepts the connections of automation tools, until the
+// listener is closed
+func serveAutomation(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go handleAutomation(conn)
+	}
+}
+
+// handleAutomation performs the requests on given connection, one per
+// line, until it is closed
+func handleAutomation(conn net.Conn) {
+	defer conn.Close()
+	scan := bufio.NewScanner(conn)
+	scan.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	enc := json.NewEncoder(conn)
+	for scan.Scan() {
+		ln := strings.TrimSpace(scan.Text())
+		if ln == "" {
+			continue
+		}
+		req := &AutomationRequest{}
+		resp := &AutomationResponse{}
+		if err := json.Unmarshal([]byte(ln), req); err != nil {
+			resp.Error = err.Error()
+		} else if err := req.Run(resp); err != nil {
+			resp.Error = err.Error()
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// Run performs the request, setting the response
+func (ar *AutomationRequest) Run(resp *AutomationResponse) error {
+	switch ar.Cmd {
+	case "tree":
+		for _, w := range automationWindows() {
+			aw := &AutomationWindow{Name: w.Nm, Title: w.Title}
+			err := automationRun(w, func() {
+				if w.Viewport != nil {
+					aw.Tree = NewAutomationNode(w.Viewport.This())
+				}
+				if w.Popup != nil {
+					aw.Popup = NewAutomationNode(w.Popup)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			resp.Windows = append(resp.Windows, aw)
+		}
+		return nil
+	case "click":
+		return automationWidget(ar.ID, func(w *Window, wb *WidgetBase) error {
+			if !wb.IsVisible() {
+				return fmt.Errorf("widget is not visible: %v", ar.ID)
+			}
+			pos := wb.This().(Node2D).ContextMenuPos()
+			for _, act := range []mouse.Actions{mouse.Press, mouse.Release} {
+				me := &mouse.Event{Where: pos, Button: mouse.Left, Action: act}
+				me.Init()
+				w.OSWin.Send(me)
+			}
+			return nil
+		})
+	case "set-text":
+		return automationWidget(ar.ID, func(w *Window, wb *WidgetBase) error {
+			return SetAutomationValue(wb.This(), ar.Text)
+		})
+	}
+	return fmt.Errorf("unknown automation command: %q -- must be tree, click, or set-text", ar.Cmd)
+}
+
+// automationWindows returns the open windows
+func automationWindows() []*Window {
+	WindowGlobalMu.Lock()
+	defer WindowGlobalMu.Unlock()
+	wins := make([]*Window, 0, len(AllWindows))
+	for _, w := range AllWindows {
+		if !w.IsClosed() {
+			wins = append(wins, w)
+		}
+	}
+	return wins
+}
+
+// automationRun runs given function on the event loop of given window,
+// waiting for it for up to AutomationTimeout
+func automationRun(w *Window, fun func()) error {
+	done := make(chan struct{})
+	w.RunOnNextFrame(func() {
+		fun()
+		close(done)
+	})
+	select {
+	case <-done:
+		return nil
+	case <-time.After(AutomationTimeout):
+		return fmt.Errorf("timed out waiting for window: %v", w.Nm)
+	}
+}
+
+// automationWidget runs given function on the event loop of the window of
+// the widget with given automation ID, with that widget
+func automationWidget(id string, fun func(w *Window, wb *WidgetBase) error) error {
+	if id == "" {
+		return errors.New("no widget ID given")
+	}
+	for _, w := range automationWindows() {
+		found := false
+		var err error
+		rerr := automationRun(w, func() {
+			var wb *WidgetBase
+			if w.Popup != nil {
+				wb = FindAutoID(w.Popup, id)
+			}
+			if wb == nil && w.Viewport != nil {
+				wb = FindAutoID(w.Viewport.This(), id)
+			}
+			if wb != nil {
+				found = true
+				err = fun(w, wb)
+			}
+		})
+		if rerr != nil {
+			return rerr
+		}
+		if found {
+			return err
+		}
+	}
+	return fmt.Errorf("no widget with ID: %v", id)
+}
+
+// FindAutoID returns the widget with given automation ID (AutoID) in the
+// tree under given node, including it, or nil if there is none
+func FindAutoID(root ki.Ki, id string) *WidgetBase {
+	var fwb *WidgetBase
+	root.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		if fwb != nil {
+			return ki.Break
+		}
+		if wi, ok := k.(Node2D); ok {
+			if wb := wi.AsWidget(); wb != nil && wb.AutoID == id {
+				fwb = wb
+				return ki.Break
+			}
+		}
+		return ki.Continue
+	})
+	return fwb
+}
+
+// NewAutomationNode returns the automation tree of given node, with its
+// children -- must be called on the event loop of its window
+func NewAutomationNode(k ki.Ki) *AutomationNode {
+	an := &AutomationNode{Name: k.Name(), Type: ki.Type(k).Name(), Path: k.Path()}
+	ni, nb := KiToNode2D(k)
+	if ni == nil {
+		return an
+	}
+	nb.BBoxMu.RLock()
+	an.Geom = nb.WinBBox
+	nb.BBoxMu.RUnlock()
+	if wb := ni.AsWidget(); wb != nil {
+		an.ID = wb.AutoID
+	}
+	an.Value, _ = AutomationValue(k)
+	if nb.IsInvisible() {
+		an.State = append(an.State, "invisible")
+	}
+	if nb.IsInactive() {
+		an.State = append(an.State, "inactive")
+	}
+	if nb.HasFocus() {
+		an.State = append(an.State, "focus")
+	}
+	if nb.IsSelected() {
+		an.State = append(an.State, "selected")
+	}
+	if bw, ok := k.(ButtonWidget); ok {
+		if bb := bw.AsButtonBase(); bb.IsCheckable() && bb.IsChecked() {
+			an.State = append(an.State, "checked")
+		}
+	}
+	for _, kid := range *k.Children() {
+		an.Kids = append(an.Kids, NewAutomationNode(kid))
+	}
+	return an
+}
+
+// AutomationValue returns the text or value of given widget, as reported
+// in the widget tree of the automation endpoint, and false if it has none
+func AutomationValue(k ki.Ki) (string, bool) {
+	switch w := k.(type) {
+	case AutomationValuer:
+		return w.AutomationValue(), true
+	case *TextField:
+		return string(w.EditTxt), true
+	case *Label:
+		return w.Text, true
+	case *SpinBox:
+		return strconv.FormatFloat(float64(w.Value), 'g', -1, 32), true
+	case *Slider:
+		return strconv.FormatFloat(float64(w.Value), 'g', -1, 32), true
+	case *ScrollBar:
+		return strconv.FormatFloat(float64(w.Value), 'g', -1, 32), true
+	case *ComboBox:
+		return ToLabel(w.CurVal), true
+	case ButtonWidget:
+		return w.AsButtonBase().Text, true
+	}
+	return "", false
+}
+
+// SetAutomationValue sets the text or value of given widget from given
+// string, as if entered by the user, for the set-text command of the
+// automation endpoint -- must be called on the event loop of its window
+func SetAutomationValue(k ki.Ki, val string) error {
+	parseVal := func() (float32, error) {
+		v, err := strconv.ParseFloat(strings.TrimSpace(val), 32)
+		return float32(v), err
+	}
+	switch w := k.(type) {
+	case AutomationValuer:
+		return w.SetAutomationValue(val)
+	case *TextField:
+		w.SetText(val)
+		w.TextFieldSig.Emit(w.This(), int64(TextFieldDone), w.Txt)
+		return nil
+	case *SpinBox:
+		v, err := parseVal()
+		if err != nil {
+			return err
+		}
+		w.SetValueAction(v)
+		return nil
+	case *Slider:
+		v, err := parseVal()
+		if err != nil {
+			return err
+		}
+		w.SetValueAction(v)
+		return nil
+	case *ComboBox:
+		for i, it := range w.Items {
+			if ToLabel(it) == val {
+				w.SelectItemAction(i)
+				return nil
+			}
+		}
+		return fmt.Errorf("no item in combo box: %v", val)
+	}
+	return fmt.Errorf("cannot set the text of widget of type: %v", ki.Type(k).Name())
+}
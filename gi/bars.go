@@ -182,7 +182,7 @@ func (mb *MenuBar) ConfigMenus(menus []string) {
 	typ := KiT_Action // note: could pass in action type to make it more flexible, but..
 	for i, m := range menus {
 		tnl[i].Type = typ
-		tnl[i].Name = m
+		tnl[i].Name = StripMnemonic(m)
 	}
 	tnl[sz].Type = KiT_Stretch
 	tnl[sz].Name = "menstr"
@@ -236,7 +236,7 @@ func (mb *MenuBar) UpdateMainMenu(win *Window) {
 	for _, mi := range mb.Kids {
 		if ki.TypeEmbeds(mi, KiT_Action) {
 			ac := mi.Embed(KiT_Action).(*Action)
-			subm := osmm.AddSubMenu(mm, ac.Text)
+			subm := osmm.AddSubMenu(mm, StripMnemonic(ac.Text))
 			mb.SetMainMenuSub(osmm, subm, ac)
 		}
 	}
@@ -267,11 +267,12 @@ func (mb *MenuBar) SetMainMenuSub(osmm oswin.MainMenu, subm oswin.Menu, am *Acti
 		if ki.TypeEmbeds(mi, KiT_Action) {
 			ac := mi.Embed(KiT_Action).(*Action)
 			if len(ac.Menu) > 0 {
-				ssubm := osmm.AddSubMenu(subm, ac.Text)
+				ssubm := osmm.AddSubMenu(subm, StripMnemonic(ac.Text))
 				mb.SetMainMenuSub(osmm, ssubm, ac)
 			} else {
-				mid := osmm.AddItem(subm, ac.Text, string(ac.Shortcut), i, ac.IsActive())
-				mb.OSMainMenus[ac.Text] = ac
+				txt := StripMnemonic(ac.Text)
+				mid := osmm.AddItem(subm, txt, string(ac.Shortcut), i, ac.IsActive())
+				mb.OSMainMenus[txt] = ac
 				ac.SetProp("__OSMainMenuItemID", mid)
 			}
 		} else if _, ok := mi.(*Separator); ok {
@@ -341,7 +342,7 @@ var ToolBarProps = ki.Props{
 func (tb *ToolBar) AddAction(opts ActOpts, sigTo ki.Ki, fun ki.RecvFunc) *Action {
 	nm := opts.Name
 	if nm == "" {
-		nm = opts.Label
+		nm = StripMnemonic(opts.Label)
 	}
 	if nm == "" {
 		nm = opts.Icon
@@ -12,7 +12,6 @@ import (
 	"image"
 	"image/jpeg"
 	"image/png"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -37,9 +36,13 @@ import (
 // can grab the image of another vp and show that
 type Bitmap struct {
 	WidgetBase
-	Filename FileName    `desc:"file name of image loaded -- set by OpenImage"`
-	Size     image.Point `desc:"size of the image"`
-	Pixels   *image.RGBA `copy:"-" view:"-" xml:"-" json:"-" desc:"the bitmap image"`
+	Filename  FileName    `desc:"file name of image loaded -- set by OpenImage"`
+	Size      image.Point `desc:"size of the image"`
+	Pixels    *image.RGBA `copy:"-" view:"-" xml:"-" json:"-" desc:"the bitmap image"`
+	TintPix   *image.RGBA `copy:"-" view:"-" xml:"-" json:"-" desc:"the bitmap image with the TintParams applied, if any -- see RenderPixels"`
+	RendTint  TintParams  `copy:"-" view:"-" xml:"-" json:"-" desc:"tint parameters used for TintPix"`
+	ZoomXForm mat32.Mat2  `copy:"-" view:"-" xml:"-" json:"-" desc:"transform from image to display coordinates, relative to the bitmap position, set by a ZoomPanArea -- none if zero"`
+	Loading   bool        `copy:"-" xml:"-" json:"-" desc:"if true, a shimmering skeleton placeholder is shown in place of the image, while it is loading -- see SetLoading and OpenImageAsync"`
 }
 
 var KiT_Bitmap = kit.Types.AddType(&Bitmap{}, BitmapProps)
@@ -64,6 +67,7 @@ func (bm *Bitmap) SetSize(nwsz image.Point) {
 		return
 	}
 	bm.Size = nwsz // always make sure
+	bm.TintPix = nil
 	if bm.Pixels != nil && bm.Pixels.Bounds().Size() == nwsz {
 		return
 	}
@@ -83,7 +87,7 @@ func (bm *Bitmap) LayoutToImgSize() {
 func (bm *Bitmap) OpenImage(filename FileName, width, height float32) error {
 	img, err := OpenImage(string(filename))
 	if err != nil {
-		log.Printf("gi.Bitmap.OpenImage -- could not open file: %v, err: %v\n", filename, err)
+		Logf(LogError, "gi.Bitmap", "OpenImage -- could not open file: %v, err: %v", filename, err)
 		return err
 	}
 	bm.Filename = filename
@@ -91,6 +95,32 @@ func (bm *Bitmap) OpenImage(filename FileName, width, height float32) error {
 	return nil
 }
 
+// OpenImageAsync opens an image for the bitmap in a separate goroutine, as
+// in OpenImage, showing a skeleton placeholder while it is loading -- the
+// width and height properties should be set for the size of the
+// placeholder, e.g., the expected size of the image.
+func (bm *Bitmap) OpenImageAsync(filename FileName, width, height float32) {
+	bm.SetLoading(true)
+	go func() {
+		bm.OpenImage(filename, width, height)
+		bm.SetLoading(false)
+	}()
+}
+
+// SetLoading sets whether the image is loading, showing a shimmering
+// skeleton placeholder in place of it while it is
+func (bm *Bitmap) SetLoading(loading bool) {
+	if bm.Loading == loading {
+		return
+	}
+	updt := bm.UpdateStart()
+	bm.Loading = loading
+	if !loading {
+		StopRenderAnim(bm.This().(Node2D))
+	}
+	bm.UpdateEnd(updt)
+}
+
 // SetImage sets an image for the bitmap , and resizes to the size of the image
 // or the specified size -- pass 0 for width and/or height to use the actual image size
 // for that dimension.  Copies from given image into internal image for this bitmap.
@@ -128,13 +158,64 @@ func (bm *Bitmap) GrabRenderFrom(nii Node2D) {
 	if img != nil {
 		bm.Pixels = img
 		bm.Size = bm.Pixels.Bounds().Size()
+		bm.TintPix = nil
+	}
+}
+
+// RenderPixels returns the image to render, with the "tint" and inactive
+// desaturation style properties applied (see TintParams) -- the tinted
+// image is cached until the image or the parameters change.
+func (bm *Bitmap) RenderPixels() *image.RGBA {
+	tp := TintParamsFor(&bm.Node2DBase)
+	if tp.IsNil() || bm.Pixels == nil {
+		bm.TintPix = nil
+		return bm.Pixels
+	}
+	if bm.TintPix != nil && bm.RendTint == tp {
+		return bm.TintPix
+	}
+	bm.TintPix = clone.AsRGBA(bm.Pixels)
+	bm.RendTint = tp
+	TintImage(bm.TintPix, bm.TintPix.Bounds(), tp)
+	return bm.TintPix
+}
+
+// SetZoomXForm sets the ZoomXForm, for the Zoomable interface
+func (bm *Bitmap) SetZoomXForm(xf mat32.Mat2) {
+	bm.ZoomXForm = xf
+}
+
+// ZoomContentSize returns the Size, for the Zoomable interface
+func (bm *Bitmap) ZoomContentSize() mat32.Vec2 {
+	return mat32.NewVec2FmPoint(bm.Size)
+}
+
+// AspectRatio returns the aspect ratio of the image, for the AspectRatioer
+// interface: with an "aspect-ratio" style of auto, the bitmap keeps the
+// proportions of its image, which is scaled to the allocated size
+func (bm *Bitmap) AspectRatio() float32 {
+	if bm.Size.X <= 0 || bm.Size.Y <= 0 {
+		return 0
 	}
+	return float32(bm.Size.X) / float32(bm.Size.Y)
 }
 
 func (bm *Bitmap) DrawIntoViewport(parVp *Viewport2D) {
-	if bm.Pixels == nil {
+	pix := bm.RenderPixels()
+	if pix == nil {
+		return
+	}
+	if bm.ZoomXForm != (mat32.Mat2{}) && !bm.ZoomXForm.IsIdentity() {
+		bm.DrawZoomed(parVp, pix)
 		return
 	}
+	if bm.Sty.Layout.AspectRatio != 0 && bm.Size.X > 0 && bm.Size.Y > 0 {
+		asz := bm.LayState.Alloc.Size
+		if asz.X > 0 && asz.Y > 0 && asz != mat32.NewVec2FmPoint(bm.Size) {
+			bm.DrawXForm(parVp, pix, mat32.Scale2D(asz.X/float32(bm.Size.X), asz.Y/float32(bm.Size.Y)))
+			return
+		}
+	}
 	pos := bm.LayState.Alloc.Pos.ToPointCeil()
 	max := pos.Add(bm.Size)
 	r := image.Rectangle{Min: pos, Max: max}
@@ -150,7 +231,45 @@ func (bm *Bitmap) DrawIntoViewport(parVp *Viewport2D) {
 		}
 		r = nr
 	}
-	draw.Draw(parVp.Pixels, r, bm.Pixels, sp, draw.Over)
+	draw.Draw(parVp.Pixels, r, pix, sp, draw.Over)
+}
+
+// DrawZoomed draws given pixels into the viewport with the ZoomXForm,
+// clipped to the allocated area of the bitmap
+func (bm *Bitmap) DrawZoomed(parVp *Viewport2D, pix *image.RGBA) {
+	bm.DrawXForm(parVp, pix, bm.ZoomXForm)
+}
+
+// DrawXForm draws given pixels into the viewport with given transform,
+// relative to the bitmap position, clipped to the allocated area of the
+// bitmap -- used for zooming, and for scaling the image to the allocated
+// size when it has an aspect-ratio style
+func (bm *Bitmap) DrawXForm(parVp *Viewport2D, pix *image.RGBA, xf mat32.Mat2) {
+	pos := bm.LayState.Alloc.Pos
+	r := image.Rectangle{Min: pos.ToPointCeil(), Max: pos.Add(bm.LayState.Alloc.Size).ToPointFloor()}
+	if bm.Par != nil {
+		pni, _ := KiToNode2D(bm.Par)
+		r = r.Intersect(pni.ChildrenBBox2D())
+	}
+	if r.Empty() {
+		return
+	}
+	m := xf.Mul(mat32.Translate2D(pos.X, pos.Y))
+	s2d := f64.Aff3{float64(m.XX), float64(m.XY), float64(m.X0), float64(m.YX), float64(m.YY), float64(m.Y0)}
+	draw.BiLinear.Transform(parVp.Pixels.SubImage(r).(*image.RGBA), s2d, pix, pix.Bounds(), draw.Over, nil)
+}
+
+// RenderLoading renders the skeleton placeholder shown while Loading,
+// highlighted relative to its background, as for Skeletons
+func (bm *Bitmap) RenderLoading() {
+	rs, _, st := bm.RenderLock()
+	clr := Prefs.Colors.Background.Highlight(10)
+	if !st.Font.BgColor.IsNil() {
+		clr = st.Font.BgColor.Color.Highlight(10)
+	}
+	RenderSkeletonBox(rs, bm.LayState.Alloc.Pos, bm.LayState.Alloc.Size, clr, st.Border.Radius.Dots)
+	bm.RenderUnlock(rs)
+	StartRenderAnim(bm.This().(Node2D))
 }
 
 func (bm *Bitmap) Render2D() {
@@ -159,7 +278,11 @@ func (bm *Bitmap) Render2D() {
 	}
 	if bm.PushBounds() {
 		bm.This().(Node2D).ConnectEvents2D()
-		bm.DrawIntoViewport(bm.Viewport)
+		if bm.Loading {
+			bm.RenderLoading()
+		} else {
+			bm.DrawIntoViewport(bm.Viewport)
+		}
 		bm.PopBounds()
 	} else {
 		bm.DisconnectAllEvents(AllPris)
@@ -182,7 +305,7 @@ func GrabRenderFrom(nii Node2D) *image.RGBA {
 	}
 	nivp = ni.Viewport
 	if nivp == nil || nivp.Pixels == nil {
-		log.Printf("gi.GrabRenderFrom could not grab from node, viewport or pixels nil: %v\n", ni.Path())
+		Logf(LogWarn, "gi.GrabRenderFrom", "could not grab from node, viewport or pixels nil: %v", ni.Path())
 		return nil
 	}
 	if ni.VpBBox.Empty() {
@@ -302,7 +425,7 @@ func ImageFmBase64PNG(eb []byte) (image.Image, error) {
 	db := make([]byte, base64.StdEncoding.DecodedLen(len(eb)))
 	_, err := base64.StdEncoding.Decode(db, eb)
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.ImageFmBase64PNG", "%v", err)
 		return nil, err
 	}
 	rb := bytes.NewReader(db)
@@ -317,7 +440,7 @@ func ImageFmBase64JPG(eb []byte) (image.Image, error) {
 	db := make([]byte, base64.StdEncoding.DecodedLen(len(eb)))
 	_, err := base64.StdEncoding.Decode(db, eb)
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.ImageFmBase64JPG", "%v", err)
 		return nil, err
 	}
 	rb := bytes.NewReader(db)
@@ -0,0 +1,274 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+// BusyDimPct is the opacity percent of the background color drawn over
+// the content of a busy Layout, to dim it (see SetBusy)
+var BusyDimPct = 60
+
+// BusySpinnerMSec is the number of milliseconds for one rotation of the
+// spinner shown over a busy Layout
+var BusySpinnerMSec = 1000
+
+// BusyCancelText is the text of the cancel button shown over a busy
+// Layout, if it can be canceled
+var BusyCancelText = "Cancel"
+
+// BusyState is the state of a Layout while it is busy, e.g., waiting for
+// the result of background work (see SetBusy)
+type BusyState struct {
+	Message   string          `desc:"message shown below the spinner -- none if empty"`
+	Cancel    func()          `desc:"function called when the cancel button is clicked -- there is no cancel button if nil"`
+	CancelBox image.Rectangle `desc:"window bounding box of the cancel button, as last rendered"`
+}
+
+// SetBusy puts the layout in busy mode, e.g., while awaiting the result of
+// background work: its content is dimmed and doesn't get any input events
+// or focus, and a spinner is shown centered over it, with given message
+// below it, if non-empty, and a cancel button that calls given function,
+// if non-nil -- which should stop the work.  Call ClearBusy when the work
+// is done (it is not called automatically on cancel).  Can be called
+// again while busy to change the message, e.g., to show progress.  It can
+// be called from another goroutine: the busy state is set immediately, so
+// input is blocked from then on, and the layout is updated on the window
+// event loop.
+func (ly *Layout) SetBusy(msg string, cancel func()) {
+	ly.BusyMu.Lock()
+	wasBusy := ly.Busy != nil
+	ly.Busy = &BusyState{Message: msg, Cancel: cancel}
+	ly.BusyMu.Unlock()
+	ly.runBusyUpdate(func() {
+		updt := ly.UpdateStart()
+		if !wasBusy {
+			ly.RenderCache.Invalidate()
+			if ly.ContainsFocus() {
+				if em := ly.EventMgr2D(); em != nil {
+					em.SetFocus(nil)
+				}
+			}
+		}
+		ly.SetFullReRender()
+		ly.UpdateEnd(updt)
+		if !wasBusy {
+			ly.UpdateBusyHover()
+		}
+	})
+}
+
+// ClearBusy ends the busy mode of the layout (see SetBusy), restoring
+// its content -- it can be called from another goroutine, as SetBusy can.
+func (ly *Layout) ClearBusy() {
+	ly.BusyMu.Lock()
+	wasBusy := ly.Busy != nil
+	ly.Busy = nil
+	ly.BusyMu.Unlock()
+	if !wasBusy {
+		return
+	}
+	ly.runBusyUpdate(func() {
+		updt := ly.UpdateStart()
+		ly.RenderCache.Invalidate()
+		StopRenderAnim(ly.This().(Node2D))
+		ly.DisconnectEvent(oswin.MouseEvent, HiPri) // normal ones reconnect on render
+		ly.SetFullReRender()
+		ly.UpdateEnd(updt)
+		ly.UpdateBusyHover()
+	})
+}
+
+// runBusyUpdate runs given update for a change of the busy mode on the
+// window event loop, on the next frame, or directly if not in a window
+func (ly *Layout) runBusyUpdate(fun func()) {
+	if win := ly.ParentWindow(); win != nil && !win.IsClosed() {
+		win.RunOnNextFrame(fun)
+	} else {
+		fun()
+	}
+}
+
+// CurBusy returns the current state of the busy mode (see SetBusy), under
+// BusyMu, or nil if the layout is not busy
+func (ly *Layout) CurBusy() *BusyState {
+	ly.BusyMu.RLock()
+	defer ly.BusyMu.RUnlock()
+	return ly.Busy
+}
+
+// IsBusy returns true if the layout is in busy mode (see SetBusy)
+func (ly *Layout) IsBusy() bool {
+	return ly.CurBusy() != nil
+}
+
+// UpdateBusyHover updates the nodes under the mouse after the busy mode
+// has changed, as the content of a busy layout can't be hovered
+func (ly *Layout) UpdateBusyHover() {
+	if em := ly.EventMgr2D(); em != nil {
+		em.UpdateMouseFocus(false)
+	}
+}
+
+// BusyLayout returns the innermost Layout that is busy (see SetBusy)
+// among given node and its parents, or nil if there is none
+func BusyLayout(k ki.Ki) *Layout {
+	var bly *Layout
+	k.FuncUp(0, k, func(k ki.Ki, level int, d any) bool {
+		nii, ok := k.(Node2D)
+		if !ok {
+			return ki.Break
+		}
+		if ly := nii.AsLayout2D(); ly != nil && ly.IsBusy() {
+			bly = ly
+			return ki.Break
+		}
+		return ki.Continue
+	})
+	return bly
+}
+
+// isBusyLayout returns true if given node is a Layout that is busy
+func isBusyLayout(k ki.Ki) bool {
+	nii, _ := KiToNode2D(k)
+	if nii == nil {
+		return false
+	}
+	ly := nii.AsLayout2D()
+	return ly != nil && ly.IsBusy()
+}
+
+// IsBusyBlocked returns true if given event should not be sent to given
+// node, because it is in the content of a busy Layout -- the busy layout
+// itself only gets mouse button events, for its cancel button
+func IsBusyBlocked(recv ki.Ki, evi oswin.Event) bool {
+	bly := BusyLayout(recv)
+	if bly == nil {
+		return false
+	}
+	if bly.This() == recv.This() && evi.Type() == oswin.MouseEvent {
+		return false
+	}
+	return true
+}
+
+// BusyEvents connects the mouse events of a busy layout, which go to its
+// cancel button, if any -- all mouse button events on it are consumed.
+func (ly *Layout) BusyEvents() {
+	ly.ConnectEvent(oswin.MouseEvent, HiPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.Event)
+		lyr := recv.Embed(KiT_Layout).(*Layout)
+		bs := lyr.CurBusy()
+		if bs == nil {
+			return
+		}
+		me.SetProcessed()
+		if bs.Cancel != nil && me.Action == mouse.Release && me.Button == mouse.Left && me.Where.In(bs.CancelBox) {
+			bs.Cancel()
+		}
+	})
+}
+
+// BusyPhase returns the current angle of the busy spinner, in radians
+func BusyPhase() float32 {
+	if BusySpinnerMSec <= 0 {
+		return 0
+	}
+	per := time.Duration(BusySpinnerMSec) * time.Millisecond
+	return 2 * mat32.Pi * float32(time.Since(RenderAnimStart)%per) / float32(per)
+}
+
+// RenderBusy renders the busy overlay over the content of a busy layout:
+// the content is dimmed, and the spinner, message and cancel button are
+// centered in the visible region -- it is animated, and its mouse events
+// are connected, while it is shown.
+func (ly *Layout) RenderBusy() {
+	bs := ly.CurBusy()
+	if bs == nil {
+		return
+	}
+	rs, pc, st := ly.RenderLock()
+	ly.BBoxMu.RLock()
+	vbb := ly.VpBBox
+	woff := ly.WinBBox.Min.Sub(ly.VpBBox.Min)
+	ly.BBoxMu.RUnlock()
+	r := vbb.Intersect(rs.Bounds)
+	if r.Empty() {
+		ly.RenderUnlock(rs)
+		return
+	}
+
+	bg := Prefs.Colors.Background
+	if !st.Font.BgColor.IsNil() {
+		bg = st.Font.BgColor.Color
+	}
+	dim := color.NRGBA{bg.R, bg.G, bg.B, uint8(255 * BusyDimPct / 100)}
+	draw.Draw(rs.Image, r, image.NewUniform(dim), image.Point{}, draw.Over)
+
+	fs := st.Font.Size.Dots
+	if fs <= 0 {
+		fs = 12
+	}
+	rad := 1.2 * fs
+	gap := 0.75 * fs
+	var msg, cnc girl.Text
+	ht := 2 * rad
+	if bs.Message != "" {
+		msg.SetString(bs.Message, &st.Font, &st.UnContext, &st.Text, true, 0, 0)
+		ht += gap + msg.Size.Y
+	}
+	var bsz mat32.Vec2
+	if bs.Cancel != nil {
+		cnc.SetString(BusyCancelText, &st.Font, &st.UnContext, &st.Text, true, 0, 0)
+		bsz = cnc.Size.Add(mat32.Vec2{2 * fs, fs})
+		ht += gap + bsz.Y
+	}
+	cx := 0.5 * float32(r.Min.X+r.Max.X)
+	y := 0.5*float32(r.Min.Y+r.Max.Y) - 0.5*ht
+
+	ph := BusyPhase()
+	lcap := pc.StrokeStyle.Cap
+	pc.FillStyle.SetColor(nil)
+	pc.StrokeStyle.SetColor(&Prefs.Colors.Link)
+	pc.StrokeStyle.Width.Dots = 0.2 * rad
+	pc.StrokeStyle.Cap = gist.LineCapRound
+	pc.NewSubPath(rs)
+	pc.DrawArc(rs, cx, y+rad, 0.9*rad, ph, ph+1.5*mat32.Pi)
+	pc.Stroke(rs)
+	pc.StrokeStyle.Cap = lcap
+	y += 2 * rad
+
+	if bs.Message != "" {
+		y += gap
+		msg.RenderTopPos(rs, mat32.Vec2{cx - 0.5*msg.Size.X, y})
+		y += msg.Size.Y
+	}
+	if bs.Cancel != nil {
+		y += gap
+		bpos := mat32.Vec2{cx - 0.5*bsz.X, y}
+		pc.FillStyle.SetColor(&Prefs.Colors.Control)
+		pc.StrokeStyle.SetColor(&Prefs.Colors.Border)
+		pc.StrokeStyle.Width.Dots = 1
+		pc.DrawRoundedRectangle(rs, bpos.X, bpos.Y, bsz.X, bsz.Y, 0.25*fs)
+		pc.FillStrokeClear(rs)
+		cnc.RenderTopPos(rs, bpos.Add(mat32.Vec2{fs, 0.5 * fs}))
+		bb := mat32.RectFromPosSizeMax(bpos, bsz)
+		bs.CancelBox = bb.Add(woff)
+	}
+	ly.RenderUnlock(rs)
+	ly.BusyEvents()
+	StartRenderAnim(ly.This().(Node2D))
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"log"
 	"strings"
 	"sync"
 
@@ -27,7 +26,7 @@ import (
 // Button, Action, MenuButton, CheckBox, etc
 type ButtonBase struct {
 	PartsWidgetBase
-	Text         string                    `xml:"text" desc:"label for the button -- if blank then no label is presented"`
+	Text         string                    `xml:"text" desc:"label for the button -- if blank then no label is presented -- an & before a character marks it as the mnemonic (access key), e.g., &File -- see ParseMnemonic"`
 	Icon         IconName                  `xml:"icon" view:"show-name" desc:"optional icon for the button -- different buttons can configure this in different ways relative to the text if both are present"`
 	Indicator    IconName                  `xml:"indicator" view:"show-name" desc:"name of the menu indicator icon to present, or blank or 'nil' or 'none' -- shown automatically when there are Menu elements present unless 'none' is set"`
 	Shortcut     key.Chord                 `xml:"shortcut" desc:"optional shortcut keyboard chord to trigger this action -- always window-wide in scope, and should generally not conflict other shortcuts (a log message will be emitted if so).  Shortcuts are processed after all other processing of keyboard input.  Use Command for Control / Meta (Mac Command key) per platform.  These are only set automatically for Menu items, NOT for items in ToolBar or buttons somewhere, but the tooltip for buttons will show the shortcut if set."`
@@ -37,6 +36,9 @@ type ButtonBase struct {
 	Menu         Menu                      `desc:"the menu items for this menu -- typically add Action elements for menus, along with separators"`
 	MakeMenuFunc MakeMenuFunc              `copy:"-" json:"-" xml:"-" view:"-" desc:"set this to make a menu on demand -- if set then this button acts like a menu button"`
 	ButStateMu   sync.Mutex                `copy:"-" json:"-" xml:"-" view:"-" desc:"button state mutex"`
+	Mnemonic     rune                      `copy:"-" json:"-" xml:"-" view:"-" desc:"lower-case mnemonic (access key) of the button, as last assigned in its scene, which resolves conflicts -- 0 if none"`
+	MnemonicIdx  int                       `copy:"-" json:"-" xml:"-" view:"-" desc:"rune index of the Mnemonic in the LabelText"`
+	MnemonicOn   bool                      `copy:"-" json:"-" xml:"-" view:"-" desc:"true if the Mnemonic is shown underlined, while the Alt key is held down"`
 }
 
 var KiT_ButtonBase = kit.Types.AddType(&ButtonBase{}, ButtonBaseProps)
@@ -49,7 +51,7 @@ var ButtonBaseProps = ki.Props{
 func (bb *ButtonBase) CopyFieldsFrom(frm any) {
 	fr, ok := frm.(*ButtonBase)
 	if !ok {
-		log.Printf("GoGi node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier ButtonBase one\n", ki.Type(bb).Name())
+		Logf(LogWarn, "gi.ButtonBase", "node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier ButtonBase one", ki.Type(bb).Name())
 		ki.GenCopyFieldsFrom(bb.This(), frm)
 		return
 	}
@@ -599,10 +601,10 @@ func (bb *ButtonBase) StyleParts() {
 func (bb *ButtonBase) ConfigParts() {
 	bb.Parts.Lay = LayoutHoriz
 	config := kit.TypeAndNameList{}
-	icIdx, lbIdx := bb.ConfigPartsIconLabel(&config, string(bb.Icon), bb.Text)
+	icIdx, lbIdx := bb.ConfigPartsIconLabel(&config, string(bb.Icon), bb.LabelText())
 	indIdx := bb.ConfigPartsAddIndicator(&config, false) // default off
 	mods, updt := bb.Parts.ConfigChildren(config)
-	bb.ConfigPartsSetIconLabel(string(bb.Icon), bb.Text, icIdx, lbIdx)
+	bb.ConfigPartsSetIconLabel(string(bb.Icon), bb.LabelText(), icIdx, lbIdx)
 	bb.ConfigPartsIndicator(indIdx)
 	if mods {
 		bb.UpdateEnd(updt)
@@ -610,7 +612,7 @@ func (bb *ButtonBase) ConfigParts() {
 }
 
 func (bb *ButtonBase) ConfigPartsIfNeeded() {
-	if !bb.PartsNeedUpdateIconLabel(string(bb.Icon), bb.Text) {
+	if !bb.PartsNeedUpdateIconLabel(string(bb.Icon), bb.LabelText()) {
 		return
 	}
 	bb.This().(ButtonWidget).ConfigParts()
@@ -735,6 +737,7 @@ func (bb *ButtonBase) Destroy() {
 	if bb.Menu != nil {
 		bb.Menu.DeleteShortcuts(bb.ParentWindow())
 	}
+	bb.WidgetBase.Destroy()
 }
 
 ///////////////////////////////////////////////////////////
@@ -999,7 +1002,7 @@ func (cb *CheckBox) ConfigParts() {
 		if lbl.Text != cb.Text {
 			cb.StylePart(cb.Parts.Child(lbIdx - 1).(Node2D)) // also get the space
 			cb.StylePart(Node2D(lbl))
-			lbl.SetText(cb.Text)
+			lbl.SetText(cb.LabelText())
 		}
 	}
 	if mods {
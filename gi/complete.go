@@ -210,6 +210,7 @@ func (c *Complete) Complete(s string) {
 	c.Cancel()
 	c.Completion = s
 	c.CompleteSig.Emit(c.This(), int64(CompleteSelect), s)
+	PlaySound(SoundCompleteAccept)
 }
 
 // KeyInput is the opportunity for completion to act on specific key inputs
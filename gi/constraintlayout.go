@@ -0,0 +1,640 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+// Constraints
+
+// ConstraintAttrs are the attributes of a child (or the container) of a
+// ConstraintLayout that can be constrained
+type ConstraintAttrs int32
+
+const (
+	// ConstrNone is no attribute: the other side of the constraint is
+	// just the constant
+	ConstrNone ConstraintAttrs = iota
+
+	// ConstrLeft is the left edge
+	ConstrLeft
+
+	// ConstrRight is the right edge
+	ConstrRight
+
+	// ConstrTop is the top edge
+	ConstrTop
+
+	// ConstrBottom is the bottom edge
+	ConstrBottom
+
+	// ConstrWidth is the width
+	ConstrWidth
+
+	// ConstrHeight is the height
+	ConstrHeight
+
+	// ConstrCenterX is the horizontal center
+	ConstrCenterX
+
+	// ConstrCenterY is the vertical center
+	ConstrCenterY
+
+	ConstraintAttrsN
+)
+
+//go:generate stringer -type=ConstraintAttrs
+
+var KiT_ConstraintAttrs = kit.Enums.AddEnumAltLower(ConstraintAttrsN, kit.NotBitFlag, nil, "Constr")
+
+func (ev ConstraintAttrs) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *ConstraintAttrs) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// ConstraintRels are the relations between the two sides of a constraint
+type ConstraintRels int32
+
+const (
+	// ConstrEq requires the item attribute to equal the other side
+	ConstrEq ConstraintRels = iota
+
+	// ConstrLe requires the item attribute to be less than or equal to the
+	// other side
+	ConstrLe
+
+	// ConstrGe requires the item attribute to be greater than or equal to
+	// the other side
+	ConstrGe
+
+	ConstraintRelsN
+)
+
+//go:generate stringer -type=ConstraintRels
+
+var KiT_ConstraintRels = kit.Enums.AddEnumAltLower(ConstraintRelsN, kit.NotBitFlag, nil, "Constr")
+
+func (ev ConstraintRels) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *ConstraintRels) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// Constraint priorities: a stronger constraint dominates any number of
+// weaker ones that conflict with it -- Required constraints are always
+// satisfied when they are consistent with each other.
+const (
+	ConstraintRequired = float32(1000)
+	ConstraintStrong   = float32(100)
+	ConstraintMedium   = float32(10)
+	ConstraintWeak     = float32(1)
+)
+
+// Constraint is a linear constraint on an attribute of a child of a
+// ConstraintLayout, of the form:
+//
+//	Item.Attr Rel Mult * To.ToAttr + Const
+//
+// where Item and To are the names of children, and an empty name refers to
+// the (content box of the) container itself, whose attributes are fixed.
+type Constraint struct {
+	Item     string          `desc:"name of the child that is constrained -- empty for the container"`
+	Attr     ConstraintAttrs `desc:"attribute of the item that is constrained"`
+	Rel      ConstraintRels  `desc:"relation between the item attribute and the other side"`
+	To       string          `desc:"name of the child on the other side -- empty for the container"`
+	ToAttr   ConstraintAttrs `desc:"attribute of the other child -- ConstrNone to constrain to just the constant"`
+	Mult     float32         `desc:"multiplier on the other attribute, e.g., for ratios -- 0 is treated as 1"`
+	Const    units.Value     `desc:"constant offset added to the other side"`
+	Priority float32         `desc:"priority of the constraint, e.g., ConstraintStrong -- 0 is treated as ConstraintRequired"`
+}
+
+// String returns a readable expression of the constraint
+func (cs *Constraint) String() string {
+	rels := []string{"==", "<=", ">="}
+	str := fmt.Sprintf("%v.%v %v ", cs.Item, cs.Attr, rels[cs.Rel])
+	if cs.ToAttr != ConstrNone {
+		str += fmt.Sprintf("%g * %v.%v + ", cs.EffMult(), cs.To, cs.ToAttr)
+	}
+	return str + cs.Const.String()
+}
+
+// EffMult returns the effective multiplier, where 0 means 1
+func (cs *Constraint) EffMult() float32 {
+	if cs.Mult == 0 {
+		return 1
+	}
+	return cs.Mult
+}
+
+// EffPriority returns the effective priority, where 0 means required
+func (cs *Constraint) EffPriority() float32 {
+	if cs.Priority <= 0 {
+		return ConstraintRequired
+	}
+	return cs.Priority
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+// ConstraintLayout
+
+// ConstraintLayout is a layout where the positions and sizes of the children
+// are defined by linear Constraints among them and the container -- e.g.,
+// anchoring an edge of one child to that of another, keeping a ratio
+// between sizes, or centering in the container -- which are solved at
+// layout time, for complex dialogs that do not fit nicely into nested
+// rows and columns.  Children can be any widgets, including standard
+// layouts.  Each child weakly keeps its preferred size (and strongly its
+// min and max sizes), and children without any constraints are placed at
+// the top-left.  As in the cassowary algorithm, the priorities are strict
+// levels: the constraints are solved level by level, from the strongest
+// down, each in the least-squares sense among the solutions that best
+// satisfy all the stronger levels, with the inequalities added as they
+// become violated.
+type ConstraintLayout struct {
+	Layout
+	Constraints []Constraint `desc:"the constraints on the children, solved in the Layout2D pass"`
+}
+
+var KiT_ConstraintLayout = kit.Types.AddType(&ConstraintLayout{}, ConstraintLayoutProps)
+
+var ConstraintLayoutProps = ki.Props{
+	"EnumType:Flag": KiT_NodeFlags,
+}
+
+// AddNewConstraintLayout adds a new constraint layout to given parent node,
+// with given name.
+func AddNewConstraintLayout(parent ki.Ki, name string) *ConstraintLayout {
+	cl := parent.AddNewChild(KiT_ConstraintLayout, name).(*ConstraintLayout)
+	cl.Lay = LayoutNil
+	return cl
+}
+
+func (cl *ConstraintLayout) CopyFieldsFrom(frm any) {
+	fr := frm.(*ConstraintLayout)
+	cl.Layout.CopyFieldsFrom(&fr.Layout)
+	cl.Constraints = make([]Constraint, len(fr.Constraints))
+	copy(cl.Constraints, fr.Constraints)
+}
+
+// AddConstraint adds given constraint, returning its index
+func (cl *ConstraintLayout) AddConstraint(cs Constraint) int {
+	cl.Constraints = append(cl.Constraints, cs)
+	return len(cl.Constraints) - 1
+}
+
+// Anchor adds a constraint with given priority (0 = required) that makes
+// given attribute of item equal to that of the other child (or container
+// if empty) plus given offset -- e.g., Anchor("ok", ConstrLeft,
+// "cancel", ConstrRight, units.NewEm(1), 0) places the ok button 1em to the
+// right of the cancel button.
+func (cl *ConstraintLayout) Anchor(item string, attr ConstraintAttrs, to string, toAttr ConstraintAttrs, off units.Value, pri float32) int {
+	return cl.AddConstraint(Constraint{Item: item, Attr: attr, Rel: ConstrEq, To: to, ToAttr: toAttr, Const: off, Priority: pri})
+}
+
+// Ratio adds a constraint with given priority (0 = required) that makes
+// given attribute of item equal to mult times that of the other child (or
+// container if empty) -- e.g., Ratio("list", ConstrWidth, "",
+// ConstrWidth, 0.3, 0) makes the list 30% of the width of the container.
+func (cl *ConstraintLayout) Ratio(item string, attr ConstraintAttrs, to string, toAttr ConstraintAttrs, mult float32, pri float32) int {
+	return cl.AddConstraint(Constraint{Item: item, Attr: attr, Rel: ConstrEq, To: to, ToAttr: toAttr, Mult: mult, Priority: pri})
+}
+
+func (cl *ConstraintLayout) Style2D() {
+	cl.Layout.Style2D()
+	cl.StyMu.RLock()
+	for i := range cl.Constraints {
+		cl.Constraints[i].Const.ToDots(&cl.Sty.UnContext)
+	}
+	cl.StyMu.RUnlock()
+}
+
+func (cl *ConstraintLayout) Size2D(iter int) {
+	cl.InitLayout2D()
+	GatherSizes(&cl.Layout)
+	// grow to fit the children as positioned by the constraints at our
+	// preferred size
+	spc := cl.BoxSpace()
+	csz := cl.LayState.Size.Pref.AddScalar(-2 * spc)
+	ext, ok := cl.SolveConstraints(csz, false)
+	if !ok {
+		return
+	}
+	ext = ext.AddScalar(2 * spc)
+	cl.LayState.Size.Pref.SetMax(ext)
+	cl.LayState.UpdateSizes()
+}
+
+func (cl *ConstraintLayout) Layout2D(parBBox image.Rectangle, iter int) bool {
+	LayAllocFromParent(&cl.Layout)
+	cl.Layout2DBase(parBBox, true, iter)
+	LayoutPctSizes(&cl.Layout)
+	spc := cl.BoxSpace()
+	cl.SolveConstraints(cl.LayState.Alloc.Size.AddScalar(-2*spc), true)
+	cl.FinalizeLayout()
+	cl.ManageOverflow()
+	cl.NeedsRedo = cl.Layout2DChildren(iter)
+	if !cl.NeedsRedo || iter == 1 {
+		delta := cl.Move2DDelta(image.ZP)
+		if delta != image.ZP {
+			cl.Move2DChildren(delta)
+		}
+	}
+	return cl.NeedsRedo
+}
+
+// SolveConstraints solves the constraints for given content size of the
+// container, returning the extent of the children (max right, bottom)
+// relative to the content box, and false if there are no children.  If
+// set is true, the positions and sizes of the children are set from the
+// solution.
+func (cl *ConstraintLayout) SolveConstraints(csz mat32.Vec2, set bool) (mat32.Vec2, bool) {
+	var kids []*WidgetBase
+	idx := map[string]int{}
+	for _, c := range cl.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		idx[ni.Nm] = len(kids)
+		kids = append(kids, ni)
+	}
+	nk := len(kids)
+	if nk == 0 {
+		return mat32.Vec2{}, false
+	}
+	spc := cl.BoxSpace()
+	cs := &constraintSystem{nvar: 4 * nk, csz: csz}
+
+	// implicit constraints for the standard sizing of the children:
+	// weakly at the top-left, at their preferred size, within min / max
+	for i, ni := range kids {
+		sz := &ni.LayState.Size
+		for d := mat32.X; d <= mat32.Y; d++ {
+			pos, size := ConstrLeft, ConstrWidth
+			if d == mat32.Y {
+				pos, size = ConstrTop, ConstrHeight
+			}
+			cs.addAttr(i, pos, 0, 0.001*ConstraintWeak, ConstrEq)
+			cs.addAttr(i, size, float64(sz.Pref.Dim(d)), 0.1*ConstraintWeak, ConstrEq)
+			cs.addAttr(i, size, float64(sz.Need.Dim(d)), ConstraintStrong, ConstrGe)
+			if mx := sz.Max.Dim(d); mx > 0 {
+				cs.addAttr(i, size, float64(mx), ConstraintStrong, ConstrLe)
+			}
+		}
+	}
+
+	for ci := range cl.Constraints {
+		c := &cl.Constraints[ci]
+		it, ok := constraintItem(idx, c.Item)
+		if !ok {
+			Logf(LogWarn, "gi.ConstraintLayout", "%v: unknown item in constraint: %v", cl.Path(), c.String())
+			continue
+		}
+		to := -1
+		if c.ToAttr != ConstrNone {
+			to, ok = constraintItem(idx, c.To)
+			if !ok {
+				Logf(LogWarn, "gi.ConstraintLayout", "%v: unknown item in constraint: %v", cl.Path(), c.String())
+				continue
+			}
+		}
+		row := make([]float64, cs.nvar)
+		rhs := float64(c.Const.Dots)
+		rhs -= cs.attrCoefs(row, it, c.Attr, 1)
+		if c.ToAttr != ConstrNone {
+			rhs -= cs.attrCoefs(row, to, c.ToAttr, -float64(c.EffMult()))
+		}
+		cs.add(row, rhs, c.EffPriority(), c.Rel)
+	}
+
+	sol := cs.solve()
+
+	var ext mat32.Vec2
+	for i, ni := range kids {
+		pos := mat32.Vec2{float32(sol[4*i]), float32(sol[4*i+1])}
+		size := mat32.Vec2{float32(sol[4*i+2]), float32(sol[4*i+3])}
+		size.SetMaxScalar(0)
+		ext.SetMax(pos.Add(size))
+		if set {
+			ni.LayState.Alloc.PosRel = pos.AddScalar(spc)
+			ni.LayState.Alloc.Size = size
+		}
+	}
+	if Layout2DTrace {
+		fmt.Printf("Layout: %v constraints solved, extent: %v\n", cl.Path(), ext)
+	}
+	return ext, true
+}
+
+// constraintItem returns the index of the named child, -1 for the
+// container, and false if not found
+func constraintItem(idx map[string]int, nm string) (int, bool) {
+	if nm == "" {
+		return -1, true
+	}
+	i, ok := idx[nm]
+	return i, ok
+}
+
+// constraintSystem is a prioritized system of linear constraints on the
+// position and size (left, top, width, height) of each child.
+type constraintSystem struct {
+	nvar int
+	csz  mat32.Vec2
+	rows []constraintRow
+}
+
+// constraintRow is one constraint: row . vars rel rhs
+type constraintRow struct {
+	coef   []float64
+	rhs    float64
+	pri    float32
+	rel    ConstraintRels
+	active bool
+}
+
+// attrCoefs adds the coefficients (times scale) of given attribute of
+// given child to the row, or returns its value times scale for the
+// container (-1), whose attributes are constants.
+func (cs *constraintSystem) attrCoefs(row []float64, it int, attr ConstraintAttrs, scale float64) float64 {
+	if it < 0 {
+		var v float32
+		switch attr {
+		case ConstrRight, ConstrWidth:
+			v = cs.csz.X
+		case ConstrBottom, ConstrHeight:
+			v = cs.csz.Y
+		case ConstrCenterX:
+			v = 0.5 * cs.csz.X
+		case ConstrCenterY:
+			v = 0.5 * cs.csz.Y
+		}
+		return scale * float64(v)
+	}
+	l, t, w, h := 4*it, 4*it+1, 4*it+2, 4*it+3
+	switch attr {
+	case ConstrLeft:
+		row[l] += scale
+	case ConstrRight:
+		row[l] += scale
+		row[w] += scale
+	case ConstrTop:
+		row[t] += scale
+	case ConstrBottom:
+		row[t] += scale
+		row[h] += scale
+	case ConstrWidth:
+		row[w] += scale
+	case ConstrHeight:
+		row[h] += scale
+	case ConstrCenterX:
+		row[l] += scale
+		row[w] += 0.5 * scale
+	case ConstrCenterY:
+		row[t] += scale
+		row[h] += 0.5 * scale
+	}
+	return 0
+}
+
+// addAttr adds a constraint that given attribute of given child has given
+// relation to given value
+func (cs *constraintSystem) addAttr(it int, attr ConstraintAttrs, val float64, pri float32, rel ConstraintRels) {
+	row := make([]float64, cs.nvar)
+	cs.attrCoefs(row, it, attr, 1)
+	cs.add(row, val, pri, rel)
+}
+
+// add adds a row with given priority -- equalities are always active,
+// while inequalities only become active when violated.
+func (cs *constraintSystem) add(row []float64, rhs float64, pri float32, rel ConstraintRels) {
+	cs.rows = append(cs.rows, constraintRow{coef: row, rhs: rhs, pri: pri, rel: rel, active: rel == ConstrEq})
+}
+
+// solve solves the system, iteratively activating violated inequalities
+func (cs *constraintSystem) solve() []float64 {
+	const tol = 0.5 // dots
+	var sol []float64
+	for iter := 0; iter < 10; iter++ {
+		sol = cs.solveActive()
+		changed := false
+		for ri := range cs.rows {
+			r := &cs.rows[ri]
+			if r.active {
+				continue
+			}
+			v := 0.0
+			for i, c := range r.coef {
+				v += c * sol[i]
+			}
+			if (r.rel == ConstrLe && v > r.rhs+tol) || (r.rel == ConstrGe && v < r.rhs-tol) {
+				r.active = true
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return sol
+}
+
+// levels returns the distinct priorities of the active rows, strongest first
+func (cs *constraintSystem) levels() []float32 {
+	var lvs []float32
+	for _, r := range cs.rows {
+		if !r.active {
+			continue
+		}
+		has := false
+		for _, l := range lvs {
+			if l == r.pri {
+				has = true
+				break
+			}
+		}
+		if !has {
+			lvs = append(lvs, r.pri)
+		}
+	}
+	sort.Slice(lvs, func(i, j int) bool { return lvs[i] > lvs[j] })
+	return lvs
+}
+
+// solveActive solves the active rows level by level, from the strongest
+// priority down: each level is solved in the least-squares sense, only
+// within the remaining space of solutions that leave the residuals of all
+// the stronger levels as they are, so that a stronger constraint is never
+// traded off against any number of weaker ones.
+func (cs *constraintSystem) solveActive() []float64 {
+	n := cs.nvar
+	sol := make([]float64, n)
+	// basis of the remaining solution space: n x nb, as columns
+	nb := n
+	basis := make([][]float64, n)
+	for i := range basis {
+		basis[i] = make([]float64, n)
+		basis[i][i] = 1
+	}
+	for _, pri := range cs.levels() {
+		if nb == 0 {
+			break
+		}
+		// the rows of the level, in terms of the remaining basis:
+		// min |m z - res|, for sol += basis z
+		var m [][]float64
+		var res []float64
+		for _, r := range cs.rows {
+			if !r.active || r.pri != pri {
+				continue
+			}
+			mr := make([]float64, nb)
+			v := r.rhs
+			for i, c := range r.coef {
+				if c == 0 {
+					continue
+				}
+				v -= c * sol[i]
+				for j := 0; j < nb; j++ {
+					mr[j] += c * basis[i][j]
+				}
+			}
+			m = append(m, mr)
+			res = append(res, v)
+		}
+		z, null := leastSquares(m, res, nb)
+		for i := 0; i < n; i++ {
+			for j := 0; j < nb; j++ {
+				sol[i] += basis[i][j] * z[j]
+			}
+		}
+		nn := len(null[0])
+		nbasis := make([][]float64, n)
+		for i := 0; i < n; i++ {
+			nbasis[i] = make([]float64, nn)
+			for k := 0; k < nn; k++ {
+				for j := 0; j < nb; j++ {
+					nbasis[i][k] += basis[i][j] * null[j][k]
+				}
+			}
+		}
+		basis, nb = nbasis, nn
+	}
+	return sol
+}
+
+// leastSquares returns the minimum-norm least-squares solution z of m z =
+// res, for m with given number of columns, along with an orthonormal basis
+// of the null space of m (as columns), within which z can vary without
+// changing m z -- computed from the eigen decomposition of the normal
+// equations.
+func leastSquares(m [][]float64, res []float64, ncol int) (z []float64, null [][]float64) {
+	g := make([][]float64, ncol)
+	h := make([]float64, ncol)
+	for i := range g {
+		g[i] = make([]float64, ncol)
+	}
+	for ri, mr := range m {
+		for i, ci := range mr {
+			if ci == 0 {
+				continue
+			}
+			for j, cj := range mr {
+				g[i][j] += ci * cj
+			}
+			h[i] += ci * res[ri]
+		}
+	}
+	vals, vecs := symEigen(g)
+	mx := 0.0
+	for _, v := range vals {
+		mx = math.Max(mx, v)
+	}
+	eps := 1e-10 * math.Max(mx, 1)
+	z = make([]float64, ncol)
+	null = make([][]float64, ncol)
+	for k, lv := range vals {
+		if lv <= eps {
+			for i := range null {
+				null[i] = append(null[i], vecs[i][k])
+			}
+			continue
+		}
+		d := 0.0
+		for i := range h {
+			d += vecs[i][k] * h[i]
+		}
+		d /= lv
+		for i := range z {
+			z[i] += d * vecs[i][k]
+		}
+	}
+	return
+}
+
+// symEigen returns the eigenvalues and eigenvectors (as columns) of given
+// symmetric matrix, which is modified, using the cyclic Jacobi method
+func symEigen(a [][]float64) (vals []float64, vecs [][]float64) {
+	n := len(a)
+	vecs = make([][]float64, n)
+	for i := range vecs {
+		vecs[i] = make([]float64, n)
+		vecs[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		off, diag := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			diag += a[i][i] * a[i][i]
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off <= 1e-24*diag || off == 0 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vecs[k][p], vecs[k][q]
+					vecs[k][p] = c*vkp - s*vkq
+					vecs[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	vals = make([]float64, n)
+	for i := range vals {
+		vals[i] = a[i][i]
+	}
+	return
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"math"
+	"testing"
+)
+
+// addVar adds a constraint on one variable of given system
+func (cs *constraintSystem) addVar(v int, val float64, pri float32, rel ConstraintRels) {
+	row := make([]float64, cs.nvar)
+	row[v] = 1
+	cs.add(row, val, pri, rel)
+}
+
+func TestConstraintSolver(t *testing.T) {
+	type cnst struct {
+		coef []float64
+		rhs  float64
+		pri  float32
+		rel  ConstraintRels
+	}
+	tests := []struct {
+		name string
+		cs   []cnst
+		sol  []float64
+	}{
+		{"required beats weak", []cnst{
+			{[]float64{1, 0}, 10, ConstraintRequired, ConstrEq},
+			{[]float64{1, 0}, 50, ConstraintWeak, ConstrEq},
+		}, []float64{10, 0}},
+		{"weak averages", []cnst{
+			{[]float64{1, 0}, 10, ConstraintWeak, ConstrEq},
+			{[]float64{1, 0}, 20, ConstraintWeak, ConstrEq},
+		}, []float64{15, 0}},
+		{"conflicting required", []cnst{
+			{[]float64{1, 0}, 10, ConstraintRequired, ConstrEq},
+			{[]float64{1, 0}, 20, ConstraintRequired, ConstrEq},
+			{[]float64{1, 0}, 100, ConstraintStrong, ConstrEq},
+		}, []float64{15, 0}},
+		{"weaker levels use remaining freedom", []cnst{
+			{[]float64{1, 1}, 100, ConstraintRequired, ConstrEq},
+			{[]float64{1, 0}, 30, ConstraintStrong, ConstrEq},
+			{[]float64{0, 1}, 0, ConstraintWeak, ConstrEq},
+		}, []float64{30, 70}},
+		{"inequality limits", []cnst{
+			{[]float64{1, 0}, 30, ConstraintRequired, ConstrLe},
+			{[]float64{1, 0}, 50, ConstraintWeak, ConstrEq},
+			{[]float64{0, 1}, 5, ConstraintStrong, ConstrGe},
+			{[]float64{0, 1}, 20, ConstraintWeak, ConstrEq},
+		}, []float64{30, 20}},
+		{"conflicting inequalities", []cnst{
+			{[]float64{1, 0}, 40, ConstraintStrong, ConstrGe},
+			{[]float64{1, 0}, 30, ConstraintMedium, ConstrLe},
+			{[]float64{1, -1}, 0, ConstraintMedium, ConstrEq},
+		}, []float64{40, 40}},
+	}
+	for _, tt := range tests {
+		cs := &constraintSystem{nvar: 2}
+		for _, c := range tt.cs {
+			cs.add(c.coef, c.rhs, c.pri, c.rel)
+		}
+		sol := cs.solve()
+		for i := range tt.sol {
+			if math.Abs(sol[i]-tt.sol[i]) > 1e-6 {
+				t.Errorf("%s: solution: %v != correct: %v\n", tt.name, sol, tt.sol)
+				break
+			}
+		}
+	}
+}
+
+// a stronger constraint dominates any number of weaker ones that conflict
+// with it, which a weighted least-squares solution does not
+func TestConstraintSolverStrengths(t *testing.T) {
+	cs := &constraintSystem{nvar: 1}
+	for i := 0; i < 1000; i++ {
+		cs.addVar(0, 0, ConstraintMedium, ConstrEq)
+	}
+	cs.addVar(0, 100, ConstraintStrong, ConstrEq)
+	if sol := cs.solve(); math.Abs(sol[0]-100) > 1e-6 {
+		t.Errorf("strong vs 1000 medium: %v != correct: 100\n", sol[0])
+	}
+
+	cs = &constraintSystem{nvar: 4}
+	cs.addVar(0, 10, ConstraintRequired, ConstrEq)
+	cs.addVar(2, 50, ConstraintStrong, ConstrGe)
+	cs.addVar(2, 80, ConstraintStrong, ConstrLe)
+	cs.addVar(2, 200, ConstraintWeak, ConstrEq)
+	cs.addVar(2, 20, ConstraintMedium, ConstrEq)
+	if sol := cs.solve(); math.Abs(sol[0]-10) > 1e-6 || math.Abs(sol[2]-50) > 1e-6 {
+		t.Errorf("min / max with medium pref: %v != correct: [10 0 50 0]\n", sol)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+)
+
+// RecoverPanics causes panics in the processing of events in the event loop
+// of each window (i.e., in event handlers and the updating and rendering
+// they trigger) to be recovered, instead of killing the whole process: a
+// dialog shows the stack trace, with the option to save a crash report
+// (see CrashReport), and the event loops of all windows keep running.  The
+// state of the window where the panic happened may be inconsistent, so
+// the user should save their work and restart.  See EnableRecoverPanics.
+var RecoverPanics = false
+
+// CrashReportFunc if set is called with each CrashReport when a panic is
+// recovered, e.g., to send it to the developers.
+var CrashReportFunc func(rep *CrashReport)
+
+// RecentLogs holds the recent output of the standard logger, for crash
+// reports, once EnableRecoverPanics has been called.
+var RecentLogs = LogRing{Max: 500}
+
+// EnableRecoverPanics sets RecoverPanics and captures the output of the
+// standard logger (which is still written to its current output) into
+// RecentLogs, to include in crash reports
+func EnableRecoverPanics() {
+	RecoverPanics = true
+	log.SetOutput(io.MultiWriter(log.Writer(), &RecentLogs))
+}
+
+// LogRing is an io.Writer that keeps the last Max lines written to it
+type LogRing struct {
+	Max   int        `desc:"maximum number of lines to keep"`
+	Mu    sync.Mutex `desc:"mutex protecting the lines"`
+	lines []string
+	part  string
+}
+
+// Write adds the lines in given bytes, implementing io.Writer
+func (lr *LogRing) Write(b []byte) (int, error) {
+	lr.Mu.Lock()
+	defer lr.Mu.Unlock()
+	str := lr.part + string(b)
+	lns := strings.Split(str, "\n")
+	lr.part = lns[len(lns)-1]
+	lr.lines = append(lr.lines, lns[:len(lns)-1]...)
+	if over := len(lr.lines) - lr.Max; lr.Max > 0 && over > 0 {
+		lr.lines = append([]string(nil), lr.lines[over:]...)
+	}
+	return len(b), nil
+}
+
+// Lines returns a copy of the lines currently kept
+func (lr *LogRing) Lines() []string {
+	lr.Mu.Lock()
+	defer lr.Mu.Unlock()
+	lns := make([]string, len(lr.lines))
+	copy(lns, lr.lines)
+	return lns
+}
+
+// CrashReport has the information about a recovered panic
+type CrashReport struct {
+	Time   time.Time `desc:"when the panic happened"`
+	Window string    `desc:"name of the window where the panic happened"`
+	Panic  string    `desc:"the value passed to panic"`
+	Stack  string    `desc:"stack trace of the panic"`
+	Logs   []string  `desc:"recent log output -- see EnableRecoverPanics"`
+	Prefs  string    `desc:"the preferences, in JSON format"`
+}
+
+// NewCrashReport returns a new crash report for given panic value and stack
+func NewCrashReport(win string, r any, stack []byte) *CrashReport {
+	rep := &CrashReport{Time: time.Now(), Window: win, Panic: fmt.Sprint(r), Stack: string(stack)}
+	rep.Logs = RecentLogs.Lines()
+	if b, err := json.MarshalIndent(&Prefs, "", "  "); err == nil {
+		rep.Prefs = string(b)
+	}
+	return rep
+}
+
+// String returns the full text of the report
+func (rep *CrashReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Crash report: %v\n", rep.Time.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "App: %v  Window: %v\n", oswin.TheApp.Name(), rep.Window)
+	fmt.Fprintf(&sb, "\npanic: %v\n\n%v\n", rep.Panic, rep.Stack)
+	if len(rep.Logs) > 0 {
+		fmt.Fprintf(&sb, "\nRecent log output:\n%v\n", strings.Join(rep.Logs, "\n"))
+	}
+	if rep.Prefs != "" {
+		fmt.Fprintf(&sb, "\nPreferences:\n%v\n", rep.Prefs)
+	}
+	return sb.String()
+}
+
+// Save saves the report to given file
+func (rep *CrashReport) Save(filename string) error {
+	err := os.WriteFile(filename, []byte(rep.String()), 0644)
+	if err != nil {
+		Logf(LogError, "gi.CrashReport", "Save: %v", err)
+	}
+	return err
+}
+
+// SaveDefault saves the report to a file named by its time in the
+// crash-reports directory in the GoGi prefs directory, returning the file
+// name
+func (rep *CrashReport) SaveDefault() (string, error) {
+	dir := filepath.Join(oswin.TheApp.GoGiPrefsDir(), "crash-reports")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		Logf(LogError, "gi.CrashReport", "SaveDefault: %v", err)
+		return "", err
+	}
+	fn := filepath.Join(dir, oswin.TheApp.Name()+"-"+rep.Time.Format("2006-01-02-15-04-05")+".txt")
+	return fn, rep.Save(fn)
+}
+
+// ProcessEventRecover processes given event as in ProcessEvent, recovering
+// from any panic if RecoverPanics is set -- see HandlePanic
+func (w *Window) ProcessEventRecover(evi oswin.Event) {
+	w.RunRecover(func() { w.ProcessEvent(evi) })
+}
+
+// RunRecover runs given function on the event loop of the window,
+// recovering from any panic if RecoverPanics is set -- see HandlePanic.
+// The event loop runs the events and the frame and idle tasks this way.
+func (w *Window) RunRecover(fun func()) {
+	if !RecoverPanics {
+		fun()
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			w.HandlePanic(r, debug.Stack())
+		}
+	}()
+	fun()
+}
+
+// crashDialogOpen is 1 while the crash dialog is open, so further panics
+// (e.g., repeated in each event) are only logged -- accessed atomically,
+// as panics can be handled in the event loops of several windows
+var crashDialogOpen int32
+
+// HandlePanic handles a panic recovered in the window event loop: it logs
+// it, calls CrashReportFunc, and opens a CrashDialog
+func (w *Window) HandlePanic(r any, stack []byte) {
+	Logf(LogError, "gi.Window", "%v: recovered from panic: %v\n%s", w.Nm, r, stack)
+	rep := NewCrashReport(w.Nm, r, stack)
+	if CrashReportFunc != nil {
+		CrashReportFunc(rep)
+	}
+	w.ClearWinUpdating()
+	if w.IsClosed() || !atomic.CompareAndSwapInt32(&crashDialogOpen, 0, 1) {
+		return
+	}
+	CrashDialog(w.Viewport, rep)
+}
+
+// CrashDialog opens a dialog showing given crash report, with buttons to
+// save the report, continue, or quit the app
+func CrashDialog(avp *Viewport2D, rep *CrashReport) {
+	dlg := NewStdDialog(DlgOpts{Title: "Unexpected Error", Prompt: "An unexpected error occurred: <b>" + html.EscapeString(rep.Panic) + "</b><br>The app has recovered, but may not work correctly -- save your work and restart it.  Please save the crash report and send it to the developers."}, NoOk, NoCancel)
+	dlg.Modal = true
+	atomic.StoreInt32(&crashDialogOpen, 1)
+
+	frame := dlg.Frame()
+	_, prIdx := dlg.PromptWidget(frame)
+	sfr := frame.InsertNewChild(KiT_Frame, prIdx+1, "stack").(*Frame)
+	sfr.Lay = LayoutVert
+	sfr.SetProp("overflow", gist.OverflowAuto)
+	sfr.SetProp("max-height", units.NewEm(20))
+	sfr.SetProp("width", units.NewCh(80))
+	sfr.SetStretchMax()
+	sl := AddNewLabel(sfr, "stack", html.EscapeString(rep.Stack))
+	sl.SetProp("white-space", gist.WhiteSpacePre)
+	sl.SetProp("font-family", Prefs.MonoFont)
+
+	bb := dlg.AddButtonBox(frame)
+	sv := AddNewButton(bb, "save-report")
+	sv.SetText("Save Report")
+	sv.ButtonSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(ButtonClicked) {
+			return
+		}
+		fn, err := rep.SaveDefault()
+		if err != nil {
+			PromptDialog(avp, DlgOpts{Title: "Crash Report Not Saved", Prompt: err.Error()}, AddOk, NoCancel, nil, nil)
+			return
+		}
+		PromptDialog(avp, DlgOpts{Title: "Crash Report Saved", Prompt: "The crash report was saved to: " + fn}, AddOk, NoCancel, nil, nil)
+	})
+	cn := AddNewButton(bb, "continue")
+	cn.SetText("Continue")
+	cn.ButtonSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(ButtonClicked) {
+			dlg.Accept()
+		}
+	})
+	qt := AddNewButton(bb, "quit")
+	qt.SetText("Quit")
+	qt.ButtonSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(ButtonClicked) {
+			dlg.Cancel()
+			Quit()
+		}
+	})
+	dlg.DialogSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data any) {
+		atomic.StoreInt32(&crashDialogOpen, 0)
+	})
+
+	dlg.UpdateEndNoSig(true) // going to be shown
+	dlg.Open(0, 0, avp, nil)
+}
@@ -5,8 +5,6 @@
 package gi
 
 import (
-	"log"
-
 	"github.com/aymerick/douceur/css"
 	"github.com/aymerick/douceur/parser"
 
@@ -45,7 +43,7 @@ func (ss *StyleSheet) CopyFieldsFrom(frm any) {
 func (ss *StyleSheet) ParseString(str string) error {
 	pss, err := parser.Parse(str)
 	if err != nil {
-		log.Printf("gist.StyleSheet ParseString parser error: %v\n", err)
+		Logf(LogError, "gi.StyleSheet", "ParseString parser error: %v", err)
 		return err
 	}
 	ss.Sheet = pss
@@ -7,7 +7,6 @@ package gi
 import (
 	"fmt"
 	"image"
-	"log"
 	"reflect"
 
 	"github.com/iancoleman/strcase"
@@ -75,6 +74,15 @@ type Dialog struct {
 	// state of the dialog
 	State DialogState `desc:"state of the dialog"`
 
+	// sound played when the dialog is opened -- see PlaySound
+	Sound SoundEvents `desc:"sound played when the dialog is opened -- see PlaySound"`
+
+	// location of the help for the dialog, in the HelpBundles -- if set, a ? button in the header opens it, as does the Help key function (F1) -- see HelpURIFor
+	HelpURI string `desc:"location of the help for the dialog, in the HelpBundles -- if set, a ? button in the header opens it, as does the Help key function (F1) -- see HelpURIFor"`
+
+	// if true, the dialog can't be accepted, e.g., because its content is not valid -- the Ok button is inactive -- see SetAcceptable
+	NotAcceptable bool `desc:"if true, the dialog can't be accepted, e.g., because its content is not valid -- the Ok button is inactive -- see SetAcceptable"`
+
 	// signal value that will be sent, if >= 0 (by default, DialogAccepted or DialogCanceled will be sent for standard Ok / Cancel buttons)
 	SigVal int64 `desc:"signal value that will be sent, if >= 0 (by default, DialogAccepted or DialogCanceled will be sent for standard Ok / Cancel buttons)"`
 
@@ -104,7 +112,7 @@ func ValidViewport(avp *Viewport2D) *Viewport2D {
 	if fwin := AllWindows.Win(0); fwin != nil {
 		return fwin.Viewport
 	}
-	log.Printf("gi.ValidViewport: No gi.AllWindows to get viewport from!\n")
+	Logf(LogWarn, "gi.ValidViewport", "No gi.AllWindows to get viewport from!")
 	return nil
 }
 
@@ -128,6 +136,7 @@ func (dlg *Dialog) Open(x, y int, avp *Viewport2D, cfgFunc func()) bool {
 	} else {
 		dlg.State = DialogOpenModeless
 	}
+	PlaySound(dlg.Sound)
 
 	if DialogsSepWindow {
 		win = NewDialogWin(dlg.Nm, dlg.Title, 100, 100, dlg.Modal)
@@ -240,7 +249,7 @@ func (dlg *Dialog) Close() {
 
 // Accept accepts the dialog, activated by the default Ok button
 func (dlg *Dialog) Accept() {
-	if dlg == nil {
+	if dlg == nil || dlg.NotAcceptable {
 		return
 	}
 	dlg.State = DialogAccepted
@@ -252,6 +261,23 @@ func (dlg *Dialog) Accept() {
 	dlg.Close()
 }
 
+// SetAcceptable sets whether the dialog can be accepted, e.g., whether its
+// content is valid, making the Ok button inactive if not -- safe to call
+// from another goroutine
+func (dlg *Dialog) SetAcceptable(acc bool) {
+	if dlg == nil {
+		return
+	}
+	dlg.NotAcceptable = !acc
+	bb, _ := dlg.ButtonBox(dlg.Frame())
+	if bb == nil {
+		return
+	}
+	if okb, ok := bb.ChildByName("ok", 0).(*Button); ok {
+		okb.SetActiveStateUpdt(acc)
+	}
+}
+
 // Cancel cancels the dialog, activated by the default Cancel button
 func (dlg *Dialog) Cancel() {
 	if dlg == nil {
@@ -361,6 +387,27 @@ func (dlg *Dialog) PromptWidgetIdx(frame *Frame) int {
 	return idx
 }
 
+// SetHelpURI sets the HelpURI and adds a header row named "help-header" at
+// the start of given frame layout if passed, with a ? button that opens the
+// help in the Help viewer
+func (dlg *Dialog) SetHelpURI(uri string, frame *Frame) *Action {
+	dlg.HelpURI = uri
+	if frame == nil {
+		return nil
+	}
+	hdr := frame.InsertNewChild(KiT_Layout, 0, "help-header").(*Layout)
+	hdr.Lay = LayoutHoriz
+	hdr.SetProp("max-width", -1)
+	AddNewStretch(hdr, "stretch")
+	hb := AddNewAction(hdr, "help")
+	hb.SetText("?")
+	hb.Tooltip = "Help (" + string(ActiveKeyMap.ShortcutForFun(KeyFunHelp)) + ")"
+	hb.ActionSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data any) {
+		OpenHelp(recv.Embed(KiT_Dialog).(*Dialog).HelpURI)
+	})
+	return hb
+}
+
 // AddButtonBox adds a button box (Row Layout) named "buttons" to given frame,
 // with an extra space above it
 func (dlg *Dialog) AddButtonBox(frame *Frame) *Layout {
@@ -472,6 +519,12 @@ type DlgOpts struct {
 
 	// optional style properties applied to dialog -- can be used to customize any aspect of existing dialogs
 	CSS ki.Props `desc:"optional style properties applied to dialog -- can be used to customize any aspect of existing dialogs"`
+
+	// optional sound played when the dialog is opened, e.g., SoundError for errors
+	Sound SoundEvents `desc:"optional sound played when the dialog is opened, e.g., SoundError for errors"`
+
+	// optional location of the help for the dialog, as bundle/page.md#anchor in the HelpBundles -- adds a ? button in the header that opens it
+	HelpURI string `desc:"optional location of the help for the dialog, as bundle/page.md#anchor in the HelpBundles -- adds a ? button in the header that opens it"`
 }
 
 // NewStdDialog returns a basic standard dialog with given options (title,
@@ -489,7 +542,12 @@ func NewStdDialog(opts DlgOpts, ok, cancel bool) *Dialog {
 	dlg.InitName(&dlg, nm)
 	dlg.UpdateStart() // guaranteed to be true
 	dlg.CSS = opts.CSS
+	dlg.Sound = opts.Sound
+	SetFocusTrap(dlg.This(), true) // tab cycles within dialog
 	dlg.StdDialog(opts.Title, opts.Prompt, ok, cancel)
+	if opts.HelpURI != "" {
+		dlg.SetHelpURI(opts.HelpURI, dlg.Frame())
+	}
 	return &dlg
 }
 
@@ -540,6 +598,13 @@ func PromptDialog(avp *Viewport2D, opts DlgOpts, ok, cancel bool, recv ki.Ki, fu
 	dlg.Open(0, 0, avp, nil)
 }
 
+// ErrorDialog opens a PromptDialog with an Ok button showing given error,
+// with given title, and plays the SoundError sound.  Viewport is optional
+// to properly contextualize dialog to given master window.
+func ErrorDialog(avp *Viewport2D, title string, err error) {
+	PromptDialog(avp, DlgOpts{Title: title, Prompt: err.Error(), Sound: SoundError}, AddOk, NoCancel, nil, nil)
+}
+
 // ChoiceDialog presents any number of buttons with labels as given, for the
 // user to choose among -- the clicked button number (starting at 0) will be
 // sent to the receiving object and function for dialog signals.  Viewport is
@@ -637,7 +702,7 @@ func NewKiDialogValues(dlg *Dialog) (int, reflect.Type) {
 	if typs.CurVal != nil {
 		typ = typs.CurVal.(reflect.Type)
 	} else {
-		log.Printf("gi.NewKiDialogValues: type is nil\n")
+		Logf(LogError, "gi.NewKiDialogValues", "type is nil")
 	}
 	return n, typ
 }
@@ -0,0 +1,181 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"html"
+	"strings"
+
+	"github.com/goki/ki/ki"
+)
+
+// DirtyProvider is a document or other data that can have unsaved changes,
+// which is registered with the window showing it (see Window.AddDirty):
+// when the window or the app is asked to close, a single dialog lists all
+// those with unsaved changes, with options to save them all, discard the
+// changes, or cancel the close.
+type DirtyProvider interface {
+	// DirtyName returns the name shown for the document in the dialog,
+	// e.g., its file name
+	DirtyName() string
+
+	// IsDirty returns true if there are unsaved changes
+	IsDirty() bool
+
+	// SaveDirty saves the changes -- if it returns an error, it is shown
+	// and the close is canceled
+	SaveDirty() error
+
+	// DiscardDirty discards the changes, e.g., by reverting to the saved
+	// version, before closing
+	DiscardDirty()
+}
+
+// DirtyFuncs is a DirtyProvider with the given name and functions, for
+// data that does not implement the interface itself -- the functions
+// other than Dirty are optional
+type DirtyFuncs struct {
+	Name    string
+	Dirty   func() bool
+	Save    func() error
+	Discard func()
+}
+
+func (df *DirtyFuncs) DirtyName() string {
+	return df.Name
+}
+
+func (df *DirtyFuncs) IsDirty() bool {
+	return df.Dirty()
+}
+
+func (df *DirtyFuncs) SaveDirty() error {
+	if df.Save == nil {
+		return nil
+	}
+	return df.Save()
+}
+
+func (df *DirtyFuncs) DiscardDirty() {
+	if df.Discard != nil {
+		df.Discard()
+	}
+}
+
+// AddDirty registers given provider of unsaved changes shown in the window,
+// whose changes must be saved or discarded before closing the window (or
+// quitting the app) -- see DirtyProvider
+func (w *Window) AddDirty(dp DirtyProvider) {
+	w.DeleteDirty(dp)
+	w.Dirty = append(w.Dirty, dp)
+}
+
+// DeleteDirty removes given provider of unsaved changes from the window,
+// e.g., when the document is closed
+func (w *Window) DeleteDirty(dp DirtyProvider) {
+	for i, d := range w.Dirty {
+		if d == dp {
+			w.Dirty = append(w.Dirty[:i], w.Dirty[i+1:]...)
+			return
+		}
+	}
+}
+
+// DirtyDocs returns the providers registered with the window that
+// currently have unsaved changes
+func (w *Window) DirtyDocs() []DirtyProvider {
+	var dps []DirtyProvider
+	for _, dp := range w.Dirty {
+		if dp.IsDirty() {
+			dps = append(dps, dp)
+		}
+	}
+	return dps
+}
+
+// CloseReqDirty handles a request to close the window: given function,
+// which actually closes it, is called once the unsaved changes of the
+// window (see AddDirty) have all been saved or discarded, per the choice
+// made in the dialog listing them.  This is the default close request
+// function of all windows, with the Close function, and the function set
+// with SetCloseReqFunc is called the same way.
+func (w *Window) CloseReqDirty(fun func()) {
+	ResolveDirty(w.Viewport, w.DirtyDocs(), "Closing", fun)
+}
+
+// QuitReqDirty handles a request to quit the app: given function, which
+// actually quits, is called once the unsaved changes of all the windows
+// (see Window.AddDirty) have all been saved or discarded, per the choice
+// made in the dialog listing them.  This is the default quit request
+// function, with the Quit function, and the function set with
+// SetQuitReqFunc is called the same way.
+func QuitReqDirty(fun func()) {
+	var dps []DirtyProvider
+	var avp *Viewport2D
+	for _, w := range AllWindows {
+		wdps := w.DirtyDocs()
+		if len(wdps) > 0 && avp == nil {
+			avp = w.Viewport
+		}
+		dps = append(dps, wdps...)
+	}
+	ResolveDirty(avp, dps, "Quitting", fun)
+}
+
+// inDirtyPrompt is true while the dialog of ResolveDirty is open, so that
+// repeated close requests do not open it again
+var inDirtyPrompt = false
+
+// ResolveDirty calls given function once the unsaved changes of given
+// providers have all been saved or discarded -- if there are any, a dialog
+// in given viewport lists them, with options to save them all (which
+// cancels if any fails to save), discard the changes, or cancel, in which
+// case the function is not called.  The action is shown in the dialog
+// title, e.g., Closing.
+func ResolveDirty(avp *Viewport2D, dps []DirtyProvider, action string, fun func()) {
+	if len(dps) == 0 {
+		fun()
+		return
+	}
+	if inDirtyPrompt {
+		return
+	}
+	inDirtyPrompt = true
+	var sb strings.Builder
+	if len(dps) == 1 {
+		sb.WriteString("The following document has unsaved changes:<br>\n")
+	} else {
+		sb.WriteString("The following documents have unsaved changes:<br>\n")
+	}
+	for _, dp := range dps {
+		sb.WriteString("&nbsp;&nbsp;&nbsp;&nbsp;" + html.EscapeString(dp.DirtyName()) + "<br>\n")
+	}
+	sb.WriteString("<br>\nDo you want to save the changes?")
+	save := "Save All"
+	if len(dps) == 1 {
+		save = "Save"
+	}
+	ChoiceDialog(avp, DlgOpts{Title: "Save Changes Before " + action + "?", Prompt: sb.String()},
+		[]string{save, "Discard Changes", "Cancel"},
+		avp.This(), func(recv, send ki.Ki, sig int64, data any) {
+			inDirtyPrompt = false
+			switch sig {
+			case 0:
+				for _, dp := range dps {
+					if err := dp.SaveDirty(); err != nil {
+						ErrorDialog(avp, "Could Not Save "+dp.DirtyName(), err)
+						return
+					}
+				}
+				fun()
+			case 1:
+				for _, dp := range dps {
+					dp.DiscardDirty()
+				}
+				fun()
+			default: // Cancel, or closed with Esc
+			}
+		})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// EmptyState is a placeholder shown in place of content that is empty,
+// e.g., a list without any elements: a large icon, a title, a hint (e.g.,
+// about how to add content), and an optional button (e.g., to add it),
+// which emits the ActionSig when clicked -- all centered in the space.
+// Call SetEmptyState, or set the fields and call Config.
+type EmptyState struct {
+	Frame
+	Icon       IconName  `desc:"large icon shown above the title -- none if empty"`
+	Title      string    `desc:"title, e.g., No items"`
+	Hint       string    `desc:"hint below the title, e.g., about how to add content -- can contain html formatting"`
+	ActionText string    `desc:"text of the button below the hint -- no button if empty"`
+	ActionIcon IconName  `desc:"icon of the button"`
+	ActionSig  ki.Signal `copy:"-" json:"-" xml:"-" view:"-" desc:"signal emitted when the button is clicked"`
+}
+
+var KiT_EmptyState = kit.Types.AddType(&EmptyState{}, EmptyStateProps)
+
+// AddNewEmptyState adds a new empty state placeholder to given parent node,
+// with given name.
+func AddNewEmptyState(parent ki.Ki, name string) *EmptyState {
+	return parent.AddNewChild(KiT_EmptyState, name).(*EmptyState)
+}
+
+func (es *EmptyState) CopyFieldsFrom(frm any) {
+	fr := frm.(*EmptyState)
+	es.Frame.CopyFieldsFrom(&fr.Frame)
+	es.Icon = fr.Icon
+	es.Title = fr.Title
+	es.Hint = fr.Hint
+	es.ActionText = fr.ActionText
+	es.ActionIcon = fr.ActionIcon
+}
+
+func (es *EmptyState) Disconnect() {
+	es.Frame.Disconnect()
+	es.ActionSig.DisconnectAll()
+}
+
+// EmptyStateProps are style properties for EmptyState
+var EmptyStateProps = ki.Props{
+	"EnumType:Flag":  KiT_NodeFlags,
+	"padding":        units.NewEm(1),
+	"spacing":        units.NewEm(0.5),
+	"max-width":      -1,
+	"max-height":     -1,
+	"vertical-align": gist.AlignMiddle,
+	"#icon": ki.Props{
+		"width":            units.NewEm(4),
+		"height":           units.NewEm(4),
+		"margin":           units.NewPx(0),
+		"padding":          units.NewPx(0),
+		"fill":             &Prefs.Colors.Icon,
+		"stroke":           &Prefs.Colors.Font,
+		"horizontal-align": gist.AlignCenter,
+	},
+	"#title": ki.Props{
+		"font-size":        "large",
+		"font-weight":      "bold",
+		"horizontal-align": gist.AlignCenter,
+	},
+	"#hint": ki.Props{
+		"color":            "highlight-40",
+		"white-space":      gist.WhiteSpaceNormal,
+		"text-align":       gist.AlignCenter,
+		"max-width":        units.NewCh(60),
+		"horizontal-align": gist.AlignCenter,
+	},
+	"#action": ki.Props{
+		"horizontal-align": gist.AlignCenter,
+	},
+}
+
+// SetEmptyState sets the icon, title, hint and button text, and updates
+// the display
+func (es *EmptyState) SetEmptyState(icon IconName, title, hint, action string) {
+	es.Icon = icon
+	es.Title = title
+	es.Hint = hint
+	es.ActionText = action
+	es.Config()
+}
+
+// Config configures the children for the current fields -- elements
+// without content are omitted
+func (es *EmptyState) Config() {
+	es.Lay = LayoutVert
+	config := kit.TypeAndNameList{}
+	if !es.Icon.IsNil() {
+		config.Add(KiT_Icon, "icon")
+	}
+	if es.Title != "" {
+		config.Add(KiT_Label, "title")
+	}
+	if es.Hint != "" {
+		config.Add(KiT_Label, "hint")
+	}
+	if es.ActionText != "" {
+		config.Add(KiT_Button, "action")
+	}
+	mods, updt := es.ConfigChildren(config)
+	if ic, ok := es.ChildByName("icon", 0).(*Icon); ok {
+		ic.SetIcon(string(es.Icon))
+	}
+	if lb, ok := es.ChildByName("title", 1).(*Label); ok {
+		lb.SetText(es.Title)
+	}
+	if lb, ok := es.ChildByName("hint", 2).(*Label); ok {
+		lb.SetText(es.Hint)
+	}
+	if bt, ok := es.ChildByName("action", 3).(*Button); ok {
+		bt.SetText(es.ActionText)
+		if !es.ActionIcon.IsNil() {
+			bt.SetIcon(string(es.ActionIcon))
+		}
+		bt.ButtonSig.ConnectOnly(es.This(), func(recv, send ki.Ki, sig int64, data any) {
+			if sig == int64(ButtonClicked) {
+				esr := recv.Embed(KiT_EmptyState).(*EmptyState)
+				esr.ActionSig.Emit(esr.This(), 0, nil)
+			}
+		})
+	}
+	if mods {
+		es.SetFullReRender()
+		es.UpdateEnd(updt)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/oswin/touch"
+	"github.com/goki/ki/ki"
+)
+
+// EventRecTarget records one receiver that an event was sent to
+type EventRecTarget struct {
+	Path      string    `desc:"path of the receiving node"`
+	Pri       EventPris `desc:"priority of the event signal connection"`
+	Processed bool      `desc:"true if the event was processed after calling this receiver"`
+}
+
+// EventRecord is one recorded event, as written to the event trace file,
+// one JSON record per line
+type EventRecord struct {
+	Time      time.Time        `desc:"time that the event was processed"`
+	Type      oswin.EventType  `desc:"type of event"`
+	Desc      string           `desc:"string description of the event"`
+	Event     json.RawMessage  `desc:"full event data, as JSON, which is used for replay"`
+	Targets   []EventRecTarget `desc:"receivers the event was sent to, in order"`
+	Processed bool             `desc:"true if the event was processed by the end of event handling"`
+	Focus     string           `desc:"path of the node with keyboard focus at the end of event handling"`
+}
+
+// EventRecorder records all events routed by the EventMgr for a window,
+// along with the targets they were sent to and whether they were
+// processed, to a trace file, which can be replayed using
+// Window.ReplayEvents.  See EventMgr.StartRecording.
+type EventRecorder struct {
+	Filename string         `desc:"file being recorded to"`
+	File     *os.File       `desc:"open file"`
+	Mu       sync.Mutex     `desc:"mutex protecting recording"`
+	Stack    []*EventRecord `desc:"stack of events currently being processed -- event processing can be nested, e.g., by modal dialogs"`
+	enc      *json.Encoder
+}
+
+// StartRecording starts recording all events routed by the event manager
+// to given file, which is created, as JSON records, one per line.
+func (em *EventMgr) StartRecording(filename string) error {
+	em.StopRecording()
+	f, err := os.Create(filename)
+	if err != nil {
+		Logf(LogError, "gi.EventMgr", "StartRecording: %v", err)
+		return err
+	}
+	er := &EventRecorder{Filename: filename, File: f, enc: json.NewEncoder(f)}
+	em.Rec = er
+	return nil
+}
+
+// StopRecording stops any current recording, closing the file
+func (em *EventMgr) StopRecording() error {
+	er := em.Rec
+	if er == nil {
+		return nil
+	}
+	em.Rec = nil
+	er.Mu.Lock()
+	defer er.Mu.Unlock()
+	return er.File.Close()
+}
+
+// IsRecording returns true if events are currently being recorded
+func (em *EventMgr) IsRecording() bool {
+	return em.Rec != nil
+}
+
+// Start starts the record for given event, at the start of processing
+func (er *EventRecorder) Start(evi oswin.Event) {
+	if er == nil {
+		return
+	}
+	er.Mu.Lock()
+	defer er.Mu.Unlock()
+	rec := &EventRecord{Time: time.Now(), Type: evi.Type(), Desc: evi.String()}
+	ejs, err := json.Marshal(evi)
+	if err == nil {
+		rec.Event = ejs
+	}
+	er.Stack = append(er.Stack, rec)
+}
+
+// AddTarget records that the current event was sent to given receiver
+func (er *EventRecorder) AddTarget(recv ki.Ki, pri EventPris, evi oswin.Event) {
+	if er == nil {
+		return
+	}
+	er.Mu.Lock()
+	defer er.Mu.Unlock()
+	sz := len(er.Stack)
+	if sz == 0 {
+		return
+	}
+	rec := er.Stack[sz-1]
+	rec.Targets = append(rec.Targets, EventRecTarget{Path: recv.Path(), Pri: pri, Processed: evi.IsProcessed()})
+}
+
+// Finish finishes the record for the current event, writing it to the file,
+// with given current focus node (can be nil).
+func (er *EventRecorder) Finish(evi oswin.Event, foc ki.Ki) {
+	if er == nil {
+		return
+	}
+	er.Mu.Lock()
+	defer er.Mu.Unlock()
+	sz := len(er.Stack)
+	if sz == 0 {
+		return
+	}
+	rec := er.Stack[sz-1]
+	er.Stack = er.Stack[:sz-1]
+	rec.Processed = evi.IsProcessed()
+	if foc != nil {
+		rec.Focus = foc.Path()
+	}
+	if err := er.enc.Encode(rec); err != nil {
+		Logf(LogError, "gi.EventRecorder", "error writing to file: %v: %v", er.Filename, err)
+	}
+}
+
+// NewReplayEvent returns a new event of the appropriate concrete type for
+// given event type, for input events that can be replayed (mouse, key,
+// touch), and nil for all others.
+func NewReplayEvent(et oswin.EventType) oswin.Event {
+	switch et {
+	case oswin.MouseEvent:
+		return &mouse.Event{}
+	case oswin.MouseMoveEvent:
+		return &mouse.MoveEvent{}
+	case oswin.MouseDragEvent:
+		return &mouse.DragEvent{}
+	case oswin.MouseScrollEvent:
+		return &mouse.ScrollEvent{}
+	case oswin.KeyEvent:
+		return &key.Event{}
+	case oswin.KeyChordEvent:
+		return &key.ChordEvent{}
+	case oswin.TouchEvent:
+		return &touch.Event{}
+	}
+	return nil
+}
+
+// ReplayEvents reads an event trace file recorded by
+// EventMgr.StartRecording, and sends the recorded input events (mouse, key,
+// touch) back through the window's event queue, so that they go through
+// the full event processing.  Other events (window, focus, etc) are
+// regenerated by the window as usual.  Events are sent with their original
+// relative timing, scaled by 1 / speed -- a speed of 0 sends events as fast
+// as possible.  The window should be in the same state and size as it was
+// at the start of recording, as events are routed by position and focus.
+// Returns an error if the file cannot be read -- the events are then
+// sent in a separate goroutine.
+func (w *Window) ReplayEvents(filename string, speed float32) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		Logf(LogError, "gi.Window", "ReplayEvents: %v", err)
+		return err
+	}
+	var recs []*EventRecord
+	scan := bufio.NewScanner(f)
+	scan.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	ln := 0
+	for scan.Scan() {
+		ln++
+		rec := &EventRecord{}
+		if err := json.Unmarshal(scan.Bytes(), rec); err != nil {
+			f.Close()
+			err = fmt.Errorf("gi.Window ReplayEvents: error in file: %v line: %d: %v", filename, ln, err)
+			Logf(LogError, "gi.Window", "ReplayEvents: %v", err)
+			return err
+		}
+		recs = append(recs, rec)
+	}
+	f.Close()
+	if err := scan.Err(); err != nil {
+		Logf(LogError, "gi.Window", "ReplayEvents: %v", err)
+		return err
+	}
+	go w.replayEvents(recs, speed)
+	return nil
+}
+
+// replayEvents sends given recorded events to the window
+func (w *Window) replayEvents(recs []*EventRecord, speed float32) {
+	var last time.Time
+	for _, rec := range recs {
+		evi := NewReplayEvent(rec.Type)
+		if evi == nil || rec.Event == nil {
+			continue
+		}
+		if err := json.Unmarshal(rec.Event, evi); err != nil {
+			Logf(LogError, "gi.Window", "ReplayEvents: could not decode event: %v: %v", rec.Desc, err)
+			continue
+		}
+		if speed > 0 && !last.IsZero() {
+			time.Sleep(time.Duration(float32(rec.Time.Sub(last)) / speed))
+		}
+		last = rec.Time
+		if w.IsClosed() {
+			return
+		}
+		evi.Init()
+		if eb, ok := evi.(interface{ ClearProcessed() }); ok {
+			eb.ClearProcessed()
+		}
+		w.OSWin.Send(evi)
+	}
+}
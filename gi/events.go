@@ -7,7 +7,6 @@ package gi
 import (
 	"fmt"
 	"image"
-	"log"
 	"sort"
 	"sync"
 	"time"
@@ -74,11 +73,18 @@ type EventMgr struct {
 	FocusMu         sync.RWMutex                            `desc:"mutex that protects focus updating"`
 	FocusStack      []ki.Ki                                 `desc:"stack of focus"`
 	StartFocus      ki.Ki                                   `desc:"node to focus on at start when no other focus has been set yet -- use SetStartFocus"`
+	FocusChainNext  map[ki.Ki]ki.Ki                         `desc:"explicit focus chain links to next node, overriding tree order -- use SetFocusChain"`
+	FocusChainPrev  map[ki.Ki]ki.Ki                         `desc:"explicit focus chain links to previous node, overriding tree order -- use SetFocusChain"`
+	FocusRestore    FocusRestores                           `desc:"policy for restoring focus when a popup is closed -- see RestoreFocus"`
 	LastModBits     int32                                   `desc:"Last modifier key bits from most recent Mouse, Keyboard events"`
 	LastSelMode     mouse.SelectModes                       `desc:"Last Select Mode from most recent Mouse, Keyboard events"`
 	LastMousePos    image.Point                             `desc:"Last mouse position from most recent Mouse events"`
 	LagSkipDeltaPos image.Point                             `desc:"change in position accumulated from skipped-over laggy mouse move events"`
 	LagLastSkipped  bool                                    `desc:"true if last event was skipped due to lag"`
+	Hovered         []ki.Ki                                 `desc:"chain of nodes under the mouse, from the top node down to the innermost one -- see GenMouseFocusEvents"`
+	HoverMu         sync.Mutex                              `desc:"mutex protecting Hovered"`
+	LastLag         time.Duration                           `desc:"lag between the generation and processing of the most recent event -- hover events are dropped when it is large, see IsHoverStale"`
+	Rec             *EventRecorder                          `desc:"event recorder, if recording events -- see StartRecording"`
 	startDrag       *mouse.DragEvent
 	dragStarted     bool
 	startDND        *mouse.DragEvent
@@ -131,7 +137,7 @@ func (wl *WinEventRecvList) AddDepth(recv ki.Ki, fun ki.RecvFunc, par ki.Ki) {
 // priority to given receiver
 func (em *EventMgr) ConnectEvent(recv ki.Ki, et oswin.EventType, pri EventPris, fun ki.RecvFunc) {
 	if et >= oswin.EventTypeN {
-		log.Printf("EventMgr ConnectEvent type: %v is not a known event type\n", et)
+		Logf(LogWarn, "gi.EventMgr", "ConnectEvent type: %v is not a known event type", et)
 		return
 	}
 	em.EventSigs[et][pri].Connect(recv, fun)
@@ -141,7 +147,7 @@ func (em *EventMgr) ConnectEvent(recv ki.Ki, et oswin.EventType, pri EventPris,
 // receiver -- pri is priority -- pass AllPris for all priorities
 func (em *EventMgr) DisconnectEvent(recv ki.Ki, et oswin.EventType, pri EventPris) {
 	if et >= oswin.EventTypeN {
-		log.Printf("EventMgr DisconnectEvent type: %v is not a known event type\n", et)
+		Logf(LogWarn, "gi.EventMgr", "DisconnectEvent type: %v is not a known event type", et)
 		return
 	}
 	if pri == AllPris {
@@ -223,6 +229,7 @@ func (em *EventMgr) SendEventSignal(evi oswin.Event, popup bool) {
 			}
 			em.EventMu.Unlock()
 			rr.Call(send, int64(et), evi) // could call further event loops..
+			em.Rec.AddTarget(rr.Recv, pri, evi)
 			em.EventMu.Lock()
 			if pri != LowRawPri && evi.IsProcessed() { // someone took care of it
 				switch evi.(type) { // only grab events if processed
@@ -253,7 +260,7 @@ func (em *EventMgr) SendEventSignal(evi oswin.Event, popup bool) {
 // SendEventSignalFunc is the inner loop of the SendEventSignal -- needed to deal with
 // map iterator locking logic in a cleaner way.  Returns true to continue, false to break
 func (em *EventMgr) SendEventSignalFunc(evi oswin.Event, popup bool, rvs *WinEventRecvList, recv ki.Ki, fun ki.RecvFunc) bool {
-	if !em.Master.IsInScope(recv, popup) {
+	if !em.Master.IsInScope(recv, popup) || IsBusyBlocked(recv, evi) {
 		return ki.Continue
 	}
 	nii, ni := KiToNode2D(recv)
@@ -495,7 +502,7 @@ func (em *EventMgr) MouseMoveEvents(evi oswin.Event) {
 		em.hoverTimer = time.AfterFunc(time.Duration(HoverStartMSec)*time.Millisecond, func() {
 			em.TimerMu.Lock()
 			hoe := em.curHover
-			if hoe != nil {
+			if hoe != nil && !em.IsHoverStale() {
 				// em.TimerMu.Unlock()
 				em.SendHoverEvent(hoe) // this attempts to lock focus
 				// em.TimerMu.Lock()
@@ -537,60 +544,172 @@ func (em *EventMgr) ResetMouseMove() {
 // GenMouseFocusEvents processes mouse.MoveEvent to generate mouse.FocusEvent
 // events -- returns true if any such events were sent.  If popup is true,
 // then only items on popup are in scope, otherwise items NOT on popup are in
-// scope (if no popup, everything is in scope).
+// scope (if no popup, everything is in scope).  The nodes under the mouse
+// are tracked as a chain from the top node down to the innermost node (see
+// HoverChain): nodes that are no longer in the chain get an Exit event,
+// innermost first, and then nodes that are newly in it get an Enter event,
+// outermost first, so nested widgets always get properly paired events.
+// Widgets also emit WidgetMouseEnter and WidgetMouseLeave on WidgetSig.
 func (em *EventMgr) GenMouseFocusEvents(mev *mouse.MoveEvent, popup bool) bool {
-	fe := mouse.FocusEvent{Event: mev.Event}
-	pos := mev.Pos()
-	ftyp := oswin.MouseFocusEvent
+	em.LastMousePos = mev.Pos()
+	return em.genMouseFocus(mev.Event, popup)
+}
+
+// UpdateMouseFocus generates mouse.FocusEvent events as in GenMouseFocusEvents
+// for the last mouse position, for when the nodes under the mouse may have
+// changed without the mouse moving, e.g., from scrolling or popups opening
+// and closing -- returns true if any such events were sent.
+func (em *EventMgr) UpdateMouseFocus(popup bool) bool {
+	me := mouse.Event{Where: em.LastMousePos, Modifiers: em.LastModBits}
+	me.SetTime()
+	return em.genMouseFocus(me, popup)
+}
+
+func (em *EventMgr) genMouseFocus(me mouse.Event, popup bool) bool {
+	chain := em.HoverChain(me.Where, popup)
+	em.HoverMu.Lock()
+	prv := em.Hovered
+	em.Hovered = chain
+	em.HoverMu.Unlock()
 	updated := false
 	updt := false
-	send := em.Master.EventTopNode()
-	for pri := HiPri; pri < EventPrisN; pri++ {
-		em.EventSigs[ftyp][pri].EmitFiltered(send, int64(ftyp), &fe, func(k ki.Ki) bool {
-			if k.IsDeleted() { // destroyed is filtered upstream
-				return ki.Break
+	send := func(k ki.Ki, act mouse.Actions) {
+		if !updated {
+			updt = em.Master.EventTopUpdateStart()
+			updated = true
+		}
+		em.SendMouseFocusEvent(k, me, act)
+	}
+	for i := len(prv) - 1; i >= 0; i-- {
+		k := prv[i]
+		if hoverChainHas(chain, k) || k.IsDeleted() || k.IsDestroyed() {
+			continue
+		}
+		send(k, mouse.Exit)
+	}
+	for _, k := range chain {
+		if hoverChainHas(prv, k) {
+			continue
+		}
+		send(k, mouse.Enter)
+	}
+	if updated {
+		em.Master.EventTopUpdateEnd(updt)
+	}
+	return updated
+}
+
+// HoverChain returns the chain of nodes under given window position, from
+// the top node down to the innermost one, only including visible nodes in
+// the popup, if popup is true and there is a popup, or otherwise the main
+// window tree.  The chain includes the Parts of widgets and the scrollbars
+// of layouts (see hoverKids).  Where children overlap, the last one is on
+// top.
+func (em *EventMgr) HoverChain(pos image.Point, popup bool) []ki.Ki {
+	var root ki.Ki
+	if popup {
+		root = em.Master.CurPopup()
+	}
+	if root == nil {
+		root = em.Master.EventTopNode()
+	}
+	var chain []ki.Ki
+	k := root
+	for k != nil {
+		nii, ni := KiToNode2D(k)
+		if nii != nil {
+			if ni.IsInvisible() || !ni.PosInWinBBox(pos) {
+				break
 			}
-			if !em.Master.IsInScope(k, popup) {
-				return ki.Break
+			chain = append(chain, k.This())
+			if ly := nii.AsLayout2D(); ly != nil && ly.IsBusy() {
+				break // busy content can't be hovered
 			}
-			_, ni := KiToNode2D(k)
-			if ni != nil {
-				in := ni.PosInWinBBox(pos)
-				if in {
-					if !ni.HasFlag(int(MouseHasEntered)) {
-						fe.Action = mouse.Enter
-						ni.SetFlag(int(MouseHasEntered))
-						if !updated {
-							updt = em.Master.EventTopUpdateStart()
-							updated = true
-						}
-						return ki.Continue // send event
-					} else {
-						return ki.Break // already in
-					}
-				} else { // mouse not in object
-					if ni.HasFlag(int(MouseHasEntered)) {
-						fe.Action = mouse.Exit
-						ni.ClearFlag(int(MouseHasEntered))
-						if !updated {
-							updt = em.Master.EventTopUpdateStart()
-							updated = true
-						}
-						return ki.Continue // send event
-					} else {
-						return ki.Break // already out
-					}
+		}
+		var next ki.Ki
+		kids := hoverKids(k)
+		for i := len(kids) - 1; i >= 0; i-- {
+			kid := kids[i]
+			if _, kn := KiToNode2D(kid); kn != nil && !kn.IsInvisible() && kn.PosInWinBBox(pos) {
+				next = kid
+				break
+			}
+		}
+		k = next
+	}
+	return chain
+}
+
+// hoverKids returns the nodes within given node that can be under the
+// mouse, in rendering order: its Ki fields (e.g., the Parts of a
+// PartsWidgetBase), its children, and the scrollbars of a layout, which are
+// rendered on top
+func hoverKids(k ki.Ki) []ki.Ki {
+	var kids []ki.Ki
+	k.FuncFields(0, nil, func(fk ki.Ki, level int, d any) bool {
+		kids = append(kids, fk)
+		return ki.Continue
+	})
+	kids = append(kids, *k.Children()...)
+	if nii, _ := KiToNode2D(k); nii != nil {
+		if ly := nii.AsLayout2D(); ly != nil {
+			for _, sc := range ly.Scrolls {
+				if sc != nil {
+					kids = append(kids, sc.This())
 				}
-			} else {
-				// 3D
-				return ki.Break
 			}
+		}
+	}
+	return kids
+}
+
+// hoverChainHas returns true if the chain contains given node
+func hoverChainHas(chain []ki.Ki, k ki.Ki) bool {
+	for _, c := range chain {
+		if c == k {
+			return true
+		}
+	}
+	return false
+}
+
+// SendMouseFocusEvent sends a mouse.FocusEvent with given action (Enter or
+// Exit) to given node, updating its MouseHasEntered flag, and emitting the
+// WidgetMouseEnter or WidgetMouseLeave signal if it is a widget
+func (em *EventMgr) SendMouseFocusEvent(k ki.Ki, me mouse.Event, act mouse.Actions) {
+	nii, ni := KiToNode2D(k)
+	if nii == nil {
+		return
+	}
+	if act == mouse.Enter {
+		ni.SetFlag(int(MouseHasEntered))
+	} else {
+		ni.ClearFlag(int(MouseHasEntered))
+	}
+	ftyp := oswin.MouseFocusEvent
+	fe := &mouse.FocusEvent{Event: me}
+	fe.Action = act
+	send := em.Master.EventTopNode()
+	for pri := HiPri; pri < EventPrisN; pri++ {
+		em.EventSigs[ftyp][pri].EmitFiltered(send, int64(ftyp), fe, func(rk ki.Ki) bool {
+			return rk.This() == k.This()
 		})
 	}
-	if updated {
-		em.Master.EventTopUpdateEnd(updt)
+	if wb := nii.AsWidget(); wb != nil {
+		if act == mouse.Enter {
+			wb.WidgetSig.Emit(wb.This(), int64(WidgetMouseEnter), nil)
+		} else {
+			wb.WidgetSig.Emit(wb.This(), int64(WidgetMouseLeave), nil)
+		}
 	}
-	return updated
+}
+
+// IsHovered returns true if given node is under the mouse, as tracked by
+// GenMouseFocusEvents
+func (em *EventMgr) IsHovered(k ki.Ki) bool {
+	em.HoverMu.Lock()
+	defer em.HoverMu.Unlock()
+	return hoverChainHas(em.Hovered, k.This())
 }
 
 // DoInstaDrag tests whether the given mouse DragEvent is on a widget marked
@@ -888,11 +1007,13 @@ func (em *EventMgr) SetFocus(k ki.Ki) bool {
 	}
 	em.setFocusPtr(k)
 	if k == nil {
+		em.sendFocusWithin(cfoc, nil)
 		return true
 	}
 	nii, ni := KiToNode2D(k)
 	if ni == nil || ni.This() == nil { // only 2d for now
 		em.setFocusPtr(nil)
+		em.sendFocusWithin(cfoc, nil)
 		return false
 	}
 	ni.SetFocusState(true)
@@ -900,20 +1021,26 @@ func (em *EventMgr) SetFocus(k ki.Ki) bool {
 	// fmt.Printf("set foc: %v\n", ni.Path())
 	em.ClearNonFocus(k) // shouldn't need this but actually sometimes do
 	nii.FocusChanged2D(FocusGot)
+	em.sendFocusWithin(cfoc, k)
 	return true
 }
 
-//	FocusNext sets the focus on the next item that can accept focus after the
-//
+// FocusNext sets the focus on the next item that can accept focus after the
 // given item (can be nil) -- returns true if a focus item found.
+// An explicit focus chain (SetFocusChain) takes precedence over tree order,
+// and focus stays within the focus scope of the item (see FocusScope).
 func (em *EventMgr) FocusNext(foc ki.Ki) bool {
+	if nxt := em.focusChainNode(foc, false); nxt != nil {
+		em.SetFocus(nxt)
+		return true
+	}
 	gotFocus := false
 	focusNext := false // get the next guy
 	if foc == nil {
 		focusNext = true
 	}
 
-	focRoot := em.Master.FocusTopNode()
+	focRoot := em.FocusScope(foc)
 
 	for i := 0; i < 2; i++ {
 		focRoot.FuncDownMeFirst(0, focRoot, func(k ki.Ki, level int, d any) bool {
@@ -931,6 +1058,9 @@ func (em *EventMgr) FocusNext(foc ki.Ki) bool {
 			if !focusNext {
 				return ki.Continue
 			}
+			if isBusyLayout(k) {
+				return ki.Break // skip busy content
+			}
 			if !ni.CanFocus() {
 				return ki.Continue
 			}
@@ -982,17 +1112,23 @@ func (em *EventMgr) FocusOnOrPrev(foc ki.Ki) bool {
 	return em.FocusPrev(foc)
 }
 
-// FocusPrev sets the focus on the previous item before the given item (can be nil)
+// FocusPrev sets the focus on the previous item before the given item (can be nil).
+// An explicit focus chain (SetFocusChain) takes precedence over tree order,
+// and focus stays within the focus scope of the item (see FocusScope).
 func (em *EventMgr) FocusPrev(foc ki.Ki) bool {
 	if foc == nil { // must have a current item here
 		em.FocusLast()
 		return false
 	}
+	if prv := em.focusChainNode(foc, true); prv != nil {
+		em.SetFocus(prv)
+		return true
+	}
 
 	gotFocus := false
 	var prevItem ki.Ki
 
-	focRoot := em.Master.FocusTopNode()
+	focRoot := em.FocusScope(foc)
 
 	focRoot.FuncDownMeFirst(0, focRoot, func(k ki.Ki, level int, d any) bool {
 		if gotFocus {
@@ -1006,6 +1142,9 @@ func (em *EventMgr) FocusPrev(foc ki.Ki) bool {
 			gotFocus = true
 			return ki.Break
 		}
+		if isBusyLayout(k) {
+			return ki.Break // skip busy content
+		}
 		if !ni.CanFocus() {
 			return ki.Continue
 		}
@@ -1020,18 +1159,22 @@ func (em *EventMgr) FocusPrev(foc ki.Ki) bool {
 	}
 }
 
-// FocusLast sets the focus on the last item in the tree -- returns true if a
+// FocusLast sets the focus on the last item in the tree, within the focus
+// scope of the current focus (see FocusScope) -- returns true if a
 // focusable item was found
 func (em *EventMgr) FocusLast() bool {
 	var lastItem ki.Ki
 
-	focRoot := em.Master.FocusTopNode()
+	focRoot := em.FocusScope(em.CurFocus())
 
 	focRoot.FuncDownMeFirst(0, focRoot, func(k ki.Ki, level int, d any) bool {
 		_, ni := KiToNode2D(k)
 		if ni == nil || ni.This() == nil {
 			return ki.Continue
 		}
+		if isBusyLayout(k) {
+			return ki.Break // skip busy content
+		}
 		if !ni.CanFocus() {
 			return ki.Continue
 		}
@@ -1093,24 +1236,21 @@ func (em *EventMgr) PushFocus(p ki.Ki) {
 	em.FocusOnOrNext(p)
 }
 
-// PopFocus pops off the focus stack and sets prev to current focus.
+// PopFocus pops off the focus stack and restores the previous focus,
+// according to the FocusRestore policy (see RestoreFocus).
 func (em *EventMgr) PopFocus() {
 	em.FocusMu.Lock()
 	if em.FocusStack == nil || len(em.FocusStack) == 0 {
 		em.Focus = nil
+		em.FocusMu.Unlock()
 		return
 	}
 	sz := len(em.FocusStack)
 	em.Focus = nil
 	nxtf := em.FocusStack[sz-1]
-	_, ni := KiToNode2D(nxtf)
-	if ni != nil && ni.This() != nil {
-		em.FocusMu.Unlock()
-		em.SetFocus(nxtf)
-		em.FocusMu.Lock()
-	}
 	em.FocusStack = em.FocusStack[:sz-1]
 	em.FocusMu.Unlock()
+	em.RestoreFocus(nxtf)
 }
 
 // SetStartFocus sets the given item to be first focus when window opens.
@@ -1245,6 +1385,15 @@ func (em *EventMgr) ManagerKeyChordEvents(e *key.ChordEvent) {
 	case KeyFunPrefs:
 		TheViewIFace.PrefsView(&Prefs)
 		e.SetProcessed()
+	case KeyFunLogConsole:
+		TheViewIFace.LogConsole()
+		e.SetProcessed()
+	case KeyFunFindAll:
+		TheViewIFace.FindAll()
+		e.SetProcessed()
+	case KeyFunHelp:
+		OpenHelp(HelpURIFor(em.CurFocus()))
+		e.SetProcessed()
 	}
 }
 
@@ -1275,6 +1424,9 @@ type EventMaster interface {
 	// IsInScope returns whether given node is in scope for receiving events
 	IsInScope(node ki.Ki, popup bool) bool
 
+	// CurPopup returns the current popup, or nil if none
+	CurPopup() ki.Ki
+
 	// CurPopupIsTooltip returns true if current popup is a tooltip
 	CurPopupIsTooltip() bool
 
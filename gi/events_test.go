@@ -0,0 +1,72 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+// testEventMaster is a minimal EventMaster for a tree without a window
+type testEventMaster struct {
+	top ki.Ki
+}
+
+func (tm *testEventMaster) EventTopNode() ki.Ki                   { return tm.top }
+func (tm *testEventMaster) FocusTopNode() ki.Ki                   { return tm.top }
+func (tm *testEventMaster) EventTopUpdateStart() bool             { return false }
+func (tm *testEventMaster) EventTopUpdateEnd(updt bool)           {}
+func (tm *testEventMaster) IsInScope(node ki.Ki, popup bool) bool { return true }
+func (tm *testEventMaster) CurPopup() ki.Ki                       { return nil }
+func (tm *testEventMaster) CurPopupIsTooltip() bool               { return false }
+func (tm *testEventMaster) DeleteTooltip()                        {}
+func (tm *testEventMaster) IsFocusActive() bool                   { return true }
+func (tm *testEventMaster) SetFocusActiveState(active bool)       {}
+
+func TestHoverPartsScrolls(t *testing.T) {
+	top := &Layout{}
+	top.InitName(top, "top")
+	top.WinBBox = image.Rect(0, 0, 200, 200)
+
+	btn := AddNewButton(top, "btn")
+	btn.WinBBox = image.Rect(0, 0, 100, 20)
+	btn.Parts.WinBBox = btn.WinBBox
+	part := btn.Parts.AddNewChild(KiT_Action, "part").(*Action)
+	part.WinBBox = image.Rect(0, 0, 20, 20)
+
+	sc := &ScrollBar{}
+	sc.InitName(sc, "scroll")
+	ki.SetParent(sc, top.This())
+	sc.Dim = mat32.Y
+	sc.WinBBox = image.Rect(190, 0, 200, 200)
+	top.Scrolls[mat32.Y] = sc
+
+	var evs []string
+	for _, wb := range []*WidgetBase{&btn.WidgetBase, &part.WidgetBase, &sc.WidgetBase} {
+		wb.WidgetSig.Connect(top.This(), func(recv, send ki.Ki, sig int64, data any) {
+			switch WidgetSignals(sig) {
+			case WidgetMouseEnter:
+				evs = append(evs, send.Name()+":enter")
+			case WidgetMouseLeave:
+				evs = append(evs, send.Name()+":exit")
+			}
+		})
+	}
+
+	em := &EventMgr{Master: &testEventMaster{top: top.This()}}
+	for _, pos := range []image.Point{{10, 10}, {195, 100}, {150, 150}} {
+		em.genMouseFocus(mouse.Event{Where: pos}, false)
+	}
+	got := strings.Join(evs, " ")
+	want := "btn:enter part:enter part:exit btn:exit scroll:enter scroll:exit"
+	if got != want {
+		t.Errorf("hover events: %v != correct: %v\n", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/cursor"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/ints"
+)
+
+// EyedropperLoupeOn determines whether a loupe showing a magnified view of
+// the pixels around the mouse is shown while sampling a color with the
+// eyedropper (see StartEyedropper)
+var EyedropperLoupeOn = true
+
+// EyedropperLoupePixels is the number of pixels across shown in the loupe
+// -- should be odd, so that the sampled pixel is in the center
+var EyedropperLoupePixels = 11
+
+// EyedropperLoupeZoom is the size in dots of each pixel shown in the loupe
+var EyedropperLoupeZoom = 8
+
+// EyedropperLoupeSpriteName is the name of the sprite of the loupe
+const EyedropperLoupeSpriteName = "gi.Window:EyedropperLoupe"
+
+// eyedropper is the state of the color sampling started by StartEyedropper
+var eyedropper struct {
+	fun func(clr gist.Color)
+	win *Window // window where the cursor was last pushed and the loupe shown
+	mu  sync.Mutex
+}
+
+// StartEyedropper starts sampling a color with the mouse, starting in given
+// window: the cursor becomes a crosshair, with a magnified loupe around it
+// (if EyedropperLoupeOn), and the next left click in any window calls given
+// function with the color of the pixel under the mouse, as last rendered,
+// instead of being processed as usual.  Escape cancels it, in which case the
+// function is not called.  Only the pixels of gi windows can be sampled, as
+// the oswin drivers do not support capturing the rest of the screen.
+func StartEyedropper(win *Window, fun func(clr gist.Color)) {
+	CancelEyedropper()
+	eyedropper.mu.Lock()
+	eyedropper.fun = fun
+	eyedropper.mu.Unlock()
+	setEyedropperWin(win)
+}
+
+// CancelEyedropper cancels the color sampling started by StartEyedropper,
+// if active
+func CancelEyedropper() {
+	endEyedropper()
+}
+
+// EyedropperActive returns true if the color sampling started by
+// StartEyedropper is active
+func EyedropperActive() bool {
+	eyedropper.mu.Lock()
+	defer eyedropper.mu.Unlock()
+	return eyedropper.fun != nil
+}
+
+// setEyedropperWin moves the eyedropper cursor and loupe to given window
+func setEyedropperWin(win *Window) {
+	eyedropper.mu.Lock()
+	prv := eyedropper.win
+	eyedropper.win = win
+	eyedropper.mu.Unlock()
+	if prv == win {
+		return
+	}
+	if prv != nil && !prv.IsClosed() {
+		oswin.TheApp.Cursor(prv.OSWin).PopIf(cursor.Cross)
+		prv.hideEyedropperLoupe()
+	}
+	if win != nil && !win.IsClosed() {
+		oswin.TheApp.Cursor(win.OSWin).Push(cursor.Cross)
+	}
+}
+
+// endEyedropper ends the color sampling, returning the function to call
+func endEyedropper() func(clr gist.Color) {
+	setEyedropperWin(nil)
+	eyedropper.mu.Lock()
+	fun := eyedropper.fun
+	eyedropper.fun = nil
+	eyedropper.mu.Unlock()
+	return fun
+}
+
+// EyedropperEvent processes given event for the color sampling started by
+// StartEyedropper, returning true if it was used
+func (w *Window) EyedropperEvent(evi oswin.Event) bool {
+	if !EyedropperActive() {
+		return false
+	}
+	switch e := evi.(type) {
+	case *mouse.MoveEvent:
+		setEyedropperWin(w)
+		w.showEyedropperLoupe(e.Where)
+		return false // also process as usual, e.g., for hover
+	case *mouse.Event:
+		if e.Button != mouse.Left {
+			return false
+		}
+		e.SetProcessed()
+		if e.Action != mouse.Press {
+			return true
+		}
+		clr, ok := w.PixelColor(e.Where)
+		if fun := endEyedropper(); fun != nil && ok {
+			fun(clr)
+		}
+		return true
+	case *key.ChordEvent:
+		if KeyFun(e.Chord()) == KeyFunAbort {
+			e.SetProcessed()
+			endEyedropper()
+			return true
+		}
+	}
+	return false
+}
+
+// PixelColor returns the color of the pixel at given window position, as
+// last rendered: in the current popup if it is there, and otherwise in the
+// main viewport -- returns false if it is outside of the window
+func (w *Window) PixelColor(pt image.Point) (gist.Color, bool) {
+	if w.IsClosed() {
+		return gist.Color{}, false
+	}
+	if cp := w.CurPopup(); cp != nil {
+		if pvp, ok := cp.Embed(KiT_Viewport2D).(*Viewport2D); ok && pvp.Pixels != nil && pt.In(pvp.WinBBox) {
+			return gist.ColorFromColor(pvp.Pixels.At(pt.X-pvp.WinBBox.Min.X, pt.Y-pvp.WinBBox.Min.Y)), true
+		}
+	}
+	vp := w.Viewport
+	if vp.Pixels == nil || !pt.In(vp.Pixels.Bounds()) {
+		return gist.Color{}, false
+	}
+	return gist.ColorFromColor(vp.Pixels.At(pt.X, pt.Y)), true
+}
+
+// showEyedropperLoupe shows the loupe with the pixels around given window
+// position, just below and to the right of it, if EyedropperLoupeOn
+func (w *Window) showEyedropperLoupe(pt image.Point) {
+	if !EyedropperLoupeOn || w.IsClosed() {
+		return
+	}
+	np := EyedropperLoupePixels
+	zm := EyedropperLoupeZoom
+	sz := image.Point{np*zm + 2, np*zm + 2}
+	sp, ok := w.SpriteByName(EyedropperLoupeSpriteName)
+	if !ok {
+		sp = NewSprite(EyedropperLoupeSpriteName, sz, image.Point{})
+		w.AddSprite(sp)
+	}
+	w.UpMu.Lock()
+	sp.SetSize(sz)
+	img := sp.Pixels
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	half := np / 2
+	for y := 0; y < np; y++ {
+		for x := 0; x < np; x++ {
+			clr, ok := w.PixelColor(pt.Add(image.Point{x - half, y - half}))
+			if !ok {
+				clr = gist.Color{R: 128, G: 128, B: 128, A: 255}
+			}
+			r := image.Rect(1+x*zm, 1+y*zm, 1+(x+1)*zm, 1+(y+1)*zm)
+			draw.Draw(img, r, image.NewUniform(clr), image.Point{}, draw.Src)
+		}
+	}
+	// outline the sampled pixel in the center
+	c := image.Rect(1+half*zm, 1+half*zm, 1+(half+1)*zm, 1+(half+1)*zm)
+	for _, r := range []image.Rectangle{
+		{c.Min, image.Point{c.Max.X, c.Min.Y + 1}}, {image.Point{c.Min.X, c.Max.Y - 1}, c.Max},
+		{c.Min, image.Point{c.Min.X + 1, c.Max.Y}}, {image.Point{c.Max.X - 1, c.Min.Y}, c.Max},
+	} {
+		draw.Draw(img, r, image.NewUniform(color.White), image.Point{}, draw.Src)
+	}
+	pos := pt.Add(image.Point{16, 16})
+	wsz := w.Viewport.Geom.Size
+	if pos.X+sz.X > wsz.X {
+		pos.X = pt.X - 16 - sz.X
+	}
+	if pos.Y+sz.Y > wsz.Y {
+		pos.Y = pt.Y - 16 - sz.Y
+	}
+	pos.X = ints.MaxInt(pos.X, 0)
+	pos.Y = ints.MaxInt(pos.Y, 0)
+	sp.Geom.Pos = pos
+	w.Sprites.Modified = true // new pixels
+	w.UpMu.Unlock()
+	w.ActivateSprite(EyedropperLoupeSpriteName)
+	w.UpdateSig()
+}
+
+// hideEyedropperLoupe hides the loupe, if shown
+func (w *Window) hideEyedropperLoupe() {
+	if _, ok := w.SpriteByName(EyedropperLoupeSpriteName); ok {
+		w.InactivateSprite(EyedropperLoupeSpriteName)
+		w.UpdateSig()
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"strings"
+	"sync"
+)
+
+// FindMatch is one match of the session-wide find (see FindProviders)
+type FindMatch struct {
+	Source string `desc:"name of the source of the match, e.g., the file name of a text buffer -- matches are grouped by source"`
+	Loc    string `desc:"location of the match within the source, e.g., the line or row number"`
+	Text   string `desc:"text containing the match, e.g., the line of text or the label of a tree node"`
+	Goto   func() `desc:"function that navigates to the match: scrolls to it, selects or highlights it, and raises its window"`
+}
+
+// FindProvider is the interface for anything with searchable content,
+// e.g., text buffers, table views and tree views, which registers itself
+// in FindProviders to be searched by the session-wide find.
+type FindProvider interface {
+	// FindName returns the name of the source of the content, which the
+	// matches are grouped under, e.g., the file name of a text buffer
+	FindName() string
+
+	// FindMatches returns all the matches of given text in the content
+	FindMatches(find string, ignoreCase bool) []FindMatch
+}
+
+// FindProviders is the registry of the providers of searchable content
+// that are searched by the session-wide find, which is opened with the
+// FindAll key function (Shift+Control+F by default).
+var FindProviders FindRegistry
+
+// FindRegistry is a registry of FindProviders
+type FindRegistry struct {
+	Provs []FindProvider `desc:"the registered providers, in order added"`
+	Mu    sync.Mutex     `desc:"mutex protecting the providers"`
+}
+
+// Add adds given provider, returning false if it was already registered
+func (fr *FindRegistry) Add(fp FindProvider) bool {
+	fr.Mu.Lock()
+	defer fr.Mu.Unlock()
+	for _, p := range fr.Provs {
+		if p == fp {
+			return false
+		}
+	}
+	fr.Provs = append(fr.Provs, fp)
+	return true
+}
+
+// Remove removes given provider, if it is registered
+func (fr *FindRegistry) Remove(fp FindProvider) {
+	fr.Mu.Lock()
+	defer fr.Mu.Unlock()
+	for i, p := range fr.Provs {
+		if p == fp {
+			fr.Provs = append(fr.Provs[:i], fr.Provs[i+1:]...)
+			return
+		}
+	}
+}
+
+// FindAll returns the matches of given text in all of the providers,
+// with the Source of each set to the FindName of its provider if empty
+func (fr *FindRegistry) FindAll(find string, ignoreCase bool) []FindMatch {
+	if find == "" {
+		return nil
+	}
+	fr.Mu.Lock()
+	provs := append([]FindProvider{}, fr.Provs...)
+	fr.Mu.Unlock()
+	var ms []FindMatch
+	for _, fp := range provs {
+		pms := fp.FindMatches(find, ignoreCase)
+		if len(pms) == 0 {
+			continue
+		}
+		nm := fp.FindName()
+		for i := range pms {
+			if pms[i].Source == "" {
+				pms[i].Source = nm
+			}
+		}
+		ms = append(ms, pms...)
+	}
+	return ms
+}
+
+// FindIndex returns the byte index of the first instance of find in given
+// text, ignoring case if ignoreCase is set, or -1 if not present
+func FindIndex(text, find string, ignoreCase bool) int {
+	if !ignoreCase {
+		return strings.Index(text, find)
+	}
+	return strings.Index(strings.ToLower(text), strings.ToLower(find))
+}
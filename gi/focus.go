@@ -0,0 +1,234 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// FocusTrapProp is the property that marks a node as a focus trap: when
+// focus is within it, Tab focus cycling (FocusNext, FocusPrev) stays within
+// it, as is standard for dialogs.  See SetFocusTrap.
+const FocusTrapProp = "focus-trap"
+
+// SetFocusTrap sets whether given node is a focus trap: when focus is
+// within it, Tab focus cycling stays within it.
+func SetFocusTrap(k ki.Ki, trap bool) {
+	if trap {
+		k.SetProp(FocusTrapProp, true)
+	} else {
+		k.DeleteProp(FocusTrapProp)
+	}
+}
+
+// IsFocusTrap returns true if given node is a focus trap
+func IsFocusTrap(k ki.Ki) bool {
+	if tp := k.Prop(FocusTrapProp); tp != nil {
+		tb, _ := kit.ToBool(tp)
+		return tb
+	}
+	return false
+}
+
+// FocusScope returns the root of the focus scope for given focus node
+// (can be nil): the closest parent (or the node itself) that is a focus
+// trap, or otherwise the overall focus top node (window or current popup).
+// Focus cycling is restricted to within this scope.
+func (em *EventMgr) FocusScope(foc ki.Ki) ki.Ki {
+	top := em.Master.FocusTopNode()
+	if foc == nil || foc.This() == nil {
+		return top
+	}
+	var trap ki.Ki
+	foc.FuncUpParent(0, foc, func(k ki.Ki, level int, d any) bool {
+		if k == top {
+			return ki.Break
+		}
+		if IsFocusTrap(k) {
+			trap = k
+			return ki.Break
+		}
+		return ki.Continue
+	})
+	if trap == nil && IsFocusTrap(foc) {
+		trap = foc
+	}
+	if trap != nil && foc.ParentLevel(top) >= 0 {
+		return trap
+	}
+	return top
+}
+
+// SetFocusChain sets an explicit focus order for the given nodes, which
+// overrides the default tree order for Tab focus cycling: FocusNext from
+// each node goes to the next one in the list, wrapping around at the end,
+// and likewise for FocusPrev.  Nodes that cannot currently take focus are
+// skipped.  Calling with no nodes clears all focus chains.
+func (em *EventMgr) SetFocusChain(chain ...ki.Ki) {
+	em.FocusMu.Lock()
+	defer em.FocusMu.Unlock()
+	if len(chain) == 0 {
+		em.FocusChainNext = nil
+		em.FocusChainPrev = nil
+		return
+	}
+	if em.FocusChainNext == nil {
+		em.FocusChainNext = make(map[ki.Ki]ki.Ki)
+		em.FocusChainPrev = make(map[ki.Ki]ki.Ki)
+	}
+	n := len(chain)
+	for i, k := range chain {
+		nxt := chain[(i+1)%n]
+		em.FocusChainNext[k] = nxt
+		em.FocusChainPrev[nxt] = k
+	}
+}
+
+// focusChainNode returns the next (or previous if prev) node that can
+// take focus in the explicit focus chain from given node, or nil if
+// the node is not in a chain or no other node in its chain can take focus.
+func (em *EventMgr) focusChainNode(foc ki.Ki, prev bool) ki.Ki {
+	if foc == nil {
+		return nil
+	}
+	em.FocusMu.RLock()
+	defer em.FocusMu.RUnlock()
+	links := em.FocusChainNext
+	if prev {
+		links = em.FocusChainPrev
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	k, has := links[foc]
+	for has && k != foc {
+		if !k.IsDeleted() {
+			if _, ni := KiToNode2D(k); ni != nil && ni.This() != nil && ni.CanFocus() && !ni.IsInvisible() {
+				return k
+			}
+		}
+		k, has = links[k]
+	}
+	return nil
+}
+
+// FocusRestores are the policies for restoring focus after a popup or
+// other temporary change of focus, see EventMgr.FocusRestore
+type FocusRestores int32
+
+//go:generate stringer -type=FocusRestores
+
+var KiT_FocusRestores = kit.Enums.AddEnum(FocusRestoresN, kit.NotBitFlag, nil)
+
+const (
+	// RestoreFocusSaved restores focus to the saved node if it is still
+	// valid, and otherwise leaves nothing focused
+	RestoreFocusSaved FocusRestores = iota
+
+	// RestoreFocusOrNext restores focus to the saved node if it is still
+	// valid, and otherwise focuses the next node that can take focus
+	// after its former position, e.g., if it was deleted by the popup
+	RestoreFocusOrNext
+
+	// RestoreFocusNone does not restore focus, leaving nothing focused
+	RestoreFocusNone
+
+	FocusRestoresN
+)
+
+// SaveFocus returns the current focus node, for restoring later with
+// RestoreFocus, e.g., around a popup or other temporary focus change
+func (em *EventMgr) SaveFocus() ki.Ki {
+	return em.CurFocus()
+}
+
+// RestoreFocus restores focus to given saved node, according to the
+// FocusRestore policy -- returns true if focus was set.
+func (em *EventMgr) RestoreFocus(saved ki.Ki) bool {
+	if em.FocusRestore == RestoreFocusNone || saved == nil {
+		return false
+	}
+	if !saved.IsDeleted() && saved.This() != nil {
+		if _, ni := KiToNode2D(saved); ni != nil && ni.This() != nil {
+			em.SetFocus(saved)
+			return true
+		}
+	}
+	if em.FocusRestore != RestoreFocusOrNext {
+		return false
+	}
+	par := saved.Parent()
+	for par != nil && par.IsDeleted() {
+		par = par.Parent()
+	}
+	if par == nil {
+		return em.FocusNext(nil)
+	}
+	return em.FocusNext(par)
+}
+
+// sendFocusWithin sends FocusWithinGot and FocusWithinLost changes to the
+// parents of the new and old focus nodes, excluding common parents of both,
+// so that containers can update when any of their children has focus.
+func (em *EventMgr) sendFocusWithin(old, nw ki.Ki) {
+	top := em.Master.FocusTopNode()
+	pars := func(k ki.Ki) []ki.Ki {
+		var pl []ki.Ki
+		if k == nil || k.This() == nil {
+			return pl
+		}
+		k.FuncUpParent(0, k, func(pk ki.Ki, level int, d any) bool {
+			pl = append(pl, pk)
+			if pk == top {
+				return ki.Break
+			}
+			return ki.Continue
+		})
+		return pl
+	}
+	opars := pars(old)
+	npars := pars(nw)
+	contains := func(pl []ki.Ki, k ki.Ki) bool {
+		for _, pk := range pl {
+			if pk == k {
+				return true
+			}
+		}
+		return false
+	}
+	for _, pk := range opars {
+		if contains(npars, pk) {
+			break
+		}
+		if nii, ni := KiToNode2D(pk); ni != nil && ni.This() != nil {
+			nii.FocusChanged2D(FocusWithinLost)
+		}
+	}
+	for _, pk := range npars {
+		if contains(opars, pk) {
+			break
+		}
+		if nii, ni := KiToNode2D(pk); ni != nil && ni.This() != nil {
+			nii.FocusChanged2D(FocusWithinGot)
+		}
+	}
+}
+
+// HasFocusWithin returns true if this node or any of its children
+// currently has keyboard focus
+func (nb *Node2DBase) HasFocusWithin() bool {
+	if nb.HasFocus() {
+		return true
+	}
+	if nb.Viewport == nil || nb.Viewport.Win == nil {
+		return false
+	}
+	foc := nb.Viewport.Win.EventMgr.CurFocus()
+	if foc == nil {
+		return false
+	}
+	return foc.ParentLevel(nb.This()) >= 0
+}
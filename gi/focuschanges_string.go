@@ -17,12 +17,14 @@ func _() {
 	_ = x[FocusGot-1]
 	_ = x[FocusInactive-2]
 	_ = x[FocusActive-3]
-	_ = x[FocusChangesN-4]
+	_ = x[FocusWithinGot-4]
+	_ = x[FocusWithinLost-5]
+	_ = x[FocusChangesN-6]
 }
 
-const _FocusChanges_name = "FocusLostFocusGotFocusInactiveFocusActiveFocusChangesN"
+const _FocusChanges_name = "FocusLostFocusGotFocusInactiveFocusActiveFocusWithinGotFocusWithinLostFocusChangesN"
 
-var _FocusChanges_index = [...]uint8{0, 9, 17, 30, 41, 54}
+var _FocusChanges_index = [...]uint8{0, 9, 17, 30, 41, 55, 70, 83}
 
 func (i FocusChanges) String() string {
 	if i < 0 || i >= FocusChanges(len(_FocusChanges_index)-1) {
@@ -6,7 +6,6 @@ package gi
 
 import (
 	"image"
-	"log"
 
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/units"
@@ -34,7 +33,7 @@ func AddNewFrame(parent ki.Ki, name string, layout Layouts) *Frame {
 func (fr *Frame) CopyFieldsFrom(frm any) {
 	cp, ok := frm.(*Frame)
 	if !ok {
-		log.Printf("GoGi node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier Frame one\n", ki.Type(fr).Name())
+		Logf(LogWarn, "gi.Frame", "node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier Frame one", ki.Type(fr).Name())
 		ki.GenCopyFieldsFrom(fr.This(), frm)
 		return
 	}
@@ -0,0 +1,181 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/goki/ki/ki"
+)
+
+// HelpBundle is a bundle of help content registered by an app in
+// HelpBundles: a file system of Markdown (.md) pages, which are shown in
+// the Help viewer.
+type HelpBundle struct {
+	Name  string `desc:"name of the bundle, which is the first element of the HelpURIs of its pages"`
+	Title string `desc:"title of the bundle, shown in the contents of the Help viewer"`
+	FS    fs.FS  `desc:"file system with the Markdown (.md) pages of the bundle"`
+	Index string `desc:"path of the page shown for the bundle itself, e.g., index.md"`
+}
+
+// Page returns the contents of the page at given path in the bundle, or
+// the Index page if path is empty
+func (hb *HelpBundle) Page(pg string) ([]byte, error) {
+	if pg == "" {
+		pg = hb.Index
+	}
+	return fs.ReadFile(hb.FS, pg)
+}
+
+// Pages returns the paths of all the Markdown (.md) pages in the bundle,
+// with the Index page first
+func (hb *HelpBundle) Pages() []string {
+	pgs := []string{hb.Index}
+	fs.WalkDir(hb.FS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.ToLower(path.Ext(p)) == ".md" && p != hb.Index {
+			pgs = append(pgs, p)
+		}
+		return nil
+	})
+	return pgs
+}
+
+// HelpBundles is the registry of the help content of the app, shown in the
+// Help viewer, which is opened with the Help key function (F1 by default)
+// at the HelpURI of the widget with the focus.
+var HelpBundles HelpRegistry
+
+// HelpRegistry is a registry of HelpBundles
+type HelpRegistry struct {
+	Bundles []*HelpBundle `desc:"the registered bundles, in order added"`
+	Mu      sync.Mutex    `desc:"mutex protecting the bundles"`
+}
+
+// Add adds a bundle with given name and title, for the Markdown pages in
+// given file system, with the index page being index.md or README.md if
+// present -- replaces any existing bundle with the same name.
+func (hr *HelpRegistry) Add(name, title string, fsys fs.FS) *HelpBundle {
+	hb := &HelpBundle{Name: name, Title: title, FS: fsys, Index: "index.md"}
+	if _, err := fs.Stat(fsys, hb.Index); err != nil {
+		if _, err := fs.Stat(fsys, "README.md"); err == nil {
+			hb.Index = "README.md"
+		}
+	}
+	hr.Mu.Lock()
+	defer hr.Mu.Unlock()
+	for i, b := range hr.Bundles {
+		if b.Name == name {
+			hr.Bundles[i] = hb
+			return hb
+		}
+	}
+	hr.Bundles = append(hr.Bundles, hb)
+	return hb
+}
+
+// Remove removes the bundle with given name, if it is registered
+func (hr *HelpRegistry) Remove(name string) {
+	hr.Mu.Lock()
+	defer hr.Mu.Unlock()
+	for i, b := range hr.Bundles {
+		if b.Name == name {
+			hr.Bundles = append(hr.Bundles[:i], hr.Bundles[i+1:]...)
+			return
+		}
+	}
+}
+
+// Bundle returns the bundle with given name, or nil if there is none
+func (hr *HelpRegistry) Bundle(name string) *HelpBundle {
+	hr.Mu.Lock()
+	defer hr.Mu.Unlock()
+	for _, b := range hr.Bundles {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// All returns a copy of the list of the registered bundles
+func (hr *HelpRegistry) All() []*HelpBundle {
+	hr.Mu.Lock()
+	defer hr.Mu.Unlock()
+	return append([]*HelpBundle(nil), hr.Bundles...)
+}
+
+// Open returns the contents of the page at given HelpURI, along with the
+// bundle and the anchor within the page (if any)
+func (hr *HelpRegistry) Open(uri string) (src []byte, hb *HelpBundle, anchor string, err error) {
+	bnm, pg, anchor := ParseHelpURI(uri)
+	hb = hr.Bundle(bnm)
+	if hb == nil {
+		return nil, nil, anchor, fmt.Errorf("gi.HelpBundles: no help bundle named: %q", bnm)
+	}
+	src, err = hb.Page(pg)
+	return
+}
+
+// ParseHelpURI parses a HelpURI, of the form bundle/page.md#anchor, into
+// the name of the bundle, the path of the page within the bundle (empty
+// for the index page), and the anchor (empty for the top of the page).
+func ParseHelpURI(uri string) (bundle, page, anchor string) {
+	if ai := strings.Index(uri, "#"); ai >= 0 {
+		uri, anchor = uri[:ai], uri[ai+1:]
+	}
+	bundle, page, _ = strings.Cut(uri, "/")
+	return
+}
+
+// HelpAnchor returns the anchor of a heading with given text, as used in
+// HelpURIs: lower case, with spaces replaced by dashes and punctuation
+// other than dashes and underscores removed, as on GitHub.
+func HelpAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// HelpURIFor returns the HelpURI for given node: that of the node itself,
+// or else that of its nearest parent that has one, including Dialogs --
+// empty if there is none.
+func HelpURIFor(k ki.Ki) string {
+	uri := ""
+	if k == nil || k.This() == nil {
+		return uri
+	}
+	k.FuncUp(0, nil, func(kp ki.Ki, level int, d any) bool {
+		if wi := kp.Embed(KiT_WidgetBase); wi != nil {
+			uri = wi.(*WidgetBase).HelpURI
+		} else if di := kp.Embed(KiT_Dialog); di != nil {
+			uri = di.(*Dialog).HelpURI
+		}
+		return uri == ""
+	})
+	return uri
+}
+
+// OpenHelp opens the Help viewer at given HelpURI, or at the contents of
+// all the HelpBundles if it is empty -- web URLs (http:, https:) are opened
+// in the system browser instead.
+func OpenHelp(uri string) {
+	if strings.HasPrefix(uri, "http:") || strings.HasPrefix(uri, "https:") {
+		OpenURL(uri)
+		return
+	}
+	TheViewIFace.HelpView(uri)
+}
@@ -7,6 +7,8 @@ package gi
 import (
 	"image"
 	"image/color"
+	"sort"
+	"strings"
 
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
@@ -27,6 +29,16 @@ func (inm IconName) IsNil() bool {
 	return inm == "" || inm == "none" || inm == "nil"
 }
 
+// Category returns the category of the icon, which is the namespace of
+// an icon in an icon set registered at runtime (e.g., "myapp" for
+// "myapp/logo"), or "" for the default icons
+func (inm IconName) Category() string {
+	if ci := strings.Index(string(inm), "/"); ci > 0 {
+		return string(inm[:ci])
+	}
+	return ""
+}
+
 // IsValid tests whether the icon name is valid -- represents a non-nil icon
 // available in the current or default icon set
 func (inm IconName) IsValid() bool {
@@ -37,11 +49,14 @@ func (inm IconName) IsValid() bool {
 // color information -- it should just be a filled shape where the fill and
 // stroke colors come from the surrounding context / paint settings.  The
 // rendered version is cached for a given size. Icons are always copied from
-// an original source icon and then can be customized from there.
+// an original source icon and then can be customized from there.  Icons with
+// their own colors can be recolored with the "tint" style property, and are
+// desaturated when inactive (see TintParams).
 type Icon struct {
 	WidgetBase
 	IconNm   string `desc:"icon name that has been set -- optimizes to prevent reloading of icon"`
 	Filename string `desc:"file name for the loaded icon, if loaded"`
+	IconGen  int    `desc:"IconsGen when the icon was set -- the icon is reloaded if the available icons have changed since then"`
 }
 
 var KiT_Icon = kit.Types.AddType(&Icon{}, IconProps)
@@ -73,7 +88,7 @@ func (ic *Icon) SetIcon(name string) (bool, error) {
 		ic.DeleteChildren(ki.DestroyKids)
 		return false, nil
 	}
-	if ic.HasChildren() && ic.IconNm == name {
+	if ic.HasChildren() && ic.IconNm == name && ic.IconGen == IconsGen {
 		return false, nil
 	}
 	// pr := prof.Start("IconSetIcon")
@@ -81,6 +96,7 @@ func (ic *Icon) SetIcon(name string) (bool, error) {
 	err := TheIconMgr.SetIcon(ic, name)
 	if err == nil {
 		ic.IconNm = string(name)
+		ic.IconGen = IconsGen
 		return true, nil
 	}
 	return false, err
@@ -180,5 +196,40 @@ type IconMgr interface {
 // import github/goki/gi/svg to get its init function
 var TheIconMgr IconMgr
 
-// CurIconList holds the current icon list, alpha sorted -- set at startup
+// CurIconList holds the current icon list, alpha sorted -- set at startup,
+// and updated by IconsChanged
 var CurIconList []IconName
+
+// IconsGen is incremented by IconsChanged, so that icons already in use
+// are reloaded when next set
+var IconsGen int
+
+// IconsChanged must be called after the set of available icons has changed
+// at runtime (e.g., via svg.AddIconSet) -- updates CurIconList, invalidates
+// the icons already in use so they are reloaded, and updates all open windows.
+func IconsChanged() {
+	IconsGen++
+	IconCache.Reset()
+	if TheIconMgr != nil {
+		CurIconList = TheIconMgr.IconList(true)
+	}
+	for _, w := range AllWindows {
+		w.FullReRender()
+	}
+}
+
+// IconCategories returns the sorted list of unique categories of the given
+// icon names (see IconName.Category), with the default "" category first
+func IconCategories(icons []IconName) []string {
+	cats := []string{""}
+	has := map[string]bool{"": true}
+	for _, ic := range icons {
+		ct := ic.Category()
+		if !has[ct] {
+			has[ct] = true
+			cats = append(cats, ct)
+		}
+	}
+	sort.Strings(cats[1:])
+	return cats
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/gi/oswin"
+)
+
+// InstanceRequest is the request of another launch of a single-instance
+// app (see SingleInstance), forwarded to the running instance
+type InstanceRequest struct {
+	Dir  string   `desc:"working directory of the other launch, for resolving relative file names in its Args"`
+	Args []string `desc:"command-line arguments of the other launch, without the program name"`
+}
+
+// AbsArgs returns the Args of the request, with those that are relative
+// names of existing files made absolute, using the working directory of
+// the launch
+func (ir *InstanceRequest) AbsArgs() []string {
+	args := make([]string, len(ir.Args))
+	for i, a := range ir.Args {
+		args[i] = a
+		if a == "" || strings.HasPrefix(a, "-") || filepath.IsAbs(a) || ir.Dir == "" {
+			continue
+		}
+		fn := filepath.Join(ir.Dir, a)
+		if _, err := os.Stat(fn); err == nil {
+			args[i] = fn
+		}
+	}
+	return args
+}
+
+// InstanceRequestFunc is called, on the event loop of the main window, with
+// the request of each other launch of a single-instance app, after the
+// main window has been raised -- e.g., to open the files in its Args
+var InstanceRequestFunc func(req *InstanceRequest)
+
+// instanceListener is the listener for the requests of other launches,
+// guarded by instanceMu
+var instanceListener net.Listener
+
+// instanceMu guards the instanceListener
+var instanceMu sync.Mutex
+
+// SocketDir returns the private directory of the sockets of the apps of the
+// current user (see SingleInstance and StartAutomation): GoGi in the
+// XDG_RUNTIME_DIR if set, and otherwise sockets in the GoGi prefs dir.  It
+// is created if needed, with access only for the user, and on unix systems
+// an error is returned if it is not a directory owned by the user, or other
+// users have access to it -- sockets in a shared directory, e.g., the temp
+// dir, could be replaced by other users, to intercept the requests.  On
+// Windows, the prefs dir is in the profile of the user, which only the user
+// has access to.
+func SocketDir() (string, error) {
+	dir := ""
+	if rd := os.Getenv("XDG_RUNTIME_DIR"); rd != "" {
+		dir = filepath.Join(rd, "GoGi")
+	} else if oswin.TheApp != nil {
+		dir = filepath.Join(oswin.TheApp.GoGiPrefsDir(), "sockets")
+	} else {
+		cd, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(cd, "GoGi", "sockets")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("gi.SocketDir: %v is not a directory", dir)
+	}
+	if err := checkSocketDir(fi); err != nil {
+		return "", fmt.Errorf("gi.SocketDir: %v: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// appSocket returns the name of the socket in the SocketDir with given
+// prefix, for the app with given name
+func appSocket(prefix, appName string) (string, error) {
+	dir, err := SocketDir()
+	if err != nil {
+		return "", err
+	}
+	nm := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, appName)
+	return filepath.Join(dir, prefix+nm+".sock"), nil
+}
+
+// InstanceSocket returns the name of the socket used for the requests to
+// the single instance of the app with given name, for the current user, in
+// the SocketDir
+func InstanceSocket(appName string) (string, error) {
+	return appSocket("instance-", appName)
+}
+
+// listenSocket listens on given unix domain socket, which only the current
+// user can connect to (see listenPrivate), removing any stale socket of an
+// instance that did not exit cleanly
+func listenSocket(sock string) (net.Listener, error) {
+	os.Remove(sock)
+	return listenPrivate(sock)
+}
+
+// SingleInstance makes this app a single-instance app: if there is already
+// a running instance of the app (with the same AppName, for the current
+// user), the command-line arguments of this launch are forwarded to it,
+// and false is returned -- the app should then exit.  Otherwise, true is
+// returned, and the requests of later launches are passed to given
+// function (see InstanceRequestFunc), after raising the main window.  It
+// must be called after SetAppName and before creating the windows.  The
+// requests are sent through a unix domain socket (also supported on
+// Windows), in the private SocketDir of the user, and errors in setting it
+// up are logged, returning true.
+func SingleInstance(fun func(req *InstanceRequest)) bool {
+	InstanceRequestFunc = fun
+	sock, err := InstanceSocket(AppName())
+	if err != nil {
+		Logf(LogError, "gi.SingleInstance", "no socket for other launches: %v", err)
+		return true
+	}
+	err = SendInstanceRequest(sock)
+	if err == nil {
+		return false
+	}
+	var operr *net.OpError
+	if !errors.As(err, &operr) || operr.Op != "dial" { // running, but did not accept it
+		Logf(LogError, "gi.SingleInstance", "could not send the request to the running instance: %v", err)
+		return false
+	}
+	ln, err := listenSocket(sock)
+	if err != nil {
+		Logf(LogError, "gi.SingleInstance", "could not listen for other launches: %v", err)
+		return true
+	}
+	instanceMu.Lock()
+	if instanceListener != nil {
+		instanceListener.Close()
+	}
+	instanceListener = ln
+	instanceMu.Unlock()
+	go serveInstanceRequests(ln)
+	return true
+}
+
+// SendInstanceRequest sends the command-line arguments of this launch to
+// the running instance listening on given socket, returning an error if
+// there is none (a dial net.OpError), the socket is not owned by the
+// current user, or the instance did not accept the request
+func SendInstanceRequest(sock string) error {
+	if fi, err := os.Lstat(sock); err == nil {
+		if err := checkSocketOwner(fi); err != nil {
+			return fmt.Errorf("gi.SendInstanceRequest: %v: %w", sock, err)
+		}
+	}
+	conn, err := net.DialTimeout("unix", sock, time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(15 * time.Second))
+	req := &InstanceRequest{Args: os.Args[1:]}
+	req.Dir, _ = os.Getwd()
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	resp, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(resp) != "ok" {
+		return errors.New("gi.SendInstanceRequest: request not accepted: " + resp)
+	}
+	return nil
+}
+
+// serveInstanceRequests accepts the requests of other launches, until the
+// listener is closed
+func serveInstanceRequests(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go handleInstanceRequest(conn)
+	}
+}
+
+// handleInstanceRequest reads one request, and passes it to the
+// InstanceRequestFunc on the event loop of the main window
+func handleInstanceRequest(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(15 * time.Second))
+	ln, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	req := &InstanceRequest{}
+	if err := json.Unmarshal(ln, req); err != nil {
+		fmt.Fprintf(conn, "error: %v\n", err)
+		return
+	}
+	var win *Window
+	for i := 0; i < 100; i++ { // wait for the main window if still starting up
+		if oswin.TheApp.IsQuitting() {
+			break
+		}
+		if win = MainWindows.Win(0); win != nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if win == nil {
+		fmt.Fprintf(conn, "error: no main window\n")
+		return
+	}
+	fmt.Fprintf(conn, "ok\n")
+	win.RunOnNextFrame(func() {
+		win.Raise()
+		if InstanceRequestFunc != nil {
+			InstanceRequestFunc(req)
+		}
+	})
+}
+
+// CloseSingleInstance stops listening for the requests of other launches,
+// e.g., when quitting, so that the next launch becomes the single instance
+func CloseSingleInstance() {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+	if instanceListener != nil {
+		instanceListener.Close()
+		instanceListener = nil
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -89,6 +88,13 @@ const (
 	KeyFunWinClose
 	KeyFunWinSnapshot
 	KeyFunGoGiEditor
+	KeyFunLogConsole
+	KeyFunFindAll       // session-wide find in all buffers, tables and trees
+	KeyFunHelp          // context help for the focused widget (F1)
+	KeyFunSplitView     // split the current editor view side-by-side
+	KeyFunSplitViewVert // split the current editor view over / under
+	KeyFunNextView      // focus the next editor view of a split view
+	KeyFunCloseView     // close the current editor view of a split view
 	// Below are menu specific functions -- use these as shortcuts for menu actions
 	// allows uniqueness of mapping and easy customization of all key actions
 	KeyFunMenuNew
@@ -140,12 +146,12 @@ func SetActiveKeyMapName(mapnm KeyMapName) {
 	if ok {
 		SetActiveKeyMap(km, mapnm)
 	} else {
-		log.Printf("gi.SetActiveKeyMapName: key map named: %v not found, using default: %v\n", mapnm, DefaultKeyMap)
+		Logf(LogWarn, "gi.SetActiveKeyMapName", "key map named: %v not found, using default: %v", mapnm, DefaultKeyMap)
 		km, _, ok = AvailKeyMaps.MapByName(DefaultKeyMap)
 		if ok {
 			SetActiveKeyMap(km, DefaultKeyMap)
 		} else {
-			log.Printf("gi.SetActiveKeyMapName: ok, this is bad: DefaultKeyMap not found either -- size of AvailKeyMaps: %v -- trying first one\n", len(AvailKeyMaps))
+			Logf(LogError, "gi.SetActiveKeyMapName", "ok, this is bad: DefaultKeyMap not found either -- size of AvailKeyMaps: %v -- trying first one", len(AvailKeyMaps))
 			if len(AvailKeyMaps) > 0 {
 				nkm := AvailKeyMaps[0]
 				SetActiveKeyMap(&nkm.Map, KeyMapName(nkm.Name))
@@ -212,7 +218,7 @@ func ShortcutForFun(kf KeyFuns) key.Chord {
 func (km *KeyMap) Update(kmName KeyMapName) {
 	for key, val := range *km {
 		if val == KeyFunNil {
-			log.Printf("KeyMap: key function is nil -- probably renamed, for key: %v\n", key)
+			Logf(LogWarn, "gi.KeyMap", "key function is nil -- probably renamed, for key: %v", key)
 			delete(*km, key)
 		}
 	}
@@ -318,7 +324,7 @@ func (km *KeyMaps) OpenJSON(filename FileName) error {
 	if err != nil {
 		// Note: keymaps are opened at startup, and this can cause crash if called then
 		// PromptDialog(nil, DlgOpts{Title: "File Not Found", Prompt: err.Error()}, true, false, nil, nil)
-		log.Println(err)
+		Logf(LogError, "gi.KeyMaps", "OpenJSON: %v", err)
 		return err
 	}
 	*km = make(KeyMaps, 0, 10) // reset
@@ -329,13 +335,13 @@ func (km *KeyMaps) OpenJSON(filename FileName) error {
 func (km *KeyMaps) SaveJSON(filename FileName) error {
 	b, err := json.MarshalIndent(km, "", "  ")
 	if err != nil {
-		log.Println(err) // unlikely
+		Logf(LogError, "gi.KeyMaps", "SaveJSON: %v", err) // unlikely
 		return err
 	}
 	err = ioutil.WriteFile(string(filename), b, 0644)
 	if err != nil {
 		// PromptDialog(nil, DlgOpts{Title: "Could not Save to File", Prompt: err.Error()}, true, false, nil, nil)
-		log.Println(err)
+		Logf(LogError, "gi.KeyMaps", "SaveJSON: %v", err)
 	}
 	return err
 }
@@ -596,6 +602,15 @@ var StdKeyMaps = KeyMaps{
 		"Shift+Control+G":         KeyFunWinSnapshot,
 		"Control+Alt+I":           KeyFunGoGiEditor,
 		"Shift+Control+I":         KeyFunGoGiEditor,
+		"Shift+Control+L":         KeyFunLogConsole,
+		"Shift+Meta+F":            KeyFunFindAll,
+		"F1":                      KeyFunHelp,
+		"Meta+\\":                 KeyFunSplitView,
+		"Shift+Meta+\\":           KeyFunSplitViewVert,
+		"Shift+Meta+|":            KeyFunSplitViewVert,
+		"Alt+Meta+\\":             KeyFunNextView,
+		"Shift+Alt+Meta+\\":       KeyFunCloseView,
+		"Shift+Alt+Meta+|":        KeyFunCloseView,
 		"Meta+N":                  KeyFunMenuNew,
 		"Shift+Meta+N":            KeyFunMenuNewAlt1,
 		"Alt+Meta+N":              KeyFunMenuNewAlt2,
@@ -730,6 +745,15 @@ var StdKeyMaps = KeyMaps{
 		"Shift+Control+G":         KeyFunWinSnapshot,
 		"Control+Alt+I":           KeyFunGoGiEditor,
 		"Shift+Control+I":         KeyFunGoGiEditor,
+		"Shift+Control+L":         KeyFunLogConsole,
+		"Shift+Meta+F":            KeyFunFindAll,
+		"F1":                      KeyFunHelp,
+		"Meta+\\":                 KeyFunSplitView,
+		"Shift+Meta+\\":           KeyFunSplitViewVert,
+		"Shift+Meta+|":            KeyFunSplitViewVert,
+		"Alt+Meta+\\":             KeyFunNextView,
+		"Shift+Alt+Meta+\\":       KeyFunCloseView,
+		"Shift+Alt+Meta+|":        KeyFunCloseView,
 		"Meta+N":                  KeyFunMenuNew,
 		"Shift+Meta+N":            KeyFunMenuNewAlt1,
 		"Alt+Meta+N":              KeyFunMenuNewAlt2,
@@ -849,6 +873,15 @@ var StdKeyMaps = KeyMaps{
 		"Shift+Control+G":         KeyFunWinSnapshot,
 		"Control+Alt+I":           KeyFunGoGiEditor,
 		"Shift+Control+I":         KeyFunGoGiEditor,
+		"Shift+Control+L":         KeyFunLogConsole,
+		"Shift+Alt+F":             KeyFunFindAll,
+		"F1":                      KeyFunHelp,
+		"Control+\\":              KeyFunSplitView,
+		"Shift+Control+\\":        KeyFunSplitViewVert,
+		"Shift+Control+|":         KeyFunSplitViewVert,
+		"Control+Alt+\\":          KeyFunNextView,
+		"Shift+Control+Alt+\\":    KeyFunCloseView,
+		"Shift+Control+Alt+|":     KeyFunCloseView,
 		"Alt+N":                   KeyFunMenuNew, // ctrl keys conflict..
 		"Shift+Alt+N":             KeyFunMenuNewAlt1,
 		"Control+Alt+N":           KeyFunMenuNewAlt2,
@@ -938,6 +971,15 @@ var StdKeyMaps = KeyMaps{
 		"Control+Alt+G":           KeyFunWinSnapshot,
 		"Shift+Control+G":         KeyFunWinSnapshot,
 		"Shift+Control+I":         KeyFunGoGiEditor,
+		"Shift+Control+L":         KeyFunLogConsole,
+		"Shift+Control+F":         KeyFunFindAll,
+		"F1":                      KeyFunHelp,
+		"Control+\\":              KeyFunSplitView,
+		"Shift+Control+\\":        KeyFunSplitViewVert,
+		"Shift+Control+|":         KeyFunSplitViewVert,
+		"Control+Alt+\\":          KeyFunNextView,
+		"Shift+Control+Alt+\\":    KeyFunCloseView,
+		"Shift+Control+Alt+|":     KeyFunCloseView,
 		"Shift+Control+N":         KeyFunMenuNewAlt1,
 		"Control+Alt+N":           KeyFunMenuNewAlt2,
 		"Control+O":               KeyFunMenuOpen,
@@ -1025,6 +1067,15 @@ var StdKeyMaps = KeyMaps{
 		"Control+Alt+G":           KeyFunWinSnapshot,
 		"Shift+Control+G":         KeyFunWinSnapshot,
 		"Shift+Control+I":         KeyFunGoGiEditor,
+		"Shift+Control+L":         KeyFunLogConsole,
+		"Shift+Control+F":         KeyFunFindAll,
+		"F1":                      KeyFunHelp,
+		"Control+\\":              KeyFunSplitView,
+		"Shift+Control+\\":        KeyFunSplitViewVert,
+		"Shift+Control+|":         KeyFunSplitViewVert,
+		"Control+Alt+\\":          KeyFunNextView,
+		"Shift+Control+Alt+\\":    KeyFunCloseView,
+		"Shift+Control+Alt+|":     KeyFunCloseView,
 		"Control+N":               KeyFunMenuNew,
 		"Shift+Control+N":         KeyFunMenuNewAlt1,
 		"Control+Alt+N":           KeyFunMenuNewAlt2,
@@ -1113,6 +1164,15 @@ var StdKeyMaps = KeyMaps{
 		"Control+Alt+G":           KeyFunWinSnapshot,
 		"Shift+Control+G":         KeyFunWinSnapshot,
 		"Shift+Control+I":         KeyFunGoGiEditor,
+		"Shift+Control+L":         KeyFunLogConsole,
+		"Shift+Control+F":         KeyFunFindAll,
+		"F1":                      KeyFunHelp,
+		"Control+\\":              KeyFunSplitView,
+		"Shift+Control+\\":        KeyFunSplitViewVert,
+		"Shift+Control+|":         KeyFunSplitViewVert,
+		"Control+Alt+\\":          KeyFunNextView,
+		"Shift+Control+Alt+\\":    KeyFunCloseView,
+		"Shift+Control+Alt+|":     KeyFunCloseView,
 		"Control+N":               KeyFunMenuNew,
 		"Shift+Control+N":         KeyFunMenuNewAlt1,
 		"Control+Alt+N":           KeyFunMenuNewAlt2,
@@ -68,23 +68,30 @@ func _() {
 	_ = x[KeyFunWinClose-52]
 	_ = x[KeyFunWinSnapshot-53]
 	_ = x[KeyFunGoGiEditor-54]
-	_ = x[KeyFunMenuNew-55]
-	_ = x[KeyFunMenuNewAlt1-56]
-	_ = x[KeyFunMenuNewAlt2-57]
-	_ = x[KeyFunMenuOpen-58]
-	_ = x[KeyFunMenuOpenAlt1-59]
-	_ = x[KeyFunMenuOpenAlt2-60]
-	_ = x[KeyFunMenuSave-61]
-	_ = x[KeyFunMenuSaveAs-62]
-	_ = x[KeyFunMenuSaveAlt-63]
-	_ = x[KeyFunMenuCloseAlt1-64]
-	_ = x[KeyFunMenuCloseAlt2-65]
-	_ = x[KeyFunsN-66]
+	_ = x[KeyFunLogConsole-55]
+	_ = x[KeyFunFindAll-56]
+	_ = x[KeyFunHelp-57]
+	_ = x[KeyFunSplitView-58]
+	_ = x[KeyFunSplitViewVert-59]
+	_ = x[KeyFunNextView-60]
+	_ = x[KeyFunCloseView-61]
+	_ = x[KeyFunMenuNew-62]
+	_ = x[KeyFunMenuNewAlt1-63]
+	_ = x[KeyFunMenuNewAlt2-64]
+	_ = x[KeyFunMenuOpen-65]
+	_ = x[KeyFunMenuOpenAlt1-66]
+	_ = x[KeyFunMenuOpenAlt2-67]
+	_ = x[KeyFunMenuSave-68]
+	_ = x[KeyFunMenuSaveAs-69]
+	_ = x[KeyFunMenuSaveAlt-70]
+	_ = x[KeyFunMenuCloseAlt1-71]
+	_ = x[KeyFunMenuCloseAlt2-72]
+	_ = x[KeyFunsN-73]
 }
 
-const _KeyFuns_name = "KeyFunNilKeyFunMoveUpKeyFunMoveDownKeyFunMoveRightKeyFunMoveLeftKeyFunPageUpKeyFunPageDownKeyFunHomeKeyFunEndKeyFunDocHomeKeyFunDocEndKeyFunWordRightKeyFunWordLeftKeyFunFocusNextKeyFunFocusPrevKeyFunEnterKeyFunAcceptKeyFunCancelSelectKeyFunSelectModeKeyFunSelectAllKeyFunAbortKeyFunCopyKeyFunCutKeyFunPasteKeyFunPasteHistKeyFunBackspaceKeyFunBackspaceWordKeyFunDeleteKeyFunDeleteWordKeyFunKillKeyFunDuplicateKeyFunTransposeKeyFunTransposeWordKeyFunUndoKeyFunRedoKeyFunInsertKeyFunInsertAfterKeyFunZoomOutKeyFunZoomInKeyFunPrefsKeyFunRefreshKeyFunRecenterKeyFunCompleteKeyFunLookupKeyFunSearchKeyFunFindKeyFunReplaceKeyFunJumpKeyFunHistPrevKeyFunHistNextKeyFunMenuKeyFunWinFocusNextKeyFunWinCloseKeyFunWinSnapshotKeyFunGoGiEditorKeyFunMenuNewKeyFunMenuNewAlt1KeyFunMenuNewAlt2KeyFunMenuOpenKeyFunMenuOpenAlt1KeyFunMenuOpenAlt2KeyFunMenuSaveKeyFunMenuSaveAsKeyFunMenuSaveAltKeyFunMenuCloseAlt1KeyFunMenuCloseAlt2KeyFunsN"
+const _KeyFuns_name = "KeyFunNilKeyFunMoveUpKeyFunMoveDownKeyFunMoveRightKeyFunMoveLeftKeyFunPageUpKeyFunPageDownKeyFunHomeKeyFunEndKeyFunDocHomeKeyFunDocEndKeyFunWordRightKeyFunWordLeftKeyFunFocusNextKeyFunFocusPrevKeyFunEnterKeyFunAcceptKeyFunCancelSelectKeyFunSelectModeKeyFunSelectAllKeyFunAbortKeyFunCopyKeyFunCutKeyFunPasteKeyFunPasteHistKeyFunBackspaceKeyFunBackspaceWordKeyFunDeleteKeyFunDeleteWordKeyFunKillKeyFunDuplicateKeyFunTransposeKeyFunTransposeWordKeyFunUndoKeyFunRedoKeyFunInsertKeyFunInsertAfterKeyFunZoomOutKeyFunZoomInKeyFunPrefsKeyFunRefreshKeyFunRecenterKeyFunCompleteKeyFunLookupKeyFunSearchKeyFunFindKeyFunReplaceKeyFunJumpKeyFunHistPrevKeyFunHistNextKeyFunMenuKeyFunWinFocusNextKeyFunWinCloseKeyFunWinSnapshotKeyFunGoGiEditorKeyFunLogConsoleKeyFunFindAllKeyFunHelpKeyFunSplitViewKeyFunSplitViewVertKeyFunNextViewKeyFunCloseViewKeyFunMenuNewKeyFunMenuNewAlt1KeyFunMenuNewAlt2KeyFunMenuOpenKeyFunMenuOpenAlt1KeyFunMenuOpenAlt2KeyFunMenuSaveKeyFunMenuSaveAsKeyFunMenuSaveAltKeyFunMenuCloseAlt1KeyFunMenuCloseAlt2KeyFunsN"
 
-var _KeyFuns_index = [...]uint16{0, 9, 21, 35, 50, 64, 76, 90, 100, 109, 122, 134, 149, 163, 178, 193, 204, 216, 234, 250, 265, 276, 286, 295, 306, 321, 336, 355, 367, 383, 393, 408, 423, 442, 452, 462, 474, 491, 504, 516, 527, 540, 554, 568, 580, 592, 602, 615, 625, 639, 653, 663, 681, 695, 712, 728, 741, 758, 775, 789, 807, 825, 839, 855, 872, 891, 910, 918}
+var _KeyFuns_index = [...]uint16{0, 9, 21, 35, 50, 64, 76, 90, 100, 109, 122, 134, 149, 163, 178, 193, 204, 216, 234, 250, 265, 276, 286, 295, 306, 321, 336, 355, 367, 383, 393, 408, 423, 442, 452, 462, 474, 491, 504, 516, 527, 540, 554, 568, 580, 592, 602, 615, 625, 639, 653, 663, 681, 695, 712, 728, 744, 757, 767, 782, 801, 815, 830, 843, 860, 877, 891, 909, 927, 941, 957, 974, 993, 1012, 1020}
 
 func (i KeyFuns) String() string {
 	if i < 0 || i >= KeyFuns(len(_KeyFuns_index)-1) {
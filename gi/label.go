@@ -23,13 +23,14 @@ import (
 // Label
 
 // Label is a widget for rendering text labels -- supports full widget model
-// including box rendering, and full HTML styling, including links -- LinkSig
-// emits link with data of URL -- opens default browser if nobody receiving
-// signal.  The default white-space option is 'pre' -- set to 'normal' or
-// other options to get word-wrapping etc.
+// including box rendering, and full HTML styling (or Markdown, see Format),
+// including links -- LinkSig emits link with data of URL -- opens default
+// browser if nobody receiving signal.  The default white-space option is
+// 'pre' -- set to 'normal' or other options to get word-wrapping etc.
 type Label struct {
 	WidgetBase
 	Text        string                   `xml:"text" desc:"label to display"`
+	Format      LabelFormats             `xml:"format" desc:"format of the Text: HTML (the default), or Markdown, which is converted to HTML (see girl.MarkdownToHTML) -- set white-space to normal for its paragraphs to wrap"`
 	Selectable  bool                     `desc:"is this label selectable? if so, it will change background color in response to selection events and update selection state on mouse clicks"`
 	Redrawable  bool                     `desc:"is this label going to be redrawn frequently without an overall full re-render?  if so, you need to set this flag to avoid weird overlapping rendering results from antialiasing.  Also, if the label will change dynamically, this must be set to true, otherwise labels will illegibly overlay on top of each other."`
 	LinkSig     ki.Signal                `copy:"-" json:"-" xml:"-" view:"-" desc:"signal for clicking on a link -- data is a string of the URL -- if nobody receiving this signal, calls TextLinkHandler then URLHandler"`
@@ -52,6 +53,7 @@ func (lb *Label) CopyFieldsFrom(frm any) {
 	fr := frm.(*Label)
 	lb.WidgetBase.CopyFieldsFrom(&fr.WidgetBase)
 	lb.Text = fr.Text
+	lb.Format = fr.Format
 	lb.Selectable = fr.Selectable
 	lb.Redrawable = fr.Redrawable
 }
@@ -104,6 +106,26 @@ var KiT_LabelStates = kit.Enums.AddEnumAltLower(LabelStatesN, kit.NotBitFlag, gi
 func (ev LabelStates) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *LabelStates) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
+// LabelFormats are the formats of the text of a label
+type LabelFormats int32
+
+const (
+	// LabelHTML is text with inline HTML formatting tags (see girl.Text.SetHTML)
+	LabelHTML LabelFormats = iota
+
+	// LabelMarkdown is Markdown-formatted text (see girl.Text.SetMarkdown)
+	LabelMarkdown
+
+	LabelFormatsN
+)
+
+//go:generate stringer -type=LabelFormats
+
+var KiT_LabelFormats = kit.Enums.AddEnumAltLower(LabelFormatsN, kit.NotBitFlag, gist.StylePropProps, "Label")
+
+func (ev LabelFormats) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *LabelFormats) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
 // LabelSelectors are Style selector names for the different states:
 var LabelSelectors = []string{":active", ":inactive", ":selected"}
 
@@ -130,10 +152,9 @@ func (lb *Label) SetText(txt string) {
 	lb.Text = txt
 	lb.Sty.Font.BgColor.Color.SetToNil() // always use transparent bg for actual text
 	// this makes it easier for it to update with dynamic bgs
-	if lb.Text == "" {
-		lb.Render.SetHTML(" ", &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
-	} else {
-		lb.Render.SetHTML(lb.Text, &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
+	htxt := lb.Text
+	if htxt == "" {
+		htxt = " "
 	}
 	spc := lb.BoxSpace()
 	sz := lb.LayState.Alloc.Size
@@ -143,7 +164,7 @@ func (lb *Label) SetText(txt string) {
 	if !sz.IsNil() {
 		sz.SetSubScalar(2 * spc)
 	}
-	lb.Render.LayoutStdLR(&lb.Sty.Text, &lb.Sty.Font, &lb.Sty.UnContext, sz)
+	lb.LayoutText(htxt, sz)
 	lb.StyMu.RUnlock()
 	lb.UpdateEnd(updt)
 }
@@ -335,13 +356,37 @@ func (lb *Label) LayoutLabel() {
 	defer lb.StyMu.RUnlock()
 
 	lb.Sty.Font.BgColor.Color.SetToNil() // always use transparent bg for actual text
-	lb.Render.SetHTML(lb.Text, &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
 	spc := lb.BoxSpace()
 	sz := lb.LayState.SizePrefOrMax()
 	if !sz.IsNil() {
 		sz.SetSubScalar(2 * spc)
 	}
-	lb.Render.LayoutStdLR(&lb.Sty.Text, &lb.Sty.Font, &lb.Sty.UnContext, sz)
+	lb.ContentSizeWrap(&sz)
+	lb.LayoutText(lb.Text, sz)
+}
+
+// LayoutText sets the Render text from given text in the Format of the
+// label, and lays it out within given size -- style must be locked
+func (lb *Label) LayoutText(txt string, sz mat32.Vec2) {
+	if lb.Format == LabelMarkdown {
+		lb.Render.SetMarkdown(txt, &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
+		lb.Render.LayoutStd(&lb.Sty.Text, &lb.Sty.Font, &lb.Sty.UnContext, sz)
+		return
+	}
+	lb.Render.SetHTMLLayout(txt, &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg, sz)
+}
+
+// ContentSizeWrap sets the width at which the text is wrapped for
+// min-content and max-content width sizing (see gist.ContentSizes): 1 for
+// min-content, so it is the width of the longest word, and 0 for
+// max-content, so the text is not wrapped.
+func (lb *Label) ContentSizeWrap(sz *mat32.Vec2) {
+	switch lb.Sty.Layout.WidthSizing {
+	case gist.ContentSizeMin:
+		sz.X = 1
+	case gist.ContentSizeMax:
+		sz.X = 0
+	}
 }
 
 func (lb *Label) Style2D() {
@@ -370,9 +415,9 @@ func (lb *Label) Layout2D(parBBox image.Rectangle, iter int) bool {
 	}
 	lb.Layout2DChildren(iter) // todo: maybe shouldn't call this on known terminals?
 	sz := lb.Size2DSubSpace()
+	lb.ContentSizeWrap(&sz)
 	lb.Sty.Font.BgColor.Color.SetToNil() // always use transparent bg for actual text
-	lb.Render.SetHTML(lb.Text, &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
-	lb.Render.LayoutStdLR(&lb.Sty.Text, &lb.Sty.Font, &lb.Sty.UnContext, sz)
+	lb.LayoutText(lb.Text, sz)
 	if lb.Sty.Text.HasWordWrap() {
 		if lb.Render.Size.Y < (sz.Y - 1) { // allow for numerical issues
 			lb.LayState.SetFromStyle(&lb.Sty.Layout)
@@ -391,6 +436,18 @@ func (lb *Label) TextPos() mat32.Vec2 {
 	return pos
 }
 
+// FirstBaseline returns the offset of the baseline of the first line of
+// text from the top of the label -- see Baseliner
+func (lb *Label) FirstBaseline(height float32) float32 {
+	if len(lb.Render.Spans) == 0 {
+		return -1
+	}
+	lb.StyMu.RLock()
+	spc := lb.Sty.BoxSpace()
+	lb.StyMu.RUnlock()
+	return spc + lb.Render.Spans[0].RelPos.Y
+}
+
 func (lb *Label) RenderLabel() {
 	lb.GrabCurBgColor()
 	lb.SetStateStyle()
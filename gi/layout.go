@@ -7,8 +7,8 @@ package gi
 import (
 	"fmt"
 	"image"
-	"log"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -52,8 +52,9 @@ func (la *LayoutAllocs) Reset() {
 // LayoutState contains all the state needed to specify the layout of an item
 // within a Layout.  Is initialized with computed values of style prefs.
 type LayoutState struct {
-	Size  gist.SizePrefs `desc:"size constraints for this item -- set from layout style at start of layout process and then updated for Layout nodes to fit everything within it"`
-	Alloc LayoutAllocs   `desc:"allocated size and position -- set by parent Layout"`
+	Size   gist.SizePrefs       `desc:"size constraints for this item -- set from layout style at start of layout process and then updated for Layout nodes to fit everything within it"`
+	Alloc  LayoutAllocs         `desc:"allocated size and position -- set by parent Layout"`
+	Sizing [2]gist.ContentSizes `desc:"intrinsic sizing in each dimension, from the min-content and max-content width and height styles -- the size is fixed to the Need (min-content) or Pref (max-content) size of the content"`
 }
 
 // todo: not using yet:
@@ -70,6 +71,8 @@ func (ld *LayoutState) SetFromStyle(ls *gist.Layout) {
 	ld.Size.Need = ls.MinSizeDots()
 	ld.Size.Pref = ls.SizeDots()
 	ld.Size.Max = ls.MaxSizeDots()
+	ld.Sizing[mat32.X] = ls.WidthSizing
+	ld.Sizing[mat32.Y] = ls.HeightSizing
 
 	// this is an actual initial desired setting
 	ld.Alloc.Pos = ls.PosDots()
@@ -93,6 +96,22 @@ func (ld *LayoutState) UpdateSizes() {
 	ld.Size.Pref.SetMax(ld.Size.Need)   // pref cannot be < min
 	ld.Size.Need.SetMinPos(ld.Size.Max) // min cannot be > max
 	ld.Size.Pref.SetMinPos(ld.Size.Max) // pref cannot be > max
+	for d := mat32.X; d <= mat32.Y; d++ {
+		var csz float32
+		switch ld.Sizing[d] {
+		case gist.ContentSizeMin:
+			csz = ld.Size.Need.Dim(d)
+		case gist.ContentSizeMax:
+			csz = ld.Size.Pref.Dim(d)
+		default:
+			continue
+		}
+		if csz > 0 { // fixed at content size
+			ld.Size.Need.SetDim(d, csz)
+			ld.Size.Pref.SetDim(d, csz)
+			ld.Size.Max.SetDim(d, csz)
+		}
+	}
 }
 
 // GridData contains data for grid layout -- only one value needed for relevant dim
@@ -137,6 +156,10 @@ type Layout struct {
 	Spacing       units.Value         `xml:"spacing" desc:"extra space to add between elements in the layout"`
 	StackTop      int                 `desc:"for Stacked layout, index of node to use as the top of the stack -- only node at this index is rendered -- if not a valid index, nothing is rendered"`
 	StackTopOnly  bool                `desc:"for stacked layout, only layout the top widget -- this is appropriate for e.g., tab layout, which does a full redraw on stack changes, but not for e.g., check boxes which don't"`
+	RubberBandSel bool                `desc:"enables rubber-band selection: dragging on empty space in the layout selects the children that intersect the selection rectangle, setting their Selected flag and emitting WidgetSelected signals -- use SelectedChildren to get them"`
+	RubberBand    RubberBand          `copy:"-" json:"-" xml:"-" view:"-" desc:"state for rubber-band selection"`
+	CacheRender   bool                `xml:"cache-render" desc:"caches the rendering of the children, and reuses it until it is invalidated by a re-layout, re-styling or scrolling of this layout, or an update of anything within it -- for expensive but static content (e.g., large SVG diagrams), so it is not re-rendered during unrelated updates"`
+	RenderCache   RenderCache         `copy:"-" json:"-" xml:"-" view:"-" desc:"cached rendering of the children, if CacheRender is set"`
 	ChildSize     mat32.Vec2          `copy:"-" json:"-" xml:"-" desc:"total max size of children as laid out"`
 	ExtraSize     mat32.Vec2          `copy:"-" json:"-" xml:"-" desc:"extra size in each dim due to scrollbars we add"`
 	HasScroll     [2]bool             `copy:"-" json:"-" xml:"-" desc:"whether scrollbar is used for given dim"`
@@ -150,6 +173,9 @@ type Layout struct {
 	FocusNameLast ki.Ki               `copy:"-" json:"-" xml:"-" desc:"last element focused on -- used as a starting point if name is the same"`
 	ScrollsOff    bool                `copy:"-" json:"-" xml:"-" desc:"scrollbars have been manually turned off due to layout being invisible -- must be reactivated when re-visible"`
 	ScrollSig     ki.Signal           `copy:"-" json:"-" xml:"-" view:"-" desc:"signal for layout scrolling -- sends signal whenever layout is scrolled due to user input -- signal type is dimension (mat32.X or Y) and data is new position (not delta)"`
+	Stuck         []Node2D            `copy:"-" json:"-" xml:"-" view:"-" desc:"sticky children currently pinned at the top of the visible region, which are rendered last -- see SetSticky"`
+	Busy          *BusyState          `copy:"-" json:"-" xml:"-" view:"-" desc:"state of the busy mode, in which the content is dimmed and blocked, with a spinner over it -- nil if not busy -- see SetBusy -- use CurBusy to access under BusyMu"`
+	BusyMu        sync.RWMutex        `copy:"-" json:"-" xml:"-" view:"-" desc:"mutex protecting Busy, which can be set from another goroutine"`
 }
 
 var KiT_Layout = kit.Types.AddType(&Layout{}, LayoutProps)
@@ -168,7 +194,7 @@ func AddNewLayout(parent ki.Ki, name string, layout Layouts) *Layout {
 func (ly *Layout) CopyFieldsFrom(frm any) {
 	fr, ok := frm.(*Layout)
 	if !ok {
-		log.Printf("GoGi node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier Layout one\n", ki.Type(ly).Name())
+		Logf(LogWarn, "gi.Layout", "node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier Layout one", ki.Type(ly).Name())
 		ki.GenCopyFieldsFrom(ly.This(), frm)
 		return
 	}
@@ -176,6 +202,27 @@ func (ly *Layout) CopyFieldsFrom(frm any) {
 	ly.Lay = fr.Lay
 	ly.Spacing = fr.Spacing
 	ly.StackTop = fr.StackTop
+	ly.RubberBandSel = fr.RubberBandSel
+	ly.CacheRender = fr.CacheRender
+}
+
+// Baseliner is implemented by widgets that contain a line of text, to
+// report the offset of their first text baseline from the top of their box,
+// given the height of the box.  Children of a LayoutHoriz with
+// "vertical-align" set to gist.AlignBaseline are positioned so
+// that their baselines line up -- e.g., a Label next to a TextField and a
+// Button.  A negative value means there is no baseline, and the widget is
+// top-aligned.
+type Baseliner interface {
+	FirstBaseline(height float32) float32
+}
+
+// AspectRatioer is implemented by widgets whose content has an intrinsic
+// aspect ratio (width / height), e.g., the image of a Bitmap, which is kept
+// during layout if their "aspect-ratio" style is auto
+// (gist.AspectRatioAuto) -- see ChildAspectRatio.  0 means there is none.
+type AspectRatioer interface {
+	AspectRatio() float32
 }
 
 // Layouts are the different types of layouts
@@ -525,8 +572,21 @@ func (ly *Layout) Layout2DChildren(iter int) bool {
 	}
 }
 
-// render the children
+// render the children -- if CacheRender is set, or the layout is busy, a
+// valid cached rendering is used instead, and otherwise the rendering is
+// cached -- the busy overlay is rendered on top of them (see SetBusy)
 func (ly *Layout) Render2DChildren() {
+	if ly.IsBusy() {
+		defer ly.RenderBusy()
+	}
+	if ly.CacheRender || ly.IsBusy() {
+		if ly.RenderCache.Restore(ly.Viewport.Pixels, ly.VpBBox) {
+			return
+		}
+		defer func() {
+			ly.RenderCache.Save(ly.Viewport.Pixels, ly.VpBBox)
+		}()
+	}
 	if ly.Lay == LayoutStacked {
 		for i, kid := range ly.Kids {
 			if _, ni := KiToNode2D(kid); ni != nil {
@@ -540,7 +600,7 @@ func (ly *Layout) Render2DChildren() {
 		// note: all nodes need to render to disconnect b/c of invisible
 	}
 	for _, kid := range ly.Kids {
-		if kid == nil {
+		if kid == nil || ly.IsStuck(kid) {
 			continue
 		}
 		nii, _ := KiToNode2D(kid)
@@ -548,6 +608,7 @@ func (ly *Layout) Render2DChildren() {
 			nii.Render2D()
 		}
 	}
+	ly.RenderStuck() // on top
 }
 
 func (ly *Layout) Move2DChildren(delta image.Point) {
@@ -560,10 +621,12 @@ func (ly *Layout) Move2DChildren(delta image.Point) {
 		nii, _ := KiToNode2D(sn)
 		nii.Move2D(delta, cbb)
 	} else {
+		ly.Stuck = ly.Stuck[:0]
 		for _, kid := range ly.Kids {
 			nii, _ := KiToNode2D(kid)
 			if nii != nil {
 				nii.Move2D(delta, cbb)
+				ly.StickChild(nii, delta, cbb)
 			}
 		}
 	}
@@ -1087,7 +1150,7 @@ func (ly *Layout) ChildrenBBox2D() image.Rectangle {
 // StyleFromProps styles Layout-specific fields from ki.Prop properties
 // doesn't support inherit or default
 func (ly *Layout) StyleFromProps(props ki.Props, vp *Viewport2D) {
-	keys := []string{"lay", "spacing"}
+	keys := []string{"lay", "spacing", "cache-render"}
 	for _, key := range keys {
 		val, has := props[key]
 		if !has {
@@ -1109,6 +1172,12 @@ func (ly *Layout) StyleFromProps(props ki.Props, vp *Viewport2D) {
 			}
 		case "spacing":
 			ly.Spacing.SetIFace(val, key)
+		case "cache-render":
+			if bv, ok := kit.ToBool(val); ok {
+				ly.CacheRender = bv
+			} else {
+				gist.StyleSetError(key, val)
+			}
 		}
 	}
 }
@@ -1144,6 +1213,7 @@ func (ly *Layout) StyleLayout() {
 }
 
 func (ly *Layout) Style2D() {
+	ly.RenderCache.Invalidate()
 	ly.StyleLayout()
 	ly.StyMu.Lock()
 	ly.LayState.SetFromStyle(&ly.Sty.Layout) // also does reset
@@ -1168,14 +1238,19 @@ func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
 	//		fmt.Printf("Layout: %v Iteration: %v  NeedsRedo: %v\n", ly.Path(), iter, ly.NeedsRedo)
 	//	}
 	//}
+	ly.RenderCache.Invalidate()
+	ly.Stuck = ly.Stuck[:0]
 	LayAllocFromParent(ly)               // in case we didn't get anything
 	ly.Layout2DBase(parBBox, true, iter) // init style
+	LayoutPctSizes(ly)
 	redo := false
 	switch ly.Lay {
 	case LayoutHoriz:
+		LayoutAspectAlong(ly, mat32.X)
 		LayoutAlongDim(ly, mat32.X)
 		LayoutSharedDim(ly, mat32.Y)
 	case LayoutVert:
+		LayoutAspectAlong(ly, mat32.Y)
 		LayoutAlongDim(ly, mat32.Y)
 		LayoutSharedDim(ly, mat32.X)
 	case LayoutGrid:
@@ -1190,6 +1265,9 @@ func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
 	case LayoutNil:
 		// nothing
 	}
+	if ly.Lay != LayoutNil {
+		LayoutAspectFit(ly)
+	}
 	ly.FinalizeLayout()
 	if redo && iter == 0 {
 		ly.NeedsRedo = true
@@ -1222,6 +1300,7 @@ func (ly *Layout) Move2DDelta(delta image.Point) image.Point {
 }
 
 func (ly *Layout) Move2D(delta image.Point, parBBox image.Rectangle) {
+	ly.RenderCache.Invalidate()
 	ly.Move2DBase(delta, parBBox)
 	ly.Move2DScrolls(delta, parBBox) // move scrolls BEFORE adding our own!
 	delta = ly.Move2DDelta(delta)    // add our offset
@@ -1251,6 +1330,9 @@ func (ly *Layout) ConnectEvents2D() {
 	if ly.HasAnyScroll() {
 		ly.LayoutScrollEvents()
 	}
+	if ly.RubberBandSel {
+		ly.RubberBandEvents()
+	}
 	ly.KeyChordEvent()
 }
 
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
 	"github.com/goki/ki/ints"
 	"github.com/goki/ki/ki"
 	"github.com/goki/mat32"
@@ -74,6 +75,7 @@ func GatherSizesSumMax(ly *Layout) (sumPref, sumNeed, maxPref, maxNeed mat32.Vec
 		if ni == nil {
 			continue
 		}
+		AspectSizes(c)
 		ni.LayState.UpdateSizes()
 		sumNeed = sumNeed.Add(ni.LayState.Size.Need)
 		sumPref = sumPref.Add(ni.LayState.Size.Pref)
@@ -95,6 +97,10 @@ func GatherSizes(ly *Layout) {
 	}
 
 	sumPref, sumNeed, maxPref, maxNeed := GatherSizesSumMax(ly)
+	if ly.Lay == LayoutHoriz {
+		maxNeed.Y = mat32.Max(maxNeed.Y, BaselinesHeight(ly, false))
+		maxPref.Y = mat32.Max(maxPref.Y, BaselinesHeight(ly, true))
+	}
 
 	prefSizing := false
 	mvp := ly.ViewportSafe()
@@ -153,6 +159,7 @@ func (ly *Layout) ChildrenUpdateSizes() {
 		if ni == nil {
 			continue
 		}
+		AspectSizes(c)
 		ni.LayState.UpdateSizes()
 	}
 }
@@ -324,6 +331,7 @@ func GatherSizesGrid(ly *Layout) {
 		if ni == nil {
 			continue
 		}
+		AspectSizes(c)
 		ni.LayState.UpdateSizes()
 		ni.StyMu.RLock()
 		lst := ni.Sty.Layout
@@ -483,6 +491,183 @@ func LayAllocFromParent(ly *Layout) {
 ////////////////////////////////////////////////////////////////////////////////////////
 //     Layout children
 
+// LayoutPctSizes resolves the size preferences of children whose width or
+// height (including min and max) are specified in percent (Pct) units,
+// against our allocated size -- these are initially resolved from the
+// style against the last known parent size or the viewport size, as our
+// size is not known until now.  Our summed size preferences along the
+// layout dimension are updated accordingly.
+func LayoutPctSizes(ly *Layout) {
+	spc := ly.BoxSpace()
+	for d := mat32.X; d <= mat32.Y; d++ {
+		avail := ly.LayState.Alloc.Size.Dim(d) - 2.0*spc
+		if avail <= 0 {
+			continue
+		}
+		sum := LaySumDim(ly.Lay, d)
+		for _, c := range ly.Kids {
+			if c == nil {
+				continue
+			}
+			ni := c.(Node2D).AsWidget()
+			if ni == nil {
+				continue
+			}
+			ni.StyMu.RLock()
+			ls := &ni.Sty.Layout
+			sz, mn, mx := ls.Width, ls.MinWidth, ls.MaxWidth
+			if d == mat32.Y {
+				sz, mn, mx = ls.Height, ls.MinHeight, ls.MaxHeight
+			}
+			ni.StyMu.RUnlock()
+			lsz := &ni.LayState.Size
+			if mx.Un == units.Pct && mx.Val > 0 {
+				lsz.Max.SetDim(d, 0.01*mx.Val*avail)
+			}
+			if mn.Un == units.Pct {
+				nd := 0.01 * mn.Val * avail
+				if sum {
+					ly.LayState.Size.Need.SetDim(d, ly.LayState.Size.Need.Dim(d)+nd-lsz.Need.Dim(d))
+				}
+				lsz.Need.SetDim(d, nd)
+			}
+			if sz.Un == units.Pct {
+				pd := mat32.Max(0.01*sz.Val*avail, lsz.Need.Dim(d))
+				if sum {
+					ly.LayState.Size.Pref.SetDim(d, ly.LayState.Size.Pref.Dim(d)+pd-lsz.Pref.Dim(d))
+				}
+				lsz.Pref.SetDim(d, pd)
+			}
+		}
+	}
+}
+
+// ChildAspectRatio returns the aspect ratio (width / height) that is kept
+// for given child during layout: its "aspect-ratio" style, or the
+// AspectRatio of its content if that is auto and it implements
+// AspectRatioer -- 0 if there is none.
+func ChildAspectRatio(c ki.Ki) float32 {
+	ni := c.(Node2D).AsWidget()
+	if ni == nil {
+		return 0
+	}
+	ni.StyMu.RLock()
+	ar := ni.Sty.Layout.AspectRatio
+	ni.StyMu.RUnlock()
+	if ar == gist.AspectRatioAuto {
+		if arr, ok := c.(AspectRatioer); ok {
+			return arr.AspectRatio()
+		}
+		return 0
+	}
+	return mat32.Max(ar, 0)
+}
+
+// aspectSize returns the size along given dim for given size along the
+// other dim, with given aspect ratio (width / height)
+func aspectSize(ar, osz float32, dim mat32.Dims) float32 {
+	if dim == mat32.X {
+		return osz * ar
+	}
+	return osz / ar
+}
+
+// AspectSizes sets the preferred size of given child, if it has an aspect
+// ratio (see ChildAspectRatio), in a dimension whose size is not specified
+// in its style, from the size specified in the other dimension.
+func AspectSizes(c ki.Ki) {
+	ar := ChildAspectRatio(c)
+	if ar <= 0 {
+		return
+	}
+	ni := c.(Node2D).AsWidget()
+	ni.StyMu.RLock()
+	ssz := ni.Sty.Layout.SizeDots()
+	ni.StyMu.RUnlock()
+	for d := mat32.X; d <= mat32.Y; d++ {
+		od := mat32.OtherDim(d)
+		if ssz.Dim(d) == 0 && ssz.Dim(od) > 0 {
+			ni.LayState.Size.Pref.SetDim(d, aspectSize(ar, ni.LayState.Size.Pref.Dim(od), d))
+		}
+	}
+}
+
+// LayoutAspectAlong sets the preferred size along the layout dimension of
+// children that have an aspect ratio, from the size they will be allocated
+// in the shared dimension, so that they keep their proportions as the
+// layout is resized -- called before LayoutAlongDim.  Our summed size
+// preferences along the layout dimension are updated accordingly.
+func LayoutAspectAlong(ly *Layout, dim mat32.Dims) {
+	od := mat32.OtherDim(dim)
+	spc := ly.BoxSpace()
+	avail := ly.LayState.Alloc.Size.Dim(od) - 2.0*spc
+	if avail <= 0 {
+		return
+	}
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ar := ChildAspectRatio(c)
+		if ar <= 0 {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		ni.StyMu.RLock()
+		al := ni.Sty.Layout.AlignDim(od)
+		ni.StyMu.RUnlock()
+		lsz := &ni.LayState.Size
+		_, osz := LayoutSharedDimImpl(ly, avail, lsz.Need.Dim(od), lsz.Pref.Dim(od), lsz.Max.Dim(od), spc, al)
+		pd := mat32.Max(aspectSize(ar, osz, dim), lsz.Need.Dim(dim))
+		if mx := lsz.Max.Dim(dim); mx > 0 {
+			pd = mat32.Min(pd, mx)
+		}
+		ly.LayState.Size.Pref.SetDim(dim, ly.LayState.Size.Pref.Dim(dim)+pd-lsz.Pref.Dim(dim))
+		lsz.Pref.SetDim(dim, pd)
+	}
+}
+
+// LayoutAspectFit fits children that have an aspect ratio within the size
+// they are allocated, keeping their proportions: their size is reduced in
+// the dimension in which it is too large, and they are positioned within
+// the allocated space in that dimension according to their alignment.
+func LayoutAspectFit(ly *Layout) {
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ar := ChildAspectRatio(c)
+		if ar <= 0 {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		asz := ni.LayState.Alloc.Size
+		if asz.X <= 0 || asz.Y <= 0 {
+			continue
+		}
+		d := mat32.Y // too tall
+		if asz.X > asz.Y*ar {
+			d = mat32.X // too wide
+		}
+		sz := aspectSize(ar, asz.Dim(mat32.OtherDim(d)), d)
+		extra := asz.Dim(d) - sz
+		if extra < 0.5 {
+			continue
+		}
+		ni.StyMu.RLock()
+		al := ni.Sty.Layout.AlignDim(d)
+		ni.StyMu.RUnlock()
+		pos := ni.LayState.Alloc.PosRel.Dim(d)
+		if gist.IsAlignMiddle(al) {
+			pos += 0.5 * extra
+		} else if gist.IsAlignEnd(al) {
+			pos += extra
+		}
+		ni.LayState.Alloc.Size.SetDim(d, sz)
+		ni.LayState.Alloc.PosRel.SetDim(d, pos)
+	}
+}
+
 // LayoutSharedDim implements calculations to layout for the shared dimension
 // (i.e., Vertical for Horizontal layout). Returns pos and size.
 func LayoutSharedDimImpl(ly *Layout, avail, need, pref, max, spc float32, al gist.Align) (pos, size float32) {
@@ -557,6 +742,98 @@ func LayoutSharedDim(ly *Layout, dim mat32.Dims) {
 		ni.LayState.Alloc.Size.SetDim(dim, size)
 		ni.LayState.Alloc.PosRel.SetDim(dim, pos)
 	}
+	if dim == mat32.Y && ly.Lay == LayoutHoriz {
+		LayoutBaselines(ly)
+	}
+}
+
+// ChildBaseline returns the first baseline of given child for given height
+// if it is baseline-aligned ("vertical-align": gist.AlignBaseline) and implements
+// Baseliner, and -1 otherwise.
+func ChildBaseline(c ki.Ki, height float32) float32 {
+	bl, ok := c.(Baseliner)
+	if !ok {
+		return -1
+	}
+	ni := c.(Node2D).AsWidget()
+	if ni == nil {
+		return -1
+	}
+	ni.StyMu.RLock()
+	al := ni.Sty.Layout.AlignV
+	ni.StyMu.RUnlock()
+	if al != gist.AlignBaseline {
+		return -1
+	}
+	return bl.FirstBaseline(height)
+}
+
+// BaselinesHeight returns the height needed to fit the baseline-aligned
+// children of a horizontal layout, with their baselines lined up: the max
+// ascent (above the baseline) plus the max descent (below it) -- uses the
+// Pref sizes if pref is true, else the Need sizes.  Returns 0 if there are
+// no such children.
+func BaselinesHeight(ly *Layout, pref bool) float32 {
+	maxAsc := float32(-1)
+	maxDesc := float32(0)
+	for _, c := range ly.Kids {
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		ht := ni.LayState.Size.Need.Y
+		if pref {
+			ht = ni.LayState.Size.Pref.Y
+		}
+		bl := ChildBaseline(c, ht)
+		if bl < 0 {
+			continue
+		}
+		maxAsc = mat32.Max(maxAsc, bl)
+		maxDesc = mat32.Max(maxDesc, ht-bl)
+	}
+	if maxAsc < 0 {
+		return 0
+	}
+	return maxAsc + maxDesc
+}
+
+// LayoutBaselines positions the baseline-aligned children of a horizontal
+// layout in the vertical dimension, after LayoutSharedDim has allocated
+// their sizes, so that their first baselines line up with the lowest one.
+func LayoutBaselines(ly *Layout) {
+	sz := len(ly.Kids)
+	if sz == 0 {
+		return
+	}
+	bls := make([]float32, sz)
+	maxAsc := float32(-1)
+	for i, c := range ly.Kids {
+		bls[i] = -1
+		if c == nil {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		bls[i] = ChildBaseline(c, ni.LayState.Alloc.Size.Y)
+		maxAsc = mat32.Max(maxAsc, bls[i])
+	}
+	if maxAsc < 0 {
+		return
+	}
+	spc := ly.BoxSpace()
+	for i, c := range ly.Kids {
+		if bls[i] < 0 {
+			continue
+		}
+		ni := c.(Node2D).AsWidget()
+		ni.LayState.Alloc.PosRel.Y = spc + maxAsc - bls[i]
+	}
 }
 
 // LayoutAlongDim lays out all children along given dim -- only affects that dim --
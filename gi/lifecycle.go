@@ -0,0 +1,187 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"sync"
+
+	"github.com/goki/ki/ki"
+)
+
+// WidgetLifecycle holds the functions that are called at the stages in the
+// lifecycle of a widget -- see OnFirstRender, OnShow, OnHide, OnDestroy.
+type WidgetLifecycle struct {
+	FirstRender []func()   `desc:"functions called after the widget is rendered for the first time"`
+	Show        []func()   `desc:"functions called after the widget becomes visible, including the first time"`
+	Hide        []func()   `desc:"functions called after the widget is no longer visible, e.g., because a different tab is selected, a parent is hidden, or the window is minimized"`
+	Destroy     []func()   `desc:"functions called when the widget is destroyed, before its children are destroyed"`
+	Rendered    bool       `desc:"whether the widget has been rendered"`
+	Shown       bool       `desc:"whether the widget is currently shown"`
+	Mu          sync.Mutex `desc:"mutex protecting the lifecycle state"`
+}
+
+// OnFirstRender adds a function that is called after this widget is rendered
+// for the first time, e.g., to load data that is only needed once the
+// widget is actually seen.  It is called on the window event loop,
+// after the render is done.
+func (wb *WidgetBase) OnFirstRender(fun func()) {
+	wb.Lifecycle.Mu.Lock()
+	wb.Lifecycle.FirstRender = append(wb.Lifecycle.FirstRender, fun)
+	wb.Lifecycle.Mu.Unlock()
+}
+
+// OnShow adds a function that is called after this widget becomes visible,
+// including the first time it is rendered, and when it is shown again after
+// being hidden (see OnHide).  It is called on the window event loop, after
+// the render is done.
+func (wb *WidgetBase) OnShow(fun func()) {
+	wb.Lifecycle.Mu.Lock()
+	wb.Lifecycle.Show = append(wb.Lifecycle.Show, fun)
+	wb.Lifecycle.Mu.Unlock()
+}
+
+// OnHide adds a function that is called after this widget is no longer
+// visible, because it or a parent is hidden (e.g., another tab selected),
+// scrolled out of view, or the window is minimized.
+// It is called on the window event loop.
+func (wb *WidgetBase) OnHide(fun func()) {
+	wb.Lifecycle.Mu.Lock()
+	wb.Lifecycle.Hide = append(wb.Lifecycle.Hide, fun)
+	wb.Lifecycle.Mu.Unlock()
+}
+
+// OnDestroy adds a function that is called when this widget is destroyed,
+// e.g., to stop timers and goroutines that it uses.  It is called directly
+// within Destroy, before the children are destroyed.
+func (wb *WidgetBase) OnDestroy(fun func()) {
+	wb.Lifecycle.Mu.Lock()
+	wb.Lifecycle.Destroy = append(wb.Lifecycle.Destroy, fun)
+	wb.Lifecycle.Mu.Unlock()
+}
+
+// IsShown returns true if the widget is currently shown, i.e., it has been
+// rendered and not hidden since then (see OnShow, OnHide)
+func (wb *WidgetBase) IsShown() bool {
+	wb.Lifecycle.Mu.Lock()
+	defer wb.Lifecycle.Mu.Unlock()
+	return wb.Lifecycle.Shown
+}
+
+// Destroy calls the OnDestroy functions and then destroys the widget
+func (wb *WidgetBase) Destroy() {
+	wb.Lifecycle.Mu.Lock()
+	funs := wb.Lifecycle.Destroy
+	wb.Lifecycle.Destroy = nil
+	wb.Lifecycle.Mu.Unlock()
+	for _, fun := range funs {
+		fun()
+	}
+	wb.Node2DBase.Destroy()
+}
+
+// LifecycleShown is called when the widget is rendered, and calls the
+// OnFirstRender and OnShow functions if it was not already shown
+func (wb *WidgetBase) LifecycleShown() {
+	lc := &wb.Lifecycle
+	lc.Mu.Lock()
+	if lc.Shown {
+		lc.Mu.Unlock()
+		return
+	}
+	lc.Shown = true
+	var funs []func()
+	if !lc.Rendered {
+		lc.Rendered = true
+		funs = append(funs, lc.FirstRender...)
+	}
+	funs = append(funs, lc.Show...)
+	lc.Mu.Unlock()
+	wb.runLifecycle(funs)
+}
+
+// LifecycleHidden is called when the widget is not rendered because it is
+// not visible, and calls the OnHide functions of it and all of its children
+// that were shown
+func (wb *WidgetBase) LifecycleHidden() {
+	wb.Lifecycle.Mu.Lock()
+	shown := wb.Lifecycle.Shown
+	wb.Lifecycle.Mu.Unlock()
+	if shown {
+		LifecycleHideTree(wb.This())
+	}
+}
+
+// LifecycleHideTree marks all the widgets in the tree under given node
+// as hidden, calling the OnHide functions for those that were shown
+func LifecycleHideTree(k ki.Ki) {
+	k.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		nii, _ := KiToNode2D(k)
+		if nii == nil {
+			return ki.Continue
+		}
+		wb := nii.AsWidget()
+		if wb == nil {
+			return ki.Continue
+		}
+		lc := &wb.Lifecycle
+		lc.Mu.Lock()
+		if !lc.Shown {
+			lc.Mu.Unlock()
+			return ki.Continue
+		}
+		lc.Shown = false
+		funs := lc.Hide
+		lc.Mu.Unlock()
+		wb.runLifecycle(funs)
+		return ki.Continue
+	})
+}
+
+// LifecycleShowTree marks all the widgets in the tree under given node that
+// were rendered and are visible as shown again, calling their OnShow
+// functions -- e.g., when the window is restored after being minimized
+func LifecycleShowTree(k ki.Ki) {
+	k.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		nii, _ := KiToNode2D(k)
+		if nii == nil {
+			return ki.Continue
+		}
+		if !nii.IsVisible() {
+			return ki.Break
+		}
+		wb := nii.AsWidget()
+		if wb == nil {
+			return ki.Continue
+		}
+		wb.BBoxMu.RLock()
+		empty := wb.VpBBox.Empty()
+		wb.BBoxMu.RUnlock()
+		wb.Lifecycle.Mu.Lock()
+		rendered := wb.Lifecycle.Rendered
+		wb.Lifecycle.Mu.Unlock()
+		if rendered && !empty {
+			wb.LifecycleShown()
+		}
+		return ki.Continue
+	})
+}
+
+// runLifecycle runs given lifecycle functions on the window event loop, after
+// the current render is done, or directly if not in a window
+func (wb *WidgetBase) runLifecycle(funs []func()) {
+	if len(funs) == 0 {
+		return
+	}
+	run := func() {
+		for _, fun := range funs {
+			fun()
+		}
+	}
+	if win := wb.ParentWindow(); win != nil && !win.IsClosed() {
+		win.RunOnNextFrame(run)
+	} else {
+		run()
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/ki/kit"
+)
+
+// LogLevels are the severity levels of log records
+type LogLevels int32
+
+const (
+	// LogDebug is for detailed information for debugging
+	LogDebug LogLevels = iota
+
+	// LogInfo is for general information
+	LogInfo
+
+	// LogWarn is for warnings about unexpected but recoverable situations
+	LogWarn
+
+	// LogError is for errors
+	LogError
+
+	LogLevelsN
+)
+
+//go:generate stringer -type=LogLevels
+
+var KiT_LogLevels = kit.Enums.AddEnumAltLower(LogLevelsN, kit.NotBitFlag, nil, "Log")
+
+func (ev LogLevels) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *LogLevels) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// LogRecord is one record of log output
+type LogRecord struct {
+	Time   time.Time `desc:"when the record was logged"`
+	Level  LogLevels `desc:"severity level"`
+	Source string    `desc:"source of the record, e.g., the package and type, as in gi.Window"`
+	Msg    string    `desc:"the message"`
+}
+
+// String returns the record formatted as a line of log output
+func (rec *LogRecord) String() string {
+	src := ""
+	if rec.Source != "" {
+		src = rec.Source + ": "
+	}
+	return fmt.Sprintf("%v %-5v %v%v", rec.Time.Format("2006/01/02 15:04:05"), strings.ToUpper(rec.Level.String()[3:]), src, rec.Msg)
+}
+
+// LogLevel is the minimum level of records that are handled by Logf
+var LogLevel = LogInfo
+
+// LogOutput is where DefaultLogHandler writes the records -- os.Stderr by
+// default, and set to the prior output of the standard logger by
+// CaptureStdLog
+var LogOutput io.Writer = os.Stderr
+
+// LogHandler handles each log record from Logf and the standard logger
+// (once CaptureStdLog has been called) -- replace it to route the records
+// elsewhere, e.g., a structured logging system.  The default is
+// DefaultLogHandler.
+var LogHandler = DefaultLogHandler
+
+// LogRecords has the recent log records added by DefaultLogHandler, as
+// shown in the LogConsole
+var LogRecords = LogStore{Max: 2000}
+
+// DefaultLogHandler writes given record to LogOutput and adds it to
+// LogRecords
+func DefaultLogHandler(rec *LogRecord) {
+	if LogOutput != nil {
+		fmt.Fprintln(LogOutput, rec.String())
+	}
+	LogRecords.Add(rec)
+}
+
+// Logf logs a record of given level from given source, with message
+// formatted as in fmt.Sprintf, if the level is at least LogLevel
+func Logf(level LogLevels, source, format string, args ...any) {
+	if level < LogLevel {
+		return
+	}
+	LogHandler(&LogRecord{Time: time.Now(), Level: level, Source: source, Msg: fmt.Sprintf(format, args...)})
+}
+
+// stdLogCaptured is set once CaptureStdLog has been called
+var stdLogCaptured = false
+
+// CaptureStdLog routes the output of the standard logger, which is used by
+// other packages (and typically the app), through LogHandler, so it shows up
+// in the LogConsole -- gimain.Main calls it at startup, so that the output
+// from the start is there.  Each line is a record of level StdLogLevel, and
+// a leading "pkg.Name:" is its source (see StdLogRecord) -- use Logf for
+// other levels.  The previous output of the standard logger becomes the
+// LogOutput.  It is safe to call multiple times.
+func CaptureStdLog() {
+	if stdLogCaptured {
+		return
+	}
+	stdLogCaptured = true
+	LogOutput = log.Writer()
+	log.SetFlags(0) // records have their own time
+	log.SetOutput(&stdLogWriter{})
+}
+
+// stdLogWriter is the output of the standard logger after CaptureStdLog
+type stdLogWriter struct {
+	mu   sync.Mutex
+	part string
+}
+
+func (lw *stdLogWriter) Write(b []byte) (int, error) {
+	lw.mu.Lock()
+	lns := strings.Split(lw.part+string(b), "\n")
+	lw.part = lns[len(lns)-1]
+	lw.mu.Unlock()
+	for _, ln := range lns[:len(lns)-1] {
+		if strings.TrimSpace(ln) == "" {
+			continue
+		}
+		LogHandler(StdLogRecord(ln))
+	}
+	return len(b), nil
+}
+
+// StdLogLevel is the level of the records of the lines of standard log
+// output, once CaptureStdLog has been called -- they are not filtered by
+// LogLevel
+var StdLogLevel = LogWarn
+
+// StdLogRecord returns a record for given line of standard log output, of
+// level StdLogLevel, with the source from a leading "pkg.Name:", if any --
+// see CaptureStdLog
+func StdLogRecord(ln string) *LogRecord {
+	rec := &LogRecord{Time: time.Now(), Level: StdLogLevel, Msg: ln}
+	if ci := strings.Index(ln, ": "); ci > 0 {
+		src := ln[:ci]
+		if !strings.ContainsAny(src, " \t/") && strings.Contains(src, ".") {
+			rec.Source = src
+			rec.Msg = ln[ci+2:]
+		}
+	}
+	return rec
+}
+
+// LogStore keeps the most recent log records, and calls listener functions
+// when records are added -- these are called from the goroutine doing the
+// logging, so updating of a view must be scheduled on its window, e.g.,
+// with Window.RunOnNextFrameOnce
+type LogStore struct {
+	Max       int            `desc:"maximum number of records to keep"`
+	Mu        sync.Mutex     `desc:"mutex protecting the records"`
+	Recs      []*LogRecord   `desc:"the records, oldest first"`
+	Listeners map[any]func() `desc:"functions called when records are added or cleared, by key"`
+}
+
+// Add adds given record, removing the oldest beyond Max
+func (ls *LogStore) Add(rec *LogRecord) {
+	ls.Mu.Lock()
+	ls.Recs = append(ls.Recs, rec)
+	if over := len(ls.Recs) - ls.Max; ls.Max > 0 && over > 0 {
+		ls.Recs = append([]*LogRecord(nil), ls.Recs[over:]...)
+	}
+	ls.Mu.Unlock()
+	ls.notify()
+}
+
+// Clear removes all the records
+func (ls *LogStore) Clear() {
+	ls.Mu.Lock()
+	ls.Recs = nil
+	ls.Mu.Unlock()
+	ls.notify()
+}
+
+// Filter returns the records with at least given level, whose source
+// contains given source string, and whose message contains given text
+// (case insensitive) -- empty strings match all
+func (ls *LogStore) Filter(level LogLevels, source, text string) []*LogRecord {
+	source = strings.ToLower(source)
+	text = strings.ToLower(text)
+	ls.Mu.Lock()
+	defer ls.Mu.Unlock()
+	var recs []*LogRecord
+	for _, rec := range ls.Recs {
+		if rec.Level < level {
+			continue
+		}
+		if source != "" && !strings.Contains(strings.ToLower(rec.Source), source) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(rec.Msg), text) {
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+// AddListener adds a function to call when records are added or cleared,
+// under given key (e.g., the view)
+func (ls *LogStore) AddListener(key any, fun func()) {
+	ls.Mu.Lock()
+	if ls.Listeners == nil {
+		ls.Listeners = make(map[any]func())
+	}
+	ls.Listeners[key] = fun
+	ls.Mu.Unlock()
+}
+
+// RemoveListener removes the listener function under given key
+func (ls *LogStore) RemoveListener(key any) {
+	ls.Mu.Lock()
+	delete(ls.Listeners, key)
+	ls.Mu.Unlock()
+}
+
+func (ls *LogStore) notify() {
+	ls.Mu.Lock()
+	funs := make([]func(), 0, len(ls.Listeners))
+	for _, fun := range ls.Listeners {
+		funs = append(funs, fun)
+	}
+	ls.Mu.Unlock()
+	for _, fun := range funs {
+		fun()
+	}
+}
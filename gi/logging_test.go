@@ -0,0 +1,35 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import "testing"
+
+func TestLogf(t *testing.T) {
+	defer func(lh func(rec *LogRecord), lv LogLevels) { LogHandler, LogLevel = lh, lv }(LogHandler, LogLevel)
+	var recs []*LogRecord
+	LogHandler = func(rec *LogRecord) { recs = append(recs, rec) }
+	LogLevel = LogWarn
+	Logf(LogInfo, "gi.Test", "not logged: %v", 1)
+	Logf(LogError, "gi.Test", "failed: %v", "bad")
+	if len(recs) != 1 || recs[0].Level != LogError || recs[0].Source != "gi.Test" || recs[0].Msg != "failed: bad" {
+		t.Errorf("got %v records: %+v", len(recs), recs)
+	}
+}
+
+func TestStdLogRecord(t *testing.T) {
+	tests := []struct {
+		ln, src, msg string
+	}{
+		{"giv.FileTree: error not found", "giv.FileTree", "error not found"},
+		{"plain message: with colon", "", "plain message: with colon"},
+		{"/tmp/x.go: no source", "", "/tmp/x.go: no source"},
+	}
+	for _, tt := range tests {
+		rec := StdLogRecord(tt.ln)
+		if rec.Level != StdLogLevel || rec.Source != tt.src || rec.Msg != tt.msg {
+			t.Errorf("%q: got %v %q %q, want %v %q %q", tt.ln, rec.Level, rec.Source, rec.Msg, StdLogLevel, tt.src, tt.msg)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/goki/gi/gist"
+)
+
+// MaterialTheme is the format of the JSON file exported by the Material
+// Theme Builder (https://m3.material.io/theme-builder), which has color
+// schemes named "light" and "dark" (among others), each mapping color roles
+// (e.g., "primary", "onBackground") to hex color strings.  Only the Schemes
+// are used here.
+type MaterialTheme struct {
+	Description string                       `json:"description,omitempty"`
+	Seed        string                       `json:"seed,omitempty"`
+	CoreColors  map[string]string            `json:"coreColors,omitempty"`
+	Schemes     map[string]map[string]string `json:"schemes"`
+}
+
+// MaterialThemeRoles maps the ColorPrefs color names to the Material
+// Theme Builder color roles used for importing and exporting
+var MaterialThemeRoles = map[string]string{
+	"Font":       "onBackground",
+	"Background": "background",
+	"Shadow":     "surfaceVariant",
+	"Border":     "outline",
+	"Control":    "secondaryContainer",
+	"Icon":       "primary",
+	"Select":     "primaryContainer",
+	"Highlight":  "tertiaryContainer",
+	"Link":       "tertiary",
+}
+
+// SetMaterialScheme sets the colors from given Material Theme Builder
+// scheme, mapping roles using MaterialThemeRoles -- colors for roles not
+// in the scheme are left unchanged, and EnsureContrast is called after.
+// Returns an error listing any roles that were missing or invalid.
+func (pf *ColorPrefs) SetMaterialScheme(sch map[string]string) error {
+	var errs []string
+	for _, nm := range ColorPrefsNames {
+		role := MaterialThemeRoles[nm]
+		hex, has := sch[role]
+		if !has {
+			errs = append(errs, fmt.Sprintf("role %q not found", role))
+			continue
+		}
+		if err := pf.PrefColor(nm).SetString(hex, nil); err != nil {
+			errs = append(errs, fmt.Sprintf("role %q: %v", role, err))
+		}
+	}
+	pf.EnsureContrast()
+	if pf.Background.IsDark() {
+		pf.HiStyle = "monokai"
+	} else {
+		pf.HiStyle = "emacs"
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("gi.ColorPrefs SetMaterialScheme: %v", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// MaterialScheme returns the colors as a Material Theme Builder scheme,
+// mapping roles using MaterialThemeRoles -- the corresponding "on" roles
+// for text and icons on each color (e.g., "onPrimary") are derived using
+// gist.OnColor, except where the role is itself one of the colors.
+func (pf *ColorPrefs) MaterialScheme() map[string]string {
+	sch := make(map[string]string, 2*len(ColorPrefsNames))
+	for _, nm := range ColorPrefsNames {
+		clr := pf.PrefColor(nm)
+		role := MaterialThemeRoles[nm]
+		sch[role] = materialHex(*clr)
+		if strings.HasPrefix(role, "on") {
+			continue
+		}
+		onrole := "on" + strings.ToUpper(role[:1]) + role[1:]
+		if _, has := sch[onrole]; !has {
+			sch[onrole] = materialHex(gist.OnColor(*clr))
+		}
+	}
+	return sch
+}
+
+// materialHex returns the #RRGGBB hex string used in Material themes
+func materialHex(clr gist.Color) string {
+	return fmt.Sprintf("#%02X%02X%02X", clr.R, clr.G, clr.B)
+}
+
+// OpenMaterialTheme opens colors from the given scheme (e.g., "light" or
+// "dark") in a Material Theme Builder JSON export file.
+func (pf *ColorPrefs) OpenMaterialTheme(filename FileName, scheme string) error {
+	mt, err := OpenMaterialThemeFile(filename)
+	if err != nil {
+		return err
+	}
+	sch, has := mt.Schemes[scheme]
+	if !has {
+		err = fmt.Errorf("gi.ColorPrefs OpenMaterialTheme: scheme %q not found in file: %v", scheme, filename)
+		Logf(LogError, "gi.ColorPrefs", "OpenMaterialTheme: %v", err)
+		return err
+	}
+	err = pf.SetMaterialScheme(sch)
+	if err != nil {
+		Logf(LogError, "gi.ColorPrefs", "OpenMaterialTheme: %v", err)
+	}
+	return err
+}
+
+// OpenMaterialThemeFile opens a Material Theme Builder JSON export file
+func OpenMaterialThemeFile(filename FileName) (*MaterialTheme, error) {
+	b, err := ioutil.ReadFile(string(filename))
+	if err != nil {
+		PromptDialog(nil, DlgOpts{Title: "File Not Found", Prompt: err.Error()}, AddOk, NoCancel, nil, nil)
+		Logf(LogError, "gi.OpenMaterialThemeFile", "%v", err)
+		return nil, err
+	}
+	mt := &MaterialTheme{}
+	err = json.Unmarshal(b, mt)
+	if err != nil {
+		Logf(LogError, "gi.OpenMaterialThemeFile", "%v", err)
+		return nil, err
+	}
+	return mt, nil
+}
+
+// OpenMaterialTheme opens the Light and Dark ColorSchemes from the "light"
+// and "dark" schemes in a Material Theme Builder JSON export file.
+func (pf *Preferences) OpenMaterialTheme(filename FileName) error {
+	mt, err := OpenMaterialThemeFile(filename)
+	if err != nil {
+		return err
+	}
+	if pf.ColorSchemes == nil {
+		pf.ColorSchemes = DefaultColorSchemes()
+	}
+	var rerr error
+	for _, nm := range []string{"Light", "Dark"} {
+		sch, has := mt.Schemes[strings.ToLower(nm)]
+		if !has {
+			continue
+		}
+		cp, ok := pf.ColorSchemes[nm]
+		if !ok {
+			cp = &ColorPrefs{}
+			pf.ColorSchemes[nm] = cp
+		}
+		if err := cp.SetMaterialScheme(sch); err != nil {
+			Logf(LogError, "gi.Preferences", "OpenMaterialTheme: %v", err)
+			rerr = err
+		}
+	}
+	pf.Changed = true
+	return rerr
+}
+
+// SaveMaterialTheme saves the Light and Dark ColorSchemes as the "light"
+// and "dark" schemes in a Material Theme Builder format JSON file.
+func (pf *Preferences) SaveMaterialTheme(filename FileName) error {
+	mt := &MaterialTheme{Description: "GoGi color schemes", Schemes: map[string]map[string]string{}}
+	for nm, cp := range pf.ColorSchemes {
+		mt.Schemes[strings.ToLower(nm)] = cp.MaterialScheme()
+	}
+	b, err := json.MarshalIndent(mt, "", "  ")
+	if err != nil {
+		Logf(LogError, "gi.Preferences", "SaveMaterialTheme: %v", err) // unlikely
+		return err
+	}
+	err = ioutil.WriteFile(string(filename), b, 0644)
+	if err != nil {
+		PromptDialog(nil, DlgOpts{Title: "Could not Save to File", Prompt: err.Error()}, AddOk, NoCancel, nil, nil)
+		Logf(LogError, "gi.Preferences", "SaveMaterialTheme: %v", err)
+	}
+	return err
+}
+
+// ColorSchemeNames returns the names of the ColorSchemes, with Light and
+// Dark first, and any others after in sorted order
+func (pf *Preferences) ColorSchemeNames() []string {
+	nms := []string{}
+	for _, nm := range []string{"Light", "Dark"} {
+		if _, has := pf.ColorSchemes[nm]; has {
+			nms = append(nms, nm)
+		}
+	}
+	var oth []string
+	for nm := range pf.ColorSchemes {
+		if nm != "Light" && nm != "Dark" {
+			oth = append(oth, nm)
+		}
+	}
+	sort.Strings(oth)
+	return append(nms, oth...)
+}
@@ -6,7 +6,6 @@ package gi
 
 import (
 	"image"
-	"log"
 
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/oswin"
@@ -58,7 +57,7 @@ type ActOpts struct {
 func (m *Menu) SetAction(ac *Action, opts ActOpts, sigTo ki.Ki, fun ki.RecvFunc) {
 	nm := opts.Name
 	if nm == "" {
-		nm = opts.Label
+		nm = StripMnemonic(opts.Label)
 	}
 	if nm == "" {
 		nm = opts.Icon
@@ -352,7 +351,7 @@ func PopupMenu(menu Menu, x, y int, parVp *Viewport2D, name string) *Viewport2D
 	win := parVp.Win
 	mainVp := win.Viewport
 	if len(menu) == 0 {
-		log.Printf("GoGi PopupMenu: empty menu given\n")
+		Logf(LogWarn, "gi.PopupMenu", "empty menu given")
 		return nil
 	}
 
@@ -409,10 +408,21 @@ func PopupMenu(menu Menu, x, y int, parVp *Viewport2D, name string) *Viewport2D
 // the index in the slice.  A string equal to curSel will be marked as
 // selected.  Location is from the ContextMenuPos of recv node.
 func StringsChooserPopup(strs []string, curSel string, recv Node2D, fun ki.RecvFunc) *Viewport2D {
+	return StringsChooserPopupInactive(strs, curSel, nil, recv, fun)
+}
+
+// StringsChooserPopupInactive is a version of StringsChooserPopup where
+// the given inactive function (if non-nil) is called for each string, with
+// its index, and if it returns true, the menu item is shown as inactive and
+// cannot be selected -- e.g., for options that are not currently available.
+func StringsChooserPopupInactive(strs []string, curSel string, inactive func(idx int, str string) bool, recv Node2D, fun ki.RecvFunc) *Viewport2D {
 	var menu Menu
 	for i, it := range strs {
 		ac := menu.AddAction(ActOpts{Label: it, Data: i}, recv, fun)
 		ac.SetSelectedState(it == curSel)
+		if inactive != nil && inactive(i, it) {
+			ac.SetInactive()
+		}
 	}
 	nb := recv.AsNode2D()
 	pos := recv.ContextMenuPos()
@@ -637,10 +647,10 @@ var MenuButtonProps = ki.Props{
 
 func (mb *MenuButton) ConfigParts() {
 	config := kit.TypeAndNameList{}
-	icIdx, lbIdx := mb.ConfigPartsIconLabel(&config, string(mb.Icon), mb.Text)
+	icIdx, lbIdx := mb.ConfigPartsIconLabel(&config, string(mb.Icon), mb.LabelText())
 	indIdx := mb.ConfigPartsAddIndicator(&config, true) // default on
 	mods, updt := mb.Parts.ConfigChildren(config)
-	mb.ConfigPartsSetIconLabel(string(mb.Icon), mb.Text, icIdx, lbIdx)
+	mb.ConfigPartsSetIconLabel(string(mb.Icon), mb.LabelText(), icIdx, lbIdx)
 	mb.ConfigPartsIndicator(indIdx)
 	if mods {
 		mb.UpdateEnd(updt)
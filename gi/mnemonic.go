@@ -0,0 +1,236 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/ki/ki"
+)
+
+// Mnemonics (access keys) are declared in the Text of buttons and actions,
+// including menu items, with an & before the character, e.g., "&File" --
+// && is a literal &.  While the Alt key is held down, the mnemonics of the
+// buttons in the current scene (the open popup, or otherwise the window)
+// are shown underlined, and Alt plus the character activates the button
+// (or just the character within a menu).  If several buttons declare the
+// same mnemonic, the later ones (in tree order) get another character of
+// their text, preferring the starts of words.
+
+// ParseMnemonic parses the mnemonic in given button text, returning the
+// text without the & markup, the lower-case mnemonic character (0 if
+// none), and its rune index in the returned text (-1 if none).  An & that
+// is not followed by a letter or digit, or that starts an html entity,
+// e.g., &amp;, is kept as is.
+func ParseMnemonic(txt string) (string, rune, int) {
+	if !strings.Contains(txt, "&") {
+		return txt, 0, -1
+	}
+	rs := []rune(txt)
+	var sb strings.Builder
+	mn := rune(0)
+	idx := -1
+	n := 0
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		if r == '&' && i+1 < len(rs) {
+			nr := rs[i+1]
+			switch {
+			case nr == '&':
+				i++
+			case mn == 0 && (unicode.IsLetter(nr) || unicode.IsDigit(nr)) && !isHTMLEntity(rs[i:]):
+				mn = unicode.ToLower(nr)
+				idx = n
+				continue
+			}
+		}
+		sb.WriteRune(r)
+		n++
+	}
+	return sb.String(), mn, idx
+}
+
+// isHTMLEntity returns true if given runes start with an html entity,
+// e.g., &amp; or &#38;
+func isHTMLEntity(rs []rune) bool {
+	for i := 1; i < len(rs) && i < 10; i++ {
+		r := rs[i]
+		if r == ';' {
+			return i > 1
+		}
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || (i == 1 && r == '#')) {
+			return false
+		}
+	}
+	return false
+}
+
+// StripMnemonic returns given button text without the & markup of its
+// mnemonic, if any -- e.g., for the name of an action
+func StripMnemonic(txt string) string {
+	str, _, _ := ParseMnemonic(txt)
+	return str
+}
+
+// LabelText returns the text shown in the label of the button: its Text,
+// without the & markup of the mnemonic, which is underlined while the
+// mnemonics are shown
+func (bb *ButtonBase) LabelText() string {
+	txt, _, _ := ParseMnemonic(bb.Text)
+	if !bb.MnemonicOn || bb.MnemonicIdx < 0 {
+		return txt
+	}
+	rs := []rune(txt)
+	if bb.MnemonicIdx >= len(rs) {
+		return txt
+	}
+	return string(rs[:bb.MnemonicIdx]) + "<u>" + string(rs[bb.MnemonicIdx]) + "</u>" + string(rs[bb.MnemonicIdx+1:])
+}
+
+// SetMnemonicOn sets whether the mnemonic of the button is shown
+// underlined, updating its label
+func (bb *ButtonBase) SetMnemonicOn(show bool) {
+	if bb.MnemonicOn == show || bb.This() == nil || bb.IsDestroyed() {
+		return
+	}
+	bb.MnemonicOn = show
+	updt := bb.UpdateStart()
+	bb.SetFullReRender()
+	bb.This().(ButtonWidget).ConfigParts()
+	bb.UpdateEnd(updt)
+}
+
+// AssignMnemonics assigns the mnemonics of the active, visible buttons
+// within given scene (the root of a window or popup), resolving conflicts
+// among them, and returns those that have a mnemonic
+func AssignMnemonics(scene ki.Ki) []*ButtonBase {
+	var bbs, confl []*ButtonBase
+	used := map[rune]bool{}
+	scene.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		nii, ni := KiToNode2D(k)
+		if nii == nil || ni.IsInvisible() || ni.IsInactive() || isBusyLayout(k) {
+			return ki.Break
+		}
+		bw, ok := k.(ButtonWidget)
+		if !ok {
+			return ki.Continue
+		}
+		bb := bw.AsButtonBase()
+		_, mn, idx := ParseMnemonic(bb.Text)
+		bb.Mnemonic, bb.MnemonicIdx = mn, idx
+		if mn == 0 || !bb.IsVisible() {
+			return ki.Continue
+		}
+		if used[mn] {
+			confl = append(confl, bb)
+		} else {
+			used[mn] = true
+			bbs = append(bbs, bb)
+		}
+		return ki.Continue
+	})
+	for _, bb := range confl {
+		bb.Mnemonic, bb.MnemonicIdx = 0, -1
+		rs := []rune(StripMnemonic(bb.Text))
+		for pass := 0; pass < 2 && bb.Mnemonic == 0; pass++ {
+			for i, r := range rs {
+				if !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+					continue
+				}
+				if pass == 0 && i > 0 && !unicode.IsSpace(rs[i-1]) { // word starts first
+					continue
+				}
+				if lr := unicode.ToLower(r); !used[lr] {
+					used[lr] = true
+					bb.Mnemonic, bb.MnemonicIdx = lr, i
+					bbs = append(bbs, bb)
+					break
+				}
+			}
+		}
+		if bb.Mnemonic == 0 && KeyEventTrace {
+			fmt.Printf("Mnemonic of button: %v conflicts, and no other character is available\n", bb.Path())
+		}
+	}
+	return bbs
+}
+
+// MnemonicScene returns the scene whose mnemonics are currently active in
+// the window: the current popup if any, and otherwise the window viewport
+func (w *Window) MnemonicScene() ki.Ki {
+	return w.FocusTopNode()
+}
+
+// ShowMnemonics shows the mnemonics of the buttons in the current scene
+// underlined (or hides them, if show is false) -- done while the Alt key
+// is held down
+func (w *Window) ShowMnemonics(show bool) {
+	if !show {
+		if w.Mnemonics == nil {
+			return
+		}
+		for _, bb := range w.Mnemonics {
+			bb.SetMnemonicOn(false)
+		}
+		w.Mnemonics = nil
+		return
+	}
+	w.ShowMnemonics(false)
+	w.Mnemonics = AssignMnemonics(w.MnemonicScene())
+	if w.Mnemonics == nil {
+		w.Mnemonics = []*ButtonBase{}
+	}
+	for _, bb := range w.Mnemonics {
+		bb.SetMnemonicOn(true)
+	}
+}
+
+// MnemonicKeyEvent shows the mnemonics while the Alt key is held down,
+// and hides them when it is released or another key is pressed without it
+func (w *Window) MnemonicKeyEvent(e *key.Event) {
+	switch {
+	case e.Code == key.CodeLeftAlt || e.Code == key.CodeRightAlt:
+		w.ShowMnemonics(e.Action == key.Press)
+	case e.Action == key.Press && !key.HasAnyModifierBits(e.Modifiers, key.Alt):
+		w.ShowMnemonics(false)
+	}
+}
+
+// TriggerMnemonic activates the button in the current scene whose
+// mnemonic is given key chord: Alt plus the character, or just the
+// character within a menu -- returns true if one was activated
+func (w *Window) TriggerMnemonic(e *key.ChordEvent) bool {
+	if e.Rune == 0 || key.HasAnyModifierBits(e.Modifiers, key.Control, key.Meta) {
+		return false
+	}
+	if !key.HasAllModifierBits(e.Modifiers, key.Alt) && !PopupIsMenu(w.CurPopup()) {
+		return false
+	}
+	mn := unicode.ToLower(e.Rune)
+	if mn == ' ' {
+		return false
+	}
+	shown := w.Mnemonics != nil
+	bbs := w.Mnemonics
+	if !shown {
+		bbs = AssignMnemonics(w.MnemonicScene())
+	}
+	for _, bb := range bbs {
+		if bb.Mnemonic != mn || bb.IsDestroyed() || bb.IsInactive() {
+			continue
+		}
+		if KeyEventTrace {
+			fmt.Printf("Win: %v Mnemonic: %c, button: %v triggered\n", w.Nm, mn, bb.Path())
+		}
+		w.ShowMnemonics(false)
+		bb.ButtonPress()
+		bb.This().(ButtonWidget).ButtonRelease()
+		return true
+	}
+	return false
+}
@@ -6,7 +6,6 @@ package gi
 
 import (
 	"image"
-	"log"
 	"sync"
 
 	"github.com/goki/gi/gist"
@@ -349,7 +348,7 @@ func (nb *NodeBase) StyleProps(selector string) ki.Props {
 	if ok {
 		return spm
 	}
-	log.Printf("gist.StyleProps: looking for a ki.Props for style selector: %v, instead got type: %T, for node: %v\n", selector, spm, nb.Path())
+	Logf(LogWarn, "gi.NodeBase", "StyleProps: looking for a ki.Props for style selector: %v, instead got type: %T, for node: %v", selector, spm, nb.Path())
 	return nil
 }
 
@@ -7,7 +7,6 @@ package gi
 import (
 	"fmt"
 	"image"
-	"log"
 	"reflect"
 
 	"github.com/goki/gi/girl"
@@ -78,7 +77,7 @@ var Node2DBaseProps = ki.Props{
 func (nb *Node2DBase) CopyFieldsFrom(frm any) {
 	fr, ok := frm.(*Node2DBase)
 	if !ok {
-		log.Printf("GoGi node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier Node2DBase one\n", ki.Type(nb).Name())
+		Logf(LogWarn, "gi.Node2DBase", "node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier Node2DBase one", ki.Type(nb).Name())
 		ki.GenCopyFieldsFrom(nb.This(), frm)
 		return
 	}
@@ -273,6 +272,15 @@ const (
 	// focused widget to resume active keyboard focus.
 	FocusActive
 
+	// FocusWithinGot means that one of the children of this node just got
+	// keyboard focus, when none had it before -- containers can use this
+	// to update their styling (see also HasFocusWithin).
+	FocusWithinGot
+
+	// FocusWithinLost means that none of the children of this node have
+	// keyboard focus anymore, after one of them had it before.
+	FocusWithinLost
+
 	FocusChangesN
 )
 
@@ -0,0 +1,226 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// OutlineSection is a section of a document, shown in an OutlinePanel
+type OutlineSection struct {
+	Title string   `desc:"title of the section, shown in the panel"`
+	Level int      `desc:"nesting level of the section: 0 for top-level sections, and one more than that of the enclosing section for nested ones"`
+	Start int      `desc:"start of the section in the document, in units defined by the Outliner, e.g., lines of text -- sections are in order of their Start"`
+	End   int      `desc:"end of the section in the document (exclusive), in the same units as Start"`
+	Icon  IconName `desc:"optional icon shown before the title"`
+}
+
+// Outliner is the interface for documents whose structure can be shown
+// in an OutlinePanel: giv.TextView (symbols of the code, from pi),
+// giv.MarkdownView (headings) and giv.StructView (fields of long forms)
+// implement it.
+type Outliner interface {
+	// OutlineSections returns the sections of the document, in order
+	OutlineSections() []OutlineSection
+
+	// OutlinePos returns the current location in the document, typically
+	// the start of the visible region, in the units of the section ranges
+	OutlinePos() int
+
+	// OutlineGoTo scrolls to and highlights the section at given index in
+	// the OutlineSections
+	OutlineGoTo(idx int)
+
+	// OutlineSignal returns the signal on which the document emits
+	// OutlineSignals when its sections or location change
+	OutlineSignal() *ki.Signal
+}
+
+// OutlineSignals are signals that an Outliner emits for OutlinePanels
+type OutlineSignals int64
+
+const (
+	// OutlineChanged means the sections of the document may have changed
+	OutlineChanged OutlineSignals = iota
+
+	// OutlineMoved means the current location in the document may have
+	// changed, e.g., by scrolling
+	OutlineMoved
+
+	OutlineSignalsN
+)
+
+//go:generate stringer -type=OutlineSignals
+
+// OutlineSectionAt returns the index of the section that has given
+// location: the last one starting at or before it, which is the innermost
+// one for nested sections -- -1 if it is before all of them.
+func OutlineSectionAt(secs []OutlineSection, pos int) int {
+	idx := -1
+	for i := range secs {
+		if secs[i].Start > pos {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//   OutlinePanel
+
+// OutlinePanel shows the structure of a document, its Src Outliner, as a
+// list of its sections, indented by their nesting level.  Clicking on a
+// section scrolls the document to it and highlights it, and the section
+// with the current location in the document is selected, and kept in view,
+// as the document scrolls.
+type OutlinePanel struct {
+	Frame
+	Src      Outliner         `json:"-" xml:"-" desc:"the document whose outline is shown"`
+	Sections []OutlineSection `json:"-" xml:"-" desc:"the sections of the Src that are shown"`
+	CurIdx   int              `json:"-" xml:"-" desc:"index of the section with the current location in the Src, -1 if none"`
+}
+
+var KiT_OutlinePanel = kit.Types.AddType(&OutlinePanel{}, OutlinePanelProps)
+
+// AddNewOutlinePanel adds a new outline panel to given parent node, with
+// given name.
+func AddNewOutlinePanel(parent ki.Ki, name string) *OutlinePanel {
+	return parent.AddNewChild(KiT_OutlinePanel, name).(*OutlinePanel)
+}
+
+// OutlinePanelProps are style properties for OutlinePanel
+var OutlinePanelProps = ki.Props{
+	"EnumType:Flag":    KiT_NodeFlags,
+	"background-color": &Prefs.Colors.Background,
+	"color":            &Prefs.Colors.Font,
+	"padding":          units.NewPx(2),
+	"spacing":          units.NewPx(0),
+	"width":            units.NewEm(12),
+	"max-width":        -1,
+	"max-height":       -1,
+	"overflow":         gist.OverflowAuto,
+}
+
+// OutlineIndent is the indentation of sections in an OutlinePanel for each
+// level of nesting
+var OutlineIndent = units.NewEm(1)
+
+// SetSrc sets the document whose outline is shown, and shows it -- nil
+// clears the panel
+func (op *OutlinePanel) SetSrc(src Outliner) {
+	if op.Src != nil {
+		op.Src.OutlineSignal().Disconnect(op.This())
+	}
+	op.Src = src
+	if src != nil {
+		src.OutlineSignal().Connect(op.This(), func(recv, send ki.Ki, sig int64, data any) {
+			opp := recv.Embed(KiT_OutlinePanel).(*OutlinePanel)
+			if OutlineSignals(sig) == OutlineChanged {
+				opp.UpdateSections()
+			} else {
+				opp.UpdateCur()
+			}
+		})
+	}
+	op.UpdateSections()
+}
+
+// UpdateSections updates the sections shown from the Src, if they changed,
+// and the current one
+func (op *OutlinePanel) UpdateSections() {
+	var secs []OutlineSection
+	if op.Src != nil {
+		secs = op.Src.OutlineSections()
+	}
+	if len(op.Kids) == len(secs) && outlineSectionsEqual(secs, op.Sections) {
+		op.UpdateCur()
+		return
+	}
+	updt := op.UpdateStart()
+	op.Lay = LayoutVert
+	op.SetFullReRender()
+	op.DeleteChildren(ki.DestroyKids)
+	op.Sections = secs
+	op.CurIdx = -1
+	for i := range secs {
+		sc := &secs[i]
+		ac := AddNewAction(op, fmt.Sprintf("sec-%d", i))
+		ac.Class = "menu-action"
+		ac.Text = sc.Title
+		ac.Icon = sc.Icon
+		ac.Data = i
+		ac.SetProp("padding-left", units.NewValue(OutlineIndent.Val*float32(sc.Level), OutlineIndent.Un))
+		ac.ActionSig.ConnectOnly(op.This(), func(recv, send ki.Ki, sig int64, data any) {
+			recv.Embed(KiT_OutlinePanel).(*OutlinePanel).GoTo(data.(int))
+		})
+	}
+	op.UpdateCur()
+	op.UpdateEnd(updt)
+}
+
+// outlineSectionsEqual returns true if the sections are the same
+func outlineSectionsEqual(a, b []OutlineSection) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateCur selects the section with the current location in the Src
+func (op *OutlinePanel) UpdateCur() {
+	if op.Src == nil {
+		return
+	}
+	op.SetCur(OutlineSectionAt(op.Sections, op.Src.OutlinePos()))
+}
+
+// SetCur selects the section at given index (-1 for none), scrolling the
+// panel to show it
+func (op *OutlinePanel) SetCur(idx int) {
+	if idx == op.CurIdx {
+		return
+	}
+	if ac := op.SectionAction(op.CurIdx); ac != nil {
+		ac.SetSelectedState(false)
+		ac.UpdateSig()
+	}
+	op.CurIdx = idx
+	if ac := op.SectionAction(idx); ac != nil {
+		ac.SetSelectedState(true)
+		ac.UpdateSig()
+		ac.ScrollToMe()
+	}
+}
+
+// SectionAction returns the action for the section at given index, or nil
+// if it is out of range
+func (op *OutlinePanel) SectionAction(idx int) *Action {
+	if idx < 0 || idx >= len(op.Kids) {
+		return nil
+	}
+	ac, _ := op.Kids[idx].(*Action)
+	return ac
+}
+
+// GoTo scrolls the Src to the section at given index and highlights it,
+// selecting it in the panel
+func (op *OutlinePanel) GoTo(idx int) {
+	if op.Src == nil || idx < 0 || idx >= len(op.Sections) {
+		return
+	}
+	op.Src.OutlineGoTo(idx)
+	op.SetCur(idx)
+}
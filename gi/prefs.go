@@ -8,18 +8,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os/user"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/goki/gi/girl"
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
 	"github.com/goki/gi/oswin/mouse"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
 	"github.com/goki/mat32"
+	"github.com/goki/pi/filecat"
 	"github.com/goki/pi/langs/golang"
 )
 
@@ -28,25 +30,31 @@ import (
 // CSS-style sheets under CustomStyle.  These prefs are saved and loaded from
 // the GoGi user preferences directory -- see oswin/App for further info.
 type Preferences struct {
-	LogicalDPIScale      float32                `min:"0.1" step:"0.1" desc:"overall scaling factor for Logical DPI as a multiplier on Physical DPI -- smaller numbers produce smaller font sizes etc"`
-	ScreenPrefs          map[string]ScreenPrefs `desc:"screen-specific preferences -- will override overall defaults if set"`
-	Colors               ColorPrefs             `desc:"active color preferences"`
-	ColorSchemes         map[string]*ColorPrefs `desc:"named color schemes -- has Light and Dark schemes by default"`
-	Params               ParamPrefs             `view:"inline" desc:"parameters controlling GUI behavior"`
-	Editor               EditorPrefs            `view:"inline" desc:"editor preferences -- for TextView etc"`
-	KeyMap               KeyMapName             `desc:"select the active keymap from list of available keymaps -- see Edit KeyMaps for editing / saving / loading that list"`
-	SaveKeyMaps          bool                   `desc:"if set, the current available set of key maps is saved to your preferences directory, and automatically loaded at startup -- this should be set if you are using custom key maps, but it may be safer to keep it <i>OFF</i> if you are <i>not</i> using custom key maps, so that you'll always have the latest compiled-in standard key maps with all the current key functions bound to standard key chords"`
-	SaveDetailed         bool                   `desc:"if set, the detailed preferences are saved and loaded at startup -- only "`
-	CustomStyles         ki.Props               `desc:"a custom style sheet -- add a separate Props entry for each type of object, e.g., button, or class using .classname, or specific named element using #name -- all are case insensitive"`
-	CustomStylesOverride bool                   `desc:"if true my custom styles override other styling (i.e., they come <i>last</i> in styling process -- otherwise they provide defaults that can be overridden by app-specific styling (i.e, they come first)."`
-	FontFamily           FontName               `desc:"default font family when otherwise not specified"`
-	MonoFont             FontName               `desc:"default mono-spaced font family"`
-	FontPaths            []string               `desc:"extra font paths, beyond system defaults -- searched first"`
-	User                 User                   `desc:"user info -- partially filled-out automatically if empty / when prefs first created"`
-	FavPaths             FavPaths               `desc:"favorite paths, shown in FileViewer and also editable there"`
-	FileViewSort         string                 `view:"-" desc:"column to sort by in FileView, and :up or :down for direction -- updated automatically via FileView"`
-	ColorFilename        FileName               `view:"-" ext:".json" desc:"filename for saving / loading colors"`
-	Changed              bool                   `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
+	LogicalDPIScale      float32                                `min:"0.1" step:"0.1" desc:"overall scaling factor for Logical DPI as a multiplier on Physical DPI -- smaller numbers produce smaller font sizes etc"`
+	TextScale            float32                                `min:"0.5" max:"4" step:"0.1" desc:"scaling factor for the size of all text, independent of the Logical DPI scaling (zoom) of everything -- e.g., 1.25 for larger text"`
+	TextScaleOS          bool                                   `desc:"if set, the text scaling factor set in the operating system, where available (e.g., the text scaling factor of the GNOME desktop on Linux, or the text size in the Windows accessibility settings), is multiplied by TextScale"`
+	ScreenPrefs          map[string]ScreenPrefs                 `desc:"screen-specific preferences -- will override overall defaults if set"`
+	Colors               ColorPrefs                             `desc:"active color preferences"`
+	ColorSchemes         map[string]*ColorPrefs                 `desc:"named color schemes -- has Light and Dark schemes by default"`
+	Params               ParamPrefs                             `view:"inline" desc:"parameters controlling GUI behavior"`
+	Editor               EditorPrefs                            `view:"inline" desc:"editor preferences -- for TextView etc"`
+	EditorLangs          map[filecat.Supported]*EditorLangPrefs `desc:"editor preferences for specific languages, which override the corresponding Editor preferences for files in them"`
+	Sounds               SoundPrefs                             `desc:"sounds played for standard events, such as error dialogs (audio cues)"`
+	KeyMap               KeyMapName                             `desc:"select the active keymap from list of available keymaps -- see Edit KeyMaps for editing / saving / loading that list"`
+	SaveKeyMaps          bool                                   `desc:"if set, the current available set of key maps is saved to your preferences directory, and automatically loaded at startup -- this should be set if you are using custom key maps, but it may be safer to keep it <i>OFF</i> if you are <i>not</i> using custom key maps, so that you'll always have the latest compiled-in standard key maps with all the current key functions bound to standard key chords"`
+	SaveDetailed         bool                                   `desc:"if set, the detailed preferences are saved and loaded at startup -- only "`
+	CustomStyles         ki.Props                               `desc:"a custom style sheet -- add a separate Props entry for each type of object, e.g., button, or class using .classname, or specific named element using #name -- all are case insensitive"`
+	CustomStylesOverride bool                                   `desc:"if true my custom styles override other styling (i.e., they come <i>last</i> in styling process -- otherwise they provide defaults that can be overridden by app-specific styling (i.e, they come first)."`
+	FontFamily           FontName                               `desc:"default font family when otherwise not specified"`
+	MonoFont             FontName                               `desc:"default mono-spaced font family"`
+	FontPaths            []string                               `desc:"extra font paths, beyond system defaults -- searched first"`
+	FontSubpixel         girl.SubpixelModes                     `desc:"subpixel (LCD) antialiasing of text, which can improve legibility on standard-DPI LCD screens -- must match the physical order of the color elements on your screen -- automatically disabled for rotated and transparent text"`
+	User                 User                                   `desc:"user info -- partially filled-out automatically if empty / when prefs first created"`
+	FavPaths             FavPaths                               `desc:"favorite paths, shown in FileViewer and also editable there"`
+	FileViewSort         string                                 `view:"-" desc:"column to sort by in FileView, and :up or :down for direction -- updated automatically via FileView"`
+	FileOpenWith         map[string]string                      `desc:"default app for opening files with given extension (e.g., .png), by the name of an in-app handler or a system app, as set with Default App in the file tree context menu -- otherwise the system default app is used"`
+	ColorFilename        FileName                               `view:"-" ext:".json" desc:"filename for saving / loading colors"`
+	Changed              bool                                   `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
 }
 
 var KiT_Preferences = kit.Types.AddType(&Preferences{}, PreferencesProps)
@@ -56,14 +64,19 @@ var Prefs = Preferences{}
 
 func init() {
 	gist.ThePrefs = &Prefs
+	girl.FontLibChangedFunc = UpdateAllFonts
 }
 
 func (pf *Preferences) Defaults() {
 	pf.LogicalDPIScale = 1.0
+	pf.TextScale = 1.0
+	pf.TextScaleOS = true
 	pf.Colors.Defaults()
 	pf.ColorSchemes = DefaultColorSchemes()
 	pf.Params.Defaults()
 	pf.Editor.Defaults()
+	pf.EditorLangs = DefaultEditorLangs()
+	pf.Sounds.Defaults()
 	pf.FavPaths.SetToDefaults()
 	pf.FontFamily = "Go"
 	pf.MonoFont = "Go Mono"
@@ -106,12 +119,12 @@ func (pf *Preferences) Save() error {
 	pnm := filepath.Join(pdir, PrefsFileName)
 	b, err := json.MarshalIndent(pf, "", "  ")
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.Preferences", "Save: %v", err)
 		return err
 	}
 	err = ioutil.WriteFile(pnm, b, 0644)
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.Preferences", "Save: %v", err)
 	}
 	if pf.SaveKeyMaps {
 		AvailKeyMaps.SavePrefs()
@@ -149,7 +162,7 @@ func (pf *Preferences) SaveColors(filename FileName) error {
 func (pf *Preferences) LightMode() {
 	lc, ok := pf.ColorSchemes["Light"]
 	if !ok {
-		log.Printf("Light ColorScheme not found\n")
+		Logf(LogWarn, "gi.Preferences", "LightMode: Light ColorScheme not found")
 		return
 	}
 	pf.Colors = *lc
@@ -161,7 +174,7 @@ func (pf *Preferences) LightMode() {
 func (pf *Preferences) DarkMode() {
 	lc, ok := pf.ColorSchemes["Dark"]
 	if !ok {
-		log.Printf("Dark ColorScheme not found\n")
+		Logf(LogWarn, "gi.Preferences", "DarkMode: Dark ColorScheme not found")
 		return
 	}
 	pf.Colors = *lc
@@ -193,7 +206,16 @@ func (pf *Preferences) Apply() {
 	TheViewIFace.SetHiStyleDefault(pf.Colors.HiStyle)
 	mouse.DoubleClickMSec = pf.Params.DoubleClickMSec
 	mouse.ScrollWheelSpeed = pf.Params.ScrollWheelSpeed
+	key.Repeat = pf.Params.KeyRepeat
+	DragStartPix = pf.Params.DragStartPix
+	HoverStartMSec = pf.Params.HoverStartMSec
+	CursorBlinkMSec = pf.Params.CursorBlinkMSec
 	LocalMainMenu = pf.Params.LocalMainMenu
+	WinRefreshPacing = pf.Params.RefreshPacing
+	WinMaxFPS = pf.Params.MaxFPS
+	WinIdleTimeout = time.Duration(pf.Params.IdleSecs) * time.Second
+	girl.TextSubpixel = pf.FontSubpixel
+	pf.ApplyTextScale()
 
 	if pf.KeyMap != "" {
 		SetActiveKeyMapName(pf.KeyMap) // fills in missing pieces
@@ -210,6 +232,20 @@ func (pf *Preferences) Apply() {
 	pf.ApplyDPI()
 }
 
+// ApplyTextScale sets the overall scaling factor for font sizes from the
+// TextScale and TextScaleOS preferences -- UpdateAll re-styles the windows
+// for it.
+func (pf *Preferences) ApplyTextScale() {
+	ts := pf.TextScale
+	if ts <= 0 {
+		ts = 1
+	}
+	if pf.TextScaleOS && oswin.TheApp != nil {
+		ts *= oswin.TheApp.TextScale()
+	}
+	gist.TextScale = ts
+}
+
 // ApplyDPI updates the screen LogicalDPI values according to current
 // preferences and zoom factor, and then updates all open windows as well.
 func (pf *Preferences) ApplyDPI() {
@@ -252,6 +288,18 @@ func (pf *Preferences) UpdateAll() {
 	}
 }
 
+// UpdateAllFonts updates all open windows after the set of available fonts
+// has changed at runtime (see girl.FontLib AddFontDir, AddFontBytes)
+func UpdateAllFonts() {
+	gist.StyleTemplatesMu.Lock()
+	gist.StyleTemplates = nil
+	gist.StyleTemplatesMu.Unlock()
+	girl.ClearLayoutCache()
+	for _, w := range AllWindows {
+		w.FullReRender()
+	}
+}
+
 // ScreenInfo returns screen info for all screens on the console.
 func (pf *Preferences) ScreenInfo() string {
 	ns := oswin.TheApp.NScreens()
@@ -272,6 +320,11 @@ func (pf *Preferences) VersionInfo() string {
 	return vinfo
 }
 
+// StartupInfo returns the timings of the steps of the startup of the app
+func (pf *Preferences) StartupInfo() string {
+	return strings.ReplaceAll(StartupReport(), "\n", "<br>\n")
+}
+
 // SaveZoom saves the current LogicalDPI scaling, either as the overall
 // default or specific to the current screen.
 func (pf *Preferences) SaveZoom(forCurrentScreen bool) {
@@ -308,6 +361,12 @@ func (pf *Preferences) EditKeyMaps() {
 	TheViewIFace.KeyMapsView(&AvailKeyMaps)
 }
 
+// EditColorSchemes opens the ColorSchemesView editor to customize the
+// Light and Dark color schemes
+func (pf *Preferences) EditColorSchemes() {
+	TheViewIFace.ColorSchemesView(pf)
+}
+
 // EditHiStyles opens the HiStyleView editor to customize highlighting styles
 func (pf *Preferences) EditHiStyles() {
 	TheViewIFace.HiStylesView(false) // false = custom
@@ -429,11 +488,20 @@ var PreferencesProps = ki.Props{
 			"icon":        "info",
 			"show-return": true,
 		}},
+		{"StartupInfo", ki.Props{
+			"desc":        "shows the timings of the steps of the startup of the app: loading preferences and fonts, preloading icons, and the first frame of each window",
+			"icon":        "info",
+			"show-return": true,
+		}},
 		{"sep-key", ki.BlankProp{}},
 		{"EditKeyMaps", ki.Props{
 			"icon": "keyboard",
 			"desc": "opens the KeyMapsView editor to create new keymaps / save / load from other files, etc.  Current keymaps are saved and loaded with preferences automatically if SaveKeyMaps is clicked (will be turned on automatically if you open this editor).",
 		}},
+		{"EditColorSchemes", ki.Props{
+			"icon": "color",
+			"desc": "opens the ColorSchemesView editor of the Light and Dark color schemes, with a live preview, and import / export of Material Theme Builder files.",
+		}},
 		{"EditHiStyles", ki.Props{
 			"icon": "file-binary",
 			"desc": "opens the HiStylesView editor of highlighting styles.",
@@ -530,7 +598,7 @@ func (pf *ColorPrefs) PrefColor(clrName string) *gist.Color {
 	case "link":
 		return &pf.Link
 	}
-	log.Printf("Preference color %v (simplified to: %v) not found\n", clrName, lc)
+	Logf(LogWarn, "gi.ColorPrefs", "Preference color %v (simplified to: %v) not found", clrName, lc)
 	return nil
 }
 
@@ -539,7 +607,7 @@ func (pf *ColorPrefs) OpenJSON(filename FileName) error {
 	b, err := ioutil.ReadFile(string(filename))
 	if err != nil {
 		PromptDialog(nil, DlgOpts{Title: "File Not Found", Prompt: err.Error()}, AddOk, NoCancel, nil, nil)
-		log.Println(err)
+		Logf(LogError, "gi.ColorPrefs", "OpenJSON: %v", err)
 		return err
 	}
 	return json.Unmarshal(b, pf)
@@ -549,13 +617,13 @@ func (pf *ColorPrefs) OpenJSON(filename FileName) error {
 func (pf *ColorPrefs) SaveJSON(filename FileName) error {
 	b, err := json.MarshalIndent(pf, "", "  ")
 	if err != nil {
-		log.Println(err) // unlikely
+		Logf(LogError, "gi.ColorPrefs", "SaveJSON: %v", err) // unlikely
 		return err
 	}
 	err = ioutil.WriteFile(string(filename), b, 0644)
 	if err != nil {
 		PromptDialog(nil, DlgOpts{Title: "Could not Save to File", Prompt: err.Error()}, AddOk, NoCancel, nil, nil)
-		log.Println(err)
+		Logf(LogError, "gi.ColorPrefs", "SaveJSON: %v", err)
 	}
 	return err
 }
@@ -567,6 +635,48 @@ func (pf *ColorPrefs) SetToPrefs() {
 	Prefs.UpdateAll()
 }
 
+// ColorPrefsNames are the names of the colors in ColorPrefs, in order,
+// as used in PrefColor
+var ColorPrefsNames = []string{"Font", "Background", "Shadow", "Border", "Control", "Icon", "Select", "Highlight", "Link"}
+
+// Inverse returns a new color scheme with the perceptual (OKLCH) lightness
+// of each of the colors inverted, keeping the hue and chroma -- e.g., to
+// generate a dark scheme from a light one or vice-versa.  EnsureContrast is
+// then called to keep text legible, and the HiStyle is set to the default
+// for the resulting light or dark background.
+func (pf *ColorPrefs) Inverse() *ColorPrefs {
+	ic := *pf
+	for _, nm := range ColorPrefsNames {
+		clr := ic.PrefColor(nm)
+		l, c, h, a := clr.ToOKLCH()
+		clr.SetOKLCH(1-l, c, h, a)
+	}
+	ic.EnsureContrast()
+	if ic.Background.IsDark() {
+		ic.HiStyle = "monokai"
+	} else {
+		ic.HiStyle = "emacs"
+	}
+	return &ic
+}
+
+// EnsureContrast adjusts the lightness of the Font and Link colors as
+// needed to have at least the gist.ContrastAA contrast ratio against the
+// Background, and the Icon color to have at least gist.ContrastAALarge
+// against the Control color, for accessible legibility -- called
+// automatically when generating color schemes.
+func (pf *ColorPrefs) EnsureContrast() {
+	pf.Font = gist.ContrastColor(pf.Font, pf.Background, gist.ContrastAA)
+	pf.Link = gist.ContrastColor(pf.Link, pf.Background, gist.ContrastAA)
+	pf.Icon = gist.ContrastColor(pf.Icon, pf.Control, gist.ContrastAALarge)
+}
+
+// SetInverse sets this color scheme to the Inverse of given other one,
+// e.g., to generate a dark scheme from a light one
+func (pf *ColorPrefs) SetInverse(src *ColorPrefs) {
+	*pf = *src.Inverse()
+}
+
 // ColorPrefsProps defines the ToolBar
 var ColorPrefsProps = ki.Props{
 	"ToolBar": ki.PropSlice{
@@ -594,6 +704,19 @@ var ColorPrefsProps = ki.Props{
 			"desc": "Sets this color scheme as the current active color scheme in Prefs.",
 			"icon": "reset",
 		}},
+		{"OpenMaterialTheme", ki.Props{
+			"label": "Import Material...",
+			"icon":  "file-open",
+			"desc":  "imports colors from a Material Theme Builder json-formatted export file, using the given scheme within it (light or dark)",
+			"Args": ki.PropSlice{
+				{"Theme File Name", ki.Props{
+					"ext": ".json",
+				}},
+				{"Scheme", ki.Props{
+					"default": "light",
+				}},
+			},
+		}},
 	},
 }
 
@@ -611,19 +734,32 @@ type ScreenPrefs struct {
 type ParamPrefs struct {
 	DoubleClickMSec  int     `min:"100" step:"50" desc:"the maximum time interval in msec between button press events to count as a double-click"`
 	ScrollWheelSpeed float32 `min:"0.01" step:"1" desc:"how fast the scroll wheel moves -- typically pixels per wheel step but units can be arbitrary.  It is generally impossible to standardize speed and variable across devices, and we don't have access to the system settings, so unfortunately you have to set it here."`
+	DragStartPix     int     `def:"4" min:"0" max:"100" step:"1" desc:"the number of pixels that the mouse must be moved with a button pressed to start dragging (as opposed to a basic press)"`
+	HoverStartMSec   int     `def:"1000" min:"10" max:"10000" step:"10" desc:"the number of milliseconds that the mouse must rest over a widget before its tooltip is shown"`
+	CursorBlinkMSec  int     `def:"500" min:"0" max:"1000" step:"5" desc:"number of milliseconds that the text cursor blinks on and off -- set to 0 to disable blinking"`
+	KeyRepeat        bool    `def:"true" desc:"holding down a key repeats it -- otherwise only the first press counts"`
 	LocalMainMenu    bool    `desc:"controls whether the main menu is displayed locally at top of each window, in addition to global menu at the top of the screen.  Mac native apps do not do this, but OTOH it makes things more consistent with other platforms, and with larger screens, it can be convenient to have access to all the menu items right there."`
 	BigFileSize      int     `def:"10000000" desc:"the limit of file size, above which user will be prompted before opening / copying, etc."`
 	SavedPathsMax    int     `desc:"maximum number of saved paths to save in FileView"`
 	Smooth3D         bool    `desc:"turn on smoothing in 3D rendering -- this should be on by default but if you get an error telling you to turn it off, then do so (because your hardware can't handle it)"`
+	RefreshPacing    bool    `def:"true" desc:"pace window updates to the refresh rate of the screen, in software -- updates arriving faster than that are combined into one -- this does not change the vsync (present mode) of the display, which is always on"`
+	MaxFPS           int     `def:"0" min:"0" desc:"maximum number of frames per second that windows update at -- 0 = no limit beyond RefreshPacing -- lower values save power at the expense of smoothness"`
+	IdleSecs         int     `def:"30" min:"0" desc:"number of seconds without any user input after which a window becomes idle, and stops cursor blinking and other decorative animations until the next input, to save power -- 0 = never idle"`
 }
 
 func (pf *ParamPrefs) Defaults() {
 	pf.DoubleClickMSec = 500
 	pf.ScrollWheelSpeed = 20
+	pf.DragStartPix = 4
+	pf.HoverStartMSec = 1000
+	pf.CursorBlinkMSec = 500
+	pf.KeyRepeat = true
 	pf.LocalMainMenu = true // much better
 	pf.BigFileSize = 10000000
 	pf.SavedPathsMax = 50
 	pf.Smooth3D = true
+	pf.RefreshPacing = true
+	pf.IdleSecs = 30
 }
 
 // User basic user information that might be needed for different apps
@@ -638,15 +774,23 @@ type User struct {
 // EditorPrefs contains editor preferences.  It can also be set
 // from ki.Props style properties.
 type EditorPrefs struct {
-	TabSize      int  `xml:"tab-size" desc:"size of a tab, in chars -- also determines indent level for space indent"`
-	SpaceIndent  bool `xml:"space-indent" desc:"use spaces for indentation, otherwise tabs"`
-	WordWrap     bool `xml:"word-wrap" desc:"wrap lines at word boundaries -- otherwise long lines scroll off the end"`
-	LineNos      bool `xml:"line-nos" desc:"show line numbers"`
-	Completion   bool `xml:"completion" desc:"use the completion system to suggest options while typing"`
-	SpellCorrect bool `xml:"spell-correct" desc:"suggest corrections for unknown words while typing"`
-	AutoIndent   bool `xml:"auto-indent" desc:"automatically indent lines when enter, tab, }, etc pressed"`
-	EmacsUndo    bool `xml:"emacs-undo" desc:"use emacs-style undo, where after a non-undo command, all the current undo actions are added to the undo stack, such that a subsequent undo is actually a redo"`
-	DepthColor   bool `xml:"depth-color" desc:"colorize the background according to nesting depth"`
+	TabSize         int   `xml:"tab-size" desc:"size of a tab, in chars -- also determines indent level for space indent"`
+	SpaceIndent     bool  `xml:"space-indent" desc:"use spaces for indentation, otherwise tabs"`
+	WordWrap        bool  `xml:"word-wrap" desc:"wrap lines at word boundaries -- otherwise long lines scroll off the end"`
+	LineNos         bool  `xml:"line-nos" desc:"show line numbers"`
+	Completion      bool  `xml:"completion" desc:"use the completion system to suggest options while typing"`
+	SpellCorrect    bool  `xml:"spell-correct" desc:"suggest corrections for unknown words while typing"`
+	AutoIndent      bool  `xml:"auto-indent" desc:"automatically indent lines when enter, tab, }, etc pressed"`
+	EmacsUndo       bool  `xml:"emacs-undo" desc:"use emacs-style undo, where after a non-undo command, all the current undo actions are added to the undo stack, such that a subsequent undo is actually a redo"`
+	DepthColor      bool  `xml:"depth-color" desc:"colorize the background according to nesting depth"`
+	ShowWhitespace  bool  `xml:"show-whitespace" desc:"show spaces as middle dots and tabs as arrows"`
+	ShowEOL         bool  `xml:"show-eol" desc:"show a return arrow at the end of each line"`
+	WrapMarks       bool  `xml:"wrap-marks" desc:"show an arrow at the right edge of each row of a line that continues on the next row through word wrapping"`
+	ShowRulers      bool  `xml:"show-rulers" desc:"show vertical ruler lines at the Rulers columns"`
+	Rulers          []int `xml:"rulers" desc:"columns at which to show vertical ruler lines if ShowRulers, e.g., 80 and 100"`
+	SmartPairs      bool  `xml:"smart-pairs" desc:"automatically insert the closing bracket or quote when typing an opening one, type over it when typing it next, and surround the selected text with the pair when typing an opening one with a selection -- the pairs depend on the language"`
+	RainbowBrackets bool  `xml:"rainbow-brackets" desc:"color brackets according to their nesting depth, for languages with full parsing support"`
+	FormatOnSave    bool  `xml:"format-on-save" desc:"format the text when saving it, with the formatter for its language, if there is one -- generally set for specific languages in EditorLangs"`
 }
 
 // Defaults are the defaults for EditorPrefs
@@ -658,6 +802,8 @@ func (pf *EditorPrefs) Defaults() {
 	pf.SpellCorrect = true
 	pf.AutoIndent = true
 	pf.DepthColor = true
+	pf.Rulers = []int{80, 100}
+	pf.SmartPairs = true
 }
 
 // StyleFromProps styles Slider-specific fields from ki.Prop properties
@@ -707,10 +853,80 @@ func (pf *EditorPrefs) StyleFromProps(props ki.Props) {
 			if iv, ok := kit.ToBool(val); ok {
 				pf.DepthColor = iv
 			}
+		case "show-whitespace":
+			if iv, ok := kit.ToBool(val); ok {
+				pf.ShowWhitespace = iv
+			}
+		case "show-eol":
+			if iv, ok := kit.ToBool(val); ok {
+				pf.ShowEOL = iv
+			}
+		case "wrap-marks":
+			if iv, ok := kit.ToBool(val); ok {
+				pf.WrapMarks = iv
+			}
+		case "show-rulers":
+			if iv, ok := kit.ToBool(val); ok {
+				pf.ShowRulers = iv
+			}
+		case "smart-pairs":
+			if iv, ok := kit.ToBool(val); ok {
+				pf.SmartPairs = iv
+			}
+		case "rainbow-brackets":
+			if iv, ok := kit.ToBool(val); ok {
+				pf.RainbowBrackets = iv
+			}
+		case "format-on-save":
+			if iv, ok := kit.ToBool(val); ok {
+				pf.FormatOnSave = iv
+			}
 		}
 	}
 }
 
+// EditorLangPrefs are editor preferences for a specific language, which
+// override the corresponding Editor preferences for files in it (see
+// Preferences.EditorLangs)
+type EditorLangPrefs struct {
+	TabSize      int  `xml:"tab-size" desc:"size of a tab, in chars -- also determines indent level for space indent"`
+	SpaceIndent  bool `xml:"space-indent" desc:"use spaces for indentation, otherwise tabs"`
+	WordWrap     bool `xml:"word-wrap" desc:"wrap lines at word boundaries -- otherwise long lines scroll off the end"`
+	AutoIndent   bool `xml:"auto-indent" desc:"automatically indent lines when enter, tab, }, etc pressed"`
+	FormatOnSave bool `xml:"format-on-save" desc:"format the text when saving it, with the formatter for the language, if there is one"`
+}
+
+// Apply overrides the corresponding fields of given editor prefs
+func (lp *EditorLangPrefs) Apply(ep *EditorPrefs) {
+	if lp.TabSize > 0 {
+		ep.TabSize = lp.TabSize
+	}
+	ep.SpaceIndent = lp.SpaceIndent
+	ep.WordWrap = lp.WordWrap
+	ep.AutoIndent = lp.AutoIndent
+	ep.FormatOnSave = lp.FormatOnSave
+}
+
+// DefaultEditorLangs returns the default per-language editor preferences
+func DefaultEditorLangs() map[filecat.Supported]*EditorLangPrefs {
+	return map[filecat.Supported]*EditorLangPrefs{
+		filecat.Go:       {TabSize: 4, WordWrap: true, AutoIndent: true, FormatOnSave: true},
+		filecat.Python:   {TabSize: 4, SpaceIndent: true, WordWrap: true, AutoIndent: true},
+		filecat.Yaml:     {TabSize: 2, SpaceIndent: true, WordWrap: true, AutoIndent: true},
+		filecat.Markdown: {TabSize: 4, SpaceIndent: true, WordWrap: true},
+	}
+}
+
+// EditorFor returns the Editor preferences for files in given language,
+// with its EditorLangs preferences applied, if any
+func (pf *Preferences) EditorFor(sup filecat.Supported) EditorPrefs {
+	ep := pf.Editor
+	if lp, has := pf.EditorLangs[sup]; has && lp != nil {
+		lp.Apply(&ep)
+	}
+	return ep
+}
+
 //////////////////////////////////////////////////////////////////
 //  FavoritePaths
 
@@ -778,13 +994,13 @@ func (pf *FilePaths) OpenJSON(filename string) error {
 func (pf *FilePaths) SaveJSON(filename string) error {
 	b, err := json.MarshalIndent(pf, "", "  ")
 	if err != nil {
-		log.Println(err) // unlikely
+		Logf(LogError, "gi.FilePaths", "SaveJSON: %v", err) // unlikely
 		return err
 	}
 	err = ioutil.WriteFile(filename, b, 0644)
 	if err != nil {
 		// PromptDialog(nil, "Could not Save to File", err.Error(), AddOk, NoCancel, nil, nil, nil)
-		log.Println(err)
+		Logf(LogError, "gi.FilePaths", "SaveJSON: %v", err)
 	}
 	return err
 }
@@ -834,32 +1050,31 @@ func OpenPaths() {
 // PrefsDetailed are more detailed params not usually customized, but
 // available for those who really care..
 type PrefsDetailed struct {
-	MenuMaxHeight              int  `def:"30" min:"5" step:"1" desc:"the maximum height of any menu popup panel in units of font height -- scroll bars are enforced beyond that size."`
-	EventSkipLagMSec           int  `def:"50" min:"5" max:"1000" step:"5" desc:"the number of milliseconds of lag between the time the event was sent to the time it is being processed, above which a repeated event type (scroll, drag, resize) is skipped"`
-	FilterLaggyKeyEvents       bool `def:"false" desc:"set to true to apply laggy filter to KeyEvents (normally excluded)"`
-	DragStartMSec              int  `def:"50" min:"5" max:"1000" step:"5" desc:"the number of milliseconds to wait before initiating a regular mouse drag event (as opposed to a basic mouse.Press)"`
-	DragStartPix               int  `def:"4" min:"0" max:"100" step:"1" desc:"the number of pixels that must be moved before initiating a regular mouse drag event (as opposed to a basic mouse.Press)"`
-	DNDStartMSec               int  `def:"200" min:"5" max:"1000" step:"5" desc:"the number of milliseconds to wait before initiating a drag-n-drop event -- gotta drag it like you mean it"`
-	DNDStartPix                int  `def:"20" min:"0" max:"100" step:"1" desc:"the number of pixels that must be moved before initiating a drag-n-drop event -- gotta drag it like you mean it"`
-	HoverStartMSec             int  `def:"1000" min:"10" max:"10000" step:"10" desc:"the number of milliseconds to wait before initiating a hover event (e.g., for opening a tooltip)"`
-	HoverMaxPix                int  `def:"5" min:"0" max:"1000" step:"1" desc:"the maximum number of pixels that mouse can move and still register a Hover event"`
-	CompleteWaitMSec           int  `def:"500" min:"10" max:"10000" step:"10" desc:"the number of milliseconds to wait before offering completions"`
-	CompleteMaxItems           int  `def:"25" min:"5" step:"1" desc:"the maximum number of completions offered in popup"`
-	CursorBlinkMSec            int  `def:"500" min:"0" max:"1000" step:"5" desc:"number of milliseconds that cursor blinks on and off -- set to 0 to disable blinking"`
-	LayoutAutoScrollDelayMSec  int  `def:"25" min:"1" step:"5" desc:"is amount of time to wait (in Milliseconds) before trying to autoscroll again"`
-	LayoutPageSteps            int  `def:"10" min:"1" step:"1" desc:"number of steps to take in PageUp / Down events in terms of number of items"`
-	LayoutFocusNameTimeoutMSec int  `def:"500" min:"0" max:"5000" step:"20" desc:"the number of milliseconds between keypresses to combine characters into name to search for within layout -- starts over after this delay"`
-	LayoutFocusNameTabMSec     int  `def:"2000" min:"10" max:"10000" step:"100" desc:"the number of milliseconds since last focus name event to allow tab to focus on next element with same name."`
-	DialogsSepWindow           bool `def:"true" desc:"open dialogs in separate windows -- else do as popups in main window"`
-	TextViewClipHistMax        int  `def:"100" min:"0" max:"1000" step:"5" desc:"Maximum amount of clipboard history to retain"`
-	TextBufMaxScopeLines       int  `def:"100" min:"10" step:"10" desc:"maximum number of lines to look for matching scope syntax (parens, brackets)"`
-	TextBufDiffRevertLines     int  `def:"10000" min:"0" step:"1000" desc:"text buffer max lines to use diff-based revert to more quickly update e.g., after file has been reformatted"`
-	TextBufDiffRevertDiffs     int  `def:"20" min:"0" step:"1" desc:"text buffer max diffs to use diff-based revert to more quickly update e.g., after file has been reformatted -- if too many differences, just revert"`
-	TextBufMarkupDelayMSec     int  `def:"1000" min:"100" step:"100" desc:"number of milliseconds to wait before starting a new background markup process, after text changes within a single line (always does after line insertion / deletion)"`
-	MapInlineLen               int  `def:"3" min:"2" step:"1" desc:"the number of map elements at or below which an inline representation of the map will be presented -- more convenient for small #'s of props"`
-	StructInlineLen            int  `def:"6" min:"2" step:"1" desc:"the number of elemental struct fields at or below which an inline representation of the struct will be presented -- more convenient for small structs"`
-	SliceInlineLen             int  `def:"6" min:"2" step:"1" desc:"the number of slice elements below which inline will be used"`
-	Changed                    bool `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
+	MenuMaxHeight              int     `def:"30" min:"5" step:"1" desc:"the maximum height of any menu popup panel in units of font height -- scroll bars are enforced beyond that size."`
+	EventSkipLagMSec           int     `def:"50" min:"5" max:"1000" step:"5" desc:"the number of milliseconds of lag between the time the event was sent to the time it is being processed, above which a repeated event type (scroll, drag, resize) is skipped"`
+	FilterLaggyKeyEvents       bool    `def:"false" desc:"set to true to apply laggy filter to KeyEvents (normally excluded)"`
+	DragStartMSec              int     `def:"50" min:"5" max:"1000" step:"5" desc:"the number of milliseconds to wait before initiating a regular mouse drag event (as opposed to a basic mouse.Press)"`
+	DNDStartMSec               int     `def:"200" min:"5" max:"1000" step:"5" desc:"the number of milliseconds to wait before initiating a drag-n-drop event -- gotta drag it like you mean it"`
+	DNDStartPix                int     `def:"20" min:"0" max:"100" step:"1" desc:"the number of pixels that must be moved before initiating a drag-n-drop event -- gotta drag it like you mean it"`
+	HoverMaxPix                int     `def:"5" min:"0" max:"1000" step:"1" desc:"the maximum number of pixels that mouse can move and still register a Hover event"`
+	CompleteWaitMSec           int     `def:"500" min:"10" max:"10000" step:"10" desc:"the number of milliseconds to wait before offering completions"`
+	CompleteMaxItems           int     `def:"25" min:"5" step:"1" desc:"the maximum number of completions offered in popup"`
+	LayoutAutoScrollDelayMSec  int     `def:"25" min:"1" step:"5" desc:"is amount of time to wait (in Milliseconds) before trying to autoscroll again"`
+	LayoutPageSteps            int     `def:"10" min:"1" step:"1" desc:"number of steps to take in PageUp / Down events in terms of number of items"`
+	LayoutFocusNameTimeoutMSec int     `def:"500" min:"0" max:"5000" step:"20" desc:"the number of milliseconds between keypresses to combine characters into name to search for within layout -- starts over after this delay"`
+	LayoutFocusNameTabMSec     int     `def:"2000" min:"10" max:"10000" step:"100" desc:"the number of milliseconds since last focus name event to allow tab to focus on next element with same name."`
+	TypeAheadTimeoutMSec       int     `def:"1000" min:"0" max:"10000" step:"100" desc:"the number of milliseconds between keypresses to combine characters into the query of the type-ahead search in trees and tables -- starts over after this delay"`
+	DialogsSepWindow           bool    `def:"true" desc:"open dialogs in separate windows -- else do as popups in main window"`
+	TextViewClipHistMax        int     `def:"100" min:"0" max:"1000" step:"5" desc:"Maximum amount of clipboard history to retain"`
+	TextBufMaxScopeLines       int     `def:"100" min:"10" step:"10" desc:"maximum number of lines to look for matching scope syntax (parens, brackets)"`
+	TextBufDiffRevertLines     int     `def:"10000" min:"0" step:"1000" desc:"text buffer max lines to use diff-based revert to more quickly update e.g., after file has been reformatted"`
+	TextBufDiffRevertDiffs     int     `def:"20" min:"0" step:"1" desc:"text buffer max diffs to use diff-based revert to more quickly update e.g., after file has been reformatted -- if too many differences, just revert"`
+	TextBufMarkupDelayMSec     int     `def:"1000" min:"100" step:"100" desc:"number of milliseconds to wait before starting a new background markup process, after text changes within a single line (always does after line insertion / deletion)"`
+	TextGamma                  float32 `def:"1.8" min:"1" max:"3" step:"0.1" desc:"gamma value used for blending subpixel-antialiased text (see FontSubpixel in main Prefs) -- higher values make text appear thinner"`
+	MapInlineLen               int     `def:"3" min:"2" step:"1" desc:"the number of map elements at or below which an inline representation of the map will be presented -- more convenient for small #'s of props"`
+	StructInlineLen            int     `def:"6" min:"2" step:"1" desc:"the number of elemental struct fields at or below which an inline representation of the struct will be presented -- more convenient for small structs"`
+	SliceInlineLen             int     `def:"6" min:"2" step:"1" desc:"the number of slice elements below which inline will be used"`
+	Changed                    bool    `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
 }
 
 var KiT_PrefsDetailed = kit.Types.AddType(&PrefsDetailed{}, PrefsDetailedProps)
@@ -890,12 +1105,12 @@ func (pf *PrefsDetailed) Save() error {
 	pnm := filepath.Join(pdir, PrefsDetailedFileName)
 	b, err := json.MarshalIndent(pf, "", "  ")
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.PrefsDetailed", "Save: %v", err)
 		return err
 	}
 	err = ioutil.WriteFile(pnm, b, 0644)
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.PrefsDetailed", "Save: %v", err)
 	}
 	pf.Changed = false
 	return err
@@ -907,20 +1122,19 @@ func (pf *PrefsDetailed) Defaults() {
 	pf.MenuMaxHeight = MenuMaxHeight
 	pf.EventSkipLagMSec = EventSkipLagMSec
 	pf.DragStartMSec = DragStartMSec
-	pf.DragStartPix = DragStartPix
 	pf.DNDStartMSec = DNDStartMSec
 	pf.DNDStartPix = DNDStartPix
-	pf.HoverStartMSec = HoverStartMSec
 	pf.HoverMaxPix = HoverMaxPix
 	pf.CompleteWaitMSec = CompleteWaitMSec
 	pf.CompleteMaxItems = CompleteMaxItems
-	pf.CursorBlinkMSec = CursorBlinkMSec
 	pf.LayoutAutoScrollDelayMSec = LayoutAutoScrollDelayMSec
 	pf.LayoutPageSteps = LayoutPageSteps
 	pf.LayoutFocusNameTimeoutMSec = LayoutFocusNameTimeoutMSec
 	pf.LayoutFocusNameTabMSec = LayoutFocusNameTabMSec
+	pf.TypeAheadTimeoutMSec = TypeAheadTimeoutMSec
 	pf.MenuMaxHeight = MenuMaxHeight
 	pf.DialogsSepWindow = DialogsSepWindow
+	pf.TextGamma = girl.TextGamma
 	TheViewIFace.PrefsDetDefaults(pf)
 	// in giv:
 	// TextViewClipHistMax
@@ -935,18 +1149,19 @@ func (pf *PrefsDetailed) Apply() {
 	MenuMaxHeight = pf.MenuMaxHeight
 	EventSkipLagMSec = pf.EventSkipLagMSec
 	DragStartMSec = pf.DragStartMSec
-	DragStartPix = pf.DragStartPix
 	DNDStartMSec = pf.DNDStartMSec
 	DNDStartPix = pf.DNDStartPix
-	HoverStartMSec = pf.HoverStartMSec
 	HoverMaxPix = pf.HoverMaxPix
 	CompleteWaitMSec = pf.CompleteWaitMSec
 	CompleteMaxItems = pf.CompleteMaxItems
-	CursorBlinkMSec = pf.CursorBlinkMSec
 	LayoutFocusNameTimeoutMSec = pf.LayoutFocusNameTimeoutMSec
 	LayoutFocusNameTabMSec = pf.LayoutFocusNameTabMSec
+	TypeAheadTimeoutMSec = pf.TypeAheadTimeoutMSec
 	MenuMaxHeight = pf.MenuMaxHeight
 	DialogsSepWindow = pf.DialogsSepWindow
+	if pf.TextGamma > 0 {
+		girl.SetTextGamma(pf.TextGamma)
+	}
 	TheViewIFace.PrefsDetApply(pf)
 	// in giv:
 	// TextViewClipHistMax = pf.TextViewClipHistMax
@@ -1029,6 +1244,8 @@ type PrefsDebug struct {
 
 	StructViewIfDebug *bool `desc:"reports errors for viewif directives in struct field tags, for giv.StructView"`
 
+	StyleProvTrace *bool `desc:"records the source of each style value for each widget, which can be viewed with the Style Dump action in the GoGiEditor -- slows styling considerably"`
+
 	Changed bool `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
 }
 
@@ -1062,6 +1279,7 @@ func (pf *PrefsDebug) Connect() {
 	pf.GoCompleteTrace = &golang.CompleteTrace
 	pf.GoTypeTrace = &golang.TraceTypes
 	pf.StructViewIfDebug = &StructViewIfDebug
+	pf.StyleProvTrace = &gist.StyleProvTrace
 }
 
 // Profile toggles profiling on / off
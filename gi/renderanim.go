@@ -0,0 +1,87 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"sync"
+	"time"
+)
+
+// RenderAnimMSec is the number of milliseconds between updates of the
+// nodes that render a continuous animation, e.g., the shimmer of
+// Skeletons or the spinner of a busy Layout -- 0 turns off the animation
+var RenderAnimMSec = 50
+
+// RenderAnimStart is the reference time for the phase of the animations,
+// so that the same kind of them are in unison (e.g., SkeletonPhase)
+var RenderAnimStart = time.Now()
+
+// RenderAnimMu is mutex protecting the RenderAnimTicker and the animated
+// nodes
+var RenderAnimMu sync.Mutex
+
+// RenderAnimTicker is the time.Ticker for the updates of the animated
+// nodes, which runs while any of them is shown
+var RenderAnimTicker *time.Ticker
+
+// renderAnimNodes are the nodes that are updated on each tick of the
+// RenderAnimTicker
+var renderAnimNodes = map[Node2D]struct{}{}
+
+// StartRenderAnim adds given node, which renders a continuous animation
+// based on the current time (e.g., a Skeleton), to the nodes that are
+// updated on each tick of the RenderAnimTicker, until it is no longer
+// visible -- called when it renders.
+func StartRenderAnim(ni Node2D) {
+	if RenderAnimMSec <= 0 {
+		return
+	}
+	RenderAnimMu.Lock()
+	defer RenderAnimMu.Unlock()
+	renderAnimNodes[ni] = struct{}{}
+	if RenderAnimTicker == nil {
+		RenderAnimTicker = time.NewTicker(time.Duration(RenderAnimMSec) * time.Millisecond)
+		go RenderAnim(RenderAnimTicker)
+	}
+}
+
+// StopRenderAnim removes given node from the nodes that are updated to
+// animate them -- e.g., when it is done loading
+func StopRenderAnim(ni Node2D) {
+	RenderAnimMu.Lock()
+	delete(renderAnimNodes, ni)
+	RenderAnimMu.Unlock()
+}
+
+// RenderAnim is the function that updates the animated nodes, on each
+// tick of given ticker, until none of them is visible
+func RenderAnim(tick *time.Ticker) {
+	for range tick.C {
+		RenderAnimMu.Lock()
+		var updt []Node2D
+		for ni := range renderAnimNodes {
+			nb := ni.AsNode2D()
+			if ni.This() == nil || nb.IsDestroyed() || nb.IsDeleted() || !ni.IsVisible() {
+				delete(renderAnimNodes, ni)
+				continue
+			}
+			win := nb.ParentWindow()
+			if win == nil || win.IsResizing() || win.IsClosed() || win.IsUpdating() {
+				continue
+			}
+			updt = append(updt, ni)
+		}
+		if len(renderAnimNodes) == 0 {
+			tick.Stop()
+			RenderAnimTicker = nil
+			RenderAnimMu.Unlock()
+			return
+		}
+		RenderAnimMu.Unlock()
+		for _, ni := range updt {
+			ni.AsNode2D().UpdateSig()
+		}
+	}
+}
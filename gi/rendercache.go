@@ -0,0 +1,68 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/goki/ki/ki"
+)
+
+// RenderCache holds the rendering of the children of a Layout that has
+// CacheRender set, or is busy (see SetBusy), which is reused for subsequent renders until it is
+// invalidated, by any re-layout, re-styling or scrolling of the layout, or
+// any update of a node within it.
+type RenderCache struct {
+	Image *image.RGBA     `desc:"cached rendering of the VpBBox region of the layout"`
+	BBox  image.Rectangle `desc:"VpBBox of the layout when the rendering was cached"`
+	Valid bool            `desc:"whether the cached rendering is valid"`
+}
+
+// Invalidate marks the cache as invalid, so the children are rendered again
+func (rc *RenderCache) Invalidate() {
+	rc.Valid = false
+}
+
+// Restore draws the cached rendering into given image, if it is valid for
+// the given bbox, returning false if not
+func (rc *RenderCache) Restore(dst *image.RGBA, bbox image.Rectangle) bool {
+	if !rc.Valid || rc.Image == nil || rc.BBox != bbox || dst == nil {
+		return false
+	}
+	draw.Draw(dst, bbox, rc.Image, image.ZP, draw.Src)
+	return true
+}
+
+// Save caches the given bbox region of given image
+func (rc *RenderCache) Save(src *image.RGBA, bbox image.Rectangle) {
+	if src == nil || bbox.Empty() {
+		rc.Valid = false
+		return
+	}
+	if rc.Image == nil || rc.Image.Bounds().Size() != bbox.Size() {
+		rc.Image = image.NewRGBA(image.Rectangle{Max: bbox.Size()})
+	}
+	draw.Draw(rc.Image, rc.Image.Bounds(), src, bbox.Min, draw.Src)
+	rc.BBox = bbox
+	rc.Valid = true
+}
+
+// InvalidateRenderCaches invalidates the render caches of given node and
+// all of its parents that have CacheRender set, or are busy, because it
+// is being re-rendered -- called for all re-renders of individual nodes
+func InvalidateRenderCaches(gni Node2D) {
+	gni.FuncUpParent(-1, gni.This(), func(k ki.Ki, level int, d any) bool {
+		if nii, _ := KiToNode2D(k); nii != nil {
+			if ly := nii.AsLayout2D(); ly != nil && (ly.CacheRender || ly.IsBusy()) {
+				ly.RenderCache.Invalidate()
+			}
+		}
+		return ki.Continue
+	})
+	if ly := gni.AsLayout2D(); ly != nil && ly.CacheRender && !ly.IsBusy() { // busy content is static
+		ly.RenderCache.Invalidate()
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/ki"
+)
+
+// RubberBandSpriteName is the name of the sprite used for drawing the
+// rubber band selection rectangle -- only one can be active at a time.
+var RubberBandSpriteName = "gi.RubberBand"
+
+// RubberBand manages rubber-band (marquee) selection: dragging on empty
+// space in a container draws a selection rectangle in the window overlay,
+// and items intersecting the rectangle are selected.  The selection mode is
+// determined by the modifier keys at the start of the drag: no modifier
+// replaces the selection, Shift adds to it, and Ctrl / Meta toggles it.
+// The container is responsible for computing which items are hit, using
+// Rect and Selected -- see Layout.RubberBandSel and giv.SliceViewBase.
+type RubberBand struct {
+	On    bool              `desc:"a rubber band drag is in progress"`
+	Start image.Point       `desc:"window position where the drag started"`
+	End   image.Point       `desc:"current window position of the drag"`
+	Mode  mouse.SelectModes `desc:"selection mode, from the modifier keys at the start"`
+	Orig  map[int]struct{}  `desc:"indexes of the items that were selected at the start, for Shift and Ctrl modes"`
+}
+
+// StartDrag starts a rubber band drag at given window position, with given
+// selection mode and indexes of items that are currently selected.
+func (rb *RubberBand) StartDrag(pos image.Point, mode mouse.SelectModes, orig map[int]struct{}) {
+	rb.On = true
+	rb.Start = pos
+	rb.End = pos
+	rb.Mode = mode
+	rb.Orig = orig
+}
+
+// Rect returns the current selection rectangle, in window coordinates
+func (rb *RubberBand) Rect() image.Rectangle {
+	return image.Rectangle{Min: rb.Start, Max: rb.End}.Canon()
+}
+
+// Selected returns whether an item should be selected, given whether it
+// was originally selected at the start and whether it is hit by the
+// current rectangle, according to the selection mode.
+func (rb *RubberBand) Selected(idx int, hit bool) bool {
+	_, orig := rb.Orig[idx]
+	switch rb.Mode {
+	case mouse.ExtendContinuous:
+		return orig || hit
+	case mouse.ExtendOne:
+		return orig != hit
+	default:
+		return hit
+	}
+}
+
+// DragTo updates the end of the rubber band to given window position,
+// and renders the rectangle sprite in given window.
+func (rb *RubberBand) DragTo(pos image.Point, win *Window) {
+	rb.End = pos
+	if win == nil {
+		return
+	}
+	r := rb.Rect()
+	sz := r.Size()
+	if sz.X < 1 {
+		sz.X = 1
+	}
+	if sz.Y < 1 {
+		sz.Y = 1
+	}
+	sp, ok := win.SpriteByName(RubberBandSpriteName)
+	if !ok {
+		sp = NewSprite(RubberBandSpriteName, sz, r.Min)
+		win.AddSprite(sp)
+		win.ActivateSprite(RubberBandSpriteName)
+	}
+	sp.SetSize(sz)
+	sp.Geom.Pos = r.Min
+	bc := Prefs.Colors.Select.Darker(40)
+	fc := color.RGBA{bc.R / 4, bc.G / 4, bc.B / 4, 64} // premultiplied, 25% opacity
+	ibox := sp.Pixels.Bounds()
+	draw.Draw(sp.Pixels, ibox, &image.Uniform{bc}, image.ZP, draw.Src)
+	ibox = ibox.Inset(1)
+	if !ibox.Empty() {
+		draw.Draw(sp.Pixels, ibox, &image.Uniform{fc}, image.ZP, draw.Src)
+	}
+	win.Sprites.Modified = true
+	win.UpdateSig()
+}
+
+// Stop ends the rubber band drag, removing the sprite from given window
+func (rb *RubberBand) Stop(win *Window) {
+	rb.On = false
+	rb.Orig = nil
+	if win != nil && win.DeleteSprite(RubberBandSpriteName) {
+		win.UpdateSig()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Layout rubber band selection
+
+// RubberBandSelectable returns true if given child of the layout
+// can be selected by rubber band selection
+func (ly *Layout) RubberBandSelectable(kid ki.Ki) bool {
+	nii, ni := KiToNode2D(kid)
+	if ni == nil || nii.AsWidget() == nil || ni.IsInvisible() {
+		return false
+	}
+	switch kid.(type) {
+	case *Stretch, *Space:
+		return false
+	}
+	return true
+}
+
+// SelectedChildren returns the children of the layout that are
+// currently selected
+func (ly *Layout) SelectedChildren() ki.Slice {
+	var sl ki.Slice
+	for _, kid := range ly.Kids {
+		if _, ni := KiToNode2D(kid); ni != nil && ni.IsSelected() {
+			sl = append(sl, kid)
+		}
+	}
+	return sl
+}
+
+// RubberBandStart starts a rubber band selection at given window position,
+// if the position is not within any child, returning true if started.
+func (ly *Layout) RubberBandStart(pos image.Point, mode mouse.SelectModes) bool {
+	orig := make(map[int]struct{})
+	for i, kid := range ly.Kids {
+		nii, ni := KiToNode2D(kid)
+		if ni == nil {
+			continue
+		}
+		if nii.AsWidget() != nil && ni.PosInWinBBox(pos) && ly.RubberBandSelectable(kid) {
+			return false // not empty space
+		}
+		if ni.IsSelected() {
+			orig[i] = struct{}{}
+		}
+	}
+	ly.RubberBand.StartDrag(pos, mode, orig)
+	return true
+}
+
+// RubberBandUpdate updates the rubber band selection to given window
+// position, selecting children that intersect the rectangle, and emitting
+// a WidgetSelected signal if the selection changed.
+func (ly *Layout) RubberBandUpdate(pos image.Point) {
+	rb := &ly.RubberBand
+	rb.DragTo(pos, ly.ParentWindow())
+	r := rb.Rect()
+	updt := ly.UpdateStart()
+	changed := false
+	for i, kid := range ly.Kids {
+		if !ly.RubberBandSelectable(kid) {
+			continue
+		}
+		_, ni := KiToNode2D(kid)
+		sel := rb.Selected(i, ni.WinBBox.Overlaps(r))
+		if sel != ni.IsSelected() {
+			ni.SetSelectedState(sel)
+			changed = true
+		}
+	}
+	if changed {
+		ly.SetFullReRender()
+	}
+	ly.UpdateEnd(updt)
+	if changed {
+		ly.WidgetSig.Emit(ly.This(), int64(WidgetSelected), nil)
+	}
+}
+
+// RubberBandEvents connects the mouse events for rubber band selection
+// of the children of the layout, if RubberBandSel is on.
+func (ly *Layout) RubberBandEvents() {
+	ly.ConnectEvent(oswin.MouseEvent, LowPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.Event)
+		li := recv.Embed(KiT_Layout).(*Layout)
+		if me.Button != mouse.Left {
+			return
+		}
+		switch me.Action {
+		case mouse.Press:
+			li.RubberBandStart(me.Pos(), me.SelectMode())
+		case mouse.Release:
+			if li.RubberBand.On {
+				li.RubberBand.Stop(li.ParentWindow())
+				me.SetProcessed()
+			}
+		}
+	})
+	ly.ConnectEvent(oswin.MouseDragEvent, LowPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.DragEvent)
+		li := recv.Embed(KiT_Layout).(*Layout)
+		if !li.RubberBand.On {
+			return
+		}
+		me.SetProcessed()
+		li.RubberBandUpdate(me.Pos())
+	})
+}
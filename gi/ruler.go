@@ -0,0 +1,371 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"strconv"
+
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+// Ruler
+
+// Ruler is a horizontal or vertical ruler that shows tick marks and numeric
+// labels in given Units, for the content of a scrollable, zoomable area
+// such as the canvas of a drawing or layout editor, which the app keeps in
+// sync by calling SetView whenever it scrolls or zooms.  Dragging from the
+// ruler creates a guide, at a position in Units that can be dragged to move
+// it, and double-clicked to delete it -- the app draws the guides over its
+// content, and is told about changes on the RulerSig.
+type Ruler struct {
+	WidgetBase
+	Dim      mat32.Dims  `xml:"dim" desc:"dimension along which the ruler runs: X = horizontal, Y = vertical"`
+	Units    units.Units `xml:"units" desc:"units of the tick labels and guides"`
+	Origin   float32     `xml:"origin" desc:"position in the content, in unzoomed dots, where the ruler is 0"`
+	Offset   float32     `xml:"offset" desc:"position in the content, in zoomed display dots, at the start of the ruler -- e.g., the scroll position of the content area"`
+	Zoom     float32     `xml:"zoom" desc:"zoom factor of the content: number of display dots per content dot -- 1 if 0"`
+	MinTick  float32     `xml:"min-tick" desc:"minimum number of display dots between labeled major ticks -- the major tick spacing is the smallest 1, 2 or 5 times a power of 10 units that is at least this -- 50 if 0"`
+	Guides   []float32   `xml:"guides" desc:"positions of the guides, in Units"`
+	DragIdx  int         `copy:"-" json:"-" xml:"-" desc:"index of the guide being dragged, or -1 if none"`
+	RulerSig ki.Signal   `copy:"-" json:"-" xml:"-" view:"-" desc:"signal for ruler guides -- see RulerSignals for the types"`
+}
+
+var KiT_Ruler = kit.Types.AddType(&Ruler{}, RulerProps)
+
+// AddNewRuler adds a new ruler to given parent node, with given name and
+// dimension.
+func AddNewRuler(parent ki.Ki, name string, dim mat32.Dims) *Ruler {
+	rl := parent.AddNewChild(KiT_Ruler, name).(*Ruler)
+	rl.Dim = dim
+	return rl
+}
+
+func (rl *Ruler) CopyFieldsFrom(frm any) {
+	fr := frm.(*Ruler)
+	rl.WidgetBase.CopyFieldsFrom(&fr.WidgetBase)
+	rl.Dim = fr.Dim
+	rl.Units = fr.Units
+	rl.Origin = fr.Origin
+	rl.Offset = fr.Offset
+	rl.Zoom = fr.Zoom
+	rl.MinTick = fr.MinTick
+	rl.Guides = append([]float32{}, fr.Guides...)
+}
+
+func (rl *Ruler) Disconnect() {
+	rl.WidgetBase.Disconnect()
+	rl.RulerSig.DisconnectAll()
+}
+
+var RulerProps = ki.Props{
+	"EnumType:Flag":    KiT_NodeFlags,
+	"padding":          units.NewPx(0),
+	"margin":           units.NewPx(0),
+	"font-size":        units.NewPt(8),
+	"color":            &Prefs.Colors.Font,
+	"border-color":     &Prefs.Colors.Border,
+	"border-width":     units.NewPx(1),
+	"background-color": &Prefs.Colors.Control,
+	"guide-color":      &Prefs.Colors.Link,
+}
+
+// RulerSignals are signals that a Ruler can send on its RulerSig, with the
+// index of the guide as the data
+type RulerSignals int64
+
+const (
+	// RulerGuideAdded means a guide was added by dragging from the ruler
+	RulerGuideAdded RulerSignals = iota
+
+	// RulerGuideMoved means a guide is being dragged to a new position --
+	// this is sent throughout the drag
+	RulerGuideMoved
+
+	// RulerGuideDeleted means a guide was deleted by double-clicking on it
+	// -- the data is the index that it had
+	RulerGuideDeleted
+
+	RulerSignalsN
+)
+
+//go:generate stringer -type=RulerSignals
+
+// RulerGuideGrab is the distance in display dots from a guide within which
+// clicking on the ruler grabs the guide
+var RulerGuideGrab = float32(4)
+
+// SetView sets the Offset and Zoom of the ruler, from the scroll position
+// and zoom of the content area, and re-renders it
+func (rl *Ruler) SetView(offset, zoom float32) {
+	if rl.Offset == offset && rl.Zoom == zoom {
+		return
+	}
+	rl.Offset = offset
+	rl.Zoom = zoom
+	rl.UpdateSig()
+}
+
+// SetGuides sets the guides, in Units, and re-renders
+func (rl *Ruler) SetGuides(guides []float32) {
+	rl.Guides = guides
+	rl.UpdateSig()
+}
+
+// EffZoom returns the effective Zoom, which is 1 if not set
+func (rl *Ruler) EffZoom() float32 {
+	if rl.Zoom == 0 {
+		return 1
+	}
+	return rl.Zoom
+}
+
+// UnitDots returns the number of display dots per unit, including the Zoom
+func (rl *Ruler) UnitDots() float32 {
+	return rl.EffZoom() * rl.Sty.UnContext.ToDotsFactor(rl.Units)
+}
+
+// ValueAt returns the value in Units at given display position along the
+// ruler, relative to its start
+func (rl *Ruler) ValueAt(pos float32) float32 {
+	return (pos + rl.Offset - rl.Origin*rl.EffZoom()) / rl.UnitDots()
+}
+
+// PosOf returns the display position along the ruler, relative to its
+// start, of given value in Units
+func (rl *Ruler) PosOf(val float32) float32 {
+	return val*rl.UnitDots() + rl.Origin*rl.EffZoom() - rl.Offset
+}
+
+// TickSpacing returns the spacing in Units of the major (labeled) ticks,
+// and the number of minor ticks per major tick
+func (rl *Ruler) TickSpacing() (float32, int) {
+	mint := rl.MinTick
+	if mint <= 0 {
+		mint = 50
+	}
+	minu := mint / rl.UnitDots()
+	sp := mat32.Pow(10, mat32.Floor(mat32.Log10(minu)))
+	switch {
+	case sp >= minu:
+		return sp, 10
+	case 2*sp >= minu:
+		return 2 * sp, 4
+	case 5*sp >= minu:
+		return 5 * sp, 5
+	}
+	return 10 * sp, 10
+}
+
+// GuideAt returns the index of the guide within RulerGuideGrab of given
+// display position, or -1 if none
+func (rl *Ruler) GuideAt(pos float32) int {
+	for i, g := range rl.Guides {
+		if mat32.Abs(rl.PosOf(g)-pos) <= RulerGuideGrab {
+			return i
+		}
+	}
+	return -1
+}
+
+// RelPos returns the display position along the ruler of given window point
+func (rl *Ruler) RelPos(pt image.Point) float32 {
+	rl.BBoxMu.RLock()
+	defer rl.BBoxMu.RUnlock()
+	rp := pt.Sub(rl.WinBBox.Min)
+	if rl.Dim == mat32.X {
+		return float32(rp.X)
+	}
+	return float32(rp.Y)
+}
+
+func (rl *Ruler) Style2D() {
+	rl.StyMu.Lock()
+	if rl.Dim == mat32.X {
+		rl.SetProp("max-width", -1)
+		rl.SetProp("min-height", units.NewEm(2))
+	} else {
+		rl.SetProp("max-height", -1)
+		rl.SetProp("min-width", units.NewEm(3))
+	}
+	rl.StyMu.Unlock()
+	rl.WidgetBase.Style2D()
+}
+
+func (rl *Ruler) Init2D() {
+	rl.Init2DWidget()
+	rl.DragIdx = -1
+}
+
+// RenderRuler renders the ticks, labels and guides
+func (rl *Ruler) RenderRuler() {
+	rs, pc, st := rl.RenderLock()
+	defer rl.RenderUnlock(rs)
+
+	pos := rl.LayState.Alloc.Pos.AddScalar(st.Layout.Margin.Dots)
+	sz := rl.LayState.Alloc.Size.AddScalar(-2.0 * st.Layout.Margin.Dots)
+	if !st.Font.BgColor.IsNil() {
+		pc.FillBox(rs, pos, sz, &st.Font.BgColor)
+	}
+	ld := rl.Dim
+	ln := sz.Dim(ld)
+	wd := sz.Dim(mat32.OtherDim(ld))
+
+	// line along the edge next to the content
+	pc.StrokeStyle.Width = st.Border.Width
+	pc.StrokeStyle.SetColor(&st.Border.Color)
+	if ld == mat32.X {
+		pc.DrawLine(rs, pos.X, pos.Y+wd, pos.X+ln, pos.Y+wd)
+	} else {
+		pc.DrawLine(rs, pos.X+wd, pos.Y, pos.X+wd, pos.Y+ln)
+	}
+
+	girl.OpenFont(&st.Font, &st.UnContext)
+	sp, nminor := rl.TickSpacing()
+	if !(sp > 0) || mat32.IsInf(sp, 0) { // no valid units
+		pc.Stroke(rs)
+		return
+	}
+	minor := sp / float32(nminor)
+	prec := -1
+	if sp < 1 { // avoid rounding errors in labels
+		prec = int(mat32.Ceil(-mat32.Log10(sp)))
+	}
+	start := mat32.Floor(rl.ValueAt(0)/sp) * sp
+	end := rl.ValueAt(ln)
+	for mi := 0; ; mi++ {
+		v := start + float32(mi)*minor
+		if v > end {
+			break
+		}
+		p := rl.PosOf(v)
+		if p < 0 {
+			continue
+		}
+		tl := 0.25 * wd
+		if mi%nminor == 0 {
+			tl = wd
+			var lbl girl.Text
+			lbl.SetString(strconv.FormatFloat(float64(v), 'f', prec, 32), &st.Font, &st.UnContext, &st.Text, true, 0, 0)
+			lp := pos
+			if ld == mat32.X {
+				lp.X += p + 2
+			} else {
+				lp.Y += p + 1
+				lp.X += 2
+			}
+			lbl.RenderTopPos(rs, lp)
+		} else if mi%nminor == nminor/2 && nminor%2 == 0 {
+			tl = 0.5 * wd
+		}
+		rl.DrawTick(rs, pc, pos, p, wd-tl, wd)
+	}
+	pc.Stroke(rs)
+
+	gc := &Prefs.Colors.Link
+	if pv, ok := rl.PropInherit("guide-color", ki.NoInherit, ki.TypeProps); ok {
+		if c, ok := pv.(*gist.Color); ok {
+			gc = c
+		}
+	}
+	pc.StrokeStyle.Width.Dots = 2
+	pc.StrokeStyle.SetColor(gc)
+	for _, g := range rl.Guides {
+		p := rl.PosOf(g)
+		if p < 0 || p > ln {
+			continue
+		}
+		rl.DrawTick(rs, pc, pos, p, 0, wd)
+	}
+	pc.Stroke(rs)
+}
+
+// DrawTick draws a tick across the ruler at given position p along it, from
+// the start to the end offset across it, measured from the side away from
+// the content (top for horizontal, left for vertical)
+func (rl *Ruler) DrawTick(rs *girl.State, pc *girl.Paint, pos mat32.Vec2, p, st, ed float32) {
+	if rl.Dim == mat32.X {
+		pc.DrawLine(rs, pos.X+p, pos.Y+st, pos.X+p, pos.Y+ed)
+	} else {
+		pc.DrawLine(rs, pos.X+st, pos.Y+p, pos.X+ed, pos.Y+p)
+	}
+}
+
+func (rl *Ruler) Render2D() {
+	if rl.FullReRenderIfNeeded() {
+		return
+	}
+	if rl.PushBounds() {
+		rl.This().(Node2D).ConnectEvents2D()
+		rl.RenderRuler()
+		rl.Render2DChildren()
+		rl.PopBounds()
+	} else {
+		rl.DisconnectAllEvents(RegPri)
+	}
+}
+
+// MouseEvent grabs the guide under the mouse or adds a new one on press,
+// and deletes the guide on double-click
+func (rl *Ruler) MouseEvent() {
+	rl.ConnectEvent(oswin.MouseEvent, RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.Event)
+		rlr := recv.Embed(KiT_Ruler).(*Ruler)
+		if rlr.IsInactive() || me.Button != mouse.Left {
+			return
+		}
+		me.SetProcessed()
+		p := rlr.RelPos(me.Where)
+		switch me.Action {
+		case mouse.Press:
+			rlr.DragIdx = rlr.GuideAt(p)
+			if rlr.DragIdx < 0 {
+				rlr.Guides = append(rlr.Guides, rlr.ValueAt(p))
+				rlr.DragIdx = len(rlr.Guides) - 1
+				rlr.RulerSig.Emit(rlr.This(), int64(RulerGuideAdded), rlr.DragIdx)
+				rlr.UpdateSig()
+			}
+		case mouse.Release:
+			rlr.DragIdx = -1
+		case mouse.DoubleClick:
+			gi := rlr.GuideAt(p)
+			if gi < 0 {
+				return
+			}
+			rlr.DragIdx = -1
+			rlr.Guides = append(rlr.Guides[:gi], rlr.Guides[gi+1:]...)
+			rlr.RulerSig.Emit(rlr.This(), int64(RulerGuideDeleted), gi)
+			rlr.UpdateSig()
+		}
+	})
+}
+
+// MouseDragEvent moves the guide being dragged
+func (rl *Ruler) MouseDragEvent() {
+	rl.ConnectEvent(oswin.MouseDragEvent, RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.DragEvent)
+		rlr := recv.Embed(KiT_Ruler).(*Ruler)
+		if rlr.IsInactive() || rlr.DragIdx < 0 || rlr.DragIdx >= len(rlr.Guides) {
+			return
+		}
+		me.SetProcessed()
+		rlr.Guides[rlr.DragIdx] = rlr.ValueAt(rlr.RelPos(me.Where))
+		rlr.RulerSig.Emit(rlr.This(), int64(RulerGuideMoved), rlr.DragIdx)
+		rlr.UpdateSig()
+	})
+}
+
+func (rl *Ruler) ConnectEvents2D() {
+	rl.MouseEvent()
+	rl.MouseDragEvent()
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+// SearchField
+
+// SearchFieldDelayMSec is the default number of milliseconds that typing in
+// a SearchField must pause for before the search is signaled
+var SearchFieldDelayMSec = 300
+
+// SearchField is a TextField for entering search text, with a leading
+// search icon and a clear button.  Escape clears the text (or reverts
+// editing when it is already empty, as in a TextField).  The search text is
+// sent on the SearchSig once typing pauses for DelayMSec, and immediately
+// on Enter or clearing, so views can filter as the user types -- clicking
+// the search icon signals the search again.
+type SearchField struct {
+	TextField
+	DelayMSec   int         `xml:"delay-msec" desc:"number of milliseconds that typing must pause for before the search is signaled -- SearchFieldDelayMSec if 0"`
+	LastSearch  string      `copy:"-" json:"-" xml:"-" desc:"the text of the last search that was signaled"`
+	SearchSig   ki.Signal   `copy:"-" json:"-" xml:"-" view:"-" desc:"signal for searching -- see SearchFieldSignals for the types"`
+	searchTimer *time.Timer `copy:"-" json:"-" xml:"-"`
+	searchMu    sync.Mutex  `copy:"-" json:"-" xml:"-"`
+}
+
+var KiT_SearchField = kit.Types.AddType(&SearchField{}, SearchFieldProps)
+
+// AddNewSearchField adds a new search field to given parent node, with given name.
+func AddNewSearchField(parent ki.Ki, name string) *SearchField {
+	return parent.AddNewChild(KiT_SearchField, name).(*SearchField)
+}
+
+func (sf *SearchField) CopyFieldsFrom(frm any) {
+	fr := frm.(*SearchField)
+	sf.TextField.CopyFieldsFrom(&fr.TextField)
+	sf.DelayMSec = fr.DelayMSec
+}
+
+func (sf *SearchField) Disconnect() {
+	sf.TextField.Disconnect()
+	sf.SearchSig.DisconnectAll()
+	sf.StopSearchTimer()
+}
+
+// SearchFieldProps are the type properties of the SearchField, which are
+// those of the TextField
+var SearchFieldProps = ki.Props{}
+
+func init() {
+	for k, v := range TextFieldProps {
+		SearchFieldProps[k] = v
+	}
+	kit.Types.SetProps(KiT_SearchField, SearchFieldProps)
+}
+
+// SearchFieldSignals are signals that a SearchField can send on its SearchSig
+type SearchFieldSignals int64
+
+const (
+	// SearchFieldSearch is emitted when the search text has changed, after
+	// typing has paused, or on Enter or clearing.  data is the text.
+	SearchFieldSearch SearchFieldSignals = iota
+
+	SearchFieldSignalsN
+)
+
+//go:generate stringer -type=SearchFieldSignals
+
+// Search signals a search with the current text, if it differs from the
+// LastSearch, or if force is true
+func (sf *SearchField) Search(force bool) {
+	sf.StopSearchTimer()
+	txt := string(sf.EditTxt)
+	if !force && txt == sf.LastSearch {
+		return
+	}
+	sf.LastSearch = txt
+	sf.SearchSig.Emit(sf.This(), int64(SearchFieldSearch), txt)
+}
+
+// SearchAfterDelay signals a search after typing has paused for DelayMSec
+// -- the search is run on the event loop of the window, at the next frame
+// after the delay, unless typing has resumed by then.
+func (sf *SearchField) SearchAfterDelay() {
+	msec := sf.DelayMSec
+	if msec <= 0 {
+		msec = SearchFieldDelayMSec
+	}
+	sf.searchMu.Lock()
+	defer sf.searchMu.Unlock()
+	if sf.searchTimer != nil {
+		sf.searchTimer.Stop()
+	}
+	var tm *time.Timer
+	tm = time.AfterFunc(time.Duration(msec)*time.Millisecond, func() {
+		if sf.IsDeleted() || sf.IsDestroyed() {
+			return
+		}
+		win := sf.ParentWindow()
+		if win == nil {
+			return
+		}
+		win.RunOnNextFrame(func() {
+			sf.searchMu.Lock()
+			cur := sf.searchTimer == tm
+			sf.searchMu.Unlock()
+			if !cur || sf.IsDeleted() || sf.IsDestroyed() {
+				return
+			}
+			sf.Search(false)
+		})
+	})
+	sf.searchTimer = tm
+}
+
+// StopSearchTimer stops any pending delayed search
+func (sf *SearchField) StopSearchTimer() {
+	sf.searchMu.Lock()
+	if sf.searchTimer != nil {
+		sf.searchTimer.Stop()
+		sf.searchTimer = nil
+	}
+	sf.searchMu.Unlock()
+}
+
+// SetSearch sets the search text and signals the search
+func (sf *SearchField) SetSearch(txt string) {
+	sf.SetText(txt)
+	sf.Search(false)
+}
+
+func (sf *SearchField) Init2D() {
+	if sf.LeadIcon.IsNil() {
+		sf.LeadIcon = "search"
+	}
+	if sf.Placeholder == "" {
+		sf.Placeholder = "Search"
+	}
+	sf.TextField.Init2D()
+	sf.TextFieldSig.Connect(sf.This(), func(recv, send ki.Ki, sig int64, data any) {
+		sff := recv.Embed(KiT_SearchField).(*SearchField)
+		switch TextFieldSignals(sig) {
+		case TextFieldInsert, TextFieldBackspace, TextFieldDelete:
+			sff.SearchAfterDelay()
+		case TextFieldDone, TextFieldCleared:
+			sff.Search(false)
+		case TextFieldLeadIconClicked:
+			sff.Search(true)
+		}
+	})
+}
+
+// SearchKeyChordEvent clears the text on Escape, ahead of the TextField key
+// handling -- Escape in an empty field reverts and defocuses as usual
+func (sf *SearchField) SearchKeyChordEvent() {
+	sf.ConnectEvent(oswin.KeyChordEvent, HiPri, func(recv, send ki.Ki, sig int64, d any) {
+		sff := recv.Embed(KiT_SearchField).(*SearchField)
+		if sff.IsInactive() || !sff.IsFocusActive() {
+			return
+		}
+		kt := d.(*key.ChordEvent)
+		if KeyFun(kt.Chord()) == KeyFunAbort && len(sff.EditTxt) > 0 {
+			kt.SetProcessed()
+			sff.CancelComplete()
+			sff.Clear()
+		}
+	})
+}
+
+func (sf *SearchField) ConnectEvents2D() {
+	sf.TextFieldEvents()
+	sf.SearchKeyChordEvent()
+}
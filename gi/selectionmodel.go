@@ -0,0 +1,242 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"sort"
+
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/kit"
+)
+
+// SelectionModes are the policies of a SelectionModel, determining how
+// many items can be selected and how the selection is extended.
+type SelectionModes int32
+
+const (
+	// SelectionMulti allows any set of items to be selected: Shift extends
+	// the selection to the range from the anchor, and Ctrl / Meta toggles
+	// one item, which becomes the new anchor
+	SelectionMulti SelectionModes = iota
+
+	// SelectionContiguous allows only one contiguous range of items to be
+	// selected: Shift and Ctrl / Meta both select the range from the anchor
+	SelectionContiguous
+
+	// SelectionSingle allows at most one item to be selected: all modes
+	// select only the item acted on
+	SelectionSingle
+
+	SelectionModesN
+)
+
+//go:generate stringer -type=SelectionModes
+
+var KiT_SelectionModes = kit.Enums.AddEnum(SelectionModesN, kit.NotBitFlag, nil)
+
+// SelectionChange records changes to a SelectionModel: the indexes of the
+// items added to and removed from the selection, in ascending order.  It is
+// the data of the WidgetSelectionChanged signal sent by item views.
+type SelectionChange struct {
+	Added   []int `desc:"indexes of the items added to the selection"`
+	Removed []int `desc:"indexes of the items removed from the selection"`
+	Current int   `desc:"index of the current item: the last one acted on -- -1 if none"`
+}
+
+// IsEmpty returns true if no items were added or removed
+func (sc *SelectionChange) IsEmpty() bool {
+	return len(sc.Added) == 0 && len(sc.Removed) == 0
+}
+
+// SelectionModel is the selection state of a view of indexed items, shared
+// by SliceView, TableView, TreeView (indexed by ViewIdx) and IconView: the
+// selected indexes, and the anchor and current item used to extend it
+// according to the Mode.  Changes are accumulated until TakeChange, which
+// views call to send a WidgetSelectionChanged signal after each action.
+type SelectionModel struct {
+	Mode     SelectionModes   `desc:"selection policy: multiple items, one contiguous range, or a single item"`
+	Selected map[int]struct{} `copy:"-" json:"-" xml:"-" desc:"indexes of the selected items"`
+	Anchor   int              `copy:"-" json:"-" xml:"-" desc:"index of the item from which ranges are extended -- -1 if none"`
+	Current  int              `copy:"-" json:"-" xml:"-" desc:"index of the current item: the last one acted on -- -1 if none"`
+	added    map[int]struct{} // pending adds since last TakeChange
+	removed  map[int]struct{} // pending removes since last TakeChange
+}
+
+// IsSelected returns true if the item at given index is selected
+func (sm *SelectionModel) IsSelected(idx int) bool {
+	_, has := sm.Selected[idx]
+	return has
+}
+
+// Len returns the number of selected items
+func (sm *SelectionModel) Len() int {
+	return len(sm.Selected)
+}
+
+// List returns the selected indexes, sorted ascending or descending
+func (sm *SelectionModel) List(descending bool) []int {
+	return sortedIdxs(sm.Selected, descending)
+}
+
+// Add adds the item at given index to the selection, returning true if it
+// was not already selected
+func (sm *SelectionModel) Add(idx int) bool {
+	if sm.IsSelected(idx) {
+		return false
+	}
+	if sm.Selected == nil {
+		sm.Selected = make(map[int]struct{})
+	}
+	sm.Selected[idx] = struct{}{}
+	if _, has := sm.removed[idx]; has {
+		delete(sm.removed, idx)
+	} else {
+		if sm.added == nil {
+			sm.added = make(map[int]struct{})
+		}
+		sm.added[idx] = struct{}{}
+	}
+	return true
+}
+
+// Remove removes the item at given index from the selection, returning
+// true if it was selected
+func (sm *SelectionModel) Remove(idx int) bool {
+	if !sm.IsSelected(idx) {
+		return false
+	}
+	delete(sm.Selected, idx)
+	if _, has := sm.added[idx]; has {
+		delete(sm.added, idx)
+	} else {
+		if sm.removed == nil {
+			sm.removed = make(map[int]struct{})
+		}
+		sm.removed[idx] = struct{}{}
+	}
+	return true
+}
+
+// Clear removes all items from the selection
+func (sm *SelectionModel) Clear() {
+	for idx := range sm.Selected {
+		sm.Remove(idx)
+	}
+}
+
+// SelectAll selects all of given number of items, unless the Mode is
+// SelectionSingle
+func (sm *SelectionModel) SelectAll(n int) {
+	if sm.Mode == SelectionSingle {
+		return
+	}
+	for idx := 0; idx < n; idx++ {
+		sm.Add(idx)
+	}
+}
+
+// SelectRange selects the items from index st to ed, inclusive, in either
+// order
+func (sm *SelectionModel) SelectRange(st, ed int) {
+	if st > ed {
+		st, ed = ed, st
+	}
+	for idx := st; idx <= ed; idx++ {
+		sm.Add(idx)
+	}
+}
+
+// Reset clears the selection and anchor without recording any change --
+// for when the items are replaced
+func (sm *SelectionModel) Reset() {
+	sm.Selected = make(map[int]struct{})
+	sm.Anchor = -1
+	sm.Current = -1
+	sm.added = nil
+	sm.removed = nil
+}
+
+// SelectAction updates the selection for an action on the item at given
+// index, using given selection mode from the modifier keys of the event,
+// as allowed by the Mode: SelectOne selects only the item, ExtendContinuous
+// selects only the range from the anchor to it, and ExtendOne toggles it.
+// Returns true if the item is selected after the action.
+func (sm *SelectionModel) SelectAction(idx int, mode mouse.SelectModes) bool {
+	if mode == mouse.NoSelect {
+		return sm.IsSelected(idx)
+	}
+	switch {
+	case sm.Mode == SelectionSingle && mode != mouse.Unselect && mode != mouse.UnselectQuiet:
+		mode = mouse.SelectOne
+	case sm.Mode == SelectionContiguous && mode == mouse.ExtendOne:
+		mode = mouse.ExtendContinuous
+	}
+	sm.Current = idx
+	switch mode {
+	case mouse.SelectOne:
+		for si := range sm.Selected {
+			if si != idx {
+				sm.Remove(si)
+			}
+		}
+		sm.Add(idx)
+		sm.Anchor = idx
+	case mouse.ExtendContinuous:
+		if sm.Anchor < 0 || (sm.Mode == SelectionContiguous && !sm.IsSelected(sm.Anchor)) {
+			sm.Anchor = idx
+		}
+		st, ed := sm.Anchor, idx
+		if st > ed {
+			st, ed = ed, st
+		}
+		for si := range sm.Selected {
+			if si < st || si > ed {
+				sm.Remove(si)
+			}
+		}
+		sm.SelectRange(st, ed)
+	case mouse.ExtendOne:
+		if !sm.Remove(idx) {
+			sm.Add(idx)
+		}
+		sm.Anchor = idx
+	case mouse.SelectQuiet:
+		sm.Add(idx)
+	case mouse.Unselect, mouse.UnselectQuiet:
+		sm.Remove(idx)
+	}
+	return sm.IsSelected(idx)
+}
+
+// PendingChange returns the changes since the last TakeChange, without
+// clearing them
+func (sm *SelectionModel) PendingChange() SelectionChange {
+	return SelectionChange{Added: sortedIdxs(sm.added, false), Removed: sortedIdxs(sm.removed, false), Current: sm.Current}
+}
+
+// TakeChange returns the changes since the last TakeChange, and clears them
+func (sm *SelectionModel) TakeChange() SelectionChange {
+	sc := sm.PendingChange()
+	sm.added = nil
+	sm.removed = nil
+	return sc
+}
+
+// sortedIdxs returns the keys of given index set, sorted
+func sortedIdxs(idxs map[int]struct{}, descending bool) []int {
+	if len(idxs) == 0 {
+		return nil
+	}
+	sl := make([]int, 0, len(idxs))
+	for idx := range idxs {
+		sl = append(sl, idx)
+	}
+	if descending {
+		sort.Sort(sort.Reverse(sort.IntSlice(sl)))
+	} else {
+		sort.Ints(sl)
+	}
+	return sl
+}
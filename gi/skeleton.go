@@ -0,0 +1,160 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+// Skeleton is a placeholder block with a shimmer that moves across it,
+// shown in place of content while it is loading -- blocks sized like the
+// eventual content (e.g., with AddNewSkeletonGrid for rows of a list)
+// give a preview of its layout.  Its size is set with the width and
+// height styles, and its color with the background-color style.
+type Skeleton struct {
+	WidgetBase
+}
+
+var KiT_Skeleton = kit.Types.AddType(&Skeleton{}, SkeletonProps)
+
+// AddNewSkeleton adds a new skeleton to given parent node, with given name.
+func AddNewSkeleton(parent ki.Ki, name string) *Skeleton {
+	return parent.AddNewChild(KiT_Skeleton, name).(*Skeleton)
+}
+
+// SkeletonProps are style properties for Skeleton
+var SkeletonProps = ki.Props{
+	"EnumType:Flag":    KiT_NodeFlags,
+	"background-color": "highlight-10",
+	"border-radius":    units.NewPx(4),
+	"margin":           units.NewPx(2),
+	"padding":          units.NewPx(0),
+	"width":            units.NewCh(12),
+	"height":           units.NewEm(1),
+}
+
+// SkeletonShimmerMSec is the number of milliseconds it takes for the
+// shimmer to move across a Skeleton
+var SkeletonShimmerMSec = 1500
+
+// SkeletonShimmerPct is the percent by which the color of the shimmer is
+// lighter (darker for dark colors) than the background of a Skeleton
+var SkeletonShimmerPct = float32(8)
+
+// AddNewSkeletonGrid adds a new grid layout of skeleton blocks to given
+// parent node, with given name, number of rows, and width of the block in
+// each column, for a preview of the layout of a list or table -- a
+// negative width value means that the block stretches to fill the column.
+func AddNewSkeletonGrid(parent ki.Ki, name string, rows int, widths []units.Value) *Layout {
+	ly := AddNewLayout(parent, name, LayoutGrid)
+	ConfigSkeletonGrid(ly, rows, widths)
+	return ly
+}
+
+// ConfigSkeletonGrid configures given grid layout to have skeleton blocks
+// in given number of rows, with the width of the block in each column --
+// a negative width value means that the block stretches to fill the
+// column.  Existing blocks are kept if they match.
+func ConfigSkeletonGrid(ly *Layout, rows int, widths []units.Value) {
+	ly.Lay = LayoutGrid
+	ly.SetProp("columns", len(widths))
+	ly.SetStretchMaxWidth()
+	config := kit.TypeAndNameList{}
+	for r := 0; r < rows; r++ {
+		for c := range widths {
+			config.Add(KiT_Skeleton, fmt.Sprintf("sk-%d-%d", r, c))
+		}
+	}
+	mods, updt := ly.ConfigChildren(config)
+	for i, k := range ly.Kids {
+		sk := k.(*Skeleton)
+		wd := widths[i%len(widths)]
+		if wd.Val < 0 {
+			sk.SetStretchMaxWidth()
+		} else {
+			sk.SetProp("width", wd)
+		}
+	}
+	if mods {
+		ly.UpdateEnd(updt)
+	}
+}
+
+// SkeletonPhase returns the current position of the shimmer moving across
+// all Skeletons, in the 0..1 range, so that they shimmer in unison
+func SkeletonPhase() float32 {
+	if SkeletonShimmerMSec <= 0 {
+		return 0
+	}
+	per := time.Duration(SkeletonShimmerMSec) * time.Millisecond
+	return float32(time.Since(RenderAnimStart)%per) / float32(per)
+}
+
+// RenderSkeletonBox renders a skeleton placeholder block with given
+// position, size, color and border radius, with the shimmer at the
+// current SkeletonPhase -- used by Skeleton, and other widgets that show
+// a skeleton while loading (e.g., Bitmap), which must call
+// StartRenderAnim to animate it.
+func RenderSkeletonBox(rs *girl.State, pos, sz mat32.Vec2, clr gist.Color, rad float32) {
+	pc := &rs.Paint
+	if sz.X <= 0 || sz.Y <= 0 {
+		return
+	}
+	pc.FillStyle.SetColor(clr)
+	pc.StrokeStyle.SetColor(nil)
+	if rad == 0 {
+		pc.DrawRectangle(rs, pos.X, pos.Y, sz.X, sz.Y)
+	} else {
+		pc.DrawRoundedRectangle(rs, pos.X, pos.Y, sz.X, sz.Y, rad)
+	}
+	pc.FillStrokeClear(rs)
+
+	// the shimmer is a band that fades in and out, in nstrip strips
+	const nstrip = 8
+	hi := clr.Samelight(SkeletonShimmerPct)
+	bw := mat32.Max(0.4*sz.X, 4*float32(nstrip))
+	sw := bw / nstrip
+	bx := pos.X - bw + SkeletonPhase()*(sz.X+bw)
+	st := pos.X + rad // not over the rounded corners
+	ed := pos.X + sz.X - rad
+	for i := 0; i < nstrip; i++ {
+		x0 := mat32.Max(bx+float32(i)*sw, st)
+		x1 := mat32.Min(bx+float32(i+1)*sw, ed)
+		if x1 <= x0 {
+			continue
+		}
+		pct := 100 * (1 - mat32.Abs(2*(float32(i)+0.5)/nstrip-1))
+		pc.FillBoxColor(rs, mat32.Vec2{x0, pos.Y}, mat32.Vec2{x1 - x0, sz.Y}, clr.Blend(pct, hi))
+	}
+}
+
+// RenderSkeleton renders the block within the margin
+func (sk *Skeleton) RenderSkeleton() {
+	rs, _, st := sk.RenderLock()
+	defer sk.RenderUnlock(rs)
+	pos := sk.LayState.Alloc.Pos.AddScalar(st.Layout.Margin.Dots)
+	sz := sk.LayState.Alloc.Size.AddScalar(-2.0 * st.Layout.Margin.Dots)
+	RenderSkeletonBox(rs, pos, sz, st.Font.BgColor.Color, st.Border.Radius.Dots)
+}
+
+func (sk *Skeleton) Render2D() {
+	if sk.FullReRenderIfNeeded() {
+		return
+	}
+	if sk.PushBounds() {
+		sk.RenderSkeleton()
+		sk.Render2DChildren()
+		sk.PopBounds()
+		StartRenderAnim(sk.This().(Node2D))
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package gi
+
+import (
+	"net"
+	"os"
+)
+
+// checkSocketOwner does nothing on Windows and other systems without unix
+// file ownership: the SocketDir is in the profile of the user, which only
+// the user has access to
+func checkSocketOwner(fi os.FileInfo) error {
+	return nil
+}
+
+// checkSocketDir does nothing on Windows and other systems without unix
+// file permissions, where directories report mode 0777 regardless of who
+// has access to them -- see checkSocketOwner
+func checkSocketDir(fi os.FileInfo) error {
+	return nil
+}
+
+// listenPrivate listens on given unix domain socket, in the SocketDir, which
+// only the current user has access to
+func listenPrivate(sock string) (net.Listener, error) {
+	return net.Listen("unix", sock)
+}
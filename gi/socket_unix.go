@@ -0,0 +1,55 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package gi
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// checkSocketOwner returns an error if given file, a socket or the SocketDir,
+// is not owned by the current user
+func checkSocketOwner(fi os.FileInfo) error {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if uid := os.Getuid(); int(st.Uid) != uid {
+		return fmt.Errorf("owned by user %d, not the current user %d", st.Uid, uid)
+	}
+	return nil
+}
+
+// checkSocketDir returns an error if given SocketDir is not owned by the
+// current user, or other users have access to it
+func checkSocketDir(fi os.FileInfo) error {
+	if err := checkSocketOwner(fi); err != nil {
+		return err
+	}
+	if perm := fi.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("accessible to other users, with mode %v", perm)
+	}
+	return nil
+}
+
+// listenPrivate listens on given unix domain socket, in the SocketDir, which
+// only the current user has access to, so other users can't connect to it
+// before it is made private to the user as well
+func listenPrivate(sock string) (net.Listener, error) {
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(sock, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
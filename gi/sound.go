@@ -0,0 +1,114 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"github.com/goki/gi/oswin"
+	"github.com/goki/ki/kit"
+)
+
+// SoundEvents are the standard events that can play a sound (an audio
+// cue), as mapped to sounds in the SoundPrefs
+type SoundEvents int32
+
+const (
+	// SoundNone plays no sound
+	SoundNone SoundEvents = iota
+
+	// SoundError is played when an error dialog is opened, e.g., with
+	// ErrorDialog, or any dialog with DlgOpts.Sound set to it
+	SoundError
+
+	// SoundCompleteAccept is played when a completion is accepted
+	SoundCompleteAccept
+
+	// SoundNotify is played for notifications, by apps calling PlaySound
+	SoundNotify
+
+	SoundEventsN
+)
+
+//go:generate stringer -type=SoundEvents
+
+var KiT_SoundEvents = kit.Enums.AddEnum(SoundEventsN, kit.NotBitFlag, nil)
+
+// SoundBell is the name of the sound that plays the system bell (Beep)
+const SoundBell = "bell"
+
+// SoundPlayer is the interface for playing the sounds of audio cues, e.g.,
+// from sound files, given the name of the sound as set in the SoundPrefs.
+type SoundPlayer interface {
+	// PlaySound plays given sound, returning any error -- it is called
+	// in a separate goroutine, so it can wait for the sound to finish
+	PlaySound(sound string) error
+}
+
+// TheSoundPlayer is the player of the sounds of audio cues, which apps set
+// to play their own sounds -- if nil, all sounds play the system bell
+var TheSoundPlayer SoundPlayer
+
+// SoundPrefs are the preferences for audio cues
+type SoundPrefs struct {
+	On     bool              `desc:"play sounds for standard events, such as error dialogs (audio cues)"`
+	Sounds map[string]string `desc:"sound played for each event, by the name of the event, e.g., SoundError -- the sound is played by the app's sound player, if any, except that bell plays the system bell, and empty plays no sound -- events that are not listed play their default sounds"`
+}
+
+// SoundDefaults are the default sounds for the SoundEvents
+var SoundDefaults = map[SoundEvents]string{
+	SoundError:          SoundBell,
+	SoundCompleteAccept: "",
+	SoundNotify:         SoundBell,
+}
+
+// Defaults are the defaults for SoundPrefs
+func (pf *SoundPrefs) Defaults() {
+	pf.On = true
+	pf.Sounds = make(map[string]string, len(SoundDefaults))
+	for ev, snd := range SoundDefaults {
+		pf.Sounds[ev.String()] = snd
+	}
+}
+
+// Sound returns the sound for given event
+func (pf *SoundPrefs) Sound(ev SoundEvents) string {
+	if snd, has := pf.Sounds[ev.String()]; has {
+		return snd
+	}
+	return SoundDefaults[ev]
+}
+
+// PlaySound plays the sound for given event, as set in the Prefs.Sounds,
+// if they are on
+func PlaySound(ev SoundEvents) {
+	if ev == SoundNone || !Prefs.Sounds.On {
+		return
+	}
+	PlaySoundName(Prefs.Sounds.Sound(ev))
+}
+
+// PlaySoundName plays the sound with given name, with TheSoundPlayer, or
+// the system bell if it is SoundBell or there is no player, or no sound
+// if it is empty
+func PlaySoundName(snd string) {
+	switch {
+	case snd == "":
+		return
+	case snd == SoundBell || TheSoundPlayer == nil:
+		Beep()
+		return
+	}
+	go func() {
+		if err := TheSoundPlayer.PlaySound(snd); err != nil {
+			Logf(LogWarn, "gi.PlaySoundName", "%v", err)
+		}
+	}()
+}
+
+// Beep plays the system alert sound (the bell)
+func Beep() {
+	if oswin.TheApp != nil {
+		oswin.TheApp.Beep()
+	}
+}
@@ -6,7 +6,6 @@ package gi
 
 import (
 	"image"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,7 +27,7 @@ func InitSpell() error {
 	if err != nil {
 		err = spell.OpenDefault()
 		if err != nil {
-			log.Println(err)
+			Logf(LogError, "gi.InitSpell", "%v", err)
 		}
 	}
 	return nil
@@ -40,7 +39,7 @@ func OpenSpellModel() error {
 	openpath := filepath.Join(pdir, "spell_en_us.json")
 	err := spell.Open(openpath)
 	if err != nil {
-		log.Printf("ERROR opening spelling dictionary: %s  error: %s\n", openpath, err)
+		Logf(LogError, "gi.InitSpell", "opening spelling dictionary: %s  error: %s", openpath, err)
 	}
 	return err
 }
@@ -49,21 +48,21 @@ func OpenSpellModel() error {
 func NewSpellModelFromText() error {
 	bigdatapath, err := dirs.GoSrcDir("github.com/goki/pi/spell")
 	if err != nil {
-		log.Printf("Error getting path to corpus directory: %v.\n", err)
+		Logf(LogError, "gi.NewSpellModelFromText", "getting path to corpus directory: %v", err)
 		return err
 	}
 
 	bigdatafile := filepath.Join(bigdatapath, "big.txt")
 	file, err := os.Open(bigdatafile)
 	if err != nil {
-		log.Printf("Could not open corpus file: %v. This file is used to create the spelling model.\n", err)
+		Logf(LogError, "gi.NewSpellModelFromText", "could not open corpus file: %v. This file is used to create the spelling model.", err)
 		PromptDialog(nil, DlgOpts{Title: "Corpus File Not Found", Prompt: "You can build a spelling model to check against by clicking the \"Train\" button and selecting text files to train on."}, AddOk, NoCancel, nil, nil)
 		return err
 	}
 
 	err = spell.Train(*file, true) // true - create a NEW spelling model
 	if err != nil {
-		log.Printf("Failed building model from corpus file: %v.\n", err)
+		Logf(LogError, "gi.NewSpellModelFromText", "failed building model from corpus file: %v", err)
 		return err
 	}
 
@@ -77,13 +76,13 @@ func AddToSpellModel(filepath string) error {
 	InitSpell() // make sure model is initialized
 	file, err := os.Open(filepath)
 	if err != nil {
-		log.Printf("Could not open text file selected for training: %v.\n", err)
+		Logf(LogError, "gi.AddToSpellModel", "could not open text file selected for training: %v", err)
 		return err
 	}
 
 	err = spell.Train(*file, false) // false - append rather than create new
 	if err != nil {
-		log.Printf("Failed appending to spell model: %v.\n", err)
+		Logf(LogError, "gi.AddToSpellModel", "failed appending to spell model: %v", err)
 		return err
 	}
 	return nil
@@ -95,7 +94,7 @@ func SaveSpellModel() error {
 	path := filepath.Join(pdir, "spell_en_us.json")
 	err := spell.Save(path)
 	if err != nil {
-		log.Printf("Could not save spelling model to file: %v.\n", err)
+		Logf(LogError, "gi.SaveSpellModel", "could not save spelling model to file: %v", err)
 	}
 	return err
 }
@@ -259,7 +258,7 @@ func (sc *Spell) IsLastLearned(wrd string) bool {
 // UnLearnLast unlearns the last learned word -- in case accidental
 func (sc *Spell) UnLearnLast() {
 	if sc.LastLearned == "" {
-		log.Println("spell.UnLearnLast: no last learned word")
+		Logf(LogWarn, "gi.Spell", "UnLearnLast: no last learned word")
 		return
 	}
 	lword := sc.LastLearned
@@ -7,7 +7,6 @@ package gi
 import (
 	"fmt"
 	"image"
-	"log"
 	"strconv"
 
 	"github.com/goki/gi/gist"
@@ -127,7 +126,7 @@ func (sb *SpinBox) SetMinMax(hasMin bool, min float32, hasMax bool, max float32)
 	sb.HasMax = hasMax
 	sb.Max = max
 	if sb.Max < sb.Min {
-		log.Printf("gi.SpinBox SetMinMax: max was less than min -- disabling limits\n")
+		Logf(LogWarn, "gi.SpinBox", "SetMinMax: max was less than min -- disabling limits")
 		sb.HasMax = false
 		sb.HasMin = false
 	}
@@ -300,7 +299,7 @@ func (sb *SpinBox) StringToVal(str string) (float32, error) {
 		fval = float32(fv)
 	}
 	if err != nil {
-		log.Println(err)
+		Logf(LogWarn, "gi.SpinBox", "StringToVal: %v", err)
 	}
 	return fval, err
 }
@@ -0,0 +1,207 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"sync"
+
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/units"
+)
+
+// SplashScreen shows a small undecorated window with a logo, title, status
+// and progress bar immediately at startup, while the heavy initialization
+// of the app (fonts, and app-specific Tasks such as loading data or
+// preferences) runs concurrently in background goroutines, and then swaps
+// to the main window.  Typical usage, in the function passed to
+// gimain.Main:
+//
+//	ss := gi.NewSplashScreen("My App", "my-logo")
+//	ss.AddTask("Loading data", loadData)
+//	ss.Run(func() *gi.Window { return configMainWindow() })
+type SplashScreen struct {
+	Title     string       `desc:"title of the app, shown below the logo"`
+	Logo      IconName     `desc:"icon shown as the logo"`
+	LogoImage image.Image  `desc:"image shown as the logo instead of the Logo icon, if set"`
+	Size      image.Point  `desc:"size of the window, in standardized 96 dpi pixels"`
+	Tasks     []SplashTask `desc:"initialization tasks, run concurrently in background goroutines by RunTasks"`
+	Win       *Window      `json:"-" xml:"-" desc:"the splash window, while it is open"`
+	Status    *Label       `json:"-" xml:"-" desc:"label showing the status"`
+	Progress  *ProgressBar `json:"-" xml:"-" desc:"progress bar showing the fraction of the Tasks done"`
+	running   []string     // names of the tasks that are running
+	mu        sync.Mutex   // protects running and Win
+}
+
+// SplashTask is an initialization task of a SplashScreen
+type SplashTask struct {
+	Name string `desc:"description of the task, shown as the status while it runs"`
+	Func func() `desc:"function that does the task"`
+}
+
+// SplashWinName is the name of the window of the SplashScreen
+var SplashWinName = "gogi-splash"
+
+// NewSplashScreen returns a new SplashScreen with given title and logo
+// icon, and the standard task of loading the list of available fonts.
+func NewSplashScreen(title string, logo IconName) *SplashScreen {
+	ss := &SplashScreen{Title: title, Logo: logo, Size: image.Point{480, 280}}
+	ss.AddTask("Loading fonts", girl.FontLibrary.Init)
+	return ss
+}
+
+// AddTask adds an initialization task with given description and function,
+// which must be safe to run concurrently with the other tasks
+func (ss *SplashScreen) AddTask(name string, fun func()) {
+	ss.Tasks = append(ss.Tasks, SplashTask{Name: name, Func: fun})
+}
+
+// Open opens the splash window, centered on the primary screen, and starts
+// its event loop in a separate goroutine
+func (ss *SplashScreen) Open() *Window {
+	Init()
+	opts := &oswin.NewWindowOptions{Title: ss.Title, Size: ss.Size, StdPixels: true}
+	opts.SetTool() // undecorated
+	if sc := oswin.TheApp.Screen(0); sc != nil {
+		// approximate, as positions are in window manager units
+		opts.Pos = sc.Geometry.Min.Add(sc.Geometry.Size().Sub(ss.Size).Div(2))
+	}
+	win := NewWindow(SplashWinName, ss.Title, opts)
+	if win == nil {
+		return nil
+	}
+	AllWindows.Add(win)
+	vp := NewViewport2D(ss.Size.X, ss.Size.Y)
+	vp.SetName("WinVp")
+	vp.SetProp("color", &Prefs.Colors.Font)
+	win.AddChild(vp)
+	win.Viewport = vp
+	vp.Win = win
+	updt := vp.UpdateStart()
+	fr := AddNewFrame(vp, "splash", LayoutVert)
+	fr.SetStretchMax()
+	fr.SetProp("background-color", &Prefs.Colors.Background)
+	fr.SetProp("border-width", units.NewPx(1))
+	fr.SetProp("border-color", &Prefs.Colors.Border)
+	fr.SetProp("padding", units.NewPx(16))
+	fr.SetProp("spacing", units.NewPx(8))
+	win.MasterVLay = &fr.Layout
+
+	AddNewStretch(fr, "str-top")
+	if ss.LogoImage != nil {
+		bm := AddNewBitmap(fr, "logo")
+		bm.SetImage(ss.LogoImage, 0, 0)
+		bm.LayoutToImgSize()
+		bm.SetProp("horizontal-align", gist.AlignCenter)
+	} else if !ss.Logo.IsNil() {
+		ic := AddNewIcon(fr, "logo", string(ss.Logo))
+		ic.SetProp("width", units.NewPx(96))
+		ic.SetProp("height", units.NewPx(96))
+		ic.SetProp("horizontal-align", gist.AlignCenter)
+	}
+	ttl := AddNewLabel(fr, "title", ss.Title)
+	ttl.SetProp("font-size", "x-large")
+	ttl.SetProp("horizontal-align", gist.AlignCenter)
+	AddNewStretch(fr, "str-bot")
+	ss.Status = AddNewLabel(fr, "status", "Starting...")
+	ss.Status.SetProp("horizontal-align", gist.AlignCenter)
+	ss.Status.Redrawable = true
+	ss.Progress = AddNewProgressBar(fr, "progress")
+	ss.Progress.SetStretchMaxWidth()
+	ss.Progress.SetProp("height", units.NewEm(0.5))
+	vp.UpdateEndNoSig(updt)
+
+	ss.mu.Lock()
+	ss.Win = win
+	ss.mu.Unlock()
+	WinNewCloseStamp()
+	win.GoStartEventLoop()
+	return win
+}
+
+// SetStatus shows given status message in the window
+func (ss *SplashScreen) SetStatus(msg string) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.Win == nil || ss.Status == nil {
+		return
+	}
+	ss.Status.SetText(msg)
+}
+
+// RunTasks runs all the Tasks concurrently, each in its own goroutine,
+// showing the status and progress in the window (if open), and returns
+// when all of them are done
+func (ss *SplashScreen) RunTasks() {
+	if ss.Progress != nil {
+		ss.Progress.Start(len(ss.Tasks) + 1)
+	}
+	var wg sync.WaitGroup
+	ss.mu.Lock()
+	for _, tk := range ss.Tasks {
+		ss.running = append(ss.running, tk.Name)
+	}
+	ss.mu.Unlock()
+	if len(ss.Tasks) > 0 {
+		ss.SetStatus(ss.Tasks[0].Name + "...")
+	}
+	for _, tk := range ss.Tasks {
+		wg.Add(1)
+		go func(tk SplashTask) {
+			defer wg.Done()
+			tk.Func()
+			ss.taskDone(tk.Name)
+		}(tk)
+	}
+	wg.Wait()
+}
+
+// taskDone records that the task with given name is done, updating the
+// status and progress
+func (ss *SplashScreen) taskDone(name string) {
+	ss.mu.Lock()
+	for i, nm := range ss.running {
+		if nm == name {
+			ss.running = append(ss.running[:i], ss.running[i+1:]...)
+			break
+		}
+	}
+	msg := "Starting..."
+	if len(ss.running) > 0 {
+		msg = ss.running[0] + "..."
+	}
+	ss.mu.Unlock()
+	if ss.Progress != nil {
+		ss.Progress.ProgStep()
+	}
+	ss.SetStatus(msg)
+}
+
+// Close closes the splash window, if it is open
+func (ss *SplashScreen) Close() {
+	ss.mu.Lock()
+	win := ss.Win
+	ss.Win = nil
+	ss.mu.Unlock()
+	if win != nil {
+		win.Close()
+	}
+}
+
+// Run opens the splash window, runs all the Tasks, and then calls given
+// function to create and configure the main window, closes the splash
+// window, and starts the event loop of the main window -- it does not
+// return until the main window is closed, as with Window.StartEventLoop.
+func (ss *SplashScreen) Run(mainWin func() *Window) {
+	ss.Open()
+	ss.RunTasks()
+	win := mainWin()
+	ss.Close()
+	if win != nil {
+		win.StartEventLoop()
+	}
+}
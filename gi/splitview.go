@@ -5,6 +5,7 @@
 package gi
 
 import (
+	"fmt"
 	"image"
 	"strconv"
 	"strings"
@@ -35,10 +36,11 @@ import (
 // displayed within each region.
 type SplitView struct {
 	PartsWidgetBase
-	HandleSize  units.Value `xml:"handle-size" desc:"size of the handle region in the middle of each split region, where the splitter can be dragged -- other-dimension size is 2x of this"`
-	Splits      []float32   `desc:"proportion (0-1 normalized, enforced) of space allocated to each element -- can enter 0 to collapse a given element"`
-	SavedSplits []float32   `desc:"A saved version of the splits which can be restored -- for dynamic collapse / expand operations"`
-	Dim         mat32.Dims  `desc:"dimension along which to split the space"`
+	HandleSize      units.Value `xml:"handle-size" desc:"size of the handle region in the middle of each split region, where the splitter can be dragged -- other-dimension size is 2x of this"`
+	TouchHandleSize units.Value `xml:"touch-handle-size" desc:"minimum size of the region where the splitters can be dragged, once touch input has been received in the window -- larger than the handle size, for fingers"`
+	Splits          []float32   `desc:"proportion (0-1 normalized, enforced) of space allocated to each element -- can enter 0 to collapse a given element"`
+	SavedSplits     []float32   `desc:"A saved version of the splits which can be restored -- for dynamic collapse / expand operations"`
+	Dim             mat32.Dims  `desc:"dimension along which to split the space"`
 }
 
 var KiT_SplitView = kit.Types.AddType(&SplitView{}, SplitViewProps)
@@ -52,18 +54,20 @@ func (sv *SplitView) CopyFieldsFrom(frm any) {
 	fr := frm.(*SplitView)
 	sv.PartsWidgetBase.CopyFieldsFrom(&fr.PartsWidgetBase)
 	sv.HandleSize = fr.HandleSize
+	sv.TouchHandleSize = fr.TouchHandleSize
 	mat32.CopyFloat32s(&sv.Splits, fr.Splits)
 	mat32.CopyFloat32s(&sv.SavedSplits, fr.SavedSplits)
 	sv.Dim = fr.Dim
 }
 
 var SplitViewProps = ki.Props{
-	"EnumType:Flag": KiT_NodeFlags,
-	"handle-size":   units.NewPx(10),
-	"max-width":     -1.0,
-	"max-height":    -1.0,
-	"margin":        0,
-	"padding":       0,
+	"EnumType:Flag":     KiT_NodeFlags,
+	"handle-size":       units.NewPx(10),
+	"touch-handle-size": units.NewPt(24),
+	"max-width":         -1.0,
+	"max-height":        -1.0,
+	"margin":            0,
+	"padding":           0,
 }
 
 // UpdateSplits updates the splits to be same length as number of children,
@@ -276,12 +280,14 @@ func (sv *SplitView) ConfigSplitters() {
 		sp.Snap = false
 		sp.SetProp("thumb-size", sv.HandleSize)
 		sp.ThumbSize = sv.HandleSize
+		sp.UpdateTooltip()
 		if mods {
 			sp.SliderSig.ConnectOnly(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
 				if sig == int64(SliderReleased) {
 					spr, _ := recv.Embed(KiT_SplitView).(*SplitView)
 					spl := send.(*Splitter)
 					spr.SetSplitAction(spl.SplitterNo, spl.Value)
+					spl.UpdateTooltip()
 				}
 			})
 		}
@@ -340,6 +346,8 @@ func (sv *SplitView) StyleSplitView() {
 	sv.LayState.SetFromStyle(&sv.Sty.Layout) // also does reset
 	sv.HandleSize.SetFmInheritProp("handle-size", sv.This(), ki.NoInherit, ki.TypeProps)
 	sv.HandleSize.ToDots(&sv.Sty.UnContext)
+	sv.TouchHandleSize.SetFmInheritProp("touch-handle-size", sv.This(), ki.NoInherit, ki.TypeProps)
+	sv.TouchHandleSize.ToDots(&sv.Sty.UnContext)
 }
 
 func (sv *SplitView) Style2D() {
@@ -530,7 +538,7 @@ func (sr *Splitter) ConfigPartsIfNeeded(render bool) {
 }
 
 func (sr *Splitter) Style2D() {
-	sr.ClearFlag(int(CanFocus))
+	sr.SetCanFocusIfActive()
 	sr.StyleSlider()
 	sr.StyMu.Lock()
 	sr.LayState.SetFromStyle(&sr.Sty.Layout) // also does reset
@@ -577,6 +585,9 @@ func (sr *Splitter) UpdateSplitterPos() {
 	sz := handsz
 	if !sr.IsDragging() {
 		sz += 2 * spc
+		if sv := sr.SplitView(); sv != nil && sr.IsTouchInput() {
+			sz = mat32.Max(sz, sv.TouchHandleSize.Dots)
+		}
 	}
 	pos := off + int(sr.Pos-0.5*sz)
 	mxpos := off + int(sr.Pos+0.5*sz)
@@ -635,14 +646,7 @@ func (sr *Splitter) MouseEvent() {
 						srr.SliderPress(float32(ed.Y) - spc)
 					}
 				} else if me.Action == mouse.DoubleClick {
-					sv := srr.SplitView()
-					if sv != nil {
-						if sv.IsCollapsed(srr.SplitterNo) {
-							sv.RestoreSplits()
-						} else {
-							sv.CollapseChild(true, srr.SplitterNo)
-						}
-					}
+					srr.ToggleCollapse()
 				} else {
 					srr.SliderRelease()
 				}
@@ -669,6 +673,117 @@ func (sr *Splitter) MouseScrollEvent() {
 	// })
 }
 
+// IsTouchInput returns true if touch input has been received in the
+// window, in which case the region where the splitter can be dragged is
+// at least the TouchHandleSize of the SplitView
+func (sr *Splitter) IsTouchInput() bool {
+	win := sr.ParentWindow()
+	return win != nil && win.IsTouchInput()
+}
+
+// ToggleCollapse collapses the element before the splitter, saving the
+// splits, or restores the saved splits if it is already collapsed --
+// triggered by double-click or the Enter key
+func (sr *Splitter) ToggleCollapse() {
+	sv := sr.SplitView()
+	if sv == nil {
+		return
+	}
+	if sv.IsCollapsed(sr.SplitterNo) {
+		sv.RestoreSplits()
+	} else {
+		sv.CollapseChild(true, sr.SplitterNo)
+	}
+	sr.UpdateTooltip()
+}
+
+// SetSplitValueAction moves the splitter to given position (0-1), resizing
+// the elements on either side of it, as when it is dragged there
+func (sr *Splitter) SetSplitValueAction(val float32) {
+	sv := sr.SplitView()
+	if sv == nil {
+		return
+	}
+	val = mat32.Clamp(val, sr.Min, sr.Max)
+	if val == sr.Value {
+		return
+	}
+	updt := sv.UpdateStart()
+	sr.SetValue(val)
+	sv.SetSplitAction(sr.SplitterNo, val)
+	sv.UpdateEnd(updt)
+	sr.UpdateTooltip()
+}
+
+// StateText returns a description of the state of the splitter, for
+// accessibility, e.g., "Splitter 1 of 2: 35%", or "Splitter 1 of 2:
+// collapsed" if the element before it is collapsed -- it is the Tooltip
+// of the splitter, updated whenever it is moved
+func (sr *Splitter) StateText() string {
+	sv := sr.SplitView()
+	if sv == nil {
+		return "Splitter"
+	}
+	str := "Splitter " + strconv.Itoa(sr.SplitterNo+1) + " of " + strconv.Itoa(len(sv.Kids)-1) + ": "
+	if sv.IsCollapsed(sr.SplitterNo) {
+		return str + "collapsed"
+	}
+	pct := 0
+	for i := 0; i <= sr.SplitterNo && i < len(sv.Splits); i++ {
+		pct += int(mat32.Round(100 * sv.Splits[i]))
+	}
+	return str + strconv.Itoa(pct) + "%"
+}
+
+// UpdateTooltip updates the Tooltip of the splitter to its StateText,
+// which announces its new state to accessibility tools
+func (sr *Splitter) UpdateTooltip() {
+	sr.Tooltip = sr.StateText()
+}
+
+// KeyInput moves the splitter by its Step for the arrow keys, and by its
+// PageStep for PageUp / PageDown, to either end for Home / End, and
+// toggles the collapse of the element before it for Enter
+func (sr *Splitter) KeyInput(kt *key.ChordEvent) {
+	if KeyEventTrace {
+		fmt.Printf("Splitter KeyInput: %v\n", sr.Path())
+	}
+	kf := KeyFun(kt.Chord())
+	switch kf {
+	case KeyFunMoveUp, KeyFunMoveLeft:
+		sr.SetSplitValueAction(sr.Value - sr.Step)
+		kt.SetProcessed()
+	case KeyFunMoveDown, KeyFunMoveRight:
+		sr.SetSplitValueAction(sr.Value + sr.Step)
+		kt.SetProcessed()
+	case KeyFunPageUp:
+		sr.SetSplitValueAction(sr.Value - sr.PageStep)
+		kt.SetProcessed()
+	case KeyFunPageDown:
+		sr.SetSplitValueAction(sr.Value + sr.PageStep)
+		kt.SetProcessed()
+	case KeyFunHome:
+		sr.SetSplitValueAction(sr.Min)
+		kt.SetProcessed()
+	case KeyFunEnd:
+		sr.SetSplitValueAction(sr.Max)
+		kt.SetProcessed()
+	case KeyFunEnter:
+		sr.ToggleCollapse()
+		kt.SetProcessed()
+	}
+}
+
+func (sr *Splitter) KeyChordEvent() {
+	sr.ConnectEvent(oswin.KeyChordEvent, RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		srr := recv.Embed(KiT_Splitter).(*Splitter)
+		if srr.IsInactive() {
+			return
+		}
+		srr.KeyInput(d.(*key.ChordEvent))
+	})
+}
+
 func (sr *Splitter) SplitterEvents() {
 	sr.MouseDragEvent()
 	sr.MouseEvent()
@@ -0,0 +1,163 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/gi/oswin"
+)
+
+// StartTime is the time that the gi package was initialized, which is
+// close to the start of the app -- StartupTimes are measured from it
+var StartTime = time.Now()
+
+// StartupTime is the timing of one step of the startup of the app
+type StartupTime struct {
+	Name  string        `desc:"name of the step"`
+	Start time.Duration `desc:"time since StartTime that the step started"`
+	Dur   time.Duration `desc:"duration of the step"`
+}
+
+// StartupTimes are the timings of the steps of the startup of the app,
+// recorded by StartupStep, in the order they finished: loading preferences
+// and fonts, preloading icons, and the first frame of each window, until
+// that of the first main window -- see StartupReport
+var StartupTimes []StartupTime
+
+// startupMu protects StartupTimes and startupDone
+var startupMu sync.Mutex
+
+// startupDone is set after the first frame of the first main window
+var startupDone bool
+
+// StartupStep starts timing a step of the startup with given name, and
+// returns the function to call when it is done, e.g.:
+//
+//	defer gi.StartupStep("Loading data")()
+//
+// Only the first time each step is done is recorded, and none are after
+// the first frame of the first main window.
+func StartupStep(name string) func() {
+	st := time.Now()
+	return func() {
+		addStartupTime(name, st.Sub(StartTime), time.Since(st))
+	}
+}
+
+// addStartupTime adds a StartupTime, if startup is not done and a step
+// with the same name has not been recorded
+func addStartupTime(name string, start, dur time.Duration) {
+	startupMu.Lock()
+	defer startupMu.Unlock()
+	if startupDone {
+		return
+	}
+	for _, stt := range StartupTimes {
+		if stt.Name == name {
+			return
+		}
+	}
+	StartupTimes = append(StartupTimes, StartupTime{Name: name, Start: start, Dur: dur})
+}
+
+// startupFrame records the first frame of given window as a startup step,
+// which ends the startup if it is a main window
+func startupFrame(w *Window) {
+	startupMu.Lock()
+	done := startupDone
+	startupMu.Unlock()
+	if done {
+		return
+	}
+	addStartupTime("First frame: "+w.Nm, 0, time.Since(StartTime))
+	if _, isMain := MainWindows.FindName(w.Nm); isMain {
+		startupMu.Lock()
+		startupDone = true
+		startupMu.Unlock()
+	}
+}
+
+// StartupReport returns a report of the StartupTimes, one step per line
+func StartupReport() string {
+	startupMu.Lock()
+	defer startupMu.Unlock()
+	var sb strings.Builder
+	for _, stt := range StartupTimes {
+		fmt.Fprintf(&sb, "%-40s start: %8.1f ms  dur: %8.1f ms\n", stt.Name, float64(stt.Start)/float64(time.Millisecond), float64(stt.Dur)/float64(time.Millisecond))
+	}
+	return sb.String()
+}
+
+// PreloadOn determines whether Init starts preloading, in a background
+// goroutine, the resources that are otherwise loaded on demand during the
+// first render: parsing all the icons, and opening the IconCache saved at
+// the end of the last run (if IconCacheSaveOn)
+var PreloadOn = true
+
+// IconCacheSaveOn determines whether the IconCache is saved in the app
+// prefs directory when the last main window closes, and opened by Preload
+// at the next startup, so that the icons are not rendered again -- the
+// cache is only used for the same Version and logical DPI
+var IconCacheSaveOn = true
+
+// IconCacheDirName is the name of the directory in the app prefs directory
+// where the IconCache is saved
+var IconCacheDirName = "icon_cache"
+
+// FontCacheFileName is the name of the file in the GoGi prefs directory
+// where the results of scanning the font paths are saved
+// (girl.FontCacheFile)
+var FontCacheFileName = "font_cache.json"
+
+// IconPreloader is an optional interface for the TheIconMgr, to open all
+// the icons ahead of their first use -- implemented by svg.IconMgr
+type IconPreloader interface {
+	PreloadIcons()
+}
+
+// iconCacheKey returns the key for the saved IconCache: the Version
+// and logical DPI of the first screen
+func iconCacheKey() string {
+	dpi := float32(0)
+	if sc := oswin.TheApp.Screen(0); sc != nil {
+		dpi = sc.LogicalDPI
+	}
+	return fmt.Sprintf("%s-dpi%g", Version, dpi)
+}
+
+// Preload preloads the icons and opens the saved IconCache, in a background
+// goroutine -- called by Init if PreloadOn
+func Preload() {
+	go func() {
+		if IconCacheSaveOn && IconCacheOn {
+			done := StartupStep("Opening icon cache")
+			dir := filepath.Join(oswin.TheApp.AppPrefsDir(), IconCacheDirName)
+			IconCache.OpenCache(dir, iconCacheKey()) // ok if not there
+			done()
+		}
+		if ip, ok := TheIconMgr.(IconPreloader); ok {
+			done := StartupStep("Preloading icons")
+			ip.PreloadIcons()
+			done()
+		}
+	}()
+}
+
+// SaveIconCache saves the IconCache in the app prefs directory, if
+// IconCacheSaveOn -- called when the last main window closes
+func SaveIconCache() {
+	if !IconCacheSaveOn || !IconCacheOn {
+		return
+	}
+	dir := filepath.Join(oswin.TheApp.AppPrefsDir(), IconCacheDirName)
+	if err := IconCache.SaveCache(dir, iconCacheKey()); err != nil {
+		Logf(LogWarn, "gi.SaveIconCache", "%v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// StickyShadowHeight is the height in dots of the shadow drawn below a
+// sticky child of a layout while it is stuck (see SetSticky)
+var StickyShadowHeight = 4
+
+// SetSticky sets the "sticky" property, which makes this widget stay
+// pinned at the top of the visible region of its parent Layout while the
+// rest of that layout scrolls underneath it, through scrolling of the
+// layout itself or of any layout it is in -- it is only pinned while some
+// of the parent layout is still visible below it, so for a vertical list
+// of sections, each a vertical Layout with a sticky header as its first
+// child, the header of the section at the top stays in view until the
+// next section pushes it out.  The widget is rendered on top of the other
+// children while stuck, with a shadow below it, so it should have an
+// opaque background.
+func (wb *WidgetBase) SetSticky(sticky bool) {
+	wb.SetProp("sticky", sticky)
+}
+
+// IsSticky returns true if the "sticky" property is set (see SetSticky)
+func (wb *WidgetBase) IsSticky() bool {
+	if pv, ok := wb.PropInherit("sticky", ki.NoInherit, ki.TypeProps); ok {
+		st, _ := kit.ToBool(pv)
+		return st
+	}
+	return false
+}
+
+// StickChild pins given child at the top of the visible region of the
+// layout, if it is sticky and has been scrolled above it, moving it down
+// by as much as needed, but not below the end of the layout, and records
+// it as Stuck -- called after moving it with given delta and parent bbox
+// in Move2DChildren.
+func (ly *Layout) StickChild(nii Node2D, delta image.Point, cbb image.Rectangle) {
+	wb := nii.AsWidget()
+	if wb == nil || !wb.IsSticky() {
+		return
+	}
+	wb.BBoxMu.RLock()
+	obb := wb.ObjBBox
+	wb.BBoxMu.RUnlock()
+	ly.BBoxMu.RLock()
+	lbb := ly.ObjBBox
+	ly.BBoxMu.RUnlock()
+	d := cbb.Min.Y - obb.Min.Y
+	d = ints.MinInt(d, lbb.Max.Y-obb.Max.Y) // stay within the layout
+	if d <= 0 {
+		return
+	}
+	nii.Move2D(delta.Add(image.Point{0, d}), cbb)
+	ly.Stuck = append(ly.Stuck, nii)
+}
+
+// IsStuck returns true if given child is currently pinned by StickChild
+func (ly *Layout) IsStuck(kid ki.Ki) bool {
+	for _, sk := range ly.Stuck {
+		if sk.This() == kid {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderStuck renders the Stuck children on top of the others, each
+// with a shadow below it of StickyShadowHeight, in the shadow color
+func (ly *Layout) RenderStuck() {
+	for _, sk := range ly.Stuck {
+		if sk.This() == nil {
+			continue
+		}
+		sk.Render2D()
+		sb := sk.AsNode2D()
+		sb.BBoxMu.RLock()
+		vbb := sb.VpBBox
+		sb.BBoxMu.RUnlock()
+		if vbb.Empty() {
+			continue
+		}
+		rs, _, _ := ly.RenderLock()
+		clr := Prefs.Colors.Shadow
+		for i := 0; i < StickyShadowHeight; i++ {
+			r := image.Rect(vbb.Min.X, vbb.Max.Y+i, vbb.Max.X, vbb.Max.Y+i+1).Intersect(rs.Bounds)
+			sc := color.NRGBA{clr.R, clr.G, clr.B, uint8(int(clr.A) * (StickyShadowHeight - i) / (StickyShadowHeight + 1))}
+			draw.Draw(rs.Image, r, image.NewUniform(sc), image.Point{}, draw.Over)
+		}
+		ly.RenderUnlock(rs)
+	}
+}
@@ -6,7 +6,6 @@ package gi
 
 import (
 	"fmt"
-	"log"
 	"reflect"
 	"sync"
 
@@ -199,7 +198,7 @@ func (tv *TabView) TabAtIndex(idx int) (Node2D, *TabButton, bool) {
 	tb := tv.Tabs()
 	sz := len(*fr.Children())
 	if idx < 0 || idx >= sz {
-		log.Printf("giv.TabView: index %v out of range for number of tabs: %v\n", idx, sz)
+		Logf(LogWarn, "gi.TabView", "index %v out of range for number of tabs: %v", idx, sz)
 		return nil, nil, false
 	}
 	tab := tb.Child(idx).Embed(KiT_TabButton).(*TabButton)
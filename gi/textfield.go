@@ -33,9 +33,18 @@ const force = true
 const dontForce = false
 
 // CursorBlinkMSec is number of milliseconds that cursor blinks on
-// and off -- set to 0 to disable blinking
+// and off -- set to 0 to disable blinking -- set from Prefs.Params
 var CursorBlinkMSec = 500
 
+// TextFieldLabelAnimMSec is number of milliseconds that the floating Label of
+// a TextField takes to move between its resting and floated positions --
+// set to 0 to disable animation
+var TextFieldLabelAnimMSec = 150
+
+// TextFieldLabelScale is the size of the font of the floated Label, and the
+// HelperText and ErrorText of a TextField, relative to the text font
+var TextFieldLabelScale = float32(0.75)
+
 ////////////////////////////////////////////////////////////////////////////////////////
 // TextField
 
@@ -44,7 +53,13 @@ type TextField struct {
 	PartsWidgetBase
 	Txt          string                       `json:"-" xml:"text" desc:"the last saved value of the text string being edited"`
 	Placeholder  string                       `json:"-" xml:"placeholder" desc:"text that is displayed when the field is empty, in a lower-contrast manner"`
+	Label        string                       `xml:"label" desc:"optional Material-style floating label, shown in the place of the placeholder when the field is empty and not focused, and floated above the text otherwise"`
+	HelperText   string                       `xml:"helper-text" desc:"optional helper text shown in a smaller font below the field"`
+	ErrorText    string                       `xml:"error-text" desc:"error text shown below the field in place of the HelperText, in the ErrorColor, which also colors the border and Label -- e.g., set from validation -- see SetErrorText"`
+	ErrorColor   gist.Color                   `xml:"error-color" desc:"color of the ErrorText, set from error-color property (inherited)"`
 	ClearAct     bool                         `xml:"clear-act" desc:"add a clear action x at right side of edit, set from clear-act property (inherited) -- on by default"`
+	LeadIcon     IconName                     `xml:"lead-icon" desc:"optional icon shown at the left side of the text, e.g., search for a SearchField -- clicking on it emits TextFieldLeadIconClicked"`
+	TrailIcon    IconName                     `xml:"trail-icon" desc:"optional icon shown at the right side of the text, before the clear action, e.g., a calendar for opening a date picker -- clicking on it emits TextFieldTrailIconClicked"`
 	CursorWidth  units.Value                  `xml:"cursor-width" desc:"width of cursor -- set from cursor-width property (inherited)"`
 	Edited       bool                         `json:"-" xml:"-" desc:"true if the text has been edited relative to the original"`
 	EditTxt      []rune                       `json:"-" xml:"-" desc:"the live text string being edited, with latest modifications -- encoded as runes"`
@@ -63,10 +78,14 @@ type TextField struct {
 	RenderVis    girl.Text                    `copy:"-" json:"-" xml:"-" desc:"render version of just visible text"`
 	StateStyles  [TextFieldStatesN]gist.Style `copy:"-" json:"-" xml:"-" desc:"normal style and focus style"`
 	FontHeight   float32                      `copy:"-" json:"-" xml:"-" desc:"font height, cached during styling"`
+	LabelPos     float32                      `copy:"-" json:"-" xml:"-" desc:"current position of the floating Label, animated between 0 = resting in the place of the placeholder, and 1 = floated above the text"`
+	labelAnim    bool                         `copy:"-" json:"-" xml:"-"`
+	labelMu      sync.Mutex                   `copy:"-" json:"-" xml:"-"`
 	BlinkOn      bool                         `copy:"-" json:"-" xml:"-" desc:"oscillates between on and off for blinking"`
 	CursorMu     sync.Mutex                   `copy:"-" json:"-" xml:"-" view:"-" desc:"mutex for updating cursor between blinker and field"`
 	Complete     *Complete                    `copy:"-" json:"-" xml:"-" desc:"functions and data for textfield completion"`
 	NoEcho       bool                         `copy:"-" json:"-" xml:"-" desc:"replace displayed characters with bullets to conceal text"`
+	InputFilter  func(str string) string      `copy:"-" json:"-" xml:"-" view:"-" desc:"optional function called on text that is typed or pasted into the field, which returns the text that is actually inserted -- e.g., to drop characters that are not allowed (an input mask), or to normalize pasted text -- nothing is inserted if it returns an empty string"`
 }
 
 var KiT_TextField = kit.Types.AddType(&TextField{}, TextFieldProps)
@@ -81,7 +100,13 @@ func (tf *TextField) CopyFieldsFrom(frm any) {
 	tf.PartsWidgetBase.CopyFieldsFrom(&fr.PartsWidgetBase)
 	tf.Txt = fr.Txt
 	tf.Placeholder = fr.Placeholder
+	tf.Label = fr.Label
+	tf.HelperText = fr.HelperText
+	tf.ErrorText = fr.ErrorText
+	tf.ErrorColor = fr.ErrorColor
 	tf.ClearAct = fr.ClearAct
+	tf.LeadIcon = fr.LeadIcon
+	tf.TrailIcon = fr.TrailIcon
 	tf.CursorWidth = fr.CursorWidth
 	tf.Edited = fr.Edited
 	tf.MaxWidthReq = fr.MaxWidthReq
@@ -103,6 +128,21 @@ var TextFieldProps = ki.Props{
 	"color":            &Prefs.Colors.Font,
 	"background-color": &Prefs.Colors.Control,
 	"clear-act":        true,
+	"error-color":      "#B3261E",
+	"#lead-icon": ki.Props{
+		"width":          units.NewEm(1),
+		"height":         units.NewEm(1),
+		"margin":         units.NewPx(0),
+		"padding":        units.NewPx(0),
+		"vertical-align": gist.AlignMiddle,
+	},
+	"#trail-icon": ki.Props{
+		"width":          units.NewEm(1),
+		"height":         units.NewEm(1),
+		"margin":         units.NewPx(0),
+		"padding":        units.NewPx(0),
+		"vertical-align": gist.AlignMiddle,
+	},
 	"#clear": ki.Props{
 		"width":          units.NewEx(0.5),
 		"height":         units.NewEx(0.5),
@@ -158,6 +198,12 @@ const (
 	// TextFieldDelete is emitted when a character after cursor is deleted
 	TextFieldDelete
 
+	// TextFieldLeadIconClicked means the LeadIcon was clicked.  data is the text.
+	TextFieldLeadIconClicked
+
+	// TextFieldTrailIconClicked means the TrailIcon was clicked.  data is the text.
+	TextFieldTrailIconClicked
+
 	TextFieldSignalsN
 )
 
@@ -609,7 +655,14 @@ func (tf *TextField) Paste() {
 }
 
 // InsertAtCursor inserts given text at current cursor position
+// (filtered through the InputFilter, if set)
 func (tf *TextField) InsertAtCursor(str string) {
+	if tf.InputFilter != nil {
+		str = tf.InputFilter(str)
+		if str == "" {
+			return
+		}
+	}
 	updt := tf.UpdateStart()
 	defer tf.UpdateEnd(updt)
 	wupdt := tf.TopUpdateStart()
@@ -752,6 +805,17 @@ func (tf *TextField) StartCharPos(idx int) float32 {
 	return sr.Render[idx].RelPos.X
 }
 
+// FirstBaseline returns the offset of the text baseline from the top of
+// the field -- see Baseliner
+func (tf *TextField) FirstBaseline(height float32) float32 {
+	tf.StyMu.RLock()
+	defer tf.StyMu.RUnlock()
+	if tf.Sty.Font.Face == nil || tf.Sty.Font.Face.Face == nil {
+		return -1
+	}
+	return tf.Sty.BoxSpace() + mat32.FromFixed(tf.Sty.Font.Face.Face.Metrics().Ascent)
+}
+
 // CharStartPos returns the starting render coords for the given character
 // position in string -- makes no attempt to rationalize that pos (i.e., if
 // not in visible range, position will be out of range too).
@@ -760,6 +824,8 @@ func (tf *TextField) CharStartPos(charidx int, wincoords bool) mat32.Vec2 {
 	st := &tf.Sty
 	spc := st.BoxSpace()
 	pos := tf.LayState.Alloc.Pos.AddScalar(spc)
+	pos.X += tf.LeadWidth()
+	pos.Y += tf.LabelHeight()
 	if wincoords {
 		mvp := tf.ViewportSafe()
 		mvp.BBoxMu.RLock()
@@ -777,6 +843,13 @@ var TextFieldBlinkMu sync.Mutex
 // only one of which can be active at at a time
 var TextFieldBlinker *time.Ticker
 
+// textFieldBlinkMSec is the CursorBlinkMSec that TextFieldBlinker ticks at
+var textFieldBlinkMSec int
+
+// textFieldBlinkStopped is set when TextFieldBlinker is stopped because the
+// window is idle -- it is restarted when the window wakes up
+var textFieldBlinkStopped bool
+
 // BlinkingTextField is the text field that is blinking
 var BlinkingTextField *TextField
 
@@ -818,12 +891,33 @@ func TextFieldBlink() {
 			TextFieldBlinkMu.Unlock()
 			continue
 		}
+		if win.IsIdle() { // leave cursor on, and stop ticking until woken up
+			if !tf.BlinkOn {
+				tf.BlinkOn = true
+				tf.RenderCursor(true)
+			}
+			TextFieldBlinker.Stop()
+			textFieldBlinkStopped = true
+			TextFieldBlinkMu.Unlock()
+			continue
+		}
 		tf.BlinkOn = !tf.BlinkOn
 		tf.RenderCursor(tf.BlinkOn)
 		TextFieldBlinkMu.Unlock()
 	}
 }
 
+// restartTextFieldBlink restarts TextFieldBlinker if it was stopped while
+// the window was idle
+func restartTextFieldBlink() {
+	TextFieldBlinkMu.Lock()
+	if TextFieldBlinker != nil && textFieldBlinkStopped && CursorBlinkMSec > 0 {
+		TextFieldBlinker.Reset(time.Duration(CursorBlinkMSec) * time.Millisecond)
+		textFieldBlinkStopped = false
+	}
+	TextFieldBlinkMu.Unlock()
+}
+
 // StartCursor starts the cursor blinking and renders it
 func (tf *TextField) StartCursor() {
 	if tf == nil || tf.This() == nil {
@@ -841,7 +935,12 @@ func (tf *TextField) StartCursor() {
 	if TextFieldBlinker == nil {
 		TextFieldBlinker = time.NewTicker(time.Duration(CursorBlinkMSec) * time.Millisecond)
 		go TextFieldBlink()
+		AddIdleWakeFunc(restartTextFieldBlink)
+	} else if textFieldBlinkMSec != CursorBlinkMSec || textFieldBlinkStopped { // prefs changed or stopped when idle
+		TextFieldBlinker.Reset(time.Duration(CursorBlinkMSec) * time.Millisecond)
 	}
+	textFieldBlinkMSec = CursorBlinkMSec
+	textFieldBlinkStopped = false
 	tf.BlinkOn = true
 	win := tf.ParentWindow()
 	if win != nil && !win.IsResizing() {
@@ -1058,7 +1157,7 @@ func (tf *TextField) PixelToCursor(pixOff float32) int {
 	st := &tf.Sty
 
 	spc := st.BoxSpace()
-	px := pixOff - spc
+	px := pixOff - spc - tf.LeadWidth()
 
 	if px <= 0 {
 		return tf.StartPos
@@ -1352,16 +1451,37 @@ func (tf *TextField) TextFieldEvents() {
 
 func (tf *TextField) ConfigParts() {
 	tf.Parts.Lay = LayoutHoriz
-	if !tf.ClearAct || tf.IsInactive() {
+	clearAct := tf.ClearAct && !tf.IsInactive()
+	if !clearAct && tf.LeadIcon.IsNil() && tf.TrailIcon.IsNil() {
 		tf.Parts.DeleteChildren(ki.DestroyKids)
 		return
 	}
 	config := kit.TypeAndNameList{}
+	if !tf.LeadIcon.IsNil() {
+		config.Add(KiT_Action, "lead-icon")
+	}
 	config.Add(KiT_Stretch, "clr-str")
-	config.Add(KiT_Action, "clear")
+	if !tf.TrailIcon.IsNil() {
+		config.Add(KiT_Action, "trail-icon")
+	}
+	if clearAct {
+		config.Add(KiT_Action, "clear")
+	}
 	mods, updt := tf.Parts.ConfigChildren(config)
+	if lead, ok := tf.Parts.ChildByName("lead-icon", 0).(*Action); ok {
+		tf.ConfigIconAct(lead, tf.LeadIcon, TextFieldLeadIconClicked, mods)
+	}
+	if trail, ok := tf.Parts.ChildByName("trail-icon", 2).(*Action); ok {
+		tf.ConfigIconAct(trail, tf.TrailIcon, TextFieldTrailIconClicked, mods)
+	}
+	clr, ok := tf.Parts.ChildByName("clear", 3).(*Action)
+	if !ok {
+		if mods {
+			tf.UpdateEnd(updt)
+		}
+		return
+	}
 	if mods || gist.RebuildDefaultStyles {
-		clr := tf.Parts.Child(1).(*Action)
 		tf.StylePart(Node2D(clr))
 		clr.SetIcon("close")
 		clr.SetProp("no-focus", true)
@@ -1375,6 +1495,45 @@ func (tf *TextField) ConfigParts() {
 	}
 }
 
+// ConfigIconAct configures the action for the LeadIcon or TrailIcon, which
+// emits given signal on the TextFieldSig when clicked
+func (tf *TextField) ConfigIconAct(ac *Action, icon IconName, sig TextFieldSignals, mods bool) {
+	if !mods && !gist.RebuildDefaultStyles && ac.Icon == icon {
+		return
+	}
+	tf.StylePart(Node2D(ac))
+	ac.SetIcon(string(icon))
+	ac.SetProp("no-focus", true)
+	ac.ActionSig.ConnectOnly(tf.This(), func(recv, send ki.Ki, s int64, data any) {
+		tff := recv.Embed(KiT_TextField).(*TextField)
+		if tff != nil {
+			tff.TextFieldSig.Emit(tff.This(), int64(sig), string(tff.EditTxt))
+		}
+	})
+}
+
+// SetLeadIcon sets the LeadIcon shown at the left side of the text --
+// connect to the TextFieldSig for TextFieldLeadIconClicked to handle
+// clicks on it.  An empty name removes the icon.
+func (tf *TextField) SetLeadIcon(icon IconName) {
+	updt := tf.UpdateStart()
+	tf.LeadIcon = icon
+	tf.ConfigParts()
+	tf.SetFullReRender()
+	tf.UpdateEnd(updt)
+}
+
+// SetTrailIcon sets the TrailIcon shown at the right side of the text --
+// connect to the TextFieldSig for TextFieldTrailIconClicked to handle
+// clicks on it.  An empty name removes the icon.
+func (tf *TextField) SetTrailIcon(icon IconName) {
+	updt := tf.UpdateStart()
+	tf.TrailIcon = icon
+	tf.ConfigParts()
+	tf.SetFullReRender()
+	tf.UpdateEnd(updt)
+}
+
 ////////////////////////////////////////////////////
 //  Node2D Interface
 
@@ -1382,6 +1541,7 @@ func (tf *TextField) Init2D() {
 	tf.Init2DWidget()
 	tf.EditTxt = []rune(tf.Txt)
 	tf.Edited = false
+	tf.UpdateLabelPos(false)
 	tf.ConfigParts()
 }
 
@@ -1422,6 +1582,9 @@ func (tf *TextField) StyleTextField() {
 	if pv, ok := tf.PropInherit("clear-act", ki.Inherit, ki.TypeProps); ok {
 		tf.ClearAct, _ = kit.ToBool(pv)
 	}
+	if pv, ok := tf.PropInherit("error-color", ki.Inherit, ki.TypeProps); ok {
+		tf.ErrorColor.SetIFace(pv, tf.Viewport, "error-color")
+	}
 	tf.StyMu.Unlock()
 	tf.ConfigParts()
 }
@@ -1462,8 +1625,15 @@ func (tf *TextField) Size2D(iter int) {
 	tf.FontHeight = tf.RenderAll.Size.Y
 	w := tf.TextWidth(tf.StartPos, tf.EndPos)
 	w += 2.0 // give some extra buffer
+	if !tf.LeadIcon.IsNil() {
+		w += tf.FontHeight // icons are 1em
+	}
+	if !tf.TrailIcon.IsNil() {
+		w += tf.FontHeight
+	}
 	// fmt.Printf("fontheight: %v width: %v\n", tf.FontHeight, w)
-	tf.Size2DFromWH(w, tf.FontHeight)
+	tf.Size2DFromWH(w, tf.FontHeight+tf.LabelHeight())
+	tf.LayState.Alloc.Size.Y += tf.HelperHeight()
 	tf.EditTxt = tmptxt
 }
 
@@ -1475,14 +1645,39 @@ func (tf *TextField) Layout2D(parBBox image.Rectangle, iter int) bool {
 	}
 	redo := tf.Layout2DChildren(iter)
 	sz := tf.LayState.Alloc.Size
-	if tf.ClearAct && len(*tf.Parts.Children()) == 2 {
-		clr := tf.Parts.Child(1).(*Action)
+	if clr, ok := tf.Parts.ChildByName("clear", 3).(*Action); ok {
 		sz.X -= clr.LayState.Alloc.Size.X
 	}
+	if trail, ok := tf.Parts.ChildByName("trail-icon", 2).(*Action); ok {
+		sz.X -= trail.LayState.Alloc.Size.X
+	}
+	sz.X -= tf.LeadWidth()
+	sz.Y -= tf.LabelHeight() + tf.HelperHeight()
 	tf.EffSize = sz
 	return redo
 }
 
+// Layout2DParts lays out the parts within the box, below the space for the
+// floated Label and above the line for the HelperText
+func (tf *TextField) Layout2DParts(parBBox image.Rectangle, iter int) {
+	spc := tf.BoxSpace()
+	lh := tf.LabelHeight()
+	tf.Parts.LayState.Alloc.Pos = tf.LayState.Alloc.Pos.AddScalar(spc)
+	tf.Parts.LayState.Alloc.Pos.Y += lh
+	tf.Parts.LayState.Alloc.Size = tf.LayState.Alloc.Size.AddScalar(-2.0 * spc)
+	tf.Parts.LayState.Alloc.Size.Y -= lh + tf.HelperHeight()
+	tf.Parts.Layout2D(parBBox, iter)
+}
+
+// LeadWidth returns the width of the LeadIcon, if any, which the text is
+// offset by
+func (tf *TextField) LeadWidth() float32 {
+	if lead, ok := tf.Parts.ChildByName("lead-icon", 0).(*Action); ok {
+		return lead.LayState.Alloc.Size.X
+	}
+	return 0
+}
+
 func (tf *TextField) RenderTextField() {
 	rs, _, st := tf.RenderLock()
 	defer tf.RenderUnlock(rs)
@@ -1507,11 +1702,22 @@ func (tf *TextField) RenderTextField() {
 	}
 	st = &tf.Sty // update
 	girl.OpenFont(&st.Font, &st.UnContext)
-	tf.RenderStdBox(st)
+	hclr := st.Font.Color.Highlight(50)
+	if tf.ErrorText != "" {
+		st.Border.Color = tf.ErrorColor
+	}
+	bpos := tf.LayState.Alloc.Pos.AddScalar(st.Layout.Margin.Dots)
+	bsz := tf.LayState.Alloc.Size.AddScalar(-2.0 * st.Layout.Margin.Dots)
+	bsz.Y -= tf.HelperHeight()
+	tf.RenderStdBoxGeom(st, bpos, bsz)
+	tf.RenderHelper(rs, st, hclr)
 	cur := tf.EditTxt[tf.StartPos:tf.EndPos]
 	tf.RenderSelect()
 	pos := tf.LayState.Alloc.Pos.AddScalar(st.BoxSpace())
-	if len(tf.EditTxt) == 0 && len(tf.Placeholder) > 0 {
+	pos.X += tf.LeadWidth()
+	pos.Y += tf.LabelHeight()
+	tf.RenderLabel(rs, st, pos, hclr)
+	if len(tf.EditTxt) == 0 && len(tf.Placeholder) > 0 && (tf.Label == "" || tf.LabelPos >= 1) {
 		st.Font.Color = st.Font.Color.Highlight(50)
 		tf.RenderVis.SetString(tf.Placeholder, &st.Font, &st.UnContext, &st.Text, true, 0, 0)
 		tf.RenderVis.RenderTopPos(rs, pos)
@@ -1525,6 +1731,155 @@ func (tf *TextField) RenderTextField() {
 	}
 }
 
+// LabelFloated returns true if the floating Label should be floated above
+// the text, when the field has text or is being edited
+func (tf *TextField) LabelFloated() bool {
+	return len(tf.EditTxt) > 0 || (tf.HasFocus() && tf.IsFocusActive())
+}
+
+// LabelHeight returns the height of the space above the text for the
+// floated Label, if any
+func (tf *TextField) LabelHeight() float32 {
+	if tf.Label == "" {
+		return 0
+	}
+	return TextFieldLabelScale * tf.FontHeight
+}
+
+// HelperHeight returns the height of the line below the field for the
+// HelperText or ErrorText, if any
+func (tf *TextField) HelperHeight() float32 {
+	if tf.HelperText == "" && tf.ErrorText == "" {
+		return 0
+	}
+	return TextFieldLabelScale * tf.FontHeight
+}
+
+// SetErrorText sets the ErrorText shown below the field, e.g., from
+// validating the text -- an empty string clears the error
+func (tf *TextField) SetErrorText(txt string) {
+	if tf.ErrorText == txt {
+		return
+	}
+	hadHelper := tf.HelperHeight() > 0
+	tf.ErrorText = txt
+	if hadHelper != (tf.HelperHeight() > 0) {
+		tf.SetFullReRender() // size changed
+	}
+	tf.UpdateSig()
+}
+
+// LabelFont returns the font for the floated Label, HelperText and
+// ErrorText, scaled by given amount relative to the text font, along with
+// the units context for it
+func (tf *TextField) LabelFont(st *gist.Style, scale float32) (gist.Font, units.Context) {
+	fnt := st.Font
+	uc := st.UnContext // font sets its em sizes in context
+	fnt.Size.Dots *= scale
+	girl.OpenFont(&fnt, &uc)
+	return fnt, uc
+}
+
+// RenderLabel renders the floating Label, if any, at its current LabelPos,
+// relative to given position of the text, with given resting color
+func (tf *TextField) RenderLabel(rs *girl.State, st *gist.Style, pos mat32.Vec2, clr gist.Color) {
+	if tf.Label == "" {
+		return
+	}
+	lp := tf.LabelPos
+	fnt, uc := tf.LabelFont(st, 1-(1-TextFieldLabelScale)*lp)
+	switch {
+	case tf.ErrorText != "":
+		fnt.Color = tf.ErrorColor
+	case lp > 0 && tf.HasFocus() && tf.IsFocusActive():
+		fnt.Color = Prefs.Colors.Icon
+	default:
+		fnt.Color = clr
+	}
+	pos.Y -= lp * tf.LabelHeight()
+	var lbl girl.Text
+	lbl.SetString(tf.Label, &fnt, &uc, &st.Text, true, 0, 0)
+	lbl.RenderTopPos(rs, pos)
+}
+
+// RenderHelper renders the ErrorText if set, or else the HelperText, below
+// the box, with given color for the HelperText
+func (tf *TextField) RenderHelper(rs *girl.State, st *gist.Style, clr gist.Color) {
+	hh := tf.HelperHeight()
+	if hh == 0 {
+		return
+	}
+	txt := tf.HelperText
+	fnt, uc := tf.LabelFont(st, TextFieldLabelScale)
+	fnt.Color = clr
+	if tf.ErrorText != "" {
+		txt = tf.ErrorText
+		fnt.Color = tf.ErrorColor
+	}
+	pos := tf.LayState.Alloc.Pos
+	pos.X += st.BoxSpace()
+	pos.Y += tf.LayState.Alloc.Size.Y - hh
+	var hlp girl.Text
+	hlp.SetString(txt, &fnt, &uc, &st.Text, true, 0, 0)
+	hlp.RenderTopPos(rs, pos)
+}
+
+// UpdateLabelPos moves the floating Label to its floated or resting
+// position, according to LabelFloated, animating it over
+// TextFieldLabelAnimMSec if animate is true
+func (tf *TextField) UpdateLabelPos(animate bool) {
+	if tf.Label == "" {
+		return
+	}
+	trg := float32(0)
+	if tf.LabelFloated() {
+		trg = 1
+	}
+	tf.labelMu.Lock()
+	defer tf.labelMu.Unlock()
+	if tf.labelAnim || tf.LabelPos == trg {
+		return
+	}
+	if !animate || TextFieldLabelAnimMSec <= 0 {
+		tf.LabelPos = trg
+		return
+	}
+	tf.labelAnim = true
+	go tf.AnimateLabel()
+}
+
+// AnimateLabel moves the floating Label step by step toward its floated or
+// resting position, re-rendering at each step, until it gets there
+func (tf *TextField) AnimateLabel() {
+	const steps = 8
+	tick := time.NewTicker(time.Duration(TextFieldLabelAnimMSec) * time.Millisecond / steps)
+	defer tick.Stop()
+	for range tick.C {
+		if tf.This() == nil || tf.IsDeleted() || tf.IsDestroyed() {
+			break
+		}
+		trg := float32(0)
+		if tf.LabelFloated() {
+			trg = 1
+		}
+		tf.labelMu.Lock()
+		if tf.LabelPos < trg {
+			tf.LabelPos = mat32.Min(tf.LabelPos+1.0/steps, trg)
+		} else {
+			tf.LabelPos = mat32.Max(tf.LabelPos-1.0/steps, trg)
+		}
+		done := tf.LabelPos == trg
+		tf.labelMu.Unlock()
+		tf.UpdateSig()
+		if done {
+			break
+		}
+	}
+	tf.labelMu.Lock()
+	tf.labelAnim = false
+	tf.labelMu.Unlock()
+}
+
 func (tf *TextField) Render2D() {
 	if tf.HasFocus() && tf.IsFocusActive() && BlinkingTextField == tf {
 		tf.ScrollLayoutToCursor()
@@ -1534,6 +1889,7 @@ func (tf *TextField) Render2D() {
 	}
 	if tf.PushBounds() {
 		tf.This().(Node2D).ConnectEvents2D()
+		tf.UpdateLabelPos(true)
 		tf.RenderTextField()
 		if tf.IsActive() {
 			if tf.HasFocus() && tf.IsFocusActive() {
@@ -0,0 +1,132 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+// Tinting recolors icons and monochrome images from style properties, so
+// the same asset adapts to the current color scheme without separate
+// variants for light and dark themes:
+//
+// * "tint" sets a color that replaces the color of every pixel, keeping
+// its alpha -- e.g., "tint": &Prefs.Colors.Icon follows the current
+// scheme.  This is inherited, so it can be set on a button for its icon.
+//
+// * "inactive-desaturate" is the percent by which the image is desaturated
+// when it or any of its parents is inactive -- defaults to 100 (grey),
+// and 0 turns it off.
+
+// InactiveDesaturateDefault is the default percent desaturation of icons
+// and images in the inactive state, if not set by "inactive-desaturate"
+var InactiveDesaturateDefault = float32(100)
+
+// TintParams are the current tint parameters of an icon or image, from
+// its style properties and inactive state -- see TintImage
+type TintParams struct {
+	Tint  gist.Color `desc:"color to recolor every pixel to, keeping alpha -- nil for none"`
+	Desat float32    `desc:"percent to desaturate by, for the inactive state"`
+}
+
+// IsNil returns true if the tint parameters do not change the image
+func (tp *TintParams) IsNil() bool {
+	return tp.Tint.IsNil() && tp.Desat <= 0
+}
+
+// TintParamsFor returns the tint parameters for given node, from its
+// "tint" and "inactive-desaturate" properties (inherited), and whether it
+// or any parent is inactive.
+func TintParamsFor(nb *Node2DBase) TintParams {
+	var tp TintParams
+	if tv, ok := nb.PropInherit("tint", ki.Inherit, ki.TypeProps); ok {
+		var ctxt gist.Context
+		if nb.Viewport != nil {
+			ctxt = nb.Viewport
+		}
+		tp.Tint.SetIFace(tv, ctxt, "tint")
+	}
+	if NodeOrParentInactive(nb.This()) {
+		tp.Desat = InactiveDesaturateDefault
+		if dv, ok := nb.PropInherit("inactive-desaturate", ki.Inherit, ki.TypeProps); ok {
+			if df, ok := kit.ToFloat32(dv); ok {
+				tp.Desat = df
+			}
+		}
+	}
+	return tp
+}
+
+// NodeOrParentInactive returns true if given node or any of its parents
+// up to the enclosing viewport is inactive
+func NodeOrParentInactive(node ki.Ki) bool {
+	for k := node; k != nil && k.This() != nil; k = k.Parent() {
+		nb, ok := k.Embed(KiT_NodeBase).(*NodeBase)
+		if !ok {
+			return false
+		}
+		if nb.IsInactive() {
+			return true
+		}
+		if _, isvp := k.Embed(KiT_Viewport2D).(*Viewport2D); isvp && k != node {
+			return false
+		}
+	}
+	return false
+}
+
+// TintImage applies given tint parameters to given region of the image,
+// modifying it in place
+func TintImage(im *image.RGBA, r image.Rectangle, tp TintParams) {
+	if !tp.Tint.IsNil() {
+		ImageTint(im, r, tp.Tint)
+	}
+	if tp.Desat > 0 {
+		ImageDesaturate(im, r, tp.Desat)
+	}
+}
+
+// ImageTint recolors every pixel within given region of the image to the
+// given color, keeping the pixel alpha (times the color alpha) -- this is
+// for monochrome images and icons, where the shape is in the alpha channel.
+func ImageTint(im *image.RGBA, r image.Rectangle, clr gist.Color) {
+	r = r.Intersect(im.Bounds())
+	ca := uint32(clr.A)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			off := im.PixOffset(x, y)
+			a := uint32(im.Pix[off+3]) * ca / 255
+			// premultiplied
+			im.Pix[off+0] = uint8(uint32(clr.R) * a / 255)
+			im.Pix[off+1] = uint8(uint32(clr.G) * a / 255)
+			im.Pix[off+2] = uint8(uint32(clr.B) * a / 255)
+			im.Pix[off+3] = uint8(a)
+		}
+	}
+}
+
+// ImageDesaturate makes the pixels within given region of the image less
+// saturated by given percent: 100 = grey with the same luminance
+func ImageDesaturate(im *image.RGBA, r image.Rectangle, pct float32) {
+	r = r.Intersect(im.Bounds())
+	fact := mat32.Clamp(pct, 0, 100) / 100
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			off := im.PixOffset(x, y)
+			pr := float32(im.Pix[off+0])
+			pg := float32(im.Pix[off+1])
+			pb := float32(im.Pix[off+2])
+			gy := 0.2126*pr + 0.7152*pg + 0.0722*pb // premultiplied grey stays premultiplied
+			im.Pix[off+0] = uint8(pr + (gy-pr)*fact + 0.5)
+			im.Pix[off+1] = uint8(pg + (gy-pg)*fact + 0.5)
+			im.Pix[off+2] = uint8(pb + (gy-pb)*fact + 0.5)
+		}
+	}
+}
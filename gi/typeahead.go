@@ -0,0 +1,106 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/goki/gi/oswin/key"
+)
+
+// TypeAheadTimeoutMSec is the number of milliseconds between keypresses to
+// combine characters into the query of a TypeAhead search -- starts over
+// after this delay.
+var TypeAheadTimeoutMSec = 1000
+
+// TypeAhead is the state of a type-ahead incremental search of the items of
+// a widget, e.g., the nodes of a TreeView or the rows of a TableView: typing
+// accumulates a query, shown in a small popup, and the widget jumps to the
+// next item whose label starts with it (ignoring case).  Typing the same
+// single character again jumps to the next item starting with it,
+// Backspace removes the last character, and Escape clears the query, as
+// does not typing for TypeAheadTimeoutMSec.
+type TypeAhead struct {
+	Query string      `desc:"the current query"`
+	Time  time.Time   `desc:"time of the last key press of the query"`
+	Popup *Viewport2D `json:"-" xml:"-" desc:"popup showing the query, if open"`
+}
+
+// KeyInput processes given key event for the type-ahead search, returning
+// true if the widget should search for the next item that Matches the
+// Query: after the current item if next is true, and otherwise starting at
+// it -- the event is marked as processed if it was used.
+func (ta *TypeAhead) KeyInput(kt *key.ChordEvent) (search, next bool) {
+	if ta.Query != "" && kt.Time().Sub(ta.Time) > time.Duration(TypeAheadTimeoutMSec)*time.Millisecond {
+		ta.Query = ""
+	}
+	kf := KeyFun(kt.Chord())
+	switch {
+	case ta.Query != "" && (kf == KeyFunAbort || kf == KeyFunCancelSelect):
+		ta.Clear()
+		kt.SetProcessed()
+		return false, false
+	case ta.Query != "" && kf == KeyFunBackspace:
+		rs := []rune(ta.Query)
+		ta.Query = string(rs[:len(rs)-1])
+		ta.Time = kt.Time()
+		kt.SetProcessed()
+		return ta.Query != "", false
+	case !unicode.IsPrint(kt.Rune) || key.HasAnyModifierBits(kt.Modifiers, key.Control, key.Alt, key.Meta):
+		return false, false
+	case kt.Rune == ' ' && ta.Query == "": // space is often an action key
+		return false, false
+	}
+	sr := string(kt.Rune)
+	if ta.Query == sr {
+		next = true
+	} else {
+		ta.Query += sr
+	}
+	ta.Time = kt.Time()
+	kt.SetProcessed()
+	return true, next
+}
+
+// Matches returns true if given label matches the Query: starts with it,
+// ignoring case
+func (ta *TypeAhead) Matches(label string) bool {
+	return ta.Query != "" && len(label) >= len(ta.Query) && strings.EqualFold(label[:len(ta.Query)], ta.Query)
+}
+
+// Clear clears the query -- the popup closes at the end of the event
+func (ta *TypeAhead) Clear() {
+	ta.Query = ""
+	ta.Popup = nil
+}
+
+// ShowPopup shows the query in a popup at the top of given widget, noting
+// whether a match was found.  The current focus is kept, and the popup
+// closes on the next event (like a tooltip), or after TypeAheadTimeoutMSec.
+func (ta *TypeAhead) ShowPopup(wb *WidgetBase, found bool) {
+	ta.Popup = nil
+	vp := wb.ViewportSafe()
+	if ta.Query == "" || vp == nil || vp.Win == nil {
+		return
+	}
+	txt := "Find: " + ta.Query
+	if !found {
+		txt += "  (not found)"
+	}
+	pos := wb.WinBBox.Min
+	pvp := TooltipViewport(txt, pos.X, pos.Y, vp, wb.Nm+"-type-ahead")
+	ta.Popup = pvp
+	win := vp.Win
+	win.SetNextPopup(pvp.This(), win.EventMgr.CurFocus())
+	time.AfterFunc(time.Duration(TypeAheadTimeoutMSec)*time.Millisecond, func() {
+		if win.IsClosed() || win.CurPopup() != pvp.This() {
+			return
+		}
+		win.SetDelPopup(pvp.This())
+		win.OSWin.SendEmptyEvent()
+	})
+}
@@ -10,7 +10,6 @@ import (
 	"image/draw"
 	"image/png"
 	"io"
-	"log"
 	"strings"
 	"sync"
 
@@ -387,6 +386,13 @@ func (vp *Viewport2D) ReRender2DNode(gni Node2D) {
 	if Render2DTrace {
 		fmt.Printf("Render: vp re-render: %v node: %v\n", vp.Path(), gn.Path())
 	}
+	InvalidateRenderCaches(gni)
+	if gn.Par != nil {
+		if bly := BusyLayout(gn.Par); bly != nil { // keep busy overlay on top
+			gni = bly.This().(Node2D)
+			gn = gni.AsNode2D()
+		}
+	}
 	// pr := prof.Start("vp.ReRender2DNode")
 	gn.Render2DTree()
 	// pr.End()
@@ -412,6 +418,7 @@ func (vp *Viewport2D) ReRender2DAnchor(gni Node2D) {
 	if Render2DTrace {
 		fmt.Printf("Render: vp anchor re-render: %v node: %v\n", vp.Path(), pw.Path())
 	}
+	InvalidateRenderCaches(gni)
 	// pr := prof.Start("vp.ReRender2DNode")
 	pw.ReRender2DTree()
 	// pr.End()
@@ -419,6 +426,11 @@ func (vp *Viewport2D) ReRender2DAnchor(gni Node2D) {
 	wbb := pw.WinBBox
 	pw.BBoxMu.RUnlock()
 	vp.This().(Viewport).VpUploadRegion(pw.VpBBox, wbb)
+	if pw.Par != nil {
+		if bly := BusyLayout(pw.Par); bly != nil { // keep busy overlay on top
+			vp.ReRender2DNode(bly.This().(Node2D))
+		}
+	}
 }
 
 // Delete this popup viewport -- has already been disconnected from window
@@ -705,7 +717,7 @@ func SignalViewport2D(vpki, send ki.Ki, sig int64, data any) {
 	}
 	if ni.IsUpdating() {
 		if Update2DTrace { // this can happen during concurrent update situations
-			log.Printf("Update: SignalViewport2D updating node %v with Updating flag set\n", ni.Path())
+			Logf(LogInfo, "gi.Viewport2D", "SignalViewport2D updating node %v with Updating flag set", ni.Path())
 		}
 		return
 	}
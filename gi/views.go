@@ -34,6 +34,22 @@ type ViewIFace interface {
 	// PrefsDetView opens an interactive view of given detailed preferences object
 	PrefsDetView(prefs *PrefsDetailed)
 
+	// ColorSchemesView opens an interactive editor of the color schemes in
+	// given preferences, with a live preview
+	ColorSchemesView(prefs *Preferences)
+
+	// LogConsole opens a window showing the log records, as captured by
+	// CaptureStdLog and Logf
+	LogConsole()
+
+	// FindAll opens a window for finding text in all of the FindProviders,
+	// e.g., all the open text buffers, table views and tree views
+	FindAll()
+
+	// HelpView opens the Help viewer at given location in the HelpBundles,
+	// or at the contents of all of them if empty -- see OpenHelp
+	HelpView(uri string)
+
 	// HiStylesView opens an interactive view of custom or std highlighting styles.
 	HiStylesView(std bool)
 
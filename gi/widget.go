@@ -7,7 +7,6 @@ package gi
 import (
 	"fmt"
 	"image"
-	"log"
 	"strings"
 	"sync"
 
@@ -30,13 +29,19 @@ import (
 // includes toggling selection on left mouse press.
 type WidgetBase struct {
 	Node2DBase
-	Tooltip      string       `desc:"text for tooltip for this widget -- can use HTML formatting"`
-	Sty          gist.Style   `json:"-" xml:"-" desc:"styling settings for this widget -- set in SetStyle2D during an initialization step, and when the structure changes"`
-	DefStyle     *gist.Style  `copy:"-" view:"-" json:"-" xml:"-" desc:"default style values computed by a parent widget for us -- if set, we are a part of a parent widget and should use these as our starting styles instead of type-based defaults"`
-	LayState     LayoutState  `copy:"-" json:"-" xml:"-" desc:"all the layout state information for this item"`
-	WidgetSig    ki.Signal    `copy:"-" json:"-" xml:"-" view:"-" desc:"general widget signals supported by all widgets, including select, focus, and context menu (right mouse button) events, which can be used by views and other compound widgets"`
-	CtxtMenuFunc CtxtMenuFunc `copy:"-" view:"-" json:"-" xml:"-" desc:"optional context menu function called by MakeContextMenu AFTER any native items are added -- this function can decide where to insert new elements -- typically add a separator to disambiguate"`
-	StyMu        sync.RWMutex `copy:"-" view:"-" json:"-" xml:"-" desc:"mutex protecting updates to the style"`
+	Tooltip      string           `desc:"text for tooltip for this widget -- can use HTML formatting"`
+	HelpURI      string           `desc:"location of the help for this widget, as bundle/page.md#anchor in the HelpBundles, shown by the Help key function (F1) -- if empty, that of the nearest parent with one is used -- see HelpURIFor"`
+	AutoID       string           `copy:"-" desc:"stable automation ID of this widget, for UI automation tools, test drivers and accessibility tools, which find it by this ID in the widget tree dumped by the automation endpoint (see StartAutomation) -- unlike the name, it is only set by the app, and it should be unique among the widgets of the app"`
+	Sty          gist.Style       `json:"-" xml:"-" desc:"styling settings for this widget -- set in SetStyle2D during an initialization step, and when the structure changes"`
+	DefStyle     *gist.Style      `copy:"-" view:"-" json:"-" xml:"-" desc:"default style values computed by a parent widget for us -- if set, we are a part of a parent widget and should use these as our starting styles instead of type-based defaults"`
+	LayState     LayoutState      `copy:"-" json:"-" xml:"-" desc:"all the layout state information for this item"`
+	WidgetSig    ki.Signal        `copy:"-" json:"-" xml:"-" view:"-" desc:"general widget signals supported by all widgets, including select, focus, and context menu (right mouse button) events, which can be used by views and other compound widgets"`
+	CtxtMenuFunc CtxtMenuFunc     `copy:"-" view:"-" json:"-" xml:"-" desc:"optional context menu function called by MakeContextMenu AFTER any native items are added -- this function can decide where to insert new elements -- typically add a separator to disambiguate"`
+	StyMu        sync.RWMutex     `copy:"-" view:"-" json:"-" xml:"-" desc:"mutex protecting updates to the style"`
+	StyProv      gist.StyleProv   `copy:"-" view:"-" json:"-" xml:"-" desc:"provenance of each style field value, recorded only when gist.StyleProvTrace is on -- see StyleDump"`
+	Filters      girl.Filters     `copy:"-" view:"-" json:"-" xml:"-" desc:"filter effects applied to the rendering of this widget and its children, from the filter style property"`
+	FilterCache  girl.FilterCache `copy:"-" view:"-" json:"-" xml:"-" desc:"cache of the filtered rendering, so the filters are only recomputed when the rendering changes"`
+	Lifecycle    WidgetLifecycle  `copy:"-" view:"-" json:"-" xml:"-" desc:"functions called at lifecycle stages of the widget -- see OnFirstRender, OnShow, OnHide, OnDestroy"`
 }
 
 var KiT_WidgetBase = kit.Types.AddType(&WidgetBase{}, WidgetBaseProps)
@@ -49,12 +54,13 @@ var WidgetBaseProps = ki.Props{
 func (wb *WidgetBase) CopyFieldsFrom(frm any) {
 	fr, ok := frm.(*WidgetBase)
 	if !ok {
-		log.Printf("GoGi node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier WidgetBase one\n", ki.Type(wb).Name())
+		Logf(LogWarn, "gi.WidgetBase", "node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier WidgetBase one", ki.Type(wb).Name())
 		ki.GenCopyFieldsFrom(wb.This(), frm)
 		return
 	}
 	wb.Node2DBase.CopyFieldsFrom(&fr.Node2DBase)
 	wb.Tooltip = fr.Tooltip
+	wb.HelpURI = fr.HelpURI
 	wb.Sty.CopyFrom(&fr.Sty)
 }
 
@@ -132,7 +138,7 @@ func DefaultStyle2DWidget(wb *WidgetBase, selector string, part *WidgetBase) *gi
 		} else {
 			spm, ok := sp.(ki.Props)
 			if !ok {
-				log.Printf("gi.DefaultStyle2DWidget: looking for a ki.Props for style selector: %v, instead got type: %T, for node type: %v\n", selector, spm, ki.Type(wb).Name())
+				Logf(LogWarn, "gi.DefaultStyle2DWidget", "looking for a ki.Props for style selector: %v, instead got type: %T, for node type: %v", selector, spm, ki.Type(wb).Name())
 			} else {
 				styprops = spm
 			}
@@ -181,10 +187,13 @@ func (wb *WidgetBase) Style2DWidget() {
 	wb.Viewport.SetCurStyleNode(gii)
 	defer wb.Viewport.SetCurStyleNode(nil)
 
+	sp := wb.newStyleProvRec()
 	if !gist.RebuildDefaultStyles && wb.DefStyle != nil {
 		wb.Sty.CopyFrom(wb.DefStyle)
+		sp.record("part defaults from parent: " + wb.Par.Name())
 	} else {
 		wb.Sty.CopyFrom(DefaultStyle2DWidget(wb, "", nil))
+		sp.record("type defaults: " + ki.Type(wb).String())
 	}
 	wb.Sty.IsSet = false    // this is always first call, restart
 	if wb.Viewport == nil { // robust
@@ -195,6 +204,7 @@ func (wb *WidgetBase) Style2DWidget() {
 	styprops := *wb.Properties()
 	parSty := wb.ParentStyle()
 	wb.Sty.SetStyleProps(parSty, styprops, wb.Viewport)
+	sp.record("props")
 
 	// look for class-specific style sheets among defaults -- have to do these
 	// dynamically now -- cannot compile into default which is type-general
@@ -203,13 +213,26 @@ func (wb *WidgetBase) Style2DWidget() {
 	classes := strings.Split(strings.ToLower(wb.Class), " ")
 	for _, cl := range classes {
 		clsty := "." + strings.TrimSpace(cl)
-		if sp, ok := ki.SubProps(tprops, clsty); ok {
-			wb.Sty.SetStyleProps(parSty, sp, wb.Viewport)
+		if csp, ok := ki.SubProps(tprops, clsty); ok {
+			wb.Sty.SetStyleProps(parSty, csp, wb.Viewport)
+			sp.record("type props: " + clsty)
 		}
 	}
 	kit.TypesMu.RUnlock()
 	wb.ParentStyleRUnlock()
 
+	// then any registered named style classes -- see gist.AddClass
+	if sp == nil {
+		wb.Sty.ApplyClasses(wb.Class)
+	} else {
+		for _, cl := range strings.Fields(wb.Class) {
+			if fun := gist.ClassFunc(cl); fun != nil {
+				fun(&wb.Sty)
+				sp.record("class func: " + cl + " " + gist.FuncSource(fun))
+			}
+		}
+	}
+
 	pagg := wb.ParentCSSAgg()
 	if pagg != nil {
 		AggCSS(&wb.CSSAgg, *pagg)
@@ -218,15 +241,71 @@ func (wb *WidgetBase) Style2DWidget() {
 	}
 	AggCSS(&wb.CSSAgg, wb.CSS)
 	StyleCSS(gii, wb.Viewport, &wb.Sty, wb.CSSAgg, "")
+	sp.record("CSS")
 
 	SetUnitContext(&wb.Sty, wb.Viewport, mat32.Vec2Zero) // todo: test for use of el-relative
 	if wb.Sty.Inactive {                                 // inactive can only set, not clear
 		wb.SetInactive()
 	}
+	wb.StyleFilters()
 
 	wb.Viewport.SetCurrentColor(wb.Sty.Font.Color)
 }
 
+// StyleFilters sets the Filters from the filter style property
+func (wb *WidgetBase) StyleFilters() {
+	wb.FilterCache.Invalidate()
+	if wb.Sty.Filter == "" {
+		wb.Filters = nil
+		return
+	}
+	fs, err := girl.ParseFilters(wb.Sty.Filter, &wb.Sty.UnContext)
+	if err != nil {
+		Logf(LogError, "gi.WidgetBase", "%v style error: %v", wb.Path(), err)
+	}
+	wb.Filters = fs
+}
+
+// styleProvRec records style provenance during Style2DWidget
+type styleProvRec struct {
+	wb   *WidgetBase
+	prev gist.Style
+}
+
+// newStyleProvRec returns a new provenance recorder if gist.StyleProvTrace
+// is on, resetting the StyProv map -- otherwise returns nil, which is safe
+// to call record on.
+func (wb *WidgetBase) newStyleProvRec() *styleProvRec {
+	if !gist.StyleProvTrace {
+		wb.StyProv = nil
+		return nil
+	}
+	wb.StyProv = make(gist.StyleProv)
+	return &styleProvRec{wb: wb, prev: gist.NewStyle()}
+}
+
+// record records given source for any style fields changed since last record
+func (sp *styleProvRec) record(src string) {
+	if sp == nil {
+		return
+	}
+	sp.wb.StyProv.Record(&sp.prev, &sp.wb.Sty, src)
+	sp.prev = sp.wb.Sty
+}
+
+// StyleDump returns a listing of the computed style for this widget, along
+// with the source that set each value, if gist.StyleProvTrace was on when
+// it was last styled.
+func (wb *WidgetBase) StyleDump() string {
+	wb.StyMu.RLock()
+	defer wb.StyMu.RUnlock()
+	hdr := fmt.Sprintf("Style of: %v (%v) class: %q\n\n", wb.Path(), ki.Type(wb.This()).Name(), wb.Class)
+	if wb.StyProv == nil {
+		hdr += "(turn on gist.StyleProvTrace to record the source of each value)\n\n"
+	}
+	return hdr + wb.Sty.Dump(wb.StyProv)
+}
+
 // StylePart sets the style properties for a child in parts (or any other
 // child) based on its name -- only call this when new parts were created --
 // name of properties is #partname (lower cased) and it should contain a
@@ -328,7 +407,12 @@ func (wb *WidgetBase) Style2D() {
 
 // SetUnitContext sets the unit context based on size of viewport and parent
 // element (from bbox) and then cache everything out in terms of raw pixel
-// dots for rendering -- call at start of render
+// dots for rendering -- call at start of render.  If the parent element size
+// is not yet known (zero, prior to layout), percent units are relative to
+// the last known parent size, or the viewport size initially, and percent
+// sizes are then resolved against the parent's allocated size during layout
+// (see LayoutPctSizes).  Rem units are relative to the font size of the
+// window's top viewport.
 func SetUnitContext(st *gist.Style, vp *Viewport2D, el mat32.Vec2) {
 	if vp != nil {
 		if vp.Win != nil {
@@ -336,13 +420,33 @@ func SetUnitContext(st *gist.Style, vp *Viewport2D, el mat32.Vec2) {
 		}
 		if vp.Render.Image != nil {
 			sz := vp.Geom.Size // Render.Image.Bounds().Size()
+			if el.X == 0 && st.UnContext.ElW == 0 {
+				el.X = float32(sz.X)
+			}
+			if el.Y == 0 && st.UnContext.ElH == 0 {
+				el.Y = float32(sz.Y)
+			}
 			st.UnContext.SetSizes(float32(sz.X), float32(sz.Y), el.X, el.Y)
 		}
 	}
 	girl.OpenFont(&st.Font, &st.UnContext) // calls SetUnContext after updating metrics
+	if rem := RootFontDots(vp); rem > 0 {
+		st.Font.Rem = rem
+		st.UnContext.FontRem = rem
+	}
 	st.ToDots()
 }
 
+// RootFontDots returns the font size in dots of the root element for given
+// viewport, which is the top viewport of its window, for Rem units --
+// returns 0 if not yet styled.
+func RootFontDots(vp *Viewport2D) float32 {
+	if vp == nil || vp.Win == nil || vp.Win.Viewport == nil {
+		return 0
+	}
+	return vp.Win.Viewport.Sty.Font.Size.Dots
+}
+
 func (wb *WidgetBase) InitLayout2D() bool {
 	wb.StyMu.Lock()
 	defer wb.StyMu.Unlock()
@@ -465,15 +569,23 @@ func (wb *WidgetBase) PushBounds() bool {
 		return false
 	}
 	if !wb.This().(Node2D).IsVisible() {
+		wb.LifecycleHidden()
 		return false
 	}
 	if wb.VpBBox.Empty() {
 		wb.ClearFullReRender()
+		wb.LifecycleHidden()
 		return false
 	}
+	wb.LifecycleShown()
 	mvp := wb.ViewportSafe()
 	rs := &mvp.Render
 	rs.PushBounds(wb.VpBBox)
+	if len(wb.Filters) > 0 {
+		rs.Lock()
+		rs.PushFilterLayer(wb.VpBBox, wb.Filters, &wb.FilterCache)
+		rs.Unlock()
+	}
 	wb.ConnectToViewport()
 	if Render2DTrace {
 		fmt.Printf("Render: %v at %v\n", wb.Path(), wb.VpBBox)
@@ -493,6 +605,11 @@ func (wb *WidgetBase) PopBounds() {
 		return
 	}
 	rs := &mvp.Render
+	if len(wb.Filters) > 0 {
+		rs.Lock()
+		rs.PopLayer(1, gist.BlendNormal)
+		rs.Unlock()
+	}
 	rs.PopBounds()
 }
 
@@ -604,6 +721,14 @@ var TooltipFrameProps = ki.Props{
 
 // PopupTooltip pops up a viewport displaying the tooltip text
 func PopupTooltip(tooltip string, x, y int, parVp *Viewport2D, name string) *Viewport2D {
+	pvp := TooltipViewport(tooltip, x, y, parVp, name)
+	parVp.Win.PushPopup(pvp.This())
+	return pvp
+}
+
+// TooltipViewport returns a new tooltip viewport displaying the tooltip
+// text, for PopupTooltip, or other popups that look like tooltips
+func TooltipViewport(tooltip string, x, y int, parVp *Viewport2D, name string) *Viewport2D {
 	win := parVp.Win
 	mainVp := win.Viewport
 	pvp := Viewport2D{}
@@ -640,8 +765,6 @@ func PopupTooltip(tooltip string, x, y int, parVp *Viewport2D, name string) *Vie
 	pvp.Resize(vpsz)
 	pvp.Geom.Pos = image.Point{x, y}
 	pvp.UpdateEndNoSig(updt)
-
-	win.PushPopup(pvp.This())
 	return &pvp
 }
 
@@ -667,6 +790,22 @@ const (
 	// EmitContextMenuSignal)
 	WidgetContextMenu
 
+	// WidgetMouseEnter is triggered when the mouse enters the widget, which
+	// is paired with a subsequent WidgetMouseLeave (see
+	// EventMgr.GenMouseFocusEvents)
+	WidgetMouseEnter
+
+	// WidgetMouseLeave is triggered when the mouse leaves the widget, or the
+	// widget is no longer under the mouse because it was scrolled or hidden,
+	// or covered by a popup
+	WidgetMouseLeave
+
+	// WidgetSelectionChanged is triggered by item views (SliceView,
+	// TableView, TreeView, IconView) after an action changes their
+	// SelectionModel -- the data is the SelectionChange, with the indexes of
+	// the items added to and removed from the selection
+	WidgetSelectionChanged
+
 	WidgetSignalsN
 )
 
@@ -687,6 +826,17 @@ func (wb *WidgetBase) EmitContextMenuSignal() {
 	wb.WidgetSig.Emit(wb.This(), int64(WidgetContextMenu), nil)
 }
 
+// EmitSelectionChangedSignal emits the WidgetSelectionChanged signal for
+// this widget, with the changes taken from given selection model -- only
+// if there were any
+func (wb *WidgetBase) EmitSelectionChangedSignal(sm *SelectionModel) {
+	sc := sm.TakeChange()
+	if sc.IsEmpty() {
+		return
+	}
+	wb.WidgetSig.Emit(wb.This(), int64(WidgetSelectionChanged), sc)
+}
+
 // HoverTooltipEvent connects to HoverEvent and pops up a tooltip -- most
 // widgets should call this as part of their event connection method
 func (wb *WidgetBase) HoverTooltipEvent() {
@@ -776,11 +926,18 @@ func (wb *WidgetBase) RenderStdBox(st *gist.Style) {
 	wb.StyMu.RLock()
 	defer wb.StyMu.RUnlock()
 
-	rs := &wb.Viewport.Render
-	pc := &rs.Paint
-
 	pos := wb.LayState.Alloc.Pos.AddScalar(st.Layout.Margin.Dots)
 	sz := wb.LayState.Alloc.Size.AddScalar(-2.0 * st.Layout.Margin.Dots)
+	wb.RenderStdBoxGeom(st, pos, sz)
+}
+
+// RenderStdBoxGeom draws standard box using given style, at given position
+// and size, which must already exclude the margin -- for widgets that draw
+// other things outside of the box.
+// girl.State and Style must already be locked at this point (RenderLock)
+func (wb *WidgetBase) RenderStdBoxGeom(st *gist.Style, pos, sz mat32.Vec2) {
+	rs := &wb.Viewport.Render
+	pc := &rs.Paint
 	rad := st.Border.Radius.Dots
 
 	// first do any shadow
@@ -861,7 +1018,7 @@ var PartsWidgetBaseProps = ki.Props{
 func (wb *PartsWidgetBase) CopyFieldsFrom(frm any) {
 	fr, ok := frm.(*PartsWidgetBase)
 	if !ok {
-		log.Printf("GoGi node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier PartsWidgetBase one\n", wb.This().Name())
+		Logf(LogWarn, "gi.PartsWidgetBase", "node of type: %v needs a CopyFieldsFrom method defined -- currently falling back on earlier PartsWidgetBase one", wb.This().Name())
 		ki.GenCopyFieldsFrom(wb.This(), frm)
 		return
 	}
@@ -1012,6 +1169,34 @@ func (wb *PartsWidgetBase) PartsNeedUpdateIconLabel(icnm string, txt string) boo
 	return false
 }
 
+// FirstBaseline returns the offset of the first baseline of the "label"
+// part from the top of the widget, for given height -- see Baseliner.
+// The label is positioned within the parts according to its vertical-align
+// style, as in LayoutSharedDim.
+func (wb *PartsWidgetBase) FirstBaseline(height float32) float32 {
+	lblk := wb.Parts.ChildByName("label", 2)
+	if lblk == nil {
+		return -1
+	}
+	lbl := lblk.(*Label)
+	bl := lbl.FirstBaseline(lbl.LayState.Size.Pref.Y)
+	if bl < 0 {
+		return -1
+	}
+	spc := wb.BoxSpace() + wb.Parts.BoxSpace()
+	extra := mat32.Max(height-2*spc-lbl.LayState.Size.Pref.Y, 0)
+	lbl.StyMu.RLock()
+	al := lbl.Sty.Layout.AlignV
+	lbl.StyMu.RUnlock()
+	switch {
+	case gist.IsAlignMiddle(al):
+		spc += 0.5 * extra
+	case gist.IsAlignEnd(al):
+		spc += extra
+	}
+	return spc + bl
+}
+
 // SetFullReRenderIconLabel sets the icon and label to be re-rendered, needed
 // when styles change
 func (wb *PartsWidgetBase) SetFullReRenderIconLabel() {
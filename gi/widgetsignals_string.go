@@ -16,12 +16,15 @@ func _() {
 	_ = x[WidgetSelected-0]
 	_ = x[WidgetFocused-1]
 	_ = x[WidgetContextMenu-2]
-	_ = x[WidgetSignalsN-3]
+	_ = x[WidgetMouseEnter-3]
+	_ = x[WidgetMouseLeave-4]
+	_ = x[WidgetSelectionChanged-5]
+	_ = x[WidgetSignalsN-6]
 }
 
-const _WidgetSignals_name = "WidgetSelectedWidgetFocusedWidgetContextMenuWidgetSignalsN"
+const _WidgetSignals_name = "WidgetSelectedWidgetFocusedWidgetContextMenuWidgetMouseEnterWidgetMouseLeaveWidgetSelectionChangedWidgetSignalsN"
 
-var _WidgetSignals_index = [...]uint8{0, 14, 27, 44, 58}
+var _WidgetSignals_index = [...]uint8{0, 14, 27, 44, 60, 76, 98, 112}
 
 func (i WidgetSignals) String() string {
 	if i < 0 || i >= WidgetSignals(len(_WidgetSignals_index)-1) {
@@ -0,0 +1,101 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"time"
+
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/mouse"
+)
+
+// EventCoalesce determines whether consecutive mouse move, drag and scroll
+// events that are waiting in the window event queue are merged into a
+// single event before being dispatched, so that the GUI does not lag
+// behind the mouse by processing every intermediate event under load.
+var EventCoalesce = true
+
+// EventCoalesceMax is the maximum number of queued events that are merged
+// into one event by CoalesceEvents
+var EventCoalesceMax = 100
+
+// CoalesceEvents merges the events of the same kind that immediately follow
+// the given event in the window event queue into it, and returns the merged
+// event: mouse scroll deltas are summed, and mouse moves and drags keep the
+// From position of the first event and the position of the last one.
+// Events only merge when their buttons and modifiers are the same.
+// The first following event that does not merge is put back at the front
+// of the queue.  Other kinds of events are returned as is.
+func (w *Window) CoalesceEvents(evi oswin.Event) oswin.Event {
+	if !EventCoalesce {
+		return evi
+	}
+	switch evi.Type() {
+	case oswin.MouseMoveEvent, oswin.MouseDragEvent, oswin.MouseScrollEvent:
+	default:
+		return evi
+	}
+	for i := 0; i < EventCoalesceMax; i++ {
+		nxt, has := w.OSWin.PollEvent()
+		if !has {
+			break
+		}
+		mrg := MergeEvents(evi, nxt)
+		if mrg == nil {
+			w.OSWin.SendFirst(nxt)
+			break
+		}
+		evi = mrg
+	}
+	return evi
+}
+
+// MergeEvents returns the merger of the given event and the next event that
+// follows it, for mouse move, drag and scroll events with the same buttons
+// and modifiers, or nil if they cannot be merged (see CoalesceEvents).
+func MergeEvents(evi, nxt oswin.Event) oswin.Event {
+	if evi.Type() != nxt.Type() {
+		return nil
+	}
+	switch ev := evi.(type) {
+	case *mouse.ScrollEvent:
+		ne := nxt.(*mouse.ScrollEvent)
+		if !sameMouseState(&ev.Event, &ne.Event) {
+			return nil
+		}
+		ne.Delta = ne.Delta.Add(ev.Delta)
+		return ne
+	case *mouse.DragEvent:
+		ne := nxt.(*mouse.DragEvent)
+		if !sameMouseState(&ev.Event, &ne.Event) || ne.Start != ev.Start {
+			return nil
+		}
+		ne.From = ev.From
+		ne.LastTime = ev.LastTime
+		return ne
+	case *mouse.MoveEvent:
+		ne := nxt.(*mouse.MoveEvent)
+		if !sameMouseState(&ev.Event, &ne.Event) {
+			return nil
+		}
+		ne.From = ev.From
+		ne.LastTime = ev.LastTime
+		return ne
+	}
+	return nil
+}
+
+// sameMouseState returns true if the mouse events have the same button,
+// action and modifiers
+func sameMouseState(ev, ne *mouse.Event) bool {
+	return ev.Button == ne.Button && ev.Action == ne.Action && ev.Modifiers == ne.Modifiers
+}
+
+// IsHoverStale returns true if a hover event should be dropped because the
+// event loop is lagging behind the events: the mouse has likely moved
+// away already, but the move events have not yet been processed
+func (em *EventMgr) IsHoverStale() bool {
+	return em.LastLag > time.Duration(EventSkipLagMSec)*time.Millisecond
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/goki/gi/colormap"
+	"github.com/goki/gi/girl"
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/oswin/cursor"
@@ -26,6 +27,7 @@ import (
 	"github.com/goki/gi/oswin/key"
 	"github.com/goki/gi/oswin/mimedata"
 	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/oswin/touch"
 	"github.com/goki/gi/oswin/window"
 	"github.com/goki/ki/bitflag"
 	"github.com/goki/ki/ki"
@@ -51,6 +53,7 @@ var (
 
 	// DragStartPix is the number of pixels that must be moved before
 	// initiating a regular mouse drag event (as opposed to a basic mouse.Press)
+	// -- set from Prefs.Params
 	DragStartPix = 4
 
 	// DNDStartMSec is the number of milliseconds to wait before initiating a
@@ -62,7 +65,7 @@ var (
 	DNDStartPix = 20
 
 	// HoverStartMSec is the number of milliseconds to wait before initiating a
-	// hover event (e.g., for opening a tooltip)
+	// hover event (e.g., for opening a tooltip) -- set from Prefs.Params
 	HoverStartMSec = 1000
 
 	// HoverMaxPix is the maximum number of pixels that mouse can move and still
@@ -170,23 +173,28 @@ const (
 //     unlimited number packed into a few descriptors for standard sizes.
 type Window struct {
 	NodeBase
-	Title             string       `desc:"displayed name of window, for window manager etc -- window object name is the internal handle and is used for tracking property info etc"`
-	Data              any          `json:"-" xml:"-" view:"-" desc:"the main data element represented by this window -- used for Recycle* methods for windows that represent a given data element -- prevents redundant windows"`
-	OSWin             oswin.Window `json:"-" xml:"-" desc:"OS-specific window interface -- handles all the os-specific functions, including delivering events etc"`
-	EventMgr          EventMgr     `json:"-" xml:"-" desc:"event manager that handles dispersing events to nodes"`
-	Viewport          *Viewport2D  `json:"-" xml:"-" desc:"convenience pointer to window's master viewport child that handles the rendering"`
-	MasterVLay        *Layout      `json:"-" xml:"-" desc:"main vertical layout under Viewport -- first element is MainMenu (always -- leave empty to not render)"`
-	MainMenu          *MenuBar     `json:"-" xml:"-" desc:"main menu -- is first element of MasterVLay always -- leave empty to not render.  On MacOS, this drives screen main menu"`
-	Sprites           Sprites      `json:"-" xml:"-" desc:"sprites are named images that are rendered last overlaying everything else."`
-	SpriteDragging    string       `json:"-" xml:"-" desc:"name of sprite that is being dragged -- sprite event function is responsible for setting this."`
-	UpMu              sync.Mutex   `json:"-" xml:"-" view:"-" desc:"mutex that protects all updating / uploading of Textures"`
-	Shortcuts         Shortcuts    `json:"-" xml:"-" desc:"currently active shortcuts for this window (shortcuts are always window-wide -- use widget key event processing for more local key functions)"`
-	Popup             ki.Ki        `json:"-" xml:"-" desc:"Current popup viewport that gets all events"`
-	PopupStack        []ki.Ki      `json:"-" xml:"-" desc:"stack of popups"`
-	NextPopup         ki.Ki        `json:"-" xml:"-" desc:"this popup will be pushed at the end of the current event cycle -- use SetNextPopup"`
-	PopupFocus        ki.Ki        `json:"-" xml:"-" desc:"node to focus on when next popup is activated -- use SetNextPopup"`
-	DelPopup          ki.Ki        `json:"-" xml:"-" desc:"this popup will be popped at the end of the current event cycle -- use SetDelPopup"`
-	PopMu             sync.RWMutex `json:"-" xml:"-" view:"-" desc:"read-write mutex that protects popup updating and access"`
+	Title             string          `desc:"displayed name of window, for window manager etc -- window object name is the internal handle and is used for tracking property info etc"`
+	Data              any             `json:"-" xml:"-" view:"-" desc:"the main data element represented by this window -- used for Recycle* methods for windows that represent a given data element -- prevents redundant windows"`
+	OSWin             oswin.Window    `json:"-" xml:"-" desc:"OS-specific window interface -- handles all the os-specific functions, including delivering events etc"`
+	EventMgr          EventMgr        `json:"-" xml:"-" desc:"event manager that handles dispersing events to nodes"`
+	Viewport          *Viewport2D     `json:"-" xml:"-" desc:"convenience pointer to window's master viewport child that handles the rendering"`
+	MasterVLay        *Layout         `json:"-" xml:"-" desc:"main vertical layout under Viewport -- first element is MainMenu (always -- leave empty to not render)"`
+	MainMenu          *MenuBar        `json:"-" xml:"-" desc:"main menu -- is first element of MasterVLay always -- leave empty to not render.  On MacOS, this drives screen main menu"`
+	Sprites           Sprites         `json:"-" xml:"-" desc:"sprites are named images that are rendered last overlaying everything else."`
+	SpriteDragging    string          `json:"-" xml:"-" desc:"name of sprite that is being dragged -- sprite event function is responsible for setting this."`
+	UpMu              sync.Mutex      `json:"-" xml:"-" view:"-" desc:"mutex that protects all updating / uploading of Textures"`
+	Shortcuts         Shortcuts       `json:"-" xml:"-" desc:"currently active shortcuts for this window (shortcuts are always window-wide -- use widget key event processing for more local key functions)"`
+	Mnemonics         []*ButtonBase   `json:"-" xml:"-" desc:"buttons in the current scene whose mnemonics (access keys) are shown underlined, while the Alt key is held down -- nil if not shown"`
+	Dirty             []DirtyProvider `json:"-" xml:"-" view:"-" desc:"providers of unsaved changes shown in the window, e.g., documents, whose changes must be saved or discarded before closing it -- use AddDirty"`
+	Popup             ki.Ki           `json:"-" xml:"-" desc:"Current popup viewport that gets all events"`
+	PopupStack        []ki.Ki         `json:"-" xml:"-" desc:"stack of popups"`
+	NextPopup         ki.Ki           `json:"-" xml:"-" desc:"this popup will be pushed at the end of the current event cycle -- use SetNextPopup"`
+	PopupFocus        ki.Ki           `json:"-" xml:"-" desc:"node to focus on when next popup is activated -- use SetNextPopup"`
+	DelPopup          ki.Ki           `json:"-" xml:"-" desc:"this popup will be popped at the end of the current event cycle -- use SetDelPopup"`
+	PopMu             sync.RWMutex    `json:"-" xml:"-" view:"-" desc:"read-write mutex that protects popup updating and access"`
+	Anchors           []*Anchor       `json:"-" xml:"-" view:"-" desc:"popups attached to widgets, which are moved with them at each Publish -- use AnchorPopup -- protected by PopMu"`
+	Tasks             WinTasks        `json:"-" xml:"-" view:"-" desc:"functions scheduled to run on the event loop: at the next frame or when idle -- see RunOnNextFrame, RunWhenIdle"`
+	Frame             WinFrame        `json:"-" xml:"-" view:"-" desc:"frame pacing of publishing updates, and user activity for idle mode -- see PublishPaced, IsIdle"`
 	lastWinMenuUpdate time.Time
 	// below are internal vars used during the event loop
 	delPop        bool
@@ -249,6 +257,11 @@ const (
 	// WinFlagFocusActive indicates if widget focus is currently in an active state or not
 	WinFlagFocusActive
 
+	// WinFlagTouchInput indicates that touch input has been received in the
+	// window, so touch targets that are small for a mouse, e.g., splitter
+	// handles, are made larger
+	WinFlagTouchInput
+
 	WinFlagsN
 )
 
@@ -263,6 +276,11 @@ func (w *Window) IsClosing() bool {
 	return w.HasFlag(int(WinFlagIsClosing))
 }
 
+// IsTouchInput returns true if touch input has been received in the window
+func (w *Window) IsTouchInput() bool {
+	return w.HasFlag(int(WinFlagTouchInput))
+}
+
 // IsFocusActive returns true if window has focus active flag set
 func (w *Window) IsFocusActive() bool {
 	return w.HasFlag(int(WinFlagFocusActive))
@@ -297,10 +315,19 @@ func SetAppAbout(about string) {
 // SetQuitReqFunc sets the function that is called whenever there is a
 // request to quit the app (via a OS or a call to QuitReq() method).  That
 // function can then adjudicate whether and when to actually call Quit.
+// It is only called once the unsaved changes of all windows have been
+// saved or discarded (see QuitReqDirty).
 func SetQuitReqFunc(fun func()) {
-	oswin.TheApp.SetQuitReqFunc(fun)
+	quitReqFuncSet = true
+	oswin.TheApp.SetQuitReqFunc(func() {
+		QuitReqDirty(fun)
+	})
 }
 
+// quitReqFuncSet is set when SetQuitReqFunc has been called, so the
+// default is not set in Init
+var quitReqFuncSet = false
+
 // SetQuitCleanFunc sets the function that is called whenever app is
 // actually about to quit (irrevocably) -- can do any necessary
 // last-minute cleanup here.
@@ -310,6 +337,8 @@ func SetQuitCleanFunc(fun func()) {
 
 // Quit closes all windows and exits the program.
 func Quit() {
+	CloseSingleInstance()
+	StopAutomation()
 	if !oswin.TheApp.IsQuitting() {
 		oswin.TheApp.Quit()
 	}
@@ -349,6 +378,9 @@ func NewWindow(name, title string, opts *oswin.NewWindowOptions) *Window {
 	}
 	win.OSWin.SetName(title)
 	win.OSWin.SetParent(win.This())
+	win.OSWin.SetCloseReqFunc(func(owin oswin.Window) {
+		win.CloseReqDirty(win.Close)
+	})
 	win.NodeSig.Connect(win.This(), SignalWindowPublish)
 	drw := win.OSWin.Drawer()
 	drw.SetMaxTextures(vgpu.MaxTexturesPerSet * 3) // use 3 sets
@@ -617,7 +649,7 @@ func (w *Window) SetName(name string) {
 			WinGeomMgr.SettingStart()
 			if w.OSWin.Size() != wgp.Size() || w.OSWin.Position() != wgp.Pos() {
 				if WinGeomTrace {
-					log.Printf("WinGeomPrefs: SetName setting geom for window: %v pos: %v size: %v\n", w.Name(), wgp.Pos(), wgp.Size())
+					Logf(LogInfo, "gi.WinGeomPrefs", "SetName setting geom for window: %v pos: %v size: %v", w.Name(), wgp.Pos(), wgp.Size())
 				}
 				w.OSWin.SetGeom(wgp.Pos(), wgp.Size())
 				oswin.TheApp.SendEmptyEvent()
@@ -750,7 +782,7 @@ func (w *Window) Resized(sz image.Point) {
 	}
 	w.Viewport.Resize(sz)
 	if WinGeomTrace {
-		log.Printf("WinGeomPrefs: recording from Resize\n")
+		Logf(LogInfo, "gi.WinGeomPrefs", "recording from Resize")
 	}
 	WinGeomMgr.RecordPref(w)
 	w.UpMu.Unlock()
@@ -831,6 +863,9 @@ func (w *Window) Closed() {
 	// these are managed by the window itself
 	w.Sprites.Reset()
 	w.UpMu.Unlock()
+	if len(MainWindows) == 0 {
+		SaveIconCache()
+	}
 }
 
 // IsClosed reports if the window has been closed
@@ -844,9 +879,13 @@ func (w *Window) IsClosed() bool {
 // SetCloseReqFunc sets the function that is called whenever there is a
 // request to close the window (via a OS or a call to CloseReq() method).  That
 // function can then adjudicate whether and when to actually call Close.
+// It is only called once the unsaved changes of the window have been
+// saved or discarded (see CloseReqDirty).
 func (w *Window) SetCloseReqFunc(fun func(win *Window)) {
 	w.OSWin.SetCloseReqFunc(func(owin oswin.Window) {
-		fun(w)
+		w.CloseReqDirty(func() {
+			fun(w)
+		})
 	})
 }
 
@@ -891,15 +930,25 @@ func (w *Window) NeedWinMenuUpdate() bool {
 // then if pref info needed.
 func Init() {
 	if Prefs.LogicalDPIScale == 0 {
+		done := StartupStep("Init (prefs and fonts)")
+		girl.FontCacheFile = filepath.Join(oswin.TheApp.GoGiPrefsDir(), FontCacheFileName)
+		girl.FontCacheVersion = Version
 		Prefs.Defaults()
 		PrefsDet.Defaults()
 		PrefsDbg.Connect()
 		Prefs.Open()
 		Prefs.Apply()
 		oswin.InitScreenLogicalDPIFunc = Prefs.ApplyDPI // called when screens are initialized
+		if !quitReqFuncSet {
+			oswin.TheApp.SetQuitReqFunc(func() { QuitReqDirty(Quit) })
+		}
 		TheViewIFace.HiStyleInit()
 		WinGeomMgr.NeedToReload() // gets time stamp associated with open, so it doesn't re-open
 		WinGeomMgr.Open()
+		done()
+		if PreloadOn {
+			Preload()
+		}
 	}
 }
 
@@ -1184,6 +1233,7 @@ func (w *Window) Publish() {
 		}
 		return
 	}
+	w.UpdateAnchors()   // before UpMu, as it needs PopMu first
 	w.UpMu.Lock()       // block all updates while we publish
 	if !w.IsVisible() { // could have closed while we waited for lock
 		if WinEventTrace {
@@ -1278,6 +1328,7 @@ func (w *Window) Publish() {
 
 	w.ClearWinUpdating()
 	w.UpMu.Unlock()
+	startupFrame(w)
 }
 
 // SignalWindowPublish is the signal receiver function that publishes the
@@ -1293,7 +1344,7 @@ func SignalWindowPublish(winki, node ki.Ki, sig int64, data any) {
 		}
 		return
 	}
-	win.Publish()
+	win.PublishPaced()
 }
 
 // AddDirectUploader adds given node to those that have a DirectWinUpload method
@@ -1537,7 +1588,11 @@ func (w *Window) PollEvents() {
 		if !has {
 			break
 		}
-		w.ProcessEvent(evi)
+		evi = w.CoalesceEvents(evi)
+		w.ProcessEventRecover(evi)
+	}
+	if w.Tasks.HasFrameTasks() {
+		w.RunRecover(w.RunFrameTasks)
 	}
 }
 
@@ -1550,13 +1605,24 @@ func (w *Window) EventLoop() {
 			w.ClearFlag(int(WinFlagStopEventLoop))
 			break
 		}
-		evi := w.OSWin.NextEvent()
+		evi, has := w.OSWin.PollEvent()
+		if !has {
+			if w.Tasks.HasIdleTasks() {
+				w.RunRecover(w.RunIdleTasks) // wakes us up again if not done
+			}
+			evi = w.OSWin.NextEvent()
+		}
 		if w.HasFlag(int(WinFlagStopEventLoop)) {
 			w.ClearFlag(int(WinFlagStopEventLoop))
 			break
 		}
-		w.ProcessEvent(evi)
+		evi = w.CoalesceEvents(evi)
+		w.ProcessEventRecover(evi)
+		if w.Tasks.HasFrameTasks() {
+			w.RunRecover(w.RunFrameTasks)
+		}
 	}
+	w.Tasks.Reset()
 	if WinEventTrace {
 		fmt.Printf("Win: %v out of event loop\n", w.Nm)
 	}
@@ -1575,6 +1641,7 @@ func (w *Window) ProcessEvent(evi oswin.Event) {
 		fmt.Printf("Win: %v got out-of-range event: %v\n", w.Nm, et)
 		return
 	}
+	startPop := w.CurPopup()
 
 	{ // popup delete check
 		w.PopMu.RLock()
@@ -1598,6 +1665,17 @@ func (w *Window) ProcessEvent(evi oswin.Event) {
 	}
 	w.EventMgr.LagLastSkipped = false
 	w.lastEt = et
+	w.EventMgr.TimerMu.Lock()
+	w.EventMgr.LastLag = time.Since(evi.Time())
+	w.EventMgr.TimerMu.Unlock()
+	if IsActivityEvent(et) {
+		w.MarkActivity()
+	}
+
+	if rec := w.EventMgr.Rec; rec != nil {
+		rec.Start(evi)
+		defer func() { rec.Finish(evi, w.EventMgr.CurFocus()) }()
+	}
 
 	if w.skippedResize != nil {
 		w.Viewport.BBoxMu.RLock()
@@ -1616,6 +1694,10 @@ func (w *Window) ProcessEvent(evi oswin.Event) {
 
 	w.EventMgr.MouseEvents(evi)
 
+	if w.EyedropperEvent(evi) {
+		return
+	}
+
 	if !w.HiPriorityEvents(evi) {
 		return
 	}
@@ -1648,7 +1730,7 @@ func (w *Window) ProcessEvent(evi oswin.Event) {
 	if !evi.IsProcessed() && et == oswin.KeyChordEvent {
 		ke := evi.(*key.ChordEvent)
 		kc := ke.Chord()
-		if w.TriggerShortcut(kc) {
+		if w.TriggerShortcut(kc) || w.TriggerMnemonic(ke) {
 			evi.SetProcessed()
 		}
 	}
@@ -1711,6 +1793,11 @@ func (w *Window) ProcessEvent(evi oswin.Event) {
 	if npop != nil {
 		w.PushPopup(npop)
 	}
+
+	// content under the mouse can change without it moving
+	if et == oswin.MouseScrollEvent || w.CurPopup() != startPop {
+		w.EventMgr.UpdateMouseFocus(!w.CurPopupIsTooltip())
+	}
 }
 
 // FilterEvent filters repeated laggy events -- key for responsive resize, scroll, etc
@@ -1800,7 +1887,7 @@ func (w *Window) HiPriorityEvents(evi oswin.Event) bool {
 			if w.HasFlag(int(WinFlagGotPaint)) { // moves before paint are not accurate on X11
 				// fmt.Printf("win move: %v\n", w.OSWin.Position())
 				if WinGeomTrace {
-					log.Printf("WinGeomPrefs: recording from Move\n")
+					Logf(LogInfo, "gi.WinGeomPrefs", "recording from Move")
 				}
 				WinGeomMgr.RecordPref(w)
 			}
@@ -1820,11 +1907,18 @@ func (w *Window) HiPriorityEvents(evi oswin.Event) bool {
 					fmt.Printf("Win: %v got extra focus\n", w.Nm)
 				}
 			}
+		case window.Minimize:
+			if w.OSWin.IsMinimized() {
+				LifecycleHideTree(w.Viewport)
+			} else {
+				LifecycleShowTree(w.Viewport)
+			}
 		case window.DeFocus:
 			if WinEventTrace {
 				fmt.Printf("Win: %v lost focus\n", w.Nm)
 			}
 			w.ClearFlag(int(WinFlagGotFocus))
+			w.ShowMnemonics(false)
 			w.SendWinFocusEvent(window.DeFocus)
 		case window.ScreenUpdate:
 			WinGeomMgr.AbortSave() // anything just prior to this is sus
@@ -1834,6 +1928,8 @@ func (w *Window) HiPriorityEvents(evi oswin.Event) bool {
 			}
 		}
 		return false // don't do anything else!
+	case *touch.Event:
+		w.SetFlag(int(WinFlagTouchInput))
 	case *mouse.DragEvent:
 		if w.EventMgr.DNDStage == DNDStarted {
 			w.DNDMoveEvent(e)
@@ -1873,6 +1969,10 @@ func (w *Window) HiPriorityEvents(evi oswin.Event) bool {
 		if e.Action == dnd.External {
 			w.EventMgr.DNDDropMod = e.Mod
 		}
+	case *key.Event:
+		w.MnemonicKeyEvent(e)
+	case *key.LayoutEvent:
+		w.KeyLayoutChanged(e)
 	case *key.ChordEvent:
 		keyDelPop := w.KeyChordEventHiPri(e)
 		if keyDelPop {
@@ -1937,6 +2037,30 @@ func (w *Window) IsInScope(k ki.Ki, popup bool) bool {
 	return !popup
 }
 
+// KeyLayoutChanged updates the window for a change of the keyboard layout:
+// the shortcuts shown in an open menu are updated to show the keys of the
+// new layout -- the key.LayoutEvent is then sent on to the widgets that
+// connect to it (oswin.KeyLayoutEvent), e.g., to adapt keymaps
+func (w *Window) KeyLayoutChanged(e *key.LayoutEvent) {
+	if KeyEventTrace {
+		fmt.Printf("Win: %v keyboard layout: %v\n", w.Nm, e.Layout)
+	}
+	pop := w.CurPopup()
+	if !PopupIsMenu(pop) {
+		return
+	}
+	vp := pop.(Node2D).AsViewport2D()
+	updt := vp.UpdateStart()
+	vp.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		if ac, ok := k.(*Action); ok && ac.Shortcut != "" {
+			ac.ConfigParts()
+		}
+		return ki.Continue
+	})
+	vp.SetFullReRender()
+	vp.UpdateEnd(updt)
+}
+
 // AddShortcut adds given shortcut to given action.
 func (w *Window) AddShortcut(chord key.Chord, act *Action) {
 	if chord == "" {
@@ -1948,7 +2072,7 @@ func (w *Window) AddShortcut(chord key.Chord, act *Action) {
 	sa, exists := w.Shortcuts[chord]
 	if exists && sa != act && sa.Text != act.Text {
 		if KeyEventTrace {
-			log.Printf("gi.Window shortcut: %v already exists on action: %v -- will be overwritten with action: %v\n", chord, sa.Text, act.Text)
+			Logf(LogWarn, "gi.Window", "shortcut: %v already exists on action: %v -- will be overwritten with action: %v", chord, sa.Text, act.Text)
 		}
 	}
 	w.Shortcuts[chord] = act
@@ -2546,6 +2670,7 @@ func StartTargProfile() {
 // EndTargProfile ends targeted profiling and prints report.
 func EndTargProfile() {
 	prof.Report(time.Millisecond)
+	fmt.Printf("\nStartup times:\n%s", StartupReport())
 	prof.Profiling = false
 }
 
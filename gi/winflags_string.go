@@ -24,12 +24,13 @@ func _() {
 	_ = x[WinFlagStopEventLoop-32]
 	_ = x[WinFlagDoFullRender-33]
 	_ = x[WinFlagFocusActive-34]
-	_ = x[WinFlagsN-35]
+	_ = x[WinFlagTouchInput-35]
+	_ = x[WinFlagsN-36]
 }
 
-const _WinFlags_name = "WinFlagHasGeomPrefsWinFlagUpdatingWinFlagIsClosingWinFlagIsResizingWinFlagGotPaintWinFlagGotFocusWinFlagSentShowWinFlagGoLoopWinFlagStopEventLoopWinFlagDoFullRenderWinFlagFocusActiveWinFlagsN"
+const _WinFlags_name = "WinFlagHasGeomPrefsWinFlagUpdatingWinFlagIsClosingWinFlagIsResizingWinFlagGotPaintWinFlagGotFocusWinFlagSentShowWinFlagGoLoopWinFlagStopEventLoopWinFlagDoFullRenderWinFlagFocusActiveWinFlagTouchInputWinFlagsN"
 
-var _WinFlags_index = [...]uint8{0, 19, 34, 50, 67, 82, 97, 112, 125, 145, 164, 182, 191}
+var _WinFlags_index = [...]uint8{0, 19, 34, 50, 67, 82, 97, 112, 125, 145, 164, 182, 199, 208}
 
 func (i WinFlags) String() string {
 	i -= 24
@@ -0,0 +1,170 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goki/gi/oswin"
+)
+
+// WinRefreshPacing, if true, paces the publishing of window updates to the
+// refresh rate of the screen the window is on, in software: updates
+// arriving faster than that are coalesced into one publish at the next
+// refresh interval.  If false, updates are published as soon as they happen
+// (subject to WinMaxFPS).  This is not vsync: the vulkan swapchain always
+// uses the FIFO present mode, so frames are never torn either way -- pacing
+// just avoids rendering frames that would never be shown.  Set from
+// Prefs.Params.RefreshPacing.
+var WinRefreshPacing = true
+
+// WinMaxFPS is the maximum number of frames per second that a window
+// publishes updates at, with additional updates coalesced as for WinRefreshPacing
+// -- 0 = no limit.  Set from Prefs.Params.MaxFPS.
+var WinMaxFPS = 0
+
+// WinIdleTimeout is the amount of time without any user input after which a
+// window becomes idle -- in idle mode, nothing is painted unless something
+// actually changes: cursor blinking and other purely decorative animations
+// stop, along with their timers, until the next input event wakes the
+// window up again (see AddIdleWakeFunc).
+// 0 = never idle.  Set from Prefs.Params.IdleSecs.
+var WinIdleTimeout = 30 * time.Second
+
+// WinFrame manages the pacing of publishing updates to the window, and
+// tracks user activity for the idle mode -- see WinRefreshPacing, WinMaxFPS,
+// WinIdleTimeout.
+type WinFrame struct {
+	Mu           sync.Mutex `desc:"mutex protecting the frame state"`
+	LastPublish  time.Time  `desc:"time of the last publish"`
+	Pending      bool       `desc:"whether a deferred publish is scheduled for the end of the current frame interval"`
+	LastActivity time.Time  `desc:"time of the last user input event"`
+}
+
+// FrameInterval returns the minimum interval between publishes of the
+// window, from WinRefreshPacing and WinMaxFPS -- 0 if not limited
+func (w *Window) FrameInterval() time.Duration {
+	var iv time.Duration
+	if WinRefreshPacing && w.OSWin != nil {
+		if sc := w.OSWin.Screen(); sc != nil && sc.RefreshRate > 0 {
+			iv = time.Duration(float64(time.Second) / float64(sc.RefreshRate))
+		}
+	}
+	if WinMaxFPS > 0 {
+		fiv := time.Second / time.Duration(WinMaxFPS)
+		if fiv > iv {
+			iv = fiv
+		}
+	}
+	return iv
+}
+
+// PublishPaced publishes the window updates subject to the frame interval
+// (see FrameInterval): if the last publish was less than the interval ago,
+// the publish is deferred until the end of the interval, when it is run on
+// the event loop of the window, and any further calls until then are
+// coalesced into it.
+func (w *Window) PublishPaced() {
+	iv := w.FrameInterval()
+	if iv == 0 {
+		w.Publish()
+		w.Frame.Mu.Lock()
+		w.Frame.LastPublish = time.Now()
+		w.Frame.Mu.Unlock()
+		return
+	}
+	w.Frame.Mu.Lock()
+	if w.Frame.Pending {
+		w.Frame.Mu.Unlock()
+		return
+	}
+	since := time.Since(w.Frame.LastPublish)
+	if since >= iv {
+		w.Frame.LastPublish = time.Now()
+		w.Frame.Mu.Unlock()
+		w.Publish()
+		return
+	}
+	w.Frame.Pending = true
+	w.Frame.Mu.Unlock()
+	time.AfterFunc(iv-since, func() {
+		w.RunOnNextFrame(func() {
+			w.Frame.Mu.Lock()
+			w.Frame.Pending = false
+			w.Frame.LastPublish = time.Now()
+			w.Frame.Mu.Unlock()
+			if w.IsClosed() || !w.IsVisible() {
+				return
+			}
+			w.Publish()
+		})
+	})
+}
+
+// IsIdle returns true if the window is in idle mode, because there has been
+// no user input for WinIdleTimeout -- widgets should not render purely
+// decorative animations (e.g., cursor blinking) when idle
+func (w *Window) IsIdle() bool {
+	w.Frame.Mu.Lock()
+	defer w.Frame.Mu.Unlock()
+	return w.Frame.isIdle()
+}
+
+// isIdle returns true if in idle mode -- Mu must be locked
+func (wf *WinFrame) isIdle() bool {
+	if WinIdleTimeout <= 0 || wf.LastActivity.IsZero() {
+		return false
+	}
+	return time.Since(wf.LastActivity) > WinIdleTimeout
+}
+
+// MarkActivity records user activity in the window, which wakes it up from
+// idle mode, calling the idle wake functions if it was idle (see
+// AddIdleWakeFunc) -- called by the event loop for user input events
+func (w *Window) MarkActivity() {
+	w.Frame.Mu.Lock()
+	wasIdle := w.Frame.isIdle()
+	w.Frame.LastActivity = time.Now()
+	w.Frame.Mu.Unlock()
+	if wasIdle {
+		idleWakeMu.Lock()
+		funs := idleWakeFuncs
+		idleWakeMu.Unlock()
+		for _, fun := range funs {
+			fun()
+		}
+	}
+}
+
+var (
+	// idleWakeFuncs are the functions called when a window wakes up from
+	// idle mode
+	idleWakeFuncs []func()
+
+	// idleWakeMu protects idleWakeFuncs
+	idleWakeMu sync.Mutex
+)
+
+// AddIdleWakeFunc adds a function that is called (on the event loop of the
+// window) when a window wakes up from idle mode -- timers of decorative
+// animations (e.g., cursor blinking) should be stopped when the window is
+// idle, so they do not keep waking up the CPU, and restarted by this.
+func AddIdleWakeFunc(fun func()) {
+	idleWakeMu.Lock()
+	idleWakeFuncs = append(idleWakeFuncs, fun)
+	idleWakeMu.Unlock()
+}
+
+// IsActivityEvent returns true if given event type represents user activity
+// for the purposes of idle mode, i.e., everything except paint, hover and
+// custom events, which are generated internally
+func IsActivityEvent(et oswin.EventType) bool {
+	switch et {
+	case oswin.WindowPaintEvent, oswin.MouseHoverEvent, oswin.CustomEventType:
+		return false
+	}
+	return true
+}
@@ -9,7 +9,6 @@ import (
 	"errors"
 	"image"
 	"io/ioutil"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -153,7 +152,7 @@ func (mgr *WinGeomPrefsMgr) Open() error {
 	}
 	err = json.Unmarshal(b, &mgr.Geoms)
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.WinGeomPrefs", "Open: %v", err)
 	}
 	oldFmt := false
 	for _, wps := range mgr.Geoms {
@@ -166,7 +165,7 @@ func (mgr *WinGeomPrefsMgr) Open() error {
 		break
 	}
 	if oldFmt {
-		log.Printf("WinGeomPrefs: resetting prefs for new format\n")
+		Logf(LogInfo, "gi.WinGeomPrefs", "resetting prefs for new format")
 		mgr.Geoms = make(WinGeomPrefs, 1000)
 		mgr.Save() // overwrite
 	}
@@ -183,12 +182,12 @@ func (mgr *WinGeomPrefsMgr) Save() error {
 	pnm := filepath.Join(pdir, mgr.FileName+".json")
 	b, err := json.MarshalIndent(mgr.Geoms, "", "\t")
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.WinGeomPrefs", "Save: %v", err)
 		return err
 	}
 	err = ioutil.WriteFile(pnm, b, 0644)
 	if err != nil {
-		log.Println(err)
+		Logf(LogError, "gi.WinGeomPrefs", "Save: %v", err)
 	} else {
 		mgr.SaveLastSave()
 	}
@@ -228,21 +227,21 @@ func (mgr *WinGeomPrefsMgr) RecordPref(win *Window) {
 	wsz := win.OSWin.Size()
 	if wsz == image.ZP {
 		if WinGeomTrace {
-			log.Printf("WinGeomPrefs: RecordPref: NOT storing null size for win: %v\n", win.Nm)
+			Logf(LogInfo, "gi.WinGeomPrefs", "RecordPref: NOT storing null size for win: %v", win.Nm)
 		}
 		return
 	}
 	pos := win.OSWin.Position()
 	if pos.X == -32000 || pos.Y == -32000 { // windows badness
 		if WinGeomTrace {
-			log.Printf("WinGeomPrefs: RecordPref: NOT storing very negative pos: %v for win: %v\n", pos, win.Nm)
+			Logf(LogInfo, "gi.WinGeomPrefs", "RecordPref: NOT storing very negative pos: %v for win: %v", pos, win.Nm)
 		}
 		return
 	}
 	mgr.Mu.Lock()
 	if mgr.SettingNoSave {
 		if WinGeomTrace {
-			log.Printf("WinGeomPrefs: RecordPref: SettingNoSave so NOT storing for win: %v\n", win.Nm)
+			Logf(LogInfo, "gi.WinGeomPrefs", "RecordPref: SettingNoSave so NOT storing for win: %v", win.Nm)
 		}
 		mgr.Mu.Unlock()
 		return
@@ -280,14 +279,14 @@ func (mgr *WinGeomPrefsMgr) AbortSave() {
 		mgr.saveTimer = nil
 		if WinGeomTrace {
 			if len(mgr.Cache) == 0 {
-				log.Printf("WinGeomPrefs: AbortSave: no cached geoms but timer was != nil -- probably already saved\n")
+				Logf(LogInfo, "gi.WinGeomPrefs", "AbortSave: no cached geoms but timer was != nil -- probably already saved")
 			} else {
-				log.Printf("WinGeomPrefs: AbortSave: there are cached geoms -- aborted in time!\n")
+				Logf(LogInfo, "gi.WinGeomPrefs", "AbortSave: there are cached geoms -- aborted in time!")
 			}
 		}
 	} else {
 		if WinGeomTrace {
-			log.Printf("WinGeomPrefs: AbortSave: no saveTimer -- already happened or nothing to save\n")
+			Logf(LogInfo, "gi.WinGeomPrefs", "AbortSave: no saveTimer -- already happened or nothing to save")
 		}
 	}
 	mgr.ResetCache()
@@ -311,7 +310,7 @@ func (mgr *WinGeomPrefsMgr) SaveCached() {
 			}
 			mgr.Geoms[winName][sc.Name] = wgr
 			if WinGeomTrace {
-				log.Printf("WinGeomPrefs: RecordPref: Saving for window: %v pos: %v size: %v  screen: %v  dpi: %v  device pixel ratio: %v\n", winName, wgr.Pos(), wgr.Size(), sc.Name, sc.LogicalDPI, sc.DevicePixelRatio)
+				Logf(LogInfo, "gi.WinGeomPrefs", "RecordPref: Saving for window: %v pos: %v size: %v  screen: %v  dpi: %v  device pixel ratio: %v", winName, wgr.Pos(), wgr.Size(), sc.Name, sc.LogicalDPI, sc.DevicePixelRatio)
 			}
 		}
 	}
@@ -342,14 +341,14 @@ func (mgr *WinGeomPrefsMgr) Pref(winName string, scrn *oswin.Screen) *WindowGeom
 	if scrn == nil {
 		scrn = oswin.TheApp.Screen(0)
 		if WinGeomTrace {
-			log.Printf("WinGeomPrefs: Pref: scrn is nil, using scrn 0: %v\n", scrn.Name)
+			Logf(LogInfo, "gi.WinGeomPrefs", "Pref: scrn is nil, using scrn 0: %v", scrn.Name)
 		}
 	}
 	wp, ok := wps[scrn.Name]
 	if ok {
 		wp.ConstrainGeom(scrn)
 		if WinGeomTrace {
-			log.Printf("WinGeomPrefs: Pref: Setting geom for window: %v pos: %v size: %v  screen: %v  dpi: %v  device pixel ratio: %v\n", winName, wp.Pos(), wp.Size(), scrn.Name, scrn.LogicalDPI, scrn.DevicePixelRatio)
+			Logf(LogInfo, "gi.WinGeomPrefs", "Pref: Setting geom for window: %v pos: %v size: %v  screen: %v  dpi: %v  device pixel ratio: %v", winName, wp.Pos(), wp.Size(), scrn.Name, scrn.LogicalDPI, scrn.DevicePixelRatio)
 		}
 		return &wp
 	}
@@ -375,21 +374,21 @@ func (mgr *WinGeomPrefsMgr) RestoreAll() {
 	WindowGlobalMu.Lock()
 	defer WindowGlobalMu.Unlock()
 	if WinGeomTrace {
-		log.Printf("WinGeomPrefs: RestoreAll: starting\n")
+		Logf(LogInfo, "gi.WinGeomPrefs", "RestoreAll: starting")
 	}
 	mgr.SettingStart()
 	for _, w := range AllWindows {
 		wgp := mgr.Pref(w.Name(), w.OSWin.Screen())
 		if wgp != nil {
 			if WinGeomTrace {
-				log.Printf("WinGeomPrefs: RestoreAll: restoring geom for window: %v pos: %v size: %v\n", w.Name(), wgp.Pos(), wgp.Size())
+				Logf(LogInfo, "gi.WinGeomPrefs", "RestoreAll: restoring geom for window: %v pos: %v size: %v", w.Name(), wgp.Pos(), wgp.Size())
 			}
 			w.OSWin.SetGeom(wgp.Pos(), wgp.Size())
 		}
 	}
 	mgr.SettingEnd()
 	if WinGeomTrace {
-		log.Printf("WinGeomPrefs: RestoreAll: done\n")
+		Logf(LogInfo, "gi.WinGeomPrefs", "RestoreAll: done")
 	}
 }
 
@@ -0,0 +1,165 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"sync"
+	"time"
+)
+
+// IdleTaskDuration is the maximum amount of time that idle tasks are given
+// to run each time the window event loop becomes idle -- see RunWhenIdle.
+var IdleTaskDuration = 10 * time.Millisecond
+
+// IdleTaskInterval is the time from the end of one run of the idle tasks
+// to the next, while there are idle tasks that are not done and no events
+// -- the event loop sleeps in between, so tasks that are never done do not
+// keep the CPU busy.
+var IdleTaskInterval = 10 * time.Millisecond
+
+// IdleFunc is a function run by RunWhenIdle, which should return by the
+// given deadline, and return true when its work is done, or false to be
+// called again at the next idle time, to do more of its work
+type IdleFunc func(deadline time.Time) bool
+
+// WinTasks manages functions that are scheduled to run on the event loop
+// goroutine of a window: on the next frame, or when it is idle.  This
+// allows widgets to defer expensive work (e.g., re-sorting a big table)
+// without spawning goroutines that then have to coordinate with updating.
+type WinTasks struct {
+	Mu        sync.Mutex     `desc:"mutex protecting the tasks -- tasks can be added from any goroutine"`
+	NextFrame []func()       `desc:"functions to run at the next frame"`
+	Once      map[any]func() `desc:"coalesced functions to run at the next frame, keyed by RunOnNextFrameOnce key"`
+	OnceOrder []any          `desc:"order in which the Once keys were first added"`
+	Idle      []IdleFunc     `desc:"functions to run when the event loop is idle"`
+	IdleWake  *time.Timer    `desc:"timer that wakes the event loop for the next run of the idle tasks"`
+}
+
+// HasFrameTasks returns true if there are tasks for the next frame
+func (wt *WinTasks) HasFrameTasks() bool {
+	wt.Mu.Lock()
+	defer wt.Mu.Unlock()
+	return len(wt.NextFrame) > 0 || len(wt.OnceOrder) > 0
+}
+
+// HasIdleTasks returns true if there are tasks to run when idle
+func (wt *WinTasks) HasIdleTasks() bool {
+	wt.Mu.Lock()
+	defer wt.Mu.Unlock()
+	return len(wt.Idle) > 0
+}
+
+// Reset removes all the tasks
+func (wt *WinTasks) Reset() {
+	wt.Mu.Lock()
+	wt.NextFrame = nil
+	wt.Once = nil
+	wt.OnceOrder = nil
+	wt.Idle = nil
+	if wt.IdleWake != nil {
+		wt.IdleWake.Stop()
+	}
+	wt.Mu.Unlock()
+}
+
+// RunOnNextFrame schedules given function to run on the event loop goroutine
+// of the window, after the current event (if any) has been processed and
+// rendered -- can be called from any goroutine, including within such a
+// function, which then runs on the following frame.
+func (w *Window) RunOnNextFrame(fun func()) {
+	w.Tasks.Mu.Lock()
+	w.Tasks.NextFrame = append(w.Tasks.NextFrame, fun)
+	w.Tasks.Mu.Unlock()
+	w.wakeEventLoop()
+}
+
+// RunOnNextFrameOnce schedules given function to run on the next frame as in
+// RunOnNextFrame, coalescing all calls with the same key (e.g., the widget
+// and the name of the computation) until then into a single call of the last
+// function given.  This is for invalidation: when many changes each
+// require the same expensive recomputation, it is only done once.
+func (w *Window) RunOnNextFrameOnce(key any, fun func()) {
+	w.Tasks.Mu.Lock()
+	if w.Tasks.Once == nil {
+		w.Tasks.Once = make(map[any]func())
+	}
+	if _, has := w.Tasks.Once[key]; !has {
+		w.Tasks.OnceOrder = append(w.Tasks.OnceOrder, key)
+	}
+	w.Tasks.Once[key] = fun
+	w.Tasks.Mu.Unlock()
+	w.wakeEventLoop()
+}
+
+// RunWhenIdle schedules given function to run on the event loop goroutine
+// of the window when there are no events waiting to be processed.  It is
+// given a deadline (see IdleTaskDuration) by which it should return, and
+// is called again at the next idle time if it returns false, so long
+// computations can be done in pieces without blocking the GUI.
+func (w *Window) RunWhenIdle(fun IdleFunc) {
+	w.Tasks.Mu.Lock()
+	w.Tasks.Idle = append(w.Tasks.Idle, fun)
+	w.Tasks.Mu.Unlock()
+	w.wakeEventLoop()
+}
+
+// wakeEventLoop sends an empty event so the event loop runs the tasks
+func (w *Window) wakeEventLoop() {
+	if w.IsClosed() || w.OSWin == nil {
+		return
+	}
+	w.OSWin.SendEmptyEvent()
+}
+
+// RunFrameTasks runs the tasks scheduled for the next frame, in the order
+// they were added -- called by the event loop after each event
+func (w *Window) RunFrameTasks() {
+	w.Tasks.Mu.Lock()
+	nf := w.Tasks.NextFrame
+	once := w.Tasks.Once
+	order := w.Tasks.OnceOrder
+	w.Tasks.NextFrame = nil
+	w.Tasks.Once = nil
+	w.Tasks.OnceOrder = nil
+	w.Tasks.Mu.Unlock()
+	for _, fun := range nf {
+		fun()
+	}
+	for _, key := range order {
+		once[key]()
+	}
+}
+
+// RunIdleTasks runs the idle tasks, until they are done or the
+// IdleTaskDuration has passed -- the ones that are not done are run again
+// at the next idle time, after IdleTaskInterval.  Called by the event loop
+// when there are no events, which then waits for the next event.
+func (w *Window) RunIdleTasks() {
+	w.Tasks.Mu.Lock()
+	idle := w.Tasks.Idle
+	w.Tasks.Idle = nil
+	w.Tasks.Mu.Unlock()
+	deadline := time.Now().Add(IdleTaskDuration)
+	var remain []IdleFunc
+	for i, fun := range idle {
+		if time.Now().After(deadline) {
+			remain = append(remain, idle[i:]...)
+			break
+		}
+		if !fun(deadline) {
+			remain = append(remain, fun)
+		}
+	}
+	if len(remain) > 0 {
+		w.Tasks.Mu.Lock()
+		w.Tasks.Idle = append(remain, w.Tasks.Idle...)
+		if w.Tasks.IdleWake == nil {
+			w.Tasks.IdleWake = time.AfterFunc(IdleTaskInterval, w.wakeEventLoop)
+		} else {
+			w.Tasks.IdleWake.Reset(IdleTaskInterval)
+		}
+		w.Tasks.Mu.Unlock()
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/cursor"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+// ZoomPanArea
+
+// Zoomable is the interface for the content of a ZoomPanArea, which zooms
+// and pans the content by setting a transform that the content applies when
+// rendering -- implemented by Bitmap, svg.SVG, and Viewport2D-based canvases
+// can implement it by pushing the transform when they render.
+type Zoomable interface {
+	// SetZoomXForm sets the transform from content to display coordinates,
+	// relative to the position of the content
+	SetZoomXForm(xf mat32.Mat2)
+
+	// ZoomContentSize returns the size of the content, in content
+	// coordinates, e.g., for fitting it to the view
+	ZoomContentSize() mat32.Vec2
+}
+
+// ZoomPanArea is a container that zooms and pans its child content (e.g.,
+// an svg.SVG or a Bitmap, implementing Zoomable) by a scale and translate
+// transform, with the content filling the area.  Scrolling the mouse wheel
+// zooms around the mouse position, dragging pans, and FitToView fits the
+// content to the area.  Changes in the transform are sent on the
+// ZoomPanSig, so that overlays such as rulers and selection boxes can stay
+// aligned with the content -- see ContentToView and ViewToContent.
+type ZoomPanArea struct {
+	Frame
+	Zoom          float32    `xml:"zoom" desc:"zoom scale: number of display dots per content unit"`
+	Pan           mat32.Vec2 `xml:"pan" desc:"pan translation, in display dots, of the content origin relative to the area"`
+	MinZoom       float32    `xml:"min-zoom" desc:"minimum zoom scale -- 0.01 if 0"`
+	MaxZoom       float32    `xml:"max-zoom" desc:"maximum zoom scale -- 100 if 0"`
+	ZoomRate      float32    `xml:"zoom-rate" desc:"factor by which each step of scrolling the mouse wheel zooms -- 1.1 if 0"`
+	SetDragCursor bool       `copy:"-" json:"-" xml:"-" view:"-" desc:"has dragging cursor been set yet?"`
+	ZoomPanSig    ki.Signal  `copy:"-" json:"-" xml:"-" view:"-" desc:"signal emitted when the Zoom or Pan changes, with the XForm as data"`
+}
+
+var KiT_ZoomPanArea = kit.Types.AddType(&ZoomPanArea{}, ZoomPanAreaProps)
+
+// AddNewZoomPanArea adds a new zoom pan area to given parent node, with given name.
+func AddNewZoomPanArea(parent ki.Ki, name string) *ZoomPanArea {
+	return parent.AddNewChild(KiT_ZoomPanArea, name).(*ZoomPanArea)
+}
+
+func (za *ZoomPanArea) CopyFieldsFrom(frm any) {
+	fr := frm.(*ZoomPanArea)
+	za.Frame.CopyFieldsFrom(&fr.Frame)
+	za.Zoom = fr.Zoom
+	za.Pan = fr.Pan
+	za.MinZoom = fr.MinZoom
+	za.MaxZoom = fr.MaxZoom
+	za.ZoomRate = fr.ZoomRate
+}
+
+func (za *ZoomPanArea) Disconnect() {
+	za.Frame.Disconnect()
+	za.ZoomPanSig.DisconnectAll()
+}
+
+var ZoomPanAreaProps = ki.Props{
+	"EnumType:Flag":    KiT_NodeFlags,
+	"border-width":     units.NewPx(1),
+	"border-color":     &Prefs.Colors.Border,
+	"padding":          units.NewPx(0),
+	"margin":           units.NewPx(0),
+	"color":            &Prefs.Colors.Font,
+	"background-color": &Prefs.Colors.Background,
+	"min-width":        units.NewEm(10),
+	"min-height":       units.NewEm(10),
+	"max-width":        -1,
+	"max-height":       -1,
+}
+
+// Content returns the first child that is Zoomable, or nil if none
+func (za *ZoomPanArea) Content() Zoomable {
+	for _, kid := range za.Kids {
+		if zc, ok := kid.(Zoomable); ok {
+			return zc
+		}
+	}
+	return nil
+}
+
+// EffZoom returns the effective Zoom, which is 1 if not set
+func (za *ZoomPanArea) EffZoom() float32 {
+	if za.Zoom == 0 {
+		return 1
+	}
+	return za.Zoom
+}
+
+// XForm returns the transform from content to display coordinates,
+// relative to the position of the content
+func (za *ZoomPanArea) XForm() mat32.Mat2 {
+	zm := za.EffZoom()
+	return mat32.Mat2{XX: zm, YY: zm, X0: za.Pan.X, Y0: za.Pan.Y}
+}
+
+// ContentToView returns the display position relative to the content
+// position of given point in content coordinates
+func (za *ZoomPanArea) ContentToView(pt mat32.Vec2) mat32.Vec2 {
+	return za.XForm().MulVec2AsPt(pt)
+}
+
+// ViewToContent returns the point in content coordinates of given display
+// position relative to the content position
+func (za *ZoomPanArea) ViewToContent(pt mat32.Vec2) mat32.Vec2 {
+	return pt.Sub(za.Pan).DivScalar(za.EffZoom())
+}
+
+// WinToView returns the display position relative to the content position
+// of given point in window coordinates, e.g., from a mouse event
+func (za *ZoomPanArea) WinToView(pt image.Point) mat32.Vec2 {
+	za.BBoxMu.RLock()
+	defer za.BBoxMu.RUnlock()
+	return mat32.NewVec2FmPoint(pt.Sub(za.WinBBox.Min)).SubScalar(za.BoxSpace())
+}
+
+// ViewSize returns the size of the area available for the content
+func (za *ZoomPanArea) ViewSize() mat32.Vec2 {
+	return za.LayState.Alloc.Size.SubScalar(2 * za.BoxSpace())
+}
+
+// ClampZoom returns given zoom limited to MinZoom and MaxZoom
+func (za *ZoomPanArea) ClampZoom(zoom float32) float32 {
+	minz, maxz := za.MinZoom, za.MaxZoom
+	if minz <= 0 {
+		minz = 0.01
+	}
+	if maxz <= 0 {
+		maxz = 100
+	}
+	return mat32.Clamp(zoom, minz, maxz)
+}
+
+// SetZoomPan sets the Zoom, limited by ClampZoom, and Pan, applies the
+// transform to the content, emits the ZoomPanSig and re-renders
+func (za *ZoomPanArea) SetZoomPan(zoom float32, pan mat32.Vec2) {
+	za.Zoom = za.ClampZoom(zoom)
+	za.Pan = pan
+	za.ApplyXForm()
+	za.ZoomPanSig.Emit(za.This(), 0, za.XForm())
+	za.SetFullReRender()
+	za.UpdateSig()
+}
+
+// ZoomAt zooms by given factor around given display position relative to
+// the content position, which stays fixed
+func (za *ZoomPanArea) ZoomAt(factor float32, pt mat32.Vec2) {
+	cpt := za.ViewToContent(pt)
+	nzm := za.ClampZoom(za.EffZoom() * factor)
+	za.SetZoomPan(nzm, pt.Sub(cpt.MulScalar(nzm)))
+}
+
+// PanBy pans by given delta in display dots
+func (za *ZoomPanArea) PanBy(delta mat32.Vec2) {
+	za.SetZoomPan(za.EffZoom(), za.Pan.Add(delta))
+}
+
+// FitToView zooms and pans so that the whole content fits in the area,
+// centered
+func (za *ZoomPanArea) FitToView() {
+	zc := za.Content()
+	if zc == nil {
+		return
+	}
+	csz := zc.ZoomContentSize()
+	vsz := za.ViewSize()
+	if csz.X <= 0 || csz.Y <= 0 || vsz.X <= 0 || vsz.Y <= 0 {
+		return
+	}
+	zm := mat32.Min(vsz.X/csz.X, vsz.Y/csz.Y)
+	za.SetZoomPan(zm, vsz.Sub(csz.MulScalar(zm)).MulScalar(0.5))
+}
+
+// ResetView resets to no zoom or pan
+func (za *ZoomPanArea) ResetView() {
+	za.SetZoomPan(1, mat32.Vec2{})
+}
+
+// ConnectRuler keeps given Ruler aligned with the content, by updating its
+// view whenever the Zoom or Pan changes
+func (za *ZoomPanArea) ConnectRuler(rl *Ruler) {
+	za.ZoomPanSig.Connect(rl.This(), func(recv, send ki.Ki, sig int64, data any) {
+		rlr := recv.Embed(KiT_Ruler).(*Ruler)
+		zaa := send.Embed(KiT_ZoomPanArea).(*ZoomPanArea)
+		rlr.SetView(-zaa.Pan.Dim(rlr.Dim), zaa.EffZoom())
+	})
+	rl.SetView(-za.Pan.Dim(rl.Dim), za.EffZoom())
+}
+
+// ApplyXForm sets the transform on the content
+func (za *ZoomPanArea) ApplyXForm() {
+	if zc := za.Content(); zc != nil {
+		zc.SetZoomXForm(za.XForm())
+	}
+}
+
+func (za *ZoomPanArea) Layout2D(parBBox image.Rectangle, iter int) bool {
+	LayAllocFromParent(&za.Layout)
+	za.Layout2DBase(parBBox, true, iter)
+	spc := za.BoxSpace()
+	for _, kid := range za.Kids {
+		nii, _ := KiToNode2D(kid)
+		if nii == nil {
+			continue
+		}
+		if wb := nii.AsWidget(); wb != nil { // content fills the area
+			wb.LayState.Alloc.PosRel = mat32.Vec2{spc, spc}
+			wb.LayState.Alloc.Size = za.ViewSize()
+		}
+	}
+	za.ApplyXForm()
+	return za.Layout2DChildren(iter)
+}
+
+// ZoomPanEvents handles wheel zooming and drag panning
+func (za *ZoomPanArea) ZoomPanEvents() {
+	za.ConnectEvent(oswin.MouseScrollEvent, RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.ScrollEvent)
+		me.SetProcessed()
+		zaa := recv.Embed(KiT_ZoomPanArea).(*ZoomPanArea)
+		del := me.NonZeroDelta(false)
+		if del == 0 {
+			return
+		}
+		rate := zaa.ZoomRate
+		if rate <= 0 {
+			rate = 1.1
+		}
+		if del < 0 {
+			rate = 1 / rate
+		}
+		zaa.ZoomAt(rate, zaa.WinToView(me.Where))
+	})
+	za.ConnectEvent(oswin.MouseDragEvent, RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.DragEvent)
+		me.SetProcessed()
+		zaa := recv.Embed(KiT_ZoomPanArea).(*ZoomPanArea)
+		if !zaa.IsDragging() {
+			zaa.PopDragCursor()
+			return
+		}
+		if !zaa.SetDragCursor {
+			oswin.TheApp.Cursor(zaa.ParentWindow().OSWin).Push(cursor.HandOpen)
+			zaa.SetDragCursor = true
+		}
+		zaa.PanBy(mat32.NewVec2FmPoint(me.Where.Sub(me.From)))
+	})
+	za.ConnectEvent(oswin.MouseEvent, RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.Event)
+		zaa := recv.Embed(KiT_ZoomPanArea).(*ZoomPanArea)
+		if me.Action == mouse.Release {
+			zaa.PopDragCursor()
+		}
+	})
+}
+
+// PopDragCursor restores the cursor after drag panning, if it was set
+func (za *ZoomPanArea) PopDragCursor() {
+	if za.SetDragCursor {
+		oswin.TheApp.Cursor(za.ParentWindow().OSWin).Pop()
+		za.SetDragCursor = false
+	}
+}
+
+func (za *ZoomPanArea) ConnectEvents2D() {
+	za.ZoomPanEvents()
+}
@@ -466,6 +466,10 @@ func (vp *EmbedViewport) IsInScope(node ki.Ki, popup bool) bool {
 	return true // no popups for embedded
 }
 
+func (vp *EmbedViewport) CurPopup() ki.Ki {
+	return nil // no popups for embedded
+}
+
 func (vp *EmbedViewport) CurPopupIsTooltip() bool {
 	return false
 }
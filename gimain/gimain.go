@@ -25,8 +25,11 @@ var dummSvg svg.Line
 var dummyVV giv.ValueViewBase
 
 // Main is run in a main package to start the GUI driver / event loop,
-// and call given function as the effective "main" function.
+// and call given function as the effective "main" function.  The output of
+// the standard logger is captured for the log console from the start (see
+// gi.CaptureStdLog).
 func Main(mainrun func()) {
+	gi.CaptureStdLog()
 	DebugEnumSizes()
 	driver.Main(func(app oswin.App) {
 		mainrun()
@@ -43,6 +46,7 @@ var started int32
 // must be launched prior to calling this in the main thread.  That thread
 // can call gimain.Quit() to close this main thread.
 func Start() {
+	gi.CaptureStdLog()
 	driver.Main(func(app oswin.App) {
 		atomic.AddInt32(&started, 1)
 		<-quit
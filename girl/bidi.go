@@ -0,0 +1,276 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"unicode"
+
+	"github.com/goki/gi/gist"
+	"golang.org/x/image/font"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Bidirectional text: the runes of a Span are always kept in logical
+// (reading) order, so that rune indexes, e.g., for cursors and links, are
+// the same as in the source text -- it is the rune positions that are
+// reordered into visual order, per the Unicode bidi algorithm, so mixed
+// right-to-left (Hebrew, Arabic) and left-to-right text is shown in the
+// right order.  The embedding level of each rune is kept in the span
+// BidiLevels: odd levels are right-to-left.  Only the implicit levels
+// (0, 1, 2) are used -- explicit embedding and override controls are not
+// supported.
+
+// SetRunePosBidi reorders the rune positions of the span, which must have
+// been set in logical order by SetRunePosLR, into visual order per the
+// Unicode bidi algorithm, given the direction of its paragraph (rtl for
+// right-to-left), and sets the Dir of the span to match (LRTB or RLTB).
+// Nothing is reordered for left-to-right paragraphs without any
+// right-to-left text.
+func (sr *Span) SetRunePosBidi(rtl bool) {
+	sr.BidiLevels = nil
+	sr.Dir = gist.LRTB
+	if rtl {
+		sr.Dir = gist.RLTB
+	}
+	if sr.IsValid() != nil || (!rtl && !HasRTL(sr.Text)) {
+		return
+	}
+	levels := BidiRuneLevels(sr.Text, rtl)
+	anyRTL := false
+	for _, lv := range levels {
+		if lv%2 == 1 {
+			anyRTL = true
+			break
+		}
+	}
+	if !anyRTL {
+		return
+	}
+	sz := len(sr.Text)
+	adv := make([]float32, sz)
+	for i := range sr.Render {
+		nx := sr.LastPos.X
+		if i < sz-1 {
+			nx = sr.Render[i+1].RelPos.X
+		}
+		adv[i] = nx - sr.Render[i].RelPos.X
+	}
+	x := sr.Render[0].RelPos.X
+	for _, i := range BidiVisualOrder(levels) {
+		sr.Render[i].RelPos.X = x
+		x += adv[i]
+	}
+	sr.BidiLevels = levels
+}
+
+// IsRTLRune returns true if the rune at given index is laid out
+// right-to-left, according to its bidi level (see SetRunePosBidi)
+func (sr *Span) IsRTLRune(idx int) bool {
+	if idx < 0 || idx >= len(sr.BidiLevels) {
+		return false
+	}
+	return sr.BidiLevels[idx]%2 == 1
+}
+
+// GlyphRune returns the rune whose glyph is rendered for the rune at given
+// index, in given face: its Glyph as set by the Shaper (see Span.Shape),
+// which is NoGlyph if it is rendered as part of a previous glyph, and
+// otherwise the rune itself, except that brackets in right-to-left text
+// are mirrored, e.g., ( is rendered as ), if the face has a glyph for it.
+func (sr *Span) GlyphRune(idx int, face font.Face) rune {
+	r := sr.Text[idx]
+	if gr := sr.Render[idx].Glyph; gr != 0 {
+		return gr
+	}
+	if !sr.IsRTLRune(idx) || face == nil {
+		return r
+	}
+	if p, _ := bidi.LookupRune(r); !p.IsBracket() {
+		return r
+	}
+	gr := []rune(bidi.ReverseString(string(r)))[0]
+	TextFontRenderMu.Lock()
+	_, ok := face.GlyphAdvance(gr)
+	TextFontRenderMu.Unlock()
+	if !ok {
+		return r
+	}
+	return gr
+}
+
+// SetRunePosBidi reorders the rune positions of all the spans into visual
+// order per the Unicode bidi algorithm (see Span.SetRunePosBidi) -- the
+// direction of each paragraph is right-to-left if that is the Direction
+// of given text style, and otherwise that of its first strong character.
+func (tr *Text) SetRunePosBidi(txtSty *gist.Text) {
+	rtl := false
+	for si := range tr.Spans {
+		sr := &(tr.Spans[si])
+		if si == 0 || sr.IsNewPara() {
+			rtl = tr.ParaRTL(si, txtSty)
+		}
+		sr.SetRunePosBidi(rtl)
+	}
+}
+
+// ParaRTL returns true if the paragraph starting at given span index is
+// right-to-left: if the Direction of given text style is, and otherwise if
+// its first strong character is right-to-left
+func (tr *Text) ParaRTL(si int, txtSty *gist.Text) bool {
+	if txtSty != nil && (txtSty.Direction == gist.RTL || txtSty.Direction == gist.RLTB) {
+		return true
+	}
+	for i := si; i < len(tr.Spans); i++ {
+		sr := &(tr.Spans[i])
+		if i > si && sr.IsNewPara() {
+			break
+		}
+		if rtl, ok := FirstStrongRTL(sr.Text); ok {
+			return rtl
+		}
+	}
+	return false
+}
+
+// FirstStrongRTL returns whether the first strong character of given text
+// is right-to-left, and false for ok if there is no strong character
+func FirstStrongRTL(txt []rune) (rtl, ok bool) {
+	for _, r := range txt {
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.L:
+			return false, true
+		case bidi.R, bidi.AL:
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// HasRTL returns true if given text has any right-to-left characters
+func HasRTL(txt []rune) bool {
+	for _, r := range txt {
+		if r < 0x0590 { // fast path: nothing right-to-left before Hebrew
+			continue
+		}
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.R, bidi.AL, bidi.RLE, bidi.RLO, bidi.RLI:
+			return true
+		}
+	}
+	return false
+}
+
+// BidiRuneLevels returns the bidi embedding level of each rune of given
+// line of text, in a paragraph of given direction (rtl for right-to-left),
+// per the Unicode bidi algorithm: 0 for left-to-right text in a
+// left-to-right paragraph, 1 for right-to-left text, and 2 for
+// left-to-right text and numbers within right-to-left text.
+func BidiRuneLevels(txt []rune, rtl bool) []uint8 {
+	sz := len(txt)
+	base := uint8(0)
+	rs := make([]rune, sz+1)
+	rs[0] = '\u200e' // LRM sets the paragraph direction
+	if rtl {
+		base = 1
+		rs[0] = '\u200f' // RLM
+	}
+	for i, r := range txt {
+		if p, _ := bidi.LookupRune(r); p.Class() == bidi.B { // would end the paragraph
+			r = ' '
+		}
+		rs[i+1] = r
+	}
+	levels := make([]uint8, sz)
+	for i := range levels {
+		levels[i] = base
+	}
+	var p bidi.Paragraph
+	p.SetString(string(rs))
+	o, err := p.Order()
+	if err != nil {
+		return levels
+	}
+	for ri := 0; ri < o.NumRuns(); ri++ {
+		run := o.Run(ri)
+		lv := base
+		switch {
+		case run.Direction() == bidi.RightToLeft:
+			lv = 1
+		case rtl:
+			lv = 2
+		}
+		st, ed := run.Pos()
+		for i := st; i <= ed; i++ {
+			if i > 0 {
+				levels[i-1] = lv
+			}
+		}
+	}
+	if !rtl { // numbers after right-to-left text go up to level 2
+		afterRTL := false
+		for i, r := range txt {
+			p, _ := bidi.LookupRune(r)
+			switch p.Class() {
+			case bidi.L:
+				afterRTL = false
+			case bidi.R, bidi.AL:
+				afterRTL = true
+			case bidi.AN:
+				levels[i] = 2
+			case bidi.EN:
+				if afterRTL {
+					levels[i] = 2
+				}
+			case bidi.CS, bidi.ES, bidi.ET: // within a number, e.g., 1.5
+				if afterRTL && i > 0 && levels[i-1] == 2 && i+1 < sz {
+					if np, _ := bidi.LookupRune(txt[i+1]); np.Class() == bidi.EN || np.Class() == bidi.AN {
+						levels[i] = 2
+					}
+				}
+			}
+		}
+	}
+	for i := sz - 1; i >= 0 && unicode.IsSpace(txt[i]); i-- { // trailing space
+		levels[i] = base
+	}
+	return levels
+}
+
+// BidiVisualOrder returns the logical indexes of the runes of a line with
+// given bidi levels in visual order, from left to right: each sequence of
+// runes at a given level or higher is reversed, from the highest level
+// down to the lowest odd level
+func BidiVisualOrder(levels []uint8) []int {
+	sz := len(levels)
+	ord := make([]int, sz)
+	maxl, minl := uint8(0), uint8(255)
+	for i, lv := range levels {
+		ord[i] = i
+		if lv > maxl {
+			maxl = lv
+		}
+		if lv < minl {
+			minl = lv
+		}
+	}
+	for lv := maxl; lv >= minl|1 && lv > 0; lv-- {
+		for i := 0; i < sz; i++ {
+			if levels[ord[i]] < lv {
+				continue
+			}
+			j := i
+			for j < sz && levels[ord[j]] >= lv {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				ord[a], ord[b] = ord[b], ord[a]
+			}
+			i = j
+		}
+	}
+	return ord
+}
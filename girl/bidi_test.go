@@ -0,0 +1,40 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"testing"
+)
+
+// visualString returns given text in visual order, in a paragraph of given direction
+func visualString(txt string, rtl bool) string {
+	rs := []rune(txt)
+	var vs []rune
+	for _, i := range BidiVisualOrder(BidiRuneLevels(rs, rtl)) {
+		vs = append(vs, rs[i])
+	}
+	return string(vs)
+}
+
+func TestBidiVisualOrder(t *testing.T) {
+	tests := []struct {
+		txt string
+		rtl bool
+		vis string
+	}{
+		{"abc def", false, "abc def"},
+		{"abc אבג def", false, "abc גבא def"},
+		{"abc שלום 123", false, "abc 123 םולש"},
+		{"שלום 123 abc", false, "123 םולש abc"},
+		{"אבג abc דהו", true, "והד abc גבא"},
+		{"אבג 1.5 דהו", true, "והד 1.5 גבא"},
+		{"abc (אבג) def", false, "abc (גבא) def"},
+	}
+	for _, tt := range tests {
+		if vis := visualString(tt.txt, tt.rtl); vis != tt.vis {
+			t.Errorf("visual order of %q (rtl: %v): got %q, want %q", tt.txt, tt.rtl, vis, tt.vis)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ints"
+	"github.com/goki/mat32"
+)
+
+// Filter is an image filter effect, applied to the rendered pixels of a
+// region, e.g., of a Layer -- see Filters, ParseFilters and PushFilterLayer
+type Filter interface {
+	// Apply applies the filter to given region of the image, in place
+	Apply(img *image.RGBA, r image.Rectangle)
+
+	// Margin returns the number of dots beyond the rendered content that
+	// the filter can draw into, e.g., for blurs and shadows
+	Margin() int
+}
+
+// Filters is a list of filter effects, applied in order
+type Filters []Filter
+
+// Apply applies all the filters in order to given region of the image
+func (fs Filters) Apply(img *image.RGBA, r image.Rectangle) {
+	for _, f := range fs {
+		f.Apply(img, r)
+	}
+}
+
+// Margin returns the total margin of all the filters
+func (fs Filters) Margin() int {
+	m := 0
+	for _, f := range fs {
+		m += f.Margin()
+	}
+	return m
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Blur
+
+// BlurFilter blurs the image with a gaussian of given standard deviation in
+// dots, as in the CSS blur() filter and SVG feGaussianBlur
+type BlurFilter struct {
+	StdDev float32 `desc:"standard deviation of the gaussian, in dots"`
+}
+
+func (bf BlurFilter) Apply(img *image.RGBA, r image.Rectangle) {
+	GaussianBlur(img, r, bf.StdDev)
+}
+
+func (bf BlurFilter) Margin() int {
+	return int(mat32.Ceil(3 * bf.StdDev))
+}
+
+// GaussianBlur blurs given region of the image in place, with a gaussian of
+// given standard deviation, approximated by three successive box blurs.
+// Pixels outside of the region are treated as transparent.
+func GaussianBlur(img *image.RGBA, r image.Rectangle, stdDev float32) {
+	r = r.Intersect(img.Bounds())
+	if stdDev <= 0 || r.Empty() {
+		return
+	}
+	const n = 3
+	wIdeal := mat32.Sqrt(12*stdDev*stdDev/n + 1)
+	wl := int(wIdeal)
+	if wl%2 == 0 {
+		wl--
+	}
+	wu := wl + 2
+	m := int(mat32.Round((12*stdDev*stdDev - float32(n*wl*wl+4*n*wl+3*n)) / float32(-4*wl-4)))
+	sz := r.Size()
+	buf := make([]uint8, 4*ints.MaxInt(sz.X, sz.Y))
+	for i := 0; i < n; i++ {
+		w := wu
+		if i < m {
+			w = wl
+		}
+		rad := (w - 1) / 2
+		if rad < 1 {
+			continue
+		}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			boxBlurLine(img.Pix[img.PixOffset(r.Min.X, y):], 4, sz.X, rad, buf)
+		}
+		for x := r.Min.X; x < r.Max.X; x++ {
+			boxBlurLine(img.Pix[img.PixOffset(x, r.Min.Y):], img.Stride, sz.Y, rad, buf)
+		}
+	}
+}
+
+// boxBlurLine does a box blur of given radius along a line of n pixels
+// starting at pix, separated by stride bytes, using buf for the original
+// values
+func boxBlurLine(pix []uint8, stride, n, rad int, buf []uint8) {
+	for i := 0; i < n; i++ {
+		copy(buf[4*i:4*i+4], pix[i*stride:i*stride+4])
+	}
+	var sum [4]int
+	for i := 0; i < rad && i < n; i++ {
+		for c := 0; c < 4; c++ {
+			sum[c] += int(buf[4*i+c])
+		}
+	}
+	w := 2*rad + 1
+	for i := 0; i < n; i++ {
+		if ad := i + rad; ad < n {
+			for c := 0; c < 4; c++ {
+				sum[c] += int(buf[4*ad+c])
+			}
+		}
+		if rm := i - rad - 1; rm >= 0 {
+			for c := 0; c < 4; c++ {
+				sum[c] -= int(buf[4*rm+c])
+			}
+		}
+		po := i * stride
+		for c := 0; c < 4; c++ {
+			pix[po+c] = uint8((sum[c] + w/2) / w)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  DropShadow
+
+// DropShadowFilter draws a blurred shadow of the alpha shape of the image
+// behind it, as in the CSS drop-shadow() filter and SVG feDropShadow
+type DropShadowFilter struct {
+	Offset mat32.Vec2 `desc:"offset of the shadow, in dots"`
+	StdDev float32    `desc:"standard deviation of the gaussian blur of the shadow, in dots"`
+	Color  color.RGBA `desc:"color of the shadow (premultiplied)"`
+}
+
+func (df DropShadowFilter) Apply(img *image.RGBA, r image.Rectangle) {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return
+	}
+	sh := image.NewRGBA(r)
+	off := image.Point{int(mat32.Round(df.Offset.X)), int(mat32.Round(df.Offset.Y))}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sp := image.Point{x, y}.Sub(off)
+			if !sp.In(r) {
+				continue
+			}
+			a := uint32(img.Pix[img.PixOffset(sp.X, sp.Y)+3])
+			if a == 0 {
+				continue
+			}
+			si := sh.PixOffset(x, y)
+			sh.Pix[si+0] = uint8(uint32(df.Color.R) * a / 255)
+			sh.Pix[si+1] = uint8(uint32(df.Color.G) * a / 255)
+			sh.Pix[si+2] = uint8(uint32(df.Color.B) * a / 255)
+			sh.Pix[si+3] = uint8(uint32(df.Color.A) * a / 255)
+		}
+	}
+	GaussianBlur(sh, r, df.StdDev)
+	for y := r.Min.Y; y < r.Max.Y; y++ { // image over shadow
+		for x := r.Min.X; x < r.Max.X; x++ {
+			di := img.PixOffset(x, y)
+			si := sh.PixOffset(x, y)
+			ia := 255 - uint32(img.Pix[di+3])
+			for c := 0; c < 4; c++ {
+				img.Pix[di+c] += uint8(uint32(sh.Pix[si+c]) * ia / 255)
+			}
+		}
+	}
+}
+
+func (df DropShadowFilter) Margin() int {
+	return int(mat32.Ceil(3*df.StdDev + mat32.Max(mat32.Abs(df.Offset.X), mat32.Abs(df.Offset.Y))))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  ColorMatrix
+
+// ColorMatrixFilter transforms the colors of the image by a 5x4 matrix
+// applied to the non-premultiplied R, G, B, A values (0-1) and a constant
+// 1, in row-major order, as in SVG feColorMatrix.  The CSS grayscale(),
+// sepia(), saturate(), brightness() and contrast() filters are all color
+// matrices -- see GrayscaleFilter etc.
+type ColorMatrixFilter struct {
+	Matrix [20]float32 `desc:"the matrix, in row-major order: one row of 5 values for each of R, G, B, A output"`
+}
+
+func (cf ColorMatrixFilter) Apply(img *image.RGBA, r image.Rectangle) {
+	r = r.Intersect(img.Bounds())
+	m := &cf.Matrix
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			pi := img.PixOffset(x, y)
+			p := img.Pix[pi : pi+4 : pi+4]
+			if p[3] == 0 && m[19] <= 0 {
+				continue
+			}
+			var in [4]float32
+			a := float32(p[3]) / 255
+			for c := 0; c < 3; c++ {
+				if a > 0 {
+					in[c] = float32(p[c]) / 255 / a
+				}
+			}
+			in[3] = a
+			var out [4]float32
+			for c := 0; c < 4; c++ {
+				rw := m[c*5 : c*5+5]
+				out[c] = mat32.Clamp(rw[0]*in[0]+rw[1]*in[1]+rw[2]*in[2]+rw[3]*in[3]+rw[4], 0, 1)
+			}
+			for c := 0; c < 3; c++ {
+				p[c] = uint8(out[c]*out[3]*255 + 0.5)
+			}
+			p[3] = uint8(out[3]*255 + 0.5)
+		}
+	}
+}
+
+func (cf ColorMatrixFilter) Margin() int {
+	return 0
+}
+
+// SaturateFilter returns a filter that saturates the colors by given
+// amount: 0 is grayscale, 1 is unchanged and larger values over-saturate
+func SaturateFilter(amt float32) ColorMatrixFilter {
+	s := amt
+	return ColorMatrixFilter{Matrix: [20]float32{
+		0.213 + 0.787*s, 0.715 - 0.715*s, 0.072 - 0.072*s, 0, 0,
+		0.213 - 0.213*s, 0.715 + 0.285*s, 0.072 - 0.072*s, 0, 0,
+		0.213 - 0.213*s, 0.715 - 0.715*s, 0.072 + 0.928*s, 0, 0,
+		0, 0, 0, 1, 0,
+	}}
+}
+
+// GrayscaleFilter returns a filter that converts the colors to grayscale
+// by given proportion (0-1)
+func GrayscaleFilter(amt float32) ColorMatrixFilter {
+	return SaturateFilter(1 - mat32.Clamp(amt, 0, 1))
+}
+
+// SepiaFilter returns a filter that converts the colors to sepia by given
+// proportion (0-1)
+func SepiaFilter(amt float32) ColorMatrixFilter {
+	a := 1 - mat32.Clamp(amt, 0, 1)
+	return ColorMatrixFilter{Matrix: [20]float32{
+		0.393 + 0.607*a, 0.769 - 0.769*a, 0.189 - 0.189*a, 0, 0,
+		0.349 - 0.349*a, 0.686 + 0.314*a, 0.168 - 0.168*a, 0, 0,
+		0.272 - 0.272*a, 0.534 - 0.534*a, 0.131 + 0.869*a, 0, 0,
+		0, 0, 0, 1, 0,
+	}}
+}
+
+// BrightnessFilter returns a filter that multiplies the colors by given
+// amount: 0 is black, 1 is unchanged
+func BrightnessFilter(amt float32) ColorMatrixFilter {
+	return ColorMatrixFilter{Matrix: [20]float32{
+		amt, 0, 0, 0, 0,
+		0, amt, 0, 0, 0,
+		0, 0, amt, 0, 0,
+		0, 0, 0, 1, 0,
+	}}
+}
+
+// ContrastFilter returns a filter that adjusts the contrast of the colors
+// by given amount: 0 is uniform gray, 1 is unchanged
+func ContrastFilter(amt float32) ColorMatrixFilter {
+	o := 0.5 - 0.5*amt
+	return ColorMatrixFilter{Matrix: [20]float32{
+		amt, 0, 0, 0, o,
+		0, amt, 0, 0, o,
+		0, 0, amt, 0, o,
+		0, 0, 0, 1, 0,
+	}}
+}
+
+// ParseFilters parses a CSS filter property value, e.g.,
+// "blur(2px) drop-shadow(2px 2px 4px black) grayscale(100%)", into a list
+// of filters, using given units context for the lengths.  The supported
+// functions are blur, drop-shadow, grayscale, sepia, saturate, brightness
+// and contrast.  "none" or an empty string returns no filters.
+func ParseFilters(str string, uc *units.Context) (Filters, error) {
+	var fs Filters
+	str = strings.TrimSpace(str)
+	for str != "" && str != "none" {
+		op := strings.Index(str, "(")
+		cp := strings.Index(str, ")")
+		if op < 0 || cp < op {
+			return fs, fmt.Errorf("girl.ParseFilters: invalid filter function: %q", str)
+		}
+		fn := strings.ToLower(strings.TrimSpace(str[:op]))
+		args := strings.Fields(strings.ReplaceAll(str[op+1:cp], ",", " "))
+		str = strings.TrimSpace(str[cp+1:])
+		switch fn {
+		case "blur":
+			if len(args) < 1 {
+				return fs, fmt.Errorf("girl.ParseFilters: blur requires a radius")
+			}
+			fs = append(fs, BlurFilter{StdDev: filterLen(args[0], uc)})
+		case "drop-shadow":
+			if len(args) < 2 {
+				return fs, fmt.Errorf("girl.ParseFilters: drop-shadow requires x and y offsets")
+			}
+			df := DropShadowFilter{Offset: mat32.Vec2{filterLen(args[0], uc), filterLen(args[1], uc)}, Color: color.RGBA{0, 0, 0, 255}}
+			for _, a := range args[2:] {
+				if (a[0] >= '0' && a[0] <= '9') || a[0] == '.' {
+					df.StdDev = filterLen(a, uc)
+				} else if clr, err := gist.ColorFromString(a, nil); err == nil {
+					df.Color = color.RGBAModel.Convert(clr).(color.RGBA)
+				}
+			}
+			fs = append(fs, df)
+		case "grayscale", "sepia", "saturate", "brightness", "contrast":
+			amt := float32(1)
+			if len(args) > 0 {
+				amt = filterAmount(args[0])
+			}
+			switch fn {
+			case "grayscale":
+				fs = append(fs, GrayscaleFilter(amt))
+			case "sepia":
+				fs = append(fs, SepiaFilter(amt))
+			case "saturate":
+				fs = append(fs, SaturateFilter(amt))
+			case "brightness":
+				fs = append(fs, BrightnessFilter(amt))
+			case "contrast":
+				fs = append(fs, ContrastFilter(amt))
+			}
+		default:
+			return fs, fmt.Errorf("girl.ParseFilters: filter function not supported: %q", fn)
+		}
+	}
+	return fs, nil
+}
+
+// filterLen returns the length in dots of given units string
+func filterLen(str string, uc *units.Context) float32 {
+	v := units.StringToValue(str)
+	return v.ToDots(uc)
+}
+
+// filterAmount returns the amount of given number or percent string
+func filterAmount(str string) float32 {
+	var amt float32
+	if strings.HasSuffix(str, "%") {
+		fmt.Sscanf(strings.TrimSuffix(str, "%"), "%g", &amt)
+		return amt / 100
+	}
+	fmt.Sscanf(str, "%g", &amt)
+	return amt
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  FilterCache
+
+// FilterCache holds the result of applying filters to a region of rendered
+// content, so that the filters are only recomputed when the content, the
+// filters or the region change -- see PushFilterLayer.
+type FilterCache struct {
+	Key    uint64          `desc:"hash of the unfiltered content and the filters"`
+	Bounds image.Rectangle `desc:"region of the cached image"`
+	Image  *image.RGBA     `desc:"the filtered result for the region"`
+}
+
+// Invalidate clears the cache
+func (fc *FilterCache) Invalidate() {
+	fc.Key = 0
+	fc.Image = nil
+}
+
+// FilterKey returns a hash of the given region of the unfiltered content
+// and the filters, for the FilterCache
+func FilterKey(img *image.RGBA, r image.Rectangle, fs Filters) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%v%v", r, fs)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		si := img.PixOffset(r.Min.X, y)
+		h.Write(img.Pix[si : si+4*r.Dx()])
+	}
+	return h.Sum64()
+}
+
+// Filter applies given filters to the region of the image, using the
+// cached result instead if the content and filters are unchanged
+func (fc *FilterCache) Filter(img *image.RGBA, r image.Rectangle, fs Filters) {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return
+	}
+	key := FilterKey(img, r, fs)
+	if fc.Image != nil && fc.Key == key && fc.Bounds == r {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			copy(img.Pix[img.PixOffset(r.Min.X, y):], fc.Image.Pix[fc.Image.PixOffset(r.Min.X, y):fc.Image.PixOffset(r.Max.X, y)])
+		}
+		return
+	}
+	fs.Apply(img, r)
+	fc.Key = key
+	fc.Bounds = r
+	fc.Image = image.NewRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		copy(fc.Image.Pix[fc.Image.PixOffset(r.Min.X, y):], img.Pix[img.PixOffset(r.Min.X, y):img.PixOffset(r.Max.X, y)])
+	}
+}
@@ -5,6 +5,8 @@
 package girl
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"math"
@@ -25,10 +27,15 @@ import (
 // font.  The font size is always rounded to nearest integer, to produce
 // better-looking results (presumably).  The current metrics and given
 // unit.Context are updated based on the properties of the font.
+//
+// Any variable font axis settings (gist.Font.Variations) are only used to
+// select the closest available static face, and the font feature settings
+// (gist.Font.Features) are applied via a FeatureFace.
 func OpenFont(fs *gist.Font, ctxt *units.Context) {
-	facenm := FontFaceName(fs.Family, fs.Stretch, fs.Weight, fs.Style)
+	str, wt, sty := fs.EffMods()
+	facenm := FontFaceName(fs.Family, str, wt, sty)
 	if fs.Size.Dots == 0 {
-		fs.Size.ToDots(ctxt)
+		fs.ToDots(ctxt)
 	}
 	intDots := int(math.Round(float64(fs.Size.Dots)))
 	if intDots == 0 {
@@ -43,10 +50,14 @@ func OpenFont(fs *gist.Font, ctxt *units.Context) {
 			fs.Face = face
 		}
 	} else {
-		fs.Face = face
+		fs.Face = FontLibrary.FeatureFont(face, fs.Features)
 	}
 	fs.Rem = ctxt.ToDots(12, units.Pt)
 	fs.SetUnitContext(ctxt)
+	if fs.StrokeWidth.Val != 0 {
+		fs.StrokeWidth.ToDots(ctxt)
+	}
+	fs.TextShadow.ToDots(ctxt)
 }
 
 // OpenFontFace loads a font file at given path, with given raw size in
@@ -57,12 +68,21 @@ func OpenFontFace(name, path string, size int, strokeWidth int) (*gist.FontFace,
 	if strings.HasPrefix(path, "gofont") {
 		return OpenGoFont(name, path, size, strokeWidth)
 	}
-	fontBytes, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
+	var fontBytes []byte
+	if strings.HasPrefix(path, FontMemPrefix) {
+		fontBytes = FontLibrary.FontBytes[path]
+		if fontBytes == nil {
+			return nil, fmt.Errorf("gi.FontLib: in-memory font not found: %v", path)
+		}
+	} else {
+		var err error
+		fontBytes, err = ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
 	}
 	ext := strings.ToLower(filepath.Ext(path))
-	if ext == ".otf" {
+	if ext == ".otf" || bytes.HasPrefix(fontBytes, []byte("OTTO")) {
 		// note: this compiles but otf fonts are NOT yet supported apparently
 		f, err := opentype.Parse(fontBytes)
 		if err != nil {
@@ -73,8 +93,12 @@ func OpenFontFace(name, path string, size int, strokeWidth int) (*gist.FontFace,
 			DPI:  72,
 			// Hinting: font.HintingFull,
 		})
+		if err != nil {
+			return nil, err
+		}
+		setGlyphOutlines(face, fontBytes, size)
 		ff := gist.NewFontFace(name, size, face)
-		return ff, err
+		return ff, nil
 	} else {
 		f, err := truetype.Parse(fontBytes)
 		if err != nil {
@@ -86,6 +110,7 @@ func OpenFontFace(name, path string, size int, strokeWidth int) (*gist.FontFace,
 			// Hinting: font.HintingFull,
 			// GlyphCacheEntries: 1024, // default is 512 -- todo benchmark
 		})
+		setGlyphOutlines(face, fontBytes, size)
 		ff := gist.NewFontFace(name, size, face)
 		return ff, nil
 	}
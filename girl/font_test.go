@@ -48,3 +48,49 @@ func TestFontFaceName(t *testing.T) {
 		}
 	}
 }
+
+// ligaShaper is a FeatureShaper that supports liga
+type ligaShaper struct {
+	SimpleShaper
+}
+
+func (ls *ligaShaper) SupportsFeature(tag string) bool {
+	return tag == "liga"
+}
+
+func TestUnsupportedFeatures(t *testing.T) {
+	defer func(ts Shaper) { TextShaper = ts }(TextShaper)
+	TextShaper = &SimpleShaper{}
+	var ff gist.FontFeatures
+	ff.SetString(`"liga" 0, "kern" 0, "tnum", "ss01"`)
+	uns := UnsupportedFeatures(ff)
+	if fmt.Sprint(uns) != "[liga ss01]" {
+		t.Errorf("UnsupportedFeatures: %v != correct: [liga ss01]\n", uns)
+	}
+	TextShaper = &ligaShaper{}
+	if uns := UnsupportedFeatures(ff); fmt.Sprint(uns) != "[ss01]" {
+		t.Errorf("UnsupportedFeatures with a FeatureShaper: %v != correct: [ss01]\n", uns)
+	}
+	ff.SetString(`"kern" 0, "tnum"`)
+	if uns := UnsupportedFeatures(ff); len(uns) != 0 {
+		t.Errorf("UnsupportedFeatures: %v != correct: none\n", uns)
+	}
+}
+
+func TestFeatureFont(t *testing.T) {
+	face, err := OpenGoFont("Go", "gofont/goregular", 12, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ff := FontLibrary.FeatureFont(face, nil); ff != face {
+		t.Errorf("FeatureFont without features: got %v, want the face as-is", ff.Name)
+	}
+	// features that FeatureFace does not apply itself are passed on to the
+	// shaper in the FeatureFace
+	var feats gist.FontFeatures
+	feats.SetString(`"liga" 0`)
+	ff, ok := FontLibrary.FeatureFont(face, feats).Face.(*FeatureFace)
+	if !ok || ff.Features.String() != feats.String() || ff.NoKern || ff.TabNums {
+		t.Errorf("FeatureFont with liga 0: got %#v, want a FeatureFace with its features", ff)
+	}
+}
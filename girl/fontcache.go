@@ -0,0 +1,121 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+)
+
+// FontCacheFile is the file where the results of scanning the FontPaths for
+// fonts are saved, so that the (often large) font directories only need to
+// be scanned again at startup when they have changed -- gi sets this to a
+// file in the GoGi prefs directory.  There is no caching if it is empty.
+var FontCacheFile string
+
+// FontCacheVersion is saved in the FontCacheFile, which is ignored if it
+// was saved with a different version -- gi sets this to its Version, so
+// that changes to the font name regularization take effect.
+var FontCacheVersion string
+
+// fontCacheEntry is a font found on a font path: regularized name and file
+type fontCacheEntry struct {
+	Name string
+	Path string
+}
+
+// fontPathCache is the result of scanning one of the FontPaths
+type fontPathCache struct {
+	Dirs  map[string]time.Time `desc:"modification times of all the directories scanned -- the result is valid if none have changed"`
+	Fonts []fontCacheEntry     `desc:"fonts found"`
+}
+
+// valid returns true if none of the directories scanned have changed
+func (pc *fontPathCache) valid() bool {
+	if len(pc.Dirs) == 0 {
+		return false
+	}
+	for dir, mt := range pc.Dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.ModTime().Equal(mt) {
+			return false
+		}
+	}
+	return true
+}
+
+// fontCache is the contents of the FontCacheFile
+type fontCache struct {
+	Version string                    `desc:"FontCacheVersion when saved"`
+	Paths   map[string]*fontPathCache `desc:"scan results by font path"`
+	changed bool
+}
+
+// openFontCache opens the FontCacheFile, returning nil if there is no
+// FontCacheFile, and an empty cache if it does not exist or is out of date
+func openFontCache() *fontCache {
+	if FontCacheFile == "" {
+		return nil
+	}
+	fc := &fontCache{}
+	b, err := os.ReadFile(FontCacheFile)
+	if err == nil {
+		err = json.Unmarshal(b, fc)
+	}
+	if err != nil || fc.Version != FontCacheVersion {
+		fc = &fontCache{Version: FontCacheVersion, changed: true}
+	}
+	if fc.Paths == nil {
+		fc.Paths = make(map[string]*fontPathCache)
+	}
+	return fc
+}
+
+// save saves the cache to the FontCacheFile, if it has changed
+func (fc *fontCache) save() {
+	if fc == nil || !fc.changed {
+		return
+	}
+	b, err := json.Marshal(fc)
+	if err == nil {
+		err = os.WriteFile(FontCacheFile, b, 0644)
+	}
+	if err != nil {
+		log.Printf("gi.FontLib: error saving font cache: %v\n", err)
+	}
+	fc.changed = false
+}
+
+// fontsAvailFromPathCached adds all the fonts on a given path to FontsAvail
+// and FontInfo, using the result in given cache if it is still valid, and
+// otherwise scanning the path and updating the cache (which can be nil)
+func (fl *FontLib) fontsAvailFromPathCached(path string, fc *fontCache) error {
+	if fc == nil {
+		return fl.FontsAvailFromPath(path)
+	}
+	pc, has := fc.Paths[path]
+	if !has || !pc.valid() {
+		fonts, dirs, err := scanFontPath(path)
+		if err != nil {
+			if has {
+				delete(fc.Paths, path)
+				fc.changed = true
+			}
+			for _, fe := range fonts {
+				fl.addFontInfo(fe.Name, fe.Path)
+			}
+			return err
+		}
+		pc = &fontPathCache{Dirs: dirs, Fonts: fonts}
+		fc.Paths[path] = pc
+		fc.changed = true
+	}
+	for _, fe := range pc.Fonts {
+		fl.addFontInfo(fe.Name, fe.Path)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fontAvail returns true if given lower-case font name is available in fl
+func fontAvail(fl *FontLib, fontnm string) bool {
+	_, ok := fl.FontsAvail[fontnm]
+	return ok
+}
+
+func TestFontCache(t *testing.T) {
+	dir := t.TempDir()
+	fdir := filepath.Join(dir, "fonts")
+	if err := os.MkdirAll(filepath.Join(fdir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range []string{"Test-Regular.ttf", "sub/Test-Bold.ttf", "readme.txt"} {
+		if err := os.WriteFile(filepath.Join(fdir, fn), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sfile, sver := FontCacheFile, FontCacheVersion
+	defer func() { FontCacheFile, FontCacheVersion = sfile, sver }()
+	FontCacheFile = filepath.Join(dir, "font_cache.json")
+	FontCacheVersion = "v1"
+
+	scan := func() *FontLib {
+		fl := &FontLib{FontPaths: []string{fdir}, FontsAvail: make(map[string]string)}
+		fl.UpdateFontsAvail()
+		return fl
+	}
+	fl := scan()
+	if !fontAvail(fl, "test bold") || !fontAvail(fl, "test") {
+		t.Errorf("fonts not found in scan: %v", fl.FontsAvail)
+	}
+
+	// change the cached result: it should be used as the dirs are unchanged
+	fc := openFontCache()
+	pc := fc.Paths[fdir]
+	if pc == nil || len(pc.Fonts) != 2 || len(pc.Dirs) != 2 {
+		t.Fatalf("bad font cache: %+v", pc)
+	}
+	pc.Fonts = append(pc.Fonts, fontCacheEntry{Name: "Cached", Path: filepath.Join(fdir, "cached.ttf")})
+	fc.changed = true
+	fc.save()
+	fl = scan()
+	if !fontAvail(fl, "cached") {
+		t.Errorf("font cache not used: %v", fl.FontsAvail)
+	}
+
+	// adding a font changes the dir, so it is scanned again
+	if err := os.WriteFile(filepath.Join(fdir, "sub", "Other.ttf"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fl = scan()
+	if fontAvail(fl, "cached") || !fontAvail(fl, "other") {
+		t.Errorf("changed font dir not scanned: %v", fl.FontsAvail)
+	}
+
+	// a different version ignores the cache
+	b, _ := os.ReadFile(FontCacheFile)
+	fc = &fontCache{}
+	json.Unmarshal(b, fc)
+	fc.Paths[fdir].Fonts = nil
+	fc.changed = true
+	fc.save()
+	FontCacheVersion = "v2"
+	fl = scan()
+	if !fontAvail(fl, "other") {
+		t.Errorf("font cache of other version used: %v", fl.FontsAvail)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/goki/gi/gist"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// FeatureFace is a font.Face with OpenType font feature settings on top of
+// a base face.  It applies those that affect glyph metrics, so that they
+// are automatically honored in all layout and rendering code:
+//   - "kern" 0: turns off kerning
+//   - "tnum" 1: tabular numbers -- all digits have the same advance
+//     (of the widest digit), and are centered within that advance.
+//
+// Other features, e.g., liga and ss01, are passed on in its Features to the
+// TextShaper, and applied if it is a FeatureShaper that supports them,
+// e.g., the GoTextShaper of the gotext build -- otherwise they have no
+// effect (see UnsupportedFeatures).
+type FeatureFace struct {
+	font.Face
+	Features gist.FontFeatures `desc:"the feature settings"`
+	NoKern   bool              `desc:"kerning is turned off"`
+	TabNums  bool              `desc:"tabular numbers are on"`
+	TabAdv   fixed.Int26_6     `desc:"advance for tabular numbers"`
+}
+
+// SupportedFeatures are the tags of the font features that FeatureFace
+// applies itself -- others are only applied by a FeatureShaper
+var SupportedFeatures = []string{"kern", "tnum"}
+
+// UnsupportedFeatures returns the tags of the given features that are not
+// among the SupportedFeatures, and not supported by the TextShaper (see
+// FeatureShaper), and so have no effect
+func UnsupportedFeatures(feats gist.FontFeatures) []string {
+	fs, _ := TextShaper.(FeatureShaper)
+	var tags []string
+outer:
+	for _, f := range feats {
+		for _, st := range SupportedFeatures {
+			if f.Tag == st {
+				continue outer
+			}
+		}
+		if fs != nil && fs.SupportsFeature(f.Tag) {
+			continue
+		}
+		tags = append(tags, f.Tag)
+	}
+	return tags
+}
+
+var (
+	// warnedFeatures records the unsupported feature tags already warned about
+	warnedFeatures   map[string]bool
+	warnedFeaturesMu sync.Mutex
+)
+
+// warnUnsupportedFeatures logs a warning the first time each unsupported
+// feature in given features is used
+func warnUnsupportedFeatures(feats gist.FontFeatures) {
+	tags := UnsupportedFeatures(feats)
+	if len(tags) == 0 {
+		return
+	}
+	warnedFeaturesMu.Lock()
+	defer warnedFeaturesMu.Unlock()
+	if warnedFeatures == nil {
+		warnedFeatures = make(map[string]bool)
+	}
+	for _, tag := range tags {
+		if !warnedFeatures[tag] {
+			warnedFeatures[tag] = true
+			log.Printf("girl.FeatureFont: font feature %q is not supported by the text shaper, and has no effect -- supported features: %v, or all with the GoTextShaper of the gotext build\n", tag, SupportedFeatures)
+		}
+	}
+}
+
+// FeaturesAffectFace returns true if any of the given features change the
+// metrics of the face, as applied by FeatureFace
+func FeaturesAffectFace(feats gist.FontFeatures) bool {
+	return !feats.IsOn("kern", true) || feats.IsOn("tnum", false)
+}
+
+// NewFeatureFace returns a new FeatureFace for given base face and features
+func NewFeatureFace(face font.Face, feats gist.FontFeatures) *FeatureFace {
+	ff := &FeatureFace{Face: face, Features: feats}
+	ff.NoKern = !feats.IsOn("kern", true)
+	ff.TabNums = feats.IsOn("tnum", false)
+	if ff.TabNums {
+		for r := '0'; r <= '9'; r++ {
+			if a, ok := face.GlyphAdvance(r); ok && a > ff.TabAdv {
+				ff.TabAdv = a
+			}
+		}
+	}
+	return ff
+}
+
+// isTabDigit returns true if given rune is a digit subject to tabular numbers
+func (ff *FeatureFace) isTabDigit(r rune) bool {
+	return ff.TabNums && r >= '0' && r <= '9'
+}
+
+// tabOffset returns the offset to center given digit within the tabular advance
+func (ff *FeatureFace) tabOffset(r rune) fixed.Int26_6 {
+	a, _ := ff.Face.GlyphAdvance(r)
+	return (ff.TabAdv - a) / 2
+}
+
+func (ff *FeatureFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	if !ff.isTabDigit(r) {
+		return ff.Face.Glyph(dot, r)
+	}
+	dot.X += ff.tabOffset(r)
+	dr, mask, maskp, _, ok = ff.Face.Glyph(dot, r)
+	return dr, mask, maskp, ff.TabAdv, ok
+}
+
+func (ff *FeatureFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	if !ff.isTabDigit(r) {
+		return ff.Face.GlyphBounds(r)
+	}
+	bounds, _, ok = ff.Face.GlyphBounds(r)
+	off := ff.tabOffset(r)
+	bounds.Min.X += off
+	bounds.Max.X += off
+	return bounds, ff.TabAdv, ok
+}
+
+func (ff *FeatureFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	if !ff.isTabDigit(r) {
+		return ff.Face.GlyphAdvance(r)
+	}
+	_, ok = ff.Face.GlyphAdvance(r)
+	return ff.TabAdv, ok
+}
+
+func (ff *FeatureFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if ff.NoKern || ff.isTabDigit(r0) || ff.isTabDigit(r1) {
+		return 0
+	}
+	return ff.Face.Kern(r0, r1)
+}
+
+// FeatureFont returns a version of given face with given font features
+// applied, as a FeatureFace, using a cache of such faces, so that all the
+// features are passed on to the TextShaper, even those that FeatureFace
+// does not apply itself.  If there are no features, the given face is
+// returned as-is.  A warning is logged the first time each unsupported
+// feature is used (see UnsupportedFeatures).
+func (fl *FontLib) FeatureFont(face *gist.FontFace, feats gist.FontFeatures) *gist.FontFace {
+	warnUnsupportedFeatures(feats)
+	if face == nil || len(feats) == 0 {
+		return face
+	}
+	key := face.Name + "|" + strconv.Itoa(face.Size) + "|" + feats.String()
+	loadFontMu.RLock()
+	if ff, has := fl.FeatureFaces[key]; has {
+		loadFontMu.RUnlock()
+		return ff
+	}
+	loadFontMu.RUnlock()
+	loadFontMu.Lock()
+	defer loadFontMu.Unlock()
+	if fl.FeatureFaces == nil {
+		fl.FeatureFaces = make(map[string]*gist.FontFace)
+	}
+	ff := gist.NewFontFace(face.Name, face.Size, NewFeatureFace(face.Face, feats))
+	fl.FeatureFaces[key] = ff
+	return ff
+}
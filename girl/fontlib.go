@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fatih/camelcase"
 	"github.com/goki/freetype/truetype"
@@ -29,6 +30,7 @@ import (
 	"golang.org/x/image/font/gofont/goregular"
 	"golang.org/x/image/font/gofont/gosmallcaps"
 	"golang.org/x/image/font/gofont/gosmallcapsitalic"
+	"golang.org/x/image/font/opentype"
 )
 
 // loadFontMu protects the font loading calls, which are not concurrent-safe
@@ -56,10 +58,12 @@ func (fi FontInfo) Label() string {
 // each font name specifies a particular font weight and style.  When fonts
 // are loaded into the library, the names are appropriately regularized.
 type FontLib struct {
-	FontPaths  []string                          `desc:"list of font paths to search for fonts"`
-	FontsAvail map[string]string                 `desc:"map of font name to path to file"`
-	FontInfo   []FontInfo                        `desc:"information about each font -- this list should be used for selecting valid regularized font names"`
-	Faces      map[string]map[int]*gist.FontFace `desc:"double-map of cached fonts, by font name and then integer font size within that"`
+	FontPaths    []string                          `desc:"list of font paths to search for fonts"`
+	FontsAvail   map[string]string                 `desc:"map of font name to path to file"`
+	FontInfo     []FontInfo                        `desc:"information about each font -- this list should be used for selecting valid regularized font names"`
+	Faces        map[string]map[int]*gist.FontFace `desc:"double-map of cached fonts, by font name and then integer font size within that"`
+	FeatureFaces map[string]*gist.FontFace         `desc:"cached fonts with font feature settings applied, keyed by font name, size and features"`
+	FontBytes    map[string][]byte                 `desc:"in-memory font file bytes added via AddFontBytes, keyed by FontMemPrefix path"`
 }
 
 // FontLibrary is the gi font library, initialized from fonts available on font paths
@@ -87,6 +91,7 @@ func (fl *FontLib) Init() {
 		fl.FontsAvail = make(map[string]string)
 		fl.FontInfo = make([]FontInfo, 0)
 		fl.Faces = make(map[string]map[int]*gist.FontFace)
+		fl.FeatureFaces = make(map[string]*gist.FontFace)
 		loadFontMu.Unlock()
 		return // no paths to load from yet
 	}
@@ -180,6 +185,91 @@ func (fl *FontLib) AddFontPaths(paths ...string) bool {
 	return fl.UpdateFontsAvail()
 }
 
+// FontLibChangedFunc, if set, is called after fonts have been added to the
+// FontLibrary at runtime via AddFontDir or AddFontBytes -- gi sets this to
+// update all open windows so they can use the new fonts.
+var FontLibChangedFunc func()
+
+// FontMemPrefix is the path prefix used for fonts added from in-memory
+// bytes via AddFontBytes
+const FontMemPrefix = "mem/"
+
+// AddFontDir adds given directory to the list of FontPaths at runtime,
+// making all the fonts in it available, and updates all open windows.
+// Returns false if no fonts are available after adding the directory.
+func (fl *FontLib) AddFontDir(dir string) bool {
+	for _, p := range fl.FontPaths {
+		if p == dir {
+			return true
+		}
+	}
+	ok := fl.AddFontPaths(dir)
+	fl.FontsChanged()
+	return ok
+}
+
+// AddFontBytes adds a font from given in-memory font file bytes (.ttf or
+// .otf format) under the given font name (e.g., "Inter Bold" -- standard
+// modifiers in the name are used to determine weight, style etc as for
+// font files), and updates all open windows.  A previously-added font of
+// the same name is replaced.  This can be used with embedded fonts, e.g.,
+// from an embed.FS.
+func (fl *FontLib) AddFontBytes(name string, data []byte) error {
+	if _, err := truetype.Parse(data); err != nil {
+		if _, oerr := opentype.Parse(data); oerr != nil {
+			return fmt.Errorf("gi.FontLib: AddFontBytes: font %v could not be parsed: %v", name, err)
+		}
+	}
+	fl.Init()
+	fn := gist.FixFontMods(name)
+	path := FontMemPrefix + fn
+	loadFontMu.Lock()
+	if fl.FontBytes == nil {
+		fl.FontBytes = make(map[string][]byte)
+	}
+	fl.FontBytes[path] = data
+	fl.addFontInfo(fn, path)
+	fl.FontsAvail[strings.ToLower(fn)] = path // in-memory fonts take precedence
+	sort.Slice(fl.FontInfo, func(i, j int) bool {
+		return fl.FontInfo[i].Name < fl.FontInfo[j].Name
+	})
+	loadFontMu.Unlock()
+	fl.FontsChanged()
+	return nil
+}
+
+// addFontInfo adds font of given regularized name at given path to the
+// FontsAvail and FontInfo lists, if not already present.  loadFontMu must
+// be locked.
+func (fl *FontLib) addFontInfo(fn, path string) {
+	basefn := strings.ToLower(fn)
+	if _, ok := fl.FontsAvail[basefn]; ok {
+		return
+	}
+	fl.FontsAvail[basefn] = path
+	fi := FontInfo{Name: fn, Example: FontInfoExample}
+	_, fi.Stretch, fi.Weight, fi.Style = gist.FontNameToMods(fn)
+	fl.FontInfo = append(fl.FontInfo, fi)
+}
+
+// FontsChanged invalidates all cached font faces and face name lookups, so
+// that subsequent font opening uses the current set of available fonts, and
+// calls FontLibChangedFunc to update any open windows.  Call this after
+// changing the available fonts directly.
+func (fl *FontLib) FontsChanged() {
+	loadFontMu.Lock()
+	fl.Faces = make(map[string]map[int]*gist.FontFace)
+	fl.FeatureFaces = make(map[string]*gist.FontFace)
+	loadFontMu.Unlock()
+	clearGlyphOutlines()
+	faceNameCacheMu.Lock()
+	faceNameCache = nil
+	faceNameCacheMu.Unlock()
+	if FontLibChangedFunc != nil {
+		FontLibChangedFunc()
+	}
+}
+
 // UpdateFontsAvail scans for all fonts we can use on the FontPaths
 func (fl *FontLib) UpdateFontsAvail() bool {
 	if len(fl.FontPaths) == 0 {
@@ -190,10 +280,18 @@ func (fl *FontLib) UpdateFontsAvail() bool {
 	defer loadFontMu.Unlock()
 	if len(fl.FontsAvail) > 0 {
 		fl.FontsAvail = make(map[string]string)
+		fl.FontInfo = make([]FontInfo, 0)
 	}
 	fl.GoFontsAvail()
+	fc := openFontCache()
 	for _, p := range fl.FontPaths {
-		fl.FontsAvailFromPath(p)
+		fl.fontsAvailFromPathCached(p, fc)
+	}
+	fc.save()
+	for path := range fl.FontBytes {
+		fn := strings.TrimPrefix(path, FontMemPrefix)
+		fl.addFontInfo(fn, path)
+		fl.FontsAvail[strings.ToLower(fn)] = path
 	}
 	sort.Slice(fl.FontInfo, func(i, j int) bool {
 		return fl.FontInfo[i].Name < fl.FontInfo[j].Name
@@ -205,11 +303,28 @@ func (fl *FontLib) UpdateFontsAvail() bool {
 // FontsAvailFromPath scans for all fonts we can use on a given path,
 // gathering info into FontsAvail and FontInfo.
 func (fl *FontLib) FontsAvailFromPath(path string) error {
+	fonts, _, err := scanFontPath(path)
+	for _, fe := range fonts {
+		fl.addFontInfo(fe.Name, fe.Path)
+	}
+	return err
+}
+
+// scanFontPath scans for all fonts we can use on a given path, returning
+// their regularized names and files, and the modification times of all the
+// directories scanned.
+func scanFontPath(path string) ([]fontCacheEntry, map[string]time.Time, error) {
+	var fonts []fontCacheEntry
+	dirs := make(map[string]time.Time)
 	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			fmt.Printf("gi.FontLib: error accessing path %q: %v\n", path, err)
 			return err
 		}
+		if info.IsDir() {
+			dirs[path] = info.ModTime()
+			return nil
+		}
 		ext := strings.ToLower(filepath.Ext(path))
 		_, ok := FontExts[ext]
 		if !ok {
@@ -248,21 +363,13 @@ func (fl *FontLib) FontsAvailFromPath(path string) error {
 			}
 		}
 		fn = gist.FixFontMods(fn)
-		basefn := strings.ToLower(fn)
-		if _, ok := fl.FontsAvail[basefn]; !ok {
-			fl.FontsAvail[basefn] = path
-			fi := FontInfo{Name: fn, Example: FontInfoExample}
-			_, fi.Stretch, fi.Weight, fi.Style = gist.FontNameToMods(fn)
-			fl.FontInfo = append(fl.FontInfo, fi)
-			// fmt.Printf("added font: %v at path %q\n", basefn, path)
-
-		}
+		fonts = append(fonts, fontCacheEntry{Name: fn, Path: path})
 		return nil
 	})
 	if err != nil {
 		log.Printf("gi.FontLib: error walking the path %q: %v\n", path, err)
 	}
-	return err
+	return fonts, dirs, err
 }
 
 var FontExts = map[string]struct{}{
@@ -354,6 +461,7 @@ func OpenGoFont(name, path string, size int, strokeWidth int) (*gist.FontFace, e
 		// GlyphCacheEntries: 1024, // default is 512 -- todo benchmark
 
 	})
+	setGlyphOutlines(face, gf.ttf, size)
 	ff := gist.NewFontFace(name, size, face)
 	return ff, nil
 }
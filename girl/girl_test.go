@@ -15,7 +15,9 @@ import (
 	"testing"
 
 	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
 	"github.com/goki/mat32"
+	"golang.org/x/image/font/basicfont"
 )
 
 // TestPrefs are needed for setting gist.ThePrefs, for any text-based
@@ -161,3 +163,175 @@ func TestRender(t *testing.T) {
 	defer file.Close()
 	png.Encode(file, img)
 }
+
+func TestPosToRune(t *testing.T) {
+	sr := Span{RelPos: mat32.Vec2{10, 20}}
+	sr.Init(3)
+	for i, r := range "abc" {
+		sr.Text = append(sr.Text, r)
+		sr.Render = append(sr.Render, Rune{RelPos: mat32.Vec2{float32(7 * i), 0}, Size: mat32.Vec2{7, 11}})
+	}
+	sr.Render[0].Face = basicfont.Face7x13
+	sr.Render[0].Color = color.Black
+	tr := &Text{Spans: []Span{sr}}
+
+	tests := []struct {
+		pos   mat32.Vec2
+		idx   int
+		after bool
+	}{
+		{mat32.Vec2{11, 15}, 0, false},
+		{mat32.Vec2{16, 15}, 0, true},
+		{mat32.Vec2{18, 15}, 1, false},
+		{mat32.Vec2{0, 15}, 0, false},
+		{mat32.Vec2{100, 40}, 2, true},
+	}
+	for _, ts := range tests {
+		idx, after, ok := tr.PosToRune(ts.pos)
+		if !ok || idx != ts.idx || after != ts.after {
+			t.Errorf("PosToRune(%v): got %v, %v, %v, want %v, %v", ts.pos, idx, after, ok, ts.idx, ts.after)
+		}
+	}
+
+	// rotated by 90 degrees, so the text runs down from the span position
+	for i := range tr.Spans[0].Render {
+		rr := &tr.Spans[0].Render[i]
+		rr.RotRad = mat32.Pi / 2
+		rr.RelPos = mat32.Vec2{0, float32(7 * i)}
+	}
+	idx, after, ok := tr.PosToRune(mat32.Vec2{15, 33})
+	if !ok || idx != 1 || !after {
+		t.Errorf("PosToRune rotated: got %v, %v, %v, want 1, true", idx, after, ok)
+	}
+
+	if _, _, ok := (&Text{}).PosToRune(mat32.Vec2{}); ok {
+		t.Errorf("PosToRune of empty Text should not be ok")
+	}
+}
+
+func TestBlendImage(t *testing.T) {
+	r := image.Rect(0, 0, 1, 1)
+	tests := []struct {
+		dst, src color.RGBA
+		opacity  float32
+		mode     gist.BlendModes
+		want     color.RGBA
+	}{
+		{color.RGBA{255, 255, 255, 255}, color.RGBA{255, 0, 0, 255}, 1, gist.BlendNormal, color.RGBA{255, 0, 0, 255}},
+		{color.RGBA{255, 255, 255, 255}, color.RGBA{255, 0, 0, 255}, 0.5, gist.BlendNormal, color.RGBA{255, 128, 128, 255}},
+		{color.RGBA{128, 255, 0, 255}, color.RGBA{128, 128, 128, 255}, 1, gist.BlendMultiply, color.RGBA{64, 128, 0, 255}},
+		{color.RGBA{128, 255, 0, 255}, color.RGBA{128, 128, 128, 255}, 1, gist.BlendScreen, color.RGBA{192, 255, 128, 255}},
+		{color.RGBA{0, 0, 0, 0}, color.RGBA{128, 0, 0, 128}, 1, gist.BlendMultiply, color.RGBA{128, 0, 0, 128}},
+	}
+	for _, ts := range tests {
+		dst := image.NewRGBA(r)
+		src := image.NewRGBA(r)
+		dst.SetRGBA(0, 0, ts.dst)
+		src.SetRGBA(0, 0, ts.src)
+		BlendImage(dst, src, r, ts.opacity, ts.mode)
+		got := dst.RGBAAt(0, 0)
+		for c, v := range []uint8{got.R - ts.want.R, got.G - ts.want.G, got.B - ts.want.B, got.A - ts.want.A} {
+			if v > 1 && v < 255 { // allow rounding
+				t.Errorf("BlendImage %v %v over %v: channel %d: got %v, want %v", ts.mode, ts.src, ts.dst, c, got, ts.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSetHTMLPlain(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+
+	pc := &Paint{}
+	pc.Defaults()
+	pc.SetUnitContextExt(image.Point{320, 240})
+	tsty := &gist.Text{}
+	tsty.Defaults()
+	fsty := &gist.Font{}
+	fsty.Defaults()
+
+	plain := &Text{}
+	plain.SetHTML("  plain\ttext,  no tags ", fsty, tsty, &pc.UnContext, nil)
+	tagged := &Text{}
+	tagged.SetHTML("plain text, <span>no</span> tags", fsty, tsty, &pc.UnContext, nil)
+	if len(plain.Spans) != 1 || string(plain.Spans[0].Text) != string(tagged.Spans[0].Text) {
+		t.Fatalf("plain text: got %q, want %q", string(plain.Spans[0].Text), string(tagged.Spans[0].Text))
+	}
+	if pr, tr := plain.Spans[0].Render[0], tagged.Spans[0].Render[0]; pr.Face != tr.Face || pr.Color != tr.Color {
+		t.Errorf("plain text style: got %v, %v, want %v, %v", pr.Face, pr.Color, tr.Face, tr.Color)
+	}
+
+	ent := &Text{}
+	ent.SetHTML("a &amp; b", fsty, tsty, &pc.UnContext, nil)
+	if got := string(ent.Spans[0].Text); got != "a & b" {
+		t.Errorf("text with entity: got %q, want %q", got, "a & b")
+	}
+
+	rnd := &plain.Spans[0].Render[0]
+	plain.SetHTML("other text", fsty, tsty, &pc.UnContext, nil)
+	if &plain.Spans[0].Render[0] != rnd {
+		t.Errorf("render info not reused when setting the text again")
+	}
+	if got := string(plain.Spans[0].Text); got != "other text" {
+		t.Errorf("text set again: got %q, want %q", got, "other text")
+	}
+}
+
+func TestRenderShadows(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+	FontLibrary.InitFontPaths("/usr/share/fonts/truetype")
+
+	render := func(shadow string) *image.RGBA {
+		rs, pc, img := newTestState(image.Point{120, 60})
+		tsty := &gist.Text{}
+		tsty.Defaults()
+		fsty := &gist.Font{}
+		fsty.Defaults()
+		fsty.Size.SetDot(40)
+		fsty.ToDots(&pc.UnContext)
+		fsty.Color = gist.Black
+		if shadow != "" {
+			fsty.SetStyleProps(nil, ki.Props{"text-shadow": shadow}, nil)
+		}
+		OpenFont(fsty, &pc.UnContext)
+		tr := &Text{}
+		tr.SetString("HI", fsty, &pc.UnContext, tsty, true, 0, 0)
+		tr.Render(rs, mat32.Vec2{10, 45})
+		return img
+	}
+	reddish := func(img *image.RGBA) (n, solid int) {
+		for y := 0; y < img.Bounds().Dy(); y++ {
+			for x := 0; x < img.Bounds().Dx(); x++ {
+				c := img.RGBAAt(x, y)
+				if int(c.R) > int(c.G)+32 {
+					n++
+					if c == (color.RGBA{0xff, 0, 0, 0xff}) {
+						solid++
+					}
+				}
+			}
+		}
+		return
+	}
+	if n, _ := reddish(render("")); n != 0 {
+		t.Errorf("red pixels without text-shadow: got %d, want 0", n)
+	}
+	sharp := render("6px 6px red")
+	n, solid := reddish(sharp)
+	if solid < 100 {
+		t.Errorf("red pixels with sharp text-shadow: got %d, want shadows of H and I", solid)
+	}
+	// the glyphs are drawn over their shadow
+	blk := color.RGBA{0, 0, 0, 0xff}
+	if c := sharp.RGBAAt(14, 30); c != blk {
+		t.Errorf("left stem of H over its shadow: got %v, want black", c)
+	}
+	bn, bsolid := reddish(render("6px 6px 8px red"))
+	if bn <= n || bsolid >= solid {
+		t.Errorf("blurred text-shadow: got %d red pixels, %d solid, want more than %d, fewer than %d", bn, bsolid, n, solid)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"container/list"
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/goki/mat32"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// GlyphCacheSize is the maximum number of rasterized glyph masks kept in
+// the glyph cache, which is used by Text.Render so that the glyphs of text
+// that is rendered repeatedly, e.g., in labels, buttons and text editors,
+// are only rasterized once -- the least recently used glyphs are dropped
+// when it is full.  Set to 0 to disable the cache.
+var GlyphCacheSize = 4096
+
+// GlyphSubpixelX and GlyphSubpixelY are the number of distinct subpixel
+// positions of the glyphs in the glyph cache, along each axis: the
+// fractional part of the position of each glyph is rounded to the nearest
+// of these -- the x positions include thirds of a pixel, for
+// DrawGlyphSubpixel.
+const (
+	GlyphSubpixelX = 6
+	GlyphSubpixelY = 2
+)
+
+// glyphKey is the key of a cached glyph: the face, which determines the
+// font and its size, the rune, or NoGlyph and the index of the glyph in
+// the font (see CachedGlyphIndex), and the subpixel position
+type glyphKey struct {
+	face   font.Face
+	r      rune
+	idx    sfnt.GlyphIndex
+	fx, fy int32
+}
+
+// glyphMask is a cached glyph coverage mask, with its bounds relative to
+// the integer part of the glyph position -- the mask itself starts at 0,0
+type glyphMask struct {
+	key  glyphKey
+	rect image.Rectangle
+	mask *image.Alpha
+	ok   bool
+}
+
+// glyphCache is the cache of glyph masks and face metrics, shared by all
+// the windows -- it is protected by its own mutex, and the faces are only
+// used, under the TextFontRenderMu, to rasterize the glyphs that are not
+// yet in the cache
+var glyphCache struct {
+	sync.Mutex
+	lru     *list.List
+	glyphs  map[glyphKey]*list.Element
+	metrics map[font.Face]font.Metrics
+}
+
+// ClearGlyphCache clears the glyph cache, e.g., after changing the fonts,
+// to release the faces and glyph masks that it holds
+func ClearGlyphCache() {
+	gc := &glyphCache
+	gc.Lock()
+	gc.lru = nil
+	gc.glyphs = nil
+	gc.metrics = nil
+	gc.Unlock()
+}
+
+// glyphPos splits given glyph position into its integer part and the index
+// of its subpixel position, with given number of subpixel positions
+func glyphPos(v fixed.Int26_6, n int32) (int, int32) {
+	i := v.Floor()
+	f := (int32(v-fixed.I(i))*n + 32) / 64
+	if f == n {
+		i++
+		f = 0
+	}
+	return i, f
+}
+
+// CachedGlyph returns the coverage mask of the glyph for given rune in
+// given face at given position (the dot, as in Face.Glyph), from the glyph
+// cache if it is there, and otherwise rasterizing it and adding it to the
+// cache -- the fractional part of the position is rounded to the nearest
+// of GlyphSubpixelX, GlyphSubpixelY positions.  The returned rectangle is
+// where the glyph is drawn, and the returned mask starts at 0,0 and must
+// not be modified.  Returns false if the face has no glyph for the rune.
+func CachedGlyph(face font.Face, dot fixed.Point26_6, r rune) (image.Rectangle, *image.Alpha, bool) {
+	return cachedGlyph(glyphKey{face: face, r: r}, dot, func(fdot fixed.Point26_6) (image.Rectangle, *image.Alpha, bool) {
+		TextFontRenderMu.Lock()
+		defer TextFontRenderMu.Unlock()
+		dr, mask, maskp, _, ok := face.Glyph(fdot, r)
+		if !ok {
+			return image.Rectangle{}, nil, false
+		}
+		// glyph masks are reused by the face, so we need our own copy
+		gm := image.NewAlpha(image.Rectangle{Max: dr.Size()})
+		draw.Draw(gm, gm.Rect, mask, maskp, draw.Src)
+		return dr, gm, true
+	})
+}
+
+// CachedGlyphIndex returns the coverage mask of the glyph with given index
+// in the font of given face, rasterized from its outline, at given
+// position, as CachedGlyph does for runes -- for the glyphs of clusters
+// shaped by glyph index (see ClusterGlyph).  Returns false if the face has
+// no outlines (see GlyphOutline), or the glyph is not in its font.
+func CachedGlyphIndex(face font.Face, dot fixed.Point26_6, idx sfnt.GlyphIndex) (image.Rectangle, *image.Alpha, bool) {
+	return cachedGlyph(glyphKey{face: face, r: NoGlyph, idx: idx}, dot, func(fdot fixed.Point26_6) (image.Rectangle, *image.Alpha, bool) {
+		pos := mat32.Vec2{mat32.FromFixed(fdot.X), mat32.FromFixed(fdot.Y)}
+		return rasterGlyphIndex(face, idx, pos, mat32.Identity2D())
+	})
+}
+
+// cachedGlyph returns the glyph mask with given key, at given position,
+// from the glyph cache if it is there, and otherwise rasterizing it with
+// given function, at the subpixel position of the dot, and adding it to
+// the cache
+func cachedGlyph(key glyphKey, dot fixed.Point26_6, raster func(fdot fixed.Point26_6) (image.Rectangle, *image.Alpha, bool)) (image.Rectangle, *image.Alpha, bool) {
+	ix, fx := glyphPos(dot.X, GlyphSubpixelX)
+	iy, fy := glyphPos(dot.Y, GlyphSubpixelY)
+	off := image.Point{ix, iy}
+	key.fx, key.fy = fx, fy
+	gc := &glyphCache
+	gc.Lock()
+	if el, has := gc.glyphs[key]; has {
+		gc.lru.MoveToFront(el)
+		gm := el.Value.(*glyphMask)
+		gc.Unlock()
+		return gm.rect.Add(off), gm.mask, gm.ok
+	}
+	gc.Unlock()
+
+	gm := &glyphMask{key: key}
+	fdot := fixed.Point26_6{X: fixed.Int26_6(fx * 64 / GlyphSubpixelX), Y: fixed.Int26_6(fy * 64 / GlyphSubpixelY)}
+	gm.rect, gm.mask, gm.ok = raster(fdot)
+
+	if GlyphCacheSize > 0 {
+		gc.Lock()
+		if gc.glyphs == nil {
+			gc.lru = list.New()
+			gc.glyphs = make(map[glyphKey]*list.Element)
+		}
+		if _, has := gc.glyphs[key]; !has { // could have been added in the meantime
+			gc.glyphs[key] = gc.lru.PushFront(gm)
+			for gc.lru.Len() > GlyphCacheSize {
+				el := gc.lru.Back()
+				delete(gc.glyphs, el.Value.(*glyphMask).key)
+				gc.lru.Remove(el)
+			}
+		}
+		gc.Unlock()
+	}
+	return gm.rect.Add(off), gm.mask, gm.ok
+}
+
+// FaceMetrics returns the metrics of given face, which are cached, so that
+// it can be called while rendering without the TextFontRenderMu
+func FaceMetrics(face font.Face) font.Metrics {
+	gc := &glyphCache
+	gc.Lock()
+	m, has := gc.metrics[face]
+	gc.Unlock()
+	if has {
+		return m
+	}
+	TextFontRenderMu.Lock()
+	m = face.Metrics()
+	TextFontRenderMu.Unlock()
+	gc.Lock()
+	if gc.metrics == nil {
+		gc.metrics = make(map[font.Face]font.Metrics)
+	}
+	gc.metrics[face] = m
+	gc.Unlock()
+	return m
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+func TestCachedGlyph(t *testing.T) {
+	ClearGlyphCache()
+	defer ClearGlyphCache()
+	face := basicfont.Face7x13
+	dot := fixed.P(10, 20)
+	dr, mask, ok := CachedGlyph(face, dot, 'A')
+	if !ok {
+		t.Fatalf("no glyph for A")
+	}
+	wdr, _, _, _, _ := face.Glyph(dot, 'A')
+	if dr != wdr {
+		t.Errorf("glyph bounds: got %v, want %v", dr, wdr)
+	}
+	if mask.Rect.Min != image.ZP || mask.Rect.Size() != dr.Size() {
+		t.Errorf("glyph mask bounds: got %v, want size %v at 0,0", mask.Rect, dr.Size())
+	}
+	dr2, mask2, _ := CachedGlyph(face, fixed.P(30, 40), 'A')
+	if mask2 != mask {
+		t.Errorf("glyph mask not reused from cache")
+	}
+	if want := dr.Add(image.Point{20, 20}); dr2 != want {
+		t.Errorf("cached glyph bounds: got %v, want %v", dr2, want)
+	}
+	if _, _, ok := CachedGlyph(face, dot, '一'); ok {
+		t.Errorf("got a glyph for a rune not in the face")
+	}
+}
+
+func TestGlyphCacheEvict(t *testing.T) {
+	ClearGlyphCache()
+	defer ClearGlyphCache()
+	defer func(sz int) { GlyphCacheSize = sz }(GlyphCacheSize)
+	GlyphCacheSize = 4
+	face := basicfont.Face7x13
+	_, ma, _ := CachedGlyph(face, fixed.P(0, 0), 'a')
+	for _, r := range "bcde" {
+		CachedGlyph(face, fixed.P(0, 0), r)
+	}
+	if n := len(glyphCache.glyphs); n != GlyphCacheSize {
+		t.Errorf("glyph cache size: got %d, want %d", n, GlyphCacheSize)
+	}
+	if _, ma2, _ := CachedGlyph(face, fixed.P(0, 0), 'a'); ma2 == ma {
+		t.Errorf("least recently used glyph not evicted")
+	}
+	_, me, _ := CachedGlyph(face, fixed.P(0, 0), 'e')
+	if _, me2, _ := CachedGlyph(face, fixed.P(0, 0), 'e'); me2 != me {
+		t.Errorf("recently used glyph evicted")
+	}
+}
+
+func TestGlyphPos(t *testing.T) {
+	tests := []struct {
+		v    fixed.Int26_6
+		i    int
+		f    int32
+		desc string
+	}{
+		{fixed.I(3), 3, 0, "integer"},
+		{fixed.I(3) + 21, 3, 2, "third"},
+		{fixed.I(3) + 63, 4, 0, "rounds up to next"},
+		{-fixed.I(1) + 32, -1, 3, "negative half"},
+	}
+	for _, tt := range tests {
+		if i, f := glyphPos(tt.v, GlyphSubpixelX); i != tt.i || f != tt.f {
+			t.Errorf("glyphPos %s: got %d, %d, want %d, %d", tt.desc, i, f, tt.i, tt.f)
+		}
+	}
+}
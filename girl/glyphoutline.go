@@ -0,0 +1,262 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/goki/mat32"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// faceOutlines is the source of the glyph outlines of a face: the font file
+// that it was opened from, which is parsed when first needed, and the size
+// of the face
+type faceOutlines struct {
+	src  []byte
+	ppem fixed.Int26_6
+	once sync.Once
+	font *sfnt.Font
+}
+
+// parsed returns the parsed font, or nil if it could not be parsed
+func (fo *faceOutlines) parsed() *sfnt.Font {
+	fo.once.Do(func() {
+		fo.font, _ = sfnt.Parse(fo.src)
+	})
+	return fo.font
+}
+
+// segments returns the outline segments of the glyph with given index,
+// loaded at the size of the face, or false if it is not in the font
+func (fo *faceOutlines) segments(buf *sfnt.Buffer, idx sfnt.GlyphIndex) (sfnt.Segments, bool) {
+	f := fo.parsed()
+	if f == nil || idx == 0 {
+		return nil, false
+	}
+	if buf == nil {
+		buf = &sfnt.Buffer{}
+	}
+	segs, err := f.LoadGlyph(buf, idx, fo.ppem, nil)
+	if err != nil {
+		return nil, false
+	}
+	return segs, true
+}
+
+// glyphOutlines are the sources of the glyph outlines of the faces opened
+// by OpenFontFace, by face
+var glyphOutlines struct {
+	sync.Mutex
+	faces map[font.Face]*faceOutlines
+}
+
+// setGlyphOutlines records the font file that given face was opened from,
+// with given size in dots, as the source of its glyph outlines
+func setGlyphOutlines(face font.Face, src []byte, size int) {
+	gl := &glyphOutlines
+	gl.Lock()
+	if gl.faces == nil {
+		gl.faces = make(map[font.Face]*faceOutlines)
+	}
+	gl.faces[face] = &faceOutlines{src: src, ppem: fixed.I(size)}
+	gl.Unlock()
+}
+
+// clearGlyphOutlines clears the sources of the glyph outlines, e.g., after
+// the cached faces are dropped, to release the font files
+func clearGlyphOutlines() {
+	gl := &glyphOutlines
+	gl.Lock()
+	gl.faces = nil
+	gl.Unlock()
+}
+
+// faceGlyphOutlines returns the source of the glyph outlines of given face,
+// or nil if there is none
+func faceGlyphOutlines(face font.Face) *faceOutlines {
+	if ff, ok := face.(*FeatureFace); ok {
+		face = ff.Face
+	}
+	gl := &glyphOutlines
+	gl.Lock()
+	defer gl.Unlock()
+	return gl.faces[face]
+}
+
+// GlyphOutline adds the outline of the glyph of given rune in given face to
+// the current path of given paint, as closed subpaths, with the origin of
+// the glyph (the start of its baseline) at pos, and scaled and rotated by
+// tx (as in Text.Render) -- e.g., to stroke it.  buf is used to load the
+// glyph: it can be nil, but reusing one is faster (it must not be used by
+// other goroutines at the same time).  Returns false if the face has no
+// outlines (i.e., it was not opened from a font file by OpenFontFace), or
+// the glyph is not in its font.
+func GlyphOutline(rs *State, pc *Paint, face font.Face, r rune, pos mat32.Vec2, tx mat32.Mat2, buf *sfnt.Buffer) bool {
+	fo := faceGlyphOutlines(face)
+	if fo == nil {
+		return false
+	}
+	f := fo.parsed()
+	if f == nil {
+		return false
+	}
+	if buf == nil {
+		buf = &sfnt.Buffer{}
+	}
+	gi, err := f.GlyphIndex(buf, r)
+	if err != nil {
+		return false
+	}
+	return GlyphIndexOutline(rs, pc, face, gi, pos, tx, buf)
+}
+
+// GlyphIndexOutline adds the outline of the glyph with given index in the
+// font of given face to the current path of given paint, as GlyphOutline
+// does for the glyph of a rune -- for the glyphs of clusters shaped by
+// glyph index (see ClusterGlyph).
+func GlyphIndexOutline(rs *State, pc *Paint, face font.Face, idx sfnt.GlyphIndex, pos mat32.Vec2, tx mat32.Mat2, buf *sfnt.Buffer) bool {
+	fo := faceGlyphOutlines(face)
+	if fo == nil {
+		return false
+	}
+	segs, ok := fo.segments(buf, idx)
+	if !ok {
+		return false
+	}
+	pt := func(p fixed.Point26_6) mat32.Vec2 { // y is down, as in the face
+		return pos.Add(tx.MulVec2AsVec(mat32.Vec2{mat32.FromFixed(p.X), mat32.FromFixed(p.Y)}))
+	}
+	open := false
+	for _, sg := range segs {
+		switch sg.Op {
+		case sfnt.SegmentOpMoveTo:
+			if open {
+				pc.ClosePath(rs)
+				pc.NewSubPath(rs)
+			}
+			p := pt(sg.Args[0])
+			pc.MoveTo(rs, p.X, p.Y)
+			open = true
+		case sfnt.SegmentOpLineTo:
+			p := pt(sg.Args[0])
+			pc.LineTo(rs, p.X, p.Y)
+		case sfnt.SegmentOpQuadTo:
+			p1, p2 := pt(sg.Args[0]), pt(sg.Args[1])
+			pc.QuadraticTo(rs, p1.X, p1.Y, p2.X, p2.Y)
+		case sfnt.SegmentOpCubeTo:
+			p1, p2, p3 := pt(sg.Args[0]), pt(sg.Args[1]), pt(sg.Args[2])
+			pc.CubicTo(rs, p1.X, p1.Y, p2.X, p2.Y, p3.X, p3.Y)
+		}
+	}
+	if open {
+		pc.ClosePath(rs)
+		pc.NewSubPath(rs)
+	}
+	return true
+}
+
+// rasterGlyphIndex rasterizes the outline of the glyph with given index in
+// the font of given face, with its origin at pos, and scaled and rotated by
+// tx, returning the coverage mask, starting at 0,0, and the rectangle
+// where it is drawn -- false if there is no outline for the glyph
+func rasterGlyphIndex(face font.Face, idx sfnt.GlyphIndex, pos mat32.Vec2, tx mat32.Mat2) (image.Rectangle, *image.Alpha, bool) {
+	fo := faceGlyphOutlines(face)
+	if fo == nil {
+		return image.Rectangle{}, nil, false
+	}
+	segs, ok := fo.segments(nil, idx)
+	if !ok {
+		return image.Rectangle{}, nil, false
+	}
+	pts := make([]mat32.Vec2, 0, 3*len(segs))
+	for _, sg := range segs {
+		n := 1
+		switch sg.Op {
+		case sfnt.SegmentOpQuadTo:
+			n = 2
+		case sfnt.SegmentOpCubeTo:
+			n = 3
+		}
+		for _, p := range sg.Args[:n] { // y is down, as in the face
+			pts = append(pts, pos.Add(tx.MulVec2AsVec(mat32.Vec2{mat32.FromFixed(p.X), mat32.FromFixed(p.Y)})))
+		}
+	}
+	if len(pts) == 0 { // e.g., a space
+		return image.Rectangle{}, image.NewAlpha(image.Rectangle{}), true
+	}
+	bmin, bmax := pts[0], pts[0]
+	for _, p := range pts[1:] { // the curves are within their control points
+		bmin, bmax = bmin.Min(p), bmax.Max(p)
+	}
+	dr := image.Rect(int(mat32.Floor(bmin.X)), int(mat32.Floor(bmin.Y)), int(mat32.Ceil(bmax.X)), int(mat32.Ceil(bmax.Y)))
+	z := vector.NewRasterizer(dr.Dx(), dr.Dy())
+	off := mat32.Vec2{float32(dr.Min.X), float32(dr.Min.Y)}
+	pi := 0
+	pt := func() (float32, float32) {
+		p := pts[pi].Sub(off)
+		pi++
+		return p.X, p.Y
+	}
+	for _, sg := range segs {
+		switch sg.Op {
+		case sfnt.SegmentOpMoveTo:
+			z.ClosePath()
+			z.MoveTo(pt())
+		case sfnt.SegmentOpLineTo:
+			z.LineTo(pt())
+		case sfnt.SegmentOpQuadTo:
+			x1, y1 := pt()
+			x2, y2 := pt()
+			z.QuadTo(x1, y1, x2, y2)
+		case sfnt.SegmentOpCubeTo:
+			x1, y1 := pt()
+			x2, y2 := pt()
+			x3, y3 := pt()
+			z.CubeTo(x1, y1, x2, y2, x3, y3)
+		}
+	}
+	z.ClosePath()
+	mask := image.NewAlpha(image.Rectangle{Max: dr.Size()})
+	z.Draw(mask, mask.Rect, image.Opaque, image.Point{})
+	return dr, mask, true
+}
+
+// DrawGlyphIndex draws the glyph with given index in the font of given
+// face into dst, restricted to bounds, in the color of src, with its
+// origin (the start of its baseline) at rp, and rotated and scaled in X as
+// given by the RotRad and ScaleX of Rune, as DrawGlyph does for the glyph
+// of a rune -- for the glyphs of clusters shaped by glyph index (see
+// ClusterGlyph).  The glyph is rasterized from its outline, and is only
+// cached if it is not rotated or scaled.  Returns false if the face has no
+// outlines (see GlyphOutline), or the glyph is not in its font.
+func DrawGlyphIndex(dst *image.RGBA, bounds image.Rectangle, face font.Face, idx sfnt.GlyphIndex, rp mat32.Vec2, src *image.Uniform, rot, scalex float32) bool {
+	var dr image.Rectangle
+	var mask *image.Alpha
+	var ok bool
+	if rot == 0 && (scalex == 0 || scalex == 1) {
+		dr, mask, ok = CachedGlyphIndex(face, rp.Fixed(), idx)
+	} else {
+		scx := float32(1)
+		if scalex != 0 {
+			scx = scalex
+		}
+		dr, mask, ok = rasterGlyphIndex(face, idx, rp, mat32.Scale2D(scx, 1).Rotate(rot))
+	}
+	if !ok {
+		return false
+	}
+	idr := dr.Intersect(bounds)
+	if idr.Empty() {
+		return true
+	}
+	draw.DrawMask(dst, idr, src, image.Point{}, mask, idr.Min.Sub(dr.Min), draw.Over)
+	return true
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/bitflag"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+	"golang.org/x/image/font/basicfont"
+)
+
+// newTestState returns a render state for a white image of given size
+func newTestState(sz image.Point) (*State, *Paint, *image.RGBA) {
+	img := image.NewRGBA(image.Rectangle{Max: sz})
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	rs := &State{}
+	pc := &Paint{}
+	pc.Defaults()
+	pc.SetUnitContextExt(sz)
+	rs.Init(sz.X, sz.Y, img)
+	rs.PushBounds(img.Bounds())
+	return rs, pc, img
+}
+
+// countColor returns the number of pixels of the image in given color
+func countColor(img *image.RGBA, clr color.RGBA) int {
+	n := 0
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			if img.RGBAAt(x, y) == clr {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestGlyphOutline(t *testing.T) {
+	ff, err := OpenGoFont("Go", "gofont/goregular", 40, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, pc, img := newTestState(image.Point{64, 64})
+	pc.FillStyle.SetColor(color.Black)
+	if !GlyphOutline(rs, pc, ff.Face, 'H', mat32.Vec2{10, 50}, mat32.Identity2D(), nil) {
+		t.Fatal("no outline for H")
+	}
+	pc.Fill(rs)
+	blk := color.RGBA{0, 0, 0, 0xff}
+	// the left stem of the H is just right of its origin, above the baseline
+	if c := img.RGBAAt(15, 40); c != blk {
+		t.Errorf("left stem of H: got %v, want black", c)
+	}
+	if c := img.RGBAAt(15, 55); c == blk {
+		t.Errorf("below the baseline: got black, want white")
+	}
+	if c := img.RGBAAt(22, 24); c == blk {
+		t.Errorf("between the stems of H: got black, want white")
+	}
+
+	// tx rotates the glyph around its origin, as in Text.Render
+	rs, pc, img = newTestState(image.Point{64, 64})
+	pc.FillStyle.SetColor(color.Black)
+	GlyphOutline(rs, pc, ff.Face, 'H', mat32.Vec2{14, 10}, mat32.Rotate2D(mat32.Pi/2), nil)
+	pc.Fill(rs)
+	if c := img.RGBAAt(24, 15); c != blk {
+		t.Errorf("left stem of H rotated 90 degrees: got %v, want black", c)
+	}
+
+	if GlyphOutline(rs, pc, basicfont.Face7x13, 'H', mat32.Vec2{}, mat32.Identity2D(), nil) {
+		t.Errorf("outline for a face without a font file")
+	}
+}
+
+func TestRenderStroke(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+	FontLibrary.InitFontPaths("/usr/share/fonts/truetype")
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	render := func(stroke bool) *image.RGBA {
+		rs, pc, img := newTestState(image.Point{120, 60})
+		tsty := &gist.Text{}
+		tsty.Defaults()
+		fsty := &gist.Font{}
+		fsty.Defaults()
+		fsty.Size.SetDot(40)
+		fsty.ToDots(&pc.UnContext)
+		fsty.Color = gist.Black
+		if stroke {
+			fsty.SetStyleProps(nil, ki.Props{"text-stroke": "3px red"}, nil)
+		}
+		OpenFont(fsty, &pc.UnContext)
+		tr := &Text{}
+		tr.SetString("HI", fsty, &pc.UnContext, tsty, true, 0, 0)
+		if hs := bitflag.Has32(int32(tr.Spans[0].HasDeco), int(gist.DecoStroke)); hs != stroke {
+			t.Errorf("DecoStroke flag: got %v, want %v", hs, stroke)
+		}
+		tr.Render(rs, mat32.Vec2{10, 50})
+		return img
+	}
+	if n := countColor(render(false), red); n != 0 {
+		t.Errorf("red pixels without text-stroke: got %d, want 0", n)
+	}
+	if n := countColor(render(true), red); n < 100 {
+		t.Errorf("red pixels with text-stroke: got %d, want outlines of H and I", n)
+	}
+}
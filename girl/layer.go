@@ -0,0 +1,137 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"log"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/mat32"
+)
+
+// Layer is an offscreen image that rendering is redirected into between
+// State.PushLayer and PopLayer, so that everything rendered in it can be
+// composited onto the image below as a whole, with a group opacity and
+// blend mode.
+type Layer struct {
+	Image   *image.RGBA     `desc:"image that rendering goes into"`
+	Bounds  image.Rectangle `desc:"region of the image that is composited"`
+	Under   *image.RGBA     `desc:"image that was being rendered into when the layer was pushed, which the layer is composited onto"`
+	Filters Filters         `desc:"filter effects applied to the layer before it is composited"`
+	Cache   *FilterCache    `desc:"if non-nil, caches the filtered layer, so the filters are only recomputed when the layer content changes"`
+}
+
+// PushLayer redirects all further rendering into a new transparent layer,
+// until PopLayer is called -- only the given bounds are composited.
+// Must protect within render mutex lock.
+func (rs *State) PushLayer(bounds image.Rectangle) {
+	ly := &Layer{Image: image.NewRGBA(rs.Image.Bounds()), Bounds: bounds, Under: rs.Image}
+	rs.LayerStack = append(rs.LayerStack, ly)
+	rs.setImage(ly.Image)
+}
+
+// PushFilterLayer redirects all further rendering into a new transparent
+// layer, as in PushLayer, which has given filters applied to it when it
+// is popped.  The bounds are extended by the Margin of the filters, e.g.,
+// for the extent of blurs and shadows.  If cache is non-nil, the filtered
+// result is cached and reused while the content is unchanged.
+// Must protect within render mutex lock.
+func (rs *State) PushFilterLayer(bounds image.Rectangle, fs Filters, cache *FilterCache) {
+	bounds = bounds.Inset(-fs.Margin()).Intersect(rs.Image.Bounds())
+	rs.PushLayer(bounds)
+	ly := rs.LayerStack[len(rs.LayerStack)-1]
+	ly.Filters = fs
+	ly.Cache = cache
+}
+
+// PopLayer composites the current layer onto the image below it, after
+// applying any filters, with given opacity and blend mode, and restores
+// rendering into that image.  Must protect within render mutex lock.
+func (rs *State) PopLayer(opacity float32, mode gist.BlendModes) {
+	sz := len(rs.LayerStack)
+	if sz == 0 {
+		log.Printf("gi.State PopLayer: stack is empty -- programmer error\n")
+		return
+	}
+	ly := rs.LayerStack[sz-1]
+	rs.LayerStack[sz-1] = nil
+	rs.LayerStack = rs.LayerStack[:sz-1]
+	rs.setImage(ly.Under)
+	if len(ly.Filters) > 0 {
+		if ly.Cache != nil {
+			ly.Cache.Filter(ly.Image, ly.Bounds, ly.Filters)
+		} else {
+			ly.Filters.Apply(ly.Image, ly.Bounds)
+		}
+	}
+	BlendImage(ly.Under, ly.Image, ly.Bounds, opacity, mode)
+}
+
+// setImage sets the image that is rendered into
+func (rs *State) setImage(img *image.RGBA) {
+	rs.Image = img
+	if rs.ImgSpanner != nil {
+		rs.ImgSpanner.SetImage(img)
+	}
+}
+
+// BlendImage composites the src image onto the dst image, within given
+// region, with given opacity and blend mode, using premultiplied alpha
+// compositing with the separable blend modes of the W3C Compositing and
+// Blending spec.
+func BlendImage(dst, src *image.RGBA, r image.Rectangle, opacity float32, mode gist.BlendModes) {
+	r = r.Intersect(dst.Bounds()).Intersect(src.Bounds())
+	if r.Empty() || opacity <= 0 {
+		return
+	}
+	opacity = mat32.Min(opacity, 1)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		si := src.PixOffset(r.Min.X, y)
+		di := dst.PixOffset(r.Min.X, y)
+		for x := r.Min.X; x < r.Max.X; x, si, di = x+1, si+4, di+4 {
+			sp := src.Pix[si : si+4 : si+4]
+			if sp[3] == 0 {
+				continue
+			}
+			dp := dst.Pix[di : di+4 : di+4]
+			sa := float32(sp[3]) / 255 * opacity
+			da := float32(dp[3]) / 255
+			for c := 0; c < 3; c++ {
+				cs := float32(sp[c]) / 255 * opacity // premultiplied
+				cb := float32(dp[c]) / 255
+				co := cs + cb*(1-sa)
+				if mode != gist.BlendNormal && da > 0 {
+					bl := BlendColor(mode, cb/da, cs/sa)
+					co = cs*(1-da) + cb*(1-sa) + sa*da*bl
+				}
+				dp[c] = uint8(mat32.Clamp(co, 0, 1)*255 + 0.5)
+			}
+			dp[3] = uint8(mat32.Clamp(sa+da*(1-sa), 0, 1)*255 + 0.5)
+		}
+	}
+}
+
+// BlendColor returns the result of blending given backdrop (cb) and source
+// (cs) color component values (non-premultiplied, 0-1) with given blend mode
+func BlendColor(mode gist.BlendModes, cb, cs float32) float32 {
+	switch mode {
+	case gist.BlendMultiply:
+		return cb * cs
+	case gist.BlendScreen:
+		return cb + cs - cb*cs
+	case gist.BlendOverlay: // hard light with source and backdrop swapped
+		if cb <= 0.5 {
+			return 2 * cb * cs
+		}
+		cb = 2*cb - 1
+		return cb + cs - cb*cs
+	case gist.BlendDarken:
+		return mat32.Min(cb, cs)
+	case gist.BlendLighten:
+		return mat32.Max(cb, cs)
+	}
+	return cs
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+// LayoutCacheSize is the maximum number of laid-out texts kept in the
+// layout cache, which is used by SetHTMLLayout so that the same text, in
+// the same styles and size, is only formatted and laid out once -- e.g.,
+// for the labels of a scrolling list, which are configured again and
+// again with the same content.  The least recently used texts are dropped
+// when it is full.  Set to 0 to disable the cache.
+var LayoutCacheSize = 1024
+
+// layoutEntry is a cached layout: a copy of the laid-out text, and the size
+// returned by LayoutStd
+type layoutEntry struct {
+	key  string
+	text Text
+	size mat32.Vec2
+}
+
+// layoutCache is the cache of laid-out texts, shared by all the windows
+var layoutCache struct {
+	sync.Mutex
+	lru   *list.List
+	texts map[string]*list.Element
+}
+
+// ClearLayoutCache clears the layout cache, e.g., after changing the fonts,
+// to release the texts that it holds
+func ClearLayoutCache() {
+	lc := &layoutCache
+	lc.Lock()
+	lc.lru = nil
+	lc.texts = nil
+	lc.Unlock()
+}
+
+// layoutKey returns the key of the layout of given text, in given styles
+// and size: it includes all the fields of the styles, and the font face,
+// which changes when the fonts are changed
+func layoutKey(str string, font *gist.Font, txtSty *gist.Text, ctxt *units.Context, cssAgg ki.Props, size mat32.Vec2) string {
+	var sb strings.Builder
+	sb.WriteString(str)
+	fmt.Fprintf(&sb, "\x00%v\x00%v\x00%v\x00%v\x00%v", *font, *txtSty, *ctxt, cssAgg, size)
+	return sb.String()
+}
+
+// SetHTMLLayout sets the text from given HTML-formatted string (SetHTML),
+// and lays it out within given size (LayoutStd), returning the size of
+// the text -- if the same text was laid out in the same styles and size
+// before, the layout is copied from the layout cache instead (see
+// LayoutCacheSize), which is much faster.
+func (tr *Text) SetHTMLLayout(str string, font *gist.Font, txtSty *gist.Text, ctxt *units.Context, cssAgg ki.Props, size mat32.Vec2) mat32.Vec2 {
+	if LayoutCacheSize <= 0 {
+		tr.SetHTML(str, font, txtSty, ctxt, cssAgg)
+		return tr.LayoutStd(txtSty, font, ctxt, size)
+	}
+	OpenFont(font, ctxt) // sets the face, which is part of the key
+	key := layoutKey(str, font, txtSty, ctxt, cssAgg, size)
+	lc := &layoutCache
+	lc.Lock()
+	if el, has := lc.texts[key]; has {
+		lc.lru.MoveToFront(el)
+		le := el.Value.(*layoutEntry)
+		tr.CopyFrom(&le.text)
+		lc.Unlock()
+		return le.size
+	}
+	lc.Unlock()
+
+	tr.SetHTML(str, font, txtSty, ctxt, cssAgg)
+	lsz := tr.LayoutStd(txtSty, font, ctxt, size)
+	le := &layoutEntry{key: key, size: lsz}
+	le.text.CopyFrom(tr)
+
+	lc.Lock()
+	if lc.texts == nil {
+		lc.lru = list.New()
+		lc.texts = make(map[string]*list.Element)
+	}
+	if _, has := lc.texts[key]; !has { // could have been added in the meantime
+		lc.texts[key] = lc.lru.PushFront(le)
+		for lc.lru.Len() > LayoutCacheSize {
+			el := lc.lru.Back()
+			delete(lc.texts, el.Value.(*layoutEntry).key)
+			lc.lru.Remove(el)
+		}
+	}
+	lc.Unlock()
+	return lsz
+}
+
+// CopyFrom sets the text to a copy of given text, with its own spans and
+// links, so that either can be laid out again without affecting the other
+// -- the faces, colors and other properties of the runes are shared
+func (tr *Text) CopyFrom(fr *Text) {
+	tr.Size = fr.Size
+	tr.Dir = fr.Dir
+	tr.Spans = make([]Span, len(fr.Spans))
+	for i := range fr.Spans {
+		sr := &tr.Spans[i]
+		*sr = fr.Spans[i]
+		sr.Text = append([]rune(nil), sr.Text...)
+		sr.Render = append([]Rune(nil), sr.Render...)
+		if sr.BidiLevels != nil {
+			sr.BidiLevels = append([]uint8(nil), sr.BidiLevels...)
+		}
+	}
+	tr.Links = append([]TextLink(nil), fr.Links...)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/mat32"
+)
+
+func TestSetHTMLLayout(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+	ClearLayoutCache()
+	defer ClearLayoutCache()
+
+	pc := &Paint{}
+	pc.Defaults()
+	pc.SetUnitContextExt(image.Point{320, 240})
+	tsty := &gist.Text{}
+	tsty.Defaults()
+	fsty := &gist.Font{}
+	fsty.Defaults()
+	str := "This is <b>HTML</b> formatted <i>text</i> that wraps"
+	sz := mat32.Vec2{100, 40}
+
+	want := &Text{}
+	want.SetHTML(str, fsty, tsty, &pc.UnContext, nil)
+	wsz := want.LayoutStdLR(tsty, fsty, &pc.UnContext, sz)
+
+	tr := &Text{}
+	if tsz := tr.SetHTMLLayout(str, fsty, tsty, &pc.UnContext, nil, sz); tsz != wsz {
+		t.Errorf("layout size: got %v, want %v", tsz, wsz)
+	}
+	if !reflect.DeepEqual(tr.Spans, want.Spans) {
+		t.Errorf("laid-out spans differ from SetHTML and LayoutStdLR")
+	}
+	tr.Spans[0].Render[0].RelPos.X = 1000 // must not change the cached copy
+
+	tr2 := &Text{}
+	if tsz := tr2.SetHTMLLayout(str, fsty, tsty, &pc.UnContext, nil, sz); tsz != wsz {
+		t.Errorf("cached layout size: got %v, want %v", tsz, wsz)
+	}
+	if !reflect.DeepEqual(tr2.Spans, want.Spans) {
+		t.Errorf("cached spans differ from SetHTML and LayoutStdLR")
+	}
+	if n := len(layoutCache.texts); n != 1 {
+		t.Errorf("layout cache size: got %d, want 1", n)
+	}
+
+	tr2.SetHTMLLayout(str, fsty, tsty, &pc.UnContext, nil, mat32.Vec2{200, 40})
+	tsty.Align = gist.AlignCenter
+	tr2.SetHTMLLayout(str, fsty, tsty, &pc.UnContext, nil, sz)
+	if n := len(layoutCache.texts); n != 3 {
+		t.Errorf("layout cache size after other width and style: got %d, want 3", n)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/goki/gi/gist"
+)
+
+// Line breaking: lines are wrapped at the end of white space, and, per the
+// word-break style (gist.WordBreaks), between the characters of Chinese,
+// Japanese and Korean text, or between any characters -- this follows the
+// main rules of the Unicode line breaking algorithm (UAX #14), except that
+// there is no dictionary for breaking words in scripts that do not use
+// spaces between words, e.g., Thai: they are broken anywhere if they do
+// not fit otherwise.  The no-break spaces (see IsNoBreakSpace) and the
+// word joiner (U+2060) keep the text around them together, e.g., in dates,
+// units and names, and the zero width space (U+200B, as from a <wbr> tag)
+// allows a break within a word.
+
+// LineBreakOK returns true if a line of given text can be wrapped before
+// the rune at given index, per given word break style
+func LineBreakOK(txt []rune, idx int, wb gist.WordBreaks) bool {
+	if idx <= 0 || idx >= len(txt) {
+		return false
+	}
+	pr, r := txt[idx-1], txt[idx]
+	if IsBreakSpace(r) {
+		return false // break at END of whitespace
+	}
+	if IsBreakSpace(pr) || pr == '\u200b' { // zero width space
+		return true
+	}
+	if NoBreakBefore(r) || NoBreakAfter(pr) {
+		return false
+	}
+	switch wb {
+	case gist.WordBreakBreakAll:
+		return true
+	case gist.WordBreakKeepAll:
+		return false
+	}
+	return IsCJKBreak(pr) || IsCJKBreak(r)
+}
+
+// IsBreakSpace returns true if given rune is white space that a line can
+// be wrapped after: all white space except the no-break spaces
+func IsBreakSpace(r rune) bool {
+	return unicode.IsSpace(r) && !IsNoBreakSpace(r)
+}
+
+// IsNoBreakSpace returns true if given rune is a space that a line should
+// never be wrapped before or after: no-break space (U+00A0, &nbsp;), figure
+// space (U+2007) or narrow no-break space (U+202F)
+func IsNoBreakSpace(r rune) bool {
+	return r == '\u00a0' || r == '\u2007' || r == '\u202f'
+}
+
+// IsCJKBreak returns true if lines can be wrapped before and after given
+// rune, as for the ideographs, kana and hangul of Chinese, Japanese and
+// Korean text, and their punctuation and full-width forms
+func IsCJKBreak(r rune) bool {
+	if r < 0x1100 { // fast path: nothing before Hangul Jamo
+		return false
+	}
+	if (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef) {
+		return true
+	}
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Bopomofo)
+}
+
+// IsComplexBreak returns true if given rune is in a script that does not
+// use spaces between words, and where finding the words requires a
+// dictionary, e.g., Thai (the SA class of UAX #14)
+func IsComplexBreak(r rune) bool {
+	if r < 0x0e00 {
+		return false
+	}
+	return unicode.In(r, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar, unicode.Tai_Tham, unicode.Tai_Viet, unicode.New_Tai_Lue)
+}
+
+// NoBreakBefore returns true if a line should never be wrapped before given
+// rune: no-break spaces and joiners, closing punctuation, combining marks,
+// and the small kana and iteration marks of Japanese
+func NoBreakBefore(r rune) bool {
+	if IsNoBreakJoin(r) || unicode.In(r, unicode.Pe, unicode.Pf, unicode.Mn, unicode.Me, unicode.Mc) {
+		return true
+	}
+	return strings.ContainsRune("!%),.:;?]}¢°·’”‰℃、。〃々〆ゝゞ・ーヽヾ！％），．：；？］｝｡｣､･ｰぁぃぅぇぉっゃゅょゎゕゖァィゥェォッャュョヮヵヶㇰㇱㇲㇳㇴㇵㇶㇷㇸㇹㇺㇻㇼㇽㇾㇿ…‥", r)
+}
+
+// NoBreakAfter returns true if a line should never be wrapped after given
+// rune: no-break spaces and joiners, and opening punctuation
+func NoBreakAfter(r rune) bool {
+	if IsNoBreakJoin(r) || unicode.In(r, unicode.Ps, unicode.Pi) {
+		return true
+	}
+	return strings.ContainsRune("$(£¥[{‘“＄（［｛￡￥｢", r)
+}
+
+// IsNoBreakJoin returns true if given rune joins the runes before and after
+// it on the same line: the no-break spaces (see IsNoBreakSpace), word joiner
+// (U+2060), and zero width no-break space (U+FEFF)
+func IsNoBreakJoin(r rune) bool {
+	return IsNoBreakSpace(r) || r == '\u2060' || r == '\ufeff'
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/mat32"
+	"golang.org/x/image/font/basicfont"
+)
+
+// monoSpan returns a span with given text, with each rune 1 unit wide
+func monoSpan(txt string) *Span {
+	sr := &Span{Text: []rune(txt)}
+	sr.Render = make([]Rune, len(sr.Text))
+	for i := range sr.Render {
+		sr.Render[i].RelPos.X = float32(i)
+		sr.Render[i].Size.X = 1
+	}
+	sr.Render[0].Face = basicfont.Face7x13
+	sr.Render[0].Color = color.Black
+	sr.LastPos.X = float32(len(sr.Text))
+	return sr
+}
+
+func TestFindWrapPosLR(t *testing.T) {
+	tests := []struct {
+		txt  string
+		size float32
+		wb   gist.WordBreaks
+		pos  int
+	}{
+		{"hello world again", 13, gist.WordBreakNormal, 12},
+		{"hello world again", 8, gist.WordBreakNormal, 6},
+		{"helloworld again", 8, gist.WordBreakNormal, 11},
+		{"helloworld again", 8, gist.WordBreakBreakAll, 8},
+		{"这是一个很长的中文句子", 5, gist.WordBreakNormal, 5},
+		{"这是一个很长的中文句子", 5, gist.WordBreakKeepAll, -1},
+		{"日本語の文章です。", 8, gist.WordBreakNormal, 7}, // not before 。
+		{"「日本語」の文章", 1, gist.WordBreakNormal, 2},  // not after 「
+		{"ภาษาไทยยาวมาก", 6, gist.WordBreakNormal, 6},
+		{"on 12\u00a0March 2024", 10, gist.WordBreakNormal, 3},  // not at no-break space
+		{"100\u00a0km away", 4, gist.WordBreakNormal, 7},        // nor after it
+		{"x a\u2060b\u2060c", 5, gist.WordBreakNormal, 2},       // word joiner
+		{"long\u200bword", 6, gist.WordBreakNormal, 5},          // zero width space
+		{"Dr.\u202fJane\u00a0Doe", 8, gist.WordBreakNormal, -1}, // names stay together
+	}
+	for _, tt := range tests {
+		sr := monoSpan(tt.txt)
+		if pos := sr.FindWrapPosLR(tt.size, float32(len(sr.Text)), tt.wb); pos != tt.pos {
+			t.Errorf("wrap position of %q in %v (%v): got %d, want %d", tt.txt, tt.size, tt.wb, pos, tt.pos)
+		}
+	}
+}
+
+func TestLineBreakOK(t *testing.T) {
+	txt := []rune("ab 漢字、かな")
+	want := []bool{false, false, false, true, true, false, true, true}
+	for i, w := range want {
+		if ok := LineBreakOK(txt, i, gist.WordBreakNormal); ok != w {
+			t.Errorf("line break before %d (%q): got %v, want %v", i, string(txt[i]), ok, w)
+		}
+	}
+
+	txt = []rune("a\u00a0b\u2060c\u200bd")
+	want = []bool{false, false, false, false, false, true, true}
+	for i, w := range want {
+		if ok := LineBreakOK(txt, i, gist.WordBreakBreakAll); ok != w {
+			t.Errorf("break-all line break before %d (%U): got %v, want %v", i, txt[i], ok, w)
+		}
+	}
+}
+
+func TestHTMLNoBreak(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+
+	pc := &Paint{}
+	pc.Defaults()
+	pc.SetUnitContextExt(image.Point{320, 240})
+	fsty := &gist.Font{}
+	fsty.Defaults()
+	tsty := &gist.Text{}
+	tsty.Defaults()
+
+	tr := &Text{}
+	tr.SetHTML("12&nbsp;March  \u00a0<b>long</b><wbr>word", fsty, tsty, &pc.UnContext, nil)
+	if got, want := string(tr.Spans[0].Text), "12\u00a0March \u00a0long\u200bword"; got != want {
+		t.Errorf("SetHTML: got %q, want %q", got, want)
+	}
+	if tr.Spans[0].Render[len("12 March  long")].Face != tr.Spans[0].Render[0].Face {
+		t.Errorf("SetHTML: bold continues after <wbr>")
+	}
+	tsty.WhiteSpace = gist.WhiteSpacePre
+	tr.SetHTML("a\u00a0 b<wbr>c", fsty, tsty, &pc.UnContext, nil)
+	if got, want := string(tr.Spans[0].Text), "a\u00a0 b\u200bc"; got != want {
+		t.Errorf("SetHTMLPre: got %q, want %q", got, want)
+	}
+
+	tsty.WhiteSpace = gist.WhiteSpaceNormal
+	tr.SetHTML("wrap at&nbsp;no&nbsp;break&nbsp;spaces", fsty, tsty, &pc.UnContext, nil)
+	tr.LayoutStdLR(tsty, fsty, &pc.UnContext, mat32.Vec2{60, 200})
+	if len(tr.Spans) != 2 || string(tr.Spans[1].Text) != "at\u00a0no\u00a0break\u00a0spaces" {
+		var lines []string
+		for _, sr := range tr.Spans {
+			lines = append(lines, string(sr.Text))
+		}
+		t.Errorf("wrapped lines: got %q, want the no-break words on one line", lines)
+	}
+}
+
+func TestJustifyLR(t *testing.T) {
+	sr := monoSpan("ab cd ef ")
+	if !sr.JustifyLR(12) {
+		t.Fatalf("span not justified")
+	}
+	want := []float32{0, 1, 2, 5, 6, 7, 10, 11, 12} // 2 spaces, 4 extra
+	for i, w := range want {
+		if x := sr.Render[i].RelPos.X; x != w {
+			t.Errorf("word-justified position of %d (%q): got %v, want %v", i, string(sr.Text[i]), x, w)
+		}
+	}
+	if end := sr.Render[7].RelPosAfterLR(); end != 12 {
+		t.Errorf("word-justified end of last letter: got %v, want 12", end)
+	}
+
+	sr = monoSpan("漢字かな")
+	if !sr.JustifyLR(7) {
+		t.Fatalf("span without spaces not justified")
+	}
+	want = []float32{0, 2, 4, 6}
+	for i, w := range want {
+		if x := sr.Render[i].RelPos.X; x != w {
+			t.Errorf("letter-justified position of %d: got %v, want %v", i, x, w)
+		}
+	}
+
+	if monoSpan("abc def").JustifyLR(5) {
+		t.Errorf("span wider than width justified")
+	}
+	if monoSpan("a ").JustifyLR(5) {
+		t.Errorf("single letter justified")
+	}
+}
+
+func TestSetRunePosVert(t *testing.T) {
+	sr := monoSpan("ab")
+	ends := sr.SetRunePosVert(0, 0, 7, 4, true)
+	if want := []float32{7, 14}; !reflect.DeepEqual(ends, want) {
+		t.Errorf("sideways ends: got %v, want %v", ends, want)
+	}
+	if rr := sr.Render[1]; rr.RotRad != mat32.Pi/2 || rr.RelPos.Y != 7 {
+		t.Errorf("sideways rune: got rotation %v at %v, want %v at 7", rr.RotRad, rr.RelPos.Y, mat32.Pi/2)
+	}
+
+	ends = sr.SetRunePosVert(0, 0, 7, 4, false) // upright: advance is ascent + descent
+	if want := []float32{13, 26}; !reflect.DeepEqual(ends, want) {
+		t.Errorf("upright ends: got %v, want %v", ends, want)
+	}
+	if rr := sr.Render[1]; rr.RotRad != 0 || rr.RelPos != (mat32.Vec2{-3.5, 24}) {
+		t.Errorf("upright rune: got rotation %v at %v, want 0 at {-3.5 24}", rr.RotRad, rr.RelPos)
+	}
+	if sr.LastPos.Y != 26 {
+		t.Errorf("upright column length: got %v, want 26", sr.LastPos.Y)
+	}
+}
+
+func TestFindWrapPosTB(t *testing.T) {
+	tests := []struct {
+		txt  string
+		size float32
+		pos  int
+	}{
+		{"日本語の文章です。", 4, 4},
+		{"日本語の文章です。", 8, 7}, // not before 。
+		{"日本語の文章です。", 20, -1},
+		{"ab cd ef", 4, 3},
+	}
+	for _, tt := range tests {
+		sr := monoSpan(tt.txt)
+		ends := make([]float32, len(sr.Text))
+		for i := range ends {
+			ends[i] = float32(i + 1)
+		}
+		if pos := sr.FindWrapPosTB(ends, tt.size, gist.WordBreakNormal); pos != tt.pos {
+			t.Errorf("vertical wrap position of %q in %v: got %d, want %d", tt.txt, tt.size, pos, tt.pos)
+		}
+	}
+}
+
+func TestTabStops(t *testing.T) {
+	stops := func(pos []float32, als []gist.TabAligns) gist.TabStops {
+		tss := make(gist.TabStops, len(pos))
+		for i := range tss {
+			tss[i].Pos.Dots = pos[i]
+			tss[i].Align = als[i]
+		}
+		return tss
+	}
+	// each glyph of basicfont.Face7x13 is 7 wide
+	sr := monoSpan("a\tb\t12.5\tx")
+	sr.SetRunePosLR(0, 0, 7, 4, stops([]float32{30, 70, 100}, []gist.TabAligns{gist.TabLeft, gist.TabDecimal, gist.TabRight}))
+	want := map[int]float32{0: 0, 2: 30, 4: 56, 6: 70, 9: 93}
+	for i, w := range want {
+		if x := sr.Render[i].RelPos.X; x != w {
+			t.Errorf("position of %d (%q): got %v, want %v", i, string(sr.Text[i]), x, w)
+		}
+	}
+	if sr.LastPos.X != 100 {
+		t.Errorf("end of right-aligned text: got %v, want 100", sr.LastPos.X)
+	}
+
+	sr = monoSpan("\tab\tc")
+	sr.SetRunePosLR(0, 0, 7, 4, stops([]float32{50}, []gist.TabAligns{gist.TabCenter}))
+	if x := sr.Render[1].RelPos.X; x != 43 {
+		t.Errorf("centered text: got %v, want 43", x)
+	}
+	if x := sr.Render[4].RelPos.X; x != 84 { // past the last stop: next multiple of 4 chars
+		t.Errorf("tab past the last stop: got %v, want 84", x)
+	}
+
+	sr = monoSpan("abcdefgh\tx")
+	sr.SetRunePosLR(0, 0, 7, 4, stops([]float32{60}, []gist.TabAligns{gist.TabRight}))
+	if x := sr.Render[9].RelPos.X; x != 56 { // does not fit before the stop
+		t.Errorf("right-aligned text that does not fit: got %v, want 56", x)
+	}
+}
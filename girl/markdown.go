@@ -0,0 +1,212 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+)
+
+// SetMarkdown sets the text from given Markdown-formatted string, which is
+// converted to HTML by MarkdownToHTML and then styled as by SetHTML, with
+// each paragraph, header, list and code block in its own span(s), and
+// white space collapsed as in normal (non-pre) HTML -- set white-space to
+// normal for LayoutStd to wrap the paragraphs.
+func (tr *Text) SetMarkdown(md string, font *gist.Font, txtSty *gist.Text, ctxt *units.Context, cssAgg ki.Props) {
+	tr.SetHTMLNoPre([]byte(MarkdownToHTML(md)), font, txtSty, ctxt, cssAgg)
+}
+
+// mdHeaderTags are the size tags (see SetHTMLSimpleTag) used for Markdown
+// headers of each level, which are also bold
+var mdHeaderTags = []string{"", "xx-large", "x-large", "large", "", "", ""}
+
+var (
+	mdHeaderRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	mdItemRe   = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+)
+
+// MarkdownToHTML converts a useful subset of Markdown to the HTML that
+// SetHTML styles: paragraphs (separated by blank lines, with a line
+// ending in two spaces as a line break), # headers, - * + and numbered
+// list items, ``` fenced code blocks, and inline **bold**, *italic*,
+// ~~strikethrough~~, `code` and [links](url), with \ escapes.  Other text,
+// including any HTML in it, is escaped, so it displays as-is.
+func MarkdownToHTML(md string) string {
+	var sb strings.Builder
+	var para []string
+	inList := false
+	inCode := false
+	ncode := 0 // lines in the code block so far
+	flush := func() {
+		if len(para) > 0 {
+			sb.WriteString("<p>")
+			for i, ln := range para {
+				if i > 0 {
+					if strings.HasSuffix(para[i-1], "  ") {
+						sb.WriteString("<br>")
+					} else {
+						sb.WriteString(" ")
+					}
+				}
+				sb.WriteString(mdInline(strings.TrimSpace(ln)))
+			}
+			sb.WriteString("</p>")
+			para = para[:0]
+		}
+		if inList {
+			sb.WriteString("</p>")
+			inList = false
+		}
+	}
+	for _, ln := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		tln := strings.TrimSpace(ln)
+		if strings.HasPrefix(tln, "```") {
+			if inCode {
+				sb.WriteString("</p>")
+			} else {
+				flush()
+				sb.WriteString("<p>")
+				ncode = 0
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode { // keep the spaces, as non-breaking spaces
+			if ncode > 0 {
+				sb.WriteString("<br>")
+			}
+			ncode++
+			cln := strings.ReplaceAll(html.EscapeString(ln), "\t", "    ")
+			sb.WriteString("<code>" + strings.ReplaceAll(cln, " ", "&#160;") + "</code>")
+			continue
+		}
+		if tln == "" {
+			flush()
+			continue
+		}
+		if m := mdHeaderRe.FindStringSubmatch(tln); m != nil {
+			flush()
+			lev := len(m[1])
+			txt := "<b>" + mdInline(m[2]) + "</b>"
+			if tag := mdHeaderTags[lev]; tag != "" {
+				txt = "<" + tag + ">" + txt + "</" + tag + ">"
+			}
+			sb.WriteString("<p>" + txt + "</p>")
+			continue
+		}
+		if m := mdItemRe.FindStringSubmatch(ln); m != nil {
+			if len(para) > 0 || !inList {
+				flush()
+				sb.WriteString("<p>")
+				inList = true
+			} else {
+				sb.WriteString("<br>")
+			}
+			lev := len(strings.ReplaceAll(m[1], "\t", "  ")) / 2
+			sb.WriteString(strings.Repeat("&#160;", 4*lev))
+			mark := m[2]
+			switch {
+			case len(mark) > 1 || unicode.IsDigit(rune(mark[0])):
+			case lev == 0:
+				mark = "•"
+			default:
+				mark = "◦"
+			}
+			sb.WriteString(mark + " " + mdInline(m[3]))
+			continue
+		}
+		if inList && ln != tln { // indented continuation of a list item
+			sb.WriteString(" " + mdInline(tln))
+			continue
+		}
+		if inList {
+			flush()
+		}
+		para = append(para, ln)
+	}
+	if inCode {
+		sb.WriteString("</p>")
+	}
+	flush()
+	return sb.String()
+}
+
+// mdPunct are the characters that can be escaped with a \ in Markdown
+const mdPunct = "\\`*_{}[]()#+-.!~<>|"
+
+// mdInline converts the inline Markdown formatting of given text to HTML,
+// escaping the rest of the text -- see MarkdownToHTML
+func mdInline(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		rest := s[i:]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(mdPunct, s[i+1]) >= 0:
+			sb.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if ed := strings.IndexByte(s[i+1:], '`'); ed > 0 {
+				sb.WriteString("<code>" + html.EscapeString(s[i+1:i+1+ed]) + "</code>")
+				i += ed + 2
+				continue
+			}
+		case c == '[':
+			if md := strings.Index(rest, "]("); md > 0 {
+				if ed := strings.IndexByte(rest[md+2:], ')'); ed >= 0 {
+					url := strings.TrimSpace(rest[md+2 : md+2+ed])
+					sb.WriteString(`<a href="` + html.EscapeString(url) + `">` + mdInline(rest[1:md]) + "</a>")
+					i += md + 3 + ed
+					continue
+				}
+			}
+		case c == '*' || c == '~' || (c == '_' && !mdIsWordEnd(s[:i])): // not within snake_case words
+			if ht, n := mdEmphasize(rest); n > 0 {
+				sb.WriteString(ht)
+				i += n
+				continue
+			}
+		}
+		sb.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return sb.String()
+}
+
+// mdEmphasize returns the HTML for the emphasized text at the start of given
+// text, and the length of its Markdown, or 0 if it does not start with
+// emphasized text
+func mdEmphasize(s string) (string, int) {
+	for _, em := range mdEmphasis {
+		if !strings.HasPrefix(s, em.delim) {
+			continue
+		}
+		n := len(em.delim)
+		if ed := strings.Index(s[n:], em.delim); ed > 0 && s[n] != ' ' && s[n+ed-1] != ' ' {
+			return "<" + em.tag + ">" + mdInline(s[n:n+ed]) + "</" + em.tag + ">", 2*n + ed
+		}
+	}
+	return "", 0
+}
+
+// mdEmphasis are the inline emphasis delimiters, longest first, and the
+// tags that they are converted to
+var mdEmphasis = []struct{ delim, tag string }{
+	{"**", "b"}, {"__", "b"}, {"~~", "s"}, {"*", "i"}, {"_", "i"},
+}
+
+// mdIsWordEnd returns true if given text ends in a letter or digit
+func mdIsWordEnd(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"testing"
+
+	"github.com/goki/gi/gist"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct{ md, html string }{
+		{"Some **bold**, *italic* and `a<b>` text", "<p>Some <b>bold</b>, <i>italic</i> and <code>a&lt;b&gt;</code> text</p>"},
+		{"snake_case_name and _em_ and ~~gone~~", "<p>snake_case_name and <i>em</i> and <s>gone</s></p>"},
+		{"a [link](https://goki.dev) \\*not em\\*", `<p>a <a href="https://goki.dev">link</a> *not em*</p>`},
+		{"line one\nline two  \nline three\n\nnext", "<p>line one line two<br>line three</p><p>next</p>"},
+		{"# Title #\n### Sub\n##### Small", "<p><xx-large><b>Title</b></xx-large></p><p><large><b>Sub</b></large></p><p><b>Small</b></p>"},
+		{"- one\n  more\n  * two\n3. three", "<p>• one more<br>&#160;&#160;&#160;&#160;◦ two<br>3. three</p>"},
+		{"text\n```\nif a {\n  b()\n}\n```", "<p>text</p><p><code>if&#160;a&#160;{</code><br><code>&#160;&#160;b()</code><br><code>}</code></p>"},
+		{"1 * 2 * 3", "<p>1 * 2 * 3</p>"},
+	}
+	for _, ts := range tests {
+		if got := MarkdownToHTML(ts.md); got != ts.html {
+			t.Errorf("MarkdownToHTML(%q):\n got %s\nwant %s", ts.md, got, ts.html)
+		}
+	}
+}
+
+func TestSetMarkdown(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+
+	pc := &Paint{}
+	pc.Defaults()
+	pc.SetUnitContextExt(image.Point{320, 240})
+	fsty := &gist.Font{}
+	fsty.Defaults()
+	tsty := &gist.Text{}
+	tsty.Defaults()
+	tsty.WhiteSpace = gist.WhiteSpacePre // markdown is never pre
+
+	tr := &Text{}
+	tr.SetMarkdown("# Head\n\nSee  the [docs](https://goki.dev).\n\n- a\n- b", fsty, tsty, &pc.UnContext, nil)
+	var lines []string
+	for _, sr := range tr.Spans {
+		if len(sr.Text) > 0 {
+			lines = append(lines, string(sr.Text))
+		}
+	}
+	want := []string{"Head", "See the docs.", "• a", "• b"}
+	if len(lines) != len(want) {
+		t.Fatalf("lines: got %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d: got %q, want %q", i, lines[i], want[i])
+		}
+	}
+	if len(tr.Links) != 1 || tr.Links[0].URL != "https://goki.dev" || tr.Links[0].Label != "docs" {
+		t.Errorf("links: got %+v, want one to https://goki.dev", tr.Links)
+	}
+}
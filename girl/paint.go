@@ -275,6 +275,10 @@ func (pc *Paint) stroke(rs *State) {
 	rs.LastRenderBBox = image.Rectangle{Min: image.Point{fbox.Min.X.Floor(), fbox.Min.Y.Floor()},
 		Max: image.Point{fbox.Max.X.Ceil(), fbox.Max.Y.Ceil()}}
 	rs.Raster.SetColor(pc.StrokeStyle.Color.RenderColor(pc.FontStyle.Opacity*pc.StrokeStyle.Opacity, rs.LastRenderBBox, rs.XForm))
+	if pc.Blend != gist.BlendNormal {
+		rs.PushLayer(rs.LastRenderBBox)
+		defer rs.PopLayer(1, pc.Blend)
+	}
 	rs.Raster.Draw()
 	rs.Raster.Clear()
 
@@ -308,6 +312,10 @@ func (pc *Paint) fill(rs *State) {
 	} else {
 		rf.SetColor(pc.FillStyle.Color.RenderColor(pc.FontStyle.Opacity*pc.FillStyle.Opacity, rs.LastRenderBBox, rs.XForm))
 	}
+	if pc.Blend != gist.BlendNormal {
+		rs.PushLayer(rs.LastRenderBBox)
+		defer rs.PopLayer(1, pc.Blend)
+	}
 	rf.Draw()
 	rf.Clear()
 
@@ -494,8 +502,10 @@ func (pc *Paint) DrawRoundedRectangle(rs *State, x, y, w, h, r float32) {
 
 // DrawEllipticalArc draws arc between angle1 and angle2 along an ellipse,
 // using quadratic bezier curves -- centers of ellipse are at cx, cy with
-// radii rx, ry -- see DrawEllipticalArcPath for a version compatible with SVG
-// A/a path drawing, which uses previous position instead of two angles
+// radii rx, ry -- see ArcTo for a version compatible with SVG
+// A/a path drawing, which uses previous position instead of two angles.
+// If there is a current point that differs from the start of the arc, a line
+// is added from it to the start, as in the HTML canvas arc method.
 func (pc *Paint) DrawEllipticalArc(rs *State, cx, cy, rx, ry, angle1, angle2 float32) {
 	const n = 16
 	for i := 0; i < n; i++ {
@@ -511,8 +521,12 @@ func (pc *Paint) DrawEllipticalArc(rs *State, cx, cy, rx, ry, angle1, angle2 flo
 		y2 := cy + ry*mat32.Sin(a2)
 		ncx := 2*x1 - x0/2 - x2/2
 		ncy := 2*y1 - y0/2 - y2/2
-		if i == 0 && !rs.HasCurrent {
-			pc.MoveTo(rs, x0, y0)
+		if i == 0 {
+			if !rs.HasCurrent {
+				pc.MoveTo(rs, x0, y0)
+			} else if pc.TransformPoint(rs, x0, y0).DistTo(rs.Current) > 0.01 {
+				pc.LineTo(rs, x0, y0)
+			}
 		}
 		pc.QuadraticTo(rs, ncx, ncy, x2, y2)
 	}
@@ -683,6 +697,145 @@ func (pc *Paint) DrawRegularPolygon(rs *State, n int, x, y, r, rotation float32)
 	pc.ClosePath(rs)
 }
 
+// ArcTo adds an elliptical arc to the current path from the current point
+// to x, y, with the semantics of the SVG A path command: radii rx, ry (which
+// are increased if needed to reach the end point), rotation of the x axis
+// of the ellipse in degrees, and the largeArc and sweep flags selecting
+// which of the four possible arcs is drawn.  A zero radius draws a line.
+// If there is no current point, it is equivalent to MoveTo(x, y).
+func (pc *Paint) ArcTo(rs *State, rx, ry, angle float32, largeArc, sweep bool, x, y float32) {
+	if !rs.HasCurrent {
+		pc.MoveTo(rs, x, y)
+		return
+	}
+	st := rs.XForm.Inverse().MulVec2AsPt(rs.Current)
+	pc.arcFromTo(rs, st, rx, ry, angle, largeArc, sweep, mat32.Vec2{x, y})
+}
+
+// arcFromTo adds an elliptical arc from st to ed, which must be the current
+// point, as in ArcTo
+func (pc *Paint) arcFromTo(rs *State, st mat32.Vec2, rx, ry, angle float32, largeArc, sweep bool, ed mat32.Vec2) {
+	if st == ed {
+		return
+	}
+	rx, ry = mat32.Abs(rx), mat32.Abs(ry)
+	if rx == 0 || ry == 0 {
+		pc.LineTo(rs, ed.X, ed.Y)
+		return
+	}
+	cx, cy := FindEllipseCenter(&rx, &ry, angle*math.Pi/180, st.X, st.Y, ed.X, ed.Y, sweep, largeArc)
+	pc.DrawEllipticalArcPath(rs, cx, cy, ed.X, ed.Y, st.X, st.Y, rx, ry, angle, largeArc, sweep)
+}
+
+// DrawRoundedPolygon draws a closed polygon through given points, with
+// each corner rounded by a circular arc of radius r, which is reduced
+// where needed so that the arcs of adjacent corners do not overlap.
+func (pc *Paint) DrawRoundedPolygon(rs *State, points []mat32.Vec2, r float32) {
+	sz := len(points)
+	if sz < 3 || r <= 0 {
+		pc.NewSubPath(rs)
+		pc.DrawPolygon(rs, points)
+		return
+	}
+	pc.NewSubPath(rs)
+	for i := 0; i < sz; i++ {
+		pv := points[(i+sz-1)%sz]
+		cp := points[i]
+		nx := points[(i+1)%sz]
+		e1 := cp.Sub(pv)
+		e2 := nx.Sub(cp)
+		l1, l2 := e1.Length(), e2.Length()
+		if l1 == 0 || l2 == 0 {
+			pc.LineTo(rs, cp.X, cp.Y)
+			continue
+		}
+		e1 = e1.DivScalar(l1)
+		e2 = e2.DivScalar(l2)
+		cross := e1.X*e2.Y - e1.Y*e2.X
+		turn := mat32.Abs(mat32.Atan2(cross, e1.Dot(e2))) // exterior angle
+		if turn < 1.0e-4 {                                // straight: no corner
+			pc.LineTo(rs, cp.X, cp.Y)
+			continue
+		}
+		tn := mat32.Tan(turn / 2)
+		d := mat32.Min(r*tn, 0.5*mat32.Min(l1, l2)) // distance of arc ends from corner
+		cr := d / tn
+		p1 := cp.Sub(e1.MulScalar(d))
+		p2 := cp.Add(e2.MulScalar(d))
+		pc.LineTo(rs, p1.X, p1.Y)
+		pc.arcFromTo(rs, p1, cr, cr, 0, false, cross > 0, p2)
+	}
+	pc.ClosePath(rs)
+}
+
+// SquircleExp is the default exponent of the superellipse corners drawn by
+// DrawSquircle: 2 is a circle, and larger values are progressively more
+// square, with a smoother transition from the straight edges
+var SquircleExp float32 = 5
+
+// superellipsePoint returns the point on the superellipse with given center,
+// radii and exponent n, at parametric angle t
+func superellipsePoint(cx, cy, rx, ry, n, t float32) mat32.Vec2 {
+	c, s := mat32.Cos(t), mat32.Sin(t)
+	ex := 2 / n
+	x := mat32.Pow(mat32.Abs(c), ex)
+	y := mat32.Pow(mat32.Abs(s), ex)
+	if c < 0 {
+		x = -x
+	}
+	if s < 0 {
+		y = -y
+	}
+	return mat32.Vec2{cx + rx*x, cy + ry*y}
+}
+
+// drawSuperellipseArc adds the part of the superellipse with given center,
+// radii and exponent n between parametric angles a1 and a2, as line
+// segments, to the current path
+func (pc *Paint) drawSuperellipseArc(rs *State, cx, cy, rx, ry, n, a1, a2 float32) {
+	const segs = 16 // per quarter
+	ns := int(mat32.Ceil(mat32.Abs(a2-a1)/(0.5*math.Pi)*segs)) + 1
+	for i := 0; i <= ns; i++ {
+		p := superellipsePoint(cx, cy, rx, ry, n, a1+(a2-a1)*float32(i)/float32(ns))
+		pc.LineTo(rs, p.X, p.Y)
+	}
+}
+
+// DrawSuperellipse draws a superellipse (Lamé curve) centered at x, y with
+// radii rx, ry and exponent n: |x/rx|^n + |y/ry|^n = 1 -- n = 2 is an
+// ellipse, n = 1 is a diamond, and larger values approach a rectangle
+func (pc *Paint) DrawSuperellipse(rs *State, x, y, rx, ry, n float32) {
+	if n <= 0 {
+		return
+	}
+	pc.NewSubPath(rs)
+	pc.drawSuperellipseArc(rs, x, y, rx, ry, n, 0, 2*mat32.Pi)
+	pc.ClosePath(rs)
+}
+
+// DrawSquircle draws a rectangle with corners of radius r that are
+// quarters of a superellipse with exponent SquircleExp, instead of
+// circular arcs as in DrawRoundedRectangle, giving a continuous curvature
+// "squircle" shape as used in modern design systems.  r is limited to
+// half of the smaller of the width and height.
+func (pc *Paint) DrawSquircle(rs *State, x, y, w, h, r float32) {
+	r = mat32.Min(r, 0.5*mat32.Min(w, h))
+	if r <= 0 {
+		pc.DrawRectangle(rs, x, y, w, h)
+		return
+	}
+	n := SquircleExp
+	x1, x2 := x+r, x+w-r
+	y1, y2 := y+r, y+h-r
+	pc.NewSubPath(rs)
+	pc.MoveTo(rs, x1, y)
+	pc.drawSuperellipseArc(rs, x2, y1, r, r, n, 1.5*mat32.Pi, 2*mat32.Pi)
+	pc.drawSuperellipseArc(rs, x2, y2, r, r, n, 0, 0.5*mat32.Pi)
+	pc.drawSuperellipseArc(rs, x1, y2, r, r, n, 0.5*mat32.Pi, mat32.Pi)
+	pc.drawSuperellipseArc(rs, x1, y1, r, r, n, mat32.Pi, 1.5*mat32.Pi)
+	pc.ClosePath(rs)
+}
+
 // DrawImage draws the specified image at the specified point.
 func (pc *Paint) DrawImage(rs *State, fmIm image.Image, x, y float32) {
 	pc.DrawImageAnchored(rs, fmIm, x, y, 0, 0)
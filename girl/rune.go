@@ -19,14 +19,19 @@ import (
 // those pointers -- float32 values used to support better accuracy when
 // transforming points
 type Rune struct {
-	Face    font.Face            `json:"-" xml:"-" desc:"fully-specified font rendering info, includes fully computed font size -- this is exactly what will be drawn -- no further transforms"`
-	Color   color.Color          `json:"-" xml:"-" desc:"color to draw characters in"`
-	BgColor color.Color          `json:"-" xml:"-" desc:"background color to fill background of color -- for highlighting, <mark> tag, etc -- unlike Face, Color, this must be non-nil for every case that uses it, as nil is also used for default transparent background"`
-	Deco    gist.TextDecorations `desc:"additional decoration to apply -- underline, strike-through, etc -- also used for encoding a few special layout hints to pass info from styling tags to separate layout algorithms (e.g., &lt;P&gt; vs &lt;BR&gt;)"`
-	RelPos  mat32.Vec2           `desc:"relative position from start of Text for the lower-left baseline rendering position of the font character"`
-	Size    mat32.Vec2           `desc:"size of the rune itself, exclusive of spacing that might surround it"`
-	RotRad  float32              `desc:"rotation in radians for this character, relative to its lower-left baseline rendering position"`
-	ScaleX  float32              `desc:"scaling of the X dimension, in case of non-uniform scaling, 0 = no separate scaling"`
+	Face        font.Face            `json:"-" xml:"-" desc:"fully-specified font rendering info, includes fully computed font size -- this is exactly what will be drawn -- no further transforms"`
+	Color       color.Color          `json:"-" xml:"-" desc:"color to draw characters in"`
+	BgColor     color.Color          `json:"-" xml:"-" desc:"background color to fill background of color -- for highlighting, <mark> tag, etc -- unlike Face, Color, this must be non-nil for every case that uses it, as nil is also used for default transparent background"`
+	StrokeColor color.Color          `json:"-" xml:"-" desc:"color of the outline of the glyph (text-stroke) -- like BgColor, this must be non-nil for every rune that is stroked"`
+	StrokeWidth float32              `desc:"width of the outline of the glyph (text-stroke), drawn over its fill, in dots -- 0 for none"`
+	Shadows     gist.TextShadows     `json:"-" xml:"-" desc:"shadows drawn behind the glyph (text-shadow), in dots, with their colors set -- runes with the same shadows share them"`
+	Deco        gist.TextDecorations `desc:"additional decoration to apply -- underline, strike-through, etc -- also used for encoding a few special layout hints to pass info from styling tags to separate layout algorithms (e.g., &lt;P&gt; vs &lt;BR&gt;)"`
+	RelPos      mat32.Vec2           `desc:"relative position from start of Text for the lower-left baseline rendering position of the font character"`
+	Size        mat32.Vec2           `desc:"size of the rune itself, exclusive of spacing that might surround it"`
+	RotRad      float32              `desc:"rotation in radians for this character, relative to its lower-left baseline rendering position"`
+	ScaleX      float32              `desc:"scaling of the X dimension, in case of non-uniform scaling, 0 = no separate scaling"`
+	Glyph       rune                 `desc:"rune whose glyph is rendered for this rune, as shaped by the Shaper, e.g., a contextual form of an Arabic letter -- 0 for the rune itself, and NoGlyph if it is rendered as part of the glyph of a previous rune, e.g., a ligature"`
+	Cluster     []ClusterGlyph       `json:"-" xml:"-" desc:"glyphs of the cluster starting at this rune, by index in the font, rendered instead of the Glyph, as shaped by a Shaper that shapes by glyph index -- nil otherwise"`
 }
 
 // HasNil returns error if any of the key info (face, color) is nil -- only
@@ -58,6 +63,37 @@ func (rr *Rune) CurColor(curColor color.Color) color.Color {
 	return curColor
 }
 
+// XForm returns the transform for the rotation and x scaling of the rune,
+// which applies relative to its lower-left baseline rendering position
+func (rr *Rune) XForm() mat32.Mat2 {
+	scx := float32(1)
+	if rr.ScaleX != 0 {
+		scx = rr.ScaleX
+	}
+	return mat32.Scale2D(scx, 1).Rotate(rr.RotRad)
+}
+
+// PosDist returns the distance from given position to the box of the rune,
+// which is 0 if the position is within it, using given span position
+// (Span.RelPos) and current font face (for the descent), as in rendering.
+// Also returns whether the position is after the middle of the rune along
+// its baseline.
+func (rr *Rune) PosDist(spos mat32.Vec2, face font.Face, pos mat32.Vec2) (dist float32, after bool) {
+	dsc := float32(0)
+	if face != nil {
+		dsc = mat32.FromFixed(face.Metrics().Descent)
+	}
+	rp := spos.Add(rr.RelPos)
+	tx := rr.XForm()
+	lp := tx.Inverse().MulVec2AsVec(pos.Sub(rp)) // position in rune coords
+	after = lp.X > 0.5*rr.Size.X
+	cp := mat32.Vec2{mat32.Clamp(lp.X, 0, rr.Size.X), mat32.Clamp(lp.Y, dsc-rr.Size.Y, dsc)}
+	if cp == lp {
+		return 0, after
+	}
+	return rp.Add(tx.MulVec2AsVec(cp)).DistTo(pos), after
+}
+
 // RelPosAfterLR returns the relative position after given rune for LR order: RelPos.X + Size.X
 func (rr *Rune) RelPosAfterLR() float32 {
 	return rr.RelPos.X + rr.Size.X
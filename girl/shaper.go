@@ -0,0 +1,299 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"unicode"
+
+	"github.com/goki/mat32"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Shaper shapes text into glyphs in a given font face: it chooses the
+// glyph for each rune, e.g., the contextual forms of Arabic letters and
+// ligatures, and computes their advances, e.g., with kerning.  To keep the
+// one-to-one correspondence of the runes and their Rune render info in a
+// Span, glyphs are given per rune, in logical order: the first rune of a
+// cluster that is rendered as one glyph, e.g., a ligature, has the glyph
+// and advance of the whole cluster, and the others have NoGlyph and zero
+// advance.  Shapers that shape by glyph index in the font, e.g., for the
+// conjuncts of Indic scripts, which have no rune, give the glyphs of each
+// cluster in the Cluster of the Glyph of its first rune.  The Shaper is
+// used by SetRunePosLR on each run of a Span with the same face -- see
+// TextShaper to plug in another one, e.g., GoTextShaper, based on HarfBuzz,
+// with the gotext build tag, for full support of complex scripts.
+type Shaper interface {
+	// Shape returns the glyph for each rune of given text, in given face
+	Shape(txt []rune, face font.Face) []Glyph
+}
+
+// FeatureShaper is a Shaper that applies font features itself, beyond the
+// SupportedFeatures applied by FeatureFace: those of the Features of a
+// FeatureFace, as set by FeatureFont for any features, e.g., GoTextShaper
+type FeatureShaper interface {
+	Shaper
+
+	// SupportsFeature returns true if the shaper applies the font feature
+	// with given tag, when the font has it
+	SupportsFeature(tag string) bool
+}
+
+// Glyph is the glyph of one rune of shaped text (see Shaper)
+type Glyph struct {
+	Rune    rune           `desc:"rune whose glyph in the font face is rendered -- NoGlyph if the rune is rendered as part of the glyph of a previous rune in its cluster, e.g., a ligature"`
+	Advance float32        `desc:"advance of the pen position to the next glyph, including any kerning with it"`
+	Cluster []ClusterGlyph `desc:"glyphs of the cluster starting at this rune, by index in the font, which are rendered instead of the glyph of the Rune, if the Shaper shapes by glyph index -- nil otherwise"`
+}
+
+// ClusterGlyph is one of the glyphs of a cluster shaped by glyph index in
+// the font of the face (see Glyph), which are rendered from their outlines
+// (see DrawGlyphIndex), so the face must have them (see GlyphOutline)
+type ClusterGlyph struct {
+	Index  sfnt.GlyphIndex `desc:"index of the glyph in the font"`
+	Offset mat32.Vec2      `desc:"position of the origin of the glyph relative to that of the cluster, with y down"`
+}
+
+// NoGlyph is the Glyph Rune of a rune that is rendered as part of the
+// glyph of a previous rune in its cluster, e.g., the second rune of a
+// ligature
+const NoGlyph = rune(-1)
+
+// TextShaper is the Shaper used for the layout of text -- it is the
+// SimpleShaper by default, or the GoTextShaper with the gotext build tag,
+// and can be set to another implementation
+var TextShaper Shaper = &SimpleShaper{}
+
+// SimpleShaper is the default Shaper, which renders one glyph per rune,
+// using the advances and kerning of the font face, except for the Arabic
+// letters, which take their contextual forms and lam-alef ligatures, as
+// presentation forms of the font -- it does not support other complex
+// scripts, e.g., Indic scripts.
+type SimpleShaper struct {
+}
+
+func (ss *SimpleShaper) Shape(txt []rune, face font.Face) []Glyph {
+	sz := len(txt)
+	gs := make([]Glyph, sz)
+	for i := 0; i < sz; i++ {
+		r := txt[i]
+		gs[i].Rune = r
+		if !IsArabicLetter(r) {
+			continue
+		}
+		if lig := ArabicLigature(txt, i); lig != 0 && hasGlyph(face, lig) {
+			gs[i].Rune = lig
+			gs[i+1].Rune = NoGlyph
+			i++
+			continue
+		}
+		if gr := ArabicForm(txt, i); hasGlyph(face, gr) {
+			gs[i].Rune = gr
+		}
+	}
+	for i := range gs {
+		g := &gs[i]
+		if g.Rune == NoGlyph {
+			continue
+		}
+		a, _ := face.GlyphAdvance(g.Rune)
+		g.Advance = mat32.FromFixed(a)
+		for j := i + 1; j < sz; j++ {
+			if gs[j].Rune != NoGlyph {
+				g.Advance += mat32.FromFixed(face.Kern(g.Rune, gs[j].Rune))
+				break
+			}
+		}
+	}
+	return gs
+}
+
+// hasGlyph returns true if given face has a glyph for given rune
+func hasGlyph(face font.Face, r rune) bool {
+	_, ok := face.GlyphAdvance(r)
+	return ok
+}
+
+// Shape shapes the text of the span using the TextShaper, on each run of
+// runes with the same font face, setting the Glyph of their render info,
+// and returns the glyphs
+func (sr *Span) Shape() []Glyph {
+	sz := len(sr.Text)
+	gs := make([]Glyph, 0, sz)
+	curFace := sr.Render[0].Face
+	st := 0
+	for i := 0; i <= sz; i++ {
+		var face font.Face
+		if i < sz {
+			face = sr.Render[i].CurFace(curFace)
+			if face == curFace {
+				continue
+			}
+		}
+		if i > st {
+			gs = append(gs, TextShaper.Shape(sr.Text[st:i], curFace)...)
+		}
+		st = i
+		curFace = face
+	}
+	for i := range gs {
+		gr := gs[i].Rune
+		if gr == sr.Text[i] {
+			gr = 0
+		}
+		sr.Render[i].Glyph = gr
+		sr.Render[i].Cluster = gs[i].Cluster
+	}
+	return gs
+}
+
+// DrawRuneGlyph draws the glyph of the rune at given index, in given face,
+// as shaped (see GlyphRune and ClusterGlyph), into dst with its origin at
+// rp, as DrawGlyph does -- nothing is drawn for runes rendered as part of
+// a previous glyph.  Subpixel rendering is only used for rune glyphs.
+func (sr *Span) DrawRuneGlyph(dst *image.RGBA, bounds image.Rectangle, idx int, face font.Face, rp mat32.Vec2, src *image.Uniform, subpix bool) {
+	rr := &sr.Render[idx]
+	if rr.Cluster == nil {
+		if r := sr.GlyphRune(idx, face); r != NoGlyph {
+			DrawGlyph(dst, bounds, face, r, rp, src, rr.RotRad, rr.ScaleX, subpix)
+		}
+		return
+	}
+	tx := rr.XForm()
+	for _, cg := range rr.Cluster {
+		DrawGlyphIndex(dst, bounds, face, cg.Index, rp.Add(tx.MulVec2AsVec(cg.Offset)), src, rr.RotRad, rr.ScaleX)
+	}
+}
+
+// RuneGlyphOutline adds the outline of the glyph of the rune at given
+// index, in given face, as shaped (see GlyphRune and ClusterGlyph), to the
+// current path of given paint, with its origin at pos, as GlyphOutline
+// does -- returns false if there is none, including for runes rendered as
+// part of a previous glyph.
+func (sr *Span) RuneGlyphOutline(rs *State, pc *Paint, idx int, face font.Face, pos mat32.Vec2, buf *sfnt.Buffer) bool {
+	rr := &sr.Render[idx]
+	tx := rr.XForm()
+	if rr.Cluster == nil {
+		r := sr.GlyphRune(idx, face)
+		return r != NoGlyph && GlyphOutline(rs, pc, face, r, pos, tx, buf)
+	}
+	ok := false
+	for _, cg := range rr.Cluster {
+		if GlyphIndexOutline(rs, pc, face, cg.Index, pos.Add(tx.MulVec2AsVec(cg.Offset)), tx, buf) {
+			ok = true
+		}
+	}
+	return ok
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Arabic shaping
+
+// arabicForms has the isolated presentation form of the basic Arabic
+// letters, from U+0621 to U+064A, and their number of forms, which are in
+// the order: isolated, final, initial, medial -- 2 forms for letters that
+// only join the preceding letter, 4 for those that join both sides, and 0
+// for those without presentation forms
+var arabicForms = [...]struct {
+	Iso   rune
+	Forms int
+}{
+	{0xFE80, 1}, {0xFE81, 2}, {0xFE83, 2}, {0xFE85, 2}, {0xFE87, 2}, {0xFE89, 4}, {0xFE8D, 2}, // ء to ا
+	{0xFE8F, 4}, {0xFE93, 2}, {0xFE95, 4}, {0xFE99, 4}, {0xFE9D, 4}, {0xFEA1, 4}, {0xFEA5, 4}, // ب to خ
+	{0xFEA9, 2}, {0xFEAB, 2}, {0xFEAD, 2}, {0xFEAF, 2}, {0xFEB1, 4}, {0xFEB5, 4}, {0xFEB9, 4}, // د to ص
+	{0xFEBD, 4}, {0xFEC1, 4}, {0xFEC5, 4}, {0xFEC9, 4}, {0xFECD, 4}, // ض to غ
+	{0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 0}, {0, 0}, // U+063B to U+0640 (tatweel)
+	{0xFED1, 4}, {0xFED5, 4}, {0xFED9, 4}, {0xFEDD, 4}, {0xFEE1, 4}, {0xFEE5, 4}, {0xFEE9, 4}, // ف to ه
+	{0xFEED, 2}, {0xFEEF, 2}, {0xFEF1, 4}, // و to ي
+}
+
+// IsArabicLetter returns true if given rune is one of the basic Arabic
+// letters that have presentation forms (see ArabicForm)
+func IsArabicLetter(r rune) bool {
+	return r >= 0x0621 && r <= 0x064A && arabicForms[r-0x0621].Forms > 0
+}
+
+// arabicJoinsNext returns true if given rune joins the letter after it
+func arabicJoinsNext(r rune) bool {
+	return r == 0x0640 || r == 0x200D || (IsArabicLetter(r) && arabicForms[r-0x0621].Forms == 4)
+}
+
+// arabicJoinsPrev returns true if given rune joins the letter before it
+func arabicJoinsPrev(r rune) bool {
+	return r == 0x0640 || r == 0x200D || (IsArabicLetter(r) && arabicForms[r-0x0621].Forms >= 2)
+}
+
+// ArabicForm returns the presentation form of the Arabic letter at given
+// index in given text, for how it joins the letters before and after it
+// (skipping marks, e.g., vowel signs) -- it is the letter itself if it is
+// not one of the basic Arabic letters.  See ArabicLigature for the
+// lam-alef ligatures.
+func ArabicForm(txt []rune, idx int) rune {
+	r := txt[idx]
+	if !IsArabicLetter(r) {
+		return r
+	}
+	af := arabicForms[r-0x0621]
+	if af.Forms < 2 {
+		return af.Iso
+	}
+	prev := false
+	for i := idx - 1; i >= 0; i-- {
+		if !unicode.Is(unicode.Mn, txt[i]) {
+			prev = arabicJoinsNext(txt[i])
+			break
+		}
+	}
+	next := false
+	if af.Forms == 4 {
+		for i := idx + 1; i < len(txt); i++ {
+			if !unicode.Is(unicode.Mn, txt[i]) {
+				next = arabicJoinsPrev(txt[i])
+				break
+			}
+		}
+	}
+	switch {
+	case prev && next:
+		return af.Iso + 3
+	case prev:
+		return af.Iso + 1
+	case next:
+		return af.Iso + 2
+	}
+	return af.Iso
+}
+
+// ArabicLigature returns the presentation form of the lam-alef ligature
+// for the lam at given index in given text, if it is followed by an alef,
+// for whether it joins the letter before it, and 0 otherwise
+func ArabicLigature(txt []rune, idx int) rune {
+	if txt[idx] != 0x0644 || idx+1 >= len(txt) {
+		return 0
+	}
+	var lig rune
+	switch txt[idx+1] {
+	case 0x0622: // alef with madda above
+		lig = 0xFEF5
+	case 0x0623: // alef with hamza above
+		lig = 0xFEF7
+	case 0x0625: // alef with hamza below
+		lig = 0xFEF9
+	case 0x0627:
+		lig = 0xFEFB
+	default:
+		return 0
+	}
+	for i := idx - 1; i >= 0; i-- {
+		if !unicode.Is(unicode.Mn, txt[i]) {
+			if arabicJoinsNext(txt[i]) {
+				lig++ // final form
+			}
+			break
+		}
+	}
+	return lig
+}
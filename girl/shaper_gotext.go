@@ -0,0 +1,185 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build gotext
+// +build gotext
+
+package girl
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/go-text/typesetting/di"
+	gtfont "github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/shaping"
+	"github.com/goki/mat32"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// GoTextShaper is a Shaper based on the HarfBuzz port of the go-text
+// typesetting module (github.com/go-text/typesetting), which
+// fully supports complex scripts, e.g., the conjuncts and reordered vowel
+// signs of Indic scripts, and applies all the font features that the font
+// has among the Features of a FeatureFace (see FeatureFont), e.g., liga 0
+// and ss01.  It shapes by glyph index (see ClusterGlyph), so it needs
+// the font file of the face (see GlyphOutline), and falls back on the
+// SimpleShaper for other faces.  It is only built with the gotext build
+// tag, in which it is the default TextShaper.
+type GoTextShaper struct {
+	mu     sync.Mutex
+	shaper shaping.HarfbuzzShaper
+	faces  map[*faceOutlines]*gtfont.Face
+}
+
+func init() {
+	TextShaper = NewGoTextShaper()
+}
+
+// NewGoTextShaper returns a new GoTextShaper
+func NewGoTextShaper() *GoTextShaper {
+	return &GoTextShaper{faces: make(map[*faceOutlines]*gtfont.Face)}
+}
+
+// SupportsFeature returns true for all the font features, which HarfBuzz
+// applies when the font has them (see FeatureShaper)
+func (gs *GoTextShaper) SupportsFeature(tag string) bool {
+	return len(tag) == 4
+}
+
+func (gs *GoTextShaper) Shape(txt []rune, face font.Face) []Glyph {
+	fo := faceGlyphOutlines(face)
+	if fo == nil || len(txt) == 0 {
+		return (&SimpleShaper{}).Shape(txt, face)
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gf := gs.goTextFace(fo)
+	if gf == nil {
+		return (&SimpleShaper{}).Shape(txt, face)
+	}
+	var feats []shaping.FontFeature
+	if ff, ok := face.(*FeatureFace); ok {
+		for _, f := range ff.Features {
+			if len(f.Tag) == 4 {
+				feats = append(feats, shaping.FontFeature{Tag: ot.MustNewTag(f.Tag), Value: uint32(f.Value)})
+			}
+		}
+	}
+	glyphs := make([]Glyph, len(txt))
+	for i := range glyphs {
+		glyphs[i].Rune = NoGlyph
+	}
+	for st := 0; st < len(txt); {
+		ed, scr := goTextScriptRun(txt, st)
+		in := shaping.Input{
+			Text:         txt,
+			RunStart:     st,
+			RunEnd:       ed,
+			Direction:    goTextDirection(txt[st:ed]),
+			Face:         gf,
+			FontFeatures: feats,
+			Size:         fo.ppem,
+			Script:       scr,
+		}
+		goTextClusters(gs.shaper.Shape(in), txt, ed, glyphs)
+		st = ed
+	}
+	return glyphs
+}
+
+// goTextFace returns the go-text face for the font file of given face
+// outlines, parsing it when first needed, or nil if it can't be parsed --
+// must be called under the mutex
+func (gs *GoTextShaper) goTextFace(fo *faceOutlines) *gtfont.Face {
+	if gf, has := gs.faces[fo]; has {
+		return gf
+	}
+	gf, err := gtfont.ParseTTF(bytes.NewReader(fo.src))
+	if err != nil {
+		gf = nil
+	}
+	if gs.faces == nil {
+		gs.faces = make(map[*faceOutlines]*gtfont.Face)
+	}
+	gs.faces[fo] = gf
+	return gf
+}
+
+// goTextScriptRun returns the end of the run of runes of the same script
+// starting at given index of given text, and its script -- runes of the
+// common and inherited scripts, e.g., spaces, digits and marks, are part of
+// the run that they are in
+func goTextScriptRun(txt []rune, st int) (int, language.Script) {
+	scr := language.Common
+	for i := st; i < len(txt); i++ {
+		rs := language.LookupScript(txt[i])
+		if rs == language.Common || rs == language.Inherited {
+			continue
+		}
+		if scr == language.Common {
+			scr = rs
+			continue
+		}
+		if rs != scr {
+			return i, scr
+		}
+	}
+	return len(txt), scr
+}
+
+// goTextDirection returns the direction of given run of text: that of its
+// first strong character
+func goTextDirection(txt []rune) di.Direction {
+	for _, r := range txt {
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.R, bidi.AL:
+			return di.DirectionRTL
+		case bidi.L:
+			return di.DirectionLTR
+		}
+	}
+	return di.DirectionLTR
+}
+
+// goTextClusters sets the glyphs of the runes of a run of given text,
+// ending at given index, from the output of shaping it: the first rune of
+// each cluster gets all of its glyphs, in visual order, and its advance
+func goTextClusters(out shaping.Output, txt []rune, ed int, glyphs []Glyph) {
+	var starts []int
+	for i := range out.Glyphs {
+		if ci := out.Glyphs[i].ClusterIndex; i == 0 || ci != out.Glyphs[i-1].ClusterIndex {
+			starts = append(starts, ci)
+		}
+	}
+	sort.Ints(starts)
+	for i := 0; i < len(out.Glyphs); {
+		ci := out.Glyphs[i].ClusterIndex
+		g := &glyphs[ci]
+		g.Rune = txt[ci]
+		x := float32(0)
+		for ; i < len(out.Glyphs) && out.Glyphs[i].ClusterIndex == ci; i++ {
+			og := &out.Glyphs[i]
+			off := mat32.Vec2{x + mat32.FromFixed(og.XOffset), -mat32.FromFixed(og.YOffset)} // y is up in go-text
+			g.Cluster = append(g.Cluster, ClusterGlyph{Index: sfnt.GlyphIndex(og.GlyphID), Offset: off})
+			x += mat32.FromFixed(og.XAdvance)
+		}
+		g.Advance += x
+		// runes after the first in the cluster are in its glyphs
+		ni := sort.SearchInts(starts, ci+1)
+		ced := ed
+		if ni < len(starts) {
+			ced = starts[ni]
+		}
+		for ri := ci + 1; ri < ced; ri++ {
+			glyphs[ri] = Glyph{Rune: NoGlyph}
+		}
+	}
+}
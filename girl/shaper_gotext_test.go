@@ -0,0 +1,174 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build gotext
+// +build gotext
+
+package girl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/language"
+	"github.com/goki/gi/gist"
+	"golang.org/x/image/font/sfnt"
+)
+
+// findTestFont returns the path of a font on the system, from given
+// environment variable, or the first that exists of given paths (glob
+// patterns), and skips the test if there is none
+func findTestFont(t *testing.T, env string, pats []string) string {
+	if fn := os.Getenv(env); fn != "" {
+		return fn
+	}
+	for _, pat := range pats {
+		if fns, _ := filepath.Glob(pat); len(fns) > 0 {
+			return fns[0]
+		}
+	}
+	t.Skipf("no font found -- set %s to the path of one", env)
+	return ""
+}
+
+// devanagariFont returns the path of a Devanagari font on the system, from
+// the GIRL_DEVANAGARI_FONT environment variable or the usual places
+func devanagariFont(t *testing.T) string {
+	return findTestFont(t, "GIRL_DEVANAGARI_FONT", []string{
+		"/usr/share/fonts/truetype/noto/NotoSansDevanagari-Regular.ttf",
+		"/usr/share/fonts/noto/NotoSansDevanagari-Regular.ttf",
+		"/usr/share/fonts/google-noto/NotoSansDevanagari-Regular.ttf",
+		"/usr/share/fonts/truetype/lohit-devanagari/Lohit-Devanagari.ttf",
+		"/usr/share/fonts/lohit-devanagari/Lohit-Devanagari.ttf",
+		"/usr/share/fonts/*/*Devanagari*.ttf",
+		"/usr/share/fonts/*/*/*Devanagari*.ttf",
+		"C:/Windows/Fonts/Nirmala.ttf",
+		"C:/Windows/Fonts/mangal.ttf",
+	})
+}
+
+// ligaFont returns the path of a font with the fi ligature on the system,
+// from the GIRL_LIGA_FONT environment variable or the usual places
+func ligaFont(t *testing.T) string {
+	return findTestFont(t, "GIRL_LIGA_FONT", []string{
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		"/usr/share/fonts/dejavu/DejaVuSans.ttf",
+		"/usr/share/fonts/TTF/DejaVuSans.ttf",
+		"/usr/share/fonts/truetype/roboto/*/Roboto-Regular.ttf",
+		"C:/Windows/Fonts/calibri.ttf",
+	})
+}
+
+func TestGoTextShaperDevanagari(t *testing.T) {
+	fn := devanagariFont(t)
+	ff, err := OpenFontFace("Devanagari", fn, 40, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := faceGlyphOutlines(ff.Face).parsed()
+	if f == nil {
+		t.Fatalf("no outlines for %v", fn)
+	}
+	var buf sfnt.Buffer
+	ka, _ := f.GlyphIndex(&buf, 'क')
+	gs := NewGoTextShaper()
+
+	// ka + vowel sign i: one cluster, with the vowel sign reordered before ka
+	txt := []rune("कि")
+	gl := gs.Shape(txt, ff.Face)
+	if len(gl) != len(txt) {
+		t.Fatalf("कि: got %d glyphs, want %d", len(gl), len(txt))
+	}
+	cl := gl[0].Cluster
+	if len(cl) != 2 || gl[1].Rune != NoGlyph || gl[1].Advance != 0 {
+		t.Fatalf("कि: got %+v, want one cluster of 2 glyphs", gl)
+	}
+	if cl[0].Index == ka || cl[1].Index != ka || cl[1].Offset.X <= 0 {
+		t.Errorf("कि: got cluster %+v, want the vowel sign before ka (glyph %d)", cl, ka)
+	}
+
+	// ka + virama + ssa + vowel sign i: the kssa conjunct, which is one
+	// glyph that has no rune, with the vowel sign before it
+	txt = []rune("क्षि")
+	gl = gs.Shape(txt, ff.Face)
+	if len(gl) != len(txt) {
+		t.Fatalf("क्षि: got %d glyphs, want %d", len(gl), len(txt))
+	}
+	cl = gl[0].Cluster
+	if len(cl) == 0 || len(cl) >= len(txt) || gl[0].Advance <= 0 {
+		t.Fatalf("क्षि: got cluster %+v, advance %g, want a conjunct", cl, gl[0].Advance)
+	}
+	for i := 1; i < len(txt); i++ {
+		if gl[i].Rune != NoGlyph || gl[i].Cluster != nil {
+			t.Errorf("क्षि: rune %d not in the cluster: %+v", i, gl[i])
+		}
+	}
+	for _, cg := range cl {
+		if cg.Index == ka {
+			t.Errorf("क्षि: got the glyph of ka in %+v, want the conjunct", cl)
+		}
+	}
+
+	// the runs of other scripts are shaped separately, in the same text
+	txt = []rune("a कि b")
+	gl = gs.Shape(txt, ff.Face)
+	if len(gl[2].Cluster) != 2 || gl[3].Rune != NoGlyph || gl[0].Rune == NoGlyph || gl[5].Rune == NoGlyph {
+		t.Errorf("a कि b: got %+v", gl)
+	}
+}
+
+func TestGoTextShaperFeatures(t *testing.T) {
+	if _, ok := TextShaper.(*GoTextShaper); !ok {
+		t.Errorf("TextShaper: got %T, want the GoTextShaper by default", TextShaper)
+	}
+	fn := ligaFont(t)
+	face, err := OpenFontFace("Liga", fn, 40, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := NewGoTextShaper()
+	txt := []rune("fi")
+	if gl := gs.Shape(txt, face.Face); len(gl[0].Cluster) != 1 || gl[1].Rune != NoGlyph {
+		t.Fatalf("fi: got %+v, want the fi ligature", gl)
+	}
+	// liga is passed on to the shaper by FeatureFont
+	var feats gist.FontFeatures
+	feats.SetString(`"liga" 0`)
+	if uns := UnsupportedFeatures(feats); len(uns) != 0 {
+		t.Errorf("unsupported features: got %v, want none", uns)
+	}
+	ffc := FontLibrary.FeatureFont(face, feats)
+	if gl := gs.Shape(txt, ffc.Face); gl[0].Rune == NoGlyph || gl[1].Rune == NoGlyph {
+		t.Errorf("fi with liga 0: got %+v, want a glyph for each rune", gl)
+	}
+}
+
+func TestGoTextScriptRuns(t *testing.T) {
+	tests := []struct {
+		txt  string
+		st   int
+		ed   int
+		scr  language.Script
+		rtl  bool
+		desc string
+	}{
+		{"abc", 0, 3, language.Latin, false, "one script"},
+		{"ab कि", 0, 3, language.Latin, false, "space in the first run"},
+		{"ab कि", 3, 5, language.Devanagari, false, "second run"},
+		{"12 सलाम", 0, 7, language.Devanagari, false, "leading digits"},
+		{"سلام!", 0, 5, language.Arabic, true, "right-to-left"},
+	}
+	for _, tt := range tests {
+		txt := []rune(tt.txt)
+		ed, scr := goTextScriptRun(txt, tt.st)
+		if ed != tt.ed || scr != tt.scr {
+			t.Errorf("%s: got run to %d in %v, want to %d in %v", tt.desc, ed, scr, tt.ed, tt.scr)
+		}
+		if rtl := goTextDirection(txt[tt.st:ed]) == di.DirectionRTL; rtl != tt.rtl {
+			t.Errorf("%s: got right-to-left %v", tt.desc, rtl)
+		}
+	}
+}
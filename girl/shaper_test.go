@@ -0,0 +1,136 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+)
+
+func TestArabicForm(t *testing.T) {
+	txt := []rune("سلام")
+	want := []rune{0xFEB3, 0xFEE0, 0xFE8E, 0xFEE1} // initial, medial, final, isolated
+	for i := range txt {
+		if f := ArabicForm(txt, i); f != want[i] {
+			t.Errorf("form of %c at %d: got %X, want %X", txt[i], i, f, want[i])
+		}
+	}
+}
+
+func TestArabicLigature(t *testing.T) {
+	tests := []struct {
+		txt string
+		idx int
+		lig rune
+	}{
+		{"لا", 0, 0xFEFB},  // isolated
+		{"سلا", 1, 0xFEFC}, // final
+		{"لأ", 0, 0xFEF7},
+		{"لم", 0, 0},
+		{"ال", 1, 0},
+	}
+	for _, tt := range tests {
+		if lig := ArabicLigature([]rune(tt.txt), tt.idx); lig != tt.lig {
+			t.Errorf("ligature of %q at %d: got %X, want %X", tt.txt, tt.idx, lig, tt.lig)
+		}
+	}
+}
+
+// indexShaper is a Shaper that shapes all of the text as one cluster, of
+// the glyphs of its runes by index in the font, for testing the rendering
+// of ClusterGlyph
+type indexShaper struct {
+}
+
+func (is *indexShaper) Shape(txt []rune, face font.Face) []Glyph {
+	gs := (&SimpleShaper{}).Shape(txt, face)
+	f := faceGlyphOutlines(face).parsed()
+	var buf sfnt.Buffer
+	var cl []ClusterGlyph
+	x := float32(0)
+	for i := range gs {
+		gi, _ := f.GlyphIndex(&buf, txt[i])
+		cl = append(cl, ClusterGlyph{Index: gi, Offset: mat32.Vec2{x, 0}})
+		x += gs[i].Advance
+		if i > 0 {
+			gs[i] = Glyph{Rune: NoGlyph}
+		}
+	}
+	gs[0].Advance = x
+	gs[0].Cluster = cl
+	return gs
+}
+
+func TestClusterGlyphs(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+	FontLibrary.InitFontPaths("/usr/share/fonts/truetype")
+	defer func() { TextShaper = &SimpleShaper{} }()
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	render := func(props ki.Props) (*image.RGBA, *Text) {
+		rs, pc, img := newTestState(image.Point{120, 60})
+		tsty := &gist.Text{}
+		tsty.Defaults()
+		fsty := &gist.Font{}
+		fsty.Defaults()
+		fsty.Size.SetDot(40)
+		fsty.ToDots(&pc.UnContext)
+		fsty.Color = gist.Black
+		fsty.SetStyleProps(nil, props, nil)
+		OpenFont(fsty, &pc.UnContext)
+		tr := &Text{}
+		tr.SetString("HI", fsty, &pc.UnContext, tsty, true, 0, 0)
+		tr.Render(rs, mat32.Vec2{10, 50})
+		return img, tr
+	}
+	// dark returns the number and bounds of the dark pixels of the image
+	dark := func(img *image.RGBA) (int, image.Rectangle) {
+		n := 0
+		var bb image.Rectangle
+		for y := 0; y < img.Bounds().Dy(); y++ {
+			for x := 0; x < img.Bounds().Dx(); x++ {
+				if img.RGBAAt(x, y).G < 0x80 {
+					n++
+					bb = bb.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		return n, bb
+	}
+
+	img, _ := render(nil)
+	wn, wbb := dark(img)
+	TextShaper = &indexShaper{}
+	img, tr := render(nil)
+	rr := tr.Spans[0].Render
+	if len(rr[0].Cluster) != 2 || rr[1].Glyph != NoGlyph {
+		t.Fatalf("cluster not set in the render info: %v, %v", rr[0].Cluster, rr[1].Glyph)
+	}
+	n, bb := dark(img)
+	if wn < 100 || n < wn*9/10 || n > wn*11/10 {
+		t.Errorf("dark pixels of the cluster glyphs: got %d, want about %d", n, wn)
+	}
+	if d := bb.Min.Sub(wbb.Min).Add(bb.Max.Sub(wbb.Max)); d.X < -2 || d.X > 2 || d.Y < -2 || d.Y > 2 {
+		t.Errorf("bounds of the cluster glyphs: got %v, want about %v", bb, wbb)
+	}
+
+	img, _ = render(ki.Props{"text-stroke": "3px red"})
+	if n := countColor(img, red); n < 100 {
+		t.Errorf("red pixels with text-stroke: got %d, want outlines of the cluster glyphs", n)
+	}
+	img, _ = render(ki.Props{"text-shadow": "4px 4px red"})
+	if n := countColor(img, red); n < 100 {
+		t.Errorf("red pixels with text-shadow: got %d, want shadows of the cluster glyphs", n)
+	}
+}
@@ -7,7 +7,10 @@ package girl
 import (
 	"errors"
 	"fmt"
+	"image"
 	"image/color"
+	"image/draw"
+	"sort"
 	"sync"
 	"unicode"
 
@@ -17,6 +20,7 @@ import (
 	"github.com/goki/ki/bitflag"
 	"github.com/goki/mat32"
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
 )
 
 // Span contains fully explicit data needed for rendering a span of text
@@ -32,12 +36,14 @@ import (
 // span-as-line.  The first Rune RelPos for LR text should be at X=0
 // (LastPos = 0 for RL) -- i.e., relpos positions are minimal for given span.
 type Span struct {
-	Text    []rune               `desc:"text as runes"`
-	Render  []Rune               `desc:"render info for each rune in one-to-one correspondence"`
-	RelPos  mat32.Vec2           `desc:"position for start of text relative to an absolute coordinate that is provided at the time of rendering -- this typically includes the baseline offset to align all rune rendering there -- individual rune RelPos are added to this plus the render-time offset to get the final position"`
-	LastPos mat32.Vec2           `desc:"rune position for further edge of last rune -- for standard flat strings this is the overall length of the string -- used for size / layout computations -- you do not add RelPos to this -- it is in same Text relative coordinates"`
-	Dir     gist.TextDirections  `desc:"where relevant, this is the (default, dominant) text direction for the span"`
-	HasDeco gist.TextDecorations `desc:"mask of decorations that have been set on this span -- optimizes rendering passes"`
+	Text       []rune               `desc:"text as runes"`
+	Render     []Rune               `desc:"render info for each rune in one-to-one correspondence"`
+	RelPos     mat32.Vec2           `desc:"position for start of text relative to an absolute coordinate that is provided at the time of rendering -- this typically includes the baseline offset to align all rune rendering there -- individual rune RelPos are added to this plus the render-time offset to get the final position"`
+	LastPos    mat32.Vec2           `desc:"rune position for further edge of last rune -- for standard flat strings this is the overall length of the string -- used for size / layout computations -- you do not add RelPos to this -- it is in same Text relative coordinates"`
+	Dir        gist.TextDirections  `desc:"where relevant, this is the (default, dominant) text direction for the span"`
+	HasDeco    gist.TextDecorations `desc:"mask of decorations that have been set on this span -- optimizes rendering passes"`
+	BidiLevels []uint8              `desc:"bidi embedding level of each rune, in logical order, when its positions have been reordered into visual order by SetRunePosBidi -- odd levels are right-to-left -- nil if all the text is left-to-right"`
+	Justified  float32              `desc:"extra space added between the words or letters by JustifyLR, which is reset by SetRunePosLR -- the rune positions must be set again before laying out the text again"`
 }
 
 // Init initializes a new span with given capacity
@@ -47,6 +53,20 @@ func (sr *Span) Init(capsz int) {
 	sr.HasDeco = 0
 }
 
+// Reset resets the span to empty, with room for given number of runes,
+// reusing its allocated render info -- the text itself is allocated anew,
+// as it can be owned by the caller (see SetRunes)
+func (sr *Span) Reset(capsz int) {
+	rnd := sr.Render[:0]
+	*sr = Span{}
+	sr.Text = make([]rune, 0, capsz)
+	if cap(rnd) >= capsz {
+		sr.Render = rnd
+	} else {
+		sr.Render = make([]Rune, 0, capsz)
+	}
+}
+
 // IsValid ensures that at least some text is represented and the sizes of
 // Text and Render slices are the same, and that the first render info is non-nil
 func (sr *Span) IsValid() error {
@@ -67,6 +87,9 @@ func (sr *Span) SizeHV() mat32.Vec2 {
 		return mat32.Vec2{}
 	}
 	sz := sr.Render[0].RelPos.Sub(sr.LastPos)
+	if sr.BidiLevels != nil { // first rune is not at the start
+		sz.X = sr.LastPos.X
+	}
 	if sz.X < 0 {
 		sz.X = -sz.X
 	}
@@ -79,26 +102,44 @@ func (sr *Span) SizeHV() mat32.Vec2 {
 // RuneRelPos returns the relative (starting) position of the given rune index
 // (adds Span RelPos and rune RelPos) -- this is typically the baseline
 // position where rendering will start, not the upper left corner. if index >
-// length, then uses LastPos
+// length, then uses EndPos.  For a right-to-left rune (see SetRunePosBidi),
+// its start is its right edge, so positions follow the visual order.
 func (sr *Span) RuneRelPos(idx int) mat32.Vec2 {
 	if idx >= len(sr.Render) {
-		return sr.LastPos
+		return sr.EndPos()
 	}
-	return sr.RelPos.Add(sr.Render[idx].RelPos)
+	spos := sr.RelPos.Add(sr.Render[idx].RelPos)
+	if sr.IsRTLRune(idx) {
+		spos.X += sr.Render[idx].Size.X
+	}
+	return spos
 }
 
 // RuneEndPos returns the relative ending position of the given rune index
 // (adds Span RelPos and rune RelPos + rune Size.X for LR writing). If index >
-// length, then uses LastPos
+// length, then uses EndPos.  For a right-to-left rune (see SetRunePosBidi),
+// its end is its left edge, so positions follow the visual order.
 func (sr *Span) RuneEndPos(idx int) mat32.Vec2 {
 	if idx >= len(sr.Render) {
-		return sr.LastPos
+		return sr.EndPos()
 	}
 	spos := sr.RelPos.Add(sr.Render[idx].RelPos)
-	spos.X += sr.Render[idx].Size.X
+	if !sr.IsRTLRune(idx) {
+		spos.X += sr.Render[idx].Size.X
+	}
 	return spos
 }
 
+// EndPos returns the position after the end of the text, for a cursor:
+// LastPos, or the left edge of the span for a right-to-left span (Dir =
+// RLTB), where the text ends
+func (sr *Span) EndPos() mat32.Vec2 {
+	if sr.Dir == gist.RLTB {
+		return mat32.Vec2{0, sr.LastPos.Y}
+	}
+	return sr.LastPos
+}
+
 // AppendRune adds one rune and associated formatting info
 func (sr *Span) HasDecoUpdate(bg color.Color, deco gist.TextDecorations) {
 	sr.HasDeco |= deco
@@ -122,6 +163,41 @@ func (sr *Span) SetNewPara() {
 	}
 }
 
+// SetEffects sets the outline (text-stroke) and shadows (text-shadow) of
+// the runes from index st to the end to those of given font style, if it
+// has them -- e.g., after appending the runes in that style with
+// AppendString
+func (sr *Span) SetEffects(st int, sty *gist.Font) {
+	if st >= len(sr.Render) {
+		return
+	}
+	if sw := sty.StrokeWidth.Dots; sw > 0 {
+		var sc color.Color = sty.StrokeColor
+		if sty.StrokeColor.IsNil() {
+			sc = sty.Color
+		}
+		for i := st; i < len(sr.Render); i++ {
+			sr.Render[i].StrokeColor = sc
+			sr.Render[i].StrokeWidth = sw
+		}
+		bitflag.Set32((*int32)(&sr.HasDeco), int(gist.DecoStroke))
+	}
+	if len(sty.TextShadow) > 0 {
+		// copy, as the style's shadows can be converted to dots again in
+		// another context
+		shs := append(gist.TextShadows(nil), sty.TextShadow...)
+		for i := range shs {
+			if shs[i].Color.IsNil() {
+				shs[i].Color = sty.Color
+			}
+		}
+		for i := st; i < len(sr.Render); i++ {
+			sr.Render[i].Shadows = shs
+		}
+		bitflag.Set32((*int32)(&sr.HasDeco), int(gist.DecoShadow))
+	}
+}
+
 // AppendRune adds one rune and associated formatting info
 func (sr *Span) AppendRune(r rune, face font.Face, clr, bg color.Color, deco gist.TextDecorations) {
 	sr.Text = append(sr.Text, r)
@@ -221,6 +297,7 @@ func (sr *Span) SetRenders(sty *gist.Font, ctxt *units.Context, noBG bool, rot,
 			sr.Render[i].Deco = sty.Deco
 		}
 	}
+	sr.SetEffects(0, sty)
 	// use unicode font for all non-ascii symbols
 	lastUc := false
 	for i, r := range sr.Text {
@@ -262,16 +339,20 @@ func (sr *Span) SetRunes(str []rune, sty *gist.Font, ctxt *units.Context, noBG b
 var TextFontRenderMu sync.Mutex
 
 // SetRunePosLR sets relative positions of each rune using a flat
-// left-to-right text layout, based on font size info and additional extra
-// letter and word spacing parameters (which can be negative)
-func (sr *Span) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize int) {
+// left-to-right text layout, based on the glyphs of the text as shaped by
+// the TextShaper (see Shape), and additional extra letter and word
+// spacing parameters (which can be negative).  Tabs advance to the next of
+// the given explicit tab stops (see gist.TabStops), which also align the
+// text after them up to the next tab, and past the last one to the next
+// multiple of tabSize characters of size chsz.
+func (sr *Span) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize int, tabStops gist.TabStops) {
 	if err := sr.IsValid(); err != nil {
 		// log.Println(err)
 		return
 	}
 	sr.Dir = gist.LRTB
+	sr.BidiLevels = nil
 	sz := len(sr.Text)
-	prevR := rune(-1)
 	lspc := letterSpace
 	wspc := wordSpace
 	if tabSize == 0 {
@@ -281,14 +362,45 @@ func (sr *Span) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize int)
 	curFace := sr.Render[0].Face
 	TextFontRenderMu.Lock()
 	defer TextFontRenderMu.Unlock()
+	gs := sr.Shape()
+	pend := -1 // tab whose stop aligns the text after it, up to ed
+	var pstop gist.TabStop
+	alignTab := func(ed int) {
+		if pend < 0 {
+			return
+		}
+		st := pend + 1
+		pend = -1
+		if st >= ed {
+			return
+		}
+		spos := sr.Render[st].RelPos.X
+		wd := sr.Render[ed-1].RelPosAfterLR() - spos
+		if pstop.Align == gist.TabDecimal {
+			for i := st; i < ed; i++ {
+				if sr.Text[i] == '.' {
+					wd = sr.Render[i].RelPos.X - spos
+					break
+				}
+			}
+		}
+		if pstop.Align == gist.TabCenter {
+			wd /= 2
+		}
+		off := pstop.Pos.Dots - wd - spos
+		if off <= 0 { // does not fit before the stop
+			return
+		}
+		for i := st; i < ed; i++ {
+			sr.Render[i].RelPos.X += off
+		}
+		fpos += off
+	}
 	for i, r := range sr.Text {
 		rr := &(sr.Render[i])
 		curFace = rr.CurFace(curFace)
 
 		fht := mat32.FromFixed(curFace.Metrics().Height)
-		if prevR >= 0 {
-			fpos += mat32.FromFixed(curFace.Kern(prevR, r))
-		}
 		rr.RelPos.X = fpos
 		rr.RelPos.Y = 0
 
@@ -300,14 +412,24 @@ func (sr *Span) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize int)
 		}
 
 		// todo: could check for various types of special unicode space chars here
-		a, _ := curFace.GlyphAdvance(r)
-		a32 := mat32.FromFixed(a)
-		if a32 == 0 {
+		a32 := gs[i].Advance
+		if a32 == 0 && gs[i].Rune != NoGlyph && !unicode.Is(unicode.Cf, r) { // format chars, e.g., zero width space, are invisible
 			a32 = .1 * fht // something..
 		}
 		rr.Size = mat32.Vec2{a32, fht}
 
 		if r == '\t' {
+			alignTab(i)
+			rr.RelPos.X = fpos
+			if ts, ok := tabStops.Next(fpos); ok {
+				if ts.Align == gist.TabLeft {
+					fpos = ts.Pos.Dots
+				} else {
+					pend = i
+					pstop = ts
+				}
+				continue
+			}
 			col := int(mat32.Ceil(fpos / chsz))
 			curtab := col / tabSize
 			curtab++
@@ -318,17 +440,18 @@ func (sr *Span) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize int)
 			}
 		} else {
 			fpos += a32
-			if i < sz-1 {
+			if i < sz-1 && gs[i+1].Rune != NoGlyph {
 				fpos += lspc
 				if unicode.IsSpace(r) {
 					fpos += wspc
 				}
 			}
 		}
-		prevR = r
 	}
+	alignTab(sz)
 	sr.LastPos.X = fpos
 	sr.LastPos.Y = 0
+	sr.Justified = 0
 }
 
 // SetRunePosTB sets relative positions of each rune using a flat
@@ -470,9 +593,130 @@ func (sr *Span) SetRunePosTBRot(letterSpace, wordSpace, chsz float32, tabSize in
 	sr.LastPos.X = 0
 }
 
-// FindWrapPosLR finds a position to do word wrapping to fit within trgSize --
-// RelPos positions must have already been set (e.g., SetRunePosLR)
-func (sr *Span) FindWrapPosLR(trgSize, curSize float32) int {
+// SetRunePosVert sets relative positions of each rune for a column of
+// vertical text (see Text.LayoutStdTB), running down from 0 and centered
+// on X = 0: the ideographs, kana and hangul of Chinese, Japanese and Korean
+// text (see IsCJKBreak) are upright, and the other runes are rotated 90
+// degrees clockwise if sideways is true (glyph-orientation-vertical of 90,
+// the default), and otherwise upright too.  Returns the position after each
+// rune along the column, for wrapping (see FindWrapPosTB).
+func (sr *Span) SetRunePosVert(letterSpace, wordSpace, chsz float32, tabSize int, sideways bool) []float32 {
+	if err := sr.IsValid(); err != nil {
+		// log.Println(err)
+		return nil
+	}
+	sr.Dir = gist.TB
+	sr.BidiLevels = nil
+	sr.Justified = 0
+	sz := len(sr.Text)
+	lspc := letterSpace
+	wspc := wordSpace
+	if tabSize == 0 {
+		tabSize = 4
+	}
+	ends := make([]float32, sz)
+	var fpos float32
+	curFace := sr.Render[0].Face
+	TextFontRenderMu.Lock()
+	defer TextFontRenderMu.Unlock()
+	col := 0 // current column position, for tabs
+	for i, r := range sr.Text {
+		rr := &(sr.Render[i])
+		curFace = rr.CurFace(curFace)
+
+		m := curFace.Metrics()
+		asc := mat32.FromFixed(m.Ascent)
+		dsc := mat32.FromFixed(m.Descent)
+		a, _ := curFace.GlyphAdvance(r)
+		a32 := mat32.FromFixed(a)
+		if a32 == 0 {
+			a32 = .1 * (asc + dsc) // something..
+		}
+		rr.Size = mat32.Vec2{a32, asc + dsc} // in the frame of the glyph, as rotated
+		adv := a32
+		if sideways && !IsCJKBreak(r) {
+			rr.RotRad = mat32.Pi / 2 // baseline runs down, centered on the column
+			rr.RelPos = mat32.Vec2{-(asc - dsc) / 2, fpos}
+		} else {
+			rr.RotRad = 0
+			if !IsCJKBreak(r) { // only CJK glyphs are square
+				adv = asc + dsc
+			}
+			rr.RelPos = mat32.Vec2{-a32 / 2, fpos + (adv-(asc+dsc))/2 + asc}
+		}
+
+		if r == '\t' {
+			curtab := col / tabSize
+			curtab++
+			col = curtab * tabSize
+			cpos := chsz * float32(col)
+			if cpos > fpos {
+				fpos = cpos
+			}
+			ends[i] = fpos
+		} else {
+			fpos += adv
+			ends[i] = fpos
+			col++
+			if i < sz-1 {
+				fpos += lspc
+				if unicode.IsSpace(r) {
+					fpos += wspc
+				}
+			}
+		}
+	}
+	sr.LastPos.X = 0
+	sr.LastPos.Y = fpos
+	return ends
+}
+
+// FindWrapPosTB finds a position to wrap a column of vertical text, with
+// given position after each rune (see SetRunePosVert), to fit within
+// trgSize, per given word break style (see LineBreakOK) -- returns -1 if
+// it fits, or there is no position to wrap at
+func (sr *Span) FindWrapPosTB(ends []float32, trgSize float32, wb gist.WordBreaks) int {
+	sz := len(sr.Text)
+	if sz == 0 || len(ends) != sz {
+		return -1
+	}
+	idx := sort.Search(sz, func(i int) bool { return ends[i] > trgSize }) // first rune that does not fit
+	if idx >= sz {
+		return -1
+	}
+	if IsBreakSpace(sr.Text[idx]) {
+		for idx < sz && IsBreakSpace(sr.Text[idx]) { // break at END of whitespace
+			idx++
+		}
+		if idx < sz {
+			return idx
+		}
+		return -1
+	}
+	for bi := idx; bi > 0; bi-- {
+		if LineBreakOK(sr.Text, bi, wb) {
+			return bi
+		}
+	}
+	if wb == gist.WordBreakNormal { // as in FindWrapPosLR
+		for bi := idx; bi > 0; bi-- {
+			if IsComplexBreak(sr.Text[bi-1]) && IsComplexBreak(sr.Text[bi]) && !NoBreakBefore(sr.Text[bi]) {
+				return bi
+			}
+		}
+	}
+	for bi := idx + 1; bi < sz; bi++ {
+		if LineBreakOK(sr.Text, bi, wb) {
+			return bi
+		}
+	}
+	return -1 // unbreakable
+}
+
+// FindWrapPosLR finds a position to do word wrapping to fit within trgSize,
+// per given word break style (see LineBreakOK) -- RelPos positions must
+// have already been set (e.g., SetRunePosLR)
+func (sr *Span) FindWrapPosLR(trgSize, curSize float32, wb gist.WordBreaks) int {
 	sz := len(sr.Text)
 	if sz == 0 {
 		return -1
@@ -501,32 +745,34 @@ func (sr *Span) FindWrapPosLR(trgSize, curSize float32) int {
 			idx++
 		}
 	}
-	if unicode.IsSpace(sr.Text[idx]) {
+	if IsBreakSpace(sr.Text[idx]) {
 		idx++
-		for idx < sz && unicode.IsSpace(sr.Text[idx]) { // break at END of whitespace
+		for idx < sz && IsBreakSpace(sr.Text[idx]) { // break at END of whitespace
 			idx++
 		}
 		return idx
 	}
-	// find earlier space
-	for idx > 0 && !unicode.IsSpace(sr.Text[idx-1]) {
-		idx--
-	}
-	if idx > 0 {
-		return idx
-	}
-	// no spaces within size -- find next break going up
-	for idx < sz && !unicode.IsSpace(sr.Text[idx]) {
-		idx++
+	// find earlier break
+	for bi := idx + 1; bi > 0; bi-- {
+		if LineBreakOK(sr.Text, bi, wb) {
+			return bi
+		}
 	}
-	if idx == sz-1 { // unbreakable
-		return -1
+	// no break within size: break words in scripts without spaces (Thai etc) anywhere
+	if wb == gist.WordBreakNormal {
+		for bi := idx + 1; bi > 0 && bi < sz; bi-- {
+			if IsComplexBreak(sr.Text[bi-1]) && IsComplexBreak(sr.Text[bi]) && !NoBreakBefore(sr.Text[bi]) {
+				return bi
+			}
+		}
 	}
-	idx++
-	for idx < sz && unicode.IsSpace(sr.Text[idx]) { // break at END of whitespace
-		idx++
+	// find next break going up
+	for bi := idx + 2; bi < sz; bi++ {
+		if LineBreakOK(sr.Text, bi, wb) {
+			return bi
+		}
 	}
-	return idx
+	return -1 // unbreakable
 }
 
 // ZeroPos ensures that the positions start at 0, for LR direction
@@ -550,7 +796,7 @@ func (sr *Span) ZeroPosLR() {
 func (sr *Span) TrimSpaceLeftLR() {
 	srr0 := sr.Render[0]
 	for range sr.Text {
-		if unicode.IsSpace(sr.Text[0]) {
+		if IsBreakSpace(sr.Text[0]) {
 			sr.Text = sr.Text[1:]
 			sr.Render = sr.Render[1:]
 			if len(sr.Render) > 0 {
@@ -573,7 +819,7 @@ func (sr *Span) TrimSpaceLeftLR() {
 func (sr *Span) TrimSpaceRightLR() {
 	for range sr.Text {
 		lidx := len(sr.Text) - 1
-		if unicode.IsSpace(sr.Text[lidx]) {
+		if IsBreakSpace(sr.Text[lidx]) {
 			sr.Text = sr.Text[:lidx]
 			sr.Render = sr.Render[:lidx]
 			lidx--
@@ -605,6 +851,7 @@ func (sr *Span) SplitAtLR(idx int) *Span {
 	nsr := Span{Text: sr.Text[idx:], Render: sr.Render[idx:], Dir: sr.Dir, HasDeco: sr.HasDeco}
 	sr.Text = sr.Text[:idx]
 	sr.Render = sr.Render[:idx]
+	sr.BidiLevels = nil // positions must be in logical order, for LR splitting
 	sr.LastPos.X = sr.Render[idx-1].RelPosAfterLR()
 	// sr.TrimSpaceLR()
 	// nsr.TrimSpaceLeftLR() // don't trim right!
@@ -622,6 +869,66 @@ func (sr *Span) SplitAtLR(idx int) *Span {
 	return &nsr
 }
 
+// JustifyLR stretches the span to given width, for text-align: justify in
+// LR direction: the extra space between the end of its last non-space rune
+// and the width is distributed evenly to its word spaces, or, if there are
+// none (e.g., in Chinese or Japanese text), between all of its letters.
+// Returns false if the span cannot be justified: if it is in visual (bidi)
+// order, it is a single letter, or it does not fit in the width.
+func (sr *Span) JustifyLR(width float32) bool {
+	if sr.IsValid() != nil || sr.BidiLevels != nil {
+		return false
+	}
+	st := 0
+	for st < len(sr.Text) && unicode.IsSpace(sr.Text[st]) { // leading space is not stretched
+		st++
+	}
+	ed := len(sr.Text)
+	for ed > st && unicode.IsSpace(sr.Text[ed-1]) { // trailing space hangs past the width
+		ed--
+	}
+	if ed-st < 2 {
+		return false
+	}
+	extra := width - (sr.Render[ed-1].RelPosAfterLR() - sr.Render[0].RelPos.X)
+	if extra <= 0 {
+		return false
+	}
+	isGap := func(i int) bool { // space stretched after rune i
+		r := sr.Text[i]
+		return unicode.IsSpace(r) && r != '\t'
+	}
+	countGaps := func() int {
+		n := 0
+		for i := st; i < ed-1; i++ {
+			if isGap(i) {
+				n++
+			}
+		}
+		return n
+	}
+	ngaps := countGaps()
+	if ngaps == 0 { // letter spacing instead, not before combining marks
+		isGap = func(i int) bool {
+			return !unicode.Is(unicode.Mn, sr.Text[i+1])
+		}
+		if ngaps = countGaps(); ngaps == 0 {
+			return false
+		}
+	}
+	per := extra / float32(ngaps)
+	off := float32(0)
+	for i := range sr.Render {
+		sr.Render[i].RelPos.X += off
+		if i >= st && i < ed-1 && isGap(i) {
+			off += per
+		}
+	}
+	sr.LastPos.X += off
+	sr.Justified = off
+	return true
+}
+
 // LastFont finds the last font and color from given span
 func (sr *Span) LastFont() (face font.Face, color color.Color) {
 	for i := len(sr.Render) - 1; i >= 0; i-- {
@@ -659,7 +966,7 @@ func (sr *Span) RenderBg(rs *State, tpos mat32.Vec2) {
 			continue
 		}
 		curFace = rr.CurFace(curFace)
-		dsc32 := mat32.FromFixed(curFace.Metrics().Descent)
+		dsc32 := mat32.FromFixed(FaceMetrics(curFace).Descent)
 		rp := tpos.Add(rr.RelPos)
 		scx := float32(1)
 		if rr.ScaleX != 0 {
@@ -689,6 +996,120 @@ func (sr *Span) RenderBg(rs *State, tpos mat32.Vec2) {
 	}
 }
 
+// RenderStroke renders the outlines of the glyphs of the runes that have a
+// text-stroke (StrokeWidth > 0), over their fill, in their StrokeColor --
+// the outlines of consecutive runes with the same stroke are stroked
+// together.  The glyphs of faces without outlines (see GlyphOutline) are
+// not stroked.
+func (sr *Span) RenderStroke(rs *State, tpos mat32.Vec2) {
+	curFace := sr.Render[0].Face
+	pc := &rs.Paint
+	pc.StrokeStyle.Defaults()
+	var buf sfnt.Buffer
+	var lastClr color.Color
+	var lastWd float32
+	didLast := false
+	for i, r := range sr.Text {
+		rr := &(sr.Render[i])
+		curFace = rr.CurFace(curFace)
+		if rr.StrokeWidth <= 0 || rr.StrokeColor == nil || !unicode.IsPrint(r) {
+			continue
+		}
+		if rr.Cluster == nil && sr.GlyphRune(i, curFace) == NoGlyph {
+			continue
+		}
+		if didLast && (rr.StrokeColor != lastClr || rr.StrokeWidth != lastWd) {
+			pc.Stroke(rs)
+			didLast = false
+		}
+		if sr.RuneGlyphOutline(rs, pc, i, curFace, tpos.Add(rr.RelPos), &buf) {
+			pc.StrokeStyle.SetColor(rr.StrokeColor)
+			pc.StrokeStyle.Width.Dots = rr.StrokeWidth
+			lastClr, lastWd = rr.StrokeColor, rr.StrokeWidth
+			didLast = true
+		}
+	}
+	if didLast {
+		pc.Stroke(rs)
+	}
+}
+
+// RenderShadows renders the shadows (text-shadow) of the glyphs of the
+// runes that have them, behind their fill: for each shadow of consecutive
+// runes with the same shadows, their glyphs are drawn at its offset in its
+// color into a separate image, which is blurred by a gaussian with half of
+// its blur radius as the standard deviation, as in CSS, and then drawn
+// over the image.  The last shadow is drawn first, so the first is on top.
+func (sr *Span) RenderShadows(rs *State, tpos mat32.Vec2) {
+	sz := len(sr.Text)
+	for st := 0; st < sz; {
+		shs := sr.Render[st].Shadows
+		ed := st + 1
+		for ed < sz && sameShadows(sr.Render[ed].Shadows, shs) {
+			ed++
+		}
+		for si := len(shs) - 1; si >= 0; si-- {
+			sr.renderShadow(rs, tpos, st, ed, &shs[si])
+		}
+		st = ed
+	}
+}
+
+// sameShadows returns true if given shadows are the same, as set by
+// SetEffects for runes in the same style
+func sameShadows(a, b gist.TextShadows) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// renderShadow renders given shadow of the glyphs of the runes from st to
+// ed (exclusive) -- see RenderShadows
+func (sr *Span) renderShadow(rs *State, tpos mat32.Vec2, st, ed int, sh *gist.TextShadow) {
+	off := mat32.Vec2{sh.HOffset.Dots, sh.VOffset.Dots}
+	sd := sh.Blur.Dots / 2
+	mg := int(mat32.Ceil(3 * sd))
+	curFace := sr.Render[0].Face
+	faces := make([]font.Face, ed-st)
+	var bmin, bmax mat32.Vec2
+	for i := 0; i < ed; i++ {
+		rr := &(sr.Render[i])
+		curFace = rr.CurFace(curFace)
+		if i < st {
+			continue
+		}
+		faces[i-st] = curFace
+		dsc32 := mat32.FromFixed(FaceMetrics(curFace).Descent)
+		scx := float32(1)
+		if rr.ScaleX != 0 {
+			scx = rr.ScaleX
+		}
+		tx := mat32.Scale2D(scx, 1).Rotate(rr.RotRad)
+		ll := tpos.Add(rr.RelPos).Add(off).Add(tx.MulVec2AsVec(mat32.Vec2{0, dsc32}))
+		ur := ll.Add(tx.MulVec2AsVec(mat32.Vec2{rr.Size.X, -rr.Size.Y}))
+		if i == st {
+			bmin, bmax = ll.Min(ur), ll.Max(ur)
+		} else {
+			bmin, bmax = bmin.Min(ll.Min(ur)), bmax.Max(ll.Max(ur))
+		}
+	}
+	reg := image.Rect(int(mat32.Floor(bmin.X))-mg, int(mat32.Floor(bmin.Y))-mg,
+		int(mat32.Ceil(bmax.X))+mg, int(mat32.Ceil(bmax.Y))+mg).Intersect(rs.Bounds)
+	if reg.Empty() {
+		return
+	}
+	img := image.NewRGBA(reg)
+	src := image.NewUniform(sh.Color)
+	for i := st; i < ed; i++ {
+		rr := &(sr.Render[i])
+		r := sr.Text[i]
+		if !unicode.IsPrint(r) {
+			continue
+		}
+		sr.DrawRuneGlyph(img, reg, i, faces[i-st], tpos.Add(rr.RelPos).Add(off), src, false)
+	}
+	GaussianBlur(img, reg, sd)
+	draw.Draw(rs.Image, reg, img, reg.Min, draw.Over)
+}
+
 // RenderUnderline renders the underline for span -- ensures continuity to do it all at once
 func (sr *Span) RenderUnderline(rs *State, tpos mat32.Vec2) {
 	curFace := sr.Render[0].Face
@@ -712,7 +1133,7 @@ func (sr *Span) RenderUnderline(rs *State, tpos mat32.Vec2) {
 		if rr.Color != nil {
 			curColor = rr.Color
 		}
-		dsc32 := mat32.FromFixed(curFace.Metrics().Descent)
+		dsc32 := mat32.FromFixed(FaceMetrics(curFace).Descent)
 		rp := tpos.Add(rr.RelPos)
 		scx := float32(1)
 		if rr.ScaleX != 0 {
@@ -774,8 +1195,8 @@ func (sr *Span) RenderLine(rs *State, tpos mat32.Vec2, deco gist.TextDecorations
 			continue
 		}
 		curFace = rr.CurFace(curFace)
-		dsc32 := mat32.FromFixed(curFace.Metrics().Descent)
-		asc32 := mat32.FromFixed(curFace.Metrics().Ascent)
+		dsc32 := mat32.FromFixed(FaceMetrics(curFace).Descent)
+		asc32 := mat32.FromFixed(FaceMetrics(curFace).Ascent)
 		rp := tpos.Add(rr.RelPos)
 		scx := float32(1)
 		if rr.ScaleX != 0 {
@@ -35,6 +35,7 @@ type State struct {
 	XFormStack     []mat32.Mat2      `desc:"stack of transforms"`
 	BoundsStack    []image.Rectangle `desc:"stack of bounds -- every render starts with a push onto this stack, and finishes with a pop"`
 	ClipStack      []*image.Alpha    `desc:"stack of clips, if needed"`
+	LayerStack     []*Layer          `desc:"stack of layers that rendering is redirected into, for group opacity and blending -- see PushLayer"`
 	PaintBack      Paint             `desc:"backup of paint -- don't need a full stack but sometimes safer to backup and restore"`
 	RenderMu       sync.Mutex        `desc:"mutex for overall rendering"`
 	RasterMu       sync.Mutex        `desc:"mutex for final rasterx rendering -- only one at a time"`
@@ -0,0 +1,94 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"strings"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+)
+
+// StyledRun is a run of text in one style, for building rich text
+// programmatically with Text.SetRuns, instead of generating HTML for
+// SetHTML to parse -- e.g., for syntax-highlighted code or chat messages.
+type StyledRun struct {
+	Text      string   `desc:"text of the run, as-is -- a newline (\n) starts a new span (line), as a <br> tag does in SetHTML"`
+	Props     ki.Props `desc:"font style properties of the run, overriding those of the base font, as in the style attribute of an HTML span, e.g., font-weight: bold, color: red"`
+	URL       string   `desc:"if non-empty, the run is a link to this URL, which is styled and recorded in the Links of the text, as an <a> tag is in SetHTML"`
+	LinkProps ki.Props `desc:"additional properties of the link, if URL is set, passed on in its TextLink Props, as the attributes of an <a> tag are"`
+	ParaStart bool     `desc:"the run starts a new paragraph, in a new span, as a <p> tag does in SetHTML"`
+}
+
+// SetRuns sets the text from given styled runs, each in the given base font
+// style with the Props of the run applied (see StyledRun).  Like SetHTML,
+// it sets the per-character font information and breaks lines into spans,
+// but does not lay out the text.
+func (tr *Text) SetRuns(runs []StyledRun, font *gist.Font, ctxt *units.Context) {
+	sz := 0
+	for i := range runs {
+		sz += len(runs[i].Text)
+	}
+	curSp := tr.ResetSpans(ints.MinInt(sz, 1020))
+	OpenFont(font, ctxt)
+
+	nextIsParaStart := false
+	for i := range runs {
+		run := &runs[i]
+		fs := font
+		if run.URL != "" || len(run.Props) > 0 {
+			fs = &gist.Font{}
+			*fs = *font
+			if run.URL != "" {
+				fs.Color.SetColor(gist.ThePrefs.PrefColor("link"))
+				fs.SetDeco(gist.DecoUnderline)
+			}
+			if len(run.Props) > 0 {
+				fs.SetStyleProps(font, run.Props, nil)
+			}
+			OpenFont(fs, ctxt)
+		}
+		if run.ParaStart {
+			if len(curSp.Text) > 0 {
+				tr.Spans = append(tr.Spans, Span{})
+				curSp = &(tr.Spans[len(tr.Spans)-1])
+			}
+			nextIsParaStart = true
+		}
+		var tl *TextLink
+		if run.URL != "" {
+			tl = &TextLink{Label: run.Text, URL: run.URL, StartSpan: len(tr.Spans) - 1, StartIdx: len(curSp.Text)}
+			tl.Props = make(ki.Props, len(run.LinkProps)+1)
+			for k, v := range run.LinkProps {
+				tl.Props[k] = v
+			}
+			tl.Props["href"] = run.URL
+		}
+		for li, ln := range strings.Split(run.Text, "\n") {
+			if li > 0 {
+				tr.Spans = append(tr.Spans, Span{})
+				curSp = &(tr.Spans[len(tr.Spans)-1])
+			}
+			if ln == "" {
+				continue
+			}
+			atStart := len(curSp.Text) == 0
+			nr := len(curSp.Render)
+			curSp.AppendString(ln, fs.Face.Face, fs.Color, fs.BgColor.ColorOrNil(), fs.Deco, font, ctxt)
+			curSp.SetEffects(nr, fs)
+			if nextIsParaStart && atStart {
+				curSp.SetNewPara()
+			}
+			nextIsParaStart = false
+		}
+		if tl != nil {
+			tl.EndSpan = len(tr.Spans) - 1
+			tl.EndIdx = len(curSp.Text)
+			tr.Links = append(tr.Links, *tl)
+		}
+	}
+}
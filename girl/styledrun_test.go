@@ -0,0 +1,68 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"testing"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
+)
+
+func TestSetRuns(t *testing.T) {
+	prefs := &TestPrefs{}
+	prefs.Defaults()
+	gist.ThePrefs = prefs
+	FontLibrary.InitFontPaths("/usr/share/fonts/truetype")
+
+	pc := &Paint{}
+	pc.Defaults()
+	pc.SetUnitContextExt(image.Point{320, 240})
+	tsty := &gist.Text{}
+	tsty.Defaults()
+	fsty := &gist.Font{}
+	fsty.Defaults()
+
+	want := &Text{}
+	want.SetHTML(`This is <b>bold</b> and <a href="https://goki.dev">a link</a><br>to <p>next para</p>`, fsty, tsty, &pc.UnContext, nil)
+
+	tr := &Text{}
+	tr.SetRuns([]StyledRun{
+		{Text: "This is "},
+		{Text: "bold", Props: ki.Props{"font-weight": "bold"}},
+		{Text: " and "},
+		{Text: "a link", URL: "https://goki.dev"},
+		{Text: "\nto "},
+		{Text: "next para", ParaStart: true},
+	}, fsty, &pc.UnContext)
+
+	if len(tr.Spans) != 3 {
+		t.Fatalf("spans: got %d, want 3", len(tr.Spans))
+	}
+	for i := range tr.Spans {
+		if got, exp := string(tr.Spans[i].Text), string(want.Spans[i].Text); got != exp {
+			t.Errorf("span %d: got %q, want %q as from SetHTML", i, got, exp)
+		}
+	}
+	sr, ws := &tr.Spans[0], &want.Spans[0]
+	if sr.Render[8].Face != ws.Render[8].Face || sr.Render[8].Face == sr.Render[0].Face {
+		t.Errorf("bold run: got face %v, want %v as from SetHTML", sr.Render[8].Face, ws.Render[8].Face)
+	}
+	if sr.Render[17].Color != ws.Render[17].Color || sr.Render[17].Deco != ws.Render[17].Deco {
+		t.Errorf("link run: got %v %v, want %v %v as from SetHTML", sr.Render[17].Color, sr.Render[17].Deco, ws.Render[17].Color, ws.Render[17].Deco)
+	}
+	if !tr.Spans[2].IsNewPara() || tr.Spans[1].IsNewPara() {
+		t.Errorf("new paragraph: got %v %v, want false true", tr.Spans[1].IsNewPara(), tr.Spans[2].IsNewPara())
+	}
+	if len(tr.Links) != 1 {
+		t.Fatalf("links: got %d, want 1", len(tr.Links))
+	}
+	tl, wl := tr.Links[0], want.Links[0]
+	if tl.URL != wl.URL || tl.Label != wl.Label || tl.StartSpan != wl.StartSpan || tl.StartIdx != wl.StartIdx ||
+		tl.EndSpan != wl.EndSpan || tl.EndIdx != wl.EndIdx || tl.Props["href"] != wl.URL {
+		t.Errorf("link: got %+v, want %+v as from SetHTML", tl, wl)
+	}
+}
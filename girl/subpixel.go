@@ -0,0 +1,208 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/goki/ki/kit"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// SubpixelModes are the options for subpixel (LCD) antialiasing of text,
+// which specify the physical order of the color elements within each pixel.
+type SubpixelModes int32
+
+const (
+	// SubpixelNone uses standard grayscale antialiasing
+	SubpixelNone SubpixelModes = iota
+
+	// SubpixelRGB is for standard LCD displays with red, green, blue
+	// elements ordered left-to-right within each pixel
+	SubpixelRGB
+
+	// SubpixelBGR is for LCD displays with blue, green, red
+	// elements ordered left-to-right within each pixel
+	SubpixelBGR
+
+	SubpixelModesN
+)
+
+//go:generate stringer -type=SubpixelModes
+
+var KiT_SubpixelModes = kit.Enums.AddEnumAltLower(SubpixelModesN, kit.NotBitFlag, nil, "Subpixel")
+
+func (ev SubpixelModes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *SubpixelModes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// TextSubpixel is the subpixel antialiasing mode used for rendering text --
+// set from gi.Prefs.  Subpixel rendering is automatically disabled for
+// rotated or scaled runes, for non-opaque text colors, and for any
+// destination pixels that are not fully opaque, as the result depends on
+// knowing the color that the text is drawn over.
+var TextSubpixel = SubpixelNone
+
+// TextGamma is the gamma value used for blending subpixel-antialiased text
+// with the background -- blending is done in linear space which avoids
+// color fringes and gives consistent stroke weight for light and dark text.
+// Call SetTextGamma to update.
+var TextGamma = float32(1.8)
+
+// subpixelGamma holds the lookup tables for converting to and from linear
+// space for the current TextGamma
+var subpixelGamma struct {
+	sync.Mutex
+	gamma   float32
+	toLin   [256]float32
+	fromLin [4096]uint8
+}
+
+// SetTextGamma sets the TextGamma value used for subpixel text blending
+func SetTextGamma(gamma float32) {
+	if gamma <= 0 {
+		gamma = 1
+	}
+	TextGamma = gamma
+	subpixelGamma.Lock()
+	subpixelGamma.gamma = 0 // force update
+	subpixelGamma.Unlock()
+}
+
+// updtGammaTables updates the gamma lookup tables if the gamma has changed --
+// subpixelGamma must be locked
+func updtGammaTables() {
+	sg := &subpixelGamma
+	if sg.gamma == TextGamma {
+		return
+	}
+	sg.gamma = TextGamma
+	g := float64(TextGamma)
+	for i := range sg.toLin {
+		sg.toLin[i] = float32(math.Pow(float64(i)/255, g))
+	}
+	ig := 1 / g
+	n := len(sg.fromLin) - 1
+	for i := range sg.fromLin {
+		sg.fromLin[i] = uint8(math.Round(255 * math.Pow(float64(i)/float64(n), ig)))
+	}
+}
+
+// SubpixelOK returns true if subpixel rendering can be used for text
+// in the given color with given rotation and x scaling.
+func SubpixelOK(clr color.Color, rot, scalex float32) bool {
+	if TextSubpixel == SubpixelNone || rot != 0 || (scalex != 0 && scalex != 1) {
+		return false
+	}
+	_, _, _, a := clr.RGBA()
+	return a == 0xffff
+}
+
+// subpixelMask is one cached glyph coverage mask, for one color channel,
+// which starts at 0,0 in the mask
+type subpixelMask struct {
+	rect image.Rectangle
+	mask *image.Alpha
+}
+
+// at returns the coverage at given absolute image coordinates
+func (sm *subpixelMask) at(x, y int) uint8 {
+	if !(image.Point{x, y}).In(sm.rect) {
+		return 0
+	}
+	return sm.mask.Pix[(y-sm.rect.Min.Y)*sm.mask.Stride+(x-sm.rect.Min.X)]
+}
+
+// DrawGlyphSubpixel draws given rune using the face at given dot position
+// into dst, restricted to bounds, using subpixel antialiasing according to
+// the current TextSubpixel mode.  The glyph is rendered at 1/3 pixel
+// offsets, giving the coverage of a one pixel wide window centered on each
+// subpixel (color element), i.e., a 3-tap box filter of the coverage at 3x
+// horizontal resolution, which is then filtered again with [1 2 1] / 4
+// over the neighboring subpixels: a 5-tap FIR filter with weights
+// [1 3 4 3 1] / 12, close to the default LCD filter of FreeType, which
+// reduces color fringing.  Destination pixels that are not fully opaque
+// fall back on grayscale coverage.  Returns false if the glyph is not
+// available in the face.
+func DrawGlyphSubpixel(dst *image.RGBA, bounds image.Rectangle, face font.Face, dot fixed.Point26_6, r rune, clr color.Color) bool {
+	third := fixed.Int26_6(64 / 3)
+	offs := [3]fixed.Int26_6{third, 0, -third} // R, G, B for RGB order
+	phys := [3]int{0, 1, 2}                    // color channel of the subpixels, left to right
+	if TextSubpixel == SubpixelBGR {
+		offs[0], offs[2] = offs[2], offs[0]
+		phys = [3]int{2, 1, 0}
+	}
+	var sms [3]subpixelMask
+	var ur image.Rectangle
+	for c := 0; c < 3; c++ {
+		d := dot
+		d.X += offs[c]
+		dr, mask, ok := CachedGlyph(face, d, r)
+		if !ok {
+			return false
+		}
+		sms[c] = subpixelMask{rect: dr, mask: mask}
+		ur = ur.Union(dr)
+	}
+	ur.Min.X-- // filtering spreads into the neighboring pixels
+	ur.Max.X++
+	ur = ur.Intersect(bounds).Intersect(dst.Rect)
+	if ur.Empty() {
+		return true
+	}
+	// box returns the box filtered coverage of subpixel p (0-2, left to
+	// right, or beyond, in the neighboring pixels) of pixel x, y
+	box := func(x, y, p int) int {
+		if p < 0 {
+			x--
+			p += 3
+		} else if p > 2 {
+			x++
+			p -= 3
+		}
+		return int(sms[phys[p]].at(x, y))
+	}
+
+	cr, cg, cb, _ := clr.RGBA()
+	sg := &subpixelGamma
+	sg.Lock()
+	defer sg.Unlock()
+	updtGammaTables()
+	nl := float32(len(sg.fromLin) - 1)
+	slin := [3]float32{sg.toLin[cr>>8], sg.toLin[cg>>8], sg.toLin[cb>>8]}
+
+	for y := ur.Min.Y; y < ur.Max.Y; y++ {
+		for x := ur.Min.X; x < ur.Max.X; x++ {
+			var cov [3]uint8
+			for p := 0; p < 3; p++ {
+				cov[phys[p]] = uint8((box(x, y, p-1) + 2*box(x, y, p) + box(x, y, p+1) + 2) / 4)
+			}
+			if cov[0] == 0 && cov[1] == 0 && cov[2] == 0 {
+				continue
+			}
+			pi := dst.PixOffset(x, y)
+			px := dst.Pix[pi : pi+4 : pi+4]
+			if px[3] != 0xff { // unknown background: grayscale
+				ga := uint32(cov[1]) * 0x101
+				ia := 0xffff - ga
+				px[0] = uint8((uint32(px[0])*0x101*ia/0xffff + cr*ga/0xffff) >> 8)
+				px[1] = uint8((uint32(px[1])*0x101*ia/0xffff + cg*ga/0xffff) >> 8)
+				px[2] = uint8((uint32(px[2])*0x101*ia/0xffff + cb*ga/0xffff) >> 8)
+				px[3] = uint8((uint32(px[3])*0x101*ia/0xffff + ga) >> 8)
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				a := float32(cov[c]) / 255
+				dl := sg.toLin[px[c]]
+				ol := dl + (slin[c]-dl)*a
+				px[c] = sg.fromLin[int(ol*nl+0.5)]
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package girl
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// drawSubpixel draws given rune in black on white, with given subpixel
+// mode, and returns the image
+func drawSubpixel(t *testing.T, mode SubpixelModes, r rune) *image.RGBA {
+	defer func(sm SubpixelModes) { TextSubpixel = sm }(TextSubpixel)
+	TextSubpixel = mode
+	ff, err := OpenGoFont("Go", "gofont/goregular", 24, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+	if !DrawGlyphSubpixel(img, img.Rect, ff.Face, fixed.P(8, 24), r, color.Black) {
+		t.Fatalf("no glyph for %q", r)
+	}
+	return img
+}
+
+func TestDrawGlyphSubpixel(t *testing.T) {
+	rgb := drawSubpixel(t, SubpixelRGB, 'l')
+	bgr := drawSubpixel(t, SubpixelBGR, 'l')
+	differ := false
+	for i := 0; i < len(rgb.Pix); i += 4 {
+		pr, pb := rgb.Pix[i:i+4], bgr.Pix[i:i+4]
+		if pr[0] != pr[2] {
+			differ = true
+		}
+		if pr[0] != pb[2] || pr[1] != pb[1] || pr[2] != pb[0] {
+			x, y := (i/4)%rgb.Stride, i/rgb.Stride
+			t.Fatalf("pixel %d,%d: RGB %v and BGR %v are not swapped", x, y, pr[:3], pb[:3])
+		}
+	}
+	if !differ {
+		t.Errorf("the red and blue coverage is the same everywhere")
+	}
+	// the left edge of a dark stem is covered more in its right subpixels:
+	// blue for RGB, so its red is lighter
+	for x := 0; x < rgb.Rect.Dx(); x++ {
+		px := rgb.Pix[rgb.PixOffset(x, 20):]
+		if px[0] == 0xff && px[1] == 0xff && px[2] == 0xff {
+			continue
+		}
+		if px[0] <= px[2] {
+			t.Errorf("left edge of l at %d: got %v, want red lighter than blue", x, px[:3])
+		}
+		break
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/goki/mat32"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/f64"
 	"golang.org/x/net/html/charset"
 )
@@ -60,13 +61,30 @@ func (tr *Text) InsertSpan(at int, ns *Span) {
 	tr.Spans[at] = *ns
 }
 
+// ResetSpans resets the text to a single empty span, with room for given
+// number of runes, for setting new text (e.g., SetHTML) -- the allocated
+// spans and render info are reused (see Span.Reset), to avoid reallocating
+// them each time the text of a label or other element is set.
+func (tr *Text) ResetSpans(capsz int) *Span {
+	if cap(tr.Spans) > 0 {
+		tr.Spans = tr.Spans[:1]
+	} else {
+		tr.Spans = make([]Span, 1)
+	}
+	tr.Links = nil
+	sr := &(tr.Spans[0])
+	sr.Reset(capsz)
+	return sr
+}
+
 // Render does text rendering into given image, within given bounds, at given
 // absolute position offset (specifying position of text baseline) -- any
 // applicable transforms (aside from the char-specific rotation in Render)
 // must be applied in advance in computing the relative positions of the
-// runes, and the overall font size, etc.  todo: does not currently support
-// stroking, only filling of text -- probably need to grab path from font and
-// use paint rendering for stroking
+// runes, and the overall font size, etc.  The glyphs are filled, over
+// their shadows if they have a text-shadow (see Span.RenderShadows), and
+// then stroked if they have a text-stroke (see Span.RenderStroke) -- see
+// RenderStroke for stroking them in the style of a Paint, e.g., for SVG.
 func (tr *Text) Render(rs *State, pos mat32.Vec2) {
 	// pr := prof.Start("RenderText")
 	// defer pr.End()
@@ -78,9 +96,8 @@ func (tr *Text) Render(rs *State, pos mat32.Vec2) {
 	defer rs.PopXForm()
 	rs.XForm = mat32.Identity2D()
 
-	TextFontRenderMu.Lock()
-	defer TextFontRenderMu.Unlock()
-
+	// note: glyphs are rendered from the glyph cache, so the TextFontRenderMu
+	// is only needed for those not yet in the cache (see CachedGlyph)
 	for _, sr := range tr.Spans {
 		if sr.IsValid() != nil {
 			continue
@@ -88,17 +105,15 @@ func (tr *Text) Render(rs *State, pos mat32.Vec2) {
 		curFace := sr.Render[0].Face
 		curColor := sr.Render[0].Color
 		tpos := pos.Add(sr.RelPos)
-
-		d := &font.Drawer{
-			Dst:  rs.Image,
-			Src:  image.NewUniform(curColor),
-			Face: curFace,
-		}
+		src := image.NewUniform(curColor)
 
 		// todo: cache flags if these are actually needed
 		if bitflag.Has32(int32(sr.HasDeco), int(gist.DecoBgColor)) {
 			sr.RenderBg(rs, tpos)
 		}
+		if bitflag.Has32(int32(sr.HasDeco), int(gist.DecoShadow)) {
+			sr.RenderShadows(rs, tpos)
+		}
 		if bitflag.HasAny32(int32(sr.HasDeco), int(gist.DecoUnderline), int(gist.DecoDottedUnderline)) {
 			sr.RenderUnderline(rs, tpos)
 		}
@@ -110,13 +125,16 @@ func (tr *Text) Render(rs *State, pos mat32.Vec2) {
 			rr := &(sr.Render[i])
 			if rr.Color != nil {
 				curColor = rr.Color
-				d.Src = image.NewUniform(curColor)
+				src = image.NewUniform(curColor)
 			}
 			curFace = rr.CurFace(curFace)
 			if !unicode.IsPrint(r) {
 				continue
 			}
-			dsc32 := mat32.FromFixed(curFace.Metrics().Descent)
+			if rr.Cluster == nil && sr.GlyphRune(i, curFace) == NoGlyph {
+				continue
+			}
+			dsc32 := mat32.FromFixed(FaceMetrics(curFace).Descent)
 			rp := tpos.Add(rr.RelPos)
 			scx := float32(1)
 			if rr.ScaleX != 0 {
@@ -129,38 +147,10 @@ func (tr *Text) Render(rs *State, pos mat32.Vec2) {
 				int(mat32.Ceil(ur.X)) < rs.Bounds.Min.X || int(mat32.Ceil(ll.Y)) < rs.Bounds.Min.Y {
 				continue
 			}
-			d.Face = curFace
-			d.Dot = rp.Fixed()
-			dr, mask, maskp, _, ok := d.Face.Glyph(d.Dot, r)
-			if !ok {
-				// fmt.Printf("not ok rendering rune: %v\n", string(r))
-				continue
-			}
-			if rr.RotRad == 0 && (rr.ScaleX == 0 || rr.ScaleX == 1) {
-				idr := dr.Intersect(rs.Bounds)
-				soff := image.ZP
-				if dr.Min.X < rs.Bounds.Min.X {
-					soff.X = rs.Bounds.Min.X - dr.Min.X
-					maskp.X += rs.Bounds.Min.X - dr.Min.X
-				}
-				if dr.Min.Y < rs.Bounds.Min.Y {
-					soff.Y = rs.Bounds.Min.Y - dr.Min.Y
-					maskp.Y += rs.Bounds.Min.Y - dr.Min.Y
-				}
-				draw.DrawMask(d.Dst, idr, d.Src, soff, mask, maskp, draw.Over)
-			} else {
-				srect := dr.Sub(dr.Min)
-				dbase := mat32.Vec2{rp.X - float32(dr.Min.X), rp.Y - float32(dr.Min.Y)}
-
-				transformer := draw.BiLinear
-				fx, fy := float32(dr.Min.X), float32(dr.Min.Y)
-				m := mat32.Translate2D(fx+dbase.X, fy+dbase.Y).Scale(scx, 1).Rotate(rr.RotRad).Translate(-dbase.X, -dbase.Y)
-				s2d := f64.Aff3{float64(m.XX), float64(m.XY), float64(m.X0), float64(m.YX), float64(m.YY), float64(m.Y0)}
-				transformer.Transform(d.Dst, s2d, d.Src, srect, draw.Over, &draw.Options{
-					SrcMask:  mask,
-					SrcMaskP: maskp,
-				})
-			}
+			sr.DrawRuneGlyph(rs.Image, rs.Bounds, i, curFace, rp, src, true)
+		}
+		if bitflag.Has32(int32(sr.HasDeco), int(gist.DecoStroke)) {
+			sr.RenderStroke(rs, tpos)
 		}
 		if bitflag.Has32(int32(sr.HasDeco), int(gist.DecoLineThrough)) {
 			sr.RenderLine(rs, tpos, gist.DecoLineThrough, 0.25)
@@ -168,6 +158,96 @@ func (tr *Text) Render(rs *State, pos mat32.Vec2) {
 	}
 }
 
+// DrawGlyph draws the glyph of given rune using the face into dst,
+// restricted to bounds, in the color of src, with its origin (the start of
+// its baseline) at rp, and rotated and scaled in X as given by the RotRad
+// and ScaleX of Rune.  Subpixel rendering is used if subpix is true and
+// SubpixelOK for the color.  Returns false if the glyph is not available
+// in the face.
+func DrawGlyph(dst *image.RGBA, bounds image.Rectangle, face font.Face, r rune, rp mat32.Vec2, src *image.Uniform, rot, scalex float32, subpix bool) bool {
+	dot := rp.Fixed()
+	if subpix && SubpixelOK(src.C, rot, scalex) {
+		return DrawGlyphSubpixel(dst, bounds, face, dot, r, src.C)
+	}
+	dr, mask, ok := CachedGlyph(face, dot, r)
+	maskp := image.ZP
+	if !ok {
+		// fmt.Printf("not ok rendering rune: %v\n", string(r))
+		return false
+	}
+	if rot == 0 && (scalex == 0 || scalex == 1) {
+		idr := dr.Intersect(bounds)
+		soff := image.ZP
+		if dr.Min.X < bounds.Min.X {
+			soff.X = bounds.Min.X - dr.Min.X
+			maskp.X += bounds.Min.X - dr.Min.X
+		}
+		if dr.Min.Y < bounds.Min.Y {
+			soff.Y = bounds.Min.Y - dr.Min.Y
+			maskp.Y += bounds.Min.Y - dr.Min.Y
+		}
+		draw.DrawMask(dst, idr, src, soff, mask, maskp, draw.Over)
+		return true
+	}
+	scx := float32(1)
+	if scalex != 0 {
+		scx = scalex
+	}
+	srect := dr.Sub(dr.Min)
+	dbase := mat32.Vec2{rp.X - float32(dr.Min.X), rp.Y - float32(dr.Min.Y)}
+
+	transformer := draw.BiLinear
+	fx, fy := float32(dr.Min.X), float32(dr.Min.Y)
+	m := mat32.Translate2D(fx+dbase.X, fy+dbase.Y).Scale(scx, 1).Rotate(rot).Translate(-dbase.X, -dbase.Y)
+	s2d := f64.Aff3{float64(m.XX), float64(m.XY), float64(m.X0), float64(m.YX), float64(m.YY), float64(m.Y0)}
+	transformer.Transform(dst, s2d, src, srect, draw.Over, &draw.Options{
+		SrcMask:  mask,
+		SrcMaskP: maskp,
+	})
+	return true
+}
+
+// RenderStroke strokes the outlines of the glyphs of the text, at the
+// same positions as Render, in the stroke style of given paint (color,
+// width, dashes, line joins etc) -- e.g., for SVG text with a stroke, after
+// Render has filled it.  The runes must already be positioned in the
+// current transform, as for Render, and the stroke width is scaled by it.
+// The glyphs of faces without outlines (see GlyphOutline) are not stroked.
+func (tr *Text) RenderStroke(rs *State, pc *Paint, pos mat32.Vec2) {
+	if !pc.StrokeStyle.On || pc.StrokeStyle.Color.IsNil() {
+		return
+	}
+	lw := pc.StrokeWidth(rs)
+	if lw <= 0 {
+		return
+	}
+	wd := pc.StrokeStyle.Width
+	defer func() { pc.StrokeStyle.Width = wd }()
+	pc.StrokeStyle.Width.Dots = lw
+
+	rs.PushXForm(mat32.Identity2D())
+	defer rs.PopXForm()
+	rs.XForm = mat32.Identity2D()
+
+	var buf sfnt.Buffer
+	for _, sr := range tr.Spans {
+		if sr.IsValid() != nil {
+			continue
+		}
+		curFace := sr.Render[0].Face
+		tpos := pos.Add(sr.RelPos)
+		for i, r := range sr.Text {
+			rr := &(sr.Render[i])
+			curFace = rr.CurFace(curFace)
+			if !unicode.IsPrint(r) {
+				continue
+			}
+			sr.RuneGlyphOutline(rs, pc, i, curFace, tpos.Add(rr.RelPos), &buf)
+		}
+	}
+	pc.Stroke(rs)
+}
+
 // RenderTopPos renders at given top position -- uses first font info to
 // compute baseline offset and calls overall Render -- convenience for simple
 // widget rendering without layouts
@@ -199,7 +279,17 @@ func (tr *Text) SetString(str string, fontSty *gist.Font, ctxt *units.Context, t
 	tr.Links = nil
 	sr := &(tr.Spans[0])
 	sr.SetString(str, fontSty, ctxt, noBG, rot, scalex)
-	sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize)
+	if txtSty.IsVertical() { // one column, centered on the position
+		sr.SetRunePosVert(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize, txtSty.OrientationVert != 0)
+		for i := range sr.Render {
+			sr.Render[i].RotRad += rot
+		}
+		tr.Dir = txtSty.WritingMode
+		tr.Size = mat32.Vec2{mat32.FromFixed(fontSty.Face.Face.Metrics().Height), sr.LastPos.Y}
+		return
+	}
+	sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize, txtSty.TabStops)
+	tr.SetRunePosBidi(txtSty)
 	ssz := sr.SizeHV()
 	vht := fontSty.Face.Face.Metrics().Height
 	tr.Size = mat32.Vec2{ssz.X, mat32.FromFixed(vht)}
@@ -239,7 +329,8 @@ func (tr *Text) SetRunes(str []rune, fontSty *gist.Font, ctxt *units.Context, tx
 	tr.Links = nil
 	sr := &(tr.Spans[0])
 	sr.SetRunes(str, fontSty, ctxt, noBG, rot, scalex)
-	sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize)
+	sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize, txtSty.TabStops)
+	tr.SetRunePosBidi(txtSty)
 	ssz := sr.SizeHV()
 	vht := fontSty.Face.Face.Metrics().Height
 	tr.Size = mat32.Vec2{ssz.X, mat32.FromFixed(vht)}
@@ -294,7 +385,7 @@ func SetHTMLSimpleTag(tag string, fs *gist.Font, ctxt *units.Context, cssAgg ki.
 		did = true
 	case "xx-small", "x-small", "smallf", "medium", "large", "x-large", "xx-large":
 		fs.Size = units.NewPt(gist.FontSizePoints[tag])
-		fs.Size.ToDots(ctxt)
+		fs.ToDots(ctxt)
 		OpenFont(fs, ctxt)
 		did = true
 	case "mark":
@@ -339,6 +430,16 @@ func (tr *Text) SetHTMLBytes(str []byte, font *gist.Font, txtSty *gist.Text, ctx
 	}
 }
 
+// htmlAutoClose are the HTML elements that are closed automatically by
+// the XML decoder: the standard empty elements, and <wbr>
+var htmlAutoClose = append(append([]string(nil), xml.HTMLAutoClose...), "wbr")
+
+// isHTMLSpace returns true for the ASCII white space that is collapsed in
+// HTML -- unlike a no-break space (&nbsp;), which is kept as is
+func isHTMLSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
 // This is the No-Pre parser that uses the golang XML decoder system, which
 // strips all whitespace and is thus unsuitable for any Pre case
 func (tr *Text) SetHTMLNoPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt *units.Context, cssAgg ki.Props) {
@@ -347,23 +448,26 @@ func (tr *Text) SetHTMLNoPre(str []byte, font *gist.Font, txtSty *gist.Text, ctx
 	if sz == 0 {
 		return
 	}
-	tr.Spans = make([]Span, 1)
-	tr.Links = nil
-	curSp := &(tr.Spans[0])
-	initsz := ints.MinInt(sz, 1020)
-	curSp.Init(initsz)
+	curSp := tr.ResetSpans(ints.MinInt(sz, 1020))
 
-	spcstr := bytes.Join(bytes.Fields(str), []byte(" "))
+	spcstr := bytes.Join(bytes.FieldsFunc(str, isHTMLSpace), []byte(" "))
+
+	OpenFont(font, ctxt)
+
+	if bytes.IndexAny(str, "<&") < 0 { // plain text, with no tags or entities: no need to decode
+		nr := len(curSp.Render)
+		curSp.AppendString(string(spcstr), font.Face.Face, font.Color, font.BgColor.ColorOrNil(), font.Deco, font, ctxt)
+		curSp.SetEffects(nr, font)
+		return
+	}
 
 	reader := bytes.NewReader(spcstr)
 	decoder := xml.NewDecoder(reader)
 	decoder.Strict = false
-	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.AutoClose = htmlAutoClose
 	decoder.Entity = xml.HTMLEntity
 	decoder.CharsetReader = charset.NewReaderLabel
 
-	OpenFont(font, ctxt)
-
 	// set when a </p> is encountered
 	nextIsParaStart := false
 	curLinkIdx := -1 // if currently processing an <a> link element
@@ -406,7 +510,9 @@ func (tr *Text) SetHTMLNoPre(str []byte, font *gist.Font, txtSty *gist.Text, ctx
 				case "q":
 					curf := fstack[len(fstack)-1]
 					atStart := len(curSp.Text) == 0
+					nr := len(curSp.Render)
 					curSp.AppendRune('“', curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco)
+					curSp.SetEffects(nr, curf)
 					if nextIsParaStart && atStart {
 						curSp.SetNewPara()
 					}
@@ -423,6 +529,9 @@ func (tr *Text) SetHTMLNoPre(str []byte, font *gist.Font, txtSty *gist.Text, ctx
 					}
 					nextIsParaStart = true
 				case "br":
+				case "wbr":
+					curf := fstack[len(fstack)-1]
+					curSp.AppendRune('\u200b', curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco) // zero width space: break opportunity
 				default:
 					// log.Printf("%v tag not recognized: %v for string\n%v\n", errstr, nm, string(str))
 				}
@@ -463,7 +572,9 @@ func (tr *Text) SetHTMLNoPre(str []byte, font *gist.Font, txtSty *gist.Text, ctx
 				curSp = &(tr.Spans[len(tr.Spans)-1])
 			case "q":
 				curf := fstack[len(fstack)-1]
+				nr := len(curSp.Render)
 				curSp.AppendRune('”', curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco)
+				curSp.SetEffects(nr, curf)
 			case "a":
 				if curLinkIdx >= 0 {
 					tl := &tr.Links[curLinkIdx]
@@ -480,11 +591,11 @@ func (tr *Text) SetHTMLNoPre(str []byte, font *gist.Font, txtSty *gist.Text, ctx
 			atStart := len(curSp.Text) == 0
 			sstr := html.UnescapeString(string(se))
 			if nextIsParaStart && atStart {
-				sstr = strings.TrimLeftFunc(sstr, func(r rune) bool {
-					return unicode.IsSpace(r)
-				})
+				sstr = strings.TrimLeftFunc(sstr, IsBreakSpace)
 			}
+			nr := len(curSp.Render)
 			curSp.AppendString(sstr, curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco, font, ctxt)
+			curSp.SetEffects(nr, curf)
 			if nextIsParaStart && atStart {
 				curSp.SetNewPara()
 			}
@@ -510,14 +621,10 @@ func (tr *Text) SetHTMLPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt
 	// errstr := "gi.Text SetHTMLPre"
 
 	sz := len(str)
-	tr.Spans = make([]Span, 1)
-	tr.Links = nil
+	curSp := tr.ResetSpans(ints.MinInt(sz, 1020))
 	if sz == 0 {
 		return
 	}
-	curSp := &(tr.Spans[0])
-	initsz := ints.MinInt(sz, 1020)
-	curSp.Init(initsz)
 
 	OpenFont(font, ctxt)
 
@@ -543,7 +650,9 @@ func (tr *Text) SetHTMLPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt
 				bidx += eidx + 2
 			} else { // get past <
 				curf := fstack[len(fstack)-1]
+				nr := len(curSp.Render)
 				curSp.AppendString(string(str[bidx:bidx+1]), curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco, font, ctxt)
+				curSp.SetEffects(nr, curf)
 				bidx++
 			}
 		}
@@ -567,7 +676,9 @@ func (tr *Text) SetHTMLPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt
 				// 	curSp = &(tr.Spans[len(tr.Spans)-1])
 				case "q":
 					curf := fstack[len(fstack)-1]
+					nr := len(curSp.Render)
 					curSp.AppendRune('”', curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco)
+					curSp.SetEffects(nr, curf)
 				case "a":
 					if curLinkIdx >= 0 {
 						tl := &tr.Links[curLinkIdx]
@@ -622,7 +733,9 @@ func (tr *Text) SetHTMLPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt
 					case "q":
 						curf := fstack[len(fstack)-1]
 						atStart := len(curSp.Text) == 0
+						nr := len(curSp.Render)
 						curSp.AppendRune('“', curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco)
+						curSp.SetEffects(nr, curf)
 						if nextIsParaStart && atStart {
 							curSp.SetNewPara()
 						}
@@ -641,6 +754,9 @@ func (tr *Text) SetHTMLPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt
 					// case "br":
 					case "pre":
 						continue // ignore
+					case "wbr": // empty element: nothing to push
+						curSp.AppendRune('\u200b', curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco) // zero width space: break opportunity
+						continue
 					default:
 						// log.Printf("%v tag not recognized: %v for string\n%v\n", errstr, stag, string(str))
 						// just ignore it and format as is, for pre case!
@@ -698,7 +814,9 @@ func (tr *Text) SetHTMLPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt
 					}
 				case '\n': // todo absorb other line endings
 					unestr := html.UnescapeString(string(tmpbuf))
+					nr := len(curSp.Render)
 					curSp.AppendString(unestr, curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco, font, ctxt)
+					curSp.SetEffects(nr, curf)
 					tmpbuf = tmpbuf[0:0]
 					tr.Spans = append(tr.Spans, Span{})
 					curSp = &(tr.Spans[len(tr.Spans)-1])
@@ -711,7 +829,9 @@ func (tr *Text) SetHTMLPre(str []byte, font *gist.Font, txtSty *gist.Text, ctxt
 			if !didNl {
 				unestr := html.UnescapeString(string(tmpbuf))
 				// fmt.Printf("%v added: %v\n", bidx, unestr)
+				nr := len(curSp.Render)
 				curSp.AppendString(unestr, curf.Face.Face, curf.Color, curf.BgColor.ColorOrNil(), curf.Deco, font, ctxt)
+				curSp.SetEffects(nr, curf)
 				if curLinkIdx >= 0 {
 					tl := &tr.Links[curLinkIdx]
 					tl.Label = unestr
@@ -775,12 +895,12 @@ func (tx *Text) RuneRelPos(idx int) (pos mat32.Vec2, si, ri int, ok bool) {
 	si, ri, ok = tx.RuneSpanPos(idx)
 	if ok {
 		sr := &tx.Spans[si]
-		return sr.RelPos.Add(sr.Render[ri].RelPos), si, ri, true
+		return sr.RuneRelPos(ri), si, ri, true
 	}
 	nsp := len(tx.Spans)
 	if nsp > 0 {
 		sr := &tx.Spans[nsp-1]
-		return sr.LastPos, nsp - 1, len(sr.Render), false
+		return sr.EndPos(), nsp - 1, len(sr.Render), false
 	}
 	return mat32.Vec2Zero, -1, -1, false
 }
@@ -795,21 +915,117 @@ func (tx *Text) RuneEndPos(idx int) (pos mat32.Vec2, si, ri int, ok bool) {
 	si, ri, ok = tx.RuneSpanPos(idx)
 	if ok {
 		sr := &tx.Spans[si]
-		spos := sr.RelPos.Add(sr.Render[ri].RelPos)
-		spos.X += sr.Render[ri].Size.X
-		return spos, si, ri, true
+		return sr.RuneEndPos(ri), si, ri, true
 	}
 	nsp := len(tx.Spans)
 	if nsp > 0 {
 		sr := &tx.Spans[nsp-1]
-		return sr.LastPos, nsp - 1, len(sr.Render), false
+		return sr.EndPos(), nsp - 1, len(sr.Render), false
 	}
 	return mat32.Vec2Zero, -1, -1, false
 }
 
+// PosToRune returns the absolute rune index of the rune nearest to given
+// position, which is relative to the same starting position passed to
+// Render (i.e., it includes Span and rune RelPos), and whether the position
+// is after the middle of the rune along its baseline, in which case a
+// cursor would go after it (at idx+1).  The extent of each rune is computed
+// as in Render, accounting for its Size, RotRad and ScaleX, so it works for
+// rotated and scaled text, and the distance to each rune is that to its
+// box, which is 0 for any rune containing the position -- the first such
+// rune is returned.  Returns false if there are no runes.
+func (tr *Text) PosToRune(pos mat32.Vec2) (idx int, after bool, ok bool) {
+	si, ri, after, ok := tr.PosToSpanRune(pos)
+	if !ok {
+		return 0, false, false
+	}
+	idx, _ = tr.SpanPosToRuneIdx(si, ri)
+	return idx, after, true
+}
+
+// PosToSpanRune returns the span index and rune index within that span of
+// the rune nearest to given position -- see PosToRune for details.
+func (tr *Text) PosToSpanRune(pos mat32.Vec2) (si, ri int, after bool, ok bool) {
+	minDist := float32(-1)
+	for i := range tr.Spans {
+		sr := &tr.Spans[i]
+		if sr.IsValid() != nil {
+			continue
+		}
+		var curFace font.Face
+		for j := range sr.Render {
+			rr := &sr.Render[j]
+			curFace = rr.CurFace(curFace)
+			dist, aft := rr.PosDist(sr.RelPos, curFace, pos)
+			if sr.IsRTLRune(j) { // after is to the left
+				aft = !aft
+			}
+			if minDist >= 0 && dist >= minDist {
+				continue
+			}
+			minDist = dist
+			si, ri, after, ok = i, j, aft, true
+			if dist == 0 {
+				return
+			}
+		}
+	}
+	return
+}
+
 //////////////////////////////////////////////////////////////////////////////////
 //  TextStyle-based Layout Routines
 
+// LayoutStd does standard layout of text per the writing mode of given text
+// style: LayoutStdTB for vertical writing modes (see gist.Text.IsVertical),
+// and otherwise LayoutStdLR
+func (tr *Text) LayoutStd(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.Context, size mat32.Vec2) mat32.Vec2 {
+	if txtSty.IsVertical() {
+		return tr.LayoutStdTB(txtSty, fontSty, ctxt, size)
+	}
+	return tr.LayoutStdLR(txtSty, fontSty, ctxt, size)
+}
+
+// SplitLinks updates the links after the span at given index has been split
+// at given rune index, with the remainder inserted as the next span
+func (tr *Text) SplitLinks(si, idx int) {
+	for li := range tr.Links {
+		tl := &tr.Links[li]
+		if tl.StartSpan == si {
+			if tl.StartIdx >= idx {
+				tl.StartIdx -= idx
+				tl.StartSpan++
+			}
+		} else if tl.StartSpan > si {
+			tl.StartSpan++
+		}
+		if tl.EndSpan == si {
+			if tl.EndIdx >= idx {
+				tl.EndIdx -= idx
+				tl.EndSpan++
+			}
+		} else if tl.EndSpan > si {
+			tl.EndSpan++
+		}
+	}
+}
+
+// ClampLinks makes sure that the links are still within the range of their
+// spans, after layout
+func (tr *Text) ClampLinks() {
+	for li := range tr.Links {
+		tl := &tr.Links[li]
+		stsp := tr.Spans[tl.StartSpan]
+		if tl.StartIdx >= len(stsp.Text) {
+			tl.StartIdx = len(stsp.Text) - 1
+		}
+		edsp := tr.Spans[tl.EndSpan]
+		if tl.EndIdx >= len(edsp.Text) {
+			tl.EndIdx = len(edsp.Text) - 1
+		}
+	}
+}
+
 // LayoutStdLR does basic standard layout of text in LR direction, assigning
 // relative positions to spans and runes according to given styles, and given
 // size overall box (nonzero values used to constrain). Returns total
@@ -842,8 +1058,8 @@ func (tr *Text) LayoutStdLR(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.C
 			si++
 			continue
 		}
-		if sr.LastPos.X == 0 { // don't re-do unless necessary
-			sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize)
+		if sr.LastPos.X == 0 || sr.BidiLevels != nil || sr.Justified != 0 { // don't re-do unless necessary, or in visual order or justified
+			sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize, txtSty.TabStops)
 		}
 		if sr.IsNewPara() {
 			sr.RelPos.X = txtSty.Indent.Dots
@@ -854,7 +1070,7 @@ func (tr *Text) LayoutStdLR(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.C
 		ssz.X += sr.RelPos.X
 		if size.X > 0 && ssz.X > size.X && txtSty.HasWordWrap() {
 			for {
-				wp := sr.FindWrapPosLR(size.X, ssz.X)
+				wp := sr.FindWrapPosLR(size.X, ssz.X, txtSty.WordBreak)
 				if wp > 0 && wp < len(sr.Text)-1 {
 					nsr := sr.SplitAtLR(wp)
 					tr.InsertSpan(si+1, nsr)
@@ -865,29 +1081,9 @@ func (tr *Text) LayoutStdLR(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.C
 					}
 					si++
 					sr = &(tr.Spans[si]) // keep going with nsr
-					sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize)
+					sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize, txtSty.TabStops)
 					ssz = sr.SizeHV()
-
-					// fixup links
-					for li := range tr.Links {
-						tl := &tr.Links[li]
-						if tl.StartSpan == si-1 {
-							if tl.StartIdx >= wp {
-								tl.StartIdx -= wp
-								tl.StartSpan++
-							}
-						} else if tl.StartSpan > si-1 {
-							tl.StartSpan++
-						}
-						if tl.EndSpan == si-1 {
-							if tl.EndIdx >= wp {
-								tl.EndIdx -= wp
-								tl.EndSpan++
-							}
-						} else if tl.EndSpan > si-1 {
-							tl.EndSpan++
-						}
-					}
+					tr.SplitLinks(si-1, wp)
 
 					if ssz.X <= size.X {
 						if ssz.X > maxw {
@@ -909,20 +1105,10 @@ func (tr *Text) LayoutStdLR(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.C
 		}
 		si++
 	}
+	tr.SetRunePosBidi(txtSty)
 	// have maxw, can do alignment cases..
 
-	// make sure links are still in range
-	for li := range tr.Links {
-		tl := &tr.Links[li]
-		stsp := tr.Spans[tl.StartSpan]
-		if tl.StartIdx >= len(stsp.Text) {
-			tl.StartIdx = len(stsp.Text) - 1
-		}
-		edsp := tr.Spans[tl.EndSpan]
-		if tl.EndIdx >= len(edsp.Text) {
-			tl.EndIdx = len(edsp.Text) - 1
-		}
-	}
+	tr.ClampLinks()
 
 	if maxw > size.X {
 		size.X = maxw
@@ -969,6 +1155,12 @@ func (tr *Text) LayoutStdLR(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.C
 		ssz := sr.SizeHV()
 		ssz.X += sr.RelPos.X
 		hextra := size.X - ssz.X
+		if txtSty.Align == gist.AlignJustify && si < nsp-1 && !tr.Spans[si+1].IsNewPara() { // not the last line of a paragraph
+			if sr.JustifyLR(size.X - sr.RelPos.X) {
+				hextra = 0
+				tr.Size.X = mat32.Max(tr.Size.X, size.X)
+			}
+		}
 		if hextra > 0 {
 			switch {
 			case gist.IsAlignMiddle(txtSty.Align):
@@ -982,6 +1174,92 @@ func (tr *Text) LayoutStdLR(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.C
 	return size
 }
 
+// LayoutStdTB does basic standard layout of vertical text, for the TBRL
+// (vertical-rl, also TB) and TBLR (vertical-lr) writing modes, e.g., for
+// Chinese or Japanese: each span is a column, running top to bottom (see
+// Span.SetRunePosVert), with the columns advancing right to left for TBRL,
+// and left to right for TBLR.  The columns are wrapped to fit within the
+// height of given size (if nonzero), and Align aligns them along that
+// height, while they start from the right (TBRL) or left (TBLR) of its
+// width.  Returns total resulting size box for text.  Decorations are not
+// yet supported for vertical text.
+func (tr *Text) LayoutStdTB(txtSty *gist.Text, fontSty *gist.Font, ctxt *units.Context, size mat32.Vec2) mat32.Vec2 {
+	if len(tr.Spans) == 0 {
+		return mat32.Vec2Zero
+	}
+	tr.Dir = gist.TBRL
+	if txtSty.WritingMode == gist.TBLR {
+		tr.Dir = gist.TBLR
+	}
+	OpenFont(fontSty, ctxt)
+	lspc := fontSty.Face.Metrics.Height * txtSty.EffLineHeight() // column width
+	sideways := txtSty.OrientationVert != 0
+
+	maxh := float32(0)
+	for si := 0; si < len(tr.Spans); si++ {
+		sr := &(tr.Spans[si])
+		if err := sr.IsValid(); err != nil {
+			continue
+		}
+		ends := sr.SetRunePosVert(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Face.Metrics.Ch, txtSty.TabSize, sideways)
+		sr.RelPos.Y = 0
+		if sr.IsNewPara() {
+			sr.RelPos.Y = txtSty.Indent.Dots
+		}
+		if size.Y > 0 && txtSty.HasWordWrap() {
+			wp := sr.FindWrapPosTB(ends, size.Y-sr.RelPos.Y, txtSty.WordBreak)
+			if wp > 0 && wp < len(sr.Text)-1 {
+				nsr := sr.SplitAtLR(wp)
+				sr.LastPos = mat32.Vec2{0, ends[wp-1]}
+				tr.InsertSpan(si+1, nsr) // next column, positioned in next iteration
+				tr.SplitLinks(si, wp)
+				sr = &(tr.Spans[si])
+			}
+		}
+		maxh = mat32.Max(maxh, sr.RelPos.Y+sr.LastPos.Y)
+	}
+	tr.ClampLinks()
+
+	nsp := len(tr.Spans)
+	wd := float32(0)
+	for si := 0; si < nsp; si++ {
+		if si > 0 && tr.Spans[si].IsNewPara() {
+			wd += txtSty.ParaSpacing.Dots
+		}
+		wd += lspc
+	}
+	if wd > size.X {
+		size.X = wd
+	}
+	if maxh > size.Y {
+		size.Y = maxh
+	}
+	tr.Size = mat32.Vec2{wd, maxh}
+
+	xpos := float32(0)
+	for si := range tr.Spans {
+		sr := &(tr.Spans[si])
+		if si > 0 && sr.IsNewPara() {
+			xpos += txtSty.ParaSpacing.Dots
+		}
+		sr.RelPos.X = xpos + lspc/2 // center of column
+		if tr.Dir == gist.TBRL {
+			sr.RelPos.X = size.X - sr.RelPos.X
+		}
+		vextra := size.Y - (sr.RelPos.Y + sr.LastPos.Y)
+		if vextra > 0 {
+			switch {
+			case gist.IsAlignMiddle(txtSty.Align):
+				sr.RelPos.Y += vextra / 2
+			case gist.IsAlignEnd(txtSty.Align):
+				sr.RelPos.Y += vextra
+			}
+		}
+		xpos += lspc
+	}
+	return size
+}
+
 //////////////////////////////////////////////////////////////////////////////////
 //  Utilities
 
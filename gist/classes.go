@@ -0,0 +1,81 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gist
+
+import (
+	"strings"
+	"sync"
+)
+
+// ClassStyleFunc is a function that sets style values for a named style class
+type ClassStyleFunc func(s *Style)
+
+// StyleClasses is the registry of named style classes, which are applied to
+// any element having that name in its space-separated Class list, e.g.,
+// Class = "danger large".  Use AddClass to register new classes.
+// Names are case insensitive, as for CSS class selectors.
+var StyleClasses map[string]ClassStyleFunc
+
+// StyleClassesMu is a mutex protecting updates to StyleClasses
+var StyleClassesMu sync.RWMutex
+
+// AddClass registers a style function for given class name, replacing any
+// existing one, e.g.,
+//
+//	gist.AddClass("danger", func(s *gist.Style) {
+//		s.Font.Color.SetString("red", nil)
+//	})
+//
+// Existing styled elements will need to be re-styled to see the new class
+// (e.g., via a FullReRender of the window).
+func AddClass(name string, fun ClassStyleFunc) {
+	StyleClassesMu.Lock()
+	if StyleClasses == nil {
+		StyleClasses = make(map[string]ClassStyleFunc)
+	}
+	StyleClasses[strings.ToLower(name)] = fun
+	StyleClassesMu.Unlock()
+	StyleTemplatesMu.Lock()
+	StyleTemplates = nil // templates may include class styles
+	StyleTemplatesMu.Unlock()
+}
+
+// DeleteClass removes the registered style function for given class name
+func DeleteClass(name string) {
+	StyleClassesMu.Lock()
+	delete(StyleClasses, strings.ToLower(name))
+	StyleClassesMu.Unlock()
+}
+
+// ClassFunc returns the registered style function for given class name,
+// or nil if none.
+func ClassFunc(name string) ClassStyleFunc {
+	StyleClassesMu.RLock()
+	defer StyleClassesMu.RUnlock()
+	return StyleClasses[strings.ToLower(name)]
+}
+
+// ApplyClasses applies the registered style functions for each of the
+// space-separated class names in given class string, in order, so that
+// later classes take precedence over earlier ones.  Returns true if
+// any were applied.
+func (s *Style) ApplyClasses(class string) bool {
+	if class == "" {
+		return false
+	}
+	StyleClassesMu.RLock()
+	defer StyleClassesMu.RUnlock()
+	if len(StyleClasses) == 0 {
+		return false
+	}
+	got := false
+	for _, cl := range strings.Fields(strings.ToLower(class)) {
+		if fun, has := StyleClasses[cl]; has && fun != nil {
+			fun(s)
+			got = true
+		}
+	}
+	return got
+}
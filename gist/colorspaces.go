@@ -0,0 +1,463 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gist
+
+import (
+	"image/color"
+
+	"github.com/goki/mat32"
+)
+
+// Perceptual color spaces: OKLCH (the polar form of OKLab, by Björn
+// Ottosson, https://bottosson.github.io/posts/oklab/) and HSLuv
+// (https://www.hsluv.org/, a human-friendly form of CIELUV LCh).  Unlike
+// HSL, equal changes in lightness or chroma in these spaces look like equal
+// changes to the eye, so they are much better for generating color variants
+// programmatically, e.g., in color schemes.  Also has the WCAG contrast
+// ratio, and functions to derive colors with sufficient contrast.
+
+/////////////////////////////////////////////////////////////////////////////
+//  sRGB linear
+
+// SRGBToLinear converts an sRGB gamma-encoded 0..1 component value to
+// linear light 0..1
+func SRGBToLinear(c float32) float32 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return mat32.Pow((c+0.055)/1.055, 2.4)
+}
+
+// SRGBFromLinear converts a linear light 0..1 component value to sRGB
+// gamma-encoded 0..1
+func SRGBFromLinear(c float32) float32 {
+	if c <= 0.0031308 {
+		return 12.92 * c
+	}
+	return 1.055*mat32.Pow(c, 1/2.4) - 0.055
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  OKLCH
+
+// OKLCH represents a color in the OKLCH color space: perceptual Lightness
+// [0..1], Chroma [0..~0.4] (0 = grey), and Hue [0..360], using float32
+// values.  Colors outside of the sRGB gamut are mapped into it by reducing
+// chroma, keeping lightness and hue.
+type OKLCH struct {
+	L, C, H, A float32
+}
+
+// Implements the color.Color interface
+func (c OKLCH) RGBA() (r, g, b, a uint32) {
+	fr, fg, fb := OKLCHtoRGBf32(c.L, c.C, c.H)
+	r = uint32(fr*c.A*65535.0 + 0.5)
+	g = uint32(fg*c.A*65535.0 + 0.5)
+	b = uint32(fb*c.A*65535.0 + 0.5)
+	a = uint32(c.A*65535.0 + 0.5)
+	return
+}
+
+// RGBtoOKLabf32 converts sRGB 0..1 values (non alpha-premultiplied) to
+// OKLab lightness L and opponent a, b axes
+func RGBtoOKLabf32(r, g, b float32) (l, oa, ob float32) {
+	r = SRGBToLinear(r)
+	g = SRGBToLinear(g)
+	b = SRGBToLinear(b)
+	lc := mat32.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	mc := mat32.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	sc := mat32.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	oa = 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	ob = 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+	return
+}
+
+// OKLabtoLinearRGBf32 converts OKLab values to linear sRGB values, which
+// are outside of the 0..1 range if the color is out of the sRGB gamut
+func OKLabtoLinearRGBf32(l, oa, ob float32) (r, g, b float32) {
+	lc := l + 0.3963377774*oa + 0.2158037573*ob
+	mc := l - 0.1055613458*oa - 0.0638541728*ob
+	sc := l - 0.0894841775*oa - 1.2914855480*ob
+	lc = lc * lc * lc
+	mc = mc * mc * mc
+	sc = sc * sc * sc
+	r = 4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc
+	g = -1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc
+	b = -0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc
+	return
+}
+
+// RGBtoOKLCHf32 converts sRGB 0..1 values (non alpha-premultiplied) to
+// OKLCH lightness [0..1], chroma, and hue [0..360]
+func RGBtoOKLCHf32(r, g, b float32) (l, c, h float32) {
+	l, oa, ob := RGBtoOKLabf32(r, g, b)
+	c = mat32.Sqrt(oa*oa + ob*ob)
+	if c < 1.0e-5 { // grey: hue is undefined
+		return l, 0, 0
+	}
+	h = mat32.RadToDeg(mat32.Atan2(ob, oa))
+	if h < 0 {
+		h += 360
+	}
+	return
+}
+
+// oklchToLinear returns linear sRGB for given OKLCH values, and whether the
+// color is within the sRGB gamut
+func oklchToLinear(l, c, h float32) (r, g, b float32, in bool) {
+	hr := mat32.DegToRad(h)
+	r, g, b = OKLabtoLinearRGBf32(l, c*mat32.Cos(hr), c*mat32.Sin(hr))
+	const eps = 1.0e-4
+	in = r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	return
+}
+
+// OKLCHInGamut returns true if the given OKLCH color is within the sRGB gamut
+func OKLCHInGamut(l, c, h float32) bool {
+	_, _, _, in := oklchToLinear(l, c, h)
+	return in
+}
+
+// OKLCHMaxChroma returns the maximum chroma that is within the sRGB gamut
+// for given OKLCH lightness and hue
+func OKLCHMaxChroma(l, h float32) float32 {
+	lo, hi := float32(0), float32(0.5)
+	for i := 0; i < 20; i++ {
+		mid := 0.5 * (lo + hi)
+		if OKLCHInGamut(l, mid, h) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// OKLCHtoRGBf32 converts OKLCH values to sRGB 0..1 values (non
+// alpha-premultiplied) -- colors out of the sRGB gamut are mapped into it
+// by reducing the chroma, which preserves the lightness and hue.
+func OKLCHtoRGBf32(l, c, h float32) (r, g, b float32) {
+	l = mat32.Clamp(l, 0, 1)
+	c = mat32.Max(c, 0)
+	lr, lg, lb, in := oklchToLinear(l, c, h)
+	if !in {
+		c = OKLCHMaxChroma(l, h)
+		lr, lg, lb, _ = oklchToLinear(l, c, h)
+	}
+	r = SRGBFromLinear(mat32.Clamp(lr, 0, 1))
+	g = SRGBFromLinear(mat32.Clamp(lg, 0, 1))
+	b = SRGBFromLinear(mat32.Clamp(lb, 0, 1))
+	return
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  HSLuv
+
+// HSLuv represents a color in the HSLuv color space: Hue [0..360],
+// Saturation [0..100] and perceptual Lightness [0..100], using float32
+// values.  Saturation is relative to the maximum chroma available in the
+// sRGB gamut for each hue and lightness, so any values are valid, and
+// colors with the same lightness have the same perceived lightness.
+type HSLuv struct {
+	H, S, L, A float32
+}
+
+// Implements the color.Color interface
+func (c HSLuv) RGBA() (r, g, b, a uint32) {
+	fr, fg, fb := HSLuvtoRGBf32(c.H, c.S, c.L)
+	r = uint32(fr*c.A*65535.0 + 0.5)
+	g = uint32(fg*c.A*65535.0 + 0.5)
+	b = uint32(fb*c.A*65535.0 + 0.5)
+	a = uint32(c.A*65535.0 + 0.5)
+	return
+}
+
+// constants for CIELUV, D65 white point
+var (
+	hsluvM = [3][3]float32{
+		{3.240969941904521, -1.537383177570093, -0.498610760293},
+		{-0.96924363628087, 1.87596750150772, 0.041555057407175},
+		{0.055630079696993, -0.20397695888897, 1.056971514242878},
+	}
+	hsluvMInv = [3][3]float32{
+		{0.41239079926595, 0.35758433938387, 0.18048078840183},
+		{0.21263900587151, 0.71516867876775, 0.072192315360733},
+		{0.019330818715591, 0.11919477979462, 0.95053215224966},
+	}
+	hsluvRefU    = float32(0.19783000664283)
+	hsluvRefV    = float32(0.46831999493879)
+	hsluvKappa   = float32(903.2962962)
+	hsluvEpsilon = float32(0.0088564516)
+)
+
+// hsluvMaxChroma returns the maximum CIELUV chroma within the sRGB gamut
+// for given lightness [0..100] and hue [0..360], as the closest
+// intersection of the hue ray with the bounds of the gamut
+func hsluvMaxChroma(l, h float32) float32 {
+	sub1 := (l + 16) * (l + 16) * (l + 16) / 1560896
+	sub2 := sub1
+	if sub1 <= hsluvEpsilon {
+		sub2 = l / hsluvKappa
+	}
+	hr := mat32.DegToRad(h)
+	sin, cos := mat32.Sin(hr), mat32.Cos(hr)
+	min := mat32.Infinity
+	for c := 0; c < 3; c++ {
+		m1, m2, m3 := hsluvM[c][0], hsluvM[c][1], hsluvM[c][2]
+		for t := float32(0); t < 2; t++ {
+			top1 := (284517*m1 - 94839*m3) * sub2
+			top2 := (838422*m3+769860*m2+731718*m1)*l*sub2 - 769860*t*l
+			bottom := (632260*m3-126452*m2)*sub2 + 126452*t
+			slope := top1 / bottom
+			icpt := top2 / bottom
+			ln := icpt / (sin - slope*cos)
+			if ln >= 0 && ln < min {
+				min = ln
+			}
+		}
+	}
+	return min
+}
+
+// RGBtoHSLuvf32 converts sRGB 0..1 values (non alpha-premultiplied) to
+// HSLuv hue [0..360], saturation [0..100] and lightness [0..100]
+func RGBtoHSLuvf32(r, g, b float32) (h, s, l float32) {
+	r = SRGBToLinear(r)
+	g = SRGBToLinear(g)
+	b = SRGBToLinear(b)
+	x := hsluvMInv[0][0]*r + hsluvMInv[0][1]*g + hsluvMInv[0][2]*b
+	y := hsluvMInv[1][0]*r + hsluvMInv[1][1]*g + hsluvMInv[1][2]*b
+	z := hsluvMInv[2][0]*r + hsluvMInv[2][1]*g + hsluvMInv[2][2]*b
+	if y <= hsluvEpsilon {
+		l = y * hsluvKappa
+	} else {
+		l = 116*mat32.Cbrt(y) - 16
+	}
+	if l < 1.0e-6 {
+		return 0, 0, 0
+	}
+	if l > 99.9999 {
+		return 0, 0, 100
+	}
+	den := x + 15*y + 3*z
+	u := 13 * l * (4*x/den - hsluvRefU)
+	v := 13 * l * (9*y/den - hsluvRefV)
+	c := mat32.Sqrt(u*u + v*v)
+	if c < 1.0e-5 { // grey: hue is undefined
+		return 0, 0, l
+	}
+	h = mat32.RadToDeg(mat32.Atan2(v, u))
+	if h < 0 {
+		h += 360
+	}
+	s = mat32.Min(c/hsluvMaxChroma(l, h)*100, 100)
+	return
+}
+
+// HSLuvtoRGBf32 converts HSLuv hue [0..360], saturation [0..100] and
+// lightness [0..100] to sRGB 0..1 values (non alpha-premultiplied)
+func HSLuvtoRGBf32(h, s, l float32) (r, g, b float32) {
+	l = mat32.Clamp(l, 0, 100)
+	s = mat32.Clamp(s, 0, 100)
+	if l < 1.0e-6 {
+		return 0, 0, 0
+	}
+	if l > 99.9999 {
+		return 1, 1, 1
+	}
+	c := hsluvMaxChroma(l, h) / 100 * s
+	hr := mat32.DegToRad(h)
+	u := c * mat32.Cos(hr)
+	v := c * mat32.Sin(hr)
+	vu := u/(13*l) + hsluvRefU
+	vv := v/(13*l) + hsluvRefV
+	var y float32
+	if l <= 8 {
+		y = l / hsluvKappa
+	} else {
+		y = (l + 16) / 116
+		y = y * y * y
+	}
+	x := -(9 * y * vu) / ((vu-4)*vv - vu*vv)
+	z := (9*y - 15*vv*y - vv*x) / (3 * vv)
+	r = hsluvM[0][0]*x + hsluvM[0][1]*y + hsluvM[0][2]*z
+	g = hsluvM[1][0]*x + hsluvM[1][1]*y + hsluvM[1][2]*z
+	b = hsluvM[2][0]*x + hsluvM[2][1]*y + hsluvM[2][2]*z
+	r = SRGBFromLinear(mat32.Clamp(r, 0, 1))
+	g = SRGBFromLinear(mat32.Clamp(g, 0, 1))
+	b = SRGBFromLinear(mat32.Clamp(b, 0, 1))
+	return
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  Color methods
+
+// ToOKLCH converts to OKLCH: perceptual Lightness [0..1], Chroma
+// [0..~0.4], and Hue [0..360], and alpha, using float32 values
+func (c *Color) ToOKLCH() (l, ch, h, a float32) {
+	r, g, b, a := c.ToNPFloat32()
+	l, ch, h = RGBtoOKLCHf32(r, g, b)
+	return
+}
+
+// SetOKLCH sets from OKLCH: perceptual Lightness [0..1], Chroma
+// [0..~0.4], and Hue [0..360], and alpha, using float32 values -- colors
+// out of the sRGB gamut are mapped into it by reducing the chroma
+func (c *Color) SetOKLCH(l, ch, h, a float32) {
+	r, g, b := OKLCHtoRGBf32(l, ch, h)
+	c.SetNPFloat32(r, g, b, a)
+}
+
+// ToHSLuv converts to HSLuv: Hue [0..360], Saturation [0..100], and
+// perceptual Lightness [0..100], and alpha, using float32 values
+func (c *Color) ToHSLuv() (h, s, l, a float32) {
+	r, g, b, a := c.ToNPFloat32()
+	h, s, l = RGBtoHSLuvf32(r, g, b)
+	return
+}
+
+// SetHSLuv sets from HSLuv: Hue [0..360], Saturation [0..100], and
+// perceptual Lightness [0..100], and alpha, using float32 values
+func (c *Color) SetHSLuv(h, s, l, a float32) {
+	r, g, b := HSLuvtoRGBf32(h, s, l)
+	c.SetNPFloat32(r, g, b, a)
+}
+
+// LighterOK returns a color that is lighter by the given percent, e.g.,
+// 50 = 50% lighter, relative to maximum possible lightness -- like Lighter,
+// but using the perceptual OKLCH lightness, so the same percent gives the
+// same visible change for all colors, and hue is preserved.
+func (c *Color) LighterOK(pct float32) Color {
+	l, ch, h, a := c.ToOKLCH()
+	pct = mat32.Clamp(pct, 0, 100.0)
+	l += (1.0 - l) * (pct / 100.0)
+	var nc Color
+	nc.SetOKLCH(l, ch, h, a)
+	return nc
+}
+
+// DarkerOK returns a color that is darker by the given percent, e.g.,
+// 50 = 50% darker, relative to maximum possible darkness -- like Darker,
+// but using the perceptual OKLCH lightness, so the same percent gives the
+// same visible change for all colors, and hue is preserved.
+func (c *Color) DarkerOK(pct float32) Color {
+	l, ch, h, a := c.ToOKLCH()
+	pct = mat32.Clamp(pct, 0, 100.0)
+	l -= l * (pct / 100.0)
+	var nc Color
+	nc.SetOKLCH(l, ch, h, a)
+	return nc
+}
+
+// SaturateOK returns a color that is more saturated by the given percent:
+// 100 = maximum chroma in the sRGB gamut for its lightness and hue -- like
+// Saturate, but using OKLCH chroma, so lightness and hue are preserved.
+func (c *Color) SaturateOK(pct float32) Color {
+	l, ch, h, a := c.ToOKLCH()
+	pct = mat32.Clamp(pct, 0, 100.0)
+	mc := OKLCHMaxChroma(l, h)
+	if ch < mc {
+		ch += (mc - ch) * (pct / 100.0)
+	}
+	var nc Color
+	nc.SetOKLCH(l, ch, h, a)
+	return nc
+}
+
+// PastelOK returns a color that is less saturated (more pastel-like) by
+// the given percent: 100 = 100% less saturated (i.e., grey) -- like
+// Pastel, but using OKLCH chroma, so lightness and hue are preserved.
+func (c *Color) PastelOK(pct float32) Color {
+	l, ch, h, a := c.ToOKLCH()
+	pct = mat32.Clamp(pct, 0, 100.0)
+	ch -= ch * (pct / 100.0)
+	var nc Color
+	nc.SetOKLCH(l, ch, h, a)
+	return nc
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  Contrast
+
+const (
+	// ContrastAA is the minimum WCAG 2 contrast ratio for normal text at level AA
+	ContrastAA float32 = 4.5
+
+	// ContrastAALarge is the minimum WCAG 2 contrast ratio for large text
+	// and graphical elements at level AA
+	ContrastAALarge float32 = 3
+
+	// ContrastAAA is the minimum WCAG 2 contrast ratio for normal text at level AAA
+	ContrastAAA float32 = 7
+)
+
+// RelativeLuminance returns the WCAG 2 relative luminance of given color,
+// from 0 = black to 1 = white, ignoring alpha
+func RelativeLuminance(clr color.Color) float32 {
+	f32 := NRGBAf32Model.Convert(clr).(NRGBAf32)
+	return 0.2126*SRGBToLinear(f32.R) + 0.7152*SRGBToLinear(f32.G) + 0.0722*SRGBToLinear(f32.B)
+}
+
+// ContrastRatio returns the WCAG 2 contrast ratio between two colors,
+// from 1 (no contrast) to 21 (black on white), which is symmetric in
+// the two colors -- see ContrastAA etc for minimum values for legibility
+func ContrastRatio(a, b color.Color) float32 {
+	la := RelativeLuminance(a)
+	lb := RelativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ContrastColor returns a version of the fg color that has at least the
+// given contrast ratio against the bg color, changing only its perceptual
+// OKLCH lightness, by as little as possible -- returns fg if it already has
+// sufficient contrast, and White or Black if the ratio cannot be reached
+// (for ratios above 4.5, some mid-tone backgrounds cannot reach the ratio
+// with any color).
+func ContrastColor(fg, bg Color, ratio float32) Color {
+	if ContrastRatio(fg, bg) >= ratio {
+		return fg
+	}
+	lighter := ContrastRatio(White, bg) >= ContrastRatio(Black, bg)
+	ext := Black
+	if lighter {
+		ext = White
+	}
+	ext.A = fg.A
+	if ContrastRatio(ext, bg) < ratio {
+		return ext
+	}
+	l, ch, h, a := fg.ToOKLCH()
+	lo, hi := l, float32(0) // lo = insufficient, hi = sufficient
+	if lighter {
+		hi = 1
+	}
+	var nc Color
+	for i := 0; i < 16; i++ {
+		mid := 0.5 * (lo + hi)
+		nc.SetOKLCH(mid, ch, h, a)
+		if ContrastRatio(nc, bg) >= ratio {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	nc.SetOKLCH(hi, ch, h, a)
+	return nc
+}
+
+// OnColor returns a color to use for text and icons on top of the given
+// background color, e.g., the "on primary" color in a color scheme: a
+// nearly grey tint of the background hue, with at least the ContrastAAA
+// contrast ratio if possible, and otherwise as much contrast as possible.
+func OnColor(bg Color) Color {
+	_, ch, h, _ := bg.ToOKLCH()
+	var tint Color
+	tint.SetOKLCH(0.5, mat32.Min(ch, 0.02), h, 1)
+	return ContrastColor(tint, bg, ContrastAAA)
+}
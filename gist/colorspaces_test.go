@@ -0,0 +1,60 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gist
+
+import (
+	"testing"
+)
+
+// colorClose returns true if the RGB components of given colors differ by
+// at most 1
+func colorClose(a, b Color) bool {
+	d := func(x, y uint8) bool {
+		df := int(x) - int(y)
+		return df >= -1 && df <= 1
+	}
+	return d(a.R, b.R) && d(a.G, b.G) && d(a.B, b.B)
+}
+
+func TestColorSpacesRoundTrip(t *testing.T) {
+	clrs := []Color{White, Black, {255, 0, 0, 255}, {0, 128, 0, 255}, {30, 60, 200, 255}, {200, 180, 20, 255}, {128, 128, 128, 255}}
+	for _, c := range clrs {
+		var oc, hc Color
+		oc.SetOKLCH(c.ToOKLCH())
+		if !colorClose(c, oc) {
+			t.Errorf("OKLCH round trip: %v != %v", oc.HexString(), c.HexString())
+		}
+		hc.SetHSLuv(c.ToHSLuv())
+		if !colorClose(c, hc) {
+			t.Errorf("HSLuv round trip: %v != %v", hc.HexString(), c.HexString())
+		}
+	}
+	if l, _, _, _ := White.ToOKLCH(); l < 0.999 || l > 1.001 {
+		t.Errorf("OKLCH white lightness: %v", l)
+	}
+	if _, _, l, _ := White.ToHSLuv(); l < 99.9 {
+		t.Errorf("HSLuv white lightness: %v", l)
+	}
+}
+
+func TestContrast(t *testing.T) {
+	if cr := ContrastRatio(Black, White); cr < 20.99 || cr > 21.01 {
+		t.Errorf("black on white contrast: %v", cr)
+	}
+	if cr := ContrastRatio(White, White); cr != 1 {
+		t.Errorf("white on white contrast: %v", cr)
+	}
+	bgs := []Color{White, Black, {30, 60, 200, 255}, {250, 240, 200, 255}, {17, 57, 57, 255}}
+	for _, bg := range bgs {
+		fg := ContrastColor(Color{128, 128, 140, 255}, bg, ContrastAA)
+		if cr := ContrastRatio(fg, bg); cr < ContrastAA {
+			t.Errorf("ContrastColor on %v: %v ratio: %v", bg.HexString(), fg.HexString(), cr)
+		}
+		on := OnColor(bg)
+		if cr := ContrastRatio(on, bg); cr < ContrastAA {
+			t.Errorf("OnColor on %v: %v ratio: %v", bg.HexString(), on.HexString(), cr)
+		}
+	}
+}
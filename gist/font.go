@@ -19,19 +19,24 @@ import (
 // is used in SVG text rendering -- used in Paint and in Style. Most of font
 // information is inherited.
 type Font struct {
-	Color   Color           `xml:"color" inherit:"true" desc:"prop: color (inherited) = text color -- also defines the currentColor variable value"`
-	BgColor ColorSpec       `xml:"background-color" desc:"prop: background-color = background color -- not inherited, transparent by default"`
-	Opacity float32         `xml:"opacity" desc:"prop: opacity = alpha value to apply to all elements"`
-	Size    units.Value     `xml:"font-size" inherit:"true" desc:"prop: font-size (inherited)= size of font to render -- convert to points when getting font to use"`
-	Family  string          `xml:"font-family" inherit:"true" desc:"prop: font-family = font family -- ordered list of comma-separated names from more general to more specific to use -- use split on , to parse"`
-	Style   FontStyles      `xml:"font-style" inherit:"true" desc:"prop: font-style = style -- normal, italic, etc"`
-	Weight  FontWeights     `xml:"font-weight" inherit:"true" desc:"prop: font-weight = weight: normal, bold, etc"`
-	Stretch FontStretch     `xml:"font-stretch" inherit:"true" desc:"prop: font-stretch = font stretch / condense options"`
-	Variant FontVariants    `xml:"font-variant" inherit:"true" desc:"prop: font-variant = normal or small caps"`
-	Deco    TextDecorations `xml:"text-decoration" desc:"prop: text-decoration = underline, line-through, etc -- not inherited"`
-	Shift   BaselineShifts  `xml:"baseline-shift" desc:"prop: baseline-shift = super / sub script -- not inherited"`
-	Face    *FontFace       `view:"-" desc:"full font information including enhanced metrics and actual font codes for drawing text -- this is a pointer into FontLibrary of loaded fonts"`
-	Rem     float32         `desc:"Rem size of font -- 12pt converted to same effective DPI as above measurements"`
+	Color       Color           `xml:"color" inherit:"true" desc:"prop: color (inherited) = text color -- also defines the currentColor variable value"`
+	BgColor     ColorSpec       `xml:"background-color" desc:"prop: background-color = background color -- not inherited, transparent by default"`
+	Opacity     float32         `xml:"opacity" desc:"prop: opacity = alpha value to apply to all elements"`
+	Size        units.Value     `xml:"font-size" inherit:"true" desc:"prop: font-size (inherited)= size of font to render -- convert to points when getting font to use"`
+	Family      string          `xml:"font-family" inherit:"true" desc:"prop: font-family = font family -- ordered list of comma-separated names from more general to more specific to use -- use split on , to parse"`
+	Style       FontStyles      `xml:"font-style" inherit:"true" desc:"prop: font-style = style -- normal, italic, etc"`
+	Weight      FontWeights     `xml:"font-weight" inherit:"true" desc:"prop: font-weight = weight: normal, bold, etc"`
+	Stretch     FontStretch     `xml:"font-stretch" inherit:"true" desc:"prop: font-stretch = font stretch / condense options"`
+	Variant     FontVariants    `xml:"font-variant" inherit:"true" desc:"prop: font-variant = normal or small caps"`
+	Deco        TextDecorations `xml:"text-decoration" desc:"prop: text-decoration = underline, line-through, etc -- not inherited"`
+	Shift       BaselineShifts  `xml:"baseline-shift" desc:"prop: baseline-shift = super / sub script -- not inherited"`
+	Variations  FontVariations  `xml:"font-variation-settings" inherit:"true" desc:"prop: font-variation-settings (inherited) = values for the standard variable font axes (wght, wdth, slnt, ital), which override the corresponding weight, stretch, and style settings in selecting the font face -- each axis selects the closest static face, as fonts are not instanced at arbitrary axis values"`
+	Features    FontFeatures    `xml:"font-feature-settings" inherit:"true" desc:"prop: font-feature-settings (inherited) = OpenType font feature settings, e.g., kern 0 to turn off kerning, tnum for tabular numbers -- only kern and tnum are currently supported"`
+	StrokeWidth units.Value     `xml:"text-stroke-width" inherit:"true" desc:"prop: text-stroke-width (inherited) = width of the outline drawn along the edges of the glyphs, over their fill, e.g., for outlined headings -- 0 for none -- text-stroke sets the width and color together, e.g., 2px red"`
+	StrokeColor Color           `xml:"text-stroke-color" inherit:"true" desc:"prop: text-stroke-color (inherited) = color of the outline of the glyphs, if text-stroke-width is > 0 -- the text color if not set"`
+	TextShadow  TextShadows     `xml:"text-shadow" inherit:"true" desc:"prop: text-shadow (inherited) = shadows drawn behind the glyphs, each with an offset, optional blur radius and color, e.g., 1px 1px 2px black, 0 0 8px blue -- the first is on top"`
+	Face        *FontFace       `view:"-" desc:"full font information including enhanced metrics and actual font codes for drawing text -- this is a pointer into FontLibrary of loaded fonts"`
+	Rem         float32         `desc:"Rem size of font -- 12pt converted to same effective DPI as above measurements"`
 	// todo: kerning
 	// todo: stretch -- css 3 -- not supported
 }
@@ -60,11 +65,32 @@ func (fs *Font) InheritFields(par *Font) {
 	fs.Weight = par.Weight
 	fs.Stretch = par.Stretch
 	fs.Variant = par.Variant
+	fs.Variations = par.Variations
+	fs.Features = par.Features
+	fs.StrokeWidth = par.StrokeWidth
+	fs.StrokeColor = par.StrokeColor
+	fs.TextShadow = par.TextShadow
 }
 
-// ToDots runs ToDots on unit values, to compile down to raw pixels
+// TextScale is the overall scaling factor for font sizes, independent of
+// the logical DPI (zoom) that scales everything -- it applies to all sizes
+// except those relative to the font of the parent (em, ex, ch, %), which
+// are already scaled.  Set from the gi TextScale preference.
+var TextScale = float32(1)
+
+// ToDots runs ToDots on unit values, to compile down to raw pixels --
+// the font size is scaled by TextScale
 func (fs *Font) ToDots(uc *units.Context) {
 	fs.Size.ToDots(uc)
+	fs.StrokeWidth.ToDots(uc)
+	fs.TextShadow.ToDots(uc)
+	if TextScale != 1 {
+		switch fs.Size.Un {
+		case units.Em, units.Ex, units.Ch, units.Pct:
+		default:
+			fs.Size.Dots *= TextScale
+		}
+	}
 }
 
 // SetDeco sets decoration (underline, etc), which uses bitflag to allow multiple combinations
@@ -147,6 +173,21 @@ func (fs *Font) CopyNonDefaultProps(node ki.Ki) {
 	if fs.Shift != ShiftBaseline {
 		node.SetProp("baseline-shift", fs.Shift)
 	}
+	if !fs.Variations.IsZero() {
+		node.SetProp("font-variation-settings", fs.Variations.String())
+	}
+	if len(fs.Features) > 0 {
+		node.SetProp("font-feature-settings", fs.Features.String())
+	}
+	if fs.StrokeWidth.Val != 0 {
+		node.SetProp("text-stroke-width", fs.StrokeWidth)
+		if !fs.StrokeColor.IsNil() {
+			node.SetProp("text-stroke-color", fs.StrokeColor)
+		}
+	}
+	if len(fs.TextShadow) > 0 {
+		node.SetProp("text-shadow", fs.TextShadow.String())
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////////////
@@ -322,6 +363,10 @@ const (
 	DecoSub
 	// DecoBgColor indicates that a bg color has been set -- for use in optimizing rendering
 	DecoBgColor
+	// DecoStroke indicates that a text stroke has been set -- for use in optimizing rendering
+	DecoStroke
+	// DecoShadow indicates that a text shadow has been set -- for use in optimizing rendering
+	DecoShadow
 	TextDecorationsN
 )
 
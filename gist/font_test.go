@@ -58,3 +58,62 @@ func TestFontMods(t *testing.T) {
 		}
 	}
 }
+
+func TestFontFeatures(t *testing.T) {
+	var ff FontFeatures
+	err := ff.SetString(`"tnum", "liga" 0, 'ss01' on, "kern" off`)
+	if err != nil {
+		t.Error(err)
+	}
+	cor := `"kern" 0, "liga" 0, "ss01" 1, "tnum" 1`
+	if ff.String() != cor {
+		t.Errorf("FontFeatures: %v != correct: %v\n", ff.String(), cor)
+	}
+	if !ff.IsOn("tnum", false) || ff.IsOn("liga", true) || !ff.IsOn("calt", true) {
+		t.Errorf("FontFeatures IsOn wrong for: %v\n", ff.String())
+	}
+	if err := ff.SetString(`"toolong" 1`); err == nil {
+		t.Errorf("FontFeatures: expected error for invalid tag\n")
+	}
+}
+
+func TestFontVariations(t *testing.T) {
+	fs := Font{}
+	err := fs.Variations.SetString(`"wght" 600, "wdth" 75, "ital" 1`)
+	if err != nil {
+		t.Error(err)
+	}
+	str, wt, sty := fs.EffMods()
+	if str != FontStrCondensed || wt != Weight600 || sty != FontItalic {
+		t.Errorf("FontVariations EffMods: %v %v %v != correct: %v %v %v\n", str, wt, sty, FontStrCondensed, Weight600, FontItalic)
+	}
+	if WeightFromVar(400) != Weight400 || WeightFromVar(1000) != Weight900 || WeightFromVar(1) != Weight100 {
+		t.Errorf("WeightFromVar wrong\n")
+	}
+}
+
+func TestTextShadows(t *testing.T) {
+	var tss TextShadows
+	err := tss.SetString(`1px 2px 3px red, rgb(0, 0, 255) -1px 0, 2px 2px`)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(tss) != 3 {
+		t.Fatalf("TextShadows: got %d shadows, want 3\n", len(tss))
+	}
+	if tss[0].HOffset.Val != 1 || tss[0].VOffset.Val != 2 || tss[0].Blur.Val != 3 || tss[0].Color != (Color{255, 0, 0, 255}) {
+		t.Errorf("TextShadows: first shadow wrong: %v\n", tss[0].String())
+	}
+	if tss[1].HOffset.Val != -1 || tss[1].Blur.Val != 0 || tss[1].Color != (Color{0, 0, 255, 255}) {
+		t.Errorf("TextShadows: color-first shadow wrong: %v\n", tss[1].String())
+	}
+	if !tss[2].Color.IsNil() {
+		t.Errorf("TextShadows: shadow without color: got %v, want nil\n", tss[2].Color)
+	}
+	if err := tss.SetString(`2px red`); err == nil {
+		t.Errorf("TextShadows: expected error for missing offset\n")
+	}
+	if err := tss.SetString(`none`); err != nil || len(tss) != 0 || tss.String() != "none" {
+		t.Errorf("TextShadows: none: got %v, %v\n", tss.String(), err)
+	}
+}
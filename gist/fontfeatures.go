@@ -0,0 +1,252 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gist
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FontVariations are the values of the standard OpenType variable font
+// design axes, as set by the font-variation-settings property, e.g.,
+// `"wght" 650, "wdth" 75`.  A zero value means the axis is not set, and the
+// regular Weight, Stretch, and Style settings are used instead.  Variable
+// fonts are not instanced at arbitrary axis values: each axis only selects
+// the closest static face of the font family (see Font.EffMods), so, e.g.,
+// "wght" 650 renders as weight 600 or 700.  Other axes are ignored.
+type FontVariations struct {
+	Weight float32 `desc:"wght axis: weight, in standard CSS units from 1 to 1000, where 400 = normal and 700 = bold"`
+	Width  float32 `desc:"wdth axis: width, as a percentage of normal width, where 100 = normal, 75 = condensed, 125 = expanded"`
+	Slant  float32 `desc:"slnt axis: slant angle in degrees, where negative values lean to the right, as in oblique fonts"`
+	Italic float32 `desc:"ital axis: 1 = use italic forms, 0 = upright"`
+}
+
+// IsZero returns true if no variation axes are set
+func (fv *FontVariations) IsZero() bool {
+	return fv.Weight == 0 && fv.Width == 0 && fv.Slant == 0 && fv.Italic == 0
+}
+
+// SetString sets variation values from a CSS font-variation-settings
+// string, e.g., `"wght" 650, "wdth" 75` -- "normal" resets all values.
+func (fv *FontVariations) SetString(str string) error {
+	*fv = FontVariations{}
+	str = strings.TrimSpace(str)
+	if str == "" || str == "normal" {
+		return nil
+	}
+	for _, it := range strings.Split(str, ",") {
+		tag, vals := splitFontTag(it)
+		if tag == "" || vals == "" {
+			return fmt.Errorf("gist.FontVariations: invalid setting: %q", it)
+		}
+		v, err := strconv.ParseFloat(vals, 32)
+		if err != nil {
+			return fmt.Errorf("gist.FontVariations: invalid value for %q: %v", tag, err)
+		}
+		switch tag {
+		case "wght":
+			fv.Weight = float32(v)
+		case "wdth":
+			fv.Width = float32(v)
+		case "slnt":
+			fv.Slant = float32(v)
+		case "ital":
+			fv.Italic = float32(v)
+		}
+	}
+	return nil
+}
+
+// String returns the CSS font-variation-settings representation
+func (fv FontVariations) String() string {
+	var sl []string
+	if fv.Weight != 0 {
+		sl = append(sl, fmt.Sprintf(`"wght" %g`, fv.Weight))
+	}
+	if fv.Width != 0 {
+		sl = append(sl, fmt.Sprintf(`"wdth" %g`, fv.Width))
+	}
+	if fv.Slant != 0 {
+		sl = append(sl, fmt.Sprintf(`"slnt" %g`, fv.Slant))
+	}
+	if fv.Italic != 0 {
+		sl = append(sl, fmt.Sprintf(`"ital" %g`, fv.Italic))
+	}
+	if len(sl) == 0 {
+		return "normal"
+	}
+	return strings.Join(sl, ", ")
+}
+
+// WeightFromVar returns the FontWeights value closest to given wght axis value
+func WeightFromVar(wght float32) FontWeights {
+	wts := []FontWeights{Weight100, Weight200, Weight300, Weight400, Weight500, Weight600, Weight700, Weight800, Weight900}
+	wi := int((wght+50)/100) - 1
+	if wi < 0 {
+		wi = 0
+	}
+	if wi >= len(wts) {
+		wi = len(wts) - 1
+	}
+	return wts[wi]
+}
+
+// StretchFromVar returns the FontStretch value closest to given wdth axis
+// value, using the standard CSS percentages for each stretch value
+func StretchFromVar(wdth float32) FontStretch {
+	switch {
+	case wdth < 56.25:
+		return FontStrUltraCondensed
+	case wdth < 68.75:
+		return FontStrExtraCondensed
+	case wdth < 81.25:
+		return FontStrCondensed
+	case wdth < 93.75:
+		return FontStrSemiCondensed
+	case wdth <= 106.25:
+		return FontStrNormal
+	case wdth <= 118.75:
+		return FontStrSemiExpanded
+	case wdth <= 137.5:
+		return FontStrExpanded
+	case wdth <= 175:
+		return FontStrExtraExpanded
+	default:
+		return FontStrUltraExpanded
+	}
+}
+
+// EffMods returns the effective stretch, weight and style to use for
+// selecting a font face, taking into account any variation axis settings,
+// which override the corresponding regular settings -- each axis value is
+// snapped to the nearest of these settings, and a non-zero slant selects
+// the oblique style, regardless of its angle.
+func (fs *Font) EffMods() (str FontStretch, wt FontWeights, sty FontStyles) {
+	str, wt, sty = fs.Stretch, fs.Weight, fs.Style
+	fv := &fs.Variations
+	if fv.Weight != 0 {
+		wt = WeightFromVar(fv.Weight)
+	}
+	if fv.Width != 0 {
+		str = StretchFromVar(fv.Width)
+	}
+	if fv.Italic >= 0.5 {
+		sty = FontItalic
+	} else if fv.Slant != 0 {
+		sty = FontOblique
+	}
+	return
+}
+
+// FontFeature is one OpenType font feature setting, e.g., "liga" = 0 to
+// turn off standard ligatures, or "tnum" = 1 for tabular numbers.
+type FontFeature struct {
+	Tag   string `desc:"four-letter OpenType feature tag, e.g., liga, tnum, kern, ss01"`
+	Value int    `desc:"value of the feature: 0 = off, 1 = on, and higher values select among alternates for some features"`
+}
+
+// FontFeatures are a list of OpenType font feature settings, as set by the
+// font-feature-settings property, e.g., `"kern" 0, "tnum"`.  They are
+// applied by girl.FeatureFace in the font face used for layout and
+// rendering: "kern" and "tnum" by the face itself (see
+// girl.SupportedFeatures), and the others only by a text shaper that
+// supports them, e.g., girl.GoTextShaper in the gotext build -- otherwise
+// they have no effect, and a warning is logged the first time each one is
+// used.
+type FontFeatures []FontFeature
+
+// SetString sets features from a CSS font-feature-settings string, e.g.,
+// `"liga" 0, "tnum", "ss01" on` -- "normal" resets to no settings.
+// Features are sorted by tag so that equivalent settings have the
+// same string representation.
+func (ff *FontFeatures) SetString(str string) error {
+	*ff = nil
+	str = strings.TrimSpace(str)
+	if str == "" || str == "normal" {
+		return nil
+	}
+	for _, it := range strings.Split(str, ",") {
+		tag, vals := splitFontTag(it)
+		if len(tag) != 4 {
+			return fmt.Errorf("gist.FontFeatures: invalid feature tag in: %q", it)
+		}
+		val := 1
+		switch vals {
+		case "", "on":
+		case "off":
+			val = 0
+		default:
+			v, err := strconv.Atoi(vals)
+			if err != nil {
+				return fmt.Errorf("gist.FontFeatures: invalid value for %q: %v", tag, err)
+			}
+			val = v
+		}
+		ff.Set(tag, val)
+	}
+	return nil
+}
+
+// Set sets the value of given feature tag, adding it if not already present
+func (ff *FontFeatures) Set(tag string, val int) {
+	for i := range *ff {
+		if (*ff)[i].Tag == tag {
+			(*ff)[i].Value = val
+			return
+		}
+	}
+	*ff = append(*ff, FontFeature{Tag: tag, Value: val})
+	sort.Slice(*ff, func(i, j int) bool {
+		return (*ff)[i].Tag < (*ff)[j].Tag
+	})
+}
+
+// Value returns the value of given feature tag, and false if not set
+func (ff FontFeatures) Value(tag string) (int, bool) {
+	for _, f := range ff {
+		if f.Tag == tag {
+			return f.Value, true
+		}
+	}
+	return 0, false
+}
+
+// IsOn returns true if given feature is set and non-zero, or if not set,
+// returns the given default value.
+func (ff FontFeatures) IsOn(tag string, def bool) bool {
+	v, ok := ff.Value(tag)
+	if !ok {
+		return def
+	}
+	return v != 0
+}
+
+// String returns the CSS font-feature-settings representation
+func (ff FontFeatures) String() string {
+	if len(ff) == 0 {
+		return "normal"
+	}
+	sl := make([]string, len(ff))
+	for i, f := range ff {
+		sl[i] = fmt.Sprintf(`"%s" %d`, f.Tag, f.Value)
+	}
+	return strings.Join(sl, ", ")
+}
+
+// splitFontTag splits a font feature or variation setting into the
+// (unquoted) tag and the value string
+func splitFontTag(str string) (tag, val string) {
+	fs := strings.Fields(strings.TrimSpace(str))
+	if len(fs) == 0 {
+		return
+	}
+	tag = strings.Trim(fs[0], `"'`)
+	if len(fs) > 1 {
+		val = fs[1]
+	}
+	return
+}
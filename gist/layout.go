@@ -5,6 +5,10 @@
 package gist
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
@@ -36,26 +40,29 @@ var ScrollBarWidthDefault = float32(16)
 
 // Layout contains style preferences on the layout of the element.
 type Layout struct {
-	ZIndex         int         `xml:"z-index" desc:"prop: z-index = ordering factor for rendering depth -- lower numbers rendered first -- sort children according to this factor"`
-	AlignH         Align       `xml:"horizontal-align" desc:"prop: horizontal-align specifies the horizontal alignment of widget elements within a *vertical* layout container (has no effect within horizontal layouts -- use space / stretch elements instead).  For text layout, use text-align. This is not a standard css property."`
-	AlignV         Align       `xml:"vertical-align" desc:"prop: vertical-align specifies the vertical alignment of widget elements within a *horizontal* layout container (has no effect within vertical layouts -- use space / stretch elements instead).  For text layout, use text-vertical-align.  This is not a standard css property"`
-	PosX           units.Value `xml:"x" desc:"prop: x = horizontal position -- often superseded by layout but otherwise used"`
-	PosY           units.Value `xml:"y" desc:"prop: y = vertical position -- often superseded by layout but otherwise used"`
-	Width          units.Value `xml:"width" desc:"prop: width = specified size of element -- 0 if not specified"`
-	Height         units.Value `xml:"height" desc:"prop: height = specified size of element -- 0 if not specified"`
-	MaxWidth       units.Value `xml:"max-width" desc:"prop: max-width = specified maximum size of element -- 0  means just use other values, negative means stretch"`
-	MaxHeight      units.Value `xml:"max-height" desc:"prop: max-height = specified maximum size of element -- 0 means just use other values, negative means stretch"`
-	MinWidth       units.Value `xml:"min-width" desc:"prop: min-width = specified minimum size of element -- 0 if not specified"`
-	MinHeight      units.Value `xml:"min-height" desc:"prop: min-height = specified minimum size of element -- 0 if not specified"`
-	Margin         units.Value `xml:"margin" desc:"prop: margin = outer-most transparent space around box element -- todo: can be specified per side"`
-	Padding        units.Value `xml:"padding" desc:"prop: padding = transparent space around central content of box -- todo: if 4 values it is top, right, bottom, left; 3 is top, right&left, bottom; 2 is top & bottom, right and left"`
-	Overflow       Overflow    `xml:"overflow" desc:"prop: overflow = what to do with content that overflows -- default is Auto add of scrollbars as needed -- todo: can have separate -x -y values"`
-	Columns        int         `xml:"columns" alt:"grid-cols" desc:"prop: columns = number of columns to use in a grid layout -- used as a constraint in layout if individual elements do not specify their row, column positions"`
-	Row            int         `xml:"row" desc:"prop: row = specifies the row that this element should appear within a grid layout"`
-	Col            int         `xml:"col" desc:"prop: col = specifies the column that this element should appear within a grid layout"`
-	RowSpan        int         `xml:"row-span" desc:"prop: row-span = specifies the number of sequential rows that this element should occupy within a grid layout (todo: not currently supported)"`
-	ColSpan        int         `xml:"col-span" desc:"prop: col-span = specifies the number of sequential columns that this element should occupy within a grid layout"`
-	ScrollBarWidth units.Value `xml:"scrollbar-width" desc:"prop: scrollbar-width = width of a layout scrollbar"`
+	ZIndex         int          `xml:"z-index" desc:"prop: z-index = ordering factor for rendering depth -- lower numbers rendered first -- sort children according to this factor"`
+	AlignH         Align        `xml:"horizontal-align" desc:"prop: horizontal-align specifies the horizontal alignment of widget elements within a *vertical* layout container (has no effect within horizontal layouts -- use space / stretch elements instead).  For text layout, use text-align. This is not a standard css property."`
+	AlignV         Align        `xml:"vertical-align" desc:"prop: vertical-align specifies the vertical alignment of widget elements within a *horizontal* layout container (has no effect within vertical layouts -- use space / stretch elements instead).  For text layout, use text-vertical-align.  This is not a standard css property"`
+	PosX           units.Value  `xml:"x" desc:"prop: x = horizontal position -- often superseded by layout but otherwise used"`
+	PosY           units.Value  `xml:"y" desc:"prop: y = vertical position -- often superseded by layout but otherwise used"`
+	Width          units.Value  `xml:"width" desc:"prop: width = specified size of element -- 0 if not specified"`
+	Height         units.Value  `xml:"height" desc:"prop: height = specified size of element -- 0 if not specified"`
+	MaxWidth       units.Value  `xml:"max-width" desc:"prop: max-width = specified maximum size of element -- 0  means just use other values, negative means stretch"`
+	MaxHeight      units.Value  `xml:"max-height" desc:"prop: max-height = specified maximum size of element -- 0 means just use other values, negative means stretch"`
+	MinWidth       units.Value  `xml:"min-width" desc:"prop: min-width = specified minimum size of element -- 0 if not specified"`
+	MinHeight      units.Value  `xml:"min-height" desc:"prop: min-height = specified minimum size of element -- 0 if not specified"`
+	WidthSizing    ContentSizes `xml:"-" desc:"intrinsic sizing of the width, set by the min-content and max-content values of the width property, which size the element to its content instead of a specified width"`
+	HeightSizing   ContentSizes `xml:"-" desc:"intrinsic sizing of the height, set by the min-content and max-content values of the height property, which size the element to its content instead of a specified height"`
+	AspectRatio    float32      `xml:"aspect-ratio" desc:"prop: aspect-ratio = ratio of width to height that is kept when the element is sized during layout, as a number or w/h, e.g., 16/9 -- 0 (none) for no constraint, and AspectRatioAuto (auto) for the intrinsic ratio of widgets that have one, e.g., the image of a Bitmap"`
+	Margin         units.Value  `xml:"margin" desc:"prop: margin = outer-most transparent space around box element -- todo: can be specified per side"`
+	Padding        units.Value  `xml:"padding" desc:"prop: padding = transparent space around central content of box -- todo: if 4 values it is top, right, bottom, left; 3 is top, right&left, bottom; 2 is top & bottom, right and left"`
+	Overflow       Overflow     `xml:"overflow" desc:"prop: overflow = what to do with content that overflows -- default is Auto add of scrollbars as needed -- todo: can have separate -x -y values"`
+	Columns        int          `xml:"columns" alt:"grid-cols" desc:"prop: columns = number of columns to use in a grid layout -- used as a constraint in layout if individual elements do not specify their row, column positions"`
+	Row            int          `xml:"row" desc:"prop: row = specifies the row that this element should appear within a grid layout"`
+	Col            int          `xml:"col" desc:"prop: col = specifies the column that this element should appear within a grid layout"`
+	RowSpan        int          `xml:"row-span" desc:"prop: row-span = specifies the number of sequential rows that this element should occupy within a grid layout (todo: not currently supported)"`
+	ColSpan        int          `xml:"col-span" desc:"prop: col-span = specifies the number of sequential columns that this element should occupy within a grid layout"`
+	ScrollBarWidth units.Value  `xml:"scrollbar-width" desc:"prop: scrollbar-width = width of a layout scrollbar"`
 }
 
 func (ls *Layout) Defaults() {
@@ -68,6 +75,14 @@ func (ls *Layout) Defaults() {
 func (ls *Layout) SetStylePost(props ki.Props) {
 }
 
+// ContentSizing returns the intrinsic sizing for given dimension
+func (ls *Layout) ContentSizing(d mat32.Dims) ContentSizes {
+	if d == mat32.X {
+		return ls.WidthSizing
+	}
+	return ls.HeightSizing
+}
+
 // return the alignment for given dimension
 func (ls *Layout) AlignDim(d mat32.Dims) Align {
 	switch d {
@@ -101,13 +116,13 @@ func (ls *Layout) MinSizeDots() mat32.Vec2 {
 // ToDots runs ToDots on unit values, to compile down to raw pixels
 func (ly *Layout) ToDots(uc *units.Context) {
 	ly.PosX.ToDots(uc)
-	ly.PosY.ToDots(uc)
+	ly.PosY.ToDotsY(uc)
 	ly.Width.ToDots(uc)
-	ly.Height.ToDots(uc)
+	ly.Height.ToDotsY(uc)
 	ly.MaxWidth.ToDots(uc)
-	ly.MaxHeight.ToDots(uc)
+	ly.MaxHeight.ToDotsY(uc)
 	ly.MinWidth.ToDots(uc)
-	ly.MinHeight.ToDots(uc)
+	ly.MinHeight.ToDotsY(uc)
 	ly.Margin.ToDots(uc)
 	ly.Padding.ToDots(uc)
 	ly.ScrollBarWidth.ToDots(uc)
@@ -201,6 +216,81 @@ func (ev *Overflow) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON
 
 //go:generate stringer -type=Overflow
 
+// ContentSizes are the intrinsic sizes of the content of an element that
+// the width and height properties can specify, with the min-content and
+// max-content values, instead of a size
+type ContentSizes int32
+
+const (
+	// ContentSizeNone means the size is not intrinsic: it is specified, or
+	// 0 for no preference
+	ContentSizeNone ContentSizes = iota
+
+	// ContentSizeMin (min-content) is the smallest size of the content
+	// without overflow, e.g., the width of the longest word of wrapping
+	// text -- the element does not stretch beyond it
+	ContentSizeMin
+
+	// ContentSizeMax (max-content) is the natural size of the content,
+	// e.g., the width of text without wrapping -- the element does not
+	// stretch beyond it
+	ContentSizeMax
+
+	ContentSizesN
+)
+
+var KiT_ContentSizes = kit.Enums.AddEnumAltLower(ContentSizesN, kit.NotBitFlag, StylePropProps, "ContentSize")
+
+func (ev ContentSizes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *ContentSizes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+//go:generate stringer -type=ContentSizes
+
+// ContentSizeValues are the values of the width and height properties
+// for the ContentSizes
+var ContentSizeValues = map[string]ContentSizes{
+	"min-content": ContentSizeMin,
+	"max-content": ContentSizeMax,
+}
+
+// AspectRatioAuto is the AspectRatio for the intrinsic aspect ratio of
+// the content of an element (aspect-ratio: auto)
+const AspectRatioAuto = float32(-1)
+
+// ParseAspectRatio parses a value of the aspect-ratio property: a number,
+// a ratio as w/h or w:h, auto (AspectRatioAuto) or none (0)
+func ParseAspectRatio(str string) (float32, error) {
+	str = strings.TrimSpace(strings.ToLower(str))
+	switch str {
+	case "", "none":
+		return 0, nil
+	case "auto":
+		return AspectRatioAuto, nil
+	}
+	if i := strings.IndexAny(str, "/:"); i > 0 {
+		w, err := strconv.ParseFloat(strings.TrimSpace(str[:i]), 32)
+		if err != nil {
+			return 0, err
+		}
+		h, err := strconv.ParseFloat(strings.TrimSpace(str[i+1:]), 32)
+		if err != nil {
+			return 0, err
+		}
+		if h <= 0 || w <= 0 {
+			return 0, fmt.Errorf("gist.ParseAspectRatio: ratio must be positive: %v", str)
+		}
+		return float32(w / h), nil
+	}
+	ar, err := strconv.ParseFloat(str, 32)
+	if err != nil {
+		return 0, err
+	}
+	if ar < 0 {
+		return 0, fmt.Errorf("gist.ParseAspectRatio: ratio must be positive: %v", str)
+	}
+	return float32(ar), nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////
 // Layout Data for actually computing the layout
 
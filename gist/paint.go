@@ -28,6 +28,7 @@ type Paint struct {
 	FontStyle   Font          `desc:"font also has global opacity setting, along with generic color, background-color settings, which can be copied into stroke / fill as needed"`
 	TextStyle   Text          `desc:"font also has global opacity setting, along with generic color, background-color settings, which can be copied into stroke / fill as needed"`
 	VecEff      VectorEffects `xml:"vector-effect" desc:"prop: vector-effect = various rendering special effects settings"`
+	Blend       BlendModes    `xml:"mix-blend-mode" desc:"prop: mix-blend-mode = how the rendering of this element is blended with what is already rendered below it -- not inherited"`
 	XForm       mat32.Mat2    `xml:"transform" desc:"prop: transform = our additions to transform -- pushed to render state"`
 	UnContext   units.Context `xml:"-" desc:"units context -- parameters necessary for anchoring relative units"`
 	StyleSet    bool          `desc:"have the styles already been set?"`
@@ -57,6 +58,7 @@ func (pc *Paint) CopyStyleFrom(cp *Paint) {
 	pc.FontStyle = cp.FontStyle
 	pc.TextStyle = cp.TextStyle
 	pc.VecEff = cp.VecEff
+	pc.Blend = cp.Blend
 }
 
 // InheritFields from parent: Manual inheriting of values is much faster than
@@ -174,6 +176,43 @@ var KiT_VectorEffects = kit.Enums.AddEnumAltLower(VecEffN, kit.NotBitFlag, Style
 func (ev VectorEffects) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *VectorEffects) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
+// BlendModes are the ways in which the colors of an element (the source)
+// are combined with the colors already rendered below it (the backdrop),
+// as in the CSS mix-blend-mode property
+type BlendModes int32
+
+const (
+	// BlendNormal draws the source over the backdrop
+	BlendNormal BlendModes = iota
+
+	// BlendMultiply multiplies the source and backdrop colors, which
+	// always results in a darker color
+	BlendMultiply
+
+	// BlendScreen multiplies the complements of the source and backdrop
+	// colors, which always results in a lighter color
+	BlendScreen
+
+	// BlendOverlay multiplies or screens the colors depending on the
+	// backdrop color, preserving its highlights and shadows
+	BlendOverlay
+
+	// BlendDarken selects the darker of the source and backdrop colors
+	BlendDarken
+
+	// BlendLighten selects the lighter of the source and backdrop colors
+	BlendLighten
+
+	BlendModesN
+)
+
+//go:generate stringer -type=BlendModes
+
+var KiT_BlendModes = kit.Enums.AddEnumAltLower(BlendModesN, kit.NotBitFlag, StylePropProps, "Blend")
+
+func (ev BlendModes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *BlendModes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
 // IMPORTANT: any changes here must be updated below in StyleFillFuncs
 
 // Fill contains all the properties for filling a region
@@ -307,6 +307,29 @@ var StylePaintFuncs = map[string]StyleFunc{
 			}
 		}
 	},
+	"mix-blend-mode": func(obj any, key string, val any, par any, ctxt Context) {
+		pc := obj.(*Paint)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				pc.Blend = par.(*Paint).Blend
+			} else if init {
+				pc.Blend = BlendNormal
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case string:
+			kit.Enums.SetAnyEnumIfaceFromString(&pc.Blend, vt)
+		case BlendModes:
+			pc.Blend = vt
+		default:
+			if iv, ok := kit.ToInt(val); ok {
+				pc.Blend = BlendModes(iv)
+			} else {
+				StyleSetError(key, val)
+			}
+		}
+	},
 	"transform": func(obj any, key string, val any, par any, ctxt Context) {
 		pc := obj.(*Paint)
 		if inh, init := StyleInhInit(val, par); inh || init {
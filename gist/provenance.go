@@ -0,0 +1,109 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gist
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// StyleProvTrace turns on recording of style provenance: for each styled
+// widget, the source that last set each style field is recorded in a
+// StyleProv map, which can be viewed using Style.Dump (e.g., from the
+// GoGiEditor).  This slows styling considerably, so it is only for debugging.
+var StyleProvTrace = false
+
+// StyleProv records the provenance of computed style values: for each style
+// field path (e.g., Font.Color, Layout.Margin), the source that last set
+// that field, e.g., "type props: gi.Button" or "class func: danger
+// (main.go:42)".
+type StyleProv map[string]string
+
+// Record compares the before and after versions of a style, recording the
+// given source for each field that differs.
+func (sp StyleProv) Record(before, after *Style, src string) {
+	styleFieldsWalk(before, after, func(path string, bv, av reflect.Value) {
+		if !reflect.DeepEqual(bv.Interface(), av.Interface()) {
+			sp[path] = src
+		}
+	})
+}
+
+// FuncSource returns the name and source location of the given function
+// value, for use in recording provenance of style functions.
+func FuncSource(fun any) string {
+	fv := reflect.ValueOf(fun)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return "<nil>"
+	}
+	rf := runtime.FuncForPC(fv.Pointer())
+	if rf == nil {
+		return "<unknown>"
+	}
+	file, line := rf.FileLine(rf.Entry())
+	return fmt.Sprintf("%s (%s:%d)", rf.Name(), filepath.Base(file), line)
+}
+
+// Dump returns a listing of all the computed values in the style, one field
+// per line, along with the provenance of each value if prov is non-nil --
+// fields without recorded provenance are shown as defaults.
+func (s *Style) Dump(prov StyleProv) string {
+	var sb strings.Builder
+	var lines []string
+	styleFieldsWalk(s, s, func(path string, v, _ reflect.Value) {
+		ln := fmt.Sprintf("%s: %v", path, v.Interface())
+		if prov != nil {
+			if src, has := prov[path]; has {
+				ln += "\t<- " + src
+			} else {
+				ln += "\t<- default"
+			}
+		}
+		lines = append(lines, ln)
+	})
+	sort.Strings(lines)
+	for _, ln := range lines {
+		sb.WriteString(ln)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// styleFieldsWalk calls fun on each corresponding pair of exported fields in
+// two styles, descending one level into the sub-style structs (Layout, Font,
+// etc), and skipping internal state fields.
+func styleFieldsWalk(a, b *Style, fun func(path string, av, bv reflect.Value)) {
+	avs := reflect.ValueOf(a).Elem()
+	bvs := reflect.ValueOf(b).Elem()
+	typ := avs.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		switch sf.Name {
+		case "Template", "UnContext", "IsSet", "PropsNil":
+			continue
+		}
+		af := avs.Field(i)
+		bf := bvs.Field(i)
+		if sf.Type.Kind() != reflect.Struct || sf.Type.PkgPath() != typ.PkgPath() {
+			fun(sf.Name, af, bf)
+			continue
+		}
+		sty := sf.Type
+		for j := 0; j < sty.NumField(); j++ {
+			ssf := sty.Field(j)
+			if ssf.PkgPath != "" || ssf.Tag.Get("xml") == "-" {
+				continue
+			}
+			fun(sf.Name+"."+ssf.Name, af.Field(j), bf.Field(j))
+		}
+	}
+}
@@ -48,6 +48,7 @@ type Style struct {
 	Text          Text          `desc:"text parameters -- no xml prefix"`
 	Outline       Border        `xml:"outline" desc:"prop: outline = draw an outline around an element -- mostly same styles as border -- default to none"`
 	PointerEvents bool          `xml:"pointer-events" desc:"prop: pointer-events = does this element respond to pointer events -- default is true"`
+	Filter        string        `xml:"filter" desc:"prop: filter = CSS filter functions applied to the rendering of the element, e.g., blur(2px) drop-shadow(2px 2px 4px black) grayscale(100%) -- see girl.ParseFilters"`
 	UnContext     units.Context `xml:"-" desc:"units context -- parameters necessary for anchoring relative units"`
 	IsSet         bool          `desc:"has this style been set from object values yet?"`
 	PropsNil      bool          `desc:"set to true if parent node has no props -- allows optimization of styling"`
@@ -6,6 +6,7 @@ package gist
 
 import (
 	"log"
+	"strings"
 
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
@@ -168,6 +169,18 @@ var StyleStyleFuncs = map[string]StyleFunc{
 			s.PointerEvents = bv
 		}
 	},
+	"filter": func(obj any, key string, val any, par any, ctxt Context) {
+		s := obj.(*Style)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				s.Filter = par.(*Style).Filter
+			} else if init {
+				s.Filter = ""
+			}
+			return
+		}
+		s.Filter = kit.ToString(val)
+	},
 }
 
 /////////////////////////////////////////////////////////////////////////////////
@@ -264,11 +277,21 @@ var StyleLayoutFuncs = map[string]StyleFunc{
 		if inh, init := StyleInhInit(val, par); inh || init {
 			if inh {
 				ly.Width = par.(*Layout).Width
+				ly.WidthSizing = par.(*Layout).WidthSizing
 			} else if init {
 				ly.Width.Val = 0
+				ly.WidthSizing = ContentSizeNone
 			}
 			return
 		}
+		if str, ok := val.(string); ok {
+			if cs, has := ContentSizeValues[str]; has {
+				ly.Width.Val = 0
+				ly.WidthSizing = cs
+				return
+			}
+		}
+		ly.WidthSizing = ContentSizeNone
 		ly.Width.SetIFace(val, key)
 	},
 	"height": func(obj any, key string, val any, par any, ctxt Context) {
@@ -276,11 +299,21 @@ var StyleLayoutFuncs = map[string]StyleFunc{
 		if inh, init := StyleInhInit(val, par); inh || init {
 			if inh {
 				ly.Height = par.(*Layout).Height
+				ly.HeightSizing = par.(*Layout).HeightSizing
 			} else if init {
 				ly.Height.Val = 0
+				ly.HeightSizing = ContentSizeNone
 			}
 			return
 		}
+		if str, ok := val.(string); ok {
+			if cs, has := ContentSizeValues[str]; has {
+				ly.Height.Val = 0
+				ly.HeightSizing = cs
+				return
+			}
+		}
+		ly.HeightSizing = ContentSizeNone
 		ly.Height.SetIFace(val, key)
 	},
 	"max-width": func(obj any, key string, val any, par any, ctxt Context) {
@@ -378,6 +411,30 @@ var StyleLayoutFuncs = map[string]StyleFunc{
 			}
 		}
 	},
+	"aspect-ratio": func(obj any, key string, val any, par any, ctxt Context) {
+		ly := obj.(*Layout)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ly.AspectRatio = par.(*Layout).AspectRatio
+			} else if init {
+				ly.AspectRatio = 0
+			}
+			return
+		}
+		if str, ok := val.(string); ok {
+			if ar, err := ParseAspectRatio(str); err == nil {
+				ly.AspectRatio = ar
+			} else {
+				StyleSetError(key, val)
+			}
+			return
+		}
+		if fv, ok := kit.ToFloat32(val); ok {
+			ly.AspectRatio = fv
+		} else {
+			StyleSetError(key, val)
+		}
+	},
 	"columns": func(obj any, key string, val any, par any, ctxt Context) {
 		ly := obj.(*Layout)
 		if inh, init := StyleInhInit(val, par); inh || init {
@@ -680,6 +737,111 @@ var StyleFontFuncs = map[string]StyleFunc{
 			}
 		}
 	},
+	"font-variation-settings": func(obj any, key string, val any, par any, ctxt Context) {
+		fs := obj.(*Font)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				fs.Variations = par.(*Font).Variations
+			} else if init {
+				fs.Variations = FontVariations{}
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case FontVariations:
+			fs.Variations = vt
+		default:
+			if err := fs.Variations.SetString(kit.ToString(val)); err != nil {
+				StyleSetError(key, val)
+			}
+		}
+	},
+	"font-feature-settings": func(obj any, key string, val any, par any, ctxt Context) {
+		fs := obj.(*Font)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				fs.Features = par.(*Font).Features
+			} else if init {
+				fs.Features = nil
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case FontFeatures:
+			fs.Features = vt
+		default:
+			if err := fs.Features.SetString(kit.ToString(val)); err != nil {
+				StyleSetError(key, val)
+			}
+		}
+	},
+	"text-stroke-width": func(obj any, key string, val any, par any, ctxt Context) {
+		fs := obj.(*Font)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				fs.StrokeWidth = par.(*Font).StrokeWidth
+			} else if init {
+				fs.StrokeWidth = units.Value{}
+			}
+			return
+		}
+		fs.StrokeWidth.SetIFace(val, key)
+	},
+	"text-stroke-color": func(obj any, key string, val any, par any, ctxt Context) {
+		fs := obj.(*Font)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				fs.StrokeColor = par.(*Font).StrokeColor
+			} else if init {
+				fs.StrokeColor = Color{}
+			}
+			return
+		}
+		fs.StrokeColor.SetIFace(val, ctxt, key)
+	},
+	"text-stroke": func(obj any, key string, val any, par any, ctxt Context) {
+		fs := obj.(*Font)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				fs.StrokeWidth = par.(*Font).StrokeWidth
+				fs.StrokeColor = par.(*Font).StrokeColor
+			} else if init {
+				fs.StrokeWidth = units.Value{}
+				fs.StrokeColor = Color{}
+			}
+			return
+		}
+		// width, then optional color, e.g., 2px red
+		flds := strings.Fields(kit.ToString(val))
+		if len(flds) == 0 {
+			StyleSetError(key, val)
+			return
+		}
+		fs.StrokeWidth.SetString(flds[0])
+		fs.StrokeColor = Color{}
+		if len(flds) > 1 {
+			fs.StrokeColor.SetIFace(strings.Join(flds[1:], " "), ctxt, key)
+		}
+	},
+	"text-shadow": func(obj any, key string, val any, par any, ctxt Context) {
+		fs := obj.(*Font)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				fs.TextShadow = par.(*Font).TextShadow
+			} else if init {
+				fs.TextShadow = nil
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case TextShadows:
+			fs.TextShadow = vt
+		default:
+			if err := fs.TextShadow.SetString(kit.ToString(val)); err != nil {
+				StyleSetError(key, val)
+			}
+		}
+	},
 }
 
 /////////////////////////////////////////////////////////////////////////////////
@@ -817,6 +979,29 @@ var StyleTextFuncs = map[string]StyleFunc{
 			}
 		}
 	},
+	"word-break": func(obj any, key string, val any, par any, ctxt Context) {
+		ts := obj.(*Text)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ts.WordBreak = par.(*Text).WordBreak
+			} else if init {
+				ts.WordBreak = WordBreakNormal
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case string:
+			kit.Enums.SetAnyEnumIfaceFromString(&ts.WordBreak, strings.ReplaceAll(vt, "-", "")) // e.g., break-all
+		case WordBreaks:
+			ts.WordBreak = vt
+		default:
+			if iv, ok := kit.ToInt(val); ok {
+				ts.WordBreak = WordBreaks(iv)
+			} else {
+				StyleSetError(key, val)
+			}
+		}
+	},
 	"unicode-bidi": func(obj any, key string, val any, par any, ctxt Context) {
 		ts := obj.(*Text)
 		if inh, init := StyleInhInit(val, par); inh || init {
@@ -875,7 +1060,16 @@ var StyleTextFuncs = map[string]StyleFunc{
 		}
 		switch vt := val.(type) {
 		case string:
-			kit.Enums.SetAnyEnumIfaceFromString(&ts.WritingMode, vt)
+			switch vt {
+			case "horizontal-tb":
+				ts.WritingMode = LRTB
+			case "vertical-rl":
+				ts.WritingMode = TBRL
+			case "vertical-lr":
+				ts.WritingMode = TBLR
+			default: // svg 1.1 names, e.g., tb-rl
+				kit.Enums.SetAnyEnumIfaceFromString(&ts.WritingMode, strings.ReplaceAll(vt, "-", ""))
+			}
 		case TextDirections:
 			ts.WritingMode = vt
 		default:
@@ -952,6 +1146,25 @@ var StyleTextFuncs = map[string]StyleFunc{
 			ts.TabSize = int(iv)
 		}
 	},
+	"tab-stops": func(obj any, key string, val any, par any, ctxt Context) {
+		ts := obj.(*Text)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				ts.TabStops = par.(*Text).TabStops
+			} else if init {
+				ts.TabStops = nil
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case TabStops:
+			ts.TabStops = vt
+		default:
+			if err := ts.TabStops.SetString(kit.ToString(val)); err != nil {
+				StyleSetError(key, val)
+			}
+		}
+	},
 }
 
 /////////////////////////////////////////////////////////////////////////////////
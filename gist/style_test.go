@@ -36,3 +36,100 @@ func TestStyle(t *testing.T) {
 	fmt.Printf("style box-shadow.v-offset: %v\n", s.BoxShadow.VOffset)
 	fmt.Printf("style border-style: %v\n", s.Border.Style)
 }
+
+func TestStyleClasses(t *testing.T) {
+	AddClass("danger", func(s *Style) {
+		s.Font.Weight = WeightBold
+	})
+	AddClass("Large", func(s *Style) {
+		s.Font.Size = units.NewPt(24)
+	})
+	defer DeleteClass("danger")
+	defer DeleteClass("large")
+
+	s := NewStyle()
+	if !s.ApplyClasses("other  DANGER large") {
+		t.Errorf("ApplyClasses did not apply any classes\n")
+	}
+	if s.Font.Weight != WeightBold {
+		t.Errorf("danger class not applied: weight = %v\n", s.Font.Weight)
+	}
+	if s.Font.Size != units.NewPt(24) {
+		t.Errorf("large class not applied: size = %v\n", s.Font.Size)
+	}
+	if s.ApplyClasses("none") {
+		t.Errorf("ApplyClasses applied unregistered class\n")
+	}
+}
+
+func TestStyleProv(t *testing.T) {
+	prov := make(StyleProv)
+	prev := NewStyle()
+	s := prev
+	props := ki.Props{"color": "red", "margin": "2px"}
+	s.SetStyleProps(nil, props, nil)
+	prov.Record(&prev, &s, "props")
+	if prov["Font.Color"] != "props" || prov["Layout.Margin"] != "props" {
+		t.Errorf("provenance not recorded: %v\n", prov)
+	}
+	if _, has := prov["Font.Size"]; has {
+		t.Errorf("provenance recorded for unchanged field: %v\n", prov)
+	}
+}
+
+func TestLayoutSizing(t *testing.T) {
+	props := make(ki.Props)
+	props["width"] = "max-content"
+	props["height"] = "min-content"
+	props["aspect-ratio"] = "16/9"
+	var s, p Style
+	s.Defaults()
+	p.Defaults()
+	s.SetStyleProps(&p, props, nil)
+	if s.Layout.WidthSizing != ContentSizeMax || s.Layout.HeightSizing != ContentSizeMin {
+		t.Errorf("content sizing: %v %v\n", s.Layout.WidthSizing, s.Layout.HeightSizing)
+	}
+	if s.Layout.AspectRatio != float32(16.0/9.0) {
+		t.Errorf("aspect-ratio: %v\n", s.Layout.AspectRatio)
+	}
+
+	props["width"] = "20em"
+	props["aspect-ratio"] = "auto"
+	s.SetStyleProps(&p, props, nil)
+	if s.Layout.WidthSizing != ContentSizeNone || s.Layout.Width.Val != 20 {
+		t.Errorf("width: %v %v\n", s.Layout.WidthSizing, s.Layout.Width)
+	}
+	if s.Layout.AspectRatio != AspectRatioAuto {
+		t.Errorf("aspect-ratio auto: %v\n", s.Layout.AspectRatio)
+	}
+
+	for str, ar := range map[string]float32{"2": 2, "4:3": 4.0 / 3.0, "none": 0} {
+		got, err := ParseAspectRatio(str)
+		if err != nil || got != ar {
+			t.Errorf("ParseAspectRatio(%q) = %v, %v, want %v\n", str, got, err, ar)
+		}
+	}
+	if _, err := ParseAspectRatio("1/0"); err == nil {
+		t.Errorf("ParseAspectRatio(1/0) should fail\n")
+	}
+}
+
+func TestTabStops(t *testing.T) {
+	var tss TabStops
+	err := tss.SetString(`3in center, 1in, 5in Decimal, 4in end`)
+	if err != nil {
+		t.Error(err)
+	}
+	cor := "1in, 3in center, 4in right, 5in decimal"
+	if tss.String() != cor {
+		t.Errorf("TabStops: %v != correct: %v\n", tss.String(), cor)
+	}
+	if err := tss.SetString(`1in middle`); err == nil {
+		t.Errorf("TabStops: expected error for invalid alignment\n")
+	}
+	ts := Text{}
+	StyleTextFuncs["tab-stops"](&ts, "tab-stops", "20px, 40px right", nil, nil)
+	if len(ts.TabStops) != 2 || ts.TabStops[1].Align != TabRight {
+		t.Errorf("TabStops: tab-stops property not set: %v\n", ts.TabStops)
+	}
+}
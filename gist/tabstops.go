@@ -0,0 +1,132 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/kit"
+)
+
+// TabStop is one explicit tab stop, as set by the tab-stops property: a
+// position from the start of the line, and how the text after a tab that
+// advances to it is aligned there
+type TabStop struct {
+	Pos   units.Value `desc:"position of the stop, from the start of the line"`
+	Align TabAligns   `desc:"how the text after the tab, up to the next tab or the end of the line, is aligned at the stop"`
+}
+
+// String returns the CSS representation of the stop, e.g., 2in decimal
+func (ts *TabStop) String() string {
+	if ts.Align == TabLeft {
+		return ts.Pos.String()
+	}
+	return ts.Pos.String() + " " + strings.TrimPrefix(strings.ToLower(ts.Align.String()), "tab")
+}
+
+// TabStops are explicit tab stops, as set by the tab-stops property, e.g.,
+// `1in, 3in center, 5in decimal`, sorted by position -- tabs past the last
+// stop advance to the next multiple of tab-size as usual.
+type TabStops []TabStop
+
+// SetString sets the stops from a tab-stops string: a comma-separated list
+// of positions, each followed by an optional alignment: left (or start,
+// the default), center, right (or end), or decimal -- "none" resets to no
+// stops.
+func (tss *TabStops) SetString(str string) error {
+	*tss = nil
+	str = strings.TrimSpace(str)
+	if str == "" || str == "none" {
+		return nil
+	}
+	for _, it := range strings.Split(str, ",") {
+		flds := strings.Fields(it)
+		if len(flds) == 0 || len(flds) > 2 {
+			return fmt.Errorf("gist.TabStops: invalid tab stop: %q", it)
+		}
+		var ts TabStop
+		ts.Pos.SetString(flds[0])
+		if len(flds) == 2 {
+			switch strings.ToLower(flds[1]) {
+			case "left", "start":
+				ts.Align = TabLeft
+			case "center":
+				ts.Align = TabCenter
+			case "right", "end":
+				ts.Align = TabRight
+			case "decimal":
+				ts.Align = TabDecimal
+			default:
+				return fmt.Errorf("gist.TabStops: invalid alignment in: %q", it)
+			}
+		}
+		*tss = append(*tss, ts)
+	}
+	sort.SliceStable(*tss, func(i, j int) bool {
+		return (*tss)[i].Pos.Val < (*tss)[j].Pos.Val
+	})
+	return nil
+}
+
+// String returns the tab-stops representation
+func (tss TabStops) String() string {
+	if len(tss) == 0 {
+		return "none"
+	}
+	sl := make([]string, len(tss))
+	for i := range tss {
+		sl[i] = tss[i].String()
+	}
+	return strings.Join(sl, ", ")
+}
+
+// ToDots runs ToDots on unit values, to compile down to raw pixels
+func (tss TabStops) ToDots(uc *units.Context) {
+	for i := range tss {
+		tss[i].Pos.ToDots(uc)
+	}
+}
+
+// Next returns the first stop after given position, and false if there is
+// none
+func (tss TabStops) Next(pos float32) (TabStop, bool) {
+	for _, ts := range tss {
+		if ts.Pos.Dots > pos {
+			return ts, true
+		}
+	}
+	return TabStop{}, false
+}
+
+// TabAligns are the alignments of text at a tab stop
+type TabAligns int32
+
+const (
+	// TabLeft starts the text at the stop
+	TabLeft TabAligns = iota
+
+	// TabCenter centers the text on the stop
+	TabCenter
+
+	// TabRight ends the text at the stop
+	TabRight
+
+	// TabDecimal puts the first decimal point (.) of the text at the stop,
+	// e.g., to align a column of numbers -- text without one ends at the
+	// stop, as for TabRight
+	TabDecimal
+
+	TabAlignsN
+)
+
+//go:generate stringer -type=TabAligns
+
+var KiT_TabAligns = kit.Enums.AddEnumAltLower(TabAlignsN, kit.NotBitFlag, StylePropProps, "Tab")
+
+func (ev TabAligns) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *TabAligns) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
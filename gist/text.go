@@ -23,6 +23,7 @@ type Text struct {
 	WordSpacing      units.Value    `xml:"word-spacing" inherit:"true" desc:"prop: word-spacing (inherited) = extra space to add between words"`
 	LineHeight       float32        `xml:"line-height" inherit:"true" desc:"prop: line-height (inherited) = specified height of a line of text, in proportion to default font height, 0 = 1 = normal (todo: specific values such as pixels are not supported, in order to properly support percentage) -- text is centered within the overall lineheight"`
 	WhiteSpace       WhiteSpaces    `xml:"white-space" desc:"prop: white-space (*not* inherited) = specifies how white space is processed, and how lines are wrapped"`
+	WordBreak        WordBreaks     `xml:"word-break" inherit:"true" desc:"prop: word-break (inherited) = where lines can be wrapped within words, e.g., between the characters of Chinese and Japanese text, which does not use spaces between words"`
 	UnicodeBidi      UnicodeBidi    `xml:"unicode-bidi" inherit:"true" desc:"prop: unicode-bidi (inherited) = determines how to treat unicode bidirectional information"`
 	Direction        TextDirections `xml:"direction" inherit:"true" desc:"prop: direction (inherited) = direction of text -- only applicable for unicode-bidi = bidi-override or embed -- applies to all text elements"`
 	WritingMode      TextDirections `xml:"writing-mode" inherit:"true" desc:"prop: writing-mode (inherited) = overall writing mode -- only for text elements, not tspan"`
@@ -31,11 +32,11 @@ type Text struct {
 	Indent           units.Value    `xml:"text-indent" inherit:"true" desc:"prop: text-indent (inherited) = how much to indent the first line in a paragraph"`
 	ParaSpacing      units.Value    `xml:"para-spacing" inherit:"true" desc:"prop: para-spacing (inherited) = extra spacing between paragraphs -- copied from Style.Layout.Margin per CSS spec if that is non-zero, else can be set directly with para-spacing"`
 	TabSize          int            `xml:"tab-size" inherit:"true" desc:"prop: tab-size (inherited) = tab size, in number of characters"`
+	TabStops         TabStops       `xml:"tab-stops" inherit:"true" desc:"prop: tab-stops (inherited) = explicit tab stop positions from the start of the line, each with an optional alignment of the text after the tab (left, center, right, decimal), e.g., 1in, 3in center, 5in decimal -- tabs past the last stop use tab-size"`
 	// todo:
 	// page-break options
 	// text-justify  inherit:"true" -- how to justify text
 	// text-overflow -- clip, ellipsis, string..
-	// text-shadow  inherit:"true"
 	// text-transform --  inherit:"true" uppercase, lowercase, capitalize
 	// user-select -- can user select text?
 }
@@ -55,6 +56,7 @@ func (ts *Text) ToDots(uc *units.Context) {
 	ts.WordSpacing.ToDots(uc)
 	ts.Indent.ToDots(uc)
 	ts.ParaSpacing.ToDots(uc)
+	ts.TabStops.ToDots(uc)
 }
 
 // SetStylePost applies any updates after generic xml-tag property setting
@@ -70,6 +72,7 @@ func (ts *Text) InheritFields(par *Text) {
 	ts.WordSpacing = par.WordSpacing
 	ts.LineHeight = par.LineHeight
 	// ts.WhiteSpace = par.WhiteSpace // todo: we can't inherit this b/c label base default then gets overwritten
+	ts.WordBreak = par.WordBreak
 	ts.UnicodeBidi = par.UnicodeBidi
 	ts.Direction = par.Direction
 	ts.WritingMode = par.WritingMode
@@ -78,6 +81,7 @@ func (ts *Text) InheritFields(par *Text) {
 	ts.Indent = par.Indent
 	ts.ParaSpacing = par.ParaSpacing
 	ts.TabSize = par.TabSize
+	ts.TabStops = par.TabStops
 }
 
 // EffLineHeight returns the effective line height (taking into account 0 value)
@@ -145,6 +149,11 @@ const (
 	TB
 	LTR
 	RTL
+
+	// TBLR is vertical writing, with lines (columns) advancing from left to
+	// right: writing-mode: vertical-lr -- TBRL (or TB) is vertical-rl
+	TBLR
+
 	TextDirectionsN
 )
 
@@ -210,6 +219,36 @@ var KiT_WhiteSpaces = kit.Enums.AddEnumAltLower(WhiteSpacesN, kit.NotBitFlag, St
 func (ev WhiteSpaces) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *WhiteSpaces) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
+// WordBreaks determine where lines can be wrapped within words, in
+// addition to at white space, per the CSS word-break property
+type WordBreaks int32
+
+const (
+	// WordBreakNormal breaks lines at white space, and between the
+	// characters of Chinese, Japanese and Korean text, except before closing
+	// and after opening punctuation.  Text in scripts that do not use spaces
+	// between words and have no such characters to break at, e.g., Thai, is
+	// broken anywhere if it does not fit otherwise.
+	WordBreakNormal WordBreaks = iota
+
+	// WordBreakBreakAll breaks lines between any two characters of words,
+	// as needed to fill the lines
+	WordBreakBreakAll
+
+	// WordBreakKeepAll only breaks lines at white space, including in
+	// Chinese, Japanese and Korean text
+	WordBreakKeepAll
+
+	WordBreaksN
+)
+
+//go:generate stringer -type=WordBreaks
+
+var KiT_WordBreaks = kit.Enums.AddEnumAltLower(WordBreaksN, kit.NotBitFlag, StylePropProps, "WordBreak")
+
+func (ev WordBreaks) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *WordBreaks) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
 // HasWordWrap returns true if current white space option supports word wrap
 func (ts *Text) HasWordWrap() bool {
 	switch ts.WhiteSpace {
@@ -224,6 +263,12 @@ func (ts *Text) HasWordWrap() bool {
 	}
 }
 
+// IsVertical returns true if the writing mode is vertical, with lines
+// (columns) running top to bottom: TBRL, TBLR or TB
+func (ts *Text) IsVertical() bool {
+	return ts.WritingMode == TBRL || ts.WritingMode == TBLR || ts.WritingMode == TB
+}
+
 // HasPre returns true if current white space option preserves existing
 // whitespace (or at least requires that parser in case of PreLine, which is
 // intermediate)
@@ -23,12 +23,14 @@ func _() {
 	_ = x[DecoSuper-7]
 	_ = x[DecoSub-8]
 	_ = x[DecoBgColor-9]
-	_ = x[TextDecorationsN-10]
+	_ = x[DecoStroke-10]
+	_ = x[DecoShadow-11]
+	_ = x[TextDecorationsN-12]
 }
 
-const _TextDecorations_name = "DecoNoneDecoUnderlineDecoOverlineDecoLineThroughDecoBlinkDecoDottedUnderlineDecoParaStartDecoSuperDecoSubDecoBgColorTextDecorationsN"
+const _TextDecorations_name = "DecoNoneDecoUnderlineDecoOverlineDecoLineThroughDecoBlinkDecoDottedUnderlineDecoParaStartDecoSuperDecoSubDecoBgColorDecoStrokeDecoShadowTextDecorationsN"
 
-var _TextDecorations_index = [...]uint8{0, 8, 21, 33, 48, 57, 76, 89, 98, 105, 116, 132}
+var _TextDecorations_index = [...]uint8{0, 8, 21, 33, 48, 57, 76, 89, 98, 105, 116, 126, 136, 152}
 
 func (i TextDecorations) String() string {
 	if i < 0 || i >= TextDecorations(len(_TextDecorations_index)-1) {
@@ -21,12 +21,13 @@ func _() {
 	_ = x[TB-5]
 	_ = x[LTR-6]
 	_ = x[RTL-7]
-	_ = x[TextDirectionsN-8]
+	_ = x[TBLR-8]
+	_ = x[TextDirectionsN-9]
 }
 
-const _TextDirections_name = "LRTBRLTBTBRLLRRLTBLTRRTLTextDirectionsN"
+const _TextDirections_name = "LRTBRLTBTBRLLRRLTBLTRRTLTBLRTextDirectionsN"
 
-var _TextDirections_index = [...]uint8{0, 4, 8, 12, 14, 16, 18, 21, 24, 39}
+var _TextDirections_index = [...]uint8{0, 4, 8, 12, 14, 16, 18, 21, 24, 28, 43}
 
 func (i TextDirections) String() string {
 	if i < 0 || i >= TextDirections(len(_TextDirections_index)-1) {
@@ -0,0 +1,127 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goki/gi/units"
+)
+
+// TextShadow is one shadow of text, as set by the text-shadow property: a
+// copy of the glyphs drawn behind them, offset, blurred, and in the color
+// of the shadow
+type TextShadow struct {
+	HOffset units.Value `xml:".h-offset" desc:"prop: .h-offset = horizontal offset of shadow -- positive = right side, negative = left side"`
+	VOffset units.Value `xml:".v-offset" desc:"prop: .v-offset = vertical offset of shadow -- positive = below, negative = above"`
+	Blur    units.Value `xml:".blur" desc:"prop: .blur = blur radius -- higher numbers = more blurry -- the standard deviation of the gaussian blur is half of it, as in CSS"`
+	Color   Color       `xml:".color" desc:"prop: .color = color of the shadow -- the text color if not set"`
+}
+
+// ToDots runs ToDots on unit values, to compile down to raw pixels
+func (ts *TextShadow) ToDots(uc *units.Context) {
+	ts.HOffset.ToDots(uc)
+	ts.VOffset.ToDots(uc)
+	ts.Blur.ToDots(uc)
+}
+
+// String returns the CSS representation of the shadow, e.g., 2px 2px 4px red
+func (ts *TextShadow) String() string {
+	str := ts.HOffset.String() + " " + ts.VOffset.String()
+	if ts.Blur.Val != 0 {
+		str += " " + ts.Blur.String()
+	}
+	if !ts.Color.IsNil() {
+		str += " " + ts.Color.String()
+	}
+	return str
+}
+
+// TextShadows are the shadows of text, as set by the text-shadow property,
+// e.g., `1px 1px 2px black, 0 0 8px blue` -- the first shadow is drawn on
+// top, as in CSS.
+type TextShadows []TextShadow
+
+// SetString sets the shadows from a CSS text-shadow string: a
+// comma-separated list of shadows, each with a horizontal and vertical
+// offset, an optional blur radius, and an optional color, which can also
+// come first -- "none" resets to no shadows.
+func (tss *TextShadows) SetString(str string) error {
+	*tss = nil
+	str = strings.TrimSpace(str)
+	if str == "" || str == "none" {
+		return nil
+	}
+	for _, it := range splitTopLevel(str, ',') {
+		var ts TextShadow
+		var lens, clrs []string
+		for _, fld := range splitTopLevel(it, ' ') {
+			if fld == "" {
+				continue
+			}
+			if c := fld[0]; c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.' {
+				lens = append(lens, fld)
+			} else {
+				clrs = append(clrs, fld)
+			}
+		}
+		if len(lens) < 2 || len(lens) > 3 {
+			return fmt.Errorf("gist.TextShadows: invalid shadow: %q", it)
+		}
+		ts.HOffset.SetString(lens[0])
+		ts.VOffset.SetString(lens[1])
+		if len(lens) == 3 {
+			ts.Blur.SetString(lens[2])
+		}
+		if len(clrs) > 0 && !strings.EqualFold(clrs[0], "currentcolor") {
+			if err := ts.Color.SetString(strings.Join(clrs, " "), nil); err != nil {
+				return fmt.Errorf("gist.TextShadows: invalid color in %q: %v", it, err)
+			}
+		}
+		*tss = append(*tss, ts)
+	}
+	return nil
+}
+
+// String returns the CSS text-shadow representation
+func (tss TextShadows) String() string {
+	if len(tss) == 0 {
+		return "none"
+	}
+	sl := make([]string, len(tss))
+	for i := range tss {
+		sl[i] = tss[i].String()
+	}
+	return strings.Join(sl, ", ")
+}
+
+// ToDots runs ToDots on unit values, to compile down to raw pixels
+func (tss TextShadows) ToDots(uc *units.Context) {
+	for i := range tss {
+		tss[i].ToDots(uc)
+	}
+}
+
+// splitTopLevel splits given string at sep, except within parentheses,
+// e.g., the commas of rgb(0, 0, 0) -- the fields are trimmed of space
+func splitTopLevel(str string, sep byte) []string {
+	var flds []string
+	depth, st := 0, 0
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				flds = append(flds, strings.TrimSpace(str[st:i]))
+				st = i + 1
+			}
+		}
+	}
+	return append(flds, strings.TrimSpace(str[st:]))
+}
@@ -0,0 +1,534 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//////////////////////////////////////////////////////////////////////////////
+//    Archive browsing
+
+// ArchiveBrowsing determines whether archive files (zip, tar, tar.gz) are
+// shown as folders in the FileTree and FileView, with the files within
+// them accessed through paths that continue on from the path of the
+// archive file, e.g., /home/me/src.zip/src/main.go.  The files in
+// archives are read-only.
+var ArchiveBrowsing = true
+
+// ArchiveExts are the extensions of the archive files that can be browsed
+var ArchiveExts = []string{".zip", ".jar", ".tar", ".tar.gz", ".tgz"}
+
+// ArchiveExt returns the archive extension of given file name, from
+// ArchiveExts, or "" if it is not an archive
+func ArchiveExt(fname string) string {
+	lfn := strings.ToLower(fname)
+	for _, ext := range ArchiveExts {
+		if strings.HasSuffix(lfn, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// IsArchiveFile returns true if given file name has one of the ArchiveExts
+func IsArchiveFile(fname string) bool {
+	return ArchiveExt(fname) != ""
+}
+
+// SplitArchivePath splits given path into the path of the archive file
+// that it is in or is, and the path within the archive, in the slash
+// separated form of io/fs, which is "." for the archive file itself.
+// Returns false if the path does not go through an archive file, or if
+// ArchiveBrowsing is off.
+func SplitArchivePath(fpath string) (arc, inner string, ok bool) {
+	if !ArchiveBrowsing {
+		return "", "", false
+	}
+	fpath = filepath.Clean(fpath)
+	for i := 1; i <= len(fpath); i++ {
+		if i < len(fpath) && fpath[i] != filepath.Separator {
+			continue
+		}
+		pre := fpath[:i]
+		if !IsArchiveFile(pre) {
+			continue
+		}
+		if st, err := os.Stat(pre); err != nil || !st.Mode().IsRegular() {
+			continue
+		}
+		inner = "."
+		if i < len(fpath) {
+			inner = filepath.ToSlash(fpath[i+1:])
+		}
+		return pre, inner, true
+	}
+	return "", "", false
+}
+
+// InArchive returns true if given path is of a file within an archive
+func InArchive(fpath string) bool {
+	_, inner, ok := SplitArchivePath(fpath)
+	return ok && inner != "."
+}
+
+// archiveFS is an open archive in the archiveFSs cache
+type archiveFS struct {
+	fsys    fs.FS
+	modTime time.Time
+	closer  io.Closer
+	used    uint64
+}
+
+// MaxArchiveFSs is the maximum number of archives that ArchiveFS keeps
+// open -- beyond it, the least recently used one is closed
+var MaxArchiveFSs = 8
+
+var (
+	archiveFSs    = map[string]*archiveFS{}
+	archiveFSsUse uint64
+	archiveFSsMu  sync.Mutex
+)
+
+// close closes the archive, if it needs closing
+func (af *archiveFS) close() {
+	if af.closer != nil {
+		af.closer.Close()
+	}
+}
+
+// ArchiveFS returns the contents of given archive file as an fs.FS -- the
+// archives are kept open, up to MaxArchiveFSs of them, and re-opened when
+// they are modified, so the fs.FS must be used right away, not kept.  Only the index of the files in tar archives is kept
+// in memory: their contents are read from the archive file when opened,
+// which requires decompressing the archive up to the file if it is
+// compressed.
+func ArchiveFS(arc string) (fs.FS, error) {
+	st, err := os.Stat(arc)
+	if err != nil {
+		return nil, err
+	}
+	archiveFSsMu.Lock()
+	defer archiveFSsMu.Unlock()
+	archiveFSsUse++
+	if af, has := archiveFSs[arc]; has {
+		if af.modTime.Equal(st.ModTime()) {
+			af.used = archiveFSsUse
+			return af.fsys, nil
+		}
+		af.close()
+		delete(archiveFSs, arc)
+	}
+	af := &archiveFS{modTime: st.ModTime(), used: archiveFSsUse}
+	switch ArchiveExt(arc) {
+	case ".zip", ".jar":
+		zr, err := zip.OpenReader(arc)
+		if err != nil {
+			return nil, err
+		}
+		af.fsys = zr
+		af.closer = zr
+	default:
+		tf, err := readTarFS(arc)
+		if err != nil {
+			return nil, err
+		}
+		af.fsys = tf
+	}
+	for len(archiveFSs) >= MaxArchiveFSs && len(archiveFSs) > 0 {
+		lru := ""
+		for fn, of := range archiveFSs {
+			if lru == "" || of.used < archiveFSs[lru].used {
+				lru = fn
+			}
+		}
+		archiveFSs[lru].close()
+		delete(archiveFSs, lru)
+	}
+	archiveFSs[arc] = af
+	return af.fsys, nil
+}
+
+// StatPath returns the file info for given path, which can be within an
+// archive, otherwise using os.Stat
+func StatPath(fpath string) (fs.FileInfo, error) {
+	if arc, inner, ok := SplitArchivePath(fpath); ok && inner != "." {
+		fsys, err := ArchiveFS(arc)
+		if err != nil {
+			return nil, err
+		}
+		return fs.Stat(fsys, inner)
+	}
+	return os.Stat(fpath)
+}
+
+// ReadDirPath returns the entries of given directory, which can be an
+// archive or a directory within one, otherwise using os.ReadDir
+func ReadDirPath(dir string) ([]fs.DirEntry, error) {
+	if arc, inner, ok := SplitArchivePath(dir); ok {
+		fsys, err := ArchiveFS(arc)
+		if err != nil {
+			return nil, err
+		}
+		return fs.ReadDir(fsys, inner)
+	}
+	return os.ReadDir(dir)
+}
+
+// ReadFilePath returns the contents of given file, which can be within an
+// archive, otherwise using os.ReadFile
+func ReadFilePath(fpath string) ([]byte, error) {
+	if arc, inner, ok := SplitArchivePath(fpath); ok && inner != "." {
+		fsys, err := ArchiveFS(arc)
+		if err != nil {
+			return nil, err
+		}
+		return fs.ReadFile(fsys, inner)
+	}
+	return os.ReadFile(fpath)
+}
+
+// ExtractArchivePath extracts the file or directory at given path within
+// an archive (or the whole archive for the archive file itself) into given
+// destination directory, keeping the paths of the files within the archive
+func ExtractArchivePath(fpath, dest string) error {
+	arc, inner, ok := SplitArchivePath(fpath)
+	if !ok {
+		return fmt.Errorf("giv.ExtractArchivePath: not an archive path: %v", fpath)
+	}
+	fsys, err := ArchiveFS(arc)
+	if err != nil {
+		return err
+	}
+	dest = filepath.Clean(dest)
+	return fs.WalkDir(fsys, inner, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return os.MkdirAll(dest, 0755)
+		}
+		trg, err := extractTarget(dest, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(trg, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(trg), 0755); err != nil {
+			return err
+		}
+		perm := fs.FileMode(0644)
+		if info, err := d.Info(); err == nil && info.Mode().Perm() != 0 {
+			perm = info.Mode().Perm()
+		}
+		return os.WriteFile(trg, b, perm)
+	})
+}
+
+// extractTarget returns the path in given (clean) destination directory
+// of the file at given path in an archive, which must not escape it, e.g.,
+// with .. elements ("zip slip")
+func extractTarget(dest, p string) (string, error) {
+	trg := filepath.Join(dest, filepath.FromSlash(p))
+	if !strings.HasPrefix(trg, dest+string(filepath.Separator)) {
+		return "", fmt.Errorf("giv.ExtractArchivePath: invalid path in archive: %v", p)
+	}
+	return trg, nil
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//    tarFS
+
+// tarFS is an fs.FS with the contents of a tar archive, which does not
+// support random access: the index of its files is read when it is opened,
+// and their contents are read from the archive file when they are opened
+type tarFS struct {
+	fname string
+	gz    bool
+	ents  map[string]*tarEnt
+}
+
+// tarEnt is a file or directory in a tarFS, which is its own fs.FileInfo
+// and fs.DirEntry
+type tarEnt struct {
+	name string
+	mode fs.FileMode
+	mod  time.Time
+	size int64
+	off  int64
+	hdr  int
+	kids []*tarEnt
+}
+
+func (te *tarEnt) Name() string               { return te.name }
+func (te *tarEnt) Size() int64                { return te.size }
+func (te *tarEnt) Mode() fs.FileMode          { return te.mode }
+func (te *tarEnt) ModTime() time.Time         { return te.mod }
+func (te *tarEnt) IsDir() bool                { return te.mode.IsDir() }
+func (te *tarEnt) Sys() any                   { return nil }
+func (te *tarEnt) Type() fs.FileMode          { return te.mode.Type() }
+func (te *tarEnt) Info() (fs.FileInfo, error) { return te, nil }
+
+// countReader counts the bytes read from a reader
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countReader) Read(b []byte) (int, error) {
+	n, err := cr.r.Read(b)
+	cr.n += int64(n)
+	return n, err
+}
+
+// openTar opens given tar file, which is gzip compressed if gz, returning
+// the file, to close after reading, and the reader of its uncompressed
+// contents
+func openTar(fname string, gz bool) (*os.File, io.Reader, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !gz {
+		return f, f, nil
+	}
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, gr, nil
+}
+
+// readTarFS reads the index of the files in given tar file, which is gzip
+// compressed for .gz and .tgz extensions, into a tarFS.  As in extracting
+// the archive, later entries replace earlier ones at the same path, except
+// that files do not replace directories.
+func readTarFS(fname string) (*tarFS, error) {
+	ext := ArchiveExt(fname)
+	tf := &tarFS{fname: fname, gz: ext == ".tar.gz" || ext == ".tgz", ents: map[string]*tarEnt{}}
+	f, r, err := openTar(fname, tf.gz)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tf.ents["."] = &tarEnt{name: ".", mode: fs.ModeDir | 0755}
+	cr := &countReader{r: r}
+	tr := tar.NewReader(cr)
+	for hi := 0; ; hi++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		nm := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if !fs.ValidPath(nm) || nm == "." {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			te := tf.dir(nm)
+			te.mode = fs.ModeDir | fs.FileMode(hdr.Mode).Perm()
+			te.mod = hdr.ModTime
+		case tar.TypeReg, tar.TypeRegA:
+			if old, has := tf.ents[nm]; has {
+				if old.IsDir() {
+					continue
+				}
+				tf.remove(nm)
+			}
+			te := &tarEnt{name: path.Base(nm), mode: fs.FileMode(hdr.Mode).Perm(), mod: hdr.ModTime, size: hdr.Size, off: cr.n, hdr: hi}
+			par := tf.dir(path.Dir(nm))
+			tf.ents[nm] = te
+			par.kids = append(par.kids, te)
+		}
+	}
+	for _, te := range tf.ents {
+		sort.Slice(te.kids, func(i, j int) bool {
+			return te.kids[i].name < te.kids[j].name
+		})
+	}
+	return tf, nil
+}
+
+// dir returns the directory entry at given path, making it and its
+// parents if needed, replacing any files at their paths
+func (tf *tarFS) dir(nm string) *tarEnt {
+	if te, has := tf.ents[nm]; has {
+		if te.IsDir() {
+			return te
+		}
+		tf.remove(nm)
+	}
+	par := tf.dir(path.Dir(nm))
+	te := &tarEnt{name: path.Base(nm), mode: fs.ModeDir | 0755}
+	tf.ents[nm] = te
+	par.kids = append(par.kids, te)
+	return te
+}
+
+// remove removes the file entry at given path
+func (tf *tarFS) remove(nm string) {
+	te := tf.ents[nm]
+	delete(tf.ents, nm)
+	par := tf.ents[path.Dir(nm)]
+	for i, k := range par.kids {
+		if k == te {
+			par.kids = append(par.kids[:i], par.kids[i+1:]...)
+			break
+		}
+	}
+}
+
+// data opens the archive file to read the contents of given file entry,
+// returning the file, to close after reading, and the reader of the
+// contents
+func (tf *tarFS) data(te *tarEnt) (*os.File, io.Reader, error) {
+	f, r, err := openTar(tf.fname, tf.gz)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !tf.gz {
+		return f, io.NewSectionReader(f, te.off, te.size), nil
+	}
+	tr := tar.NewReader(r)
+	for hi := 0; hi <= te.hdr; hi++ {
+		if _, err := tr.Next(); err != nil {
+			f.Close()
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, nil, err
+		}
+	}
+	return f, tr, nil
+}
+
+func (tf *tarFS) ent(op, name string) (*tarEnt, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	te, has := tf.ents[name]
+	if !has {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return te, nil
+}
+
+func (tf *tarFS) Open(name string) (fs.File, error) {
+	te, err := tf.ent("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if te.IsDir() {
+		return &tarDir{ent: te}, nil
+	}
+	f, r, err := tf.data(te)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &tarFile{ent: te, f: f, r: r}, nil
+}
+
+func (tf *tarFS) Stat(name string) (fs.FileInfo, error) {
+	return tf.ent("stat", name)
+}
+
+func (tf *tarFS) ReadFile(name string) ([]byte, error) {
+	te, err := tf.ent("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if te.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	f, r, err := tf.data(te)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	defer f.Close()
+	b := make([]byte, te.size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return b, nil
+}
+
+func (tf *tarFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	te, err := tf.ent("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !te.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	des := make([]fs.DirEntry, len(te.kids))
+	for i, k := range te.kids {
+		des[i] = k
+	}
+	return des, nil
+}
+
+// tarFile is an open file in a tarFS, reading from the archive file
+type tarFile struct {
+	ent *tarEnt
+	f   *os.File
+	r   io.Reader
+}
+
+func (tf *tarFile) Stat() (fs.FileInfo, error) { return tf.ent, nil }
+func (tf *tarFile) Read(b []byte) (int, error) { return tf.r.Read(b) }
+func (tf *tarFile) Close() error               { return tf.f.Close() }
+
+// tarDir is an open directory in a tarFS
+type tarDir struct {
+	ent *tarEnt
+	off int
+}
+
+func (td *tarDir) Stat() (fs.FileInfo, error) { return td.ent, nil }
+func (td *tarDir) Close() error               { return nil }
+
+func (td *tarDir) Read(b []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: td.ent.name, Err: fs.ErrInvalid}
+}
+
+func (td *tarDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := td.ent.kids[td.off:]
+	if n > 0 && len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > 0 && n < len(rest) {
+		rest = rest[:n]
+	}
+	td.off += len(rest)
+	des := make([]fs.DirEntry, len(rest))
+	for i, k := range rest {
+		des[i] = k
+	}
+	return des, nil
+}
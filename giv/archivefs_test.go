@@ -0,0 +1,211 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testArcFile is a file in a test archive -- a directory if its name ends
+// in a slash
+type testArcFile struct {
+	name, data string
+}
+
+var testArcFiles = []testArcFile{
+	{"README", "top"},
+	{"src/", ""},
+	{"src/main.go", "package main"},
+	{"src/sub/deep/x.txt", "deep down"},
+	{strings.Repeat("long/", 30) + "name.txt", "pax name"},
+}
+
+// writeTestTar writes a tar archive with given files to given file,
+// compressed if gz
+func writeTestTar(t *testing.T, fname string, gz bool, files []testArcFile) {
+	f, err := os.Create(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var w io.Writer = f
+	if gz {
+		gw := gzip.NewWriter(f)
+		defer gw.Close()
+		w = gw
+	}
+	tw := tar.NewWriter(w)
+	for _, af := range files {
+		hdr := &tar.Header{Name: af.name, Mode: 0644, Size: int64(len(af.data)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(af.name, "/") {
+			hdr.Mode, hdr.Typeflag = 0755, tar.TypeDir
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(af.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeTestZip writes a zip archive with given files to given file
+func writeTestZip(t *testing.T, fname string, files []testArcFile) {
+	f, err := os.Create(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, af := range files {
+		w, err := zw.Create(af.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(af.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// dirNames returns the names of the entries of given directory, with a
+// slash after those of directories
+func dirNames(t *testing.T, dir string) string {
+	des, err := ReadDirPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nms := make([]string, len(des))
+	for i, de := range des {
+		nms[i] = de.Name()
+		if de.IsDir() {
+			nms[i] += "/"
+		}
+	}
+	return strings.Join(nms, " ")
+}
+
+func TestArchiveFS(t *testing.T) {
+	dir := t.TempDir()
+	arcs := []string{filepath.Join(dir, "t.zip"), filepath.Join(dir, "t.tar"), filepath.Join(dir, "t.tar.gz")}
+	writeTestZip(t, arcs[0], testArcFiles)
+	writeTestTar(t, arcs[1], false, testArcFiles)
+	writeTestTar(t, arcs[2], true, testArcFiles)
+	for _, arc := range arcs {
+		if got := dirNames(t, arc); got != "README long/ src/" {
+			t.Errorf("%s: listing: got %q", arc, got)
+		}
+		if got := dirNames(t, filepath.Join(arc, "src")); got != "main.go sub/" {
+			t.Errorf("%s: listing src: got %q", arc, got)
+		}
+		if got := dirNames(t, filepath.Join(arc, "src", "sub")); got != "deep/" {
+			t.Errorf("%s: listing src/sub: got %q", arc, got)
+		}
+		for _, af := range testArcFiles {
+			if strings.HasSuffix(af.name, "/") {
+				continue
+			}
+			fn := filepath.Join(arc, filepath.FromSlash(af.name))
+			if !InArchive(fn) {
+				t.Errorf("%s: not in archive", fn)
+			}
+			b, err := ReadFilePath(fn)
+			if err != nil || string(b) != af.data {
+				t.Errorf("%s: read: got %q, %v, want %q", fn, b, err, af.data)
+			}
+			if st, err := StatPath(fn); err != nil || st.Size() != int64(len(af.data)) || st.IsDir() {
+				t.Errorf("%s: stat: got %v, %v", fn, st, err)
+			}
+		}
+		if _, err := ReadFilePath(filepath.Join(arc, "nothing")); err == nil {
+			t.Errorf("%s: read of missing file: no error", arc)
+		}
+	}
+}
+
+func TestArchiveFSTarCollisions(t *testing.T) {
+	arc := filepath.Join(t.TempDir(), "t.tgz")
+	writeTestTar(t, arc, true, []testArcFile{
+		{"a", "file a"},
+		{"a/b", "b in dir a"}, // dir a replaces file a
+		{"a", "file a again"}, // file does not replace dir a
+		{"c", "first c"},
+		{"c", "second c"}, // later file replaces earlier one
+	})
+	if got := dirNames(t, arc); got != "a/ c" {
+		t.Errorf("listing: got %q, want %q", got, "a/ c")
+	}
+	if got := dirNames(t, filepath.Join(arc, "a")); got != "b" {
+		t.Errorf("listing a: got %q, want %q", got, "b")
+	}
+	if b, err := ReadFilePath(filepath.Join(arc, "c")); err != nil || string(b) != "second c" {
+		t.Errorf("read c: got %q, %v, want %q", b, err, "second c")
+	}
+}
+
+func TestArchiveFSCache(t *testing.T) {
+	defer func(mx int) { MaxArchiveFSs = mx }(MaxArchiveFSs)
+	MaxArchiveFSs = 2
+	dir := t.TempDir()
+	for _, nm := range []string{"a.zip", "b.zip", "c.zip"} {
+		arc := filepath.Join(dir, nm)
+		writeTestZip(t, arc, testArcFiles)
+		if _, err := ArchiveFS(arc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archiveFSsMu.Lock()
+	_, hasA := archiveFSs[filepath.Join(dir, "a.zip")]
+	_, hasC := archiveFSs[filepath.Join(dir, "c.zip")]
+	n := len(archiveFSs)
+	archiveFSsMu.Unlock()
+	if n > 2 || hasA || !hasC {
+		t.Errorf("cache: got %d archives, a: %v, c: %v, want 2, with c and without a", n, hasA, hasC)
+	}
+}
+
+func TestExtractArchivePath(t *testing.T) {
+	dir := t.TempDir()
+	arc := filepath.Join(dir, "t.tar.gz")
+	writeTestTar(t, arc, true, append(testArcFiles, testArcFile{"../evil.txt", "escaped"}))
+	dest := filepath.Join(dir, "out")
+	if err := ExtractArchivePath(filepath.Join(arc, "src"), dest); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(filepath.Join(dest, "src", "sub", "deep", "x.txt")); err != nil || string(b) != "deep down" {
+		t.Errorf("extracted file: got %q, %v", b, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "README")); err == nil {
+		t.Errorf("extracted README, which is not in src")
+	}
+	if err := ExtractArchivePath(arc, dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil.txt")); err == nil {
+		t.Errorf("extracted a file outside of the destination")
+	}
+
+	for _, p := range []string{"../x", "a/../../x", "../out2/x"} {
+		if trg, err := extractTarget(dest, p); err == nil {
+			t.Errorf("extractTarget %q: got %q, want an error", p, trg)
+		}
+	}
+	if trg, err := extractTarget(dest, "a/b"); err != nil || trg != filepath.Join(dest, "a", "b") {
+		t.Errorf("extractTarget a/b: got %q, %v", trg, err)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+// ColorSchemesView opens an editor of the ColorSchemes in given
+// preferences: each color of the selected scheme can be edited with a color
+// picker, with a live preview of common widgets in those colors.  A dark
+// scheme can be generated from a light one and vice-versa, and schemes
+// can be imported and exported in the Material Theme Builder JSON format.
+func ColorSchemesView(pf *gi.Preferences) *gi.Window {
+	winm := "gogi-color-schemes"
+	width := 1024
+	height := 800
+	win, recyc := gi.RecycleMainWindow(&pf.ColorSchemes, winm, "GoGi Color Schemes", width, height)
+	if recyc {
+		return win
+	}
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+	mfr.Lay = gi.LayoutVert
+
+	if len(pf.ColorSchemes) == 0 {
+		pf.ColorSchemes = gi.DefaultColorSchemes()
+	}
+	nms := pf.ColorSchemeNames()
+	cur := nms[0]
+
+	tbar := gi.AddNewToolBar(mfr, "toolbar")
+	tbar.SetStretchMaxWidth()
+	gi.AddNewLabel(tbar, "scheme-lab", "Scheme:")
+	cb := gi.AddNewComboBox(tbar, "scheme")
+	cb.ItemsFromStringList(nms, true, 0)
+	cb.Tooltip = "color scheme to edit"
+
+	split := gi.AddNewSplitView(mfr, "split")
+	split.Dim = mat32.X
+	split.SetStretchMax()
+
+	sv := AddNewStructView(split, "sv")
+	sv.Viewport = vp
+	sv.SetStretchMax()
+
+	pfr := gi.AddNewFrame(split, "preview", gi.LayoutVert)
+	pfr.SetStretchMax()
+	split.SetSplits(.5, .5)
+
+	setScheme := func(nm string) {
+		cur = nm
+		cp := pf.ColorSchemes[cur]
+		sv.SetStruct(cp)
+		ConfigColorSchemePreview(pfr, cp)
+	}
+	changed := func() {
+		pf.Changed = true
+		sv.UpdateFields()
+		ConfigColorSchemePreview(pfr, pf.ColorSchemes[cur])
+	}
+
+	cb.ComboSig.Connect(mfr.This(), func(recv, send ki.Ki, sig int64, data any) {
+		setScheme(data.(string))
+	})
+	sv.ViewSig.Connect(mfr.This(), func(recv, send ki.Ki, sig int64, data any) {
+		pf.Changed = true
+		ConfigColorSchemePreview(pfr, pf.ColorSchemes[cur])
+	})
+
+	tbar.AddSeparator("sep-gen")
+	tbar.AddAction(gi.ActOpts{Label: "Dark From Light", Icon: "color", Tooltip: "sets the Dark scheme to the inverse of the Light scheme, inverting the lightness of each color"}, mfr.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			lc, lok := pf.ColorSchemes["Light"]
+			dc, dok := pf.ColorSchemes["Dark"]
+			if lok && dok {
+				dc.SetInverse(lc)
+				changed()
+			}
+		})
+	tbar.AddAction(gi.ActOpts{Label: "Light From Dark", Icon: "color", Tooltip: "sets the Light scheme to the inverse of the Dark scheme, inverting the lightness of each color"}, mfr.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			lc, lok := pf.ColorSchemes["Light"]
+			dc, dok := pf.ColorSchemes["Dark"]
+			if lok && dok {
+				lc.SetInverse(dc)
+				changed()
+			}
+		})
+	tbar.AddSeparator("sep-file")
+	tbar.AddAction(gi.ActOpts{Label: "Import Material...", Icon: "file-open", Tooltip: "imports the Light and Dark schemes from a Material Theme Builder JSON export file"}, mfr.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			FileViewDialog(vp, "", ".json", DlgOpts{Title: "Import Material Theme"}, nil,
+				mfr.This(), func(recv, send ki.Ki, sig int64, data any) {
+					if sig == int64(gi.DialogAccepted) {
+						dlg, _ := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
+						fn := gi.FileName(FileViewDialogValue(dlg))
+						pf.OpenMaterialTheme(fn)
+						changed()
+					}
+				})
+		})
+	tbar.AddAction(gi.ActOpts{Label: "Export Material...", Icon: "file-save", Tooltip: "exports the schemes to a Material Theme Builder format JSON file"}, mfr.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			FileViewDialog(vp, "", ".json", DlgOpts{Title: "Export Material Theme"}, nil,
+				mfr.This(), func(recv, send ki.Ki, sig int64, data any) {
+					if sig == int64(gi.DialogAccepted) {
+						dlg, _ := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
+						fn := gi.FileName(FileViewDialogValue(dlg))
+						pf.SaveMaterialTheme(fn)
+					}
+				})
+		})
+	tbar.AddSeparator("sep-apply")
+	tbar.AddAction(gi.ActOpts{Label: "Apply", Icon: "update", Tooltip: "sets the current scheme as the active colors, and updates all windows"}, mfr.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			pf.ColorSchemes[cur].SetToPrefs()
+			pf.Changed = true
+		})
+	tbar.AddAction(gi.ActOpts{Label: "Save", Icon: "file-save", Tooltip: "saves the preferences, including the color schemes"}, mfr.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			pf.Save()
+		})
+
+	setScheme(cur)
+
+	if !win.HasGeomPrefs() { // resize to contents
+		vpsz := vp.PrefSize(win.OSWin.Screen().PixSize)
+		win.SetSize(vpsz)
+	}
+
+	vp.UpdateEndNoSig(updt)
+	win.GoStartEventLoop()
+	return win
+}
+
+// ConfigColorSchemePreview configures given frame to show a preview of
+// common widgets in the colors of given color scheme.  The widgets are
+// mock-ups styled directly with the colors, so they do not depend on the
+// current Prefs colors.
+func ConfigColorSchemePreview(fr *gi.Frame, cp *gi.ColorPrefs) {
+	updt := fr.UpdateStart()
+	fr.SetFullReRender()
+	fr.DeleteChildren(ki.DestroyKids)
+	fr.SetProp("background-color", cp.Background)
+	fr.SetProp("color", cp.Font)
+	fr.SetProp("padding", units.NewEm(1))
+	fr.SetProp("spacing", units.NewEm(1))
+
+	mockFrame := func(par ki.Ki, nm, txt string, bg, fg, border gist.Color) *gi.Frame {
+		mf := gi.AddNewFrame(par, nm, gi.LayoutHoriz)
+		mf.SetProp("background-color", bg)
+		mf.SetProp("border-color", border)
+		mf.SetProp("border-width", units.NewPx(1))
+		mf.SetProp("border-radius", units.NewPx(4))
+		mf.SetProp("padding", units.NewEx(0.5))
+		mf.SetProp("box-shadow.h-offset", units.NewPx(2))
+		mf.SetProp("box-shadow.v-offset", units.NewPx(2))
+		mf.SetProp("box-shadow.color", cp.Shadow)
+		lb := gi.AddNewLabel(mf, "lab", txt)
+		lb.SetProp("color", fg)
+		return mf
+	}
+
+	gi.AddNewLabel(fr, "title", "<b>Preview</b>").SetProp("color", cp.Font)
+	gi.AddNewLabel(fr, "text", "Regular text in the Font color on the Background").SetProp("color", cp.Font)
+	lk := gi.AddNewLabel(fr, "link", "<u>a link to somewhere</u>")
+	lk.SetProp("color", cp.Link)
+
+	bl := gi.AddNewLayout(fr, "buttons", gi.LayoutHoriz)
+	bl.SetProp("spacing", units.NewEm(1))
+	mockFrame(bl, "button", "Button", cp.Control, cp.Font, cp.Border)
+	mockFrame(bl, "button2", "Cancel", cp.Control, cp.Font, cp.Border)
+	ic := gi.AddNewFrame(bl, "icon", gi.LayoutHoriz)
+	ic.SetProp("background-color", cp.Icon)
+	ic.SetProp("min-width", units.NewEm(1.5))
+	ic.SetProp("min-height", units.NewEm(1.5))
+	ic.SetProp("border-radius", units.NewPx(4))
+	ic.Tooltip = "Icon color"
+
+	tf := mockFrame(fr, "textfield", "text field contents", cp.Background, cp.Font, cp.Border)
+	tf.SetStretchMaxWidth()
+
+	ll := gi.AddNewFrame(fr, "list", gi.LayoutVert)
+	ll.SetProp("background-color", cp.Background)
+	ll.SetProp("border-color", cp.Border)
+	ll.SetProp("border-width", units.NewPx(1))
+	ll.SetStretchMaxWidth()
+	for i, it := range []string{"list item", "selected item", "highlighted item", "list item"} {
+		ib := gi.AddNewFrame(ll, fmt.Sprintf("item-%d", i), gi.LayoutHoriz)
+		switch i {
+		case 1:
+			ib.SetProp("background-color", cp.Select)
+		case 2:
+			ib.SetProp("background-color", cp.Highlight)
+		default:
+			ib.SetProp("background-color", cp.Background)
+		}
+		ib.SetStretchMaxWidth()
+		gi.AddNewLabel(ib, "lab", it).SetProp("color", cp.Font)
+	}
+	fr.UpdateEnd(updt)
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mimedata"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+	"github.com/goki/pi/filecat"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//  RecentColors
+
+// RecentColorsMax is the maximum number of RecentColors
+var RecentColorsMax = 16
+
+// RecentColors are the colors most recently chosen in any ColorView or
+// ColorValueView, most recent first -- shown in the ColorView for reuse
+var RecentColors []gist.Color
+
+// AddRecentColor adds given color to the front of the RecentColors, removing
+// any previous occurrence of it
+func AddRecentColor(clr gist.Color) {
+	for i, rc := range RecentColors {
+		if rc == clr {
+			RecentColors = append(RecentColors[:i], RecentColors[i+1:]...)
+			break
+		}
+	}
+	RecentColors = append([]gist.Color{clr}, RecentColors...)
+	if len(RecentColors) > RecentColorsMax {
+		RecentColors = RecentColors[:RecentColorsMax]
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  ColorSwatch
+
+// ColorSwatch is an action button showing a color, drawn over a
+// checkerboard so that its alpha is visible.  When it has the focus, Copy
+// copies the color as hex text (#RRGGBBAA) to the clipboard, and Paste sets
+// it from text (hex or a color name) on the clipboard, sending ColorSig.
+type ColorSwatch struct {
+	gi.Action
+	Color    gist.Color `desc:"the color shown"`
+	ColorSig ki.Signal  `json:"-" xml:"-" view:"-" desc:"signal sent when the color is changed by pasting -- data is the color"`
+}
+
+var KiT_ColorSwatch = kit.Types.AddType(&ColorSwatch{}, ColorSwatchProps)
+
+// AddNewColorSwatch adds a new color swatch to given parent node, with given name.
+func AddNewColorSwatch(parent ki.Ki, name string) *ColorSwatch {
+	return parent.AddNewChild(KiT_ColorSwatch, name).(*ColorSwatch)
+}
+
+func (sw *ColorSwatch) Disconnect() {
+	sw.Action.Disconnect()
+	sw.ColorSig.DisconnectAll()
+}
+
+var ColorSwatchProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_ButtonFlags,
+	"border-width":     units.NewPx(1),
+	"border-radius":    units.NewPx(2),
+	"border-color":     &gi.Prefs.Colors.Border,
+	"background-color": &gi.Prefs.Colors.Control,
+	"color":            &gi.Prefs.Colors.Font,
+	"padding":          units.NewPx(2),
+	"margin":           units.NewPx(2),
+	"min-width":        units.NewEm(3),
+	"min-height":       units.NewEm(1.2),
+	gi.ButtonSelectors[gi.ButtonActive]: ki.Props{
+		"background-color": "linear-gradient(lighter-0, highlight-10)",
+	},
+	gi.ButtonSelectors[gi.ButtonInactive]: ki.Props{
+		"border-color": "highlight-50",
+	},
+	gi.ButtonSelectors[gi.ButtonHover]: ki.Props{
+		"background-color": "highlight-10",
+	},
+	gi.ButtonSelectors[gi.ButtonFocus]: ki.Props{
+		"border-width": units.NewPx(2),
+		"border-color": &gi.Prefs.Colors.Select,
+	},
+	gi.ButtonSelectors[gi.ButtonDown]: ki.Props{
+		"background-color": "highlight-20",
+	},
+	gi.ButtonSelectors[gi.ButtonSelected]: ki.Props{
+		"background-color": &gi.Prefs.Colors.Select,
+	},
+}
+
+// SetColor sets the color shown, and its hex value as the tooltip
+func (sw *ColorSwatch) SetColor(clr gist.Color) {
+	sw.Color = clr
+	sw.Tooltip = clr.HexString()
+	sw.UpdateSig()
+}
+
+// CheckerSize is the size in dots of the squares of the checkerboard
+// drawn under colors with alpha
+var CheckerSize = 6
+
+// DrawChecker draws a light and dark gray checkerboard with squares of
+// CheckerSize into given region of given image
+func DrawChecker(img draw.Image, r image.Rectangle) {
+	lt := image.NewUniform(color.RGBA{204, 204, 204, 255})
+	dk := image.NewUniform(color.RGBA{153, 153, 153, 255})
+	for y := r.Min.Y; y < r.Max.Y; y += CheckerSize {
+		for x := r.Min.X; x < r.Max.X; x += CheckerSize {
+			src := lt
+			if ((x-r.Min.X)/CheckerSize+(y-r.Min.Y)/CheckerSize)%2 == 1 {
+				src = dk
+			}
+			sq := image.Rect(x, y, x+CheckerSize, y+CheckerSize).Intersect(r)
+			draw.Draw(img, sq, src, image.Point{}, draw.Src)
+		}
+	}
+}
+
+// RenderSwatch renders the color over a checkerboard in the content box
+func (sw *ColorSwatch) RenderSwatch() {
+	rs, _, st := sw.RenderLock()
+	defer sw.RenderUnlock(rs)
+	spc := st.BoxSpace()
+	pos := sw.LayState.Alloc.Pos.AddScalar(spc)
+	sz := sw.LayState.Alloc.Size.SubScalar(2 * spc)
+	r := rs.Bounds.Intersect(mat32.RectFromPosSizeMax(pos, sz))
+	if r.Empty() {
+		return
+	}
+	if sw.Color.A < 255 {
+		DrawChecker(rs.Image, r)
+	}
+	clr := color.NRGBA{sw.Color.R, sw.Color.G, sw.Color.B, sw.Color.A}
+	draw.Draw(rs.Image, r, image.NewUniform(clr), image.Point{}, draw.Over)
+}
+
+// Copy copies the color as hex text to the clipboard
+func (sw *ColorSwatch) Copy() {
+	win := sw.ParentWindow()
+	if win == nil {
+		return
+	}
+	md := mimedata.NewText(sw.Color.HexString())
+	oswin.TheApp.ClipBoard(win.OSWin).Write(md)
+}
+
+// Paste sets the color from text on the clipboard (hex or a color name),
+// sending ColorSig -- returns false if it is not a valid color
+func (sw *ColorSwatch) Paste() bool {
+	win := sw.ParentWindow()
+	if win == nil || sw.IsInactive() {
+		return false
+	}
+	data := oswin.TheApp.ClipBoard(win.OSWin).Read([]string{filecat.TextPlain})
+	if data == nil {
+		return false
+	}
+	var clr gist.Color
+	if err := clr.SetString(data.Text(filecat.TextPlain), nil); err != nil {
+		return false
+	}
+	sw.SetColor(clr)
+	sw.ColorSig.Emit(sw.This(), 0, clr)
+	return true
+}
+
+// ClipKeyEvent processes the Copy and Paste keys
+func (sw *ColorSwatch) ClipKeyEvent() {
+	sw.ConnectEvent(oswin.KeyChordEvent, gi.HiPri, func(recv, send ki.Ki, sig int64, d any) {
+		sww := recv.Embed(KiT_ColorSwatch).(*ColorSwatch)
+		kt := d.(*key.ChordEvent)
+		switch gi.KeyFun(kt.Chord()) {
+		case gi.KeyFunCopy:
+			kt.SetProcessed()
+			sww.Copy()
+		case gi.KeyFunPaste:
+			kt.SetProcessed()
+			sww.Paste()
+		}
+	})
+}
+
+func (sw *ColorSwatch) ConnectEvents2D() {
+	sw.Action.ConnectEvents2D()
+	sw.ClipKeyEvent()
+}
+
+func (sw *ColorSwatch) Render2D() {
+	if sw.FullReRenderIfNeeded() {
+		return
+	}
+	if sw.PushBounds() {
+		sw.This().(gi.Node2D).ConnectEvents2D()
+		sw.UpdateButtonStyle()
+		sw.RenderButton()
+		sw.RenderSwatch()
+		sw.Render2DChildren()
+		sw.PopBounds()
+	} else {
+		sw.DisconnectAllEvents(gi.AllPris)
+	}
+}
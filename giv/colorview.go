@@ -5,6 +5,7 @@
 package giv
 
 import (
+	"fmt"
 	"image/color"
 	"log"
 	"reflect"
@@ -123,6 +124,7 @@ func (cv *ColorView) Config() {
 	cv.ConfigHSLSlider(ls, 2)
 
 	cv.ConfigPalette()
+	cv.ConfigTools()
 
 	cv.UpdateEnd(updt)
 }
@@ -312,6 +314,81 @@ func (cv *ColorView) ConfigPalette() {
 	}
 }
 
+// ConfigTools configures the row below the palette, with a field for the
+// hex value, the eyedropper, and the RecentColors
+func (cv *ColorView) ConfigTools() {
+	tl := gi.AddNewLayout(cv, "tool-lay", gi.LayoutHoriz)
+	tl.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	gi.AddNewLabel(tl, "hex-lab", "Hex:")
+	hx := gi.AddNewTextField(tl, "hex")
+	hx.SetMinPrefWidth(units.NewCh(12))
+	hx.Tooltip = "hex value of the color: #RRGGBBAA -- a color name can also be entered or pasted"
+	hx.TextFieldSig.ConnectOnly(cv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(gi.TextFieldDone) {
+			return
+		}
+		cvv, _ := recv.Embed(KiT_ColorView).(*ColorView)
+		tf := send.Embed(gi.KiT_TextField).(*gi.TextField)
+		var clr gist.Color
+		if err := clr.SetString(tf.Text(), nil); err == nil {
+			cvv.SetColorAction(clr)
+		}
+	})
+	ed := gi.AddNewAction(tl, "eyedropper")
+	ed.SetIcon("color")
+	ed.Tooltip = "eyedropper: click anywhere in any window to pick the color there -- Escape cancels"
+	ed.ActionSig.ConnectOnly(cv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		cvv, _ := recv.Embed(KiT_ColorView).(*ColorView)
+		cvv.Eyedropper()
+	})
+	if len(RecentColors) == 0 {
+		return
+	}
+	gi.AddNewLabel(tl, "recent-lab", "Recent:")
+	rl := gi.AddNewLayout(tl, "recent", gi.LayoutHoriz)
+	for i, rc := range RecentColors {
+		sw := AddNewColorSwatch(rl, fmt.Sprintf("recent-%d", i))
+		sw.SetColor(rc)
+		sw.SetProp("min-width", units.NewEm(1.3))
+		sw.ActionSig.ConnectOnly(cv.This(), func(recv, send ki.Ki, sig int64, data any) {
+			cvv, _ := recv.Embed(KiT_ColorView).(*ColorView)
+			sww := send.Embed(KiT_ColorSwatch).(*ColorSwatch)
+			cvv.SetColorAction(sww.Color)
+		})
+	}
+}
+
+// ToolLay returns the row with the hex field, eyedropper and recent colors
+func (cv *ColorView) ToolLay() *gi.Layout {
+	return cv.ChildByName("tool-lay", 3).(*gi.Layout)
+}
+
+// HexField returns the field showing the hex value of the color
+func (cv *ColorView) HexField() *gi.TextField {
+	return cv.ToolLay().ChildByName("hex", 1).(*gi.TextField)
+}
+
+// SetColorAction sets the color as chosen by the user, updating the view
+// and sending ViewSig
+func (cv *ColorView) SetColorAction(clr gist.Color) {
+	updt := cv.UpdateStart()
+	cv.Color = clr
+	if cv.TmpSave != nil {
+		cv.TmpSave.SaveTmp()
+	}
+	cv.ViewSig.Emit(cv.This(), 0, nil)
+	cv.UpdateImpl()
+	cv.UpdateEnd(updt)
+}
+
+// Eyedropper starts picking the color with the mouse, from anywhere in any
+// window -- see gi.StartEyedropper
+func (cv *ColorView) Eyedropper() {
+	gi.StartEyedropper(cv.ParentWindow(), func(clr gist.Color) {
+		cv.SetColorAction(clr)
+	})
+}
+
 func (cv *ColorView) Update() {
 	updt := cv.UpdateStart()
 	cv.UpdateImpl()
@@ -325,6 +402,9 @@ func (cv *ColorView) UpdateImpl() {
 	cv.NumView.UpdateWidget()
 	v := cv.Value()
 	v.Sty.Font.BgColor.Color = cv.Color // direct copy
+	if hx := cv.HexField(); !hx.HasFocus2D() {
+		hx.SetText(cv.Color.HexString())
+	}
 }
 
 func (cv *ColorView) Render2D() {
@@ -343,7 +423,9 @@ func (cv *ColorView) Render2D() {
 ////////////////////////////////////////////////////////////////////////////////////////
 //  ColorValueView
 
-// ColorValueView presents a StructViewInline for a struct plus a ColorView button..
+// ColorValueView presents a ColorSwatch showing the color, which opens a
+// ColorViewDialog to pick a new one, and supports copying and pasting the
+// color as hex text.  Chosen colors are added to the RecentColors.
 type ColorValueView struct {
 	ValueViewBase
 	TmpColor gist.Color
@@ -403,7 +485,7 @@ func (vv *ColorValueView) SetColor(clr gist.Color) {
 }
 
 func (vv *ColorValueView) WidgetType() reflect.Type {
-	vv.WidgetTyp = KiT_StructViewInline
+	vv.WidgetTyp = KiT_ColorSwatch
 	return vv.WidgetTyp
 }
 
@@ -411,43 +493,31 @@ func (vv *ColorValueView) UpdateWidget() {
 	if vv.Widget == nil {
 		return
 	}
-	sv := vv.Widget.(*StructViewInline)
+	sw := vv.Widget.(*ColorSwatch)
 	clr, ok := vv.Color()
 	if ok && clr != nil {
-		edack, err := sv.Parts.Children().ElemFromEndTry(0) // action at end, from AddAction above
-		if err == nil {
-			edac := edack.(*gi.Action)
-			edac.SetProp("background-color", *clr)
-			edac.SetFullReRender()
-		}
+		sw.SetColor(*clr)
 	}
-	sv.UpdateFields()
 }
 
 func (vv *ColorValueView) ConfigWidget(widg gi.Node2D) {
 	vv.Widget = widg
 	vv.StdConfigWidget(widg)
-	sv := vv.Widget.(*StructViewInline)
-	sv.AddAction = true
-	sv.ViewPath = vv.ViewPath
-	sv.TmpSave = vv.TmpSave
-	vv.CreateTempIfNotPtr() // we need our value to be a ptr to a struct -- if not make a tmp
-	sv.SetStruct(vv.Value.Interface())
-
-	edack, err := sv.Parts.Children().ElemFromEndTry(0) // action at end, from AddAction above
-	if err == nil {
-		edac := edack.(*gi.Action)
-		edac.SetIcon("color")
-		edac.Tooltip = "color selection dialog"
-		edac.ActionSig.ConnectOnly(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
-			svv, _ := recv.Embed(KiT_StructViewInline).(*StructViewInline)
-			vv.Activate(svv.ViewportSafe(), nil, nil)
-		})
+	sw := vv.Widget.(*ColorSwatch)
+	if desc, ok := vv.Tag("desc"); ok {
+		sw.Tooltip = desc
 	}
-	sv.ViewSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+	sw.ActionSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		vvv, _ := recv.Embed(KiT_ColorValueView).(*ColorValueView)
+		sww := vvv.Widget.(*ColorSwatch)
+		vvv.Activate(sww.ViewportSafe(), nil, nil)
+	})
+	sw.ColorSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
 		vvv, _ := recv.Embed(KiT_ColorValueView).(*ColorValueView)
-		vvv.UpdateWidget() // necessary in this case!
-		vvv.ViewSig.Emit(vvv.This(), 0, nil)
+		clr := data.(gist.Color)
+		AddRecentColor(clr)
+		vvv.SetColor(clr)
+		vvv.UpdateWidget()
 	})
 	vv.UpdateWidget()
 }
@@ -474,6 +544,7 @@ func (vv *ColorValueView) Activate(vp *gi.Viewport2D, dlgRecv ki.Ki, dlgFunc ki.
 			if sig == int64(gi.DialogAccepted) {
 				ddlg := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
 				cclr := ColorViewDialogValue(ddlg)
+				AddRecentColor(cclr)
 				vv.SetColor(cclr)
 				vv.UpdateWidget()
 			}
@@ -5,7 +5,9 @@
 package giv
 
 import (
+	"io/fs"
 	"reflect"
+	"strings"
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/girl"
@@ -105,7 +107,8 @@ func TextViewDialogTextView(dlg *gi.Dialog) *TextView {
 
 // StructViewDialog is for editing fields of a structure using a StructView --
 // optionally connects to given signal receiving object and function for
-// dialog signals (nil to ignore)
+// dialog signals (nil to ignore).  If the struct is a FormRuler, the Ok
+// button is only active while it is valid according to its rules.
 // gopy:interface=handle
 func StructViewDialog(avp *gi.Viewport2D, stru any, opts DlgOpts, recv ki.Ki, dlgFunc ki.RecvFunc) *gi.Dialog {
 	dlg, recyc := gi.RecycleStdDialog(stru, opts.ToGiOpts(), opts.Ok, opts.Cancel)
@@ -124,6 +127,12 @@ func StructViewDialog(avp *gi.Viewport2D, stru any, opts DlgOpts, recv ki.Ki, dl
 	sv.ViewPath = opts.ViewPath
 	sv.TmpSave = opts.TmpSave
 	sv.SetStruct(stru)
+	if sv.Validator != nil && !opts.Inactive { // gate Ok on validity
+		dlg.SetAcceptable(sv.IsValid())
+		sv.ValidSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data any) {
+			recv.Embed(gi.KiT_Dialog).(*gi.Dialog).SetAcceptable(data.(bool))
+		})
+	}
 	if recv != nil && dlgFunc != nil {
 		dlg.DialogSig.Connect(recv, dlgFunc)
 	}
@@ -397,16 +406,47 @@ var FontChooserSizeDots = 18
 
 // FontChooserDialog for choosing a font -- the recv and func signal receivers
 // if non-nil are connected to the selection signal for the struct table view,
-// so they are updated with that
+// so they are updated with that.  Each font name and example is rendered in
+// its own face, and the example preview text can be edited at the top.
 func FontChooserDialog(avp *gi.Viewport2D, opts DlgOpts, recv ki.Ki, dlgFunc ki.RecvFunc) *gi.Dialog {
 	FontChooserSizeDots = int(avp.Sty.UnContext.ToDots(float32(FontChooserSize), units.Pt))
 	girl.FontLibrary.OpenAllFonts(FontChooserSizeDots)
 	dlg := TableViewSelectDialog(avp, &girl.FontLibrary.FontInfo, opts, -1, FontInfoStyleFunc, recv, dlgFunc)
+	frame := dlg.Frame()
+	if frame.ChildByName("preview", 0) != nil { // recycled
+		return dlg
+	}
+	tv := frame.ChildByName("tableview", 0).(*TableView)
+	updt := frame.UpdateStart()
+	frame.SetFullReRender()
+	_, prIdx := dlg.PromptWidget(frame)
+	tf := frame.InsertNewChild(gi.KiT_TextField, prIdx+1, "preview").(*gi.TextField)
+	tf.Placeholder = "Preview text"
+	tf.Tooltip = "enter text to preview in each font"
+	tf.SetText(girl.FontInfoExample)
+	tf.SetStretchMaxWidth()
+	tf.TextFieldSig.Connect(tv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(gi.TextFieldDone) && sig != int64(gi.TextFieldInsert) && sig != int64(gi.TextFieldBackspace) && sig != int64(gi.TextFieldDelete) {
+			return
+		}
+		ttv := recv.Embed(KiT_TableView).(*TableView)
+		SetFontChooserPreview(send.(*gi.TextField).Text())
+		ttv.UpdateSliceGrid()
+	})
+	frame.UpdateEnd(updt)
 	return dlg
 }
 
+// SetFontChooserPreview sets the example preview text shown for each font
+// in the FontChooserDialog
+func SetFontChooserPreview(txt string) {
+	for i := range girl.FontLibrary.FontInfo {
+		girl.FontLibrary.FontInfo[i].Example = txt
+	}
+}
+
 func FontInfoStyleFunc(tv *TableView, slice any, widg gi.Node2D, row, col int, vv ValueView) {
-	if col == 4 {
+	if col == 0 || col == 4 {
 		finf, ok := slice.([]girl.FontInfo)
 		if ok {
 			widg.SetProp("font-family", (finf)[row].Name)
@@ -421,7 +461,9 @@ func FontInfoStyleFunc(tv *TableView, slice any, widg gi.Node2D, row, col int, v
 
 // IconChooserDialog for choosing an Icon -- the recv and fun signal receivers
 // if non-nil are connected to the selection signal for the slice view, and
-// the dialog signal.
+// the dialog signal.  The icons can be filtered by a search string and by
+// category (the namespace of registered icon sets), and the selected icon
+// is returned by IconChooserDialogValue.
 func IconChooserDialog(avp *gi.Viewport2D, curIc gi.IconName, opts DlgOpts, recv ki.Ki, dlgFunc ki.RecvFunc) *gi.Dialog {
 	if opts.CSS == nil {
 		opts.CSS = ki.Props{
@@ -431,10 +473,84 @@ func IconChooserDialog(avp *gi.Viewport2D, curIc gi.IconName, opts DlgOpts, recv
 			},
 		}
 	}
-	dlg := SliceViewSelectDialog(avp, &gi.CurIconList, curIc, opts, IconChooserStyleFunc, recv, dlgFunc)
+	IconChooserList = append(IconChooserList[:0], gi.CurIconList...)
+	dlg := SliceViewSelectDialog(avp, &IconChooserList, curIc, opts, IconChooserStyleFunc, recv, dlgFunc)
+	frame := dlg.Frame()
+	sv := frame.ChildByName("slice-view", 0).(*SliceView)
+	if fl := frame.ChildByName("filter", 0); fl != nil { // recycled
+		fl.ChildByName("category", 0).(*gi.ComboBox).SelectItem(0)
+		fl.ChildByName("search", 0).(*gi.SearchField).SetSearch("")
+		sv.SetSlice(&IconChooserList)
+		return dlg
+	}
+	updt := frame.UpdateStart()
+	frame.SetFullReRender()
+	_, prIdx := dlg.PromptWidget(frame)
+	fl := frame.InsertNewChild(gi.KiT_Layout, prIdx+1, "filter").(*gi.Layout)
+	fl.Lay = gi.LayoutHoriz
+	fl.SetStretchMaxWidth()
+	fl.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	sf := gi.AddNewSearchField(fl, "search")
+	sf.Tooltip = "only show icons whose names contain this text"
+	sf.SetStretchMaxWidth()
+	cb := gi.AddNewComboBox(fl, "category")
+	cb.Tooltip = "only show icons in this category -- icon sets registered at runtime are in the category of their namespace"
+	cats := append([]string{"All", "Default"}, gi.IconCategories(gi.CurIconList)[1:]...)
+	cb.ItemsFromStringList(cats, true, 0)
+
+	filter := func() {
+		IconChooserList = IconChooserFilter(gi.CurIconList, sf.LastSearch, cb.CurIndex)
+		sv.SetSlice(&IconChooserList)
+	}
+	sf.SearchSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		filter()
+	})
+	cb.ComboSig.Connect(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		filter()
+	})
+	frame.UpdateEnd(updt)
 	return dlg
 }
 
+// IconChooserList is the filtered list of icons shown in the IconChooserDialog
+var IconChooserList []gi.IconName
+
+// IconChooserFilter returns the icons in given list whose names contain the
+// search string (case insensitive), and which are in the category at
+// given index in the IconChooserDialog category chooser: 0 = all, 1 =
+// default icons, and then the registered icon set categories in order.
+func IconChooserFilter(icons []gi.IconName, search string, catIdx int) []gi.IconName {
+	cat := ""
+	if catIdx > 1 {
+		cats := gi.IconCategories(icons)
+		if catIdx-1 < len(cats) {
+			cat = cats[catIdx-1]
+		}
+	}
+	search = strings.ToLower(search)
+	var fl []gi.IconName
+	for _, ic := range icons {
+		if catIdx > 0 && ic.Category() != cat {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(string(ic)), search) {
+			continue
+		}
+		fl = append(fl, ic)
+	}
+	return fl
+}
+
+// IconChooserDialogValue returns the icon selected in the IconChooserDialog,
+// or "" if none selected
+func IconChooserDialogValue(dlg *gi.Dialog) gi.IconName {
+	si := SliceViewSelectDialogValue(dlg)
+	if si < 0 || si >= len(IconChooserList) {
+		return ""
+	}
+	return IconChooserList[si]
+}
+
 func IconChooserStyleFunc(sv *SliceView, slice any, widg gi.Node2D, row int, vv ValueView) {
 	ic, ok := slice.([]gi.IconName)
 	if ok {
@@ -481,6 +597,46 @@ func ColorViewDialogValue(dlg *gi.Dialog) gist.Color {
 	return gist.Color{}
 }
 
+// GradientEditorDialog for editing a gradient color spec using a
+// GradientEditor -- optionally connects to given signal receiving object and
+// function for dialog signals (nil to ignore)
+func GradientEditorDialog(avp *gi.Viewport2D, cs *gist.ColorSpec, opts DlgOpts, recv ki.Ki, dlgFunc ki.RecvFunc) *gi.Dialog {
+	dlg, recyc := gi.RecycleStdDialog(cs, opts.ToGiOpts(), gi.AddOk, gi.AddCancel)
+	if recyc {
+		return dlg
+	}
+
+	frame := dlg.Frame()
+	_, prIdx := dlg.PromptWidget(frame)
+
+	ge := frame.InsertNewChild(KiT_GradientEditor, prIdx+1, "gradient-editor").(*GradientEditor)
+	ge.Viewport = dlg.Embed(gi.KiT_Viewport2D).(*gi.Viewport2D)
+	ge.ViewPath = opts.ViewPath
+	ge.TmpSave = opts.TmpSave
+	ge.SetSpec(cs)
+
+	if recv != nil && dlgFunc != nil {
+		dlg.DialogSig.Connect(recv, dlgFunc)
+	}
+
+	dlg.UpdateEndNoSig(true)
+	dlg.Open(0, 0, avp, nil)
+	return dlg
+}
+
+// GradientEditorDialogValue gets the color spec from the dialog
+func GradientEditorDialogValue(dlg *gi.Dialog) gist.ColorSpec {
+	frame := dlg.Frame()
+	gek := frame.ChildByType(KiT_GradientEditor, ki.Embeds, 2)
+	if gek != nil {
+		ge := gek.(*GradientEditor)
+		cs := gist.ColorSpec{}
+		cs.CopyFrom(&ge.Spec)
+		return cs
+	}
+	return gist.ColorSpec{}
+}
+
 // FileViewDialog is for selecting / manipulating files -- ext is one or more
 // (comma separated) extensions -- files with those will be highlighted
 // (include the . at the start of the extension).  recv and dlgFunc connect to the
@@ -489,6 +645,14 @@ func ColorViewDialogValue(dlg *gi.Dialog) gist.Color {
 // files shown in the view -- e.g., FileViewDirOnlyFilter (for only showing
 // directories) and FileViewExtOnlyFilter (for only showing directories).
 func FileViewDialog(avp *gi.Viewport2D, filename, ext string, opts DlgOpts, filterFunc FileViewFilterFunc, recv ki.Ki, dlgFunc ki.RecvFunc) *gi.Dialog {
+	return FileViewDialogFS(avp, nil, filename, ext, opts, filterFunc, recv, dlgFunc)
+}
+
+// FileViewDialogFS is a FileViewDialog for the files in given fs.FS
+// filesystem (e.g., embedded assets, or an in-memory or remote filesystem),
+// with filename the slash-separated path within it -- a nil fs.FS is the OS
+// filesystem.  FileViewDialogValue returns the path within the fs.FS.
+func FileViewDialogFS(avp *gi.Viewport2D, fsys fs.FS, filename, ext string, opts DlgOpts, filterFunc FileViewFilterFunc, recv ki.Ki, dlgFunc ki.RecvFunc) *gi.Dialog {
 	dlg := gi.NewStdDialog(opts.ToGiOpts(), gi.AddOk, gi.AddCancel)
 	dlg.SetName("file-view") // use a consistent name for consistent sizing / placement
 
@@ -498,6 +662,7 @@ func FileViewDialog(avp *gi.Viewport2D, filename, ext string, opts DlgOpts, filt
 	fv := frame.InsertNewChild(KiT_FileView, prIdx+1, "file-view").(*FileView)
 	fv.Viewport = dlg.Embed(gi.KiT_Viewport2D).(*gi.Viewport2D)
 	fv.FilterFunc = filterFunc
+	fv.FS = fsys
 	fv.SetFilename(filename, ext)
 
 	fv.FileSig.Connect(dlg.This(), func(recv, send ki.Ki, sig int64, data any) {
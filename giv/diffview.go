@@ -104,16 +104,22 @@ func DiffViewDialog(avp *gi.Viewport2D, astr, bstr []string, afile, bfile, arev,
 // DiffView
 
 // DiffView presents two side-by-side TextView windows showing the differences
-// between two files (represented as lines of strings).
+// between two files (represented as lines of strings), or a single unified
+// inline view of the differences if Inline is set.
 type DiffView struct {
 	gi.Frame
 	FileA  string        `desc:"first file name being compared"`
 	FileB  string        `desc:"second file name being compared"`
 	RevA   string        `desc:"revision for first file, if relevant"`
 	RevB   string        `desc:"revision for second file, if relevant"`
+	Inline bool          `desc:"show a unified inline diff, with removed lines from A and added lines from B interleaved, instead of side-by-side -- use SetInline to change"`
 	Diffs  textbuf.Diffs `json:"-" xml:"-" desc:"the diff records"`
 	BufA   *TextBuf      `json:"-" xml:"-" desc:"textbuf for A"`
 	BufB   *TextBuf      `json:"-" xml:"-" desc:"textbuf for B"`
+	BufU   *TextBuf      `json:"-" xml:"-" desc:"textbuf for the unified inline diff"`
+	StrA   []string      `json:"-" xml:"-" desc:"original lines of A, for the inline diff"`
+	StrB   []string      `json:"-" xml:"-" desc:"original lines of B, for the inline diff"`
+	HunksU []int         `json:"-" xml:"-" desc:"starting lines of each changed region in the inline diff, for navigation"`
 	AlignD textbuf.Diffs `json:"-" xml:"-" desc:"aligned diffs records diff for aligned lines"`
 	EditA  textbuf.Diffs `json:"-" xml:"-" desc:"edit diffs records aligned diffs with edits applied"`
 	EditB  textbuf.Diffs `json:"-" xml:"-" desc:"edit diffs records aligned diffs with edits applied"`
@@ -130,6 +136,9 @@ func AddNewDiffView(parent ki.Ki, name string) *DiffView {
 
 // NextDiff moves to next diff region
 func (dv *DiffView) NextDiff(ab int) bool {
+	if dv.Inline {
+		return dv.NextInlineDiff()
+	}
 	tva, tvb := dv.TextViews()
 	tv := tva
 	if ab == 1 {
@@ -160,6 +169,9 @@ func (dv *DiffView) NextDiff(ab int) bool {
 
 // PrevDiff moves to previous diff region
 func (dv *DiffView) PrevDiff(ab int) bool {
+	if dv.Inline {
+		return dv.PrevInlineDiff()
+	}
 	tva, tvb := dv.TextViews()
 	tv := tva
 	if ab == 1 {
@@ -262,6 +274,8 @@ func (dv *DiffView) DiffStrings(astr, bstr []string) {
 	if !dv.IsConfiged() {
 		dv.Config()
 	}
+	dv.StrA = astr
+	dv.StrB = bstr
 	av, bv := dv.TextViews()
 	aupdt := av.UpdateStart()
 	bupdt := bv.UpdateStart()
@@ -376,6 +390,9 @@ func (dv *DiffView) DiffStrings(astr, bstr []string) {
 	dv.BufB.ReMarkup()
 	av.UpdateEnd(aupdt)
 	bv.UpdateEnd(bupdt)
+	if dv.Inline {
+		dv.InlineDiffs()
+	}
 }
 
 // TagWordDiffs goes through replace diffs and tags differences at the
@@ -391,39 +408,164 @@ func (dv *DiffView) TagWordDiffs() {
 		stln := df.I1
 		for i := 0; i < mx; i++ {
 			ln := stln + i
-			ra := dv.BufA.Lines[ln]
-			rb := dv.BufB.Lines[ln]
-			lna := lex.RuneFields(ra)
-			lnb := lex.RuneFields(rb)
-			fla := lna.RuneStrings(ra)
-			flb := lnb.RuneStrings(rb)
-			nab := ints.MaxInt(len(fla), len(flb))
-			ldif := textbuf.DiffLines(fla, flb)
-			ndif := len(ldif)
-			if nab > 25 && ndif > nab/2 { // more than half of big diff -- skip
-				continue
+			TagWordDiffLines(dv.BufA, ln, dv.BufB, ln, 0)
+		}
+	}
+}
+
+// TagWordDiffLines tags differences at the word level between line la in
+// buffer ba and line lb in buffer bb, starting at rune offset off in each
+// line (e.g., to skip a diff prefix) -- ba and bb can be the same buffer.
+func TagWordDiffLines(ba *TextBuf, la int, bb *TextBuf, lb int, off int) {
+	ra := ba.Lines[la][off:]
+	rb := bb.Lines[lb][off:]
+	lna := lex.RuneFields(ra)
+	lnb := lex.RuneFields(rb)
+	fla := lna.RuneStrings(ra)
+	flb := lnb.RuneStrings(rb)
+	nab := ints.MaxInt(len(fla), len(flb))
+	ldif := textbuf.DiffLines(fla, flb)
+	ndif := len(ldif)
+	if nab > 25 && ndif > nab/2 { // more than half of big diff -- skip
+		return
+	}
+	for _, ld := range ldif {
+		switch ld.Tag {
+		case 'r':
+			sla := lna[ld.I1]
+			ela := lna[ld.I2-1]
+			ba.AddTag(la, off+sla.St, off+ela.Ed, token.TextStyleError)
+			slb := lnb[ld.J1]
+			elb := lnb[ld.J2-1]
+			bb.AddTag(lb, off+slb.St, off+elb.Ed, token.TextStyleError)
+		case 'd':
+			sla := lna[ld.I1]
+			ela := lna[ld.I2-1]
+			ba.AddTag(la, off+sla.St, off+ela.Ed, token.TextStyleDeleted)
+		case 'i':
+			slb := lnb[ld.J1]
+			elb := lnb[ld.J2-1]
+			bb.AddTag(lb, off+slb.St, off+elb.Ed, token.TextStyleDeleted)
+		}
+	}
+}
+
+// InlineDiffs computes the unified inline diff from the original lines of
+// A and B, with unchanged lines prefixed by two spaces, lines removed from A
+// prefixed by "- " and lines added in B prefixed by "+ ", and word-level
+// differences tagged between replaced lines.
+func (dv *DiffView) InlineDiffs() {
+	if dv.BufU == nil {
+		return
+	}
+	tv := dv.TextViewU()
+	updt := tv.UpdateStart()
+	dv.BufU.LineColors = nil
+	dv.HunksU = nil
+	var ub [][]byte
+	type rpair struct{ a, b int }
+	var rps []rpair
+	var clrs []string
+	add := func(pfx, ln, clr string) {
+		ub = append(ub, []byte(pfx+ln))
+		clrs = append(clrs, clr)
+	}
+	for _, df := range dv.Diffs {
+		if df.Tag != 'e' {
+			dv.HunksU = append(dv.HunksU, len(ub))
+		}
+		switch df.Tag {
+		case 'e':
+			for i := df.I1; i < df.I2; i++ {
+				add("  ", dv.StrA[i], "")
 			}
-			for _, ld := range ldif {
-				switch ld.Tag {
-				case 'r':
-					sla := lna[ld.I1]
-					ela := lna[ld.I2-1]
-					dv.BufA.AddTag(ln, sla.St, ela.Ed, token.TextStyleError)
-					slb := lnb[ld.J1]
-					elb := lnb[ld.J2-1]
-					dv.BufB.AddTag(ln, slb.St, elb.Ed, token.TextStyleError)
-				case 'd':
-					sla := lna[ld.I1]
-					ela := lna[ld.I2-1]
-					dv.BufA.AddTag(ln, sla.St, ela.Ed, token.TextStyleDeleted)
-				case 'i':
-					slb := lnb[ld.J1]
-					elb := lnb[ld.J2-1]
-					dv.BufB.AddTag(ln, slb.St, elb.Ed, token.TextStyleDeleted)
-				}
+		case 'd':
+			for i := df.I1; i < df.I2; i++ {
+				add("- ", dv.StrA[i], "red")
+			}
+		case 'i':
+			for j := df.J1; j < df.J2; j++ {
+				add("+ ", dv.StrB[j], "green")
 			}
+		case 'r':
+			ast := len(ub)
+			for i := df.I1; i < df.I2; i++ {
+				add("- ", dv.StrA[i], "red")
+			}
+			bst := len(ub)
+			for j := df.J1; j < df.J2; j++ {
+				add("+ ", dv.StrB[j], "green")
+			}
+			n := ints.MinInt(df.I2-df.I1, df.J2-df.J1)
+			for i := 0; i < n; i++ {
+				rps = append(rps, rpair{ast + i, bst + i})
+			}
+		}
+	}
+	dv.BufU.SetTextLines(ub, false) // don't copy
+	for ln, clr := range clrs {
+		if clr != "" {
+			dv.BufU.SetLineColor(ln, clr)
+		}
+	}
+	for _, rp := range rps {
+		TagWordDiffLines(dv.BufU, rp.a, dv.BufU, rp.b, 2)
+	}
+	dv.BufU.ReMarkup()
+	tv.UpdateEnd(updt)
+}
+
+// SetInline sets whether to show a unified inline diff, instead of
+// side-by-side -- the inline view is not editable.
+func (dv *DiffView) SetInline(inline bool) {
+	dv.Inline = inline
+	if !dv.IsConfiged() {
+		return
+	}
+	updt := dv.UpdateStart()
+	if inline {
+		dv.InlineDiffs()
+		dv.DiffLay().StackTop = 1
+	} else {
+		dv.DiffLay().StackTop = 0
+	}
+	dv.UpdateToolBar()
+	dv.SetFullReRender()
+	dv.UpdateEnd(updt)
+}
+
+// ToggleInline toggles between unified inline and side-by-side display
+func (dv *DiffView) ToggleInline() {
+	dv.SetInline(!dv.Inline)
+}
+
+// NextInlineDiff moves to the next changed region in the inline view
+func (dv *DiffView) NextInlineDiff() bool {
+	tv := dv.TextViewU()
+	curLn := tv.CursorPos.Ln
+	for _, hl := range dv.HunksU {
+		if hl > curLn {
+			tv.SetCursorShow(lex.Pos{Ln: hl})
+			tv.ScrollCursorToVertCenter()
+			return true
+		}
+	}
+	return false
+}
+
+// PrevInlineDiff moves to the previous changed region in the inline view
+func (dv *DiffView) PrevInlineDiff() bool {
+	tv := dv.TextViewU()
+	curLn := tv.CursorPos.Ln
+	for i := len(dv.HunksU) - 1; i >= 0; i-- {
+		hl := dv.HunksU[i]
+		if hl < curLn {
+			tv.SetCursorShow(lex.Pos{Ln: hl})
+			tv.ScrollCursorToVertCenter()
+			return true
 		}
 	}
+	return false
 }
 
 // ApplyDiff applies change from the other buffer to the buffer for given file
@@ -575,6 +717,18 @@ func (dv *DiffView) HasDiffsUpdate(act *gi.Action) {
 	act.SetActiveStateUpdt(len(dv.AlignD) > 1) // always has at least 1
 }
 
+func (dv *DiffView) SideBySideUpdate(act *gi.Action) {
+	act.SetActiveStateUpdt(!dv.Inline && len(dv.AlignD) > 1)
+}
+
+func (dv *DiffView) SideBySideModifiedUpdateA(act *gi.Action) {
+	act.SetActiveStateUpdt(!dv.Inline && dv.BufA.IsChanged())
+}
+
+func (dv *DiffView) SideBySideModifiedUpdateB(act *gi.Action) {
+	act.SetActiveStateUpdt(!dv.Inline && dv.BufB.IsChanged())
+}
+
 func (dv *DiffView) ConfigToolBar() {
 	tb := dv.ToolBar()
 	tb.SetStretchMaxWidth()
@@ -593,23 +747,29 @@ func (dv *DiffView) ConfigToolBar() {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			dvv.PrevDiff(0)
 		})
-	tb.AddAction(gi.ActOpts{Label: "A <- B", Icon: "copy", Tooltip: "for current diff region, apply change from corresponding version in B, and move to next diff", UpdateFunc: dv.HasDiffsUpdate},
+	tb.AddAction(gi.ActOpts{Label: "A <- B", Icon: "copy", Tooltip: "for current diff region, apply change from corresponding version in B, and move to next diff", UpdateFunc: dv.SideBySideUpdate},
 		dv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			dvv.ApplyDiff(0, -1)
 			dvv.NextDiff(0)
 		})
-	tb.AddAction(gi.ActOpts{Label: "Undo", Icon: "undo", Tooltip: "undo last diff apply action (A <- B)", UpdateFunc: dv.FileModifiedUpdateA},
+	tb.AddAction(gi.ActOpts{Label: "Undo", Icon: "undo", Tooltip: "undo last diff apply action (A <- B)", UpdateFunc: dv.SideBySideModifiedUpdateA},
 		dv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			dvv.UndoDiff(0)
 		})
-	tb.AddAction(gi.ActOpts{Label: "Save", Icon: "file-save", Tooltip: "save edited version of file -- prompts for filename -- this will convert file back to its original form (removing side-by-side alignment) and end the diff editing function", UpdateFunc: dv.FileModifiedUpdateA},
+	tb.AddAction(gi.ActOpts{Label: "Save", Icon: "file-save", Tooltip: "save edited version of file -- prompts for filename -- this will convert file back to its original form (removing side-by-side alignment) and end the diff editing function", UpdateFunc: dv.SideBySideModifiedUpdateA},
 		dv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			CallMethod(dvv, "SaveFileA", dv.Viewport)
 		})
 	gi.AddNewStretch(tb, "str")
+	tb.AddAction(gi.ActOpts{Label: "Inline", Icon: "file-text", Tooltip: "toggle between side-by-side display and a unified inline display of the differences (which is not editable)"},
+		dv.This(), func(recv, send ki.Ki, sig int64, data any) {
+			dvv := recv.Embed(KiT_DiffView).(*DiffView)
+			dvv.ToggleInline()
+		})
+	gi.AddNewStretch(tb, "str-b")
 
 	txtb := "B: " + DirAndFile(dv.FileB)
 	if dv.RevB != "" {
@@ -626,18 +786,18 @@ func (dv *DiffView) ConfigToolBar() {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			dvv.PrevDiff(1)
 		})
-	tb.AddAction(gi.ActOpts{Label: "A -> B", Icon: "copy", Tooltip: "for current diff region, apply change from corresponding version in A, and move to next diff", UpdateFunc: dv.HasDiffsUpdate},
+	tb.AddAction(gi.ActOpts{Label: "A -> B", Icon: "copy", Tooltip: "for current diff region, apply change from corresponding version in A, and move to next diff", UpdateFunc: dv.SideBySideUpdate},
 		dv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			dvv.ApplyDiff(1, -1)
 			dvv.NextDiff(1)
 		})
-	tb.AddAction(gi.ActOpts{Label: "Undo", Icon: "undo", Tooltip: "undo last diff apply action (A -> B)", UpdateFunc: dv.FileModifiedUpdateB},
+	tb.AddAction(gi.ActOpts{Label: "Undo", Icon: "undo", Tooltip: "undo last diff apply action (A -> B)", UpdateFunc: dv.SideBySideModifiedUpdateB},
 		dv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			dvv.UndoDiff(1)
 		})
-	tb.AddAction(gi.ActOpts{Label: "Save", Icon: "file-save", Tooltip: "save edited version of file -- prompts for filename -- this will convert file back to its original form (removing side-by-side alignment) and end the diff editing function", UpdateFunc: dv.FileModifiedUpdateB},
+	tb.AddAction(gi.ActOpts{Label: "Save", Icon: "file-save", Tooltip: "save edited version of file -- prompts for filename -- this will convert file back to its original form (removing side-by-side alignment) and end the diff editing function", UpdateFunc: dv.SideBySideModifiedUpdateB},
 		dv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			dvv := recv.Embed(KiT_DiffView).(*DiffView)
 			CallMethod(dvv, "SaveFileB", dv.Viewport)
@@ -676,12 +836,17 @@ func (dv *DiffView) DiffLay() *gi.Layout {
 }
 
 func (dv *DiffView) TextViewLays() (*gi.Layout, *gi.Layout) {
-	lay := dv.DiffLay()
+	lay := dv.DiffLay().Child(0)
 	a := lay.Child(0).(*gi.Layout)
 	b := lay.Child(1).(*gi.Layout)
 	return a, b
 }
 
+// TextViewU returns the text view for the unified inline diff
+func (dv *DiffView) TextViewU() *DiffTextView {
+	return dv.DiffLay().Child(1).Child(0).(*DiffTextView)
+}
+
 func (dv *DiffView) TextViews() (*DiffTextView, *DiffTextView) {
 	a, b := dv.TextViewLays()
 	av := a.Child(0).(*DiffTextView)
@@ -696,6 +861,8 @@ func (dv *DiffView) ConfigTexts() {
 		dv.BufA.InitName(dv.BufA, "diff-buf-a")
 		dv.BufB = &TextBuf{}
 		dv.BufB.InitName(dv.BufB, "diff-buf-b")
+		dv.BufU = &TextBuf{}
+		dv.BufU.InitName(dv.BufU, "diff-buf-u")
 	}
 	dv.BufA.Filename = gi.FileName(dv.FileA)
 	dv.BufA.Opts.LineNos = true
@@ -703,15 +870,40 @@ func (dv *DiffView) ConfigTexts() {
 	dv.BufB.Filename = gi.FileName(dv.FileB)
 	dv.BufB.Opts.LineNos = true
 	dv.BufB.Stat() // update markup
-	lay.Lay = gi.LayoutHoriz
+	dv.BufU.Filename = gi.FileName(dv.FileB)
+	dv.BufU.Opts.LineNos = true
+	dv.BufU.Stat() // update markup
+	lay.Lay = gi.LayoutStacked
 	lay.SetStretchMax()
+	if dv.Inline {
+		lay.StackTop = 1
+	}
+	sconfig := kit.TypeAndNameList{}
+	sconfig.Add(gi.KiT_Layout, "sbs-lay")
+	sconfig.Add(gi.KiT_Layout, "text-u-lay")
+	smods, supdt := lay.ConfigChildren(sconfig)
+	if smods {
+		sbs := lay.Child(0).(*gi.Layout)
+		sbs.Lay = gi.LayoutHoriz
+		sbs.SetStretchMax()
+		ul := lay.Child(1).(*gi.Layout)
+		ul.SetStretchMax()
+		ul.SetMinPrefWidth(units.NewCh(80))
+		ul.SetMinPrefHeight(units.NewEm(40))
+		uv := AddNewDiffTextView(ul, "text-u")
+		uv.SetProp("font-family", gi.Prefs.MonoFont)
+		uv.SetInactive()
+		uv.SetBuf(dv.BufU)
+	}
+	lay.UpdateEnd(supdt)
+	sbs := lay.Child(0)
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_Layout, "text-a-lay")
 	config.Add(gi.KiT_Layout, "text-b-lay")
-	mods, updt := lay.ConfigChildren(config)
+	mods, updt := sbs.ConfigChildren(config)
 	al, bl := dv.TextViewLays()
 	if !mods {
-		updt = lay.UpdateStart()
+		updt = sbs.UpdateStart()
 	} else {
 		al.SetStretchMax()
 		al.SetMinPrefWidth(units.NewCh(80))
@@ -737,7 +929,7 @@ func (dv *DiffView) ConfigTexts() {
 			al.ScrollToPos(mat32.Dims(sig), data.(float32))
 		})
 	}
-	lay.UpdateEnd(updt)
+	sbs.UpdateEnd(updt)
 }
 
 func (dv *DiffView) IsConfiged() bool {
@@ -807,7 +999,7 @@ func (tv *DiffTextView) MouseEvent(me *mouse.Event) {
 		newPos := tv.PixelToCursor(pt)
 		ln := newPos.Ln
 		dv := tv.DiffView()
-		if dv != nil && tv.Buf != nil {
+		if dv != nil && tv.Buf != nil && tv.Nm != "text-u" {
 			if tv.Nm == "text-a" {
 				dv.ApplyDiff(0, ln)
 			} else {
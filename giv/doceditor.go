@@ -0,0 +1,800 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// DocEditor is a structured editor of JSON and YAML documents: the
+// document is shown as a tree of keys in a TreeTable, with the type and
+// value of each, and the selected key is edited in the edit bar below,
+// with an editor appropriate for its type (a check box for booleans, and
+// a choice of the allowed values if the schema has them).  Keys and
+// elements can be added, deleted and moved with the toolbar actions.
+// The document can be validated against an optional JSON Schema
+// (DocSchema), which is done after every change, with the errors listed
+// at the bottom -- clicking on one selects the key with the error.
+// Comments in the document are preserved (see DocNode), and can be
+// edited for each key.
+type DocEditor struct {
+	gi.Frame
+	Doc      *DocNode          `json:"-" xml:"-" desc:"root of the document being edited"`
+	Filename gi.FileName       `desc:"current file name for opening and saving -- files ending in .yaml or .yml are YAML, and otherwise JSON"`
+	Schema   *DocSchema        `json:"-" xml:"-" desc:"optional schema that the document is validated against"`
+	Errs     []*DocSchemaError `json:"-" xml:"-" desc:"the errors from the last validation against the schema"`
+	Sel      *DocNode          `json:"-" xml:"-" desc:"the selected node, which is edited in the edit bar"`
+	Changed  bool              `desc:"whether the document has been changed since it was opened or saved"`
+	DocSig   ki.Signal         `json:"-" xml:"-" view:"-" desc:"signal for when the document is changed in the editor -- data is the node that changed"`
+}
+
+var KiT_DocEditor = kit.Types.AddType(&DocEditor{}, DocEditorProps)
+
+// AddNewDocEditor adds a new document editor to given parent node, with
+// given name.
+func AddNewDocEditor(parent ki.Ki, name string) *DocEditor {
+	return parent.AddNewChild(KiT_DocEditor, name).(*DocEditor)
+}
+
+// IsYAML returns true if the document is YAML, based on the Filename
+func (de *DocEditor) IsYAML() bool {
+	ext := strings.ToLower(filepath.Ext(string(de.Filename)))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// SetDoc sets the document to edit, and configures the editor
+func (de *DocEditor) SetDoc(doc *DocNode) {
+	updt := de.UpdateStart()
+	de.Doc = doc
+	de.Sel = nil
+	de.Changed = false
+	de.Config()
+	de.TreeTable().SetRootNode(doc)
+	de.SelectNode(doc)
+	de.Validate()
+	de.UpdateEnd(updt)
+}
+
+// ParseDoc parses given JSON or YAML text, depending on the Filename, as
+// the document to edit
+func (de *DocEditor) ParseDoc(src []byte) error {
+	var doc *DocNode
+	var err error
+	if de.IsYAML() {
+		doc, err = ParseYAMLDoc(src)
+	} else {
+		doc, err = ParseJSONDoc(src)
+	}
+	if err != nil {
+		return err
+	}
+	de.SetDoc(doc)
+	return nil
+}
+
+// DocText returns the text of the document, as YAML or JSON depending on
+// the Filename
+func (de *DocEditor) DocText() []byte {
+	if de.Doc == nil {
+		return nil
+	}
+	if de.IsYAML() {
+		return de.Doc.YAML()
+	}
+	return de.Doc.JSON()
+}
+
+// Open opens the JSON or YAML document in given file
+func (de *DocEditor) Open(filename gi.FileName) {
+	b, err := os.ReadFile(string(filename))
+	if err == nil {
+		prv := de.Filename
+		de.Filename = filename
+		if err = de.ParseDoc(b); err != nil {
+			de.Filename = prv
+		}
+	}
+	if err != nil {
+		de.ErrorDialog("Could Not Open Document", err)
+	}
+}
+
+// Save saves the document to the current Filename
+func (de *DocEditor) Save() {
+	if de.Filename == "" {
+		return
+	}
+	de.SaveAs(de.Filename)
+}
+
+// SaveAs saves the document to given file, as YAML if it ends in .yaml
+// or .yml, and otherwise as JSON
+func (de *DocEditor) SaveAs(filename gi.FileName) {
+	de.Filename = filename
+	if err := de.SaveFile(); err != nil {
+		de.ErrorDialog("Could Not Save Document", err)
+	}
+}
+
+// SaveFile saves the document to the current Filename, returning any error
+func (de *DocEditor) SaveFile() error {
+	if de.Filename == "" {
+		return errors.New("the document has no file name -- use Save As")
+	}
+	if err := os.WriteFile(string(de.Filename), de.DocText(), 0644); err != nil {
+		return err
+	}
+	de.Changed = false
+	de.UpdateToolBar()
+	return nil
+}
+
+// DirtyName returns the file name of the document, for the list of unsaved
+// documents when closing -- DocEditor is a gi.DirtyProvider
+func (de *DocEditor) DirtyName() string {
+	if de.Filename == "" {
+		return "untitled document"
+	}
+	return filepath.Base(string(de.Filename))
+}
+
+// IsDirty returns true if the document has unsaved changes
+func (de *DocEditor) IsDirty() bool {
+	return de.Changed
+}
+
+// SaveDirty saves the document before closing
+func (de *DocEditor) SaveDirty() error {
+	return de.SaveFile()
+}
+
+// DiscardDirty does nothing: the changes are discarded when closing
+func (de *DocEditor) DiscardDirty() {
+}
+
+// OpenSchema opens a JSON Schema, in JSON or YAML, from given file, and
+// validates the document against it
+func (de *DocEditor) OpenSchema(filename gi.FileName) {
+	ds, err := OpenDocSchema(string(filename))
+	if err != nil {
+		de.ErrorDialog("Could Not Open Schema", err)
+		return
+	}
+	de.SetSchema(ds)
+}
+
+// SetSchema sets the schema that the document is validated against (can
+// be nil for none), and validates it
+func (de *DocEditor) SetSchema(ds *DocSchema) {
+	de.Schema = ds
+	if de.HasChildren() {
+		de.ConfigEditBar()
+		de.Validate()
+	}
+}
+
+// Validate validates the document against the Schema, if set, and shows
+// the errors, returning true if there are none
+func (de *DocEditor) Validate() bool {
+	de.Errs = nil
+	if de.Schema != nil && de.Doc != nil {
+		de.Errs = de.Schema.Validate(de.Doc)
+	}
+	if de.HasChildren() {
+		de.UpdateErrs()
+	}
+	return len(de.Errs) == 0
+}
+
+// ErrorDialog shows given error in a dialog with given title
+func (de *DocEditor) ErrorDialog(title string, err error) {
+	gi.ErrorDialog(de.Viewport, title, err)
+}
+
+// SetChanged records that given node was changed in the editor: the
+// document is validated, the edit bar updated if it was the selected
+// node, and DocSig is emitted
+func (de *DocEditor) SetChanged(dn *DocNode) {
+	de.Changed = true
+	de.Validate()
+	if dn == de.Sel {
+		de.ConfigEditBar()
+	}
+	de.UpdateToolBar()
+	de.DocSig.Emit(de.This(), 0, dn)
+}
+
+// UpdateToolBar updates the active state of the toolbar actions
+func (de *DocEditor) UpdateToolBar() {
+	if de.HasChildren() {
+		de.ToolBar().UpdateActions()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Editing actions
+
+// AddKey adds a new key with given name (ignored for arrays) and kind of
+// value: at the end of the selected object or array, or after the
+// selected key.  Its value is the default value in the schema, if any.
+func (de *DocEditor) AddKey(key string, kind DocKinds) {
+	if de.Sel == nil {
+		return
+	}
+	par, idx := de.Sel, de.Sel.NumChildren()
+	if !de.Sel.IsContainer() {
+		par = de.Sel.ParentDoc()
+		if par == nil {
+			de.ErrorDialog("Cannot Add", errors.New("the document is not an object or array -- change its type to add keys"))
+			return
+		}
+		idx, _ = par.Kids.IndexOf(de.Sel, 0)
+		idx++
+	}
+	if par.Kind == DocObject {
+		if key == "" {
+			de.ErrorDialog("Cannot Add", errors.New("a key name is required to add to an object"))
+			return
+		}
+		if par.ChildByName(key, 0) != nil {
+			de.ErrorDialog("Cannot Add", fmt.Errorf("key %q already exists", key))
+			return
+		}
+	}
+	updt := par.UpdateStart()
+	par.SetChildAdded()
+	dn := par.InsertNewChild(KiT_DocNode, idx, key).(*DocNode)
+	dn.SetKind(kind)
+	if par.Kind == DocArray {
+		par.RenumberKids()
+	}
+	if de.Schema != nil {
+		if ks := de.Schema.NodeSchema(dn); ks != nil && ks.Default != nil {
+			DocNodeSetAny(dn, ks.Default)
+		}
+	}
+	par.UpdateEnd(updt)
+	de.SetChanged(par)
+	de.SelectNode(dn)
+}
+
+// DeleteKey deletes the selected key, and its value
+func (de *DocEditor) DeleteKey() {
+	if de.Sel == nil {
+		return
+	}
+	par := de.Sel.ParentDoc()
+	if par == nil {
+		return
+	}
+	idx, _ := par.Kids.IndexOf(de.Sel, 0)
+	updt := par.UpdateStart()
+	par.SetChildAdded() // deleting triggers full re-render too
+	par.DeleteChild(de.Sel, ki.DestroyKids)
+	if par.Kind == DocArray {
+		par.RenumberKids()
+	}
+	par.UpdateEnd(updt)
+	de.SetChanged(par)
+	if par.NumChildren() > 0 {
+		de.SelectNode(par.Child(ints.MinInt(idx, par.NumChildren()-1)).(*DocNode))
+	} else {
+		de.SelectNode(par)
+	}
+}
+
+// MoveUp moves the selected key up, before the previous one
+func (de *DocEditor) MoveUp() {
+	de.Move(-1)
+}
+
+// MoveDown moves the selected key down, after the next one
+func (de *DocEditor) MoveDown() {
+	de.Move(1)
+}
+
+// Move moves the selected key by given offset within its object or array,
+// if possible
+func (de *DocEditor) Move(off int) {
+	if de.Sel == nil || de.Sel.ParentDoc() == nil {
+		return
+	}
+	par, dn := de.Sel.ParentDoc(), de.Sel
+	idx, _ := par.Kids.IndexOf(dn, 0)
+	to := idx + off
+	if to < 0 || to >= par.NumChildren() {
+		return
+	}
+	updt := par.UpdateStart()
+	par.SetChildAdded()
+	par.Kids.Move(idx, to)
+	if par.Kind == DocArray {
+		par.RenumberKids()
+	}
+	par.UpdateEnd(updt)
+	de.SetChanged(par)
+	de.SelectNode(dn)
+}
+
+// Rename renames the selected key, returning an error if the name is
+// empty, already exists, or the key is in an array
+func (de *DocEditor) Rename(key string) error {
+	dn := de.Sel
+	if dn == nil || dn.ParentDoc() == nil || dn.Nm == key {
+		return nil
+	}
+	par := dn.ParentDoc()
+	switch {
+	case par.Kind != DocObject:
+		return errors.New("the elements of an array do not have keys")
+	case key == "":
+		return errors.New("the key cannot be empty")
+	case par.ChildByName(key, 0) != nil:
+		return fmt.Errorf("key %q already exists", key)
+	}
+	updt := dn.UpdateStart()
+	dn.SetName(key)
+	dn.UpdateEnd(updt)
+	de.SetChanged(dn)
+	return nil
+}
+
+// DocNodeSetAny sets given node to given value, in the standard Go
+// representation of JSON values (see DocNode.ToAny), e.g., the default
+// value of a DocSchema
+func DocNodeSetAny(dn *DocNode, v any) {
+	updt := dn.UpdateStart()
+	defer dn.UpdateEnd(updt)
+	if dn.HasChildren() {
+		dn.SetChildAdded()
+		dn.DeleteChildren(ki.DestroyKids)
+	}
+	switch vv := v.(type) {
+	case nil:
+		dn.SetKind(DocNull)
+	case bool:
+		dn.Kind = DocBool
+		dn.Value = strconv.FormatBool(vv)
+	case float64:
+		dn.Kind = DocNumber
+		dn.Value = strconv.FormatFloat(vv, 'g', -1, 64)
+	case string:
+		dn.Kind = DocString
+		dn.Value = vv
+	case map[string]any:
+		dn.SetKind(DocObject)
+		for key, kv := range vv {
+			DocNodeSetAny(AddNewDocNode(dn, key, DocNull), kv)
+		}
+	case []any:
+		dn.SetKind(DocArray)
+		for _, kv := range vv {
+			DocNodeSetAny(AddNewDocNode(dn, "", DocNull), kv)
+		}
+	default:
+		dn.Kind = DocString
+		dn.Value = kit.ToString(v)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  GUI
+
+// Config configures the toolbar, tree table, edit bar and errors
+func (de *DocEditor) Config() {
+	de.Lay = gi.LayoutVert
+	de.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(KiT_TreeTable, "tree-table")
+	config.Add(gi.KiT_Layout, "edit-bar")
+	config.Add(gi.KiT_Label, "errors")
+	mods, updt := de.ConfigChildren(config)
+	if !mods {
+		return
+	}
+	tb := de.ToolBar()
+	tb.SetStretchMaxWidth()
+	ToolBarView(de, de.Viewport, tb)
+	tt := de.TreeTable()
+	tt.SortIdx = -1
+	tt.TreeName = "Key"
+	tt.AddCol("Type", 10, nil)
+	tt.AddCol("Value", 40, nil)
+	tt.Config()
+	tr := tt.Tree()
+	tr.SetInactive() // edited with the editor actions, to keep a valid document
+	tr.TreeViewSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(TreeViewSelected) || data == nil {
+			return
+		}
+		tvn, _ := data.(ki.Ki).Embed(KiT_TreeView).(*TreeView)
+		if tvn == nil {
+			return
+		}
+		if dn, ok := tvn.SrcNode.(*DocNode); ok && dn != de.Sel {
+			de.Sel = dn
+			de.ConfigEditBar()
+			de.UpdateToolBar()
+		}
+	})
+	eb := de.EditBar()
+	eb.Lay = gi.LayoutHoriz
+	eb.SetStretchMaxWidth()
+	el := de.ErrsLabel()
+	el.SetStretchMaxWidth()
+	el.Redrawable = true
+	el.LinkSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+		idx, err := strconv.Atoi(strings.TrimPrefix(data.(string), "docerr:///"))
+		if err == nil && idx >= 0 && idx < len(de.Errs) {
+			de.SelectNode(de.Errs[idx].Node)
+		}
+	})
+	de.UpdateEnd(updt)
+}
+
+// ToolBar returns the toolbar
+func (de *DocEditor) ToolBar() *gi.ToolBar {
+	return de.ChildByName("toolbar", 0).(*gi.ToolBar)
+}
+
+// TreeTable returns the tree table showing the document
+func (de *DocEditor) TreeTable() *TreeTable {
+	return de.ChildByName("tree-table", 1).(*TreeTable)
+}
+
+// EditBar returns the layout with the editors of the selected node
+func (de *DocEditor) EditBar() *gi.Layout {
+	return de.ChildByName("edit-bar", 2).(*gi.Layout)
+}
+
+// ErrsLabel returns the label showing the validation errors
+func (de *DocEditor) ErrsLabel() *gi.Label {
+	return de.ChildByName("errors", 3).(*gi.Label)
+}
+
+// SelectNode selects given node in the tree, and edits it in the edit bar
+func (de *DocEditor) SelectNode(dn *DocNode) {
+	if dn == nil || !de.HasChildren() {
+		return
+	}
+	de.Sel = dn
+	if tvn := de.TreeTable().Tree().FindSrcNode(dn); tvn != nil {
+		tvn.OpenParents()
+		tvn.SelectAction(mouse.SelectOne)
+		tvn.ScrollToMe()
+	}
+	de.ConfigEditBar()
+	de.UpdateToolBar()
+}
+
+// SelSchema returns the schema for the selected node, or nil
+func (de *DocEditor) SelSchema() *DocSchema {
+	if de.Schema == nil || de.Sel == nil {
+		return nil
+	}
+	return de.Schema.NodeSchema(de.Sel)
+}
+
+// ConfigEditBar configures the editors of the selected node: its key, type,
+// value and comment, with the editor of the value appropriate for its type
+func (de *DocEditor) ConfigEditBar() {
+	eb := de.EditBar()
+	dn := de.Sel
+	if dn == nil {
+		eb.DeleteChildren(ki.DestroyKids)
+		return
+	}
+	ds := de.SelSchema()
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Label, "key-lbl")
+	config.Add(gi.KiT_TextField, "key")
+	config.Add(gi.KiT_ComboBox, "type")
+	switch {
+	case dn.Kind == DocBool:
+		config.Add(gi.KiT_CheckBox, "value")
+	case (dn.Kind == DocNumber || dn.Kind == DocString) && ds != nil && len(ds.Enum) > 0:
+		config.Add(gi.KiT_ComboBox, "value")
+	case dn.Kind == DocNumber || dn.Kind == DocString:
+		config.Add(gi.KiT_TextField, "value")
+	default:
+		config.Add(gi.KiT_Label, "value")
+	}
+	config.Add(gi.KiT_Label, "cmt-lbl")
+	config.Add(gi.KiT_TextField, "comment")
+	mods, updt := eb.ConfigChildren(config)
+	if !mods {
+		updt = eb.UpdateStart()
+	}
+
+	eb.ChildByName("key-lbl", 0).(*gi.Label).SetText("Key:")
+	kf := eb.ChildByName("key", 1).(*gi.TextField)
+	kf.SetProp("width", units.NewCh(20))
+	kf.SetText(dn.Nm)
+	kf.SetInactiveState(dn.ParentDoc() == nil || dn.ParentDoc().Kind == DocArray)
+	kf.Tooltip = "key of the value -- press enter to rename"
+	if ds != nil && ds.Tooltip() != "" {
+		kf.Tooltip = ds.Tooltip()
+	}
+	kf.TextFieldSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(gi.TextFieldDone) {
+			return
+		}
+		if err := de.Rename(kf.Text()); err != nil {
+			de.ErrorDialog("Cannot Rename", err)
+			kf.SetText(dn.Nm)
+		}
+	})
+
+	tc := eb.ChildByName("type", 2).(*gi.ComboBox)
+	if mods {
+		kinds := make([]string, DocKindsN)
+		for k := DocNull; k < DocKindsN; k++ {
+			kinds[k] = k.JSONType()
+		}
+		tc.ItemsFromStringList(kinds, false, 0)
+	}
+	tc.SetCurIndex(int(dn.Kind))
+	tc.Tooltip = "type of the value -- changing it to a value type deletes all of the keys or elements"
+	tc.ComboSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if DocKinds(sig) == dn.Kind {
+			return
+		}
+		dn.SetKind(DocKinds(sig))
+		de.SetChanged(dn)
+	})
+
+	de.ConfigValueEditor(eb.ChildByName("value", 3), ds)
+
+	eb.ChildByName("cmt-lbl", 4).(*gi.Label).SetText("Comment:")
+	cf := eb.ChildByName("comment", 5).(*gi.TextField)
+	cf.SetStretchMaxWidth()
+	cf.SetText(strings.ReplaceAll(dn.Comment, "\n", " / "))
+	cf.Tooltip = "comment before the key in the file -- lines are separated by / -- press enter to set"
+	cf.TextFieldSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(gi.TextFieldDone) {
+			return
+		}
+		lns := strings.Split(cf.Text(), " / ")
+		for i := range lns {
+			lns[i] = strings.TrimSpace(lns[i])
+		}
+		dn.Comment = strings.TrimSpace(strings.Join(lns, "\n"))
+		de.SetChanged(dn)
+	})
+	eb.UpdateEnd(updt)
+}
+
+// ConfigValueEditor configures given editor of the value of the selected
+// node, with given schema for it (can be nil)
+func (de *DocEditor) ConfigValueEditor(ed ki.Ki, ds *DocSchema) {
+	dn := de.Sel
+	switch vw := ed.(type) {
+	case *gi.CheckBox:
+		vw.SetText("true")
+		vw.SetChecked(dn.Value == "true")
+		vw.ButtonSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+			if sig == int64(gi.ButtonToggled) {
+				dn.SetValue(strconv.FormatBool(vw.IsChecked()))
+				de.SetChanged(dn)
+			}
+		})
+	case *gi.ComboBox:
+		vals := make([]string, len(ds.Enum))
+		for i, ev := range ds.Enum {
+			vals[i] = kit.ToString(ev)
+		}
+		vw.ItemsFromStringList(vals, false, 40)
+		vw.SetCurVal(dn.Value)
+		vw.ComboSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+			if sig >= 0 && int(sig) < len(ds.Enum) {
+				DocNodeSetAny(dn, ds.Enum[sig])
+				de.SetChanged(dn)
+			}
+		})
+	case *gi.TextField:
+		vw.SetStretchMaxWidth()
+		vw.SetProp("min-width", units.NewCh(20))
+		vw.SetText(dn.Value)
+		vw.TextFieldSig.Connect(de.This(), func(recv, send ki.Ki, sig int64, data any) {
+			if sig != int64(gi.TextFieldDone) || vw.Text() == dn.Value {
+				return
+			}
+			if err := dn.SetValue(vw.Text()); err != nil {
+				de.ErrorDialog("Invalid Value", err)
+				vw.SetText(dn.Value)
+				return
+			}
+			de.SetChanged(dn)
+		})
+	case *gi.Label:
+		vw.SetText(dn.ValueString())
+	}
+	if wb, ok := ed.(gi.Node2D); ok && ds != nil && ds.Tooltip() != "" {
+		wb.AsWidget().Tooltip = ds.Tooltip()
+	}
+}
+
+// UpdateErrs shows the validation errors, with links to select the nodes
+func (de *DocEditor) UpdateErrs() {
+	el := de.ErrsLabel()
+	if len(de.Errs) == 0 {
+		if de.Schema != nil {
+			el.SetText("Valid")
+		} else {
+			el.SetText("")
+		}
+		return
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<b>%d errors:</b>", len(de.Errs))
+	for i, err := range de.Errs {
+		pth := err.Node.DocPath()
+		if pth == "" {
+			pth = "/"
+		}
+		fmt.Fprintf(&b, `<br><a href="docerr:///%d">%s</a>: %s`, i, html.EscapeString(pth), html.EscapeString(err.Msg))
+	}
+	el.SetText(b.String())
+}
+
+// DocEditorProps are style properties and the toolbar for DocEditor
+var DocEditorProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_NodeFlags,
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+	"max-width":        -1,
+	"max-height":       -1,
+	"ToolBar": ki.PropSlice{
+		{"Open", ki.Props{
+			"label": "Open...",
+			"icon":  "file-open",
+			"desc":  "open a JSON or YAML document",
+			"Args": ki.PropSlice{
+				{"File Name", ki.Props{
+					"default-field": "Filename",
+					"ext":           ".json,.yaml,.yml",
+				}},
+			},
+		}},
+		{"Save", ki.Props{
+			"icon": "file-save",
+			"desc": "save the document to the current file",
+			"updtfunc": ActionUpdateFunc(func(dei any, act *gi.Action) {
+				de := dei.(*DocEditor)
+				act.SetActiveStateUpdt(de.Changed && de.Filename != "")
+			}),
+		}},
+		{"SaveAs", ki.Props{
+			"label": "Save As...",
+			"icon":  "file-save",
+			"desc":  "save the document to a file -- as YAML if it ends in .yaml or .yml, and otherwise as JSON",
+			"Args": ki.PropSlice{
+				{"File Name", ki.Props{
+					"default-field": "Filename",
+					"ext":           ".json,.yaml,.yml",
+				}},
+			},
+		}},
+		{"sep-edit", ki.BlankProp{}},
+		{"AddKey", ki.Props{
+			"label": "Add...",
+			"icon":  "plus",
+			"desc":  "add a new key at the end of the selected object or array, or after the selected key",
+			"updtfunc": ActionUpdateFunc(func(dei any, act *gi.Action) {
+				de := dei.(*DocEditor)
+				act.SetActiveStateUpdt(de.Sel != nil && (de.Sel.IsContainer() || de.Sel.ParentDoc() != nil))
+			}),
+			"Args": ki.PropSlice{
+				{"Key", ki.Props{
+					"desc": "name of the key -- ignored for arrays",
+				}},
+				{"Kind", ki.Props{
+					"default": DocString,
+				}},
+			},
+		}},
+		{"DeleteKey", ki.Props{
+			"label": "Delete",
+			"icon":  "minus",
+			"desc":  "delete the selected key",
+			"updtfunc": ActionUpdateFunc(func(dei any, act *gi.Action) {
+				de := dei.(*DocEditor)
+				act.SetActiveStateUpdt(de.Sel != nil && de.Sel.ParentDoc() != nil)
+			}),
+		}},
+		{"MoveUp", ki.Props{
+			"label": "Up",
+			"icon":  "wedge-up",
+			"desc":  "move the selected key up",
+			"updtfunc": ActionUpdateFunc(func(dei any, act *gi.Action) {
+				de := dei.(*DocEditor)
+				act.SetActiveStateUpdt(de.Sel != nil && de.Sel.ParentDoc() != nil && de.Sel.ParentDoc().Child(0) != de.Sel.This())
+			}),
+		}},
+		{"MoveDown", ki.Props{
+			"label": "Down",
+			"icon":  "wedge-down",
+			"desc":  "move the selected key down",
+			"updtfunc": ActionUpdateFunc(func(dei any, act *gi.Action) {
+				de := dei.(*DocEditor)
+				act.SetActiveStateUpdt(de.Sel != nil && de.Sel.ParentDoc() != nil && de.Sel.ParentDoc().Child(de.Sel.ParentDoc().NumChildren()-1) != de.Sel.This())
+			}),
+		}},
+		{"sep-schema", ki.BlankProp{}},
+		{"OpenSchema", ki.Props{
+			"label": "Schema...",
+			"icon":  "file-open",
+			"desc":  "open a JSON Schema, in JSON or YAML, to validate the document against",
+			"Args": ki.PropSlice{
+				{"File Name", ki.Props{
+					"ext": ".json,.yaml,.yml",
+				}},
+			},
+		}},
+		{"Validate", ki.Props{
+			"icon": "checkmark",
+			"desc": "validate the document against the schema -- this is also done after every change",
+			"updtfunc": ActionUpdateFunc(func(dei any, act *gi.Action) {
+				de := dei.(*DocEditor)
+				act.SetActiveStateUpdt(de.Schema != nil)
+			}),
+		}},
+	},
+}
+
+// DocEditorView opens a window with a DocEditor of the JSON or YAML document
+// in given file, or a new empty JSON document if filename is "", returning
+// the editor
+func DocEditorView(filename string) *DocEditor {
+	width := 1024
+	height := 768
+	wnm := "doc-editor-" + filename
+	wti := "Document Editor"
+	if filename != "" {
+		wti += ": " + filepath.Base(filename)
+	}
+	win, recyc := gi.RecycleMainWindow(wnm, wnm, wti, width, height)
+	if recyc {
+		mfr, err := win.MainFrame()
+		if err == nil {
+			return mfr.Child(0).(*DocEditor)
+		}
+	}
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+	de := AddNewDocEditor(mfr, "doc-editor")
+	de.Viewport = vp
+	de.Filename = gi.FileName(filename)
+	if filename != "" {
+		de.Open(gi.FileName(filename))
+	}
+	if de.Doc == nil {
+		de.SetDoc(NewDocNode(DocObject))
+	}
+
+	win.AddDirty(de)
+
+	vp.UpdateEndNoSig(updt)
+	win.GoStartEventLoop()
+	return de
+}
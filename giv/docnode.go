@@ -0,0 +1,575 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// DocKinds are the kinds of values in a JSON or YAML document (see DocNode)
+type DocKinds int32
+
+const (
+	// DocNull is a null value
+	DocNull DocKinds = iota
+
+	// DocBool is a true or false value
+	DocBool
+
+	// DocNumber is a number value
+	DocNumber
+
+	// DocString is a string value
+	DocString
+
+	// DocObject is an object (mapping) of keys to values, which are the
+	// children of the node
+	DocObject
+
+	// DocArray is an array (sequence) of values, which are the children
+	// of the node
+	DocArray
+
+	DocKindsN
+)
+
+//go:generate stringer -type=DocKinds
+
+var KiT_DocKinds = kit.Enums.AddEnum(DocKindsN, kit.NotBitFlag, nil)
+
+// JSONType returns the JSON Schema name of the kind, e.g., "boolean"
+func (dk DocKinds) JSONType() string {
+	switch dk {
+	case DocBool:
+		return "boolean"
+	case DocNumber:
+		return "number"
+	case DocString:
+		return "string"
+	case DocObject:
+		return "object"
+	case DocArray:
+		return "array"
+	}
+	return "null"
+}
+
+// DocNode is a node of a JSON or YAML document, as edited in a DocEditor.
+// Objects and arrays have the child nodes for their members and elements,
+// in order, with the name of each child being its key, or its index in an
+// array.  The comments before each node and after it on the same line
+// are preserved, for JSON with comments (// and /* */) and YAML (#).
+type DocNode struct {
+	ki.Node
+	Kind        DocKinds `desc:"kind of value"`
+	Value       string   `desc:"value of a scalar: the number text for DocNumber, the string for DocString, and true or false for DocBool"`
+	Comment     string   `desc:"comment lines before the node, without the comment markers"`
+	LineComment string   `desc:"comment after the value on the same line, without the comment marker"`
+	EndComment  string   `desc:"comment lines at the end of an object or array, or after the value of a document root that is not -- comments after the document are kept at the end of a root object or array"`
+}
+
+var KiT_DocNode = kit.Types.AddType(&DocNode{}, nil)
+
+// AddNewDocNode adds a new document node of given kind to given parent
+// node, with given key (ignored for arrays, where the name is the index)
+func AddNewDocNode(parent ki.Ki, key string, kind DocKinds) *DocNode {
+	if pn, ok := parent.(*DocNode); ok && pn.Kind == DocArray {
+		key = strconv.Itoa(pn.NumChildren())
+	}
+	dn := parent.AddNewChild(KiT_DocNode, key).(*DocNode)
+	dn.SetKind(kind)
+	return dn
+}
+
+// NewDocNode returns a new document root node of given kind
+func NewDocNode(kind DocKinds) *DocNode {
+	dn := &DocNode{}
+	dn.InitName(dn, "root")
+	dn.SetKind(kind)
+	return dn
+}
+
+// IsContainer returns true if the node is an object or array
+func (dn *DocNode) IsContainer() bool {
+	return dn.Kind == DocObject || dn.Kind == DocArray
+}
+
+// ParentDoc returns the parent document node, or nil for the root
+func (dn *DocNode) ParentDoc() *DocNode {
+	pn, _ := dn.Parent().(*DocNode)
+	return pn
+}
+
+// SetKind sets the kind of the node, deleting any children if it is no
+// longer a container, and setting a default value for scalars if the
+// current value is not valid for the kind
+func (dn *DocNode) SetKind(kind DocKinds) {
+	updt := dn.UpdateStart()
+	defer dn.UpdateEnd(updt)
+	if !(kind == DocObject || kind == DocArray) && dn.HasChildren() {
+		dn.DeleteChildren(ki.DestroyKids)
+	}
+	if kind == DocArray && dn.Kind != DocArray {
+		dn.RenumberKids()
+	}
+	dn.Kind = kind
+	switch kind {
+	case DocBool:
+		if dn.Value != "true" && dn.Value != "false" {
+			dn.Value = "false"
+		}
+	case DocNumber:
+		if !DocIsNumber(dn.Value) {
+			dn.Value = "0"
+		}
+	case DocString:
+	default:
+		dn.Value = ""
+	}
+}
+
+// SetValue sets the value of a scalar node from given text, returning an
+// error if it is not valid for the kind of the node
+func (dn *DocNode) SetValue(val string) error {
+	switch dn.Kind {
+	case DocBool:
+		if val != "true" && val != "false" {
+			return fmt.Errorf("invalid boolean value: %q -- must be true or false", val)
+		}
+	case DocNumber:
+		val = strings.TrimSpace(val)
+		if !DocIsNumber(val) {
+			return fmt.Errorf("invalid number value: %q", val)
+		}
+	case DocNull, DocObject, DocArray:
+		return fmt.Errorf("%v does not have a value", dn.Kind.JSONType())
+	}
+	updt := dn.UpdateStart()
+	dn.Value = val
+	dn.UpdateEnd(updt)
+	return nil
+}
+
+// RenumberKids sets the names of the children to their indexes, for arrays
+func (dn *DocNode) RenumberKids() {
+	for i, k := range dn.Kids {
+		k.SetName(strconv.Itoa(i))
+	}
+}
+
+// Key returns the key of the node in its parent object, or its index
+// in brackets in an array
+func (dn *DocNode) Key() string {
+	if pn := dn.ParentDoc(); pn != nil && pn.Kind == DocArray {
+		return "[" + dn.Nm + "]"
+	}
+	return dn.Nm
+}
+
+// DocPath returns the path of the node from the document root, as a
+// JSON Pointer (RFC 6901), e.g., /items/0/name -- "" for the root
+func (dn *DocNode) DocPath() string {
+	pn := dn.ParentDoc()
+	if pn == nil {
+		return ""
+	}
+	nm := strings.ReplaceAll(strings.ReplaceAll(dn.Nm, "~", "~0"), "/", "~1")
+	return pn.DocPath() + "/" + nm
+}
+
+// Label is the label of the node in the DocEditor tree: its Key
+func (dn *DocNode) Label() string {
+	if dn.ParentDoc() == nil {
+		return "(root)"
+	}
+	return dn.Key()
+}
+
+// ValueString returns the value of the node for display: the value of
+// scalars, with strings quoted, and the number of items in containers
+func (dn *DocNode) ValueString() string {
+	switch dn.Kind {
+	case DocNull:
+		return "null"
+	case DocString:
+		return strconv.Quote(dn.Value)
+	case DocObject:
+		return fmt.Sprintf("{%d}", dn.NumChildren())
+	case DocArray:
+		return fmt.Sprintf("[%d]", dn.NumChildren())
+	}
+	return dn.Value
+}
+
+// TreeTableValue returns the "Type" and "Value" columns for the TreeTable
+// of the DocEditor
+func (dn *DocNode) TreeTableValue(col string) (any, bool) {
+	switch col {
+	case "Type":
+		return dn.Kind.JSONType(), true
+	case "Value":
+		return dn.ValueString(), true
+	}
+	return nil, false
+}
+
+// ToAny returns the value of the node as the standard Go representation
+// of JSON values: nil, bool, float64, string, map[string]any and []any
+func (dn *DocNode) ToAny() any {
+	switch dn.Kind {
+	case DocBool:
+		return dn.Value == "true"
+	case DocNumber:
+		f, _ := strconv.ParseFloat(dn.Value, 64)
+		return f
+	case DocString:
+		return dn.Value
+	case DocObject:
+		m := make(map[string]any, dn.NumChildren())
+		for _, k := range dn.Kids {
+			m[k.Name()] = k.(*DocNode).ToAny()
+		}
+		return m
+	case DocArray:
+		a := make([]any, dn.NumChildren())
+		for i, k := range dn.Kids {
+			a[i] = k.(*DocNode).ToAny()
+		}
+		return a
+	}
+	return nil
+}
+
+// DocIsNumber returns true if given text is a valid JSON number
+func DocIsNumber(s string) bool {
+	if s == "" || !(s[0] == '-' || (s[0] >= '0' && s[0] <= '9')) {
+		return false
+	}
+	var f float64
+	return json.Unmarshal([]byte(s), &f) == nil
+}
+
+// docQuote returns given string as a JSON string, without escaping html
+func docQuote(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// docCommentLines returns the lines of given comment
+func docCommentLines(cmt string) []string {
+	if cmt == "" {
+		return nil
+	}
+	return strings.Split(cmt, "\n")
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  JSON
+
+// ParseJSONDoc parses given JSON text into a document tree, which is
+// returned as its root node.  Comments (// and /* */) are allowed, as in
+// JSON with comments, and are preserved with the nodes, as are trailing
+// commas.
+func ParseJSONDoc(src []byte) (*DocNode, error) {
+	p := &docJSONParser{src: src}
+	root := NewDocNode(DocNull)
+	p.skipSpace()
+	root.Comment = p.takeComment()
+	if err := p.parseValue(root); err != nil {
+		return nil, err
+	}
+	root.LineComment = p.lineComment()
+	p.skipSpace()
+	if cmt := p.takeComment(); cmt != "" {
+		root.EndComment = strings.TrimPrefix(root.EndComment+"\n"+cmt, "\n")
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected text after the value")
+	}
+	return root, nil
+}
+
+// docJSONParser parses JSON with comments
+type docJSONParser struct {
+	src  []byte
+	pos  int
+	cmts []string
+}
+
+// errorf returns an error with the current line number
+func (p *docJSONParser) errorf(format string, args ...any) error {
+	ln := bytes.Count(p.src[:p.pos], []byte("\n")) + 1
+	return fmt.Errorf("line %d: %s", ln, fmt.Sprintf(format, args...))
+}
+
+// peek returns the current byte, or 0 at the end
+func (p *docJSONParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// comment parses a comment at the current position, if any, returning it
+// without the markers
+func (p *docJSONParser) comment() (string, bool) {
+	rest := p.src[p.pos:]
+	switch {
+	case bytes.HasPrefix(rest, []byte("//")):
+		end := bytes.IndexByte(rest, '\n')
+		if end < 0 {
+			end = len(rest)
+		}
+		p.pos += end
+		return strings.TrimSpace(string(rest[2:end])), true
+	case bytes.HasPrefix(rest, []byte("/*")):
+		end := bytes.Index(rest[2:], []byte("*/"))
+		if end < 0 {
+			p.pos = len(p.src)
+			return strings.TrimSpace(string(rest[2:])), true
+		}
+		p.pos += end + 4
+		return strings.TrimSpace(string(rest[2 : end+2])), true
+	}
+	return "", false
+}
+
+// skipSpace skips white space, collecting any comments
+func (p *docJSONParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+			continue
+		}
+		cmt, ok := p.comment()
+		if !ok {
+			return
+		}
+		p.cmts = append(p.cmts, cmt)
+	}
+}
+
+// takeComment returns the collected comments, and clears them
+func (p *docJSONParser) takeComment() string {
+	cmt := strings.Join(p.cmts, "\n")
+	p.cmts = nil
+	return cmt
+}
+
+// lineComment returns the comment on the rest of the current line, if any
+func (p *docJSONParser) lineComment() string {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+	if cmt, ok := p.comment(); ok {
+		return cmt
+	}
+	return ""
+}
+
+// parseValue parses the value at the current position into given node
+func (p *docJSONParser) parseValue(dn *DocNode) error {
+	switch c := p.peek(); {
+	case c == '{':
+		return p.parseContainer(dn, DocObject, '}')
+	case c == '[':
+		return p.parseContainer(dn, DocArray, ']')
+	case c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		dn.Kind = DocString
+		dn.Value = s
+	case c == '-' || (c >= '0' && c <= '9'):
+		st := p.pos
+		for p.pos < len(p.src) && bytes.IndexByte([]byte("+-.eE0123456789"), p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		num := string(p.src[st:p.pos])
+		if !DocIsNumber(num) {
+			return p.errorf("invalid number: %s", num)
+		}
+		dn.Kind = DocNumber
+		dn.Value = num
+	default:
+		for _, lit := range []string{"true", "false", "null"} {
+			if bytes.HasPrefix(p.src[p.pos:], []byte(lit)) {
+				p.pos += len(lit)
+				if lit == "null" {
+					dn.Kind = DocNull
+				} else {
+					dn.Kind = DocBool
+					dn.Value = lit
+				}
+				return nil
+			}
+		}
+		if c == 0 {
+			return p.errorf("unexpected end of text")
+		}
+		return p.errorf("unexpected character: %q", c)
+	}
+	return nil
+}
+
+// parseString parses the string at the current position
+func (p *docJSONParser) parseString() (string, error) {
+	st := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '\n':
+			return "", p.errorf("newline in string")
+		case '"':
+			p.pos++
+			var s string
+			if err := json.Unmarshal(p.src[st:p.pos], &s); err != nil {
+				return "", p.errorf("invalid string: %v", err)
+			}
+			return s, nil
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseContainer parses an object or array at the current position
+func (p *docJSONParser) parseContainer(dn *DocNode, kind DocKinds, end byte) error {
+	dn.Kind = kind
+	p.pos++
+	for {
+		p.skipSpace()
+		if p.peek() == end {
+			p.pos++
+			dn.EndComment = p.takeComment()
+			return nil
+		}
+		cmt := p.takeComment()
+		key := ""
+		if kind == DocObject {
+			if p.peek() != '"' {
+				return p.errorf("expected a string key or }")
+			}
+			var err error
+			if key, err = p.parseString(); err != nil {
+				return err
+			}
+			p.skipSpace()
+			if p.peek() != ':' {
+				return p.errorf("expected : after key %q", key)
+			}
+			p.pos++
+			p.skipSpace()
+			if c := p.takeComment(); c != "" { // comments between key and value
+				cmt = strings.TrimPrefix(cmt+"\n"+c, "\n")
+			}
+		}
+		kid := AddNewDocNode(dn, key, DocNull)
+		kid.Comment = cmt
+		if err := p.parseValue(kid); err != nil {
+			return err
+		}
+		for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+			p.pos++
+		}
+		comma := p.peek() == ','
+		if comma {
+			p.pos++
+		}
+		kid.LineComment = p.lineComment()
+		if !comma {
+			p.skipSpace()
+			switch p.peek() {
+			case ',': // comma on the next line
+				p.pos++
+			case end:
+			default:
+				return p.errorf("expected , or %c", end)
+			}
+		}
+	}
+}
+
+// JSON returns the document under this node as JSON text, indented with
+// tabs.  The comments are written as // comments, so the text is only
+// standard JSON if there are no comments.
+func (dn *DocNode) JSON() []byte {
+	var b bytes.Buffer
+	dn.writeJSONComment(&b, dn.Comment, 0)
+	dn.writeJSON(&b, 0)
+	if dn.LineComment != "" {
+		b.WriteString(" // " + dn.LineComment)
+	}
+	b.WriteString("\n")
+	if dn.ParentDoc() == nil && !dn.IsContainer() {
+		dn.writeJSONComment(&b, dn.EndComment, 0)
+	}
+	return b.Bytes()
+}
+
+// writeJSONComment writes given comment lines at given indent
+func (dn *DocNode) writeJSONComment(b *bytes.Buffer, cmt string, ind int) {
+	for _, ln := range docCommentLines(cmt) {
+		b.WriteString(strings.Repeat("\t", ind))
+		b.WriteString(strings.TrimSpace("// " + ln))
+		b.WriteString("\n")
+	}
+}
+
+// writeJSON writes the value of the node, for given indent of the node
+func (dn *DocNode) writeJSON(b *bytes.Buffer, ind int) {
+	switch dn.Kind {
+	case DocNull:
+		b.WriteString("null")
+	case DocString:
+		b.WriteString(docQuote(dn.Value))
+	case DocObject, DocArray:
+		st, end := "{", "}"
+		if dn.Kind == DocArray {
+			st, end = "[", "]"
+		}
+		if !dn.HasChildren() && dn.EndComment == "" {
+			b.WriteString(st + end)
+			return
+		}
+		b.WriteString(st + "\n")
+		kidInd := strings.Repeat("\t", ind+1)
+		for i, k := range dn.Kids {
+			kn := k.(*DocNode)
+			dn.writeJSONComment(b, kn.Comment, ind+1)
+			b.WriteString(kidInd)
+			if dn.Kind == DocObject {
+				b.WriteString(docQuote(kn.Nm) + ": ")
+			}
+			kn.writeJSON(b, ind+1)
+			if i < len(dn.Kids)-1 {
+				b.WriteString(",")
+			}
+			if kn.LineComment != "" {
+				b.WriteString(" // " + kn.LineComment)
+			}
+			b.WriteString("\n")
+		}
+		dn.writeJSONComment(b, dn.EndComment, ind+1)
+		b.WriteString(strings.Repeat("\t", ind) + end)
+	default:
+		b.WriteString(dn.Value)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"testing"
+)
+
+func TestJSONDocRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"scalars", "{\n\t\"a\": 1,\n\t\"b\": true,\n\t\"c\": null,\n\t\"d\": \"<text>\"\n}\n"},
+		{"comments", "// head\n{\n\t// before a\n\t\"a\": 1, // one\n\t\"b\": [\n\t\t\"x\" // ex\n\t\t// end of b\n\t]\n\t// end of doc\n}\n"},
+		{"empty", "{\n\t\"a\": {},\n\t\"b\": []\n}\n"},
+	}
+	for _, tt := range tests {
+		dn, err := ParseJSONDoc([]byte(tt.src))
+		if err != nil {
+			t.Errorf("%s: parse error: %v", tt.name, err)
+			continue
+		}
+		if got := string(dn.JSON()); got != tt.src {
+			t.Errorf("%s: round trip:\n%s\nwant:\n%s", tt.name, got, tt.src)
+		}
+	}
+}
+
+func TestDocNodeSetValue(t *testing.T) {
+	tests := []struct {
+		kind DocKinds
+		val  string
+		ok   bool
+	}{
+		{DocBool, "true", true},
+		{DocBool, "yes", false},
+		{DocNumber, " 1.5e3 ", true},
+		{DocNumber, "0x10", false},
+		{DocString, "", true},
+		{DocNull, "x", false},
+		{DocArray, "x", false},
+	}
+	for _, tt := range tests {
+		dn := NewDocNode(tt.kind)
+		if err := dn.SetValue(tt.val); (err == nil) != tt.ok {
+			t.Errorf("%v %q: got error: %v", tt.kind, tt.val, err)
+		}
+	}
+}
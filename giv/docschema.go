@@ -0,0 +1,282 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DocSchema is a JSON Schema for validating the documents edited in a
+// DocEditor.  It supports the commonly used subset of the standard: type
+// (including integer), properties, required, additionalProperties, items,
+// enum, const, minimum, maximum, minLength, maxLength, pattern, minItems
+// and maxItems.  The title and description are shown as tooltips, and
+// enum values are offered as choices in the editor.
+type DocSchema struct {
+	Type                 any                   `json:"type,omitempty" desc:"name of the type, or list of names of the allowed types: null, boolean, integer, number, string, object, array"`
+	Title                string                `json:"title,omitempty" desc:"title of the value"`
+	Description          string                `json:"description,omitempty" desc:"description of the value"`
+	Properties           map[string]*DocSchema `json:"properties,omitempty" desc:"schemas of the properties of an object"`
+	Required             []string              `json:"required,omitempty" desc:"names of the required properties of an object"`
+	AdditionalProperties json.RawMessage       `json:"additionalProperties,omitempty" desc:"false if no properties other than Properties are allowed, or the schema of the other properties"`
+	Items                *DocSchema            `json:"items,omitempty" desc:"schema of the elements of an array"`
+	Enum                 []any                 `json:"enum,omitempty" desc:"allowed values"`
+	Const                any                   `json:"const,omitempty" desc:"the only allowed value"`
+	Minimum              *float64              `json:"minimum,omitempty" desc:"minimum of a number"`
+	Maximum              *float64              `json:"maximum,omitempty" desc:"maximum of a number"`
+	MinLength            *int                  `json:"minLength,omitempty" desc:"minimum length of a string"`
+	MaxLength            *int                  `json:"maxLength,omitempty" desc:"maximum length of a string"`
+	Pattern              string                `json:"pattern,omitempty" desc:"regular expression that a string must match"`
+	MinItems             *int                  `json:"minItems,omitempty" desc:"minimum number of elements of an array"`
+	MaxItems             *int                  `json:"maxItems,omitempty" desc:"maximum number of elements of an array"`
+	Default              any                   `json:"default,omitempty" desc:"default value"`
+}
+
+// DocSchemaError is an error from validating a document against a
+// DocSchema, at a node of the document
+type DocSchemaError struct {
+	Node *DocNode `desc:"node with the error"`
+	Msg  string   `desc:"the error message"`
+}
+
+func (de *DocSchemaError) Error() string {
+	return de.Node.DocPath() + ": " + de.Msg
+}
+
+// ParseDocSchema parses a DocSchema from given JSON or YAML text
+func ParseDocSchema(src []byte, yaml bool) (*DocSchema, error) {
+	var dn *DocNode
+	var err error
+	if yaml {
+		dn, err = ParseYAMLDoc(src)
+	} else {
+		dn, err = ParseJSONDoc(src)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(dn.ToAny())
+	if err != nil {
+		return nil, err
+	}
+	ds := &DocSchema{}
+	if err := json.Unmarshal(b, ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// OpenDocSchema opens a DocSchema from given JSON or YAML (.yaml, .yml) file
+func OpenDocSchema(filename string) (*DocSchema, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ParseDocSchema(b, ext == ".yaml" || ext == ".yml")
+}
+
+// Types returns the names of the allowed types, or nil if any type is allowed
+func (ds *DocSchema) Types() []string {
+	switch tv := ds.Type.(type) {
+	case string:
+		return []string{tv}
+	case []any:
+		ts := make([]string, 0, len(tv))
+		for _, t := range tv {
+			if s, ok := t.(string); ok {
+				ts = append(ts, s)
+			}
+		}
+		return ts
+	}
+	return nil
+}
+
+// Kinds returns the DocKinds of the allowed types, or nil if any type is
+// allowed
+func (ds *DocSchema) Kinds() []DocKinds {
+	var ks []DocKinds
+	for _, t := range ds.Types() {
+		if t == "integer" {
+			t = "number"
+		}
+		for k := DocNull; k < DocKindsN; k++ {
+			if k.JSONType() == t {
+				ks = append(ks, k)
+			}
+		}
+	}
+	return ks
+}
+
+// Tooltip returns the title and description of the schema, for tooltips
+func (ds *DocSchema) Tooltip() string {
+	switch {
+	case ds.Title != "" && ds.Description != "":
+		return ds.Title + ": " + ds.Description
+	case ds.Title != "":
+		return ds.Title
+	}
+	return ds.Description
+}
+
+// additional returns the schema of the additional properties, or nil,
+// and false if they are not allowed
+func (ds *DocSchema) additional() (*DocSchema, bool) {
+	raw := strings.TrimSpace(string(ds.AdditionalProperties))
+	switch raw {
+	case "":
+		return nil, true
+	case "false":
+		return nil, false
+	}
+	as := &DocSchema{}
+	if err := json.Unmarshal(ds.AdditionalProperties, as); err != nil {
+		return nil, true
+	}
+	return as, true
+}
+
+// KidSchema returns the schema of the member with given key of an object,
+// or of the elements of an array, or nil if there is none
+func (ds *DocSchema) KidSchema(parent *DocNode, key string) *DocSchema {
+	if parent.Kind == DocArray {
+		return ds.Items
+	}
+	if ps, has := ds.Properties[key]; has {
+		return ps
+	}
+	as, _ := ds.additional()
+	return as
+}
+
+// NodeSchema returns the schema for given node of a document that this is
+// the schema of, or nil if there is none
+func (ds *DocSchema) NodeSchema(dn *DocNode) *DocSchema {
+	pn := dn.ParentDoc()
+	if pn == nil {
+		return ds
+	}
+	ps := ds.NodeSchema(pn)
+	if ps == nil {
+		return nil
+	}
+	return ps.KidSchema(pn, dn.Nm)
+}
+
+// Validate validates the document under given node against the schema,
+// returning all of the errors
+func (ds *DocSchema) Validate(dn *DocNode) []*DocSchemaError {
+	var errs []*DocSchemaError
+	ds.validate(dn, &errs)
+	return errs
+}
+
+func (ds *DocSchema) validate(dn *DocNode, errs *[]*DocSchemaError) {
+	errf := func(format string, args ...any) {
+		*errs = append(*errs, &DocSchemaError{Node: dn, Msg: fmt.Sprintf(format, args...)})
+	}
+	if ts := ds.Types(); len(ts) > 0 && !ds.typeOK(dn, ts) {
+		errf("type is %s, must be %s", dn.Kind.JSONType(), strings.Join(ts, " or "))
+		return
+	}
+	val := dn.ToAny()
+	if len(ds.Enum) > 0 {
+		ok := false
+		for _, ev := range ds.Enum {
+			if reflect.DeepEqual(ev, val) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			errf("value %s is not one of the allowed values", dn.ValueString())
+		}
+	}
+	if ds.Const != nil && !reflect.DeepEqual(ds.Const, val) {
+		errf("value %s must be %v", dn.ValueString(), ds.Const)
+	}
+	switch dn.Kind {
+	case DocNumber:
+		f := val.(float64)
+		if ds.Minimum != nil && f < *ds.Minimum {
+			errf("value %s is less than the minimum of %g", dn.Value, *ds.Minimum)
+		}
+		if ds.Maximum != nil && f > *ds.Maximum {
+			errf("value %s is greater than the maximum of %g", dn.Value, *ds.Maximum)
+		}
+	case DocString:
+		n := utf8.RuneCountInString(dn.Value)
+		if ds.MinLength != nil && n < *ds.MinLength {
+			errf("length %d is less than the minimum of %d", n, *ds.MinLength)
+		}
+		if ds.MaxLength != nil && n > *ds.MaxLength {
+			errf("length %d is greater than the maximum of %d", n, *ds.MaxLength)
+		}
+		if ds.Pattern != "" {
+			re, err := regexp.Compile(ds.Pattern)
+			if err != nil {
+				errf("invalid pattern in schema: %v", err)
+			} else if !re.MatchString(dn.Value) {
+				errf("value %s does not match the pattern %s", dn.ValueString(), ds.Pattern)
+			}
+		}
+	case DocObject:
+		for _, req := range ds.Required {
+			if dn.ChildByName(req, 0) == nil {
+				errf("required property %q is missing", req)
+			}
+		}
+		_, addOK := ds.additional()
+		for _, k := range dn.Kids {
+			kn := k.(*DocNode)
+			if _, has := ds.Properties[kn.Nm]; !has && !addOK {
+				*errs = append(*errs, &DocSchemaError{Node: kn, Msg: "property is not allowed"})
+				continue
+			}
+			if ks := ds.KidSchema(dn, kn.Nm); ks != nil {
+				ks.validate(kn, errs)
+			}
+		}
+	case DocArray:
+		n := dn.NumChildren()
+		if ds.MinItems != nil && n < *ds.MinItems {
+			errf("%d elements is less than the minimum of %d", n, *ds.MinItems)
+		}
+		if ds.MaxItems != nil && n > *ds.MaxItems {
+			errf("%d elements is greater than the maximum of %d", n, *ds.MaxItems)
+		}
+		if ds.Items != nil {
+			for _, k := range dn.Kids {
+				ds.Items.validate(k.(*DocNode), errs)
+			}
+		}
+	}
+}
+
+// typeOK returns true if the type of given node is one of given types
+func (ds *DocSchema) typeOK(dn *DocNode, ts []string) bool {
+	for _, t := range ts {
+		if t == "integer" && dn.Kind == DocNumber {
+			if f := dn.ToAny().(float64); f == math.Trunc(f) {
+				return true
+			}
+			continue
+		}
+		if t == dn.Kind.JSONType() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocSchemaValidate(t *testing.T) {
+	schema := `
+type: object
+required: ["name", "port"]
+additionalProperties: false
+properties:
+  name:
+    type: string
+    minLength: 2
+    pattern: "^[a-z]+$"
+  port:
+    type: integer
+    minimum: 1
+    maximum: 65535
+  mode:
+    enum: ["fast", "slow"]
+  tags:
+    type: array
+    maxItems: 2
+    items:
+      type: string
+`
+	ds, err := ParseDocSchema([]byte(schema), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		doc  string
+		errs []string
+	}{
+		{"valid", "name: web\nport: 80\nmode: fast\ntags: [\"a\"]", nil},
+		{"type", "name: web\nport: eighty", []string{"/port: type is string, must be integer"}},
+		{"integer", "name: web\nport: 80.5", []string{"/port: type is number, must be integer"}},
+		{"range", "name: web\nport: 0", []string{"/port: value 0 is less than the minimum of 1"}},
+		{"required", "name: web", []string{`: required property "port" is missing`}},
+		{"additional", "name: web\nport: 80\nhost: x", []string{"/host: property is not allowed"}},
+		{"enum", "name: web\nport: 80\nmode: medium", []string{`/mode: value "medium" is not one of the allowed values`}},
+		{"string", "name: W\nport: 80", []string{
+			"/name: length 1 is less than the minimum of 2",
+			`/name: value "W" does not match the pattern ^[a-z]+$`,
+		}},
+		{"items", "name: web\nport: 80\ntags:\n  - a\n  - 2\n  - c", []string{
+			"/tags: 3 elements is greater than the maximum of 2",
+			"/tags/1: type is number, must be string",
+		}},
+		{"root", "- web", []string{": type is array, must be object"}},
+	}
+	for _, tt := range tests {
+		dn, err := ParseYAMLDoc([]byte(tt.doc))
+		if err != nil {
+			t.Errorf("%s: parse error: %v", tt.name, err)
+			continue
+		}
+		errs := ds.Validate(dn)
+		var got []string
+		for _, e := range errs {
+			got = append(got, e.Error())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.errs, "\n") {
+			t.Errorf("%s: got errors:\n%s\nwant:\n%s", tt.name, strings.Join(got, "\n"), strings.Join(tt.errs, "\n"))
+		}
+	}
+}
+
+func TestDocSchemaParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		yaml bool
+	}{
+		{"json syntax", `{"type": "object",}x`, false},
+		{"yaml syntax", "type: object\n  items: 1\n", true},
+		{"field type", `{"minimum": "one"}`, false},
+		{"yaml field type", "required: name\n", true},
+	}
+	for _, tt := range tests {
+		if _, err := ParseDocSchema([]byte(tt.src), tt.yaml); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
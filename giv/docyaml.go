@@ -0,0 +1,548 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goki/ki/ints"
+)
+
+// ParseYAMLDoc parses given YAML text into a document tree, which is
+// returned as its root node, preserving the # comments with the nodes.
+// It supports the block style of YAML that is typical of configuration
+// files: mappings, sequences, plain and quoted scalars, literal (|) and
+// folded (>) block scalars, and flow collections that are valid JSON
+// (e.g., [] and {}).  Anchors, aliases, tags and multiple documents are
+// not supported, and return an error.
+func ParseYAMLDoc(src []byte) (*DocNode, error) {
+	p := &docYAMLParser{}
+	for i, ln := range strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(ln, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		txt := strings.TrimLeft(ln, " ")
+		yl := docYAMLLine{ln: i + 1, ind: len(ln) - len(txt), raw: ln}
+		yl.lind = yl.ind
+		yl.text, yl.cmt = docYAMLSplitComment(txt)
+		p.lines = append(p.lines, yl)
+	}
+	root := NewDocNode(DocNull)
+	l := p.next()
+	if l != nil && l.ind == 0 && (l.text == "---" || strings.HasPrefix(l.text, "--- ")) {
+		l.text = strings.TrimSpace(strings.TrimPrefix(l.text, "---"))
+		if l.text == "" {
+			p.i++
+		}
+	}
+	root.Comment = p.takeComment()
+	if l = p.next(); l != nil {
+		if err := p.parseBlock(root, l.ind); err != nil {
+			return nil, err
+		}
+	}
+	if l = p.next(); l != nil {
+		if l.ind == 0 && (l.text == "---" || strings.HasPrefix(l.text, "--- ")) {
+			return nil, fmt.Errorf("line %d: multiple documents are not supported", l.ln)
+		}
+		if l.text != "..." {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.ln)
+		}
+		p.i++
+		p.next()
+	}
+	if cmt := p.takeComment(); cmt != "" {
+		root.EndComment = strings.TrimPrefix(root.EndComment+"\n"+cmt, "\n")
+	}
+	return root, nil
+}
+
+// docYAMLLine is a line of YAML text
+type docYAMLLine struct {
+	ln   int    // line number, from 1
+	ind  int    // indentation of the text
+	lind int    // indentation of the line, before the dash of a sequence item
+	text string // text, without indentation and comment
+	cmt  string // comment at the end of the line, or the whole line
+	raw  string // original line, for block scalars
+}
+
+// docYAMLParser parses YAML text
+type docYAMLParser struct {
+	lines   []docYAMLLine
+	i       int
+	cmts    []string
+	cmtInds []int // indentation of each of the cmts
+}
+
+// next returns the next line with text, collecting the comments of any
+// comment lines before it, or nil at the end
+func (p *docYAMLParser) next() *docYAMLLine {
+	for ; p.i < len(p.lines); p.i++ {
+		l := &p.lines[p.i]
+		if l.text != "" {
+			return l
+		}
+		if l.cmt != "" {
+			p.cmts = append(p.cmts, l.cmt)
+			p.cmtInds = append(p.cmtInds, l.ind)
+			l.cmt = "" // only collect once
+		}
+	}
+	return nil
+}
+
+// takeComment returns the collected comments, and clears them
+func (p *docYAMLParser) takeComment() string {
+	cmt := strings.Join(p.cmts, "\n")
+	p.cmts, p.cmtInds = nil, nil
+	return cmt
+}
+
+// takeEndComment returns the collected comments that are indented at
+// least given indentation of a block that ends before the next line,
+// which is indented less, and clears them -- the rest are left for the
+// next node
+func (p *docYAMLParser) takeEndComment(ind int) string {
+	if l := p.next(); l != nil && l.ind >= ind {
+		return ""
+	}
+	n := 0
+	for n < len(p.cmts) && p.cmtInds[n] >= ind {
+		n++
+	}
+	cmt := strings.Join(p.cmts[:n], "\n")
+	p.cmts, p.cmtInds = p.cmts[n:], p.cmtInds[n:]
+	return cmt
+}
+
+// docYAMLIsDocMarker returns true if given line is the start (---) or end
+// (...) of a document
+func docYAMLIsDocMarker(l *docYAMLLine) bool {
+	return l.ind == 0 && (l.text == "---" || strings.HasPrefix(l.text, "--- ") || l.text == "...")
+}
+
+// docYAMLIsSeqItem returns true if given line text is a sequence item
+func docYAMLIsSeqItem(txt string) bool {
+	return txt == "-" || strings.HasPrefix(txt, "- ")
+}
+
+// parseBlock parses the mapping, sequence or scalar starting at the
+// current line, which has given indentation, into given node
+func (p *docYAMLParser) parseBlock(dn *DocNode, ind int) error {
+	l := p.next()
+	if docYAMLIsSeqItem(l.text) {
+		dn.Kind = DocArray
+		for l = p.next(); l != nil && l.ind == ind && docYAMLIsSeqItem(l.text); l = p.next() {
+			kid := AddNewDocNode(dn, "", DocNull)
+			kid.Comment = p.takeComment()
+			rest := strings.TrimLeft(l.text[1:], " ")
+			if rest == "" {
+				kid.LineComment = l.cmt
+				p.i++
+				if err := p.parseNested(kid, ind, false); err != nil {
+					return err
+				}
+				continue
+			}
+			// parse the rest as a line at its own indentation
+			l.ind += len(l.text) - len(rest)
+			l.text = rest
+			if err := p.parseBlock(kid, l.ind); err != nil {
+				return err
+			}
+		}
+		return p.endBlock(dn, ind)
+	}
+	if _, _, ok := docYAMLSplitKey(l.text); !ok {
+		p.i++
+		dn.LineComment = l.cmt
+		return p.parseScalar(dn, l.text, l)
+	}
+	dn.Kind = DocObject
+	for l = p.next(); l != nil && l.ind == ind && !docYAMLIsSeqItem(l.text) && !docYAMLIsDocMarker(l); l = p.next() {
+		key, rest, ok := docYAMLSplitKey(l.text)
+		if !ok {
+			return fmt.Errorf("line %d: expected a key: %s", l.ln, l.text)
+		}
+		kid := AddNewDocNode(dn, key, DocNull)
+		kid.Comment = p.takeComment()
+		kid.LineComment = l.cmt
+		p.i++
+		if rest == "" {
+			if err := p.parseNested(kid, ind, true); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.parseScalar(kid, rest, l); err != nil {
+			return err
+		}
+	}
+	return p.endBlock(dn, ind)
+}
+
+// endBlock ends the mapping or sequence of given node, with given
+// indentation, setting its end comment, and returning an error if the
+// next line is indented more than it
+func (p *docYAMLParser) endBlock(dn *DocNode, ind int) error {
+	if l := p.next(); l != nil && l.ind > ind {
+		return fmt.Errorf("line %d: unexpected indentation", l.ln)
+	}
+	dn.EndComment = p.takeEndComment(ind)
+	return nil
+}
+
+// parseNested parses the value after a key or sequence item with no value
+// on its line, which is a block on the following lines indented more than
+// given indentation of the key or item, or null if there is none.  A
+// sequence may be at the same indentation as the key of a mapping.
+func (p *docYAMLParser) parseNested(dn *DocNode, ind int, isKey bool) error {
+	l := p.next()
+	if l == nil {
+		return nil
+	}
+	if l.ind > ind || (isKey && l.ind == ind && docYAMLIsSeqItem(l.text)) {
+		if cmt := p.takeComment(); cmt != "" {
+			dn.Comment = strings.TrimPrefix(dn.Comment+"\n"+cmt, "\n")
+		}
+		return p.parseBlock(dn, l.ind)
+	}
+	return nil
+}
+
+// parseScalar parses given scalar value text, from given line, into given
+// node, including block scalars on the following lines
+func (p *docYAMLParser) parseScalar(dn *DocNode, txt string, l *docYAMLLine) error {
+	switch txt[0] {
+	case '"':
+		s, err := docYAMLUnquote(txt)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", l.ln, err)
+		}
+		dn.Kind = DocString
+		dn.Value = s
+	case '\'':
+		if len(txt) < 2 || txt[len(txt)-1] != '\'' {
+			return fmt.Errorf("line %d: unterminated string: %s", l.ln, txt)
+		}
+		dn.Kind = DocString
+		dn.Value = strings.ReplaceAll(txt[1:len(txt)-1], "''", "'")
+	case '[', '{':
+		fn, err := ParseJSONDoc([]byte(txt))
+		if err != nil {
+			return fmt.Errorf("line %d: only flow collections that are valid JSON are supported: %v", l.ln, err)
+		}
+		dn.Kind = fn.Kind
+		for fn.HasChildren() {
+			k := fn.Child(0)
+			fn.DeleteChildAtIndex(0, false)
+			dn.AddChild(k)
+		}
+	case '|', '>':
+		dn.Kind = DocString
+		dn.Value = p.blockScalar(txt, l.lind)
+	case '&', '*', '!':
+		return fmt.Errorf("line %d: anchors, aliases and tags are not supported: %s", l.ln, txt)
+	default:
+		docYAMLPlain(dn, txt)
+	}
+	return nil
+}
+
+// blockScalar returns the value of a literal (|) or folded (>) block
+// scalar, with given header, from the lines indented more than given
+// indentation of the line with its key or sequence item
+func (p *docYAMLParser) blockScalar(hdr string, ind int) string {
+	var lns []string
+	bind := -1
+	for ; p.i < len(p.lines); p.i++ {
+		l := &p.lines[p.i]
+		if strings.TrimSpace(l.raw) == "" {
+			lns = append(lns, "")
+			continue
+		}
+		if l.ind <= ind {
+			break
+		}
+		if bind < 0 {
+			bind = l.ind
+		}
+		lns = append(lns, l.raw[ints.MinInt(bind, l.ind):])
+	}
+	nblank := 0 // trailing blank lines are not part of the block
+	for nblank < len(lns) && lns[len(lns)-1-nblank] == "" {
+		nblank++
+	}
+	p.i -= nblank
+	lns = lns[:len(lns)-nblank]
+	var s string
+	if hdr[0] == '|' {
+		s = strings.Join(lns, "\n")
+	} else {
+		var b strings.Builder
+		for i, ln := range lns {
+			switch {
+			case i == 0:
+			case ln == "": // blank lines are line breaks
+				b.WriteString("\n")
+			case lns[i-1] == "": // replacing the break before them
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(ln)
+		}
+		s = b.String()
+	}
+	switch {
+	case strings.Contains(hdr, "-"):
+	case strings.Contains(hdr, "+"):
+		s += strings.Repeat("\n", nblank+1)
+	case len(lns) > 0:
+		s += "\n"
+	}
+	return s
+}
+
+// docYAMLPlain sets given node from given plain scalar text: null, a
+// boolean, a number, or otherwise a string
+func docYAMLPlain(dn *DocNode, txt string) {
+	switch txt {
+	case "~", "null", "Null", "NULL":
+		dn.Kind = DocNull
+	case "true", "True", "TRUE":
+		dn.Kind = DocBool
+		dn.Value = "true"
+	case "false", "False", "FALSE":
+		dn.Kind = DocBool
+		dn.Value = "false"
+	default:
+		if DocIsNumber(txt) {
+			dn.Kind = DocNumber
+		} else {
+			dn.Kind = DocString
+		}
+		dn.Value = txt
+	}
+}
+
+// docYAMLUnquote returns the value of given double-quoted YAML string
+func docYAMLUnquote(txt string) (string, error) {
+	var s string
+	if err := json.Unmarshal([]byte(txt), &s); err == nil {
+		return s, nil
+	}
+	return strconv.Unquote(txt)
+}
+
+// docYAMLQuoteEnd returns the index of the quote that ends the quoted
+// string at the start of given text, or -1 if there is none
+func docYAMLQuoteEnd(txt string) int {
+	q := txt[0]
+	for i := 1; i < len(txt); i++ {
+		switch {
+		case q == '"' && txt[i] == '\\':
+			i++
+		case q == '\'' && txt[i] == '\'' && i+1 < len(txt) && txt[i+1] == '\'':
+			i++
+		case txt[i] == q:
+			return i
+		}
+	}
+	return -1
+}
+
+// docYAMLSplitComment splits given line text into the text and the
+// comment at its end (or the whole line), outside of any quoted string
+func docYAMLSplitComment(txt string) (string, string) {
+	for i := 0; i < len(txt); i++ {
+		switch c := txt[i]; {
+		case (c == '"' || c == '\'') && (i == 0 || strings.ContainsRune(" :-[{,", rune(txt[i-1]))):
+			if end := docYAMLQuoteEnd(txt[i:]); end > 0 {
+				i += end
+			}
+		case c == '#' && (i == 0 || txt[i-1] == ' '):
+			return strings.TrimRight(txt[:i], " "), strings.TrimSpace(txt[i+1:])
+		}
+	}
+	return strings.TrimRight(txt, " "), ""
+}
+
+// docYAMLSplitKey splits given line text into the key and value of a
+// mapping entry, returning false if it is not one
+func docYAMLSplitKey(txt string) (key, rest string, ok bool) {
+	if txt[0] == '"' || txt[0] == '\'' {
+		end := docYAMLQuoteEnd(txt)
+		if end < 0 || end+1 >= len(txt) || txt[end+1] != ':' {
+			return "", "", false
+		}
+		kn := &DocNode{}
+		if err := (&docYAMLParser{}).parseScalar(kn, txt[:end+1], &docYAMLLine{}); err != nil {
+			return "", "", false
+		}
+		return kn.Value, strings.TrimSpace(txt[end+2:]), true
+	}
+	if txt[0] == '[' || txt[0] == '{' {
+		return "", "", false
+	}
+	for i := 0; i < len(txt); i++ {
+		if txt[i] == ':' && (i == len(txt)-1 || txt[i+1] == ' ') {
+			return strings.TrimSpace(txt[:i]), strings.TrimSpace(txt[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// YAML returns the document under this node as YAML text, indented with
+// two spaces, with the comments as # comments
+func (dn *DocNode) YAML() []byte {
+	var b bytes.Buffer
+	writeYAMLComment(&b, dn.Comment, 0)
+	if dn.IsContainer() && dn.HasChildren() {
+		dn.writeYAMLKids(&b, 0, false)
+	} else {
+		dn.writeYAMLScalar(&b, 2)
+		if dn.ParentDoc() == nil {
+			writeYAMLComment(&b, dn.EndComment, 0)
+		}
+	}
+	return b.Bytes()
+}
+
+// writeYAMLComment writes given comment lines at given indent
+func writeYAMLComment(b *bytes.Buffer, cmt string, ind int) {
+	for _, ln := range docCommentLines(cmt) {
+		b.WriteString(strings.Repeat(" ", ind))
+		b.WriteString(strings.TrimSpace("# " + ln))
+		b.WriteString("\n")
+	}
+}
+
+// writeYAMLLineComment writes given comment at the end of a line
+func writeYAMLLineComment(b *bytes.Buffer, cmt string) {
+	if cmt != "" {
+		b.WriteString(" # " + cmt)
+	}
+}
+
+// writeYAMLKids writes the children of a non-empty object or array, at
+// given indent -- if inItem, the first line follows a sequence item dash
+// that is already written
+func (dn *DocNode) writeYAMLKids(b *bytes.Buffer, ind int, inItem bool) {
+	pre := strings.Repeat(" ", ind)
+	for i, k := range dn.Kids {
+		kn := k.(*DocNode)
+		if !(inItem && i == 0) {
+			writeYAMLComment(b, kn.Comment, ind)
+			b.WriteString(pre)
+		}
+		if dn.Kind == DocArray {
+			b.WriteString("-")
+		} else {
+			b.WriteString(docYAMLKey(kn.Nm) + ":")
+		}
+		switch {
+		case !kn.IsContainer() || !kn.HasChildren():
+			b.WriteString(" ")
+			kn.writeYAMLScalar(b, ind+2)
+		case dn.Kind == DocArray && kn.LineComment == "" && kn.Child(0).(*DocNode).Comment == "":
+			b.WriteString(" ") // first item on the same line as the dash
+			kn.writeYAMLKids(b, ind+2, true)
+		default:
+			writeYAMLLineComment(b, kn.LineComment)
+			b.WriteString("\n")
+			kn.writeYAMLKids(b, ind+2, false)
+		}
+	}
+	writeYAMLComment(b, dn.EndComment, ind)
+}
+
+// writeYAMLScalar writes the YAML text of a scalar or empty container
+// value, and its line comment, where multi-line strings are block scalars
+// indented by given indent
+func (dn *DocNode) writeYAMLScalar(b *bytes.Buffer, ind int) {
+	switch dn.Kind {
+	case DocNull:
+		b.WriteString("null")
+	case DocObject:
+		b.WriteString("{}")
+	case DocArray:
+		b.WriteString("[]")
+	case DocString:
+		s := docYAMLString(dn.Value, ind, dn.LineComment)
+		b.WriteString(s)
+		if strings.HasSuffix(s, "\n") { // block scalar, with the line comment
+			return
+		}
+	default:
+		b.WriteString(dn.Value)
+	}
+	writeYAMLLineComment(b, dn.LineComment)
+	b.WriteString("\n")
+}
+
+// docYAMLKey returns given key as YAML text, quoted if needed
+func docYAMLKey(key string) string {
+	if docYAMLPlainOK(key) {
+		return key
+	}
+	return docQuote(key)
+}
+
+// docYAMLString returns given string as YAML text: plain if possible, a
+// literal block scalar at given indent for multiple lines, or quoted
+func docYAMLString(s string, ind int, lineCmt string) string {
+	if docYAMLPlainOK(s) {
+		return s
+	}
+	lns := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if len(lns) < 2 || strings.HasSuffix(s, "\n\n") || strings.HasPrefix(s, " ") {
+		return docQuote(s)
+	}
+	for _, ln := range lns {
+		if strings.TrimRight(ln, " \t") != ln {
+			return docQuote(s)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("|")
+	if !strings.HasSuffix(s, "\n") {
+		b.WriteString("-")
+	}
+	if lineCmt != "" {
+		b.WriteString(" # " + lineCmt)
+	}
+	pre := strings.Repeat(" ", ind)
+	for _, ln := range lns {
+		b.WriteString("\n")
+		if ln != "" {
+			b.WriteString(pre + ln)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// docYAMLPlainOK returns true if given string can be written as a plain
+// YAML scalar that is read back as the same string
+func docYAMLPlainOK(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, "\n\t\r") {
+		return false
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(s[0])) || strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "yes", "no", "on", "off", "y", "n": // booleans in YAML 1.1
+		return false
+	}
+	dn := &DocNode{}
+	docYAMLPlain(dn, s)
+	return dn.Kind == DocString
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLDocRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"scalars", "a: 1\nb: true\nc: null\nd: text\n"},
+		{"comments", "# head\n# of doc\na: 1 # one\n# before b\nb:\n  - x # ex\n  # before z\n  - z\n  # end of b\n# end of doc\n"},
+		{"nested", "a:\n  b:\n    c: 1\n  d:\n    - e: 2\n      f: 3\n    - 4\n"},
+		{"empty", "a: {}\nb: []\n"},
+		{"quoted", "\"a b: c\": \"# not a comment\"\nb: \"yes\"\nc: \"1\"\nd: \"\"\ne: \" lead\"\n"},
+		{"literal", "a: |\n  one\n  two\n"},
+		{"literal strip", "a: |- # cmt\n  one\n  two\nb: 1\n"},
+	}
+	for _, tt := range tests {
+		dn, err := ParseYAMLDoc([]byte(tt.src))
+		if err != nil {
+			t.Errorf("%s: parse error: %v", tt.name, err)
+			continue
+		}
+		if got := string(dn.YAML()); got != tt.src {
+			t.Errorf("%s: round trip:\n%s\nwant:\n%s", tt.name, got, tt.src)
+		}
+	}
+}
+
+func TestYAMLDocValues(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		kind DocKinds
+		val  string
+	}{
+		{"plain", "a: hello world", DocString, "hello world"},
+		{"number", "a: -1.5e3", DocNumber, "-1.5e3"},
+		{"bool", "a: True", DocBool, "true"},
+		{"null", "a: ~", DocNull, ""},
+		{"double", `a: "x\ty\n\"z\""`, DocString, "x\ty\n\"z\""},
+		{"single", "a: 'it''s # here'", DocString, "it's # here"},
+		{"quoted number", "a: '42'", DocString, "42"},
+		{"hash in plain", "a: b#c # cmt", DocString, "b#c"},
+		{"literal", "a: |\n  one\n    two\n\n  three\nb: 1", DocString, "one\n  two\n\nthree\n"},
+		{"literal strip", "a: |-\n  one\n  two\n\n", DocString, "one\ntwo"},
+		{"literal keep", "a: |+\n  one\n\n\nb: 1", DocString, "one\n\n\n"},
+		{"folded", "a: >\n  one\n  two\n\n  three\n", DocString, "one two\nthree\n"},
+		{"folded strip", "a: >-\n  one\n  two\n", DocString, "one two"},
+	}
+	for _, tt := range tests {
+		dn, err := ParseYAMLDoc([]byte(tt.src))
+		if err != nil {
+			t.Errorf("%s: parse error: %v", tt.name, err)
+			continue
+		}
+		a, ok := dn.ChildByName("a", 0).(*DocNode)
+		if !ok {
+			t.Errorf("%s: no a node in: %s", tt.name, dn.YAML())
+			continue
+		}
+		if a.Kind != tt.kind || a.Value != tt.val {
+			t.Errorf("%s: got %v %q, want %v %q", tt.name, a.Kind, a.Value, tt.kind, tt.val)
+		}
+	}
+}
+
+func TestYAMLDocQuoting(t *testing.T) {
+	tests := []struct {
+		val  string
+		want string
+	}{
+		{"plain", "plain"},
+		{"two words", "two words"},
+		{"", `""`},
+		{"true", `"true"`},
+		{"yes", `"yes"`},
+		{"null", `"null"`},
+		{"12", `"12"`},
+		{"a: b", `"a: b"`},
+		{"a #b", `"a #b"`},
+		{"key:", `"key:"`},
+		{"-dash", `"-dash"`},
+		{"*star", `"*star"`},
+		{" lead", `" lead"`},
+		{"tab\there", `"tab\there"`},
+		{"<b>", "<b>"},
+	}
+	for _, tt := range tests {
+		dn := NewDocNode(DocString)
+		dn.Value = tt.val
+		got := strings.TrimSuffix(string(dn.YAML()), "\n")
+		if got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.val, got, tt.want)
+			continue
+		}
+		rn, err := ParseYAMLDoc(dn.YAML())
+		if err != nil {
+			t.Errorf("%q: parse error: %v", tt.val, err)
+			continue
+		}
+		if rn.Kind != DocString || rn.Value != tt.val {
+			t.Errorf("%q: read back as %v %q", tt.val, rn.Kind, rn.Value)
+		}
+	}
+}
+
+func TestYAMLDocErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{"anchor", "a: &x 1\nb: 2\n", "line 1: anchors, aliases and tags are not supported"},
+		{"alias", "a: 1\nb: *x\n", "line 2: anchors, aliases and tags are not supported"},
+		{"tag", "a: !!str 1\n", "line 1: anchors, aliases and tags are not supported"},
+		{"multiple documents", "a: 1\n---\nb: 2\n", "line 2: multiple documents are not supported"},
+		{"second document", "---\na: 1\n---\n", "line 3: multiple documents are not supported"},
+		{"tab", "a:\n\tb: 1\n", "line 2: tabs are not allowed for indentation"},
+		{"indentation", "a: 1\n  b: 2\n", "line 2: unexpected indentation"},
+		{"no key", "a: 1\nb\n", "line 2: expected a key"},
+		{"unterminated", "a: 'x\n", "line 1: unterminated string"},
+		{"flow", "a: [x, y]\n", "line 1: only flow collections that are valid JSON are supported"},
+	}
+	for _, tt := range tests {
+		_, err := ParseYAMLDoc([]byte(tt.src))
+		if err == nil {
+			t.Errorf("%s: no error", tt.name)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("%s: got error: %v, want: %s", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/antonmedv/expr/ast"
+	"github.com/antonmedv/expr/builtin"
+	"github.com/antonmedv/expr/file"
+	"github.com/antonmedv/expr/parser"
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/pi/complete"
+	"github.com/goki/pi/filecat"
+	"github.com/goki/pi/lex"
+	"github.com/goki/pi/token"
+)
+
+// Expr is a string holding an expression or formula, e.g., a parameter
+// of a simulation that is computed from other parameters, using the syntax
+// of github.com/antonmedv/expr.  It is edited in an ExprEdit, with syntax
+// highlighting, completion of symbols, and underlining of errors.
+// A string field with a `view:"expr"` tag is edited in the same way.
+type Expr string
+
+// ExprSymbols are the symbols that are available in all expressions,
+// for completion and for checking that only known symbols are used,
+// in addition to any provided by the owner of the expression
+// (see ExprSymbolser) -- typically set by the app at startup.
+var ExprSymbols complete.Completions
+
+// ExprSymbolser is an interface that the struct holding an Expr field can
+// implement to provide the symbols that can be used in the expression for
+// the field with given name, for completion and checking.
+type ExprSymbolser interface {
+	ExprSymbols(field string) complete.Completions
+}
+
+// ExprCheck checks given expression for syntax errors, and, if syms is
+// non-empty, for names that are not among the symbols -- the error is a
+// *file.Error with the location of the problem
+func ExprCheck(src string, syms complete.Completions) error {
+	tree, err := parser.Parse(src)
+	if err != nil {
+		return err
+	}
+	if len(syms) == 0 {
+		return nil
+	}
+	ec := &exprSymCheck{syms: syms}
+	ast.Walk(&tree.Node, ec)
+	return ec.err
+}
+
+// exprSymCheck is an ast.Visitor that records the first name that
+// is not one of the symbols or a variable declared in the expression
+type exprSymCheck struct {
+	syms complete.Completions
+	vars []string
+	err  error
+}
+
+func (ec *exprSymCheck) Visit(node *ast.Node) {
+	switch nd := (*node).(type) {
+	case *ast.VariableDeclaratorNode:
+		ec.vars = append(ec.vars, nd.Name)
+	case *ast.IdentifierNode:
+		if ec.err != nil || ec.known(nd.Value) {
+			return
+		}
+		ec.err = &file.Error{Location: nd.Location(), Message: "unknown name: " + nd.Value}
+	}
+}
+
+func (ec *exprSymCheck) known(nm string) bool {
+	for _, sy := range ec.syms {
+		if sy.Text == nm {
+			return true
+		}
+	}
+	for _, vr := range ec.vars {
+		if vr == nm {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExprSymRune returns true if given rune can be part of a symbol name
+// in an expression, including the . for fields
+func IsExprSymRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// CompleteExpr completes the symbol name at the end of given text from the
+// symbols in the ExprEdit passed as data and the builtin functions
+func CompleteExpr(data any, text string, posLn, posCh int) (md complete.Matches) {
+	ee, ok := data.(*ExprEdit)
+	if !ok || ee == nil {
+		return md
+	}
+	rs := []rune(text)
+	st := len(rs)
+	for st > 0 && IsExprSymRune(rs[st-1]) {
+		st--
+	}
+	md.Seed = string(rs[st:])
+	if md.Seed == "" {
+		return md
+	}
+	syms := append(complete.Completions{}, ee.AllSymbols()...)
+	for _, bf := range builtin.Builtins {
+		syms = append(syms, complete.Completion{Text: bf.Name, Icon: "function", Desc: "builtin function"})
+	}
+	md.Matches = complete.MatchSeedCompletion(syms, md.Seed)
+	return md
+}
+
+// CompleteExprEdit replaces the seed and the rest of the symbol name
+// after the cursor with the completion
+func CompleteExprEdit(data any, text string, cursorPos int, comp complete.Completion, seed string) (ed complete.Edit) {
+	rs := []rune(text)
+	fd := 0
+	for cursorPos+fd < len(rs) && IsExprSymRune(rs[cursorPos+fd]) {
+		fd++
+	}
+	ed.NewText = comp.Text
+	ed.ForwardDelete = fd
+	return ed
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  ExprValueView
+
+// ValueView registers ExprValueView as the viewer of Expr
+func (ex Expr) ValueView() ValueView {
+	vv := &ExprValueView{}
+	ki.InitNode(vv)
+	return vv
+}
+
+// ExprValueView presents an ExprEdit for an Expr, or a string
+// with a `view:"expr"` tag
+type ExprValueView struct {
+	ValueViewBase
+}
+
+var KiT_ExprValueView = kit.Types.AddType(&ExprValueView{}, nil)
+
+func (vv *ExprValueView) WidgetType() reflect.Type {
+	vv.WidgetTyp = KiT_ExprEdit
+	return vv.WidgetTyp
+}
+
+func (vv *ExprValueView) UpdateWidget() {
+	if vv.Widget == nil {
+		return
+	}
+	ee := vv.Widget.(*ExprEdit)
+	txt := kit.ToString(vv.Value.Interface())
+	if txt != ee.Text() {
+		ee.SetText(txt)
+	}
+}
+
+func (vv *ExprValueView) ConfigWidget(widg gi.Node2D) {
+	vv.Widget = widg
+	vv.StdConfigWidget(widg)
+	ee := vv.Widget.(*ExprEdit)
+	ee.Tooltip, _ = vv.Tag("desc")
+	ee.Symbols = vv.Symbols()
+	ee.Config()
+	ee.TextView().SetInactiveState(vv.This().(ValueView).IsInactive())
+	ee.ExprSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		vvv, _ := recv.Embed(KiT_ExprValueView).(*ExprValueView)
+		if vvv.SetValue(data.(string)) {
+			vvv.UpdateWidget()
+		}
+		vvv.ViewSig.Emit(vvv.This(), 0, nil)
+	})
+	vv.UpdateWidget()
+}
+
+func (vv *ExprValueView) HasAction() bool {
+	return false
+}
+
+// Symbols returns the symbols provided by the owner of the value,
+// if it implements the ExprSymbolser interface
+func (vv *ExprValueView) Symbols() complete.Completions {
+	if vv.Owner == nil || vv.Field == nil {
+		return nil
+	}
+	if es, ok := vv.Owner.(ExprSymbolser); ok {
+		return es.ExprSymbols(vv.Field.Name)
+	}
+	if es, ok := kit.PtrInterface(vv.Owner).(ExprSymbolser); ok {
+		return es.ExprSymbols(vv.Field.Name)
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// ExprEdit
+
+// ExprEdit is a one-line editor for an expression, with syntax highlighting,
+// completion of the names of symbols, and underlining of errors, which are
+// checked as the expression is edited.  The expression is applied when
+// enter is pressed, which emits the ExprSig signal.
+type ExprEdit struct {
+	gi.Layout
+	Buf       *TextBuf               `json:"-" xml:"-" desc:"the text buffer holding the expression"`
+	Symbols   complete.Completions   `json:"-" xml:"-" desc:"symbols that can be used in the expression, in addition to ExprSymbols, for completion and checking"`
+	CheckFunc func(src string) error `json:"-" xml:"-" view:"-" desc:"function that checks the expression for errors -- ExprCheck with all of the symbols is used if nil -- the location of a *file.Error is underlined, and the entire expression for other errors"`
+	Err       error                  `json:"-" xml:"-" desc:"error from the last check of the expression -- nil if ok"`
+	ExprSig   ki.Signal              `json:"-" xml:"-" view:"-" desc:"signal emitted when the expression is applied by pressing enter -- data is the text"`
+}
+
+var KiT_ExprEdit = kit.Types.AddType(&ExprEdit{}, ExprEditProps)
+
+// AddNewExprEdit adds a new expression editor to given parent node, with given name.
+func AddNewExprEdit(parent ki.Ki, name string) *ExprEdit {
+	return parent.AddNewChild(KiT_ExprEdit, name).(*ExprEdit)
+}
+
+func (ee *ExprEdit) Disconnect() {
+	ee.Layout.Disconnect()
+	ee.ExprSig.DisconnectAll()
+}
+
+var ExprEditProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"min-width":     units.NewCh(20),
+	"max-width":     -1,
+	"font-family":   &gi.Prefs.MonoFont,
+}
+
+// Config configures the text view and buffer, if not already done
+func (ee *ExprEdit) Config() {
+	ee.Lay = gi.LayoutHoriz
+	if ee.Buf == nil {
+		ee.Buf = &TextBuf{}
+		ee.Buf.InitName(ee.Buf, "expr-buf")
+		ee.Buf.Defaults()
+		ee.Buf.Opts.LineNos = false
+		ee.Buf.Opts.Completion = true
+		ee.Buf.Info.Sup = filecat.Go // closest pi language to expr syntax
+		ee.Buf.SetCompleter(ee, CompleteExpr, CompleteExprEdit, nil)
+		ee.Buf.TextBufSig.Connect(ee.This(), func(recv, send ki.Ki, sig int64, data any) {
+			eev := recv.Embed(KiT_ExprEdit).(*ExprEdit)
+			switch TextBufSignals(sig) {
+			case TextBufInsert, TextBufDelete:
+				eev.Check()
+			}
+		})
+	}
+	if ee.HasChildren() {
+		return
+	}
+	tv := AddNewTextView(ee, "expr-text")
+	tv.SetProp("white-space", gist.WhiteSpacePre)
+	tv.SetStretchMaxWidth()
+	tv.SetBuf(ee.Buf)
+}
+
+// TextView returns the text view that edits the expression
+func (ee *ExprEdit) TextView() *TextView {
+	if !ee.HasChildren() {
+		ee.Config()
+	}
+	return ee.Child(0).Embed(KiT_TextView).(*TextView)
+}
+
+// Text returns the current text of the expression
+func (ee *ExprEdit) Text() string {
+	if ee.Buf == nil {
+		return ""
+	}
+	txt := string(ee.Buf.LinesToBytesCopy())
+	return strings.ReplaceAll(strings.TrimRight(txt, "\n"), "\n", " ")
+}
+
+// SetText sets the text of the expression, and checks it
+func (ee *ExprEdit) SetText(txt string) {
+	ee.Config()
+	ee.Buf.SetText([]byte(txt))
+	ee.Check()
+}
+
+// AllSymbols returns the Symbols and the global ExprSymbols
+func (ee *ExprEdit) AllSymbols() complete.Completions {
+	if len(ee.Symbols) == 0 {
+		return ExprSymbols
+	}
+	return append(append(complete.Completions{}, ee.Symbols...), ExprSymbols...)
+}
+
+// Check checks the expression for errors using CheckFunc, and underlines the
+// location of any error, which is also set as the tooltip of the text view
+func (ee *ExprEdit) Check() error {
+	if ee.Buf == nil || ee.Buf.NumLines() == 0 {
+		return nil
+	}
+	src := ee.Text()
+	if strings.TrimSpace(src) == "" {
+		ee.Err = nil
+	} else if ee.CheckFunc != nil {
+		ee.Err = ee.CheckFunc(src)
+	} else {
+		ee.Err = ExprCheck(src, ee.AllSymbols())
+	}
+	ee.MarkErr()
+	return ee.Err
+}
+
+// MarkErr tags the location of the current error in the buffer with
+// the token.Error tag, removing any previous one
+func (ee *ExprEdit) MarkErr() {
+	tb := ee.Buf
+	ln := 0
+	ed := tb.LineLen(ln)
+	tb.MarkupMu.Lock()
+	tgs := tb.AdjustedTags(ln)
+	tgs.DeleteToken(token.Error)
+	if ee.Err != nil {
+		st := 0
+		var ferr *file.Error
+		if errors.As(ee.Err, &ferr) && !ferr.Location.Empty() {
+			st = ferr.Location.Column
+		}
+		if st >= ed {
+			st = ints.MaxInt(ed-1, 0)
+		}
+		if ed > st {
+			tgs.AddSort(lex.NewLex(token.KeyToken{Tok: token.Error}, st, ed))
+		}
+	}
+	tb.Tags[ln] = tgs
+	tb.MarkupMu.Unlock()
+	tb.MarkupLinesLock(ln, ln)
+	if ee.HasChildren() {
+		tv := ee.TextView()
+		if ee.Err != nil {
+			tv.Tooltip = ee.Err.Error()
+		} else {
+			tv.Tooltip = ee.Tooltip
+		}
+		tv.Refresh()
+	}
+}
+
+// Apply checks the expression and emits the ExprSig signal with its text
+func (ee *ExprEdit) Apply() {
+	ee.Check()
+	ee.ExprSig.Emit(ee.This(), 0, ee.Text())
+}
+
+// ExprKeys handles the keys that apply the expression, ahead of the text view
+func (ee *ExprEdit) ExprKeys() {
+	ee.ConnectEvent(oswin.KeyChordEvent, gi.HiPri, func(recv, send ki.Ki, sig int64, d any) {
+		eev := recv.Embed(KiT_ExprEdit).(*ExprEdit)
+		kt := d.(*key.ChordEvent)
+		kf := gi.KeyFun(kt.Chord())
+		if kf != gi.KeyFunEnter && kf != gi.KeyFunAccept {
+			return
+		}
+		if win := eev.ParentWindow(); win != nil && gi.PopupIsCompleter(win.CurPopup()) {
+			return // text view selects completion
+		}
+		kt.SetProcessed()
+		eev.TextView().CancelComplete()
+		eev.Apply()
+	})
+}
+
+func (ee *ExprEdit) ConnectEvents2D() {
+	ee.Layout.ConnectEvents2D()
+	ee.ExprKeys()
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"io/fs"
+	"mime"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/goki/pi/filecat"
+)
+
+//////////////////////////////////////////////////////////////////////////////
+//    fs.FS file access
+
+// The FileView, FileTree and FileValueView can show the files in any fs.FS
+// filesystem (e.g., embedded assets, an in-memory filesystem, or remote
+// storage), set in their FS field, instead of the OS filesystem.  Paths in
+// an fs.FS are slash-separated and relative to its root, which is ".", and
+// the files are read-only.  A nil fs.FS means the OS filesystem, including
+// the files within archives -- see ArchiveBrowsing.
+
+// FSPath returns given path in the valid form for an fs.FS: slash
+// separated, cleaned, without a leading slash, and "." for the root
+func FSPath(fpath string) string {
+	fpath = strings.TrimPrefix(fpath, filepath.VolumeName(fpath))
+	fpath = path.Clean(filepath.ToSlash(fpath))
+	fpath = strings.TrimPrefix(fpath, "/")
+	if fpath == "" {
+		return "."
+	}
+	return fpath
+}
+
+// FSStat returns the file info for given path in given fs.FS, or on the OS
+// filesystem if nil (see StatPath)
+func FSStat(fsys fs.FS, fpath string) (fs.FileInfo, error) {
+	if fsys == nil {
+		return StatPath(fpath)
+	}
+	return fs.Stat(fsys, FSPath(fpath))
+}
+
+// FSReadDir returns the entries of given directory in given fs.FS, or on
+// the OS filesystem if nil (see ReadDirPath)
+func FSReadDir(fsys fs.FS, dir string) ([]fs.DirEntry, error) {
+	if fsys == nil {
+		return ReadDirPath(dir)
+	}
+	return fs.ReadDir(fsys, FSPath(dir))
+}
+
+// FSReadFile returns the contents of given file in given fs.FS, or on the
+// OS filesystem if nil (see ReadFilePath)
+func FSReadFile(fsys fs.FS, fpath string) ([]byte, error) {
+	if fsys == nil {
+		return ReadFilePath(fpath)
+	}
+	return fs.ReadFile(fsys, FSPath(fpath))
+}
+
+// FSMime returns the mime type of given file in given fs.FS, based on its
+// extension, as the contents of the file are not sniffed for fs.FS files
+func FSMime(fsys fs.FS, fpath string) (string, error) {
+	if fsys == nil {
+		mtyp, _, err := filecat.MimeFromFile(fpath)
+		return mtyp, err
+	}
+	ext := strings.ToLower(path.Ext(fpath))
+	if mtyp, has := filecat.ExtMimeMap[ext]; has {
+		return mtyp, nil
+	}
+	if mtyp := mime.TypeByExtension(ext); mtyp != "" {
+		return mtyp, nil
+	}
+	return "", fs.ErrNotExist
+}
+
+var (
+	// FileFSs are the named fs.FS filesystems that can be selected for
+	// FileValueView fields with the fs:"name" tag -- see RegisterFileFS
+	FileFSs   = map[string]fs.FS{}
+	fileFSsMu sync.Mutex
+)
+
+// RegisterFileFS registers given fs.FS under given name, which can then be
+// used in the fs:"name" tag of gi.FileName fields, so that the file
+// chooser for the field shows the files in that filesystem
+func RegisterFileFS(name string, fsys fs.FS) {
+	fileFSsMu.Lock()
+	FileFSs[name] = fsys
+	fileFSsMu.Unlock()
+}
+
+// FileFSByName returns the registered fs.FS of given name, or nil if none
+func FileFSByName(name string) fs.FS {
+	fileFSsMu.Lock()
+	defer fileFSsMu.Unlock()
+	return FileFSs[name]
+}
@@ -8,9 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"io/ioutil"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -37,6 +39,7 @@ type FileInfo struct {
 	ModTime FileTime          `desc:"time that contents (only) were last modified"`
 	Vcs     vci.FileStatus    `tableview:"-" desc:"version control system status, when enabled"`
 	Path    string            `tableview:"-" desc:"full path to file, including name -- for file functions"`
+	FS      fs.FS             `view:"-" json:"-" xml:"-" desc:"filesystem that the file is in, if not the OS filesystem -- Path is then the slash-separated path within it"`
 }
 
 var KiT_FileInfo = kit.Types.AddType(&FileInfo{}, FileInfoProps)
@@ -51,6 +54,27 @@ func NewFileInfo(fname string) (*FileInfo, error) {
 	return fi, err
 }
 
+// NewFileInfoFS returns a new FileInfo for a file at given path in given
+// fs.FS -- directly returns the fs.Stat error on the file.  A nil fs.FS is
+// the OS filesystem, as in NewFileInfo.
+func NewFileInfoFS(fsys fs.FS, fname string) (*FileInfo, error) {
+	fi := &FileInfo{}
+	err := fi.InitFileFS(fsys, fname)
+	return fi, err
+}
+
+// InitFileFS initializes a FileInfo for a file at given path in given fs.FS
+// -- a nil fs.FS is the OS filesystem, as in InitFile.
+func (fi *FileInfo) InitFileFS(fsys fs.FS, fname string) error {
+	fi.FS = fsys
+	if fsys == nil {
+		return fi.InitFile(fname)
+	}
+	fi.Path = FSPath(fname)
+	fi.Name = path.Base(fi.Path)
+	return fi.Stat()
+}
+
 // InitFile initializes a FileInfo based on a filename -- directly returns
 // filepath.Abs or os.Stat error on the given file.  filename can be anything
 // that works given current directory -- Path will contain the full
@@ -60,16 +84,18 @@ func (fi *FileInfo) InitFile(fname string) error {
 	if err != nil {
 		return err
 	}
+	fi.FS = nil
 	fi.Path = path
 	_, fi.Name = filepath.Split(path)
 	return fi.Stat()
 }
 
-// Stat runs os.Stat on file, returns any error directly but otherwise updates
-// file info, including mime type, which then drives Kind and Icon -- this is
-// the main function to call to update state.
+// Stat runs os.Stat on file (or StatPath for files within archives, or
+// fs.Stat for files in an fs.FS), returns any error directly but otherwise
+// updates file info, including mime type, which then drives Kind and Icon --
+// this is the main function to call to update state.
 func (fi *FileInfo) Stat() error {
-	info, err := os.Stat(fi.Path)
+	info, err := FSStat(fi.FS, fi.Path)
 	if err != nil {
 		return err
 	}
@@ -84,7 +110,7 @@ func (fi *FileInfo) Stat() error {
 		fi.Cat = filecat.Unknown
 		fi.Sup = filecat.NoSupport
 		fi.Kind = ""
-		mtyp, _, err := filecat.MimeFromFile(fi.Path)
+		mtyp, err := FSMime(fi.FS, fi.Path)
 		if err == nil {
 			fi.Mime = mtyp
 			fi.Cat = filecat.CatFromMime(fi.Mime)
@@ -114,6 +140,12 @@ func (fi *FileInfo) IsDir() bool {
 	return fi.Mode.IsDir()
 }
 
+// IsArchive returns true if file is an archive file that can be browsed as
+// a folder -- see ArchiveBrowsing
+func (fi *FileInfo) IsArchive() bool {
+	return ArchiveBrowsing && fi.FS == nil && fi.Mode.IsRegular() && IsArchiveFile(fi.Path) && !InArchive(fi.Path)
+}
+
 // IsExec returns true if file is an executable file
 func (fi *FileInfo) IsExec() bool {
 	if fi.Mode&0111 != 0 {
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"io/fs"
 	"io/ioutil"
 	"log"
 	"os"
@@ -78,6 +79,7 @@ type FileTree struct {
 	LastWatchUpdt string            `view:"-" desc:"last path updated by watcher"`
 	LastWatchTime time.Time         `view:"-" desc:"timestamp of last update"`
 	UpdtMu        sync.Mutex        `view:"-" desc:"Update mutex"`
+	FS            fs.FS             `view:"-" json:"-" xml:"-" desc:"filesystem that the tree shows, if not the OS filesystem -- see OpenFS -- the paths of the nodes are then the paths within it, rooted at the separator, and the files are read-only"`
 }
 
 var KiT_FileTree = kit.Types.AddType(&FileTree{}, FileTreeProps)
@@ -123,10 +125,25 @@ func (ft *FileTree) OpenPath(path string) {
 		log.Printf("giv.FileTree:OpenPath: %s\n", err)
 		abs = effpath
 	}
+	ft.FS = nil
 	ft.FPath = gi.FileName(abs)
 	ft.UpdateAll()
 }
 
+// OpenFS opens a filetree at given directory path in given fs.FS (e.g.,
+// embedded assets, or an in-memory or remote filesystem), with the files
+// read-only.  The paths of the nodes are the paths within the fs.FS, rooted
+// at the separator, e.g., /images/icon.png.
+func (ft *FileTree) OpenFS(fsys fs.FS, path string) {
+	ft.FRoot = ft // we are our own root..
+	if ft.NodeType == nil {
+		ft.NodeType = KiT_FileNode
+	}
+	ft.FS = fsys
+	ft.FPath = gi.FileName(filepath.Join(string(filepath.Separator), filepath.FromSlash(FSPath(path))))
+	ft.UpdateAll()
+}
+
 // UpdateAll does a full update of the tree -- calls ReadDir on current path
 func (ft *FileTree) UpdateAll() {
 	ft.UpdtMu.Lock()
@@ -238,8 +255,12 @@ func (ft *FileTree) WatchUpdt(path string) {
 	fn.UpdateNode()
 }
 
-// WatchPath adds given path to those watched
+// WatchPath adds given path to those watched -- paths in an fs.FS (see
+// OpenFS) or within an archive file are not watched (see IsOSPath)
 func (ft *FileTree) WatchPath(path gi.FileName) error {
+	if !ft.IsOSPath(path) {
+		return nil
+	}
 	return nil // disable for all platforms for now -- getting some issues
 	if oswin.TheApp.Platform() == oswin.MacOS {
 		return nil // mac is not supported in a high-capacity fashion at this point
@@ -263,6 +284,9 @@ func (ft *FileTree) WatchPath(path gi.FileName) error {
 
 // UnWatchPath removes given path from those watched
 func (ft *FileTree) UnWatchPath(path gi.FileName) {
+	if !ft.IsOSPath(path) {
+		return
+	}
 	rp := ft.RelPath(path)
 	on, has := ft.WatchedPaths[rp]
 	if !on || !has {
@@ -273,6 +297,18 @@ func (ft *FileTree) UnWatchPath(path gi.FileName) {
 	ft.WatchedPaths[rp] = false
 }
 
+// IsOSPath returns true if given path is that of a directory in the OS
+// filesystem, and not a path in the fs.FS of the tree (see OpenFS), e.g.,
+// "/", or an archive file browsed as a folder or a path within it, which
+// must not be passed to the OS, e.g., to be watched
+func (ft *FileTree) IsOSPath(path gi.FileName) bool {
+	if ft.FS != nil {
+		return false
+	}
+	_, _, inArc := SplitArchivePath(string(path))
+	return !inArc
+}
+
 // IsDirOpen returns true if given directory path is open (i.e., has been
 // opened in the view)
 func (ft *FileTree) IsDirOpen(fpath gi.FileName) bool {
@@ -429,9 +465,36 @@ func (fn *FileNode) CopyFieldsFrom(frm any) {
 	// and indeed nothing here should be copied!
 }
 
-// IsDir returns true if file is a directory (folder)
+// IsDir returns true if file is a directory (folder), or an archive file
+// that is browsed as a folder
 func (fn *FileNode) IsDir() bool {
-	return fn.Info.IsDir()
+	return fn.Info.IsDir() || fn.Info.IsArchive()
+}
+
+// IsArchive returns true if file is an archive file that is browsed as a
+// folder -- see ArchiveBrowsing
+func (fn *FileNode) IsArchive() bool {
+	return fn.Info.IsArchive()
+}
+
+// IsInArchive returns true if file is within an archive file, and thus
+// read-only
+func (fn *FileNode) IsInArchive() bool {
+	return fn.FS() == nil && InArchive(string(fn.FPath))
+}
+
+// FS returns the fs.FS filesystem of the tree, or nil for the OS filesystem
+func (fn *FileNode) FS() fs.FS {
+	if fn.FRoot == nil {
+		return nil
+	}
+	return fn.FRoot.FS
+}
+
+// IsReadOnly returns true if the file cannot be changed, because it is in
+// an fs.FS filesystem or within an archive file
+func (fn *FileNode) IsReadOnly() bool {
+	return fn.FS() != nil || fn.IsInArchive()
 }
 
 // IsIrregular  returns true if file is a special "Irregular" node
@@ -524,6 +587,27 @@ func (fn *FileNode) MyRelPath() string {
 	return RelFilePath(string(fn.FPath), string(fn.FRoot.FPath))
 }
 
+// TreeTableValue returns the values of the Size, Kind, Mode, Modified and
+// Vcs columns of a TreeTable showing files, satisfying TreeTableValuer
+func (fn *FileNode) TreeTableValue(col string) (any, bool) {
+	switch col {
+	case "Size":
+		if fn.IsDir() {
+			return nil, true
+		}
+		return fn.Info.Size, true
+	case "Kind":
+		return fn.Info.Kind, true
+	case "Mode":
+		return fn.Info.Mode, true
+	case "Modified":
+		return fn.Info.ModTime, true
+	case "Vcs":
+		return fn.Info.Vcs, true
+	}
+	return nil, false
+}
+
 // ReadDir reads all the files at given directory into this directory node --
 // uses config children to preserve extra info already stored about files. The
 // root node represents the directory at the given path.  Returns os.Stat
@@ -536,7 +620,7 @@ func (fn *FileNode) ReadDir(path string) error {
 		return err
 	}
 	fn.FPath = gi.FileName(pth)
-	err = fn.Info.InitFile(string(fn.FPath))
+	err = fn.Info.InitFileFS(fn.FS(), string(fn.FPath))
 	if err != nil {
 		log.Printf("giv.FileTree: could not read directory: %v err: %v\n", fn.FPath, err)
 		return err
@@ -550,6 +634,9 @@ func (fn *FileNode) ReadDir(path string) error {
 // a VCS repository.  if updateFiles is true, gets the files in the dir.
 // returns true if a repository was newly found here.
 func (fn *FileNode) DetectVcsRepo(updateFiles bool) bool {
+	if fn.FS() != nil {
+		return false
+	}
 	repo, _ := fn.Repo()
 	if repo != nil {
 		if updateFiles {
@@ -584,6 +671,7 @@ func (fn *FileNode) UpdateDir() {
 	fn.SetOpen()
 	fn.FRoot.SetDirOpen(fn.FPath)
 	config := fn.ConfigOfFiles(path)
+	inArc := fn.IsArchive() || fn.IsReadOnly()
 	hasExtFiles := false
 	if fn.This() == fn.FRoot.This() {
 		if len(fn.FRoot.ExtFiles) > 0 {
@@ -608,7 +696,7 @@ func (fn *FileNode) UpdateDir() {
 		// 	fmt.Printf("fp: %v  nm: %v\n", fp, sf.Nm)
 		// }
 		sf.SetNodePath(fp)
-		if sf.IsDir() {
+		if sf.IsDir() || inArc {
 			sf.Info.Vcs = vci.Stored // always
 		} else if repo != nil {
 			rstat := rnode.RepoFiles.Status(repo, string(sf.FPath))
@@ -628,30 +716,49 @@ func (fn *FileNode) ConfigOfFiles(path string) kit.TypeAndNameList {
 	config1 := kit.TypeAndNameList{}
 	config2 := kit.TypeAndNameList{}
 	typ := fn.FRoot.NodeType
-	filepath.Walk(path, func(pth string, info os.FileInfo, err error) error {
+	fsys := fn.FS()
+	readDir := fsys != nil
+	if !readDir {
+		_, _, readDir = SplitArchivePath(path)
+	}
+	if readDir {
+		ents, err := FSReadDir(fsys, path)
 		if err != nil {
-			emsg := fmt.Sprintf("giv.FileNode ConfigFilesIn Path %q: Error: %v", path, err)
-			log.Println(emsg)
-			return nil // ignore
+			log.Printf("giv.FileNode ConfigFilesIn Path %q: Error: %v\n", path, err)
 		}
-		if pth == path { // proceed..
-			return nil
-		}
-		_, fnm := filepath.Split(pth)
-		if fn.FRoot.DirsOnTop {
-			if info.IsDir() {
-				config1.Add(typ, fnm)
+		for _, ent := range ents {
+			if fn.FRoot.DirsOnTop && !ent.IsDir() {
+				config2.Add(typ, ent.Name())
 			} else {
-				config2.Add(typ, fnm)
+				config1.Add(typ, ent.Name())
 			}
-		} else {
-			config1.Add(typ, fnm)
-		}
-		if info.IsDir() {
-			return filepath.SkipDir
 		}
-		return nil
-	})
+	} else {
+		filepath.Walk(path, func(pth string, info os.FileInfo, err error) error {
+			if err != nil {
+				emsg := fmt.Sprintf("giv.FileNode ConfigFilesIn Path %q: Error: %v", path, err)
+				log.Println(emsg)
+				return nil // ignore
+			}
+			if pth == path { // proceed..
+				return nil
+			}
+			_, fnm := filepath.Split(pth)
+			if fn.FRoot.DirsOnTop {
+				if info.IsDir() {
+					config1.Add(typ, fnm)
+				} else {
+					config2.Add(typ, fnm)
+				}
+			} else {
+				config1.Add(typ, fnm)
+			}
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
 	modSort := fn.FRoot.DirSortByModTime(gi.FileName(path))
 	if fn.FRoot.DirsOnTop {
 		if modSort {
@@ -669,8 +776,8 @@ func (fn *FileNode) ConfigOfFiles(path string) kit.TypeAndNameList {
 // SortConfigByModTime sorts given config list by mod time
 func (fn *FileNode) SortConfigByModTime(confg kit.TypeAndNameList) {
 	sort.Slice(confg, func(i, j int) bool {
-		ifn, _ := os.Stat(filepath.Join(string(fn.FPath), confg[i].Name))
-		jfn, _ := os.Stat(filepath.Join(string(fn.FPath), confg[j].Name))
+		ifn, _ := FSStat(fn.FS(), filepath.Join(string(fn.FPath), confg[i].Name))
+		jfn, _ := FSStat(fn.FS(), filepath.Join(string(fn.FPath), confg[j].Name))
 		return ifn.ModTime().After(jfn.ModTime()) // descending
 	})
 }
@@ -697,14 +804,16 @@ func (fn *FileNode) SetNodePath(path string) error {
 
 // InitFileInfo initializes file info
 func (fn *FileNode) InitFileInfo() error {
-	effpath, err := filepath.EvalSymlinks(string(fn.FPath))
-	if err != nil {
-		// this happens too often for links -- skip
-		// log.Printf("giv.FileNode Path: %v could not be opened -- error: %v\n", fn.FPath, err)
-		return err
+	if !fn.IsReadOnly() { // no links in archives or fs.FS
+		effpath, err := filepath.EvalSymlinks(string(fn.FPath))
+		if err != nil {
+			// this happens too often for links -- skip
+			// log.Printf("giv.FileNode Path: %v could not be opened -- error: %v\n", fn.FPath, err)
+			return err
+		}
+		fn.FPath = gi.FileName(effpath)
 	}
-	fn.FPath = gi.FileName(effpath)
-	err = fn.Info.InitFile(string(fn.FPath))
+	err := fn.Info.InitFileFS(fn.FS(), string(fn.FPath))
 	if err != nil {
 		emsg := fmt.Errorf("giv.FileNode InitFileInfo Path %q: Error: %v", fn.FPath, err)
 		log.Println(emsg)
@@ -814,6 +923,7 @@ func (fn *FileNode) OpenBuf() (bool, error) {
 		fn.Buf.AddFileNode(fn)
 	}
 	fn.Buf.Hi.Style = FileNodeHiStyle
+	fn.Buf.FS = fn.FS()
 	return true, fn.Buf.Open(fn.FPath)
 }
 
@@ -1102,6 +1212,9 @@ func (fn *FileNode) OpenFileWith(command string) error {
 // Duplicate creates a copy of given file -- only works for regular files, not
 // directories
 func (fn *FileNode) DuplicateFile() error {
+	if fn.IsReadOnly() {
+		return fmt.Errorf("giv.FileNode cannot duplicate read-only file: %v", fn.FPath)
+	}
 	_, err := fn.Info.Duplicate()
 	if err == nil && fn.Par != nil {
 		fnp := fn.Par.Embed(KiT_FileNode).(*FileNode)
@@ -1110,9 +1223,24 @@ func (fn *FileNode) DuplicateFile() error {
 	return err
 }
 
+// ExtractFile extracts this file or directory within an archive (or all
+// of the archive, for the archive file itself) into a folder next to the
+// archive file, named for it without its extension, keeping the paths of
+// the files within the archive.
+func (fn *FileNode) ExtractFile() error {
+	arc, _, ok := SplitArchivePath(string(fn.FPath))
+	if !ok {
+		return fmt.Errorf("giv.FileNode cannot extract file that is not in an archive: %v", fn.FPath)
+	}
+	dest := arc[:len(arc)-len(ArchiveExt(arc))]
+	err := ExtractArchivePath(string(fn.FPath), dest)
+	fn.FRoot.UpdateNewFile(dest)
+	return err
+}
+
 // DeleteFile deletes this file
 func (fn *FileNode) DeleteFile() (err error) {
-	if fn.IsExternal() {
+	if fn.IsExternal() || fn.IsReadOnly() {
 		return nil
 	}
 	fn.CloseBuf()
@@ -1132,7 +1260,7 @@ func (fn *FileNode) DeleteFile() (err error) {
 
 // RenameFile renames file to new name
 func (fn *FileNode) RenameFile(newpath string) (err error) {
-	if fn.IsExternal() {
+	if fn.IsExternal() || fn.IsReadOnly() {
 		return nil
 	}
 	fn.CloseBuf() // invalid after this point
@@ -1174,7 +1302,7 @@ func (fn *FileNode) RenameFile(newpath string) (err error) {
 
 // NewFile makes a new file in given selected directory node
 func (fn *FileNode) NewFile(filename string, addToVcs bool) {
-	if fn.IsExternal() {
+	if fn.IsExternal() || fn.IsArchive() || fn.IsReadOnly() {
 		return
 	}
 	ppath := string(fn.FPath)
@@ -1198,7 +1326,7 @@ func (fn *FileNode) NewFile(filename string, addToVcs bool) {
 
 // NewFolder makes a new folder (directory) in given selected directory node
 func (fn *FileNode) NewFolder(foldername string) {
-	if fn.IsExternal() {
+	if fn.IsExternal() || fn.IsArchive() || fn.IsReadOnly() {
 		return
 	}
 	ppath := string(fn.FPath)
@@ -1218,7 +1346,7 @@ func (fn *FileNode) NewFolder(foldername string) {
 // CopyFileToDir copies given file path into node that is a directory.
 // This does NOT check for overwriting -- that must be done at higher level!
 func (fn *FileNode) CopyFileToDir(filename string, perm os.FileMode) {
-	if fn.IsExternal() {
+	if fn.IsExternal() || fn.IsArchive() || fn.IsReadOnly() {
 		return
 	}
 	ppath := string(fn.FPath)
@@ -1831,6 +1959,10 @@ func (ftv *FileTreeView) KeyInput(kt *key.ChordEvent) {
 	if gi.KeyEventTrace {
 		fmt.Printf("TreeView KeyInput: %v\n", ftv.Path())
 	}
+	ftv.TypeAheadKeyInput(kt)
+	if kt.IsProcessed() {
+		return
+	}
 	kf := gi.KeyFun(kt.Chord())
 	selMode := mouse.SelectModeBits(kt.Modifiers)
 
@@ -1879,8 +2011,9 @@ func (ftv *FileTreeView) ShowFileInfo() {
 	}
 }
 
-// OpenFileDefault opens file with default app for that file type (os defined)
-// runs open on Mac, xdg-open on Linux, and start on Windows
+// OpenFileDefault opens file with the default app for its extension set
+// in gi.Prefs.FileOpenWith, or the system default app for that file type
+// (runs open on Mac, xdg-open on Linux, and start on Windows)
 func (ftv *FileTreeView) OpenFileDefault() {
 	sels := ftv.SelectedViews()
 	for i := len(sels) - 1; i >= 0; i-- {
@@ -1888,7 +2021,7 @@ func (ftv *FileTreeView) OpenFileDefault() {
 		fftv := sn.Embed(KiT_FileTreeView).(*FileTreeView)
 		fn := fftv.FileNode()
 		if fn != nil {
-			fn.OpenFileDefault()
+			fn.OpenFile()
 		}
 	}
 }
@@ -1919,6 +2052,22 @@ func (ftv *FileTreeView) DuplicateFiles() {
 	}
 }
 
+// ExtractFiles calls ExtractFile on any selected nodes
+func (ftv *FileTreeView) ExtractFiles() {
+	sels := ftv.SelectedViews()
+	for i := len(sels) - 1; i >= 0; i-- {
+		sn := sels[i]
+		ftvv := sn.Embed(KiT_FileTreeView).(*FileTreeView)
+		fn := ftvv.FileNode()
+		if fn == nil {
+			continue
+		}
+		if err := fn.ExtractFile(); err != nil {
+			gi.PromptDialog(ftv.ViewportSafe(), gi.DlgOpts{Title: "Couldn't Extract", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
+		}
+	}
+}
+
 // DeleteFilesImpl does the actual deletion, no prompts
 func (ftv *FileTreeView) DeleteFilesImpl() {
 	sels := ftv.SelectedViews()
@@ -2381,7 +2530,7 @@ func (ftv *FileTreeView) PasteMime(md mimedata.Mimes) {
 		return
 	}
 	tfn := ftv.FileNode()
-	if tfn == nil || tfn.IsExternal() {
+	if tfn == nil || tfn.IsExternal() || tfn.IsArchive() || tfn.IsReadOnly() {
 		ftv.DropCancel()
 		return
 	}
@@ -2507,11 +2656,12 @@ func (ftv *FileTreeView) Dragged(de *dnd.Event) {
 }
 
 // FileTreeInactiveExternFunc is an ActionUpdateFunc that inactivates action if node is external
+// or read-only
 var FileTreeInactiveExternFunc = ActionUpdateFunc(func(fni any, act *gi.Action) {
 	ftv := fni.(ki.Ki).Embed(KiT_FileTreeView).(*FileTreeView)
 	fn := ftv.FileNode()
 	if fn != nil {
-		act.SetInactiveState(fn.IsExternal())
+		act.SetInactiveState(fn.IsExternal() || fn.IsReadOnly())
 	}
 })
 
@@ -2525,11 +2675,12 @@ var FileTreeActiveExternFunc = ActionUpdateFunc(func(fni any, act *gi.Action) {
 })
 
 // FileTreeInactiveDirFunc is an ActionUpdateFunc that inactivates action if node is a dir
+// (other than an archive file) or read-only
 var FileTreeInactiveDirFunc = ActionUpdateFunc(func(fni any, act *gi.Action) {
 	ftv := fni.(ki.Ki).Embed(KiT_FileTreeView).(*FileTreeView)
 	fn := ftv.FileNode()
 	if fn != nil {
-		act.SetInactiveState(fn.IsDir() || fn.IsExternal())
+		act.SetInactiveState((fn.IsDir() && !fn.IsArchive()) || fn.IsExternal() || fn.IsReadOnly())
 	}
 })
 
@@ -2542,6 +2693,26 @@ var FileTreeActiveDirFunc = ActionUpdateFunc(func(fni any, act *gi.Action) {
 	}
 })
 
+// FileTreeActiveWritableDirFunc is an ActionUpdateFunc that activates action if node is a dir
+// that files can be added to -- not an archive or read-only
+var FileTreeActiveWritableDirFunc = ActionUpdateFunc(func(fni any, act *gi.Action) {
+	ftv := fni.(ki.Ki).Embed(KiT_FileTreeView).(*FileTreeView)
+	fn := ftv.FileNode()
+	if fn != nil {
+		act.SetActiveState(fn.IsDir() && !fn.IsExternal() && !fn.IsArchive() && !fn.IsReadOnly())
+	}
+})
+
+// FileTreeActiveArchiveFunc is an ActionUpdateFunc that activates action if node is an archive
+// file or within one
+var FileTreeActiveArchiveFunc = ActionUpdateFunc(func(fni any, act *gi.Action) {
+	ftv := fni.(ki.Ki).Embed(KiT_FileTreeView).(*FileTreeView)
+	fn := ftv.FileNode()
+	if fn != nil {
+		act.SetActiveState(fn.IsArchive() || fn.IsInArchive())
+	}
+})
+
 // FileTreeActiveNotInVcsFunc is an ActionUpdateFunc that inactivates action if node is not under version control
 var FileTreeActiveNotInVcsFunc = ActionUpdateFunc(func(fni any, act *gi.Action) {
 	ftv := fni.(ki.Ki).Embed(KiT_FileTreeView).(*FileTreeView)
@@ -2678,6 +2849,29 @@ var FileTreeViewProps = ki.Props{
 		{"OpenFileDefault", ki.Props{
 			"label": "Open (w/default app)",
 		}},
+		{"OpenWith", ki.Props{
+			"label":        "Open With",
+			"desc":         "Open the file with an in-app handler, a system app, or a command",
+			"updtfunc":     FileTreeInactiveDirFunc,
+			"submenu-func": SubMenuFunc(FileTreeOpenWithMenu),
+			"Args": ki.PropSlice{
+				{"App", ki.Props{}},
+			},
+		}},
+		{"SetDefaultApp", ki.Props{
+			"label":        "Default App",
+			"desc":         "Set the default app for opening files with this extension",
+			"updtfunc":     FileTreeInactiveDirFunc,
+			"submenu-func": SubMenuFunc(FileTreeDefaultAppMenu),
+			"Args": ki.PropSlice{
+				{"App", ki.Props{}},
+			},
+		}},
+		{"ExtractFiles", ki.Props{
+			"label":    "Extract",
+			"desc":     "Extract the selected files from the archive, into a folder next to the archive named for it",
+			"updtfunc": FileTreeActiveArchiveFunc,
+		}},
 		{"sep-act", ki.BlankProp{}},
 		{"DuplicateFiles", ki.Props{
 			"label":    "Duplicate",
@@ -2714,7 +2908,7 @@ var FileTreeViewProps = ki.Props{
 			"label":    "New File...",
 			"desc":     "make a new file in this folder",
 			"shortcut": gi.KeyFunInsert,
-			"updtfunc": FileTreeActiveDirFunc,
+			"updtfunc": FileTreeActiveWritableDirFunc,
 			"Args": ki.PropSlice{
 				{"File Name", ki.Props{
 					"width": 60,
@@ -2726,7 +2920,7 @@ var FileTreeViewProps = ki.Props{
 			"label":    "New Folder...",
 			"desc":     "make a new folder within this folder",
 			"shortcut": gi.KeyFunInsertAfter,
-			"updtfunc": FileTreeActiveDirFunc,
+			"updtfunc": FileTreeActiveWritableDirFunc,
 			"Args": ki.PropSlice{
 				{"Folder Name", ki.Props{
 					"width": 60,
@@ -5,6 +5,7 @@
 package giv
 
 import (
+	"io/fs"
 	"reflect"
 
 	"github.com/goki/gi/gi"
@@ -17,7 +18,8 @@ import (
 //  FileValueView
 
 // FileValueView presents an action for displaying a FileName and selecting
-// icons from FileChooserDialog
+// icons from FileChooserDialog.  The fs:"name" tag selects the files from
+// the fs.FS registered under that name with RegisterFileFS.
 type FileValueView struct {
 	ValueViewBase
 }
@@ -65,7 +67,11 @@ func (vv *FileValueView) Activate(vp *gi.Viewport2D, dlgRecv ki.Ki, dlgFunc ki.R
 	cur := kit.ToString(vv.Value.Interface())
 	ext, _ := vv.Tag("ext")
 	desc, _ := vv.Tag("desc")
-	FileViewDialog(vp, cur, ext, DlgOpts{Title: vv.Name(), Prompt: desc}, nil,
+	var fsys fs.FS
+	if fsnm, ok := vv.Tag("fs"); ok {
+		fsys = FileFSByName(fsnm)
+	}
+	FileViewDialogFS(vp, fsys, cur, ext, DlgOpts{Title: vv.Name(), Prompt: desc}, nil,
 		vv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			if sig == int64(gi.DialogAccepted) {
 				dlg, _ := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
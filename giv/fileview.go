@@ -6,9 +6,10 @@ package giv
 
 import (
 	"fmt"
-	"io/ioutil"
+	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -51,6 +52,7 @@ type FileView struct {
 	DoneWatcher chan bool          `view:"-" desc:"channel to close watcher watcher"`
 	UpdtMu      sync.Mutex         `view:"-" desc:"UpdateFiles mutex"`
 	PrevPath    string             `view:"-" desc:"Previous path that was processed via UpdateFiles"`
+	FS          fs.FS              `view:"-" json:"-" xml:"-" desc:"filesystem to show the files of, if not the OS filesystem -- DirPath is then the slash-separated path within it, and the files are read-only"`
 }
 
 var KiT_FileView = kit.Types.AddType(&FileView{}, FileViewProps)
@@ -79,9 +81,10 @@ func FileViewDirOnlyFilter(fv *FileView, fi *FileInfo) bool {
 }
 
 // FileViewExtOnlyFilter is a FileViewFilterFunc that only shows files that
-// match the target extensions, and directories.
+// match the target extensions, and directories (including archive files
+// that can be browsed).
 func FileViewExtOnlyFilter(fv *FileView, fi *FileInfo) bool {
-	if fi.IsDir() {
+	if fi.IsDir() || fi.IsArchive() {
 		return true
 	}
 	ext := strings.ToLower(filepath.Ext(fi.Name))
@@ -92,7 +95,7 @@ func FileViewExtOnlyFilter(fv *FileView, fi *FileInfo) bool {
 // SetFilename sets the initial filename (splitting out path and filename) and
 // initializes the view
 func (fv *FileView) SetFilename(filename, ext string) {
-	fv.DirPath, fv.SelFile = filepath.Split(filename)
+	fv.DirPath, fv.SelFile = fv.SplitPath(filename)
 	fv.SetExt(ext)
 	fv.Config()
 }
@@ -105,9 +108,36 @@ func (fv *FileView) SetPathFile(path, file, ext string) {
 	fv.Config()
 }
 
-// SelectedFile returns the full path to selected file
+// SetFS sets the fs.FS filesystem to show the files of (nil for the OS
+// filesystem), at given path within it, with given initial selected file
+// (or "") and extension(s), and initializes the view
+func (fv *FileView) SetFS(fsys fs.FS, path, file, ext string) {
+	fv.FS = fsys
+	fv.SetPathFile(path, file, ext)
+}
+
+// JoinPath joins given path elements using the OS separator, or slashes for
+// paths in an fs.FS
+func (fv *FileView) JoinPath(elem ...string) string {
+	if fv.FS != nil {
+		return path.Join(elem...)
+	}
+	return filepath.Join(elem...)
+}
+
+// SplitPath splits given path into the directory and file, using the OS
+// separator, or slashes for paths in an fs.FS
+func (fv *FileView) SplitPath(fpath string) (dir, file string) {
+	if fv.FS != nil {
+		return path.Split(fpath)
+	}
+	return filepath.Split(fpath)
+}
+
+// SelectedFile returns the full path to selected file -- the path within
+// the FS if set
 func (fv *FileView) SelectedFile() string {
-	return filepath.Join(fv.DirPath, fv.SelFile)
+	return fv.JoinPath(fv.DirPath, fv.SelFile)
 }
 
 // SelectedFileInfo returns the currently-selected fileinfo, returns
@@ -119,12 +149,12 @@ func (fv *FileView) SelectedFileInfo() (*FileInfo, bool) {
 	return fv.Files[fv.SelectedIdx], true
 }
 
-// SelectFile selects the current file -- if a directory it opens
-// the directory; if a file it selects the file and closes dialog
+// SelectFile selects the current file -- if a directory (or archive file)
+// it opens the directory; if a file it selects the file and closes dialog
 func (fv *FileView) SelectFile() {
 	if fi, ok := fv.SelectedFileInfo(); ok {
-		if fi.IsDir() {
-			fv.DirPath = filepath.Join(fv.DirPath, fi.Name)
+		if fi.IsDir() || fi.IsArchive() {
+			fv.DirPath = fv.JoinPath(fv.DirPath, fi.Name)
 			fv.SelFile = ""
 			fv.SelectedIdx = -1
 			fv.UpdateFilesAction()
@@ -486,6 +516,10 @@ func (fv *FileView) ExtField() *gi.TextField {
 
 // UpdatePath ensures that path is in abs form and ready to be used..
 func (fv *FileView) UpdatePath() {
+	if fv.FS != nil {
+		fv.DirPath = FSPath(fv.DirPath)
+		return
+	}
 	if fv.DirPath == "" {
 		fv.DirPath, _ = os.Getwd()
 	}
@@ -521,45 +555,32 @@ func (fv *FileView) UpdateFiles() {
 
 	fv.UpdatePath()
 	pf := fv.PathField()
-	if len(gi.SavedPaths) == 0 {
-		gi.OpenPaths()
+	if fv.FS != nil { // saved paths are on the OS filesystem
+		pf.ItemsFromStringList([]string{fv.DirPath}, true, 0)
+	} else {
+		if len(gi.SavedPaths) == 0 {
+			gi.OpenPaths()
+		}
+		gi.SavedPaths.AddPath(fv.DirPath, gi.Prefs.Params.SavedPathsMax)
+		gi.SavePaths()
+		sp := []string(gi.SavedPaths)
+		pf.ItemsFromStringList(sp, true, 0)
 	}
-	gi.SavedPaths.AddPath(fv.DirPath, gi.Prefs.Params.SavedPathsMax)
-	gi.SavePaths()
-	sp := []string(gi.SavedPaths)
-	pf.ItemsFromStringList(sp, true, 0)
 	pf.SetText(fv.DirPath)
+	if tb := fv.ChildByName("path-tbar", 0); tb != nil {
+		for _, nm := range []string{"path-fav", "new-folder"} { // OS filesystem only
+			if ac, ok := tb.ChildByName(nm, 0).(*gi.Action); ok {
+				ac.SetInactiveState(fv.FS != nil)
+			}
+		}
+	}
 	sf := fv.SelField()
 	sf.SetText(fv.SelFile)
 	oswin.TheApp.Cursor(owin).Push(cursor.Wait)
 	defer oswin.TheApp.Cursor(owin).Pop()
 
-	effpath, err := filepath.EvalSymlinks(fv.DirPath)
-	if err != nil {
-		log.Printf("gi.FileView Path: %v could not be opened -- error: %v\n", effpath, err)
-		return
-	}
-	_, err = os.Lstat(effpath)
-	if err != nil {
-		log.Printf("gi.FileView Path: %v could not be opened -- error: %v\n", effpath, err)
-		return
-	}
-
 	fv.Files = make([]*FileInfo, 0, 1000)
-	filepath.Walk(effpath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			emsg := fmt.Sprintf("Path %q: Error: %v", effpath, err)
-			// if fv.Viewport != nil {
-			// 	gi.PromptDialog(fv.Viewport, "FileView UpdateFiles", emsg, gi.AddOk, gi.NoCancel, nil, nil)
-			// } else {
-			log.Printf("gi.FileView error: %v\n", emsg)
-			// }
-			return nil // ignore
-		}
-		if path == effpath { // proceed..
-			return nil
-		}
-		fi, ferr := NewFileInfo(path)
+	addFile := func(fi *FileInfo, ferr error) {
 		keep := ferr == nil
 		if fv.FilterFunc != nil {
 			keep = fv.FilterFunc(fv, fi)
@@ -567,11 +588,53 @@ func (fv *FileView) UpdateFiles() {
 		if keep {
 			fv.Files = append(fv.Files, fi)
 		}
-		if info.IsDir() {
-			return filepath.SkipDir
+	}
+
+	readDir := fv.FS != nil
+	if !readDir {
+		_, _, readDir = SplitArchivePath(fv.DirPath)
+	}
+	if readDir {
+		ents, err := FSReadDir(fv.FS, fv.DirPath)
+		if err != nil {
+			log.Printf("gi.FileView Path: %v could not be opened -- error: %v\n", fv.DirPath, err)
+			return
+		}
+		for _, ent := range ents {
+			addFile(NewFileInfoFS(fv.FS, fv.JoinPath(fv.DirPath, ent.Name())))
+		}
+	} else {
+		effpath, err := filepath.EvalSymlinks(fv.DirPath)
+		if err != nil {
+			log.Printf("gi.FileView Path: %v could not be opened -- error: %v\n", effpath, err)
+			return
+		}
+		_, err = os.Lstat(effpath)
+		if err != nil {
+			log.Printf("gi.FileView Path: %v could not be opened -- error: %v\n", effpath, err)
+			return
 		}
-		return nil
-	})
+
+		filepath.Walk(effpath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				emsg := fmt.Sprintf("Path %q: Error: %v", effpath, err)
+				// if fv.Viewport != nil {
+				// 	gi.PromptDialog(fv.Viewport, "FileView UpdateFiles", emsg, gi.AddOk, gi.NoCancel, nil, nil)
+				// } else {
+				log.Printf("gi.FileView error: %v\n", emsg)
+				// }
+				return nil // ignore
+			}
+			if path == effpath { // proceed..
+				return nil
+			}
+			addFile(NewFileInfo(path))
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
 
 	fvv := fv.FavsView()
 	fvv.ResetSelectedIdxs()
@@ -593,17 +656,15 @@ func (fv *FileView) UpdateFiles() {
 	}
 
 	if fv.PrevPath != fv.DirPath {
-		if oswin.TheApp.Platform() != oswin.MacOS {
+		if fv.FS == nil && oswin.TheApp.Platform() != oswin.MacOS {
 			// mac is not supported in a high-capacity fashion at this point
-			if fv.PrevPath == "" {
+			if fv.Watcher == nil {
 				fv.ConfigWatcher()
-			} else {
+				fv.WatchWatcher()
+			} else if fv.PrevPath != "" {
 				fv.Watcher.Remove(fv.PrevPath)
 			}
 			fv.Watcher.Add(fv.DirPath)
-			if fv.PrevPath == "" {
-				fv.WatchWatcher()
-			}
 		}
 		fv.PrevPath = fv.DirPath
 	}
@@ -618,7 +679,7 @@ func (fv *FileView) UpdateFavs() {
 // AddPathToFavs adds the current path to favorites
 func (fv *FileView) AddPathToFavs() {
 	dp := fv.DirPath
-	if dp == "" {
+	if dp == "" || fv.FS != nil {
 		return
 	}
 	_, fnm := filepath.Split(dp)
@@ -643,6 +704,14 @@ func (fv *FileView) AddPathToFavs() {
 
 // DirPathUp moves up one directory in the path
 func (fv *FileView) DirPathUp() {
+	if fv.FS != nil {
+		if fv.DirPath == "." {
+			return
+		}
+		fv.DirPath = path.Dir(fv.DirPath)
+		fv.UpdateFilesAction()
+		return
+	}
 	pdr, _ := filepath.Split(fv.DirPath)
 	if pdr == "" {
 		return
@@ -666,7 +735,7 @@ func (fv *FileView) PathFieldHistNext() {
 // NewFolder creates a new folder in current directory
 func (fv *FileView) NewFolder() {
 	dp := fv.DirPath
-	if dp == "" {
+	if dp == "" || fv.FS != nil {
 		return
 	}
 	np := filepath.Join(dp, "NewFolder")
@@ -750,6 +819,9 @@ func (fv *FileView) FavSelect(idx int) {
 	if idx < 0 || idx >= len(gi.Prefs.FavPaths) {
 		return
 	}
+	if fv.FS != nil { // favorites are on the OS filesystem
+		return
+	}
 	fi := gi.Prefs.FavPaths[idx]
 	fv.DirPath, _ = homedir.Expand(fi.Path)
 	fv.UpdateFilesAction()
@@ -846,15 +918,12 @@ func (fv *FileView) FileComplete(data any, text string, posLn, posCh int) (md co
 
 // PathComplete finds the possible completions for the path field
 func (fv *FileView) PathComplete(data any, path string, posLn, posCh int) (md complete.Matches) {
-	dir, seed := filepath.Split(path)
+	dir, seed := fv.SplitPath(path)
 	md.Seed = seed
-	d, err := os.Open(dir)
+	files, err := FSReadDir(fv.FS, dir)
 	if err != nil {
 		return md
 	}
-	defer d.Close()
-
-	files, err := ioutil.ReadDir(dir)
 	var dirs = []string{}
 	for _, f := range files {
 		if f.IsDir() && !strings.HasPrefix(f.Name(), ".") {
@@ -876,7 +945,11 @@ func (fv *FileView) PathComplete(data any, path string, posLn, posCh int) (md co
 // PathCompleteEdit is the editing function called when inserting the completion selection in the path field
 func (fv *FileView) PathCompleteEdit(data any, text string, cursorPos int, c complete.Completion, seed string) (ed complete.Edit) {
 	ed = complete.EditWord(text, cursorPos, c.Text, seed)
-	path := ed.NewText + string(filepath.Separator)
+	sep := string(filepath.Separator)
+	if fv.FS != nil {
+		sep = "/"
+	}
+	path := ed.NewText + sep
 	ed.NewText = path
 	ed.CursorAdjust += 1
 	return ed
@@ -0,0 +1,388 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// FindAll finds text in all of the gi.FindProviders -- all the text buffers
+// that are open in a view, the table and slice views, and the tree views --
+// and lists the matches grouped by their source.  Clicking on a match
+// navigates to it, in whatever window it is in.  It can be embedded in an
+// app, or opened in its own window with FindAllView, or the FindAll key
+// function (Shift+Control+F by default).
+type FindAll struct {
+	gi.Layout
+	Find       string         `desc:"text to find"`
+	IgnoreCase bool           `desc:"ignore case when finding"`
+	Matches    []gi.FindMatch `json:"-" xml:"-" desc:"the current matches, in the order shown"`
+	Buf        *TextBuf       `json:"-" xml:"-" desc:"buffer with the text of the matches"`
+}
+
+var KiT_FindAll = kit.Types.AddType(&FindAll{}, FindAllProps)
+
+// AddNewFindAll adds a new find all view to given parent node, with given
+// name -- call Config to configure it.
+func AddNewFindAll(parent ki.Ki, name string) *FindAll {
+	return parent.AddNewChild(KiT_FindAll, name).(*FindAll)
+}
+
+// FindAllProps are style properties for FindAll
+var FindAllProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"max-width":     -1,
+	"max-height":    -1,
+}
+
+// Config configures the view
+func (fa *FindAll) Config() {
+	fa.Lay = gi.LayoutVert
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(gi.KiT_Layout, "text-lay")
+	mods, updt := fa.ConfigChildren(config)
+	if !mods {
+		return
+	}
+	fa.IgnoreCase = true
+	fa.Buf = &TextBuf{}
+	fa.Buf.InitName(fa.Buf, "find-all-buf")
+	tl := fa.TextLay()
+	tl.SetStretchMax()
+	tv := AddNewTextView(tl, "text-view")
+	tv.SetInactive()
+	tv.SetProp("font-family", gi.Prefs.MonoFont)
+	tv.SetBuf(fa.Buf)
+	gi.FindProviders.Remove(fa.Buf) // don't find the matches themselves
+	tv.LinkSig.Connect(fa.This(), func(recv, send ki.Ki, sig int64, data any) {
+		fa.OpenLink(data.(string))
+	})
+	fa.ConfigToolBar()
+	fa.UpdateEnd(updt)
+}
+
+// ToolBar returns the toolbar
+func (fa *FindAll) ToolBar() *gi.ToolBar {
+	return fa.ChildByName("toolbar", 0).(*gi.ToolBar)
+}
+
+// TextLay returns the layout for the text view
+func (fa *FindAll) TextLay() *gi.Layout {
+	return fa.ChildByName("text-lay", 1).(*gi.Layout)
+}
+
+// TextView returns the text view
+func (fa *FindAll) TextView() *TextView {
+	return fa.TextLay().ChildByName("text-view", 0).(*TextView)
+}
+
+// FindText finds given text in all of the providers and shows the matches
+func (fa *FindAll) FindText(find string) {
+	fa.Find = find
+	fa.Matches = gi.FindProviders.FindAll(find, fa.IgnoreCase)
+	fa.UpdateMatches()
+}
+
+// UpdateMatches shows the current matches, grouped by source, with a link
+// to each match
+func (fa *FindAll) UpdateMatches() {
+	if fa.Buf == nil || fa.IsDestroyed() {
+		return
+	}
+	grps := map[string][]int{}
+	var srcs []string
+	for i, m := range fa.Matches {
+		if _, has := grps[m.Source]; !has {
+			srcs = append(srcs, m.Source)
+		}
+		grps[m.Source] = append(grps[m.Source], i)
+	}
+	var txt, mrk bytes.Buffer
+	if len(fa.Matches) == 0 && fa.Find != "" {
+		fmt.Fprintf(&txt, "No matches for: %s\n", fa.Find)
+		fmt.Fprintf(&mrk, "No matches for: %s\n", html.EscapeString(fa.Find))
+	}
+	for _, src := range srcs {
+		idxs := grps[src]
+		fmt.Fprintf(&txt, "%s: %d\n", src, len(idxs))
+		fmt.Fprintf(&mrk, "<b>%s</b>: %d\n", html.EscapeString(src), len(idxs))
+		for _, i := range idxs {
+			m := &fa.Matches[i]
+			fmt.Fprintf(&txt, "    %s: %s\n", m.Loc, m.Text)
+			fmt.Fprintf(&mrk, `    <a href="findall:///%d">%s</a>: %s`+"\n", i, html.EscapeString(m.Loc), FindMarkup(m.Text, fa.Find, fa.IgnoreCase))
+		}
+	}
+	fa.Buf.New(0)
+	fa.Buf.AppendTextMarkup(txt.Bytes(), mrk.Bytes(), EditSignal)
+	fa.Buf.Refresh()
+}
+
+// OpenLink navigates to the match for given findall:/// link
+func (fa *FindAll) OpenLink(url string) {
+	idx, err := strconv.Atoi(strings.TrimPrefix(url, "findall:///"))
+	if err != nil || idx < 0 || idx >= len(fa.Matches) {
+		return
+	}
+	if m := fa.Matches[idx]; m.Goto != nil {
+		m.Goto()
+	}
+}
+
+// ConfigToolBar configures the find field and actions in the toolbar
+func (fa *FindAll) ConfigToolBar() {
+	tb := fa.ToolBar()
+	tb.SetStretchMaxWidth()
+	gi.AddNewLabel(tb, "find-lbl", "Find:")
+	fnd := gi.AddNewTextField(tb, "find")
+	fnd.SetProp("width", units.NewCh(40))
+	fnd.SetStretchMaxWidth()
+	fnd.SetText(fa.Find)
+	fnd.Tooltip = "text to find in all the open buffers, tables and trees -- press enter to find"
+	fnd.TextFieldSig.Connect(fa.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(gi.TextFieldDone) {
+			fa.FindText(fnd.Text())
+		}
+	})
+	ic := gi.AddNewCheckBox(tb, "ignore-case")
+	ic.SetText("Ignore Case")
+	ic.Tooltip = "ignore case when finding"
+	ic.SetChecked(fa.IgnoreCase)
+	ic.ButtonSig.Connect(fa.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(gi.ButtonToggled) {
+			fa.IgnoreCase = ic.IsChecked()
+			fa.FindText(fa.Find)
+		}
+	})
+	tb.AddSeparator("act-sep")
+	tb.AddAction(gi.ActOpts{Label: "Find", Icon: "search", Tooltip: "find the text again, e.g., after changes"}, fa.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			fa.FindText(fnd.Text())
+		})
+}
+
+// FindMarkup returns the html-escaped text with the instances of find marked
+func FindMarkup(text, find string, ignoreCase bool) string {
+	var b strings.Builder
+	for find != "" {
+		i := gi.FindIndex(text, find, ignoreCase)
+		if i < 0 || i+len(find) > len(text) {
+			break
+		}
+		b.WriteString(html.EscapeString(text[:i]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[i : i+len(find)]))
+		b.WriteString("</mark>")
+		text = text[i+len(find):]
+	}
+	b.WriteString(html.EscapeString(text))
+	return b.String()
+}
+
+// FindAllView opens a window with a FindAll view, or raises the
+// existing one
+func FindAllView() *gi.Window {
+	winm := "gogi-find-all"
+	width := 1024
+	height := 600
+	win, recyc := gi.RecycleMainWindow(&gi.FindProviders, winm, "GoGi Find All", width, height)
+	if recyc {
+		return win
+	}
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+	fa := AddNewFindAll(mfr, "find-all")
+	fa.Viewport = vp
+	fa.Config()
+
+	vp.UpdateEndNoSig(updt)
+	win.GoStartEventLoop()
+	return win
+}
+
+// findGoto runs given navigation function on the event loop of given
+// window, and raises the window
+func findGoto(win *gi.Window, fun func()) {
+	if win == nil || win.IsClosed() {
+		return
+	}
+	win.RunOnNextFrame(fun)
+	win.Raise()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  FindProvider implementations
+
+// FindName is the gi.FindProvider name of the buffer: its file name
+func (tb *TextBuf) FindName() string {
+	if tb.Filename != "" {
+		return string(tb.Filename)
+	}
+	return tb.Nm
+}
+
+// FindMatches returns the lines of the buffer that contain given text, for
+// the gi.FindProvider interface -- buffers are registered while they have
+// a view.  Going to a match highlights it in the first view.
+func (tb *TextBuf) FindMatches(find string, ignoreCase bool) []gi.FindMatch {
+	_, tms := tb.Search([]byte(find), ignoreCase, false)
+	ms := make([]gi.FindMatch, len(tms))
+	for i, tm := range tms {
+		reg := tm.Reg
+		ms[i] = gi.FindMatch{Loc: fmt.Sprintf("%d", reg.Start.Ln+1), Text: strings.TrimSpace(string(tb.Line(reg.Start.Ln)))}
+		ms[i].Goto = func() {
+			if len(tb.Views) == 0 {
+				return
+			}
+			tv := tb.Views[0]
+			findGoto(tv.ParentWindow(), func() {
+				if !reg.IsNil() && tb.IsValidLine(reg.Start.Ln) {
+					tv.SetCursorShow(reg.Start)
+					tv.HighlightRegion(reg)
+					tv.GrabFocus()
+				}
+			})
+		}
+	}
+	return ms
+}
+
+// FindName is the gi.FindProvider name of the slice view: its ViewPath
+// if set, or its name
+func (sv *SliceViewBase) FindName() string {
+	if sv.ViewPath != "" {
+		return sv.ViewPath
+	}
+	return sv.Nm
+}
+
+// FindMatches returns the rows of the slice that contain given text in the
+// string representation of their value (or of any field, for structs),
+// for the gi.FindProvider interface.  Going to a match selects its row.
+func (sv *SliceViewBase) FindMatches(find string, ignoreCase bool) []gi.FindMatch {
+	if sv.Slice == nil || kit.IfaceIsNil(sv.Slice) {
+		return nil
+	}
+	if sv.ViewMu != nil {
+		sv.ViewMu.Lock()
+		defer sv.ViewMu.Unlock()
+	}
+	var ms []gi.FindMatch
+	sz := sv.SliceNPVal.Len()
+	for i := 0; i < sz; i++ {
+		txt := findValText(kit.OnePtrUnderlyingValue(sv.SliceNPVal.Index(i)))
+		if gi.FindIndex(txt, find, ignoreCase) < 0 {
+			continue
+		}
+		idx := i
+		ms = append(ms, gi.FindMatch{Loc: fmt.Sprintf("row %d", idx), Text: txt, Goto: func() {
+			findGoto(sv.ParentWindow(), func() {
+				if idx >= sv.UpdtSliceSize() {
+					return
+				}
+				sv.ScrollToIdx(idx)
+				sv.SelectIdxAction(idx, mouse.SelectOne)
+			})
+		}})
+	}
+	return ms
+}
+
+// findValText returns the text of given value for finding: the exported
+// fields of structs, separated by spaces, and the string of other values
+func findValText(val reflect.Value) string {
+	npv := kit.NonPtrValue(val)
+	if npv.Kind() != reflect.Struct {
+		return kit.ToString(val.Interface())
+	}
+	typ := npv.Type()
+	var flds []string
+	for i := 0; i < typ.NumField(); i++ {
+		fld := typ.Field(i)
+		if fld.PkgPath != "" || fld.Tag.Get("view") == "-" {
+			continue
+		}
+		flds = append(flds, kit.ToString(npv.Field(i).Interface()))
+	}
+	return strings.Join(flds, "  ")
+}
+
+// FindName is the gi.FindProvider name of the tree: the label of its root
+func (tv *TreeView) FindName() string {
+	if tv.SrcNode == nil {
+		return tv.Nm
+	}
+	return tv.Label()
+}
+
+// FindMatches returns the nodes of the tree whose label contains given
+// text, for the gi.FindProvider interface -- root tree views are
+// registered.  Going to a match opens its parents and selects it.
+func (tv *TreeView) FindMatches(find string, ignoreCase bool) []gi.FindMatch {
+	var ms []gi.FindMatch
+	tv.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		tvki := k.Embed(KiT_TreeView)
+		if tvki == nil {
+			return ki.Break
+		}
+		tvn := tvki.(*TreeView)
+		if tvn.SrcNode == nil || tvn.IsDeleted() || tvn.IsDestroyed() {
+			return ki.Break
+		}
+		lbl := tvn.Label()
+		if gi.FindIndex(lbl, find, ignoreCase) >= 0 {
+			ms = append(ms, gi.FindMatch{Loc: tvn.SrcNode.PathFrom(tv.SrcNode), Text: lbl, Goto: func() {
+				findGoto(tvn.ParentWindow(), func() {
+					if tvn.IsDeleted() || tvn.IsDestroyed() {
+						return
+					}
+					tvn.OpenParents()
+					tvn.SelectAction(mouse.SelectOne)
+					tvn.ScrollToMe()
+				})
+			}})
+		}
+		return ki.Continue
+	})
+	return ms
+}
+
+// RegisterFind registers the slice view as a gi.FindProvider, until it is
+// destroyed
+func (sv *SliceViewBase) RegisterFind() {
+	fp, ok := sv.This().(gi.FindProvider)
+	if !ok || !gi.FindProviders.Add(fp) {
+		return
+	}
+	sv.OnDestroy(func() {
+		gi.FindProviders.Remove(fp)
+	})
+}
+
+// RegisterFind registers the root tree view as a gi.FindProvider, until it
+// is destroyed
+func (tv *TreeView) RegisterFind() {
+	fp, ok := tv.This().(gi.FindProvider)
+	if !ok || !gi.FindProviders.Add(fp) {
+		return
+	}
+	tv.OnDestroy(func() {
+		gi.FindProviders.Remove(fp)
+	})
+}
@@ -0,0 +1,389 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// FormRule is a validation rule for a form editing a struct (e.g., a
+// StructView), which can span multiple fields of the struct -- e.g., that
+// an end date is after a start date.  Either Check or CheckAsync must be
+// set.
+type FormRule struct {
+	Name       string                      `desc:"name of the rule, e.g., for removing it"`
+	Fields     []string                    `desc:"names of the fields that the rule applies to -- it is checked when any of them changes, and its error is shown on them -- if empty, it is checked on any change, and its error is only shown in the summary"`
+	Check      func(stru any) error        `desc:"returns an error if the struct is not valid according to the rule, with the message shown to the user"`
+	CheckAsync func(stru any) func() error `desc:"for rules that take a while (e.g., uniqueness checks on a server): called on the struct to get the values that are needed, returning a function that does the check, which is run in a separate goroutine -- the form is pending until it is done, and its result is discarded if the rule is checked again in the meantime"`
+}
+
+// FormError is an error from a FormRule
+type FormError struct {
+	Rule *FormRule `desc:"rule that failed"`
+	Err  error     `desc:"the error returned by the rule"`
+}
+
+func (fe *FormError) Error() string {
+	return fe.Err.Error()
+}
+
+// FormRuler is an interface for structs that define the FormRules for
+// validating them in a form, which are used by the StructView
+type FormRuler interface {
+	// FormRules returns the rules for validating the struct
+	FormRules() []*FormRule
+}
+
+// FormValidator validates a struct against a set of FormRules, keeping
+// the errors from the last check of each rule, and tracking the async
+// rules that are pending.  It is safe to use from multiple goroutines.
+type FormValidator struct {
+	Rules   []*FormRule         `desc:"the rules, in the order that their errors are listed"`
+	Errs    map[*FormRule]error `desc:"errors from the last check of each rule that failed"`
+	Pending map[*FormRule]int   `desc:"async rules that are being checked, with the generation of the check"`
+	Gen     int                 `desc:"generation of the checks, incremented for each one, for discarding the results of stale async checks"`
+	Mu      sync.Mutex          `view:"-" desc:"mutex protecting the state"`
+}
+
+// AddRule adds a rule with given name, fields it applies to (see
+// FormRule), and check function
+func (fv *FormValidator) AddRule(name string, fields []string, check func(stru any) error) *FormRule {
+	rl := &FormRule{Name: name, Fields: fields, Check: check}
+	fv.AddRules(rl)
+	return rl
+}
+
+// AddAsyncRule adds a rule with given name, fields it applies to, and
+// async check function (see FormRule)
+func (fv *FormValidator) AddAsyncRule(name string, fields []string, check func(stru any) func() error) *FormRule {
+	rl := &FormRule{Name: name, Fields: fields, CheckAsync: check}
+	fv.AddRules(rl)
+	return rl
+}
+
+// AddRules adds given rules
+func (fv *FormValidator) AddRules(rules ...*FormRule) {
+	fv.Mu.Lock()
+	fv.Rules = append(fv.Rules, rules...)
+	fv.Mu.Unlock()
+}
+
+// DeleteRule deletes the rule with given name, and its error, returning
+// false if not found
+func (fv *FormValidator) DeleteRule(name string) bool {
+	fv.Mu.Lock()
+	defer fv.Mu.Unlock()
+	for i, rl := range fv.Rules {
+		if rl.Name == name {
+			fv.Rules = append(fv.Rules[:i], fv.Rules[i+1:]...)
+			delete(fv.Errs, rl)
+			delete(fv.Pending, rl)
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesTo returns true if the rule is checked when given field changes
+// -- all rules apply to the empty field name
+func (rl *FormRule) AppliesTo(field string) bool {
+	if field == "" || len(rl.Fields) == 0 {
+		return true
+	}
+	for _, f := range rl.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the rules that apply to given changed field, or all of
+// them if it is empty, on given struct.  The async ones are started in
+// separate goroutines, and given function is called (if non-nil) when
+// each of them is done, unless it was checked again in the meantime --
+// it is called in that goroutine.
+func (fv *FormValidator) Validate(stru any, field string, done func()) {
+	fv.Mu.Lock()
+	fv.initMaps()
+	fv.Gen++
+	gen := fv.Gen
+	rules := make([]*FormRule, len(fv.Rules))
+	copy(rules, fv.Rules)
+	fv.Mu.Unlock()
+
+	for _, rl := range rules {
+		if !rl.AppliesTo(field) {
+			continue
+		}
+		if rl.CheckAsync == nil {
+			var err error
+			if rl.Check != nil {
+				err = rl.Check(stru)
+			}
+			fv.setErr(rl, err)
+			continue
+		}
+		chk := rl.CheckAsync(stru)
+		fv.Mu.Lock()
+		fv.initMaps()
+		delete(fv.Errs, rl)
+		fv.Pending[rl] = gen
+		fv.Mu.Unlock()
+		go func(rl *FormRule) {
+			err := chk()
+			fv.Mu.Lock()
+			if pg, has := fv.Pending[rl]; !has || pg != gen { // stale
+				fv.Mu.Unlock()
+				return
+			}
+			delete(fv.Pending, rl)
+			if err != nil {
+				fv.Errs[rl] = err
+			}
+			fv.Mu.Unlock()
+			if done != nil {
+				done()
+			}
+		}(rl)
+	}
+}
+
+// initMaps makes the maps if needed -- must be called under mutex
+func (fv *FormValidator) initMaps() {
+	if fv.Errs == nil {
+		fv.Errs = make(map[*FormRule]error)
+	}
+	if fv.Pending == nil {
+		fv.Pending = make(map[*FormRule]int)
+	}
+}
+
+// setErr sets the error of given rule, deleting it if nil
+func (fv *FormValidator) setErr(rl *FormRule, err error) {
+	fv.Mu.Lock()
+	fv.initMaps()
+	delete(fv.Pending, rl)
+	if err != nil {
+		fv.Errs[rl] = err
+	} else {
+		delete(fv.Errs, rl)
+	}
+	fv.Mu.Unlock()
+}
+
+// Reset clears all the errors, and discards the pending checks
+func (fv *FormValidator) Reset() {
+	fv.Mu.Lock()
+	fv.Errs = nil
+	fv.Pending = nil
+	fv.Mu.Unlock()
+}
+
+// IsValid returns true if there are no errors and no pending checks, so
+// the form can be submitted
+func (fv *FormValidator) IsValid() bool {
+	fv.Mu.Lock()
+	defer fv.Mu.Unlock()
+	return len(fv.Errs) == 0 && len(fv.Pending) == 0
+}
+
+// IsPending returns true if any async checks are pending
+func (fv *FormValidator) IsPending() bool {
+	fv.Mu.Lock()
+	defer fv.Mu.Unlock()
+	return len(fv.Pending) > 0
+}
+
+// AllErrs returns all the errors, in the order of the rules
+func (fv *FormValidator) AllErrs() []*FormError {
+	fv.Mu.Lock()
+	defer fv.Mu.Unlock()
+	var errs []*FormError
+	for _, rl := range fv.Rules {
+		if err, has := fv.Errs[rl]; has {
+			errs = append(errs, &FormError{Rule: rl, Err: err})
+		}
+	}
+	return errs
+}
+
+// FieldErrs returns the errors of the rules for given field, in the order
+// of the rules
+func (fv *FormValidator) FieldErrs(field string) []*FormError {
+	var errs []*FormError
+	for _, fe := range fv.AllErrs() {
+		if len(fe.Rule.Fields) > 0 && fe.Rule.AppliesTo(field) {
+			errs = append(errs, fe)
+		}
+	}
+	return errs
+}
+
+// FieldErrText returns the messages of the errors for given field, one
+// per line, or "" if there are none
+func (fv *FormValidator) FieldErrText(field string) string {
+	var msgs []string
+	for _, fe := range fv.FieldErrs(field) {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Summary returns a summary of all the errors, for showing in a banner,
+// with html formatting, and a note about the pending checks, if any --
+// "" if there is nothing to report
+func (fv *FormValidator) Summary() string {
+	errs := fv.AllErrs()
+	pend := fv.IsPending()
+	if len(errs) == 0 {
+		if pend {
+			return "<i>Checking...</i>"
+		}
+		return ""
+	}
+	var b strings.Builder
+	if len(errs) == 1 {
+		b.WriteString("<b>1 error:</b>")
+	} else {
+		fmt.Fprintf(&b, "<b>%d errors:</b>", len(errs))
+	}
+	for _, fe := range errs {
+		b.WriteString("<br>")
+		if len(fe.Rule.Fields) > 0 {
+			fmt.Fprintf(&b, "%s: ", strings.Join(fe.Rule.Fields, ", "))
+		}
+		b.WriteString(html.EscapeString(fe.Error()))
+	}
+	if pend {
+		b.WriteString("<br><i>Checking...</i>")
+	}
+	return b.String()
+}
+
+/////////////////////////////////////////////////////////////////////////
+//  StructView validation
+
+// FormValidator returns the Validator of the struct, making it if nil
+func (sv *StructView) FormValidator() *FormValidator {
+	if sv.Validator == nil {
+		sv.Validator = &FormValidator{}
+	}
+	return sv.Validator
+}
+
+// AddFormRule adds a rule for validating the struct, with given name,
+// fields it applies to, and check function (see FormRule) -- must be
+// called after SetStruct, which resets the rules to those of the struct,
+// if it is a FormRuler.  Call Validate after adding the rules.
+func (sv *StructView) AddFormRule(name string, fields []string, check func(stru any) error) *FormRule {
+	return sv.FormValidator().AddRule(name, fields, check)
+}
+
+// AddAsyncFormRule adds an async rule for validating the struct, with
+// given name, fields it applies to, and async check function (see
+// FormRule) -- see AddFormRule.
+func (sv *StructView) AddAsyncFormRule(name string, fields []string, check func(stru any) func() error) *FormRule {
+	return sv.FormValidator().AddAsyncRule(name, fields, check)
+}
+
+// Validate checks all the rules of the Validator, showing the errors, and
+// emitting the ValidSig -- returns true if it is valid, which is false
+// while async checks are pending, and will be updated when they are done.
+func (sv *StructView) Validate() bool {
+	return sv.ValidateField("")
+}
+
+// ValidateField checks the rules of the Validator that apply to given
+// field, which has changed, or all of them if it is empty -- see Validate.
+// Called automatically when a field is edited.
+func (sv *StructView) ValidateField(field string) bool {
+	if sv.Validator == nil || kit.IfaceIsNil(sv.Struct) {
+		return true
+	}
+	sv.Validator.Validate(sv.Struct, field, func() {
+		sv.UpdateFormErrs()
+	})
+	return sv.UpdateFormErrs()
+}
+
+// IsValid returns true if the struct is valid according to the rules of
+// the Validator, if any, as of the last checks, and none are pending
+func (sv *StructView) IsValid() bool {
+	return sv.Validator == nil || sv.Validator.IsValid()
+}
+
+// UpdateFormErrs updates the display of the errors of the Validator, and
+// emits the ValidSig -- returns true if it is valid -- safe to call from
+// another goroutine
+func (sv *StructView) UpdateFormErrs() bool {
+	if sv.This() == nil || sv.IsDeleted() || sv.IsDestroyed() {
+		return false
+	}
+	valid := sv.IsValid()
+	fe := ""
+	if sv.Validator != nil {
+		fe = sv.Validator.Summary()
+	}
+	updt := sv.UpdateStart()
+	banner := (fe != "") != (sv.FormErrs != "")
+	sv.FormErrs = fe
+	if banner && sv.IsConfiged() {
+		sv.SetFullReRender()
+		sv.Config() // adds or removes the banner
+	} else {
+		sv.ConfigFormErrs()
+	}
+	sv.UpdateEnd(updt)
+	sv.ValidSig.Emit(sv.This(), 0, valid)
+	return valid
+}
+
+// ConfigFormErrs configures the display of the errors of the Validator:
+// the FormErrs summary banner above the fields, and the labels of the
+// fields with errors, which are in the error color, and the error text of
+// those edited with a gi.TextField
+func (sv *StructView) ConfigFormErrs() {
+	if !sv.IsConfiged() {
+		return
+	}
+	if lb, ok := sv.ChildByName("form-errs", 1).(*gi.Label); ok {
+		lb.SetText(sv.FormErrs)
+	}
+	if sv.Validator == nil {
+		return
+	}
+	sg := sv.StructGrid()
+	ec := any("#B3261E")
+	if pv, ok := sv.PropInherit("error-color", ki.Inherit, ki.TypeProps); ok {
+		ec = pv
+	}
+	for i, vv := range sv.FieldViews {
+		if sg.NumChildren() <= 2*i+1 {
+			break
+		}
+		etxt := sv.Validator.FieldErrText(vv.AsValueViewBase().Field.Name)
+		lbl := sg.Child(i * 2).(*gi.Label)
+		had := lbl.Prop("color") != nil
+		if etxt != "" {
+			lbl.SetProp("color", ec)
+		} else {
+			lbl.DeleteProp("color")
+		}
+		if had != (etxt != "") {
+			sv.SetFullReRender() // restyle
+		}
+		if tf, ok := sg.Child(i*2 + 1).Embed(gi.KiT_TextField).(*gi.TextField); ok {
+			tf.SetErrorText(etxt)
+		}
+	}
+}
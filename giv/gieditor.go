@@ -203,6 +203,27 @@ func (ge *GiEditor) ConfigSplitView() {
 	sv.SetStruct(ge.KiRoot)
 }
 
+// StyleDump shows the computed style of the currently selected widget in
+// the tree, along with the source of each style value -- this turns on
+// gist.StyleProvTrace and re-styles the widget to record the sources.
+func (ge *GiEditor) StyleDump() {
+	sels := ge.TreeView().SelectedSrcNodes()
+	if len(sels) == 0 {
+		gi.PromptDialog(ge.Viewport, gi.DlgOpts{Title: "No Widget Selected", Prompt: "Select a widget in the tree to view its style"}, gi.AddOk, gi.NoCancel, nil, nil)
+		return
+	}
+	gii, ok := sels[0].(gi.Node2D)
+	if !ok || gii.AsWidget() == nil {
+		gi.PromptDialog(ge.Viewport, gi.DlgOpts{Title: "Not a Widget", Prompt: fmt.Sprintf("Selected node: %v is not a widget and has no style", sels[0].Name())}, gi.AddOk, gi.NoCancel, nil, nil)
+		return
+	}
+	if !gist.StyleProvTrace {
+		gist.StyleProvTrace = true
+		gii.Style2D()
+	}
+	TextViewDialog(ge.Viewport, []byte(gii.AsWidget().StyleDump()), DlgOpts{Title: "Style Dump: " + sels[0].Name()})
+}
+
 func (ge *GiEditor) SetChanged() {
 	ge.Changed = true
 	ge.ToolBar().UpdateActions() // nil safe
@@ -237,6 +258,11 @@ var GiEditorProps = ki.Props{
 				act.SetActiveStateUpdt(ge.Changed)
 			}),
 		}},
+		{"StyleDump", ki.Props{
+			"label": "Style Dump",
+			"icon":  "info",
+			"desc":  "show the computed style of the selected widget, along with the source of each style value (turns on gist.StyleProvTrace)",
+		}},
 		{"sep-file", ki.BlankProp{}},
 		{"Open", ki.Props{
 			"label": "Open",
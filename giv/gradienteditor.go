@@ -0,0 +1,721 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+	"github.com/srwiley/rasterx"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//  GradientEditor
+
+// GradientEditor edits a gradient color spec: the stops are shown on a bar,
+// where they can be selected and dragged to new positions (double-click on
+// the bar to add a new stop), and the color of the selected stop is edited
+// with a ColorView.  The gradient can be toggled between linear and radial,
+// and the angle of a linear gradient set in degrees, using the CSS
+// convention (0 = to top, 90 = to right).
+type GradientEditor struct {
+	gi.Frame
+	Spec     gist.ColorSpec `desc:"the gradient color spec that we edit"`
+	Angle    float32        `desc:"angle of a linear gradient, in degrees: 0 = to top, 90 = to right"`
+	CurStop  int            `desc:"index of the currently selected stop"`
+	TmpSave  ValueView      `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	ViewSig  ki.Signal      `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
+	ManipSig ki.Signal      `json:"-" xml:"-" desc:"manipulating signal -- this is sent when stops are being dragged or colors manipulated -- ViewSig is only sent at end for final selected value"`
+	ViewPath string         `desc:"a record of parent View names that have led up to this view -- displayed as extra contextual information in view dialog windows"`
+}
+
+var KiT_GradientEditor = kit.Types.AddType(&GradientEditor{}, GradientEditorProps)
+
+// AddNewGradientEditor adds a new gradient editor to given parent node, with given name.
+func AddNewGradientEditor(parent ki.Ki, name string) *GradientEditor {
+	return parent.AddNewChild(KiT_GradientEditor, name).(*GradientEditor)
+}
+
+func (ge *GradientEditor) Disconnect() {
+	ge.Frame.Disconnect()
+	ge.ViewSig.DisconnectAll()
+	ge.ManipSig.DisconnectAll()
+}
+
+var GradientEditorProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_NodeFlags,
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+}
+
+// SetSpec sets the color spec to edit, making a copy of it -- if it is
+// not a gradient, a linear gradient from its color to white is created
+func (ge *GradientEditor) SetSpec(cs *gist.ColorSpec) {
+	ge.Spec.CopyFrom(cs)
+	if ge.Spec.Gradient == nil || ge.Spec.Source == gist.SolidColor {
+		clr := ge.Spec.Color
+		if clr.IsNil() {
+			clr.SetColor(color.Black)
+		}
+		ge.Spec.NewLinearGradient()
+		ge.Spec.Color = clr
+		ge.Spec.Gradient.Stops = []rasterx.GradStop{
+			{StopColor: clr, Offset: 0, Opacity: 1},
+			{StopColor: color.White, Offset: 1, Opacity: 1},
+		}
+		ge.Angle = 90
+		ge.SetLinearPoints()
+	} else if !ge.Spec.Gradient.IsRadial {
+		ge.Angle = ge.AngleFromPoints()
+	}
+	ge.CurStop = ints.MaxInt(0, ints.MinInt(ge.CurStop, len(ge.Spec.Gradient.Stops)-1))
+	ge.Config()
+	ge.Update()
+}
+
+// Stops returns the gradient stops
+func (ge *GradientEditor) Stops() []rasterx.GradStop {
+	if ge.Spec.Gradient == nil {
+		return nil
+	}
+	return ge.Spec.Gradient.Stops
+}
+
+// SetRadial sets the gradient to be radial (else linear), centered in
+// the bounding box of the object
+func (ge *GradientEditor) SetRadial(radial bool) {
+	gr := ge.Spec.Gradient
+	gr.IsRadial = radial
+	gr.Units = rasterx.ObjectBoundingBox
+	gr.Matrix = rasterx.Identity
+	if radial {
+		ge.Spec.Source = gist.RadialGradient
+		gr.Points = [5]float64{0.5, 0.5, 0.5, 0.5, 0.5}
+	} else {
+		ge.Spec.Source = gist.LinearGradient
+		ge.SetLinearPoints()
+	}
+	ge.Changed(true)
+}
+
+// SetAngle sets the angle of a linear gradient, in degrees
+func (ge *GradientEditor) SetAngle(deg float32) {
+	ge.Angle = deg
+	if !ge.Spec.Gradient.IsRadial {
+		ge.SetLinearPoints()
+	}
+	ge.Changed(true)
+}
+
+// SetLinearPoints sets the points of a linear gradient from the Angle,
+// within the bounding box of the object
+func (ge *GradientEditor) SetLinearPoints() {
+	gr := ge.Spec.Gradient
+	gr.Units = rasterx.ObjectBoundingBox
+	rad := mat32.DegToRad(ge.Angle)
+	dx := 0.5 * mat32.Sin(rad)
+	dy := -0.5 * mat32.Cos(rad)
+	gr.Points = [5]float64{float64(0.5 - dx), float64(0.5 - dy), float64(0.5 + dx), float64(0.5 + dy), 0}
+}
+
+// AngleFromPoints returns the angle of a linear gradient from its points
+func (ge *GradientEditor) AngleFromPoints() float32 {
+	gr := ge.Spec.Gradient
+	dx := float32(gr.Points[gist.GpX2] - gr.Points[gist.GpX1])
+	dy := float32(gr.Points[gist.GpY2] - gr.Points[gist.GpY1])
+	if dx == 0 && dy == 0 {
+		return 90
+	}
+	deg := mat32.RadToDeg(mat32.Atan2(dx, -dy))
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// SortStops sorts the stops by offset, keeping CurStop on the same stop
+func (ge *GradientEditor) SortStops() {
+	stops := ge.Stops()
+	n := len(stops)
+	if n == 0 {
+		return
+	}
+	idxs := make([]int, n)
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		return stops[idxs[i]].Offset < stops[idxs[j]].Offset
+	})
+	sorted := make([]rasterx.GradStop, n)
+	cur := ge.CurStop
+	for i, si := range idxs {
+		sorted[i] = stops[si]
+		if si == cur {
+			ge.CurStop = i
+		}
+	}
+	copy(stops, sorted)
+}
+
+// SelectStop selects the stop at given index
+func (ge *GradientEditor) SelectStop(idx int) {
+	if idx < 0 || idx >= len(ge.Stops()) {
+		return
+	}
+	ge.CurStop = idx
+	ge.Update()
+}
+
+// SetStopOffset sets the offset (0-1) of the current stop, re-sorting the stops
+func (ge *GradientEditor) SetStopOffset(off float32, final bool) {
+	stops := ge.Stops()
+	if ge.CurStop >= len(stops) {
+		return
+	}
+	stops[ge.CurStop].Offset = float64(mat32.Clamp(off, 0, 1))
+	ge.SortStops()
+	ge.Changed(final)
+}
+
+// SetStopColor sets the color of the current stop
+func (ge *GradientEditor) SetStopColor(clr gist.Color, final bool) {
+	stops := ge.Stops()
+	if ge.CurStop >= len(stops) {
+		return
+	}
+	stops[ge.CurStop].StopColor = clr
+	stops[ge.CurStop].Opacity = 1
+	ge.Changed(final)
+}
+
+// AddStop adds a new stop at given offset (0-1), with the color of the
+// gradient at that point, and selects it
+func (ge *GradientEditor) AddStop(off float32) {
+	gr := ge.Spec.Gradient
+	off = mat32.Clamp(off, 0, 1)
+	clr := ge.StopsColorAt(off)
+	gr.Stops = append(gr.Stops, rasterx.GradStop{StopColor: clr, Offset: float64(off), Opacity: 1})
+	ge.CurStop = len(gr.Stops) - 1
+	ge.SortStops()
+	ge.Changed(true)
+}
+
+// AddStopAfterCur adds a new stop half-way between the current stop and
+// the next one (or the previous one if it is the last)
+func (ge *GradientEditor) AddStopAfterCur() {
+	stops := ge.Stops()
+	n := len(stops)
+	switch {
+	case n == 0:
+		ge.AddStop(0.5)
+	case ge.CurStop < n-1:
+		ge.AddStop(float32(0.5 * (stops[ge.CurStop].Offset + stops[ge.CurStop+1].Offset)))
+	case n > 1:
+		ge.AddStop(float32(0.5 * (stops[n-2].Offset + stops[n-1].Offset)))
+	default:
+		ge.AddStop(float32(0.5 * stops[0].Offset))
+	}
+}
+
+// DeleteCurStop deletes the current stop -- a gradient must have at
+// least two stops
+func (ge *GradientEditor) DeleteCurStop() {
+	gr := ge.Spec.Gradient
+	if len(gr.Stops) <= 2 {
+		return
+	}
+	gr.Stops = append(gr.Stops[:ge.CurStop], gr.Stops[ge.CurStop+1:]...)
+	ge.CurStop = ints.MinInt(ge.CurStop, len(gr.Stops)-1)
+	ge.Changed(true)
+}
+
+// StopsColorAt returns the color of the gradient stops at given offset (0-1)
+func (ge *GradientEditor) StopsColorAt(off float32) color.Color {
+	stops := ge.Stops()
+	n := len(stops)
+	if n == 0 {
+		return color.Black
+	}
+	o := float64(off)
+	if o <= stops[0].Offset {
+		return stops[0].StopColor
+	}
+	for i := 1; i < n; i++ {
+		s1 := stops[i-1]
+		s2 := stops[i]
+		if o > s2.Offset {
+			continue
+		}
+		c1 := gist.ColorFromColor(s1.StopColor)
+		c2 := gist.ColorFromColor(s2.StopColor)
+		d := s2.Offset - s1.Offset
+		if d <= 0 {
+			return c2
+		}
+		return c1.Blend(100*float32((o-s1.Offset)/d), c2)
+	}
+	return stops[n-1].StopColor
+}
+
+// Changed is called when the gradient has changed, sending ViewSig if
+// final, else ManipSig, and updating the display
+func (ge *GradientEditor) Changed(final bool) {
+	if ge.TmpSave != nil {
+		ge.TmpSave.SaveTmp()
+	}
+	if final {
+		ge.ViewSig.Emit(ge.This(), 0, nil)
+	} else {
+		ge.ManipSig.Emit(ge.This(), 0, nil)
+	}
+	ge.Update()
+}
+
+// Config configures a standard setup of entire view
+func (ge *GradientEditor) Config() {
+	if ge.HasChildren() {
+		return
+	}
+	updt := ge.UpdateStart()
+	ge.Lay = gi.LayoutVert
+	ge.SetProp("spacing", gi.StdDialogVSpaceUnits)
+
+	tl := gi.AddNewLayout(ge, "tool-lay", gi.LayoutHoriz)
+	tl.SetProp("spacing", units.NewEx(1))
+	gi.AddNewLabel(tl, "type-lab", "Type:")
+	tc := gi.AddNewComboBox(tl, "type")
+	tc.ItemsFromStringList([]string{"Linear", "Radial"}, false, 0)
+	tc.Tooltip = "linear or radial gradient"
+	tc.ComboSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data any) {
+		gev, _ := recv.Embed(KiT_GradientEditor).(*GradientEditor)
+		gev.SetRadial(sig == 1)
+	})
+	al := gi.AddNewLabel(tl, "angle-lab", "Angle:")
+	al.Redrawable = true
+	as := gi.AddNewSpinBox(tl, "angle")
+	as.Defaults()
+	as.SetMinMax(true, 0, true, 360)
+	as.Step = 15
+	as.PageStep = 45
+	as.Tooltip = "angle of linear gradient, in degrees: 0 = to top, 90 = to right"
+	as.SpinBoxSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data any) {
+		gev, _ := recv.Embed(KiT_GradientEditor).(*GradientEditor)
+		sb := send.Embed(gi.KiT_SpinBox).(*gi.SpinBox)
+		gev.SetAngle(sb.Value)
+	})
+	gi.AddNewStretch(tl, "str")
+	ad := gi.AddNewAction(tl, "add-stop")
+	ad.SetText("Add Stop")
+	ad.SetIcon("plus")
+	ad.Tooltip = "add a new stop after the current one -- or double-click on the bar"
+	ad.ActionSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data any) {
+		gev, _ := recv.Embed(KiT_GradientEditor).(*GradientEditor)
+		gev.AddStopAfterCur()
+	})
+	dl := gi.AddNewAction(tl, "del-stop")
+	dl.SetText("Delete Stop")
+	dl.SetIcon("minus")
+	dl.Tooltip = "delete the current stop -- there must be at least two stops"
+	dl.ActionSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data any) {
+		gev, _ := recv.Embed(KiT_GradientEditor).(*GradientEditor)
+		gev.DeleteCurStop()
+	})
+
+	bl := gi.AddNewLayout(ge, "bar-lay", gi.LayoutHoriz)
+	bl.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	gb := AddNewGradientBar(bl, "bar", ge)
+	gb.SetStretchMaxWidth()
+	pv := gi.AddNewFrame(bl, "preview", gi.LayoutHoriz)
+	pv.SetProp("min-width", units.NewEm(6))
+	pv.SetProp("min-height", units.NewEm(6))
+
+	sl := gi.AddNewLayout(ge, "stop-lay", gi.LayoutHoriz)
+	sl.SetProp("spacing", units.NewEx(1))
+	gi.AddNewLabel(sl, "off-lab", "Stop Offset:")
+	ofs := gi.AddNewSpinBox(sl, "offset")
+	ofs.Defaults()
+	ofs.SetMinMax(true, 0, true, 1)
+	ofs.Step = 0.05
+	ofs.PageStep = 0.25
+	ofs.Prec = 3
+	ofs.Tooltip = "position of the current stop along the gradient, from 0 to 1"
+	ofs.SpinBoxSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data any) {
+		gev, _ := recv.Embed(KiT_GradientEditor).(*GradientEditor)
+		sb := send.Embed(gi.KiT_SpinBox).(*gi.SpinBox)
+		gev.SetStopOffset(sb.Value, true)
+	})
+
+	cv := AddNewColorView(ge, "color")
+	cv.ViewPath = ge.ViewPath
+	cv.SetColor(color.Black)
+	cv.ViewSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data any) {
+		gev, _ := recv.Embed(KiT_GradientEditor).(*GradientEditor)
+		cvv := send.Embed(KiT_ColorView).(*ColorView)
+		gev.SetStopColor(cvv.Color, true)
+	})
+	cv.ManipSig.Connect(ge.This(), func(recv, send ki.Ki, sig int64, data any) {
+		gev, _ := recv.Embed(KiT_GradientEditor).(*GradientEditor)
+		cvv := send.Embed(KiT_ColorView).(*ColorView)
+		gev.SetStopColor(cvv.Color, false)
+	})
+	ge.UpdateEnd(updt)
+}
+
+// IsConfiged returns true if widget is fully configured
+func (ge *GradientEditor) IsConfiged() bool {
+	return ge.HasChildren()
+}
+
+func (ge *GradientEditor) ToolLay() *gi.Layout {
+	return ge.ChildByName("tool-lay", 0).(*gi.Layout)
+}
+
+func (ge *GradientEditor) Bar() *GradientBar {
+	return ge.ChildByName("bar-lay", 1).ChildByName("bar", 0).(*GradientBar)
+}
+
+func (ge *GradientEditor) Preview() *gi.Frame {
+	return ge.ChildByName("bar-lay", 1).ChildByName("preview", 1).(*gi.Frame)
+}
+
+func (ge *GradientEditor) StopLay() *gi.Layout {
+	return ge.ChildByName("stop-lay", 2).(*gi.Layout)
+}
+
+func (ge *GradientEditor) ColorView() *ColorView {
+	return ge.ChildByName("color", 3).(*ColorView)
+}
+
+func (ge *GradientEditor) Update() {
+	updt := ge.UpdateStart()
+	ge.UpdateImpl()
+	ge.UpdateEnd(updt)
+}
+
+// UpdateImpl does the raw updates based on current value,
+// without UpdateStart / End wrapper
+func (ge *GradientEditor) UpdateImpl() {
+	if !ge.IsConfiged() || ge.Spec.Gradient == nil {
+		return
+	}
+	radial := ge.Spec.Gradient.IsRadial
+	tl := ge.ToolLay()
+	tc := tl.ChildByName("type", 1).(*gi.ComboBox)
+	if radial {
+		tc.SetCurIndex(1)
+	} else {
+		tc.SetCurIndex(0)
+	}
+	as := tl.ChildByName("angle", 3).(*gi.SpinBox)
+	as.SetValue(ge.Angle)
+	as.SetInactiveState(radial)
+	stops := ge.Stops()
+	if ge.CurStop < len(stops) {
+		st := stops[ge.CurStop]
+		ofs := ge.StopLay().ChildByName("offset", 1).(*gi.SpinBox)
+		ofs.SetValue(float32(st.Offset))
+		cv := ge.ColorView()
+		cv.Color = gist.ColorFromColor(st.StopColor)
+		cv.Update()
+	}
+	ge.Bar().UpdateSig()
+	pv := ge.Preview()
+	pv.Sty.Font.BgColor.CopyFrom(&ge.Spec)
+	pv.UpdateSig()
+}
+
+func (ge *GradientEditor) Render2D() {
+	if ge.FullReRenderIfNeeded() {
+		return
+	}
+	if ge.PushBounds() {
+		pv := ge.Preview()
+		pv.Sty.Font.BgColor.CopyFrom(&ge.Spec) // styling resets
+		ge.PopBounds()
+	}
+	ge.Frame.Render2D()
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  GradientBar
+
+// GradientBar displays the stops of the gradient in a GradientEditor along
+// a horizontal bar, and supports selecting, dragging and adding stops with
+// the mouse.
+type GradientBar struct {
+	gi.WidgetBase
+	Editor   *GradientEditor `json:"-" xml:"-" view:"-" desc:"the editor that we are a part of"`
+	Dragging bool            `json:"-" xml:"-" view:"-" desc:"true if a stop is being dragged"`
+}
+
+var KiT_GradientBar = kit.Types.AddType(&GradientBar{}, GradientBarProps)
+
+// AddNewGradientBar adds a new gradient bar to given parent node, with given name.
+func AddNewGradientBar(parent ki.Ki, name string, ge *GradientEditor) *GradientBar {
+	gb := parent.AddNewChild(KiT_GradientBar, name).(*GradientBar)
+	gb.Editor = ge
+	return gb
+}
+
+var GradientBarProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"min-width":     units.NewEm(20),
+	"min-height":    units.NewEm(3),
+	"padding":       units.NewPx(6),
+	"border-width":  units.NewPx(1),
+	"border-color":  &gi.Prefs.Colors.Border,
+}
+
+// BarBox returns the position and size of the bar in which the gradient is
+// drawn, excluding the space below for the stop handles
+func (gb *GradientBar) BarBox() (pos, sz mat32.Vec2) {
+	spc := gb.Sty.BoxSpace()
+	pos = gb.LayState.Alloc.Pos.AddScalar(spc)
+	sz = gb.LayState.Alloc.Size.SubScalar(2 * spc)
+	sz.Y *= 0.6
+	return
+}
+
+// OffsetFromPos returns the stop offset (0-1) at given relative x position
+func (gb *GradientBar) OffsetFromPos(x float32) float32 {
+	_, sz := gb.BarBox()
+	if sz.X <= 0 {
+		return 0
+	}
+	return mat32.Clamp((x-gb.Sty.BoxSpace())/sz.X, 0, 1)
+}
+
+// StopAtPos returns the index of the stop whose handle is at given
+// relative x position, or -1 if none
+func (gb *GradientBar) StopAtPos(x float32) int {
+	_, sz := gb.BarBox()
+	spc := gb.Sty.BoxSpace()
+	tol := float32(5)
+	best := -1
+	bestd := tol
+	for i, st := range gb.Editor.Stops() {
+		sx := spc + float32(st.Offset)*sz.X
+		d := mat32.Abs(sx - x)
+		if d <= bestd {
+			best = i
+			bestd = d
+		}
+	}
+	return best
+}
+
+func (gb *GradientBar) MouseEvent() {
+	gb.ConnectEvent(oswin.MouseEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.Event)
+		gbb := recv.Embed(KiT_GradientBar).(*GradientBar)
+		if me.Button != mouse.Left || gbb.Editor == nil {
+			return
+		}
+		me.SetProcessed()
+		x := float32(gbb.PointToRelPos(me.Where).X)
+		switch me.Action {
+		case mouse.Press:
+			si := gbb.StopAtPos(x)
+			if si >= 0 {
+				gbb.Dragging = true
+				gbb.Editor.SelectStop(si)
+			}
+		case mouse.DoubleClick:
+			gbb.Dragging = false
+			gbb.Editor.AddStop(gbb.OffsetFromPos(x))
+		case mouse.Release:
+			if gbb.Dragging {
+				gbb.Dragging = false
+				gbb.Editor.SetStopOffset(gbb.OffsetFromPos(x), true)
+			}
+		}
+	})
+}
+
+func (gb *GradientBar) MouseDragEvent() {
+	gb.ConnectEvent(oswin.MouseDragEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.DragEvent)
+		gbb := recv.Embed(KiT_GradientBar).(*GradientBar)
+		if !gbb.Dragging || gbb.Editor == nil {
+			return
+		}
+		me.SetProcessed()
+		x := float32(gbb.PointToRelPos(me.Where).X)
+		gbb.Editor.SetStopOffset(gbb.OffsetFromPos(x), false)
+	})
+}
+
+func (gb *GradientBar) ConnectEvents2D() {
+	gb.MouseEvent()
+	gb.MouseDragEvent()
+	gb.HoverTooltipEvent()
+}
+
+// RenderBar renders the gradient stops along the bar, and the stop handles
+func (gb *GradientBar) RenderBar() {
+	if gb.Editor == nil || gb.Editor.Spec.Gradient == nil {
+		return
+	}
+	rs, pc, st := gb.RenderLock()
+	defer gb.RenderUnlock(rs)
+
+	pos, sz := gb.BarBox()
+	var cs gist.ColorSpec
+	cs.NewLinearGradient()
+	cs.CopyStopsFrom(&gb.Editor.Spec)
+	cs.SetGradientPoints(mat32.Box2{Min: pos, Max: pos.Add(sz)})
+	pc.FillBox(rs, pos, sz, &cs)
+
+	pc.FillStyle.SetColor(nil)
+	pc.StrokeStyle.SetColor(&st.Border.Color)
+	pc.StrokeStyle.Width = st.Border.Width
+	pc.DrawRectangle(rs, pos.X, pos.Y, sz.X, sz.Y)
+	pc.FillStrokeClear(rs)
+
+	hsz := mat32.Min(0.5*(gb.LayState.Alloc.Size.Y-2*st.BoxSpace()-sz.Y), 0.5*sz.Y)
+	hy := pos.Y + sz.Y + 0.25*hsz
+	for i, stp := range gb.Editor.Stops() {
+		x := pos.X + float32(stp.Offset)*sz.X
+		pc.FillStyle.SetColor(nil)
+		pc.StrokeStyle.SetColor(&st.Font.Color)
+		pc.StrokeStyle.Width.SetDot(1)
+		pc.DrawLine(rs, x, pos.Y, x, hy)
+		pc.Stroke(rs)
+		pc.FillStyle.SetColor(stp.StopColor)
+		if i == gb.Editor.CurStop {
+			pc.StrokeStyle.SetColor(&gi.Prefs.Colors.Select)
+			pc.StrokeStyle.Width.SetDot(3)
+		}
+		pc.DrawRectangle(rs, x-0.5*hsz, hy, hsz, hsz)
+		pc.FillStrokeClear(rs)
+	}
+}
+
+func (gb *GradientBar) Render2D() {
+	if gb.FullReRenderIfNeeded() {
+		return
+	}
+	if gb.PushBounds() {
+		gb.This().(gi.Node2D).ConnectEvents2D()
+		gb.RenderBar()
+		gb.Render2DChildren()
+		gb.PopBounds()
+	} else {
+		gb.DisconnectAllEvents(gi.RegPri)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  ColorSpecValueView
+
+// ColorSpecValueView presents an action for displaying a gist.ColorSpec,
+// showing its color or gradient, which pulls up a GradientEditor dialog
+type ColorSpecValueView struct {
+	ValueViewBase
+}
+
+var KiT_ColorSpecValueView = kit.Types.AddType(&ColorSpecValueView{}, nil)
+
+// ColorSpec returns the color spec value, or nil if not valid
+func (vv *ColorSpecValueView) ColorSpec() *gist.ColorSpec {
+	switch cs := vv.Value.Interface().(type) {
+	case gist.ColorSpec:
+		return &cs
+	case *gist.ColorSpec:
+		return cs
+	case **gist.ColorSpec:
+		if cs != nil {
+			return *cs
+		}
+	default:
+		log.Printf("ColorSpecValueView: could not get color spec value from type: %T val: %+v\n", cs, cs)
+	}
+	return nil
+}
+
+func (vv *ColorSpecValueView) WidgetType() reflect.Type {
+	vv.WidgetTyp = gi.KiT_Action
+	return vv.WidgetTyp
+}
+
+func (vv *ColorSpecValueView) UpdateWidget() {
+	if vv.Widget == nil {
+		return
+	}
+	ac := vv.Widget.(*gi.Action)
+	cs := vv.ColorSpec()
+	txt := "(none)"
+	if cs != nil && !cs.IsNil() {
+		switch cs.Source {
+		case gist.SolidColor:
+			txt = cs.Color.String()
+		default:
+			txt = fmt.Sprintf("%v (%d stops)", cs.Source, len(cs.Gradient.Stops))
+		}
+		if cs.Source == gist.SolidColor {
+			ac.SetProp("background-color", cs.Color)
+		} else {
+			bg := &gist.ColorSpec{}
+			bg.CopyFrom(cs)
+			ac.SetProp("background-color", bg)
+		}
+		ac.SetFullReRender()
+	}
+	ac.SetText(txt)
+}
+
+func (vv *ColorSpecValueView) ConfigWidget(widg gi.Node2D) {
+	vv.Widget = widg
+	vv.StdConfigWidget(widg)
+	ac := vv.Widget.(*gi.Action)
+	ac.SetProp("border-radius", units.NewPx(4))
+	ac.Tooltip, _ = vv.Tag("desc")
+	ac.ActionSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		vvv, _ := recv.Embed(KiT_ColorSpecValueView).(*ColorSpecValueView)
+		ac := vvv.Widget.(*gi.Action)
+		vvv.Activate(ac.ViewportSafe(), nil, nil)
+	})
+	vv.UpdateWidget()
+}
+
+func (vv *ColorSpecValueView) HasAction() bool {
+	return true
+}
+
+func (vv *ColorSpecValueView) Activate(vp *gi.Viewport2D, dlgRecv ki.Ki, dlgFunc ki.RecvFunc) {
+	if vv.IsInactive() {
+		return
+	}
+	cs := vv.ColorSpec()
+	if cs == nil {
+		return
+	}
+	desc, _ := vv.Tag("desc")
+	GradientEditorDialog(vp, cs, DlgOpts{Title: "Gradient Editor", Prompt: desc, TmpSave: vv.TmpSave},
+		vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+			if sig == int64(gi.DialogAccepted) {
+				ddlg := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
+				ncs := GradientEditorDialogValue(ddlg)
+				vv.SetValue(ncs)
+				vv.UpdateWidget()
+			}
+			if dlgRecv != nil && dlgFunc != nil {
+				dlgFunc(dlgRecv, send, sig, data)
+			}
+		})
+}
@@ -0,0 +1,312 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+// HelpView is the Help viewer: it shows the Markdown pages of the help
+// content registered in gi.HelpBundles in a MarkdownView, with the
+// contents of all the bundles in a tree on the left -- clicking on a
+// topic opens it.  Links between pages are relative to the page they are
+// in, and links of the form help:bundle/page.md#anchor can link to other
+// bundles.  It is opened with HelpViewOpen, or gi.OpenHelp, including by
+// the Help key function (F1 by default) at the gi.HelpURIFor the focus.
+type HelpView struct {
+	gi.Frame
+	URI     string     `desc:"HelpURI of the page being shown, as bundle/page.md#anchor -- empty for the contents of all the bundles"`
+	History []string   `desc:"HelpURIs of the pages shown, for going back and forward"`
+	HistIdx int        `desc:"index of the current page in the History"`
+	Topics  *HelpTopic `json:"-" xml:"-" desc:"root of the tree of the contents"`
+}
+
+var KiT_HelpView = kit.Types.AddType(&HelpView{}, HelpViewProps)
+
+// AddNewHelpView adds a new help view to given parent node, with given name.
+func AddNewHelpView(parent ki.Ki, name string) *HelpView {
+	return parent.AddNewChild(KiT_HelpView, name).(*HelpView)
+}
+
+// HelpTopic is a topic in the contents of the HelpView: a bundle, a page,
+// or a heading within a page
+type HelpTopic struct {
+	ki.Node
+	Title string `desc:"title of the topic"`
+	URI   string `desc:"HelpURI of the topic"`
+}
+
+var KiT_HelpTopic = kit.Types.AddType(&HelpTopic{}, nil)
+
+// Label returns the Title, for the contents tree
+func (ht *HelpTopic) Label() string {
+	return ht.Title
+}
+
+// Open opens the page at given HelpURI, or the contents of all the
+// bundles if it is empty, adding it to the History
+func (hv *HelpView) Open(uri string) {
+	if hv.HistIdx < len(hv.History) {
+		hv.History = hv.History[:hv.HistIdx+1]
+	}
+	hv.History = append(hv.History, uri)
+	hv.HistIdx = len(hv.History) - 1
+	hv.ShowURI(uri)
+}
+
+// Back goes back to the previous page in the History
+func (hv *HelpView) Back() {
+	if hv.HistIdx > 0 {
+		hv.HistIdx--
+		hv.ShowURI(hv.History[hv.HistIdx])
+	}
+}
+
+// Forward goes forward to the next page in the History
+func (hv *HelpView) Forward() {
+	if hv.HistIdx < len(hv.History)-1 {
+		hv.HistIdx++
+		hv.ShowURI(hv.History[hv.HistIdx])
+	}
+}
+
+// Contents opens the contents of all the bundles
+func (hv *HelpView) Contents() {
+	hv.Open("")
+}
+
+// ShowURI shows the page at given HelpURI, scrolled to its anchor, without
+// changing the History
+func (hv *HelpView) ShowURI(uri string) {
+	hv.Config()
+	md := hv.MarkdownView()
+	bnm, pg, anchor := gi.ParseHelpURI(uri)
+	cbnm, cpg, _ := gi.ParseHelpURI(hv.URI)
+	same := hv.URI != "" && bnm == cbnm && pg == cpg
+	hv.URI = uri
+	switch {
+	case same:
+	case uri == "":
+		md.SetMarkdown(hv.ContentsMarkdown())
+	default:
+		src, _, _, err := gi.HelpBundles.Open(uri)
+		if err != nil {
+			md.SetMarkdown("# Help Not Found\n\n" + err.Error())
+		} else {
+			md.SetMarkdown(string(src))
+		}
+	}
+	md.ScrollToAnchor(anchor)
+	hv.UpdateToolBar()
+}
+
+// OpenLink opens given link from the page being shown: links of the form
+// help:bundle/page.md#anchor, and links relative to the page, are opened
+// in the viewer, and all others in the system browser
+func (hv *HelpView) OpenLink(url string) {
+	switch {
+	case strings.HasPrefix(url, "help:"):
+		hv.Open(strings.TrimPrefix(url, "help:"))
+	case strings.Contains(url, ":"):
+		gi.OpenURL(url)
+	default:
+		bnm, pg, _ := gi.ParseHelpURI(hv.URI)
+		if pg == "" {
+			if hb := gi.HelpBundles.Bundle(bnm); hb != nil {
+				pg = hb.Index
+			}
+		}
+		hv.Open(bnm + "/" + path.Join(path.Dir(pg), url))
+	}
+}
+
+// ContentsMarkdown returns the Markdown for the contents of all the
+// bundles, with links to their pages
+func (hv *HelpView) ContentsMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Help\n\n")
+	hbs := gi.HelpBundles.All()
+	if len(hbs) == 0 {
+		sb.WriteString("No help is available.\n")
+	}
+	for _, hb := range hbs {
+		fmt.Fprintf(&sb, "- [%s](help:%s)\n", hb.Title, hb.Name)
+	}
+	return sb.String()
+}
+
+// ConfigTopics configures the tree of the contents of all the bundles:
+// their pages, and the top-level headings within them
+func (hv *HelpView) ConfigTopics() {
+	root := &HelpTopic{Title: "Help"}
+	root.InitName(root, "help")
+	for _, hb := range gi.HelpBundles.All() {
+		bt := root.AddNewChild(KiT_HelpTopic, hb.Name).(*HelpTopic)
+		bt.Title, bt.URI = hb.Title, hb.Name
+		for _, pg := range hb.Pages() {
+			src, err := hb.Page(pg)
+			if err != nil {
+				continue
+			}
+			hds := MarkdownHeadings(string(src))
+			pt := bt
+			if pg != hb.Index {
+				pt = bt.AddNewChild(KiT_HelpTopic, pg).(*HelpTopic)
+				pt.Title, pt.URI = strings.TrimSuffix(path.Base(pg), path.Ext(pg)), hb.Name+"/"+pg
+				if len(hds) > 0 {
+					pt.Title = hds[0].Text
+				}
+			}
+			for _, hd := range hds {
+				if hd.Level != 2 {
+					continue
+				}
+				ht := pt.AddNewChild(KiT_HelpTopic, hd.Anchor).(*HelpTopic)
+				ht.Title, ht.URI = hd.Text, pt.URI+"#"+hd.Anchor
+			}
+		}
+	}
+	hv.Topics = root
+	hv.TreeView().SetRootNode(root)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//   GUI configs
+
+// Config configures the view
+func (hv *HelpView) Config() {
+	hv.Lay = gi.LayoutVert
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(gi.KiT_SplitView, "splitview")
+	mods, updt := hv.ConfigChildren(config)
+	if !mods {
+		return
+	}
+	tb := hv.ToolBar()
+	tb.SetStretchMaxWidth()
+	ToolBarView(hv, hv.Viewport, tb)
+	split := hv.SplitView()
+	split.Dim = mat32.X
+	tvfr := gi.AddNewFrame(split, "tv-frame", gi.LayoutVert)
+	tvfr.SetStretchMax()
+	tvfr.SetProp("overflow", "auto")
+	tvfr.SetReRenderAnchor()
+	tv := AddNewTreeView(tvfr, "tv")
+	tv.SetInactive()
+	tv.TreeViewSig.Connect(hv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(TreeViewSelected) || data == nil {
+			return
+		}
+		tvn, _ := data.(ki.Ki).Embed(KiT_TreeView).(*TreeView)
+		if tvn == nil {
+			return
+		}
+		if ht, ok := tvn.SrcNode.(*HelpTopic); ok && ht.URI != hv.URI {
+			hv.Open(ht.URI)
+		}
+	})
+	md := AddNewMarkdownView(split, "markdown")
+	md.SetProp("width", units.NewCh(60))
+	md.LinkSig.Connect(hv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		recv.Embed(KiT_HelpView).(*HelpView).OpenLink(data.(string))
+	})
+	split.SetSplits(.25, .75)
+	hv.ConfigTopics()
+	hv.UpdateEnd(updt)
+}
+
+// ToolBar returns the toolbar
+func (hv *HelpView) ToolBar() *gi.ToolBar {
+	return hv.ChildByName("toolbar", 0).(*gi.ToolBar)
+}
+
+// SplitView returns the split view with the contents and the page
+func (hv *HelpView) SplitView() *gi.SplitView {
+	return hv.ChildByName("splitview", 1).(*gi.SplitView)
+}
+
+// TreeView returns the tree view of the contents
+func (hv *HelpView) TreeView() *TreeView {
+	return hv.SplitView().Child(0).Child(0).(*TreeView)
+}
+
+// MarkdownView returns the view of the page
+func (hv *HelpView) MarkdownView() *MarkdownView {
+	return hv.SplitView().Child(1).(*MarkdownView)
+}
+
+// UpdateToolBar updates the active state of the toolbar actions
+func (hv *HelpView) UpdateToolBar() {
+	if hv.HasChildren() {
+		hv.ToolBar().UpdateActions()
+	}
+}
+
+// HelpViewProps are style properties for HelpView
+var HelpViewProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_NodeFlags,
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+	"max-width":        -1,
+	"max-height":       -1,
+	"ToolBar": ki.PropSlice{
+		{"Back", ki.Props{
+			"icon": "backward",
+			"desc": "go back to the previous page",
+			"updtfunc": ActionUpdateFunc(func(hvi any, act *gi.Action) {
+				act.SetActiveStateUpdt(hvi.(*HelpView).HistIdx > 0)
+			}),
+		}},
+		{"Forward", ki.Props{
+			"icon": "forward",
+			"desc": "go forward to the next page",
+			"updtfunc": ActionUpdateFunc(func(hvi any, act *gi.Action) {
+				hv := hvi.(*HelpView)
+				act.SetActiveStateUpdt(hv.HistIdx < len(hv.History)-1)
+			}),
+		}},
+		{"Contents", ki.Props{
+			"icon": "home",
+			"desc": "show the contents of all the help",
+		}},
+	},
+}
+
+// HelpViewOpen opens the Help viewer at given HelpURI, in its own window,
+// raising the existing one if it is open -- see gi.OpenHelp
+func HelpViewOpen(uri string) *HelpView {
+	width := 1024
+	height := 768
+	win, recyc := gi.RecycleMainWindow(&gi.HelpBundles, "gogi-help", "Help", width, height)
+	if recyc {
+		mfr, err := win.MainFrame()
+		if err == nil {
+			hv := mfr.Child(0).(*HelpView)
+			hv.Open(uri)
+			return hv
+		}
+	}
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+	hv := AddNewHelpView(mfr, "help-view")
+	hv.Viewport = vp
+	hv.Open(uri)
+
+	vp.UpdateEndNoSig(updt)
+	win.GoStartEventLoop()
+	return hv
+}
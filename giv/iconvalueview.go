@@ -80,9 +80,8 @@ func (vv *IconValueView) Activate(vp *gi.Viewport2D, dlgRecv ki.Ki, dlgFunc ki.R
 		vv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			if sig == int64(gi.DialogAccepted) {
 				ddlg := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
-				si := SliceViewSelectDialogValue(ddlg)
-				if si >= 0 {
-					ic := gi.CurIconList[si]
+				ic := IconChooserDialogValue(ddlg)
+				if ic != "" {
 					vv.SetValue(ic)
 					vv.UpdateWidget()
 				}
@@ -0,0 +1,629 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+// IconViewItem is one item shown as a tile in an IconView
+type IconViewItem struct {
+	Label string      `desc:"label shown under the icon"`
+	Icon  gi.IconName `desc:"icon shown for the item, until a thumbnail is available"`
+	Thumb image.Image `desc:"thumbnail image for the item -- if nil and the IconView has a ThumbFunc, it is loaded lazily when the tile becomes visible"`
+	Data  any         `desc:"arbitrary data associated with the item, e.g., a FileInfo"`
+
+	thumbReq bool // thumbnail has been requested
+}
+
+// IconViewThumbFunc is a function that returns a thumbnail image for given
+// item, or nil if none is available.  It is called in a separate goroutine.
+type IconViewThumbFunc func(it *IconViewItem) image.Image
+
+// IconViewSignals are signals that IconView can send, in addition to the
+// WidgetSig WidgetSelected signals sent when the selection changes.
+type IconViewSignals int
+
+const (
+	// IconViewDoubleClicked is emitted when an item is double-clicked or
+	// Enter is pressed -- data is the index of the item
+	IconViewDoubleClicked IconViewSignals = iota
+
+	// IconViewZoomed is emitted when the tile size has been changed by
+	// zooming -- data is the new TileSize
+	IconViewZoomed
+
+	IconViewSignalsN
+)
+
+//go:generate stringer -type=IconViewSignals
+
+// IconViewMaxThumbLoads is the maximum number of thumbnails that are
+// loaded concurrently by an IconView
+var IconViewMaxThumbLoads = 4
+
+// IconView presents a list of items as a wrapping grid of tiles, each with
+// an icon or thumbnail image and a label, e.g., for files in a directory or
+// assets in a browser.  It supports selection with the mouse (including
+// rubber-band selection on empty space), keyboard navigation in both
+// dimensions, zooming of the tile size (Ctrl+scroll wheel or zoom keys),
+// and lazy loading of thumbnails via ThumbFunc, only for visible tiles.
+// The Selection is reflected in the Selected flag of each tile, and changes
+// are sent on WidgetSig WidgetSelected and WidgetSelectionChanged signals.
+type IconView struct {
+	gi.Frame
+	Items       []*IconViewItem   `desc:"items to display -- call Config after changing"`
+	TileSize    float32           `def:"96" min:"16" max:"512" desc:"size of the icon or thumbnail area of each tile, in raw dots (pixels) -- changed by zooming"`
+	ThumbFunc   IconViewThumbFunc `view:"-" json:"-" xml:"-" desc:"optional function for loading thumbnails -- called lazily for visible items without a Thumb"`
+	CurIdx      int               `copy:"-" json:"-" xml:"-" desc:"index of the current item for keyboard navigation"`
+	Selection   gi.SelectionModel `copy:"-" desc:"selected item indexes, with the selection policy Mode -- rubber-band selection is only available if it is not SelectionSingle"`
+	SelectMode  bool              `copy:"-" json:"-" xml:"-" desc:"keyboard select mode: navigation extends the selection"`
+	IconViewSig ki.Signal         `copy:"-" json:"-" xml:"-" view:"-" desc:"icon view specific signals: double-click, zoom"`
+	thumbSem    chan struct{}
+	thumbMu     sync.Mutex
+}
+
+var KiT_IconView = kit.Types.AddType(&IconView{}, IconViewProps)
+
+// AddNewIconView adds a new iconview to given parent node, with given name.
+func AddNewIconView(parent ki.Ki, name string) *IconView {
+	return parent.AddNewChild(KiT_IconView, name).(*IconView)
+}
+
+func (iv *IconView) Disconnect() {
+	iv.Frame.Disconnect()
+	iv.IconViewSig.DisconnectAll()
+}
+
+var IconViewProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_NodeFlags,
+	"background-color": &gi.Prefs.Colors.Background,
+	"max-width":        -1,
+	"max-height":       -1,
+	"spacing":          gi.StdDialogVSpaceUnits,
+	".tile": ki.Props{
+		"padding": units.NewPx(4),
+		"margin":  units.NewPx(2),
+	},
+	"#label": ki.Props{
+		"text-align":  gist.AlignCenter,
+		"white-space": gist.WhiteSpaceNormal,
+	},
+}
+
+// SetItems sets the items to display, and configures the view
+func (iv *IconView) SetItems(items []*IconViewItem) {
+	iv.Items = items
+	iv.CurIdx = -1
+	iv.Selection.Reset()
+	iv.Config()
+}
+
+// Config configures the tiles for the current Items
+func (iv *IconView) Config() {
+	iv.Lay = gi.LayoutHorizFlow
+	if iv.Selection.Selected == nil {
+		iv.Selection.Reset()
+	}
+	iv.RubberBandSel = iv.Selection.Mode != gi.SelectionSingle
+	iv.SetCanFocus()
+	iv.WidgetSig.Connect(iv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(gi.WidgetSelected) && data == nil { // from rubber band
+			recv.Embed(KiT_IconView).(*IconView).SyncSelection()
+		}
+	})
+	if iv.TileSize <= 0 {
+		iv.TileSize = 96
+	}
+	config := kit.TypeAndNameList{}
+	for i := range iv.Items {
+		config.Add(gi.KiT_Frame, fmt.Sprintf("tile-%d", i))
+	}
+	mods, updt := iv.ConfigChildren(config)
+	if !mods {
+		updt = iv.UpdateStart()
+	}
+	for i, it := range iv.Items {
+		iv.ConfigTile(i, it)
+	}
+	iv.SetFullReRender()
+	iv.UpdateEnd(updt)
+}
+
+// Tile returns the tile frame for given item index, or nil if out of range
+func (iv *IconView) Tile(idx int) *gi.Frame {
+	if idx < 0 || idx >= len(iv.Kids) {
+		return nil
+	}
+	return iv.Kids[idx].(*gi.Frame)
+}
+
+// ConfigTile configures the tile for given item
+func (iv *IconView) ConfigTile(idx int, it *IconViewItem) {
+	tl := iv.Tile(idx)
+	if tl == nil {
+		return
+	}
+	tl.Lay = gi.LayoutVert
+	tl.SetProp("width", units.NewDot(iv.TileSize+8))
+	tl.SetProp("max-width", units.NewDot(iv.TileSize+8))
+	tl.SetProp("horizontal-align", gist.AlignCenter)
+	tl.Tooltip = it.Label
+	tl.Class = "tile"
+	config := kit.TypeAndNameList{}
+	if it.Thumb != nil {
+		config.Add(gi.KiT_Bitmap, "thumb")
+	} else {
+		config.Add(gi.KiT_Icon, "icon")
+	}
+	config.Add(gi.KiT_Label, "label")
+	tl.ConfigChildren(config)
+	if it.Thumb != nil {
+		bm := tl.Child(0).(*gi.Bitmap)
+		sz := it.Thumb.Bounds().Size()
+		sc := iv.TileSize / mat32.Max(float32(sz.X), float32(sz.Y))
+		bm.SetImage(it.Thumb, float32(sz.X)*sc, float32(sz.Y)*sc)
+		bm.LayoutToImgSize()
+		bm.SetProp("horizontal-align", gist.AlignCenter)
+	} else {
+		ic := tl.Child(0).(*gi.Icon)
+		icnm := string(it.Icon)
+		if it.Icon.IsNil() {
+			icnm = "blank"
+		}
+		ic.SetIcon(icnm)
+		ic.SetProp("width", units.NewDot(iv.TileSize))
+		ic.SetProp("height", units.NewDot(iv.TileSize))
+		ic.SetProp("horizontal-align", gist.AlignCenter)
+	}
+	lb := tl.Child(1).(*gi.Label)
+	lb.SetText(it.Label)
+	lb.SetProp("max-width", units.NewDot(iv.TileSize+8))
+	tl.SetSelectedState(iv.Selection.IsSelected(idx))
+	iv.UpdateTileStyle(idx)
+}
+
+// UpdateTileStyle updates the style properties of the tile for given index
+// to reflect its selection state
+func (iv *IconView) UpdateTileStyle(idx int) {
+	tl := iv.Tile(idx)
+	if tl == nil {
+		return
+	}
+	if tl.IsSelected() {
+		tl.SetProp("background-color", &gi.Prefs.Colors.Select)
+	} else {
+		tl.DeleteProp("background-color")
+	}
+	if idx == iv.CurIdx && iv.HasFocus() {
+		tl.SetProp("border-width", units.NewPx(1))
+		tl.SetProp("border-color", &gi.Prefs.Colors.Border)
+	} else {
+		tl.DeleteProp("border-width")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Selection
+
+// IdxIsSelected returns true if the item at given index is selected
+func (iv *IconView) IdxIsSelected(idx int) bool {
+	return iv.Selection.IsSelected(idx)
+}
+
+// SelectedIdxsList returns the indexes of the selected items, in order
+func (iv *IconView) SelectedIdxsList() []int {
+	return iv.Selection.List(false)
+}
+
+// SelectedItems returns the selected items, in order
+func (iv *IconView) SelectedItems() []*IconViewItem {
+	var sl []*IconViewItem
+	for _, i := range iv.SelectedIdxsList() {
+		if i < len(iv.Items) {
+			sl = append(sl, iv.Items[i])
+		}
+	}
+	return sl
+}
+
+// SelectIdxState sets the selection state of given index
+func (iv *IconView) SelectIdxState(idx int, sel bool) {
+	if sel {
+		iv.Selection.Add(idx)
+	} else {
+		iv.Selection.Remove(idx)
+	}
+	if tl := iv.Tile(idx); tl != nil {
+		tl.SetSelectedState(sel)
+	}
+}
+
+// UnselectAllIdxs unselects all items
+func (iv *IconView) UnselectAllIdxs() {
+	for _, i := range iv.Selection.List(false) {
+		iv.SelectIdxState(i, false)
+	}
+}
+
+// SelectAllIdxs selects all items (unless the Selection Mode is
+// SelectionSingle)
+func (iv *IconView) SelectAllIdxs() {
+	updt := iv.UpdateStart()
+	iv.Selection.SelectAll(len(iv.Kids))
+	for _, i := range iv.Selection.List(false) {
+		iv.SelectIdxState(i, true)
+	}
+	iv.SetFullReRender()
+	iv.UpdateEnd(updt)
+	iv.WidgetSig.Emit(iv.This(), int64(gi.WidgetSelected), iv.CurIdx)
+	iv.EmitSelectionChangedSignal(&iv.Selection)
+}
+
+// SyncSelection updates the Selection from the Selected flags of the tiles,
+// after they were set by rubber-band selection, and emits a
+// WidgetSelectionChanged signal
+func (iv *IconView) SyncSelection() {
+	for i, kid := range iv.Kids {
+		if _, ni := gi.KiToNode2D(kid); ni != nil {
+			if ni.IsSelected() {
+				iv.Selection.Add(i)
+			} else {
+				iv.Selection.Remove(i)
+			}
+		}
+	}
+	iv.EmitSelectionChangedSignal(&iv.Selection)
+}
+
+// SelectIdxAction updates the selection for an action on given index
+// (e.g., a mouse click or keyboard navigation), according to the mode and
+// the Selection Mode, makes it the current item, and emits WidgetSelected
+// and WidgetSelectionChanged signals, except for the Quiet modes.
+func (iv *IconView) SelectIdxAction(idx int, mode mouse.SelectModes) {
+	if idx < 0 || idx >= len(iv.Kids) || mode == mouse.NoSelect {
+		return
+	}
+	updt := iv.UpdateStart()
+	iv.Selection.SelectAction(idx, mode)
+	sc := iv.Selection.PendingChange()
+	for _, i := range sc.Removed {
+		iv.SelectIdxState(i, false)
+	}
+	for _, i := range sc.Added {
+		iv.SelectIdxState(i, true)
+	}
+	iv.CurIdx = idx
+	iv.ScrollToIdx(idx)
+	iv.SetFullReRender()
+	iv.UpdateEnd(updt)
+	if mode != mouse.SelectQuiet && mode != mouse.UnselectQuiet {
+		iv.WidgetSig.Emit(iv.This(), int64(gi.WidgetSelected), idx)
+		iv.EmitSelectionChangedSignal(&iv.Selection)
+	}
+}
+
+// IdxFromPos returns the index of the tile at given window position,
+// and false if none
+func (iv *IconView) IdxFromPos(pos image.Point) (int, bool) {
+	for i, kid := range iv.Kids {
+		if kid.(*gi.Frame).PosInWinBBox(pos) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// ScrollToIdx ensures that the tile at given index is visible
+func (iv *IconView) ScrollToIdx(idx int) bool {
+	tl := iv.Tile(idx)
+	if tl == nil {
+		return false
+	}
+	return iv.ScrollToItem(tl)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Navigation
+
+// NCols returns the number of columns of tiles in the current layout,
+// i.e., the number of tiles in the first row
+func (iv *IconView) NCols() int {
+	if len(iv.Kids) == 0 {
+		return 1
+	}
+	y0 := iv.Tile(0).LayState.Alloc.Pos.Y
+	nc := 0
+	for i := range iv.Kids {
+		if iv.Tile(i).LayState.Alloc.Pos.Y != y0 {
+			break
+		}
+		nc++
+	}
+	if nc < 1 {
+		nc = 1
+	}
+	return nc
+}
+
+// MoveBy moves the current item by given delta, clamped to the valid
+// range, with given selection mode
+func (iv *IconView) MoveBy(delta int, mode mouse.SelectModes) {
+	n := len(iv.Kids)
+	if n == 0 {
+		return
+	}
+	idx := iv.CurIdx + delta
+	if iv.CurIdx < 0 {
+		idx = 0
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= n {
+		idx = n - 1
+	}
+	iv.SelectIdxAction(idx, mode)
+}
+
+// KeyInput handles keyboard navigation and selection
+func (iv *IconView) KeyInput(kt *key.ChordEvent) {
+	if gi.KeyEventTrace {
+		fmt.Printf("IconView KeyInput: %v\n", iv.Path())
+	}
+	kf := gi.KeyFun(kt.Chord())
+	selMode := mouse.SelectModeBits(kt.Modifiers)
+	if selMode == mouse.SelectOne {
+		if iv.SelectMode {
+			selMode = mouse.ExtendContinuous
+		}
+	}
+	nc := iv.NCols()
+	switch kf {
+	case gi.KeyFunMoveRight:
+		kt.SetProcessed()
+		iv.MoveBy(1, selMode)
+	case gi.KeyFunMoveLeft:
+		kt.SetProcessed()
+		iv.MoveBy(-1, selMode)
+	case gi.KeyFunMoveDown:
+		kt.SetProcessed()
+		iv.MoveBy(nc, selMode)
+	case gi.KeyFunMoveUp:
+		kt.SetProcessed()
+		iv.MoveBy(-nc, selMode)
+	case gi.KeyFunPageDown:
+		kt.SetProcessed()
+		iv.MoveBy(nc*iv.PageRows(), selMode)
+	case gi.KeyFunPageUp:
+		kt.SetProcessed()
+		iv.MoveBy(-nc*iv.PageRows(), selMode)
+	case gi.KeyFunHome, gi.KeyFunDocHome:
+		kt.SetProcessed()
+		iv.MoveBy(-len(iv.Kids), selMode)
+	case gi.KeyFunEnd, gi.KeyFunDocEnd:
+		kt.SetProcessed()
+		iv.MoveBy(len(iv.Kids), selMode)
+	case gi.KeyFunSelectMode:
+		kt.SetProcessed()
+		iv.SelectMode = !iv.SelectMode
+	case gi.KeyFunCancelSelect:
+		kt.SetProcessed()
+		iv.SelectMode = false
+		updt := iv.UpdateStart()
+		iv.UnselectAllIdxs()
+		iv.SetFullReRender()
+		iv.UpdateEnd(updt)
+		iv.WidgetSig.Emit(iv.This(), int64(gi.WidgetSelected), -1)
+		iv.EmitSelectionChangedSignal(&iv.Selection)
+	case gi.KeyFunSelectAll:
+		kt.SetProcessed()
+		iv.SelectAllIdxs()
+	case gi.KeyFunEnter, gi.KeyFunAccept:
+		if iv.CurIdx >= 0 {
+			kt.SetProcessed()
+			iv.IconViewSig.Emit(iv.This(), int64(IconViewDoubleClicked), iv.CurIdx)
+		}
+	case gi.KeyFunZoomIn:
+		kt.SetProcessed()
+		iv.Zoom(1.25)
+	case gi.KeyFunZoomOut:
+		kt.SetProcessed()
+		iv.Zoom(0.8)
+	}
+}
+
+// PageRows returns the number of rows of tiles visible in the view
+func (iv *IconView) PageRows() int {
+	tl := iv.Tile(0)
+	if tl == nil {
+		return 1
+	}
+	th := tl.LayState.Alloc.Size.Y
+	if th <= 0 {
+		return 1
+	}
+	nr := int(float32(iv.VpBBox.Dy()) / th)
+	if nr < 1 {
+		nr = 1
+	}
+	return nr
+}
+
+// Zoom multiplies the tile size by given factor, within the range
+// of 16 to 512 dots, and reconfigures the view
+func (iv *IconView) Zoom(factor float32) {
+	ts := mat32.Clamp(iv.TileSize*factor, 16, 512)
+	if ts == iv.TileSize {
+		return
+	}
+	iv.TileSize = ts
+	iv.Config()
+	if iv.CurIdx >= 0 {
+		iv.ScrollToIdx(iv.CurIdx)
+	}
+	iv.IconViewSig.Emit(iv.This(), int64(IconViewZoomed), iv.TileSize)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Thumbnails
+
+// LoadVisibleThumbs requests thumbnails for all visible tiles that do not
+// yet have one, using ThumbFunc in separate goroutines, with at most
+// IconViewMaxThumbLoads running at a time.
+func (iv *IconView) LoadVisibleThumbs() {
+	if iv.ThumbFunc == nil {
+		return
+	}
+	iv.thumbMu.Lock()
+	defer iv.thumbMu.Unlock()
+	if iv.thumbSem == nil {
+		iv.thumbSem = make(chan struct{}, IconViewMaxThumbLoads)
+	}
+	for i, it := range iv.Items {
+		if it.Thumb != nil || it.thumbReq {
+			continue
+		}
+		tl := iv.Tile(i)
+		if tl == nil || tl.VpBBox.Empty() {
+			continue
+		}
+		it.thumbReq = true
+		go iv.loadThumb(i, it)
+	}
+}
+
+// loadThumb loads the thumbnail for given item, in its own goroutine, and
+// then sets it and updates its tile on the event loop of the window, at the
+// next frame, as the tile can't be configured while the view is rendered
+func (iv *IconView) loadThumb(idx int, it *IconViewItem) {
+	iv.thumbSem <- struct{}{}
+	img := iv.ThumbFunc(it)
+	<-iv.thumbSem
+	if img == nil || iv.IsDeleted() || iv.IsDestroyed() {
+		return
+	}
+	win := iv.ParentWindow()
+	if win == nil || win.IsClosed() {
+		return
+	}
+	win.RunOnNextFrame(func() {
+		if iv.IsDeleted() || iv.IsDestroyed() {
+			return
+		}
+		iv.thumbMu.Lock()
+		if idx >= len(iv.Items) || iv.Items[idx] != it {
+			iv.thumbMu.Unlock()
+			return // items have changed
+		}
+		it.Thumb = img
+		iv.thumbMu.Unlock()
+		updt := iv.UpdateStart()
+		iv.ConfigTile(idx, it)
+		iv.SetFullReRender()
+		iv.UpdateEnd(updt)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Node2D interface
+
+func (iv *IconView) Style2D() {
+	for i := range iv.Kids {
+		iv.UpdateTileStyle(i)
+	}
+	iv.Frame.Style2D()
+}
+
+func (iv *IconView) Render2D() {
+	if iv.FullReRenderIfNeeded() {
+		return
+	}
+	if iv.PushBounds() {
+		iv.FrameStdRender()
+		iv.This().(gi.Node2D).ConnectEvents2D()
+		iv.RenderScrolls()
+		iv.Render2DChildren()
+		iv.PopBounds()
+		iv.LoadVisibleThumbs()
+	} else {
+		iv.DisconnectAllEvents(gi.AllPris)
+	}
+}
+
+func (iv *IconView) HasFocus2D() bool {
+	if iv.IsInactive() {
+		return false
+	}
+	return iv.ContainsFocus()
+}
+
+func (iv *IconView) FocusChanged2D(change gi.FocusChanges) {
+	switch change {
+	case gi.FocusLost, gi.FocusGot:
+		iv.SetFullReRender()
+		iv.UpdateSig()
+	}
+}
+
+func (iv *IconView) ConnectEvents2D() {
+	iv.Frame.ConnectEvents2D()
+	iv.IconViewEvents()
+}
+
+// IconViewEvents connects the mouse and keyboard events for the view
+func (iv *IconView) IconViewEvents() {
+	iv.ConnectEvent(oswin.MouseEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.Event)
+		ivv := recv.Embed(KiT_IconView).(*IconView)
+		if me.Button != mouse.Left {
+			return
+		}
+		idx, ok := ivv.IdxFromPos(me.Pos())
+		if !ok {
+			return
+		}
+		switch me.Action {
+		case mouse.Press:
+			me.SetProcessed()
+			ivv.GrabFocus()
+			ivv.SelectIdxAction(idx, me.SelectMode())
+		case mouse.DoubleClick:
+			me.SetProcessed()
+			ivv.IconViewSig.Emit(ivv.This(), int64(IconViewDoubleClicked), idx)
+		}
+	})
+	iv.ConnectEvent(oswin.MouseScrollEvent, gi.HiPri, func(recv, send ki.Ki, sig int64, d any) {
+		me := d.(*mouse.ScrollEvent)
+		ivv := recv.Embed(KiT_IconView).(*IconView)
+		if !key.HasAnyModifierBits(me.Modifiers, key.Control, key.Meta) {
+			return // regular scrolling
+		}
+		me.SetProcessed()
+		del := me.NonZeroDelta(false)
+		if del < 0 {
+			ivv.Zoom(1.1)
+		} else if del > 0 {
+			ivv.Zoom(1 / 1.1)
+		}
+	})
+	iv.ConnectEvent(oswin.KeyChordEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d any) {
+		ivv := recv.Embed(KiT_IconView).(*IconView)
+		kt := d.(*key.ChordEvent)
+		ivv.KeyInput(kt)
+	})
+}
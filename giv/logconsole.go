@@ -0,0 +1,231 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bytes"
+	"log"
+	"os"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/mimedata"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// LogConsole shows the gi.LogRecords -- the output of gi.Logf and the
+// standard logger, once gi.CaptureStdLog has been called -- filtered by
+// level, source and text, and updated as new records come in.  In Follow
+// mode, it keeps showing the latest records.  It can be embedded in an app,
+// or opened in its own window with LogConsoleView, or the LogConsole
+// key function (Shift+Control+L by default).
+type LogConsole struct {
+	gi.Layout
+	MinLevel gi.LogLevels `desc:"minimum level of records to show"`
+	Source   string       `desc:"only show records whose source contains this string -- empty for all"`
+	Filter   string       `desc:"only show records whose message contains this text -- empty for all"`
+	Follow   bool         `desc:"keep showing the latest records as they come in"`
+	Buf      *TextBuf     `json:"-" xml:"-" desc:"buffer with the text of the shown records"`
+}
+
+var KiT_LogConsole = kit.Types.AddType(&LogConsole{}, LogConsoleProps)
+
+// AddNewLogConsole adds a new log console to given parent node, with given
+// name -- call Config to configure it.
+func AddNewLogConsole(parent ki.Ki, name string) *LogConsole {
+	return parent.AddNewChild(KiT_LogConsole, name).(*LogConsole)
+}
+
+// LogConsoleProps are style properties for LogConsole
+var LogConsoleProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"max-width":     -1,
+	"max-height":    -1,
+}
+
+// Config configures the console and starts listening for new records
+func (lc *LogConsole) Config() {
+	lc.Lay = gi.LayoutVert
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(gi.KiT_Layout, "text-lay")
+	mods, updt := lc.ConfigChildren(config)
+	if mods {
+		lc.Follow = true
+		lc.MinLevel = gi.LogInfo
+		lc.Buf = &TextBuf{}
+		lc.Buf.InitName(lc.Buf, "log-console-buf")
+		tl := lc.TextLay()
+		tl.SetStretchMax()
+		tv := AddNewTextView(tl, "text-view")
+		tv.SetInactive()
+		tv.SetProp("font-family", gi.Prefs.MonoFont)
+		tv.SetBuf(lc.Buf)
+		lc.ConfigToolBar()
+		gi.LogRecords.AddListener(lc.This(), func() {
+			if win := lc.ParentWindow(); win != nil {
+				win.RunOnNextFrameOnce(lc.This(), lc.UpdateRecords)
+			}
+		})
+	} else {
+		updt = lc.UpdateStart()
+	}
+	lc.UpdateRecords()
+	lc.UpdateEnd(updt)
+}
+
+func (lc *LogConsole) Destroy() {
+	gi.LogRecords.RemoveListener(lc.This())
+	lc.Layout.Destroy()
+}
+
+// ToolBar returns the toolbar
+func (lc *LogConsole) ToolBar() *gi.ToolBar {
+	return lc.ChildByName("toolbar", 0).(*gi.ToolBar)
+}
+
+// TextLay returns the layout for the text view
+func (lc *LogConsole) TextLay() *gi.Layout {
+	return lc.ChildByName("text-lay", 1).(*gi.Layout)
+}
+
+// TextView returns the text view
+func (lc *LogConsole) TextView() *TextView {
+	return lc.TextLay().ChildByName("text-view", 0).(*TextView)
+}
+
+// RecordsText returns the text of the records that pass the filters, one
+// per line
+func (lc *LogConsole) RecordsText() []byte {
+	var b bytes.Buffer
+	for _, rec := range gi.LogRecords.Filter(lc.MinLevel, lc.Source, lc.Filter) {
+		b.WriteString(rec.String())
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// UpdateRecords updates the view with the current records that pass the
+// filters, showing the latest in Follow mode
+func (lc *LogConsole) UpdateRecords() {
+	if lc.Buf == nil || lc.IsDestroyed() {
+		return
+	}
+	lc.Buf.SetText(lc.RecordsText())
+	if lc.Follow {
+		lc.Buf.AutoScrollViews()
+	}
+}
+
+// Copy copies the text of the shown records to the clipboard
+func (lc *LogConsole) Copy() {
+	win := lc.ParentWindow()
+	if win == nil {
+		return
+	}
+	oswin.TheApp.ClipBoard(win.OSWin).Write(mimedata.NewTextBytes(lc.RecordsText()))
+}
+
+// Save saves the text of the shown records to given file
+func (lc *LogConsole) Save(filename gi.FileName) error {
+	err := os.WriteFile(string(filename), lc.RecordsText(), 0644)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+// ConfigToolBar configures the filter fields and actions in the toolbar
+func (lc *LogConsole) ConfigToolBar() {
+	tb := lc.ToolBar()
+	tb.SetStretchMaxWidth()
+	gi.AddNewLabel(tb, "level-lbl", "Level:")
+	lvl := gi.AddNewComboBox(tb, "level")
+	lvl.ItemsFromEnum(gi.KiT_LogLevels, false, 0)
+	lvl.SetCurVal(lc.MinLevel)
+	lvl.Tooltip = "minimum level of records to show"
+	lvl.ComboSig.Connect(lc.This(), func(recv, send ki.Ki, sig int64, data any) {
+		lc.MinLevel = gi.LogLevels(sig)
+		lc.UpdateRecords()
+	})
+	gi.AddNewLabel(tb, "source-lbl", "Source:")
+	src := gi.AddNewTextField(tb, "source")
+	src.SetProp("width", units.NewCh(16))
+	src.Placeholder = "all"
+	src.Tooltip = "only show records whose source (e.g., gi.Window) contains this"
+	src.TextFieldSig.Connect(lc.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(gi.TextFieldDone) || sig == int64(gi.TextFieldDeFocused) {
+			lc.Source = src.Text()
+			lc.UpdateRecords()
+		}
+	})
+	gi.AddNewLabel(tb, "filter-lbl", "Filter:")
+	flt := gi.AddNewTextField(tb, "filter")
+	flt.SetProp("width", units.NewCh(24))
+	flt.Tooltip = "only show records whose message contains this text"
+	flt.TextFieldSig.Connect(lc.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(gi.TextFieldDone) || sig == int64(gi.TextFieldDeFocused) {
+			lc.Filter = flt.Text()
+			lc.UpdateRecords()
+		}
+	})
+	fol := gi.AddNewCheckBox(tb, "follow")
+	fol.SetText("Follow")
+	fol.Tooltip = "keep showing the latest records as they come in"
+	fol.SetChecked(lc.Follow)
+	fol.ButtonSig.Connect(lc.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(gi.ButtonToggled) {
+			lc.Follow = fol.IsChecked()
+			lc.UpdateRecords()
+		}
+	})
+	tb.AddSeparator("act-sep")
+	tb.AddAction(gi.ActOpts{Label: "Copy", Icon: "copy", Tooltip: "copy the shown records to the clipboard"}, lc.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			lc.Copy()
+		})
+	tb.AddAction(gi.ActOpts{Label: "Save...", Icon: "file-save", Tooltip: "save the shown records to a file"}, lc.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			FileViewDialog(lc.ViewportSafe(), "", ".log", DlgOpts{Title: "Save Log"}, nil,
+				lc.This(), func(recv, send ki.Ki, sig int64, data any) {
+					if sig == int64(gi.DialogAccepted) {
+						dlg, _ := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
+						lc.Save(gi.FileName(FileViewDialogValue(dlg)))
+					}
+				})
+		})
+	tb.AddAction(gi.ActOpts{Label: "Clear", Icon: "delete", Tooltip: "remove all the records"}, lc.This(),
+		func(recv, send ki.Ki, sig int64, data any) {
+			gi.LogRecords.Clear()
+		})
+}
+
+// LogConsoleView opens a window with a LogConsole, calling gi.CaptureStdLog
+// so that the output of the standard logger is shown, in case it was not
+// already called at startup, by gimain.Main
+func LogConsoleView() *gi.Window {
+	gi.CaptureStdLog()
+	winm := "gogi-log-console"
+	width := 1024
+	height := 600
+	win, recyc := gi.RecycleMainWindow(&gi.LogRecords, winm, "GoGi Log Console", width, height)
+	if recyc {
+		return win
+	}
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+	lc := AddNewLogConsole(mfr, "log-console")
+	lc.Viewport = vp
+	lc.Config()
+
+	vp.UpdateEndNoSig(updt)
+	win.GoStartEventLoop()
+	return win
+}
@@ -0,0 +1,510 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+// MarkdownView shows a Markdown document as formatted, word-wrapped text.
+// It supports the commonly used subset of Markdown: headings (# and
+// underlined), paragraphs, bulleted and numbered lists, block quotes,
+// fenced code blocks and rules, with emphasis, code and links within the
+// text.  Each heading has an anchor (see gi.HelpAnchor), which links of
+// the form #anchor scroll to, as does ScrollToAnchor.  Other links are
+// emitted on the LinkSig, or opened in the system browser if nobody is
+// receiving the signal.  It is a gi.Outliner of its headings.
+type MarkdownView struct {
+	gi.Frame
+	Src        string            `desc:"the Markdown source of the document"`
+	Headings   []MarkdownHeading `json:"-" xml:"-" desc:"the headings of the document, in order"`
+	LinkSig    ki.Signal         `json:"-" xml:"-" view:"-" desc:"signal for clicking on a link other than to an anchor in the document -- data is a string of the URL"`
+	OutlineSig ki.Signal         `json:"-" xml:"-" view:"-" desc:"signal for gi.OutlinePanels showing the headings -- see gi.OutlineSignals for the types"`
+	anchor     string            // anchor to scroll to after the next layout
+	scroll     bool              // scroll to anchor after the next layout
+	outPos     int               // last OutlinePos, for OutlineMoved
+	hiHeading  *gi.Label         // heading highlighted by OutlineGoTo
+}
+
+var KiT_MarkdownView = kit.Types.AddType(&MarkdownView{}, MarkdownViewProps)
+
+// AddNewMarkdownView adds a new markdown view to given parent node, with
+// given name.
+func AddNewMarkdownView(parent ki.Ki, name string) *MarkdownView {
+	return parent.AddNewChild(KiT_MarkdownView, name).(*MarkdownView)
+}
+
+func (mv *MarkdownView) Disconnect() {
+	mv.Frame.Disconnect()
+	mv.LinkSig.DisconnectAll()
+	mv.OutlineSig.DisconnectAll()
+}
+
+// MarkdownViewProps are style properties for MarkdownView
+var MarkdownViewProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_NodeFlags,
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+	"padding":          units.NewEm(.5),
+	"spacing":          units.NewEm(.25),
+	"max-width":        -1,
+	"max-height":       -1,
+	"overflow":         gist.OverflowAuto,
+}
+
+// MarkdownHeading is a heading of a Markdown document
+type MarkdownHeading struct {
+	Level  int    `desc:"level of the heading, from 1 for # to 6 for ######"`
+	Text   string `desc:"text of the heading"`
+	Anchor string `desc:"anchor of the heading, for links to it, unique within the document"`
+}
+
+// SetMarkdown sets the Markdown source of the document and shows it
+func (mv *MarkdownView) SetMarkdown(src string) {
+	updt := mv.UpdateStart()
+	mv.Lay = gi.LayoutVert
+	mv.Src = src
+	mv.SetFullReRender()
+	mv.DeleteChildren(ki.DestroyKids)
+	mv.anchor, mv.scroll = "", false
+	mv.hiHeading = nil
+	bks := parseMarkdown(src)
+	mv.Headings = markdownHeadings(bks)
+	hi := 0
+	for i, bk := range bks {
+		nm := fmt.Sprintf("blk-%d", i)
+		switch bk.kind {
+		case mdHeading:
+			lb := mv.addText("h-"+mv.Headings[hi].Anchor, MarkdownInlineHTML(bk.text))
+			hi++
+			lb.SetProp("font-weight", gist.WeightBold)
+			lb.SetProp("font-size", mdHeadingSizes[ints.MinInt(bk.level, len(mdHeadingSizes))-1])
+		case mdPara:
+			mv.addText(nm, MarkdownInlineHTML(bk.text))
+		case mdList:
+			lb := mv.addText(nm, html.EscapeString(bk.marker)+" "+MarkdownInlineHTML(bk.text))
+			lb.SetProp("padding-left", units.NewEm(float32(bk.level+1)))
+		case mdQuote:
+			lb := mv.addText(nm, MarkdownInlineHTML(bk.text))
+			lb.SetProp("font-style", gist.FontItalic)
+			lb.SetProp("padding-left", units.NewEm(2))
+		case mdCode:
+			lb := gi.AddNewLabel(mv, nm, html.EscapeString(bk.text))
+			lb.SetProp("font-family", gi.Prefs.MonoFont)
+			lb.SetProp("background-color", &gi.Prefs.Colors.Highlight)
+			lb.SetProp("padding", units.NewEm(.5))
+			lb.SetStretchMaxWidth()
+		case mdRule:
+			gi.AddNewSeparator(mv, nm, true)
+		}
+	}
+	mv.UpdateEnd(updt)
+	mv.OutlineSig.Emit(mv.This(), int64(gi.OutlineChanged), nil)
+}
+
+// mdHeadingSizes are the font sizes of the heading levels
+var mdHeadingSizes = []string{"xx-large", "x-large", "large", "medium"}
+
+// addText adds a word-wrapped label with given html text
+func (mv *MarkdownView) addText(nm, txt string) *gi.Label {
+	lb := gi.AddNewLabel(mv, nm, txt)
+	lb.SetProp("white-space", gist.WhiteSpaceNormal)
+	lb.SetProp("width", units.NewCh(30))
+	lb.SetProp("max-width", -1)
+	lb.LinkSig.Connect(mv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		recv.Embed(KiT_MarkdownView).(*MarkdownView).OpenLink(data.(string))
+	})
+	return lb
+}
+
+// OpenLink opens given link: scrolls to the anchor for links of the form
+// #anchor, and otherwise emits it on the LinkSig, or opens it in the
+// system browser if nobody is receiving the signal.
+func (mv *MarkdownView) OpenLink(url string) {
+	if strings.HasPrefix(url, "#") {
+		mv.ScrollToAnchor(url[1:])
+		return
+	}
+	if len(mv.LinkSig.Cons) == 0 {
+		gi.OpenURL(url)
+		return
+	}
+	mv.LinkSig.Emit(mv.This(), 0, url)
+}
+
+// HeadingLabel returns the label of the heading with given anchor, or nil
+// if there is none
+func (mv *MarkdownView) HeadingLabel(anchor string) *gi.Label {
+	if lbi := mv.ChildByName("h-"+anchor, 0); lbi != nil {
+		return lbi.(*gi.Label)
+	}
+	return nil
+}
+
+// ScrollToAnchor scrolls to put the heading with given anchor at the top
+// of the view, or to the top of the document if the anchor is empty --
+// returns false if there is no such heading.  If the document has not
+// been laid out yet, this happens after the next layout.
+func (mv *MarkdownView) ScrollToAnchor(anchor string) bool {
+	if anchor != "" && mv.HeadingLabel(anchor) == nil {
+		return false
+	}
+	mv.anchor, mv.scroll = anchor, true
+	if mv.VpBBox != (image.Rectangle{}) && mv.scrollToAnchor() {
+		mv.UpdateSig()
+	}
+	return true
+}
+
+// scrollToAnchor scrolls to the pending anchor, if it has been laid out
+func (mv *MarkdownView) scrollToAnchor() bool {
+	if mv.anchor == "" {
+		mv.ScrollToPos(mat32.Y, 0)
+		mv.scroll = false
+		return true
+	}
+	lb := mv.HeadingLabel(mv.anchor)
+	if lb == nil || lb.ObjBBox == (image.Rectangle{}) {
+		return false
+	}
+	mv.ScrollDimToStart(mat32.Y, lb.ObjBBox.Min.Y)
+	mv.scroll = false
+	return true
+}
+
+func (mv *MarkdownView) Layout2D(parBBox image.Rectangle, iter int) bool {
+	redo := mv.Frame.Layout2D(parBBox, iter)
+	if mv.scroll && !redo {
+		mv.scrollToAnchor()
+	}
+	return redo
+}
+
+func (mv *MarkdownView) Move2D(delta image.Point, parBBox image.Rectangle) {
+	mv.Frame.Move2D(delta, parBBox)
+	if pos := mv.OutlinePos(); pos != mv.outPos {
+		mv.outPos = pos
+		mv.OutlineSig.Emit(mv.This(), int64(gi.OutlineMoved), nil)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//   gi.Outliner
+
+// OutlineSections returns the headings as sections of the document, nested
+// within the previous headings of lower levels, in units of heading index
+func (mv *MarkdownView) OutlineSections() []gi.OutlineSection {
+	secs := make([]gi.OutlineSection, len(mv.Headings))
+	var encl []int // levels of enclosing headings
+	for i, hd := range mv.Headings {
+		for len(encl) > 0 && encl[len(encl)-1] >= hd.Level {
+			encl = encl[:len(encl)-1]
+		}
+		ed := i + 1
+		for ed < len(mv.Headings) && mv.Headings[ed].Level > hd.Level {
+			ed++
+		}
+		secs[i] = gi.OutlineSection{Title: hd.Text, Level: len(encl), Start: i, End: ed}
+		encl = append(encl, hd.Level)
+	}
+	return secs
+}
+
+// OutlinePos returns the index of the last heading at or above the top of
+// the view, or -1 if there is none
+func (mv *MarkdownView) OutlinePos() int {
+	top := mv.VpBBox.Min.Y + int(mv.Sty.Font.Size.Dots)
+	pos := -1
+	for i, hd := range mv.Headings {
+		lb := mv.HeadingLabel(hd.Anchor)
+		if lb == nil || lb.ObjBBox.Min.Y > top {
+			break
+		}
+		pos = i
+	}
+	return pos
+}
+
+// OutlineGoTo scrolls to the heading at given index and highlights it, by
+// selecting it until another one is gone to
+func (mv *MarkdownView) OutlineGoTo(idx int) {
+	if idx < 0 || idx >= len(mv.Headings) {
+		return
+	}
+	if mv.hiHeading != nil {
+		mv.hiHeading.SetSelectedState(false)
+		mv.hiHeading.UpdateSig()
+	}
+	mv.hiHeading = mv.HeadingLabel(mv.Headings[idx].Anchor)
+	if mv.hiHeading != nil {
+		mv.hiHeading.SetSelectedState(true)
+		mv.hiHeading.UpdateSig()
+	}
+	mv.ScrollToAnchor(mv.Headings[idx].Anchor)
+}
+
+// OutlineSignal returns the OutlineSig
+func (mv *MarkdownView) OutlineSignal() *ki.Signal {
+	return &mv.OutlineSig
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//   Markdown parsing
+
+// MarkdownHeadings returns the headings of given Markdown source, with
+// the same anchors as in a MarkdownView
+func MarkdownHeadings(src string) []MarkdownHeading {
+	return markdownHeadings(parseMarkdown(src))
+}
+
+// markdownHeadings returns the headings of given blocks, with their
+// anchors made unique by numbering any repeats, as on GitHub
+func markdownHeadings(bks []mdBlock) []MarkdownHeading {
+	var hds []MarkdownHeading
+	anchors := map[string]int{}
+	for _, bk := range bks {
+		if bk.kind != mdHeading {
+			continue
+		}
+		anc := gi.HelpAnchor(bk.text)
+		if n := anchors[anc]; n > 0 {
+			anchors[anc] = n + 1
+			anc = fmt.Sprintf("%s-%d", anc, n)
+		} else {
+			anchors[anc] = 1
+		}
+		hds = append(hds, MarkdownHeading{Level: bk.level, Text: bk.text, Anchor: anc})
+	}
+	return hds
+}
+
+// mdBlockKinds are the kinds of blocks of a Markdown document
+type mdBlockKinds int
+
+const (
+	mdPara mdBlockKinds = iota
+	mdHeading
+	mdList
+	mdQuote
+	mdCode
+	mdRule
+)
+
+// mdBlock is a block of a Markdown document
+type mdBlock struct {
+	kind   mdBlockKinds
+	level  int    // heading level, or list nesting level
+	marker string // list item marker
+	text   string // text of the block, with the lines of paragraphs joined
+}
+
+// parseMarkdown parses the blocks of given Markdown source
+func parseMarkdown(src string) []mdBlock {
+	var bks []mdBlock
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	open := false // last block takes continuation lines
+	for i := 0; i < len(lines); i++ {
+		ln := strings.TrimRight(lines[i], " \t")
+		tl := strings.TrimLeft(ln, " \t")
+		ind := len(strings.ReplaceAll(ln[:len(ln)-len(tl)], "\t", "    "))
+		last := len(bks) - 1
+		switch {
+		case tl == "":
+			open = false
+		case strings.HasPrefix(tl, "```") || strings.HasPrefix(tl, "~~~"):
+			fence := tl[:3]
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					break
+				}
+				code = append(code, strings.TrimRight(lines[i], " \t\r"))
+			}
+			bks = append(bks, mdBlock{kind: mdCode, text: strings.Join(code, "\n")})
+			open = false
+		case mdHeadingLevel(tl) > 0:
+			lev := mdHeadingLevel(tl)
+			txt := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(tl[lev:]), "#"))
+			bks = append(bks, mdBlock{kind: mdHeading, level: lev, text: txt})
+			open = false
+		case open && bks[last].kind == mdPara && mdIsRun(tl, '='):
+			bks[last].kind, bks[last].level = mdHeading, 1
+			open = false
+		case open && bks[last].kind == mdPara && mdIsRun(tl, '-'):
+			bks[last].kind, bks[last].level = mdHeading, 2
+			open = false
+		case mdIsRule(tl):
+			bks = append(bks, mdBlock{kind: mdRule})
+			open = false
+		case strings.HasPrefix(tl, ">"):
+			txt := strings.TrimSpace(strings.TrimPrefix(tl, ">"))
+			if open && bks[last].kind == mdQuote {
+				bks[last].text += " " + txt
+			} else {
+				bks = append(bks, mdBlock{kind: mdQuote, text: txt})
+				open = true
+			}
+		case mdListMarker(tl) != "":
+			mk := mdListMarker(tl)
+			txt := strings.TrimSpace(tl[len(mk):])
+			if mk == "-" || mk == "*" || mk == "+" {
+				mk = "•"
+			}
+			bks = append(bks, mdBlock{kind: mdList, level: ind / 2, marker: mk, text: txt})
+			open = true
+		case open:
+			bks[last].text += " " + tl
+		default:
+			bks = append(bks, mdBlock{kind: mdPara, text: tl})
+			open = true
+		}
+	}
+	return bks
+}
+
+// mdHeadingLevel returns the level of the heading on given line, or 0 if
+// it is not a heading
+func mdHeadingLevel(tl string) int {
+	lev := 0
+	for lev < len(tl) && tl[lev] == '#' {
+		lev++
+	}
+	if lev == 0 || lev > 6 || (lev < len(tl) && tl[lev] != ' ' && tl[lev] != '\t') {
+		return 0
+	}
+	return lev
+}
+
+// mdIsRun returns true if given line is only the given character
+func mdIsRun(tl string, c byte) bool {
+	return strings.Trim(tl, string(c)) == ""
+}
+
+// mdIsRule returns true if given line is a rule: three or more of the
+// same one of - * _, optionally separated by spaces
+func mdIsRule(tl string) bool {
+	s := strings.ReplaceAll(tl, " ", "")
+	return len(s) >= 3 && (mdIsRun(s, '-') || mdIsRun(s, '*') || mdIsRun(s, '_'))
+}
+
+// mdListMarker returns the list item marker at the start of given line,
+// e.g., - or 1. -- empty if it is not a list item
+func mdListMarker(tl string) string {
+	if len(tl) >= 2 && strings.IndexByte("-*+", tl[0]) >= 0 && tl[1] == ' ' {
+		return tl[:1]
+	}
+	n := 0
+	for n < len(tl) && tl[n] >= '0' && tl[n] <= '9' {
+		n++
+	}
+	if n > 0 && n+1 < len(tl) && (tl[n] == '.' || tl[n] == ')') && tl[n+1] == ' ' {
+		return tl[:n+1]
+	}
+	return ""
+}
+
+// MarkdownInlineHTML converts the inline Markdown formatting of given
+// text into the html formatting supported by gi.Label: **strong** and
+// *emphasis* (or with _), `code`, [links](url) and <url> links, with
+// backslash escapes -- all other text is html-escaped.
+func MarkdownInlineHTML(s string) string {
+	var b strings.Builder
+	var tags []string // open emphasis tags, innermost last
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()<>#+-.!|", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			n := mdRunLen(s[i:], '`')
+			if e := strings.Index(s[i+n:], s[i:i+n]); e >= 0 {
+				b.WriteString("<code>" + html.EscapeString(strings.TrimSpace(s[i+n:i+n+e])) + "</code>")
+				i += n + e + n
+				continue
+			}
+		case c == '[' || (c == '!' && i+1 < len(s) && s[i+1] == '['):
+			st := i
+			if c == '!' {
+				st++
+			}
+			if te := strings.Index(s[st:], "]("); te > 0 {
+				te += st
+				if ue := strings.IndexByte(s[te+2:], ')'); ue >= 0 {
+					url := strings.Fields(s[te+2 : te+2+ue]) // drop any "title"
+					if len(url) > 0 {
+						b.WriteString(`<a href="` + html.EscapeString(url[0]) + `">` + MarkdownInlineHTML(s[st+1:te]) + "</a>")
+						i = te + 2 + ue + 1
+						continue
+					}
+				}
+			}
+		case c == '<':
+			if e := strings.IndexByte(s[i:], '>'); e > 0 && strings.Contains(s[i:i+e], "://") && !strings.ContainsAny(s[i:i+e], " \t") {
+				url := html.EscapeString(s[i+1 : i+e])
+				b.WriteString(`<a href="` + url + `">` + url + "</a>")
+				i += e + 1
+				continue
+			}
+		case c == '*' || c == '_':
+			n := mdRunLen(s[i:], c)
+			tag := "i"
+			if n >= 2 {
+				n, tag = 2, "b"
+			}
+			delim := s[i : i+n]
+			// intraword underscores are literal, e.g., in snake_case names
+			wordBefore := i > 0 && mdIsWordChar(s[i-1])
+			wordAfter := i+n < len(s) && mdIsWordChar(s[i+n])
+			if len(tags) > 0 && tags[len(tags)-1] == tag && i > 0 && s[i-1] != ' ' && !(c == '_' && wordAfter) {
+				b.WriteString("</" + tag + ">")
+				tags = tags[:len(tags)-1]
+				i += n
+				continue
+			}
+			if i+n < len(s) && s[i+n] != ' ' && !(c == '_' && wordBefore) && strings.Contains(s[i+n+1:], delim) {
+				b.WriteString("<" + tag + ">")
+				tags = append(tags, tag)
+				i += n
+				continue
+			}
+			b.WriteString(delim)
+			i += n
+			continue
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	for j := len(tags) - 1; j >= 0; j-- {
+		b.WriteString("</" + tags[j] + ">")
+	}
+	return b.String()
+}
+
+// mdRunLen returns the number of leading instances of given character
+func mdRunLen(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// mdIsWordChar returns true if given byte is part of a word
+func mdIsWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
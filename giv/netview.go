@@ -0,0 +1,328 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  NetKinds
+
+// NetKinds are the kinds of network values edited by a NetValueView, each
+// with its own input mask, paste normalization and validation
+type NetKinds int32
+
+const (
+	// NetIP is an IPv4 or IPv6 address, e.g., 192.168.0.1 or ::1 --
+	// net.IP, netip.Addr, or a string with a `view:"ip"` tag
+	NetIP NetKinds = iota
+
+	// NetCIDR is an IP address with a prefix length, e.g., 10.0.0.0/8 --
+	// net.IPNet, netip.Prefix, or a string with a `view:"cidr"` tag -- a
+	// bare address gets the full prefix length, e.g., /32 for IPv4
+	NetCIDR
+
+	// NetURL is an absolute URL, e.g., https://example.com/path --
+	// url.URL, or a string with a `view:"url"` tag -- https:// is added
+	// if there is no scheme
+	NetURL
+
+	// NetEmail is an email address, e.g., name@example.com -- a string
+	// with a `view:"email"` tag
+	NetEmail
+
+	NetKindsN
+)
+
+//go:generate stringer -type=NetKinds
+
+var KiT_NetKinds = kit.Enums.AddEnum(NetKindsN, kit.NotBitFlag, nil)
+
+// NetPlaceholders are the placeholder texts shown in empty fields for
+// each kind of network value
+var NetPlaceholders = [NetKindsN]string{"192.168.0.1", "10.0.0.0/8", "https://example.com", "name@example.com"}
+
+// Allowed returns true if given character is allowed in the input of the
+// kind of network value -- this is its input mask
+func (nk NetKinds) Allowed(r rune) bool {
+	switch nk {
+	case NetIP:
+		return r == '.' || r == ':' || unicode.In(r, unicode.ASCII_Hex_Digit)
+	case NetCIDR:
+		return r == '/' || NetIP.Allowed(r)
+	case NetEmail:
+		return unicode.IsPrint(r) && !unicode.IsSpace(r) && !strings.ContainsRune(`"(),:;<>[\]`, r)
+	}
+	return unicode.IsPrint(r) && !unicode.IsSpace(r)
+}
+
+// Normalize returns given text, e.g., as pasted, normalized for the kind
+// of network value: surrounding space, quotes and brackets are removed,
+// and a mailto: prefix or display name is removed from an email address,
+// e.g., "Jane Doe <jane@example.com>" becomes jane@example.com
+func (nk NetKinds) Normalize(s string) string {
+	str := strings.TrimSpace(s)
+	for len(str) >= 2 {
+		f, l := str[0], str[len(str)-1]
+		if (f == '"' && l == '"') || (f == '\'' && l == '\'') || (f == '<' && l == '>') || (f == '[' && l == ']') {
+			str = strings.TrimSpace(str[1 : len(str)-1])
+			continue
+		}
+		break
+	}
+	if nk == NetEmail {
+		if len(str) > 7 && strings.EqualFold(str[:7], "mailto:") {
+			str = str[7:]
+		}
+		if strings.ContainsAny(str, "<\"") {
+			if a, err := mail.ParseAddress(str); err == nil {
+				str = a.Address
+			}
+		}
+	}
+	return str
+}
+
+// Filter returns given typed or pasted text normalized and with the
+// characters that are not allowed removed -- for the InputFilter of a
+// gi.TextField
+func (nk NetKinds) Filter(s string) string {
+	return strings.Map(func(r rune) rune {
+		if nk.Allowed(r) {
+			return r
+		}
+		return -1
+	}, nk.Normalize(s))
+}
+
+// Parse validates given text as the kind of network value, returning its
+// canonical text, e.g., ::1 for 0:0:0:0:0:0:0:1 -- empty text is valid,
+// for an unset value
+func (nk NetKinds) Parse(s string) (string, error) {
+	str := nk.Normalize(s)
+	if str == "" {
+		return "", nil
+	}
+	switch nk {
+	case NetIP:
+		ip := net.ParseIP(str)
+		if ip == nil {
+			return "", fmt.Errorf("invalid IP address %q", str)
+		}
+		return ip.String(), nil
+	case NetCIDR:
+		if !strings.Contains(str, "/") {
+			ip := net.ParseIP(str)
+			if ip == nil {
+				return "", fmt.Errorf("invalid IP address %q", str)
+			}
+			if ip.To4() != nil {
+				return ip.String() + "/32", nil
+			}
+			return ip.String() + "/128", nil
+		}
+		ip, ipn, err := net.ParseCIDR(str)
+		if err != nil {
+			return "", fmt.Errorf("invalid CIDR address %q -- use an IP address and prefix length, e.g., 10.0.0.0/8", str)
+		}
+		ones, _ := ipn.Mask.Size()
+		return fmt.Sprintf("%s/%d", ip, ones), nil
+	case NetURL:
+		if !strings.Contains(str, ":") {
+			str = "https://" + str
+		}
+		u, err := url.Parse(str)
+		if err != nil {
+			return "", fmt.Errorf("invalid URL %q", str)
+		}
+		if u.Scheme == "" {
+			return "", fmt.Errorf("invalid URL %q: no scheme, e.g., https://", str)
+		}
+		if u.Opaque == "" && u.Host == "" && u.Scheme != "file" {
+			return "", fmt.Errorf("invalid URL %q: no host", str)
+		}
+		return u.String(), nil
+	case NetEmail:
+		a, err := mail.ParseAddress(str)
+		if err != nil || !strings.Contains(a.Address[strings.LastIndex(a.Address, "@")+1:], ".") {
+			return "", fmt.Errorf("invalid email address %q -- use name@example.com", str)
+		}
+		return a.Address, nil
+	}
+	return str, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  NetValueView
+
+// NetValueView presents a network value of given NetKinds, e.g., an IP
+// address, CIDR address, URL or email address, in a text field with an
+// input mask, normalization of pasted text, and validation, which shows
+// the error below the field and keeps the invalid text for fixing it
+type NetValueView struct {
+	ValueViewBase
+	Kind NetKinds `desc:"kind of network value"`
+}
+
+var KiT_NetValueView = kit.Types.AddType(&NetValueView{}, nil)
+
+func (vv *NetValueView) WidgetType() reflect.Type {
+	vv.WidgetTyp = gi.KiT_TextField
+	return vv.WidgetTyp
+}
+
+// NetVal returns the underlying non-pointer value, which can be set --
+// if alloc is true, a nil pointer is set to a new value, and otherwise
+// it returns an invalid value for it
+func (vv *NetValueView) NetVal(alloc bool) reflect.Value {
+	v := vv.Value
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !alloc || !v.CanSet() {
+				return reflect.Value{}
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// NetText returns the text of the value, which is empty if it is unset
+func (vv *NetValueView) NetText() string {
+	v := vv.NetVal(false)
+	if !v.IsValid() {
+		return ""
+	}
+	switch nv := v.Interface().(type) {
+	case net.IP:
+		if len(nv) == 0 {
+			return ""
+		}
+		return nv.String()
+	case net.IPNet:
+		if len(nv.IP) == 0 {
+			return ""
+		}
+		ones, _ := nv.Mask.Size()
+		return fmt.Sprintf("%s/%d", nv.IP, ones)
+	case netip.Addr:
+		if !nv.IsValid() {
+			return ""
+		}
+		return nv.String()
+	case netip.Prefix:
+		if !nv.IsValid() {
+			return ""
+		}
+		return nv.String()
+	case url.URL:
+		return nv.String()
+	}
+	return kit.ToString(v.Interface())
+}
+
+// SetNetText sets the value from given text, which is validated for the
+// kind of network value -- returns an error if it is invalid
+func (vv *NetValueView) SetNetText(txt string) error {
+	str, err := vv.Kind.Parse(txt)
+	if err != nil {
+		return err
+	}
+	if kit.NonPtrType(vv.Value.Type()).Kind() == reflect.String {
+		vv.SetValue(str)
+		return nil
+	}
+	if vv.This().(ValueView).IsInactive() {
+		return nil
+	}
+	v := vv.NetVal(true)
+	if !v.IsValid() || !v.CanSet() {
+		return fmt.Errorf("%s value cannot be set", vv.Kind)
+	}
+	var nv any
+	switch v.Interface().(type) {
+	case net.IP:
+		nv = net.ParseIP(str)
+	case net.IPNet:
+		ipn := net.IPNet{}
+		if str != "" {
+			ip, pn, _ := net.ParseCIDR(str)
+			ipn = net.IPNet{IP: ip, Mask: pn.Mask}
+		}
+		nv = ipn
+	case netip.Addr:
+		a, _ := netip.ParseAddr(str)
+		nv = a
+	case netip.Prefix:
+		p, _ := netip.ParsePrefix(str)
+		nv = p
+	case url.URL:
+		u := &url.URL{}
+		if str != "" {
+			u, _ = url.Parse(str)
+		}
+		nv = *u
+	default:
+		return fmt.Errorf("%s value of type %s is not supported", vv.Kind, v.Type())
+	}
+	v.Set(reflect.ValueOf(nv))
+	vv.ViewSig.Emit(vv.This(), 0, nil)
+	return nil
+}
+
+func (vv *NetValueView) UpdateWidget() {
+	if vv.Widget == nil {
+		return
+	}
+	tf := vv.Widget.(*gi.TextField)
+	tf.SetText(vv.NetText())
+	tf.SetErrorText("")
+}
+
+func (vv *NetValueView) ConfigWidget(widg gi.Node2D) {
+	vv.Widget = widg
+	vv.StdConfigWidget(widg)
+	tf := vv.Widget.(*gi.TextField)
+	tf.SetStretchMaxWidth()
+	tf.Tooltip, _ = vv.Tag("desc")
+	tf.SetInactiveState(vv.This().(ValueView).IsInactive())
+	tf.Placeholder = NetPlaceholders[vv.Kind]
+	tf.SetProp("min-width", units.NewCh(float32(len(tf.Placeholder)+2)))
+	tf.InputFilter = vv.Kind.Filter
+	tf.TextFieldSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		vvv, _ := recv.Embed(KiT_NetValueView).(*NetValueView)
+		tf := send.(*gi.TextField)
+		switch sig {
+		case int64(gi.TextFieldDone), int64(gi.TextFieldDeFocused):
+			if err := vvv.SetNetText(tf.Text()); err != nil {
+				tf.SetErrorText(err.Error())
+				return
+			}
+			vvv.UpdateWidget()
+		case int64(gi.TextFieldInsert), int64(gi.TextFieldBackspace), int64(gi.TextFieldDelete):
+			if tf.ErrorText == "" {
+				return
+			}
+			if _, err := vvv.Kind.Parse(string(tf.EditTxt)); err == nil { // fixed
+				tf.SetErrorText("")
+			}
+		}
+	})
+	vv.UpdateWidget()
+}
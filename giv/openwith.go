@@ -0,0 +1,488 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/pi/filecat"
+)
+
+//////////////////////////////////////////////////////////////////////////////
+//    Open With
+
+// FileOpenFunc opens given file in the app
+type FileOpenFunc func(fn *FileNode) error
+
+// FileOpenHandler is an in-app handler for opening files of given
+// categories, listed in the Open With menu of the file tree -- see
+// RegisterFileOpenHandler
+type FileOpenHandler struct {
+	Name string        `desc:"name of the handler, shown in the menu"`
+	Cats []filecat.Cat `desc:"categories of files that the handler opens -- all files if empty"`
+	Open FileOpenFunc  `desc:"function that opens the file"`
+}
+
+// Handles returns true if the handler opens files of given category
+func (fh *FileOpenHandler) Handles(cat filecat.Cat) bool {
+	if len(fh.Cats) == 0 {
+		return true
+	}
+	for _, c := range fh.Cats {
+		if c == cat {
+			return true
+		}
+	}
+	return false
+}
+
+// FileOpenHandlers are the registered in-app handlers for opening files
+var FileOpenHandlers []*FileOpenHandler
+
+// RegisterFileOpenHandler registers an in-app handler with given name for
+// opening files of given categories (e.g., filecat.Image), or all files if
+// none are given, which is then listed in the Open With menu of the file
+// tree for those files.  A handler with the same name is replaced.
+func RegisterFileOpenHandler(name string, open FileOpenFunc, cats ...filecat.Cat) {
+	fh := &FileOpenHandler{Name: name, Cats: cats, Open: open}
+	for i, h := range FileOpenHandlers {
+		if h.Name == name {
+			FileOpenHandlers[i] = fh
+			return
+		}
+	}
+	FileOpenHandlers = append(FileOpenHandlers, fh)
+}
+
+// FileOpenHandlersFor returns the registered in-app handlers for files of
+// given category
+func FileOpenHandlersFor(cat filecat.Cat) []*FileOpenHandler {
+	var fhs []*FileOpenHandler
+	for _, h := range FileOpenHandlers {
+		if h.Handles(cat) {
+			fhs = append(fhs, h)
+		}
+	}
+	return fhs
+}
+
+// OpenWithApp is an app that a file can be opened with: either an in-app
+// FileOpenHandler or a system app command
+type OpenWithApp struct {
+	Name    string           `desc:"name of the app, shown in the menu"`
+	Handler *FileOpenHandler `desc:"the in-app handler, if this is one"`
+	Command []string         `desc:"the command and args of the system app, with the field codes of desktop entries, e.g., %f for the file path, which is added at the end if there are none -- see CommandArgs"`
+	Icon    string           `desc:"icon of the system app, from its desktop entry, for the %i field code"`
+	Desktop string           `desc:"path of the desktop entry of the system app, for the %k field code"`
+}
+
+// CommandArgs returns the command and args of the system app for opening
+// the file at given path, expanding the field codes of desktop entries in
+// the Command: %f, %F, %u and %U are the file path, %i is --icon and the
+// Icon, if any, %c is the Name, %k is the Desktop entry path, and %% is %
+// -- other, deprecated codes are removed.  The file path is added at the
+// end if there are no codes for it.
+func (ap *OpenWithApp) CommandArgs(fpath string) []string {
+	var args []string
+	hasFile := false
+	for _, a := range ap.Command {
+		switch a {
+		case "%f", "%F", "%u", "%U":
+			args = append(args, fpath)
+			hasFile = true
+			continue
+		case "%i":
+			if ap.Icon != "" {
+				args = append(args, "--icon", ap.Icon)
+			}
+			continue
+		}
+		var sb strings.Builder
+		for i := 0; i < len(a); i++ {
+			if a[i] != '%' || i+1 == len(a) {
+				sb.WriteByte(a[i])
+				continue
+			}
+			i++
+			switch a[i] {
+			case '%':
+				sb.WriteByte('%')
+			case 'f', 'F', 'u', 'U':
+				sb.WriteString(fpath)
+				hasFile = true
+			case 'c':
+				sb.WriteString(ap.Name)
+			case 'k':
+				sb.WriteString(ap.Desktop)
+			}
+		}
+		if sb.Len() == 0 && a != "" { // only removed codes
+			continue
+		}
+		args = append(args, sb.String())
+	}
+	if !hasFile {
+		args = append(args, fpath)
+	}
+	return args
+}
+
+const (
+	// OpenWithSystemDefault is the name of the system default app in the
+	// Open With menus
+	OpenWithSystemDefault = "System Default"
+
+	// OpenWithOther is the Open With menu item for opening with a command
+	// entered by the user
+	OpenWithOther = "Other..."
+)
+
+// SystemOpenApps returns the system apps for opening files of given mime
+// type -- on Linux these come from the desktop entries of the installed
+// apps, and on other platforms only the system default is available.
+func SystemOpenApps(mime string) []OpenWithApp {
+	if oswin.TheApp.Platform() != oswin.LinuxX11 {
+		return nil
+	}
+	desktopAppsOnce.Do(loadDesktopApps)
+	if mi := strings.Index(mime, ";"); mi > 0 { // e.g., text/plain; charset=utf-8
+		mime = strings.TrimSpace(mime[:mi])
+	}
+	var apps []OpenWithApp
+	if mt := strings.Index(mime, "/"); mt > 0 {
+		apps = append(apps, desktopApps[mime[:mt]+"/*"]...)
+	}
+	return append(desktopApps[mime], apps...)
+}
+
+var (
+	desktopAppsOnce sync.Once
+
+	// desktopApps are the apps from the desktop entries, by mime type
+	desktopApps map[string][]OpenWithApp
+)
+
+// loadDesktopApps loads the desktop entries of the installed apps, in the
+// standard XDG data directories
+func loadDesktopApps() {
+	desktopApps = make(map[string][]OpenWithApp)
+	var dirs []string
+	if dh := os.Getenv("XDG_DATA_HOME"); dh != "" {
+		dirs = append(dirs, dh)
+	} else if hd, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(hd, ".local", "share"))
+	}
+	dd := os.Getenv("XDG_DATA_DIRS")
+	if dd == "" {
+		dd = "/usr/local/share:/usr/share"
+	}
+	dirs = append(dirs, filepath.SplitList(dd)...)
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		fns, _ := filepath.Glob(filepath.Join(dir, "applications", "*.desktop"))
+		for _, fn := range fns {
+			id := filepath.Base(fn)
+			if seen[id] { // earlier dirs take precedence
+				continue
+			}
+			seen[id] = true
+			app, mimes := readDesktopEntry(fn)
+			for _, mt := range mimes {
+				desktopApps[mt] = append(desktopApps[mt], app)
+			}
+		}
+	}
+	for _, apps := range desktopApps {
+		sort.Slice(apps, func(i, j int) bool {
+			return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+		})
+	}
+}
+
+// readDesktopEntry reads the app and the mime types it opens from given
+// desktop entry file -- returns no mime types for hidden apps
+func readDesktopEntry(fname string) (OpenWithApp, []string) {
+	app := OpenWithApp{Desktop: fname}
+	f, err := os.Open(fname)
+	if err != nil {
+		return app, nil
+	}
+	defer f.Close()
+	var mimes []string
+	inEntry := false
+	hidden := false
+	execVal := ""
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		ln := strings.TrimSpace(scan.Text())
+		if strings.HasPrefix(ln, "[") {
+			inEntry = ln == "[Desktop Entry]"
+			continue
+		}
+		ei := strings.Index(ln, "=")
+		if !inEntry || ei < 0 {
+			continue
+		}
+		key, val := strings.TrimSpace(ln[:ei]), strings.TrimSpace(ln[ei+1:])
+		switch key {
+		case "Name":
+			app.Name = unescapeDesktopValue(val)
+		case "Exec":
+			execVal = unescapeDesktopValue(val)
+		case "Icon":
+			app.Icon = unescapeDesktopValue(val)
+		case "MimeType":
+			for _, mt := range strings.Split(val, ";") {
+				if mt != "" {
+					mimes = append(mimes, mt)
+				}
+			}
+		case "NoDisplay", "Hidden":
+			hidden = hidden || val == "true"
+		}
+	}
+	app.Command, err = splitDesktopExec(execVal)
+	if err != nil {
+		log.Printf("giv.readDesktopEntry: %v: %v\n", fname, err)
+	}
+	if hidden || err != nil || app.Name == "" || len(app.Command) == 0 {
+		return app, nil
+	}
+	return app, mimes
+}
+
+// unescapeDesktopValue returns given string value of a desktop entry with
+// its escape sequences (\s, \n, \t, \r and \\) replaced
+func unescapeDesktopValue(val string) string {
+	if !strings.Contains(val, `\`) {
+		return val
+	}
+	var sb strings.Builder
+	for i := 0; i < len(val); i++ {
+		c := val[i]
+		if c == '\\' && i+1 < len(val) {
+			i++
+			switch val[i] {
+			case 's':
+				c = ' '
+			case 'n':
+				c = '\n'
+			case 't':
+				c = '\t'
+			case 'r':
+				c = '\r'
+			case '\\':
+				c = '\\'
+			default:
+				sb.WriteByte('\\')
+				c = val[i]
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// splitDesktopExec splits given (unescaped) Exec value of a desktop entry
+// into the command and its args, which are separated by spaces, and can be
+// enclosed in double quotes, within which the characters " ` $ and \ are
+// escaped with a backslash.  The field codes (e.g., %f) are kept, for
+// CommandArgs.
+func splitDesktopExec(val string) ([]string, error) {
+	var args []string
+	var sb strings.Builder
+	inArg := false
+	quoted := false
+	for i := 0; i < len(val); i++ {
+		c := val[i]
+		switch {
+		case quoted && c == '\\':
+			if i+1 < len(val) && strings.IndexByte("\"`$\\", val[i+1]) >= 0 {
+				i++
+				c = val[i]
+			}
+			sb.WriteByte(c)
+		case c == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (c == ' ' || c == '\t' || c == '\n'):
+			if inArg {
+				args = append(args, sb.String())
+				sb.Reset()
+				inArg = false
+			}
+		default:
+			sb.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in Exec: %v", val)
+	}
+	if inArg {
+		args = append(args, sb.String())
+	}
+	return args, nil
+}
+
+// OpenWithApps returns the apps that this file can be opened with: the
+// in-app handlers registered for its category, followed by the system apps
+// for its mime type
+func (fn *FileNode) OpenWithApps() []OpenWithApp {
+	var apps []OpenWithApp
+	for _, h := range FileOpenHandlersFor(fn.Info.Cat) {
+		apps = append(apps, OpenWithApp{Name: h.Name, Handler: h})
+	}
+	return append(apps, SystemOpenApps(fn.Info.Mime)...)
+}
+
+// OpenWith opens the file with the app of given name, from OpenWithApps,
+// or the system default app
+func (fn *FileNode) OpenWith(app string) error {
+	if app == OpenWithSystemDefault {
+		return fn.OpenFileDefault()
+	}
+	for _, ap := range fn.OpenWithApps() {
+		if ap.Name != app {
+			continue
+		}
+		if ap.Handler != nil {
+			return ap.Handler.Open(fn)
+		}
+		args := ap.CommandArgs(string(fn.FPath))
+		cmd := exec.Command(args[0], args[1:]...)
+		err := cmd.Start()
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := cmd.Wait(); err != nil {
+				log.Println(err)
+			}
+		}()
+		return nil
+	}
+	return fmt.Errorf("giv.FileNode OpenWith: app %q not found for file: %v", app, fn.FPath)
+}
+
+// DefaultApp returns the name of the default app for opening this file,
+// set for its extension in gi.Prefs.FileOpenWith, or OpenWithSystemDefault
+func (fn *FileNode) DefaultApp() string {
+	ext := strings.ToLower(filepath.Ext(string(fn.FPath)))
+	if app, has := gi.Prefs.FileOpenWith[ext]; has && ext != "" {
+		return app
+	}
+	return OpenWithSystemDefault
+}
+
+// OpenFile opens the file with its DefaultApp, falling back on the system
+// default app if that app is no longer available
+func (fn *FileNode) OpenFile() error {
+	app := fn.DefaultApp()
+	err := fn.OpenWith(app)
+	if err != nil && app != OpenWithSystemDefault {
+		log.Println(err)
+		return fn.OpenFileDefault()
+	}
+	return err
+}
+
+// SetDefaultApp sets the app of given name as the default for opening
+// files with the same extension as this one, in gi.Prefs.FileOpenWith,
+// and saves the preferences -- OpenWithSystemDefault removes it
+func (fn *FileNode) SetDefaultApp(app string) {
+	ext := strings.ToLower(filepath.Ext(string(fn.FPath)))
+	if ext == "" {
+		return
+	}
+	if app == OpenWithSystemDefault {
+		delete(gi.Prefs.FileOpenWith, ext)
+	} else {
+		if gi.Prefs.FileOpenWith == nil {
+			gi.Prefs.FileOpenWith = make(map[string]string)
+		}
+		gi.Prefs.FileOpenWith[ext] = app
+	}
+	gi.Prefs.Save()
+}
+
+// FileTreeOpenWithMenu is the SubMenuFunc for the Open With menu of the
+// file tree, listing the apps for the file
+func FileTreeOpenWithMenu(it any, vp *gi.Viewport2D) []string {
+	ftv, ok := it.(*FileTreeView)
+	if !ok {
+		return nil
+	}
+	fn := ftv.FileNode()
+	if fn == nil {
+		return nil
+	}
+	var items []string
+	for _, ap := range fn.OpenWithApps() {
+		items = append(items, ap.Name)
+	}
+	if len(items) > 0 {
+		items = append(items, gi.MenuTextSeparator)
+	}
+	return append(items, OpenWithSystemDefault, OpenWithOther)
+}
+
+// FileTreeDefaultAppMenu is the SubMenuFunc for the Default App menu of
+// the file tree, listing the apps for the file
+func FileTreeDefaultAppMenu(it any, vp *gi.Viewport2D) []string {
+	ftv, ok := it.(*FileTreeView)
+	if !ok {
+		return nil
+	}
+	fn := ftv.FileNode()
+	if fn == nil {
+		return nil
+	}
+	items := []string{OpenWithSystemDefault}
+	for _, ap := range fn.OpenWithApps() {
+		items = append(items, ap.Name)
+	}
+	return items
+}
+
+// OpenWith opens the selected files with the app of given name, or a
+// command entered by the user for OpenWithOther
+func (ftv *FileTreeView) OpenWith(app string) {
+	sels := ftv.SelectedViews()
+	for i := len(sels) - 1; i >= 0; i-- {
+		sn := sels[i]
+		fftv := sn.Embed(KiT_FileTreeView).(*FileTreeView)
+		fn := fftv.FileNode()
+		if fn == nil {
+			continue
+		}
+		if app == OpenWithOther {
+			CallMethod(fn, "OpenFileWith", ftv.ViewportSafe())
+			continue
+		}
+		if err := fn.OpenWith(app); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// SetDefaultApp sets the app of given name as the default for opening
+// files with the extension of this file
+func (ftv *FileTreeView) SetDefaultApp(app string) {
+	fn := ftv.FileNode()
+	if fn != nil {
+		fn.SetDefaultApp(app)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDesktopEntryExec(t *testing.T) {
+	tests := []struct {
+		name string
+		exec string
+		args []string
+	}{
+		{"plain", `gedit`, []string{"gedit", "/tmp/a b.txt"}},
+		{"file code", `gedit --new-window %U`, []string{"gedit", "--new-window", "/tmp/a b.txt"}},
+		{"single file", `app -f %f --x`, []string{"app", "-f", "/tmp/a b.txt", "--x"}},
+		{"embedded", `app --file=%f`, []string{"app", "--file=/tmp/a b.txt"}},
+		{"icon etc", `app %i --name %c --from %k %F`, []string{"app", "--icon", "app-icon", "--name", "The App", "--from", "/apps/app.desktop", "/tmp/a b.txt"}},
+		{"deprecated", `app %d %m %f`, []string{"app", "/tmp/a b.txt"}},
+		{"quoted", `"/opt/my app/bin" "a \"b\" \$c \\\\d" %f`, []string{"/opt/my app/bin", `a "b" $c \d`, "/tmp/a b.txt"}},
+		{"escaped space", `"my\sapp" %f`, []string{"my app", "/tmp/a b.txt"}},
+		{"percent", `sh -c "echo 100%%" %f`, []string{"sh", "-c", "echo 100%", "/tmp/a b.txt"}},
+		{"empty quoted", `app "" %f`, []string{"app", "", "/tmp/a b.txt"}},
+	}
+	for _, tt := range tests {
+		cmd, err := splitDesktopExec(unescapeDesktopValue(tt.exec))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		app := OpenWithApp{Name: "The App", Command: cmd, Icon: "app-icon", Desktop: "/apps/app.desktop"}
+		if args := app.CommandArgs("/tmp/a b.txt"); !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: got %q, want %q", tt.name, args, tt.args)
+		}
+	}
+	if _, err := splitDesktopExec(`app "unterminated %f`); err == nil {
+		t.Errorf("unterminated quote: no error")
+	}
+	if args := (&OpenWithApp{Name: "App", Command: []string{"app", "%i", "%f"}}).CommandArgs("x"); !reflect.DeepEqual(args, []string{"app", "x"}) {
+		t.Errorf("%%i without icon: got %q", args)
+	}
+}
+
+func TestReadDesktopEntry(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "app.desktop")
+	ent := "[Desktop Entry]\nName=My App\nIcon=myapp\nExec=\"/opt/my app/run\" --open %F\nMimeType=text/plain;image/png;\n[Desktop Action New]\nExec=other\n"
+	if err := os.WriteFile(fn, []byte(ent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app, mimes := readDesktopEntry(fn)
+	if app.Name != "My App" || app.Icon != "myapp" || app.Desktop != fn || !reflect.DeepEqual(mimes, []string{"text/plain", "image/png"}) {
+		t.Errorf("got %+v, %v", app, mimes)
+	}
+	if args := app.CommandArgs("f.txt"); !reflect.DeepEqual(args, []string{"/opt/my app/run", "--open", "f.txt"}) {
+		t.Errorf("args: got %q", args)
+	}
+}
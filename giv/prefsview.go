@@ -5,11 +5,7 @@
 package giv
 
 import (
-	"fmt"
-
 	"github.com/goki/gi/gi"
-	"github.com/goki/gi/oswin"
-	"github.com/goki/ki/ki"
 )
 
 // PrefsView opens a view of user preferences
@@ -36,34 +32,12 @@ func PrefsView(pf *gi.Preferences) *gi.Window {
 	mmen := win.MainMenu
 	MainMenuView(pf, win, mmen)
 
-	inClosePrompt := false
-	win.OSWin.SetCloseReqFunc(func(w oswin.Window) {
-		if !pf.Changed {
-			win.Close()
-			return
-		}
-		if inClosePrompt {
-			return
-		}
-		inClosePrompt = true
-		gi.ChoiceDialog(vp, gi.DlgOpts{Title: "Save Prefs Before Closing?",
-			Prompt: "Do you want to save any changes to preferences before closing?"},
-			[]string{"Save and Close", "Discard and Close", "Cancel"},
-			win.This(), func(recv, send ki.Ki, sig int64, data any) {
-				switch sig {
-				case 0:
-					pf.Save()
-					fmt.Println("Preferences Saved to prefs.json")
-					win.Close()
-				case 1:
-					pf.Open() // if we don't do this, then it actually remains in edited state
-					win.Close()
-				case 2:
-					inClosePrompt = false
-					// default is to do nothing, i.e., cancel
-				}
-			})
-	})
+	win.AddDirty(&gi.DirtyFuncs{Name: "GoGi Preferences",
+		Dirty: func() bool { return pf.Changed },
+		Save:  pf.Save,
+		Discard: func() {
+			pf.Open() // if we don't do this, then it actually remains in edited state
+		}})
 
 	win.MainMenuUpdated()
 
@@ -101,34 +75,12 @@ func PrefsDetView(pf *gi.PrefsDetailed) *gi.Window {
 	mmen := win.MainMenu
 	MainMenuView(pf, win, mmen)
 
-	inClosePrompt := false
-	win.OSWin.SetCloseReqFunc(func(w oswin.Window) {
-		if !pf.Changed {
-			win.Close()
-			return
-		}
-		if inClosePrompt {
-			return
-		}
-		inClosePrompt = true
-		gi.ChoiceDialog(vp, gi.DlgOpts{Title: "Save Prefs Before Closing?",
-			Prompt: "Do you want to save any changes to detailed preferences before closing?"},
-			[]string{"Save and Close", "Discard and Close", "Cancel"},
-			win.This(), func(recv, send ki.Ki, sig int64, data any) {
-				switch sig {
-				case 0:
-					pf.Save()
-					fmt.Println("Preferences Saved to prefs_det.json")
-					win.Close()
-				case 1:
-					pf.Open() // if we don't do this, then it actually remains in edited state
-					win.Close()
-				case 2:
-					inClosePrompt = false
-					// default is to do nothing, i.e., cancel
-				}
-			})
-	})
+	win.AddDirty(&gi.DirtyFuncs{Name: "GoGi Detailed Preferences",
+		Dirty: func() bool { return pf.Changed },
+		Save:  pf.Save,
+		Discard: func() {
+			pf.Open() // if we don't do this, then it actually remains in edited state
+		}})
 
 	win.MainMenuUpdated()
 
@@ -0,0 +1,361 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Quantities
+
+// QuantityUnit is a unit of a QuantityKind
+type QuantityUnit struct {
+	Name    string   `desc:"name of the unit, as shown, e.g., MB"`
+	Size    float64  `desc:"size of the unit, in the base unit of the quantity (e.g., bytes)"`
+	Aliases []string `desc:"other names of the unit that are accepted in the input, in lower case -- the name is always accepted, in any case"`
+}
+
+// QuantityKind is a kind of quantity that is shown in humanized text,
+// e.g., a duration as 1h 23m, or a byte size as 4.2 MB, and parsed from
+// flexible input in any of its units, e.g., 90s or 1.5gb -- see
+// QuantityValueView.
+type QuantityKind struct {
+	Name    string                 `desc:"name of the kind of quantity, e.g., for error messages"`
+	Units   []QuantityUnit         `desc:"the units, in increasing size, which are offered in the unit dropdown"`
+	DefUnit int                    `desc:"index of the unit of a number without a unit in the input, if no unit is selected in the dropdown"`
+	Format  func(v float64) string `desc:"returns the humanized text for given value, in the base unit"`
+}
+
+// DurationQuantity is the QuantityKind for time.Duration values, in
+// nanoseconds, which are shown with the two largest units, e.g., 1h 23m
+var DurationQuantity = &QuantityKind{
+	Name:    "duration",
+	Units:   DurationUnits,
+	DefUnit: 3,
+	Format:  func(v float64) string { return HumanDuration(time.Duration(v)) },
+}
+
+// DurationUnits are the units of the DurationQuantity, in nanoseconds
+var DurationUnits = []QuantityUnit{
+	{"ns", 1, []string{"nsec", "nanosecond", "nanoseconds"}},
+	{"µs", 1e3, []string{"us", "usec", "microsecond", "microseconds"}},
+	{"ms", 1e6, []string{"msec", "millisecond", "milliseconds"}},
+	{"s", 1e9, []string{"sec", "secs", "second", "seconds"}},
+	{"min", 60e9, []string{"m", "mins", "minute", "minutes"}},
+	{"h", 3600e9, []string{"hr", "hrs", "hour", "hours"}},
+	{"d", 86400e9, []string{"day", "days"}},
+}
+
+// ByteSizeQuantity is the QuantityKind for byte sizes, using binary
+// units (1 KB = 1024 B), which are shown with one decimal, e.g., 4.2 MB
+var ByteSizeQuantity = &QuantityKind{
+	Name:    "byte size",
+	Units:   ByteSizeUnits,
+	DefUnit: 0,
+	Format:  func(v float64) string { return HumanBytes(int64(v)) },
+}
+
+// ByteSizeUnits are the units of the ByteSizeQuantity, in bytes
+var ByteSizeUnits = []QuantityUnit{
+	{"B", 1, []string{"byte", "bytes"}},
+	{"KB", 1 << 10, []string{"k", "kib"}},
+	{"MB", 1 << 20, []string{"m", "mib"}},
+	{"GB", 1 << 30, []string{"g", "gib"}},
+	{"TB", 1 << 40, []string{"t", "tib"}},
+	{"PB", 1 << 50, []string{"p", "pib"}},
+}
+
+// UnitIndex returns the index of the unit with given name or alias, in
+// any case, or -1 if there is none
+func (qk *QuantityKind) UnitIndex(name string) int {
+	lnm := strings.ToLower(name)
+	for i := range qk.Units {
+		u := &qk.Units[i]
+		if strings.ToLower(u.Name) == lnm {
+			return i
+		}
+		for _, al := range u.Aliases {
+			if al == lnm {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// UnitNames returns the names of the units
+func (qk *QuantityKind) UnitNames() []string {
+	nms := make([]string, len(qk.Units))
+	for i := range qk.Units {
+		nms[i] = qk.Units[i].Name
+	}
+	return nms
+}
+
+// Parse parses given input into a value in the base unit: a sequence of
+// numbers, each followed by a unit (e.g., 1h 23m, 90s, or 1.5gb), where
+// a number without a unit is in the unit with given index, optionally
+// starting with a sign.
+func (qk *QuantityKind) Parse(s string, defUnit int) (float64, error) {
+	str := strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(str, "-") {
+		neg = true
+		str = strings.TrimSpace(str[1:])
+	} else if strings.HasPrefix(str, "+") {
+		str = strings.TrimSpace(str[1:])
+	}
+	if str == "" {
+		return 0, fmt.Errorf("no %s in %q", qk.Name, s)
+	}
+	rs := []rune(str)
+	sum := 0.0
+	for i := 0; i < len(rs); {
+		st := i
+		for i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.') {
+			i++
+		}
+		if i == st {
+			return 0, fmt.Errorf("invalid %s %q: expected a number at %q", qk.Name, s, string(rs[st:]))
+		}
+		num, err := strconv.ParseFloat(string(rs[st:i]), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: bad number %q", qk.Name, s, string(rs[st:i]))
+		}
+		for i < len(rs) && unicode.IsSpace(rs[i]) {
+			i++
+		}
+		st = i
+		for i < len(rs) && unicode.IsLetter(rs[i]) {
+			i++
+		}
+		ui := defUnit
+		if i > st {
+			ui = qk.UnitIndex(string(rs[st:i]))
+			if ui < 0 {
+				return 0, fmt.Errorf("invalid %s %q: unknown unit %q -- use one of: %s", qk.Name, s, string(rs[st:i]), strings.Join(qk.UnitNames(), ", "))
+			}
+		}
+		sum += num * qk.Units[ui].Size
+		for i < len(rs) && (unicode.IsSpace(rs[i]) || rs[i] == ',') {
+			i++
+		}
+	}
+	if neg {
+		sum = -sum
+	}
+	return sum, nil
+}
+
+// FormatIn returns the text for given value in the base unit, as a number
+// in the unit with given index, or humanized text if it is out of range
+func (qk *QuantityKind) FormatIn(v float64, unit int) string {
+	if unit < 0 || unit >= len(qk.Units) {
+		return qk.Format(v)
+	}
+	return strconv.FormatFloat(v/qk.Units[unit].Size, 'g', 6, 64)
+}
+
+// HumanDuration returns humanized text for given duration, with its two
+// largest units, e.g., 1h 23m or 2d 4h -- durations below a minute are
+// in the largest unit, with up to 3 decimals, e.g., 1.5s or 250ms
+func HumanDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + HumanDuration(-d)
+	}
+	if d == 0 {
+		return "0s"
+	}
+	if d < time.Minute {
+		uts := DurationUnits[:4]
+		ui := len(uts) - 1
+		for ui > 0 && float64(d) < uts[ui].Size {
+			ui--
+		}
+		return strconv.FormatFloat(math.Round(1000*float64(d)/uts[ui].Size)/1000, 'f', -1, 64) + uts[ui].Name
+	}
+	type part struct {
+		n    time.Duration
+		unit string
+	}
+	dur := d.Round(time.Second)
+	parts := []part{{dur / (24 * time.Hour), "d"}, {dur / time.Hour % 24, "h"}, {dur / time.Minute % 60, "m"}, {dur / time.Second % 60, "s"}}
+	var strs []string
+	for _, p := range parts {
+		if len(strs) == 0 && p.n == 0 {
+			continue
+		}
+		if p.n != 0 {
+			strs = append(strs, fmt.Sprintf("%d%s", p.n, p.unit))
+		}
+		if len(strs) == 2 || (len(strs) == 1 && p.n == 0) {
+			break
+		}
+	}
+	return strings.Join(strs, " ")
+}
+
+// HumanBytes returns humanized text for given byte size, in the largest
+// binary unit with one decimal, e.g., 4.2 MB or 512 B
+func HumanBytes(n int64) string {
+	if n < 0 {
+		return "-" + HumanBytes(-n)
+	}
+	uts := ByteSizeUnits
+	ui := len(uts) - 1
+	for ui > 0 && float64(n) < uts[ui].Size {
+		ui--
+	}
+	if ui == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return strconv.FormatFloat(math.Round(10*float64(n)/uts[ui].Size)/10, 'f', -1, 64) + " " + uts[ui].Name
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  QuantityValueView
+
+// QuantityValueView presents a number in a QuantityKind, e.g., a
+// time.Duration or a byte size (datasize.ByteSize, or an int with a
+// `view:"bytes"` tag), in a text field with humanized text, which accepts
+// flexible input, and a dropdown for the unit in which it is shown, which
+// is also the unit of a number without a unit in the input -- auto for
+// humanized text.  A `unit` tag sets the initial unit.
+type QuantityValueView struct {
+	ValueViewBase
+	Kind *QuantityKind `desc:"kind of quantity"`
+	Unit int           `desc:"index of the unit in which the value is shown -- -1 for auto"`
+}
+
+var KiT_QuantityValueView = kit.Types.AddType(&QuantityValueView{}, nil)
+
+// QuantityAutoUnit is the name of the auto unit in the unit dropdown of a
+// QuantityValueView, for showing humanized text
+var QuantityAutoUnit = "auto"
+
+func (vv *QuantityValueView) WidgetType() reflect.Type {
+	vv.WidgetTyp = gi.KiT_Layout
+	return vv.WidgetTyp
+}
+
+// QuantityVal returns the value, in the base unit of the quantity
+func (vv *QuantityValueView) QuantityVal() float64 {
+	v := kit.NonPtrValue(vv.Value)
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	case v.CanFloat():
+		return v.Float()
+	}
+	return 0
+}
+
+// SetQuantityVal sets the value from given value in the base unit of the
+// quantity, rounded to an integer if needed
+func (vv *QuantityValueView) SetQuantityVal(qv float64) bool {
+	v := kit.NonPtrValue(vv.Value)
+	switch {
+	case v.CanInt():
+		return vv.SetValue(int64(math.Round(qv)))
+	case v.CanUint():
+		if qv < 0 {
+			return false
+		}
+		return vv.SetValue(uint64(math.Round(qv)))
+	}
+	return vv.SetValue(qv)
+}
+
+// TextField returns the text field of the widget
+func (vv *QuantityValueView) TextField() *gi.TextField {
+	return vv.Widget.(*gi.Layout).ChildByName("text", 0).(*gi.TextField)
+}
+
+// UnitComboBox returns the unit dropdown of the widget
+func (vv *QuantityValueView) UnitComboBox() *gi.ComboBox {
+	return vv.Widget.(*gi.Layout).ChildByName("unit", 1).(*gi.ComboBox)
+}
+
+func (vv *QuantityValueView) UpdateWidget() {
+	if vv.Widget == nil {
+		return
+	}
+	tf := vv.TextField()
+	tf.SetText(vv.Kind.FormatIn(vv.QuantityVal(), vv.Unit))
+	tf.SetErrorText("")
+	vv.UnitComboBox().SetCurIndex(vv.Unit + 1)
+}
+
+func (vv *QuantityValueView) ConfigWidget(widg gi.Node2D) {
+	vv.Widget = widg
+	vv.StdConfigWidget(widg)
+	if vv.Kind == nil {
+		vv.Kind = ByteSizeQuantity
+	}
+	vv.Unit = -1
+	if ut, has := vv.Tag("unit"); has {
+		vv.Unit = vv.Kind.UnitIndex(ut)
+	}
+	ly := vv.Widget.(*gi.Layout)
+	ly.Lay = gi.LayoutHoriz
+	ly.SetProp("spacing", units.NewPx(2))
+	ly.SetProp("vertical-align", gist.AlignMiddle)
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_TextField, "text")
+	config.Add(gi.KiT_ComboBox, "unit")
+	ly.ConfigChildren(config)
+	inact := vv.This().(ValueView).IsInactive()
+
+	tf := vv.TextField()
+	tf.Tooltip, _ = vv.Tag("desc")
+	tf.SetInactiveState(inact)
+	tf.SetProp("min-width", units.NewCh(10))
+	tf.SetStretchMaxWidth()
+	tf.TextFieldSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(gi.TextFieldDone) || sig == int64(gi.TextFieldDeFocused) {
+			vvv, _ := recv.Embed(KiT_QuantityValueView).(*QuantityValueView)
+			tf := send.(*gi.TextField)
+			du := vvv.Unit
+			if du < 0 {
+				du = vvv.Kind.DefUnit
+			}
+			qv, err := vvv.Kind.Parse(tf.Text(), du)
+			if err != nil {
+				tf.SetErrorText(err.Error())
+				return
+			}
+			if vvv.SetQuantityVal(qv) {
+				vvv.UpdateWidget()
+			}
+		}
+	})
+
+	cb := vv.UnitComboBox()
+	cb.Tooltip = "unit in which the value is shown, and of a number entered without a unit -- " + QuantityAutoUnit + " shows it in the largest units"
+	cb.SetProp("padding", units.NewPx(2))
+	cb.SetProp("margin", units.NewPx(2))
+	cb.ItemsFromStringList(append([]string{QuantityAutoUnit}, vv.Kind.UnitNames()...), false, 0)
+	cb.ComboSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		vvv, _ := recv.Embed(KiT_QuantityValueView).(*QuantityValueView)
+		vvv.Unit = int(sig) - 1
+		vvv.UpdateWidget()
+	})
+	vv.UpdateWidget()
+}
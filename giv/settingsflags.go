@@ -0,0 +1,254 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/kit"
+	"github.com/iancoleman/strcase"
+)
+
+// SettingsFlags exposes the fields of a settings / config struct, as edited
+// in a StructView (e.g., gi.Prefs), as command-line flags: each field that
+// is shown in the view (not view:"-"), of a basic kind, enum, color,
+// units.Value, time.Duration, or a type that implements flag.Value or
+// encoding.TextUnmarshaler, is a flag, named by the kebab-case path of the
+// field, e.g., -font-family, -params.big-file-size -- sub-structs are
+// followed, and the name of a field can be set with a flag:"name" tag, or
+// flag:"-" to skip it.  The usage of the flag is the desc tag of the field.
+//
+// The flags set the fields of the struct itself, so the GUI shows the
+// values given on the command line.  The precedence is defaults < settings
+// file < flags: the flags are parsed after opening the settings file (see
+// Parse), and the values given on the command line are re-applied if it is
+// opened again (see Apply).
+type SettingsFlags struct {
+	Obj     any               `desc:"the settings struct, as a pointer"`
+	Prefix  string            `desc:"prefix of the flag names, e.g., the name of the settings followed by a ., to distinguish those of several settings structs, or empty"`
+	FlagSet *flag.FlagSet     `desc:"the flag set that the flags are defined in"`
+	Set     map[string]string `desc:"values given on the command line, by flag name"`
+	Names   []string          `desc:"names of all the flags, in field order"`
+}
+
+// NewSettingsFlags defines flags in given flag set (flag.CommandLine if
+// nil) for the fields of given settings struct (a pointer), with names
+// starting with given prefix (can be empty) -- this must be done before
+// parsing the flags, e.g., at the start of main.
+func NewSettingsFlags(obj any, prefix string, fset *flag.FlagSet) *SettingsFlags {
+	if fset == nil {
+		fset = flag.CommandLine
+	}
+	sf := &SettingsFlags{Obj: obj, Prefix: prefix, FlagSet: fset, Set: map[string]string{}}
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		log.Printf("giv.NewSettingsFlags: settings must be a pointer to a struct, not: %T\n", obj)
+		return sf
+	}
+	sf.addFields(v.Elem(), prefix)
+	return sf
+}
+
+// addFields adds the flags for the fields of given struct value, with
+// names starting with given prefix
+func (sf *SettingsFlags) addFields(v reflect.Value, prefix string) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("view") == "-" || f.Tag.Get("json") == "-" {
+			continue
+		}
+		nm := f.Tag.Get("flag")
+		if nm == "-" {
+			continue
+		}
+		if nm == "" {
+			nm = strcase.ToKebab(f.Name)
+		}
+		nm = prefix + nm
+		fv := v.Field(i)
+		sv := &settingsValue{sf: sf, name: nm, val: fv}
+		if !sv.settable() {
+			if f.Type.Kind() == reflect.Struct {
+				sf.addFields(fv, nm+".")
+			}
+			continue
+		}
+		if sf.FlagSet.Lookup(nm) != nil {
+			log.Printf("giv.NewSettingsFlags: flag -%s already defined, not adding field: %v\n", nm, f.Name)
+			continue
+		}
+		usage := f.Tag.Get("desc")
+		if kit.Enums.TypeRegistered(f.Type) {
+			usage = strings.TrimSpace(usage + " (" + strings.Join(settingsEnumNames(f.Type), ", ") + ")")
+		}
+		sf.FlagSet.Var(sv, nm, usage)
+		sf.Names = append(sf.Names, nm)
+	}
+}
+
+// settingsEnumNames returns the names of the values of given enum type,
+// as accepted for its flag
+func settingsEnumNames(typ reflect.Type) []string {
+	vals := kit.Enums.TypeValues(typ, true)
+	nms := make([]string, len(vals))
+	for i, ev := range vals {
+		nms[i] = ev.Name
+	}
+	return nms
+}
+
+// Parse opens the settings with given function (e.g., the Open method of
+// the settings, which reads the settings file over the defaults -- a
+// missing file is not an error, and it can be nil), and then parses the
+// flags of the flag set from given arguments (e.g., os.Args[1:]), so that
+// the flags take precedence over the file.  If the flag set was already
+// parsed (e.g., for another settings struct in the same flag set), the
+// values given on the command line are re-applied instead.  Any changes
+// must then be applied as for any other change of the settings, e.g.,
+// gi.Prefs.Apply().
+func (sf *SettingsFlags) Parse(args []string, open func() error) error {
+	if open != nil {
+		if err := open(); err != nil && !os.IsNotExist(err) {
+			log.Printf("giv.SettingsFlags Parse: error opening settings: %v\n", err)
+		}
+	}
+	if sf.FlagSet.Parsed() {
+		return sf.Apply()
+	}
+	return sf.FlagSet.Parse(args)
+}
+
+// Apply sets the fields of the settings to the values given on the command
+// line again, e.g., after opening the settings file again, so that they
+// still take precedence over it
+func (sf *SettingsFlags) Apply() error {
+	nms := make([]string, 0, len(sf.Set))
+	for nm := range sf.Set {
+		nms = append(nms, nm)
+	}
+	sort.Strings(nms)
+	var errs []string
+	for _, nm := range nms {
+		sv := sf.FlagSet.Lookup(nm).Value.(*settingsValue)
+		if err := sv.setValue(sf.Set[nm]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("giv.SettingsFlags Apply: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// IsSet returns true if the flag with given name was given on the command
+// line, e.g., to show that it overrides the settings file
+func (sf *SettingsFlags) IsSet(name string) bool {
+	_, has := sf.Set[name]
+	return has
+}
+
+// settingsValue is the flag.Value of one field of the settings
+type settingsValue struct {
+	sf   *SettingsFlags
+	name string
+	val  reflect.Value
+}
+
+// settable returns true if the field can be set from a flag
+func (sv *settingsValue) settable() bool {
+	if !sv.val.CanAddr() {
+		return false
+	}
+	switch sv.val.Addr().Interface().(type) {
+	case flag.Value, encoding.TextUnmarshaler, *gist.Color, *units.Value, *time.Duration:
+		return true
+	}
+	vk := sv.val.Kind()
+	return vk == reflect.Bool || vk == reflect.String || (vk >= reflect.Int && vk <= reflect.Float64)
+}
+
+func (sv *settingsValue) String() string {
+	if sv == nil || !sv.val.IsValid() {
+		return ""
+	}
+	if kit.Enums.TypeRegistered(sv.val.Type()) {
+		return kit.Enums.EnumIfaceToAltString(sv.val.Interface())
+	}
+	switch p := sv.val.Addr().Interface().(type) {
+	case flag.Value:
+		return p.String()
+	case encoding.TextMarshaler:
+		b, _ := p.MarshalText()
+		return string(b)
+	case fmt.Stringer:
+		return p.String()
+	}
+	return kit.ToString(sv.val.Interface())
+}
+
+// IsBoolFlag allows boolean flags to be given without a value
+func (sv *settingsValue) IsBoolFlag() bool {
+	return sv.val.Kind() == reflect.Bool
+}
+
+// Set sets the field from a flag given on the command line, recording it
+// to re-apply it with SettingsFlags.Apply
+func (sv *settingsValue) Set(s string) error {
+	if err := sv.setValue(s); err != nil {
+		return err
+	}
+	sv.sf.Set[sv.name] = s
+	return nil
+}
+
+// setValue sets the field from given string
+func (sv *settingsValue) setValue(s string) error {
+	switch p := sv.val.Addr().Interface().(type) {
+	case flag.Value:
+		return p.Set(s)
+	case encoding.TextUnmarshaler:
+		return p.UnmarshalText([]byte(s))
+	case *gist.Color:
+		return p.SetString(s, nil)
+	case *units.Value:
+		p.SetString(s)
+		return nil
+	case *time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*p = d
+		return nil
+	}
+	if kit.Enums.TypeRegistered(sv.val.Type()) {
+		if kit.Enums.IsBitFlag(sv.val.Type()) {
+			return kit.Enums.SetAnyEnumValueFromString(sv.val.Addr(), s)
+		}
+		if kit.Enums.SetEnumValueFromAltString(sv.val.Addr(), s) == nil {
+			return nil
+		}
+		// kit ignores the error of FromString for invalid names
+		if fs, ok := sv.val.Addr().Interface().(interface{ FromString(s string) error }); ok {
+			return fs.FromString(s)
+		}
+		return fmt.Errorf("invalid value for %v: %q", sv.val.Type(), s)
+	}
+	if !kit.SetRobust(sv.val.Addr().Interface(), s) {
+		return fmt.Errorf("invalid value for %v: %q", sv.val.Type(), s)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goki/gi/gist"
+)
+
+type testSettingsParams struct {
+	BigFileSize int    `desc:"size of big files"`
+	Mode        string `flag:"params-mode"`
+}
+
+type testSettings struct {
+	FontFamily string          `desc:"font family"`
+	FontStyle  gist.FontStyles `desc:"font style"`
+	Zoom       float32         `desc:"zoom factor"`
+	Hidden     string          `view:"-"`
+	NoFlag     int             `flag:"-"`
+	Verbose    bool            `flag:"v"`
+	Delay      time.Duration
+	Params     testSettingsParams
+	private    int
+}
+
+func TestSettingsFlagsNames(t *testing.T) {
+	tests := []struct {
+		prefix string
+		names  string
+	}{
+		{"", "font-family font-style zoom v delay params.big-file-size params.params-mode"},
+		{"app.", "app.font-family app.font-style app.zoom app.v app.delay app.params.big-file-size app.params.params-mode"},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		sf := NewSettingsFlags(&testSettings{}, tt.prefix, fs)
+		if got := strings.Join(sf.Names, " "); got != tt.names {
+			t.Errorf("prefix %q: names: got %q, want %q", tt.prefix, got, tt.names)
+		}
+		for _, nm := range []string{"hidden", "no-flag", "private", "verbose"} {
+			if fs.Lookup(tt.prefix+nm) != nil {
+				t.Errorf("prefix %q: flag %q should not be defined", tt.prefix, tt.prefix+nm)
+			}
+		}
+		if fl := fs.Lookup(tt.prefix + "font-style"); fl == nil || !strings.Contains(fl.Usage, "italic") {
+			t.Errorf("prefix %q: font-style usage does not list the enum values: %v", tt.prefix, fl)
+		}
+	}
+}
+
+func TestSettingsFlagsParse(t *testing.T) {
+	st := &testSettings{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	sf := NewSettingsFlags(st, "", fs)
+	opens := 0
+	open := func() error { // the settings file, over the defaults
+		opens++
+		st.FontFamily = "file-font"
+		st.Zoom = 2
+		st.FontStyle = gist.FontOblique
+		return nil
+	}
+	args := []string{"-font-family", "flag-font", "-font-style=italic", "-v", "-delay", "1.5s", "-params.big-file-size", "1000"}
+	if err := sf.Parse(args, open); err != nil {
+		t.Fatal(err)
+	}
+	check := func(when string) {
+		if st.FontFamily != "flag-font" || st.FontStyle != gist.FontItalic || !st.Verbose || st.Delay != 1500*time.Millisecond || st.Params.BigFileSize != 1000 {
+			t.Errorf("%s: flags not applied: %+v", when, st)
+		}
+		if st.Zoom != 2 {
+			t.Errorf("%s: zoom from the file: got %v, want 2", when, st.Zoom)
+		}
+	}
+	check("parse")
+	if !sf.IsSet("font-style") || sf.IsSet("zoom") {
+		t.Errorf("IsSet: font-style: %v, zoom: %v", sf.IsSet("font-style"), sf.IsSet("zoom"))
+	}
+
+	// reopening the file and re-applying keeps file < flags
+	open()
+	if err := sf.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	check("apply")
+
+	// parsing again, e.g., for other settings, re-applies over the file
+	if err := sf.Parse(nil, open); err != nil {
+		t.Fatal(err)
+	}
+	check("reparse")
+	if opens != 3 {
+		t.Errorf("settings opened %d times, want 3", opens)
+	}
+
+	fs2 := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs2.SetOutput(io.Discard)
+	sf2 := NewSettingsFlags(&testSettings{}, "", fs2)
+	if err := sf2.Parse([]string{"-font-style=bogus"}, nil); err == nil {
+		t.Errorf("invalid enum value: no error")
+	}
+}
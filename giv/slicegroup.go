@@ -0,0 +1,339 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Grouping
+
+// SliceGroup is a group of the elements of the slice shown in a
+// SliceView or TableView with a GroupBy function: all the elements with
+// the same group name, shown below a header with the name and count,
+// which can be collapsed to hide them.
+type SliceGroup struct {
+	Name      string `desc:"name of the group, returned by GroupBy"`
+	Idxs      []int  `desc:"slice indexes of the elements in the group, in order"`
+	Collapsed bool   `desc:"whether the elements are hidden"`
+}
+
+// SliceGroupRow is a display row of a grouped SliceView or TableView:
+// either the header of a group, or an element of it
+type SliceGroupRow struct {
+	Group int `desc:"index of the group in Groups"`
+	Idx   int `desc:"slice index of the element, or -1 for the header of the group"`
+}
+
+// SetGroupBy sets the GroupBy function, which returns the name of the
+// group of the element at given slice index, and updates the display
+// -- the groups are shown in the order of their first element, and nil
+// turns off grouping.
+func (sv *SliceViewBase) SetGroupBy(fun func(idx int) string) {
+	sv.GroupBy = fun
+	sv.StartIdx = 0
+	sv.Update()
+}
+
+// IsGrouped returns true if the elements are shown in groups (GroupBy is set)
+func (sv *SliceViewBase) IsGrouped() bool {
+	return sv.GroupBy != nil
+}
+
+// UpdateGroups updates the Groups and GroupRows from GroupBy, for the
+// current SliceSize, keeping the Page in range and only grouping the
+// elements on it if paged -- must be protected by mutex
+func (sv *SliceViewBase) UpdateGroups() {
+	sv.clampPage()
+	sv.Groups = nil
+	sv.GroupRows = nil
+	sv.groupOrder = nil
+	sv.groupPos = nil
+	sv.idxDisp = nil
+	sv.idxGroup = nil
+	if sv.GroupBy == nil {
+		return
+	}
+	ps := sv.PageStart()
+	pe := ps + sv.PageRows()
+	gmap := make(map[string]int)
+	for i := ps; i < pe; i++ {
+		nm := sv.GroupBy(i)
+		g, has := gmap[nm]
+		if !has {
+			g = len(sv.Groups)
+			gmap[nm] = g
+			sv.Groups = append(sv.Groups, &SliceGroup{Name: nm, Collapsed: sv.CollapsedGroups[nm]})
+		}
+		sv.Groups[g].Idxs = append(sv.Groups[g].Idxs, i)
+	}
+	sv.groupOrder = make([]int, 0, pe-ps)
+	sv.groupPos = make([]int, sv.SliceSize)
+	sv.idxDisp = make([]int, sv.SliceSize)
+	sv.idxGroup = make([]int, sv.SliceSize)
+	for i := range sv.idxDisp { // not on the page
+		sv.groupPos[i] = -1
+		sv.idxDisp[i] = -1
+		sv.idxGroup[i] = -1
+	}
+	for g, gp := range sv.Groups {
+		sv.GroupRows = append(sv.GroupRows, SliceGroupRow{Group: g, Idx: -1})
+		for _, si := range gp.Idxs {
+			sv.idxGroup[si] = g
+			sv.groupPos[si] = len(sv.groupOrder)
+			sv.groupOrder = append(sv.groupOrder, si)
+			if gp.Collapsed {
+				sv.idxDisp[si] = -1
+				continue
+			}
+			sv.idxDisp[si] = len(sv.GroupRows)
+			sv.GroupRows = append(sv.GroupRows, SliceGroupRow{Group: g, Idx: si})
+		}
+	}
+}
+
+// DispSize returns the total number of rows to display: the SliceSize,
+// or the number of elements on the current page if paged, or, if grouped,
+// the number of group headers and elements of expanded groups
+func (sv *SliceViewBase) DispSize() int {
+	if sv.IsGrouped() {
+		return len(sv.GroupRows)
+	}
+	return sv.PageRows()
+}
+
+// RowIdx returns the slice index of the element shown in given display
+// row, or -1 if it is out of range or the header of a group
+func (sv *SliceViewBase) RowIdx(row int) int {
+	d := sv.StartIdx + row
+	if d < 0 || d >= sv.DispSize() {
+		return -1
+	}
+	if sv.IsGrouped() {
+		return sv.GroupRows[d].Idx
+	}
+	return sv.PageStart() + d
+}
+
+// RowGroup returns the index in Groups of the group whose header is
+// shown in given display row, or -1 if it is not a group header
+func (sv *SliceViewBase) RowGroup(row int) int {
+	d := sv.StartIdx + row
+	if !sv.IsGrouped() || d < 0 || d >= len(sv.GroupRows) || sv.GroupRows[d].Idx >= 0 {
+		return -1
+	}
+	return sv.GroupRows[d].Group
+}
+
+// IdxDisp returns the index among all the display rows (not just the
+// visible ones, see IdxRow) of given slice index, or -1 if it is in a
+// collapsed group or not on the current page
+func (sv *SliceViewBase) IdxDisp(idx int) int {
+	if !sv.IsGrouped() {
+		if !sv.Paged {
+			return idx
+		}
+		d := idx - sv.PageStart()
+		if d < 0 || d >= sv.PageRows() {
+			return -1
+		}
+		return d
+	}
+	if idx < 0 || idx >= len(sv.idxDisp) {
+		return -1
+	}
+	return sv.idxDisp[idx]
+}
+
+// IdxRow returns the display row of given slice index, which is out of
+// the range of the visible rows if it is not visible (-1 if it is in a
+// collapsed group or not on the current page)
+func (sv *SliceViewBase) IdxRow(idx int) int {
+	d := sv.IdxDisp(idx)
+	if d < 0 {
+		return -1
+	}
+	return d - sv.StartIdx
+}
+
+// IdxGroup returns the index in Groups of the group of given slice
+// index, or -1 if not grouped
+func (sv *SliceViewBase) IdxGroup(idx int) int {
+	if !sv.IsGrouped() || idx < 0 || idx >= len(sv.idxGroup) {
+		return -1
+	}
+	return sv.idxGroup[idx]
+}
+
+// VisIdxStep returns the slice index of the element n display rows from
+// given slice index (down for n > 0, up for n < 0), stopping at the first
+// or last element (of the current page if paged), and skipping group
+// headers and collapsed groups, so that keyboard navigation moves through
+// the visible elements in display order -- returns -1 if no elements are
+// visible
+func (sv *SliceViewBase) VisIdxStep(idx, n int) int {
+	if !sv.IsGrouped() {
+		ps := sv.PageStart()
+		pr := sv.PageRows()
+		if pr == 0 {
+			return -1
+		}
+		if idx < ps || idx >= ps+pr { // e.g., none selected on this page
+			if n > 0 {
+				return ps
+			}
+			return ps + pr - 1
+		}
+		return ints.MinInt(ints.MaxInt(idx+n, ps), ps+pr-1)
+	}
+	ord := sv.groupOrder
+	pos := -1
+	last := -1
+	if idx >= 0 && idx < len(sv.groupPos) && sv.groupPos[idx] >= 0 {
+		pos = sv.groupPos[idx]
+		if sv.idxDisp[idx] >= 0 {
+			last = idx
+		}
+	}
+	dir := 1
+	if n < 0 {
+		dir = -1
+		n = -n
+		if pos < 0 {
+			pos = len(ord)
+		}
+	}
+	for p := pos + dir; p >= 0 && p < len(ord) && n > 0; p += dir {
+		if si := ord[p]; sv.idxDisp[si] >= 0 {
+			last = si
+			n--
+		}
+	}
+	return last
+}
+
+// SetGroupCollapsed sets whether the group with given name is collapsed,
+// hiding its elements, and updates the display -- the selection is kept.
+func (sv *SliceViewBase) SetGroupCollapsed(name string, collapsed bool) {
+	if sv.CollapsedGroups == nil {
+		sv.CollapsedGroups = make(map[string]bool)
+	}
+	if collapsed {
+		sv.CollapsedGroups[name] = true
+	} else {
+		delete(sv.CollapsedGroups, name)
+	}
+	sv.Update()
+}
+
+// ToggleGroup toggles whether the group with given name is collapsed
+func (sv *SliceViewBase) ToggleGroup(name string) {
+	sv.SetGroupCollapsed(name, !sv.CollapsedGroups[name])
+}
+
+// expandIdxGroup expands the group of given slice index if it is
+// collapsed, updating the groups but not the display -- returns true if so
+func (sv *SliceViewBase) expandIdxGroup(idx int) bool {
+	if sv.IdxDisp(idx) >= 0 {
+		return false
+	}
+	g := sv.IdxGroup(idx)
+	if g < 0 {
+		return false
+	}
+	delete(sv.CollapsedGroups, sv.Groups[g].Name)
+	sv.UpdateGroups()
+	return true
+}
+
+// deleteGridCell deletes the widget at given index in the slice grid,
+// leaving a nil in its place to be configured again
+func deleteGridCell(sg *gi.Frame, cidx int) {
+	kid := sg.Kids[cidx]
+	if kid == nil {
+		return
+	}
+	kid.SetFlag(int(ki.NodeDeleted))
+	kid.NodeSignal().Emit(kid, int64(ki.NodeSignalDeleting), nil)
+	ki.SetParent(kid, nil)
+	ki.DelMgr.Add(kid)
+	ki.UpdateReset(kid)
+	sg.Kids[cidx] = nil
+}
+
+// isGroupCell returns true if given slice grid cell is part of a group header row
+func isGroupCell(kid ki.Ki) bool {
+	if kid == nil {
+		return false
+	}
+	_, err := kid.PropTry("slv-group")
+	return err == nil
+}
+
+// ClearGroupRow deletes the widgets of given display row of the slice
+// grid if they are for a group header, so that the widgets for an element
+// can be configured in their place
+func (sv *SliceViewBase) ClearGroupRow(sg *gi.Frame, row, nWidgPerRow int) {
+	ridx := row * nWidgPerRow
+	if !isGroupCell(sg.Kids[ridx]) {
+		return
+	}
+	for c := 0; c < nWidgPerRow; c++ {
+		deleteGridCell(sg, ridx+c)
+	}
+}
+
+// ConfigGroupRow configures given display row of the slice grid as the
+// header of given group: an action showing its name and number of
+// elements, which toggles whether it is collapsed, in the first value
+// column, and empty labels in the others -- vals are the ValueViews
+// for the row, which are reset as their widgets are deleted.
+func (sv *SliceViewBase) ConfigGroupRow(sg *gi.Frame, row, group, nWidgPerRow, idxOff int, vals []ValueView) {
+	ridx := row * nWidgPerRow
+	if !isGroupCell(sg.Kids[ridx]) {
+		for c := 0; c < nWidgPerRow; c++ {
+			deleteGridCell(sg, ridx+c)
+		}
+		for i := range vals {
+			vals[i] = nil
+		}
+	}
+	itxt := fmt.Sprintf("%05d", row)
+	for c := 0; c < nWidgPerRow; c++ {
+		if sg.Kids[ridx+c] != nil {
+			continue
+		}
+		if c == idxOff {
+			ac := &gi.Action{}
+			sg.SetChild(ac, ridx+c, "group-"+itxt)
+			ac.SetProp("slv-group", true)
+			ac.SetProp("horizontal-align", "left")
+			ac.Sty.Template = "giv.SliceViewBase.GroupAction"
+			ac.ActionSig.ConnectOnly(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+				svv := recv.Embed(KiT_SliceViewBase).(*SliceViewBase)
+				svv.ToggleGroup(send.(*gi.Action).Data.(string))
+			})
+			continue
+		}
+		lb := &gi.Label{}
+		sg.SetChild(lb, ridx+c, fmt.Sprintf("group-%v-%d", itxt, c))
+		lb.SetProp("slv-group", true)
+	}
+	gp := sv.Groups[group]
+	ac := sg.Kids[ridx+idxOff].(*gi.Action)
+	ac.Data = gp.Name
+	icnm := "wedge-down"
+	if gp.Collapsed {
+		icnm = "wedge-right"
+	}
+	ac.SetIcon(icnm)
+	ac.SetText(fmt.Sprintf("%s (%d)", gp.Name, len(gp.Idxs)))
+	ac.Tooltip = "click to collapse or expand this group"
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Paging
+
+// SlicePageSizes are the page sizes offered in the pager of a paged
+// SliceView or TableView
+var SlicePageSizes = []int{25, 50, 100, 250, 500, 1000}
+
+// SlicePageSizeDefault is the PageSize used when paging is turned on
+// without one being set
+var SlicePageSizeDefault = 50
+
+// SetPaged turns on or off the paged mode, in which only one page of
+// PageSize elements is shown at a time, with a pager below the grid to
+// move among the pages and set the page size, instead of scrolling
+// through all of them -- starts at the first page.
+func (sv *SliceViewBase) SetPaged(paged bool) {
+	sv.Paged = paged
+	if sv.PageSize <= 0 {
+		sv.PageSize = SlicePageSizeDefault
+	}
+	sv.Page = 0
+	sv.StartIdx = 0
+	if !sv.IsConfiged() {
+		return
+	}
+	sv.This().(SliceViewer).Config()
+	sv.Update()
+}
+
+// SetTotalRows sets the TotalRows for lazily fetched pages, where the
+// Slice only holds the elements of the current Page, which must be
+// replaced on each SliceViewPageChanged signal, and updates the display
+// -- 0 means that the Slice holds all of the elements.
+func (sv *SliceViewBase) SetTotalRows(n int) {
+	sv.TotalRows = n
+	sv.Update()
+}
+
+// IsLazyPaged returns true if the Slice only holds the elements of the
+// current page (paged with TotalRows set)
+func (sv *SliceViewBase) IsLazyPaged() bool {
+	return sv.Paged && sv.TotalRows > 0
+}
+
+// TotalSize returns the total number of elements over all pages: the
+// TotalRows if lazily paged, and otherwise the SliceSize
+func (sv *SliceViewBase) TotalSize() int {
+	if sv.IsLazyPaged() {
+		return sv.TotalRows
+	}
+	return sv.SliceSize
+}
+
+// NPages returns the number of pages (always at least 1), or 1 if not paged
+func (sv *SliceViewBase) NPages() int {
+	if !sv.Paged || sv.PageSize <= 0 {
+		return 1
+	}
+	return ints.MaxInt(1, (sv.TotalSize()+sv.PageSize-1)/sv.PageSize)
+}
+
+// PageStart returns the slice index of the first element of the current
+// page: 0 if not paged or lazily paged
+func (sv *SliceViewBase) PageStart() int {
+	if !sv.Paged || sv.IsLazyPaged() {
+		return 0
+	}
+	return sv.Page * sv.PageSize
+}
+
+// PageRows returns the number of elements on the current page, or the
+// SliceSize if not paged
+func (sv *SliceViewBase) PageRows() int {
+	if !sv.Paged {
+		return sv.SliceSize
+	}
+	ps := sv.PageStart()
+	return ints.MaxInt(0, ints.MinInt(sv.PageSize, sv.SliceSize-ps))
+}
+
+// clampPage keeps the Page within the current number of pages
+func (sv *SliceViewBase) clampPage() {
+	sv.Page = ints.MaxInt(0, ints.MinInt(sv.Page, sv.NPages()-1))
+}
+
+// SetPage moves to given page (0-based, clamped to the range of pages),
+// emitting SliceViewPageChanged with the page as data before updating the
+// display, so that a lazily paged Slice can be filled with its elements.
+// The selection is reset if lazily paged, as the slice indexes then refer
+// to different elements.  Does nothing if not paged or already on it.
+func (sv *SliceViewBase) SetPage(page int) {
+	if !sv.Paged {
+		return
+	}
+	page = ints.MaxInt(0, ints.MinInt(page, sv.NPages()-1))
+	if page == sv.Page {
+		return
+	}
+	sv.setPage(page)
+	sv.Update()
+}
+
+// setPage sets the Page and emits SliceViewPageChanged, without updating
+func (sv *SliceViewBase) setPage(page int) {
+	sv.Page = page
+	sv.StartIdx = 0
+	if sv.IsLazyPaged() {
+		sv.SelectedIdx = -1
+		sv.ResetSelectedIdxs()
+	}
+	sv.SliceViewSig.Emit(sv.This(), int64(SliceViewPageChanged), page)
+}
+
+// FirstPage moves to the first page
+func (sv *SliceViewBase) FirstPage() {
+	sv.SetPage(0)
+}
+
+// PrevPage moves to the previous page
+func (sv *SliceViewBase) PrevPage() {
+	sv.SetPage(sv.Page - 1)
+}
+
+// NextPage moves to the next page
+func (sv *SliceViewBase) NextPage() {
+	sv.SetPage(sv.Page + 1)
+}
+
+// LastPage moves to the last page
+func (sv *SliceViewBase) LastPage() {
+	sv.SetPage(sv.NPages() - 1)
+}
+
+// SetPageSize sets the number of elements per page, moving to the page
+// that has the first element of the current one, and emitting
+// SliceViewPageChanged if that changes the page, or if lazily paged,
+// where the elements of the page change even if it does not.
+func (sv *SliceViewBase) SetPageSize(size int) {
+	if size <= 0 || size == sv.PageSize {
+		return
+	}
+	first := sv.Page * sv.PageSize
+	sv.PageSize = size
+	if !sv.Paged {
+		return
+	}
+	page := first / size
+	if page != sv.Page || sv.IsLazyPaged() {
+		sv.setPage(page)
+	}
+	sv.StartIdx = 0
+	sv.Update()
+}
+
+// pageToIdx moves to the page with given slice index if it is on another
+// one, without updating the display -- returns true if so.  Only possible
+// if not lazily paged.
+func (sv *SliceViewBase) pageToIdx(idx int) bool {
+	if !sv.Paged || sv.IsLazyPaged() || sv.PageSize <= 0 || idx < 0 || idx >= sv.SliceSize {
+		return false
+	}
+	page := idx / sv.PageSize
+	if page == sv.Page {
+		return false
+	}
+	sv.setPage(page)
+	sv.UpdateGroups()
+	sv.DispRows = ints.MinInt(sv.DispSize(), sv.VisRows)
+	return true
+}
+
+// Pager returns the pager toolbar below the grid, or nil if not paged
+func (sv *SliceViewBase) Pager() *gi.ToolBar {
+	pi := sv.ChildByName("pager", 2)
+	if pi == nil {
+		return nil
+	}
+	return pi.(*gi.ToolBar)
+}
+
+// ConfigPager configures the pager toolbar, if paged: actions to move to
+// the first, previous, next and last pages around the current page
+// number, the page size chooser, and the range of elements shown
+func (sv *SliceViewBase) ConfigPager() {
+	pg := sv.Pager()
+	if pg == nil || pg.HasChildren() {
+		return
+	}
+	pg.SetStretchMaxWidth()
+	pg.AddAction(gi.ActOpts{Name: "first", Icon: "fast-bkwd", Tooltip: "first page"},
+		sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+			recv.Embed(KiT_SliceViewBase).(*SliceViewBase).FirstPage()
+		})
+	pg.AddAction(gi.ActOpts{Name: "prev", Icon: "step-bkwd", Tooltip: "previous page"},
+		sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+			recv.Embed(KiT_SliceViewBase).(*SliceViewBase).PrevPage()
+		})
+	gi.AddNewLabel(pg, "page", "")
+	pg.AddAction(gi.ActOpts{Name: "next", Icon: "step-fwd", Tooltip: "next page"},
+		sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+			recv.Embed(KiT_SliceViewBase).(*SliceViewBase).NextPage()
+		})
+	pg.AddAction(gi.ActOpts{Name: "last", Icon: "fast-fwd", Tooltip: "last page"},
+		sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+			recv.Embed(KiT_SliceViewBase).(*SliceViewBase).LastPage()
+		})
+	pg.AddSeparator("sep")
+	gi.AddNewLabel(pg, "size-lbl", "Per page:")
+	cb := gi.AddNewComboBox(pg, "size")
+	cb.Tooltip = "number of rows per page"
+	cb.Items = make([]any, len(SlicePageSizes))
+	for i, sz := range SlicePageSizes {
+		cb.Items[i] = sz
+	}
+	cb.ComboSig.ConnectOnly(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		svv := recv.Embed(KiT_SliceViewBase).(*SliceViewBase)
+		if sz, ok := send.(*gi.ComboBox).CurVal.(int); ok {
+			svv.SetPageSize(sz)
+		}
+	})
+	gi.AddNewStretch(pg, "stretch")
+	gi.AddNewLabel(pg, "rows", "")
+}
+
+// UpdatePager updates the page number, page size and range of elements
+// shown in the pager, and which of its actions are active
+func (sv *SliceViewBase) UpdatePager() {
+	pg := sv.Pager()
+	if pg == nil || !pg.HasChildren() {
+		return
+	}
+	np := sv.NPages()
+	pg.ChildByName("page", 2).(*gi.Label).SetText(fmt.Sprintf("Page %d of %d", sv.Page+1, np))
+	pg.ChildByName("first", 0).(*gi.Action).SetInactiveState(sv.Page == 0)
+	pg.ChildByName("prev", 1).(*gi.Action).SetInactiveState(sv.Page == 0)
+	pg.ChildByName("next", 3).(*gi.Action).SetInactiveState(sv.Page >= np-1)
+	pg.ChildByName("last", 4).(*gi.Action).SetInactiveState(sv.Page >= np-1)
+	cb := pg.ChildByName("size", 7).(*gi.ComboBox)
+	if cb.CurVal != sv.PageSize {
+		cb.SetCurVal(sv.PageSize)
+	}
+	rtxt := "0 rows"
+	if tot := sv.TotalSize(); tot > 0 {
+		st := sv.Page * sv.PageSize
+		rtxt = fmt.Sprintf("%d-%d of %d", st+1, ints.MinInt(st+sv.PageSize, tot), tot)
+	}
+	pg.ChildByName("rows", 9).(*gi.Label).SetText(rtxt)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ki"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Placeholders
+
+// SliceEmptyTitle is the title of the gi.EmptyState shown in a SliceView
+// or TableView whose slice has no elements
+var SliceEmptyTitle = "No items"
+
+// SliceSkeletonRows is the number of rows of the gi.Skeleton placeholder
+// shown in a SliceView or TableView while Loading
+var SliceSkeletonRows = 5
+
+// SetLoading sets whether the elements are loading, e.g., in a background
+// goroutine, showing rows of gi.Skeleton placeholders in place of them
+// while they are, and updates the display.  SetSlice must have been
+// called, e.g., with an empty slice that is filled while loading.
+func (sv *SliceViewBase) SetLoading(loading bool) {
+	if sv.Loading == loading {
+		return
+	}
+	sv.Loading = loading
+	if !sv.This().(SliceViewer).IsConfiged() {
+		return
+	}
+	sv.Update()
+}
+
+// ConfigPlaceholder shows a placeholder in given slice grid instead of
+// rows: rows of gi.Skeleton blocks if Loading, and otherwise the
+// gi.EmptyState for an empty slice -- the existing placeholder is kept if
+// it matches.
+func (sv *SliceViewBase) ConfigPlaceholder(sg *gi.Frame) {
+	nm := "empty"
+	typ := gi.KiT_EmptyState
+	if sv.Loading {
+		nm = "skeleton"
+		typ = gi.KiT_Layout
+	}
+	if len(sg.Kids) != 1 || sg.Kids[0] == nil || sg.Kids[0].Name() != nm {
+		sg.DeleteChildren(ki.DestroyKids)
+		sg.AddNewChild(typ, nm)
+		sg.SetFullReRender()
+	}
+	sg.Lay = gi.LayoutVert
+	sg.Stripes = gi.NoStripes
+	sv.Values = nil // rows must be remade
+	sv.DispRows = 0
+	if sv.Loading {
+		ly := sg.Kids[0].(*gi.Layout)
+		gi.ConfigSkeletonGrid(ly, SliceSkeletonRows, sv.SkeletonWidths())
+		return
+	}
+	es := sg.Kids[0].(*gi.EmptyState)
+	es.SetStretchMax()
+	es.Title = SliceEmptyTitle
+	es.Hint = ""
+	es.ActionText = ""
+	es.ActionIcon = ""
+	if !sv.IsInactive() && !sv.isArray && !sv.NoAdd {
+		es.ActionText = "Add"
+		es.ActionIcon = "plus"
+	}
+	es.ActionSig.ConnectOnly(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		svv := recv.Embed(KiT_SliceViewBase).(*SliceViewBase)
+		svv.This().(SliceViewer).SliceNewAt(-1)
+	})
+	if sv.EmptyFunc != nil {
+		sv.EmptyFunc(es)
+	}
+	es.Config()
+}
+
+// ClearPlaceholder restores the grid layout of given slice grid after a
+// placeholder was shown in it (see ConfigPlaceholder), for the rows that
+// replace it -- returns true if there was one
+func (sv *SliceViewBase) ClearPlaceholder(sg *gi.Frame) bool {
+	if sg.Lay == gi.LayoutGrid {
+		return false
+	}
+	sg.Lay = gi.LayoutGrid
+	sg.Stripes = gi.RowStripes
+	sg.DeleteChildren(ki.DestroyKids)
+	sv.Values = nil
+	return true
+}
+
+// SkeletonWidths returns the widths of the gi.Skeleton blocks in each
+// column of the placeholder rows shown while Loading: narrow ones for the
+// index and the add / delete actions, and stretching ones for the values.
+func (sv *SliceViewBase) SkeletonWidths() []units.Value {
+	svr := sv.This().(SliceViewer)
+	nWidgPerRow, idxOff := svr.RowWidgetNs()
+	nact := 0
+	if !sv.IsInactive() && !sv.isArray {
+		if !sv.NoAdd {
+			nact++
+		}
+		if !sv.NoDelete {
+			nact++
+		}
+	}
+	wds := make([]units.Value, nWidgPerRow)
+	for i := range wds {
+		switch {
+		case i < idxOff:
+			wds[i] = units.NewCh(5)
+		case i >= nWidgPerRow-nact:
+			wds[i] = units.NewEm(1)
+		default:
+			wds[i] = units.NewValue(-1, units.Ch)
+		}
+	}
+	return wds
+}
@@ -113,6 +113,10 @@ type SliceViewer interface {
 
 	// NeedsDoubleReRender returns true if initial render requires a 2nd pass
 	NeedsDoubleReRender() bool
+
+	// RowLabel returns the label of the element at given slice index, for the
+	// type-ahead search
+	RowLabel(idx int) string
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////
@@ -128,33 +132,34 @@ type SliceViewer interface {
 // set prop toolbar = false to turn off
 type SliceViewBase struct {
 	gi.Frame
-	Slice            any              `copy:"-" view:"-" json:"-" xml:"-" desc:"the slice that we are a view onto -- must be a pointer to that slice"`
-	ViewMu           *sync.Mutex      `copy:"-" view:"-" json:"-" xml:"-" desc:"optional mutex that, if non-nil, will be used around any updates that read / modify the underlying Slice data -- can be used to protect against random updating if your code has specific update points that can be likewise protected with this same mutex"`
-	SliceNPVal       reflect.Value    `copy:"-" view:"-" json:"-" xml:"-" desc:"non-ptr reflect.Value of the slice"`
-	SliceValView     ValueView        `copy:"-" view:"-" json:"-" xml:"-" desc:"ValueView for the slice itself, if this was created within value view framework -- otherwise nil"`
-	isArray          bool             `copy:"-" view:"-" json:"-" xml:"-" desc:"whether the slice is actually an array -- no modifications -- set by SetSlice"`
-	NoAdd            bool             `desc:"if true, user cannot add elements to the slice"`
-	NoDelete         bool             `desc:"if true, user cannot delete elements from the slice"`
-	ShowViewCtxtMenu bool             `desc:"if the type we're viewing has its own CtxtMenu property defined, should we also still show the view's standard context menu?"`
-	Changed          bool             `desc:"has the slice been edited?"`
-	Values           []ValueView      `copy:"-" view:"-" json:"-" xml:"-" desc:"ValueView representations of the slice values"`
-	ShowIndex        bool             `xml:"index" desc:"whether to show index or not -- updated from 'index' property (bool)"`
-	InactKeyNav      bool             `xml:"inact-key-nav" desc:"support key navigation when inactive (default true) -- updated from 'intact-key-nav' property (bool) -- no focus really plausible in inactive case, so it uses a low-pri capture of up / down events"`
-	SelVal           any              `copy:"-" view:"-" json:"-" xml:"-" desc:"current selection value -- initially select this value if set"`
-	SelectedIdx      int              `copy:"-" json:"-" xml:"-" desc:"index of currently-selected item, in Inactive mode only"`
-	SelectMode       bool             `copy:"-" desc:"editing-mode select rows mode"`
-	InactMultiSel    bool             `desc:"if view is inactive, default selection mode is to choose one row only -- if this is true, standard multiple selection logic with modifier keys is instead supported"`
-	SelectedIdxs     map[int]struct{} `copy:"-" desc:"list of currently-selected slice indexes"`
-	DraggedIdxs      []int            `copy:"-" desc:"list of currently-dragged indexes"`
-	SliceViewSig     ki.Signal        `copy:"-" json:"-" xml:"-" desc:"slice view specific signals: insert, delete, double-click"`
-	ViewSig          ki.Signal        `copy:"-" json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
-	ViewPath         string           `desc:"a record of parent View names that have led up to this view -- displayed as extra contextual information in view dialog windows"`
-	TmpSave          ValueView        `copy:"-" json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
-	ToolbarSlice     any              `copy:"-" view:"-" json:"-" xml:"-" desc:"the slice that we successfully set a toolbar for"`
+	Slice            any               `copy:"-" view:"-" json:"-" xml:"-" desc:"the slice that we are a view onto -- must be a pointer to that slice"`
+	ViewMu           *sync.Mutex       `copy:"-" view:"-" json:"-" xml:"-" desc:"optional mutex that, if non-nil, will be used around any updates that read / modify the underlying Slice data -- can be used to protect against random updating if your code has specific update points that can be likewise protected with this same mutex"`
+	SliceNPVal       reflect.Value     `copy:"-" view:"-" json:"-" xml:"-" desc:"non-ptr reflect.Value of the slice"`
+	SliceValView     ValueView         `copy:"-" view:"-" json:"-" xml:"-" desc:"ValueView for the slice itself, if this was created within value view framework -- otherwise nil"`
+	isArray          bool              `copy:"-" view:"-" json:"-" xml:"-" desc:"whether the slice is actually an array -- no modifications -- set by SetSlice"`
+	NoAdd            bool              `desc:"if true, user cannot add elements to the slice"`
+	NoDelete         bool              `desc:"if true, user cannot delete elements from the slice"`
+	ShowViewCtxtMenu bool              `desc:"if the type we're viewing has its own CtxtMenu property defined, should we also still show the view's standard context menu?"`
+	Changed          bool              `desc:"has the slice been edited?"`
+	Values           []ValueView       `copy:"-" view:"-" json:"-" xml:"-" desc:"ValueView representations of the slice values"`
+	ShowIndex        bool              `xml:"index" desc:"whether to show index or not -- updated from 'index' property (bool)"`
+	InactKeyNav      bool              `xml:"inact-key-nav" desc:"support key navigation when inactive (default true) -- updated from 'intact-key-nav' property (bool) -- no focus really plausible in inactive case, so it uses a low-pri capture of up / down events"`
+	SelVal           any               `copy:"-" view:"-" json:"-" xml:"-" desc:"current selection value -- initially select this value if set"`
+	SelectedIdx      int               `copy:"-" json:"-" xml:"-" desc:"index of currently-selected item, in Inactive mode only"`
+	SelectMode       bool              `copy:"-" desc:"editing-mode select rows mode"`
+	InactMultiSel    bool              `desc:"if view is inactive, default selection mode is to choose one row only -- if this is true, standard multiple selection logic with modifier keys is instead supported"`
+	Selection        gi.SelectionModel `copy:"-" desc:"currently-selected slice indexes, with the selection policy Mode -- changes are sent as WidgetSig WidgetSelectionChanged signals"`
+	SelectedIdxs     map[int]struct{}  `copy:"-" view:"-" json:"-" xml:"-" desc:"Deprecated: use Selection -- the same map as Selection.Selected, kept for compatibility -- changes made directly to it are not sent as WidgetSelectionChanged signals"`
+	DraggedIdxs      []int             `copy:"-" desc:"list of currently-dragged indexes"`
+	SliceViewSig     ki.Signal         `copy:"-" json:"-" xml:"-" desc:"slice view specific signals: insert, delete, double-click"`
+	ViewSig          ki.Signal         `copy:"-" json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
+	ViewPath         string            `desc:"a record of parent View names that have led up to this view -- displayed as extra contextual information in view dialog windows"`
+	TmpSave          ValueView         `copy:"-" json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	ToolbarSlice     any               `copy:"-" view:"-" json:"-" xml:"-" desc:"the slice that we successfully set a toolbar for"`
 
 	SliceSize     int     `inactive:"+" copy:"-" json:"-" xml:"-" desc:"size of slice"`
-	DispRows      int     `inactive:"+" copy:"-" json:"-" xml:"-" desc:"actual number of rows displayed = min(VisRows, SliceSize)"`
-	StartIdx      int     `inactive:"+" copy:"-" json:"-" xml:"-" desc:"starting slice index of visible rows"`
+	DispRows      int     `inactive:"+" copy:"-" json:"-" xml:"-" desc:"actual number of rows displayed = min(VisRows, DispSize())"`
+	StartIdx      int     `inactive:"+" copy:"-" json:"-" xml:"-" desc:"starting slice index of visible rows -- or, if grouped, starting index in GroupRows"`
 	RowHeight     float32 `inactive:"+" copy:"-" json:"-" xml:"-" desc:"height of a single row"`
 	VisRows       int     `inactive:"+" copy:"-" json:"-" xml:"-" desc:"total number of rows visible in allocated display size"`
 	LayoutHeight  float32 `copy:"-" view:"-" json:"-" xml:"-" desc:"the height of grid from last layout -- determines when update needed"`
@@ -162,6 +167,25 @@ type SliceViewBase struct {
 	InFocusGrab   bool    `copy:"-" view:"-" json:"-" xml:"-" desc:"guard for recursive focus grabbing"`
 	InFullRebuild bool    `copy:"-" view:"-" json:"-" xml:"-" desc:"guard for recursive rebuild"`
 	CurIdx        int     `copy:"-" view:"-" json:"-" xml:"-" desc:"temp idx state for e.g., dnd"`
+
+	TypeAhead gi.TypeAhead `copy:"-" view:"-" json:"-" xml:"-" desc:"state of the type-ahead search of the rows, in Inactive mode"`
+
+	GroupBy         func(idx int) string `copy:"-" view:"-" json:"-" xml:"-" desc:"optional function returning the name of the group of the element at given slice index -- if set, the elements are shown in groups, each below a header with its name and count that collapses or expands it -- see SetGroupBy"`
+	Groups          []*SliceGroup        `copy:"-" view:"-" json:"-" xml:"-" desc:"groups of the elements, in display order, if GroupBy is set"`
+	GroupRows       []SliceGroupRow      `copy:"-" view:"-" json:"-" xml:"-" desc:"all the display rows if GroupBy is set: the header of each group, followed by its elements if it is not collapsed"`
+	CollapsedGroups map[string]bool      `copy:"-" json:"-" xml:"-" desc:"names of the groups that are collapsed"`
+	groupOrder      []int                // slice indexes in group order, including collapsed ones
+	groupPos        []int                // position in groupOrder of each slice index
+	idxDisp         []int                // index in GroupRows of each slice index, -1 if collapsed
+	idxGroup        []int                // group of each slice index
+
+	Paged     bool `copy:"-" json:"-" xml:"-" desc:"if true, only one page of PageSize elements is shown at a time, with a pager to move among the pages, instead of scrolling through all of them -- see SetPaged"`
+	PageSize  int  `copy:"-" json:"-" xml:"-" desc:"number of elements per page when Paged"`
+	Page      int  `copy:"-" json:"-" xml:"-" desc:"current page when Paged, starting at 0 -- see SetPage"`
+	TotalRows int  `copy:"-" json:"-" xml:"-" desc:"if > 0 when Paged, the total number of elements over all pages, with the Slice only holding those of the current Page, which are fetched lazily on each SliceViewPageChanged signal -- see SetTotalRows"`
+
+	Loading   bool                    `copy:"-" json:"-" xml:"-" desc:"if true, rows of gi.Skeleton placeholders are shown in place of the elements, while they are loading -- see SetLoading"`
+	EmptyFunc func(es *gi.EmptyState) `copy:"-" view:"-" json:"-" xml:"-" desc:"optional function that configures the gi.EmptyState shown in place of the elements when the slice is empty, e.g., setting its icon, title, hint and button, and connecting to its ActionSig -- by default it has the SliceEmptyTitle, and an Add button if elements can be added"`
 }
 
 var KiT_SliceViewBase = kit.Types.AddType(&SliceViewBase{}, nil)
@@ -256,6 +280,10 @@ const (
 	// SliceViewDeleted emitted when an item is deleted -- data is index of item deleted
 	SliceViewDeleted
 
+	// SliceViewPageChanged emitted when the page changes in paged mode, just
+	// before the display is updated -- data is the new page
+	SliceViewPageChanged
+
 	SliceViewSignalsN
 )
 
@@ -277,6 +305,9 @@ func (sv *SliceViewBase) Config() {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "toolbar")
 	config.Add(gi.KiT_Layout, "grid-lay")
+	if sv.Paged {
+		config.Add(gi.KiT_ToolBar, "pager")
+	}
 	mods, updt := sv.ConfigChildren(config)
 
 	gl := sv.GridLayout()
@@ -289,6 +320,8 @@ func (sv *SliceViewBase) Config() {
 
 	sv.ConfigSliceGrid()
 	sv.ConfigToolbar()
+	sv.ConfigPager()
+	sv.RegisterFind()
 	if mods {
 		sv.SetFullReRender()
 		sv.UpdateEnd(updt)
@@ -347,10 +380,16 @@ func (sv *SliceViewBase) RowWidgetNs() (nWidgPerRow, idxOff int) {
 	return
 }
 
-// UpdtSliceSize updates and returns the size of the slice and sets SliceSize
+// UpdtSliceSize updates and returns the size of the slice and sets
+// SliceSize, removing any selected indexes past the end from the Selection
 func (sv *SliceViewBase) UpdtSliceSize() int {
 	sz := sv.SliceNPVal.Len()
 	sv.SliceSize = sz
+	for r := range sv.Selection.Selected {
+		if r >= sz {
+			sv.Selection.Remove(r)
+		}
+	}
 	return sz
 }
 
@@ -403,7 +442,8 @@ func (sv *SliceViewBase) ConfigSliceGrid() {
 		return
 	}
 	sz := sv.This().(SliceViewer).UpdtSliceSize()
-	if sz == 0 {
+	if sz == 0 || sv.Loading {
+		sv.ConfigPlaceholder(sg)
 		return
 	}
 
@@ -483,7 +523,8 @@ func (sv *SliceViewBase) ConfigScroll() {
 
 // UpdateStartIdx updates StartIdx to fit current view
 func (sv *SliceViewBase) UpdateStartIdx() {
-	sz := sv.This().(SliceViewer).UpdtSliceSize()
+	sv.This().(SliceViewer).UpdtSliceSize()
+	sz := sv.DispSize()
 	if sz > sv.DispRows {
 		lastSt := sz - sv.DispRows
 		sv.StartIdx = ints.MinInt(lastSt, sv.StartIdx)
@@ -497,7 +538,7 @@ func (sv *SliceViewBase) UpdateStartIdx() {
 func (sv *SliceViewBase) UpdateScroll() {
 	sb := sv.This().(SliceViewer).ScrollBar()
 	updt := sb.UpdateStart()
-	sb.Max = float32(sv.SliceSize) + 0.01 // bit of extra to ensure last line always shows up
+	sb.Max = float32(sv.DispSize()) + 0.01 // bit of extra to ensure last line always shows up
 	if sv.DispRows > 0 {
 		sb.PageStep = float32(sv.DispRows) * sb.Step
 		sb.ThumbVal = float32(sv.DispRows)
@@ -507,7 +548,7 @@ func (sv *SliceViewBase) UpdateScroll() {
 	}
 	sb.TrackThr = sb.Step
 	sb.SetValue(float32(sv.StartIdx)) // essential for updating pos from value
-	if sv.DispRows == sv.SliceSize {
+	if sv.DispRows == sv.DispSize() {
 		sb.Off = true
 	} else {
 		sb.Off = false
@@ -545,10 +586,13 @@ func (sv *SliceViewBase) LayoutSliceGrid() bool {
 	defer sv.ViewMuUnlock()
 
 	sz := sv.This().(SliceViewer).UpdtSliceSize()
-	if sz == 0 {
-		sg.DeleteChildren(ki.DestroyKids)
+	if sz == 0 || sv.Loading {
+		sv.ConfigPlaceholder(sg)
 		return false
 	}
+	sv.ClearPlaceholder(sg)
+
+	sv.UpdateGroups()
 
 	nWidgPerRow, _ := sv.RowWidgetNs()
 	if len(sg.GridData) > 0 && len(sg.GridData[gi.Row]) > 0 {
@@ -571,7 +615,7 @@ func (sv *SliceViewBase) LayoutSliceGrid() bool {
 		}
 		sv.VisRows = int(mat32.Floor(sgHt / sv.RowHeight))
 	}
-	sv.DispRows = ints.MinInt(sv.SliceSize, sv.VisRows)
+	sv.DispRows = ints.MinInt(sv.DispSize(), sv.VisRows)
 
 	nWidg := nWidgPerRow * sv.DispRows
 
@@ -614,11 +658,13 @@ func (sv *SliceViewBase) UpdateSliceGrid() {
 	defer sv.ViewMuUnlock()
 
 	sz := sv.This().(SliceViewer).UpdtSliceSize()
-	if sz == 0 {
-		sg.DeleteChildren(ki.DestroyKids)
+	if sz == 0 || sv.Loading {
+		sv.ConfigPlaceholder(sg)
 		return
 	}
-	sv.DispRows = ints.MinInt(sv.SliceSize, sv.VisRows)
+	sv.UpdateGroups()
+	sv.DispRows = ints.MinInt(sv.DispSize(), sv.VisRows)
+	sv.UpdatePager()
 
 	nWidgPerRow, idxOff := sv.RowWidgetNs()
 	nWidg := nWidgPerRow * sv.DispRows
@@ -634,7 +680,12 @@ func (sv *SliceViewBase) UpdateSliceGrid() {
 
 	for i := 0; i < sv.DispRows; i++ {
 		ridx := i * nWidgPerRow
-		si := sv.StartIdx + i // slice idx
+		if g := sv.RowGroup(i); g >= 0 {
+			sv.ConfigGroupRow(sg, i, g, nWidgPerRow, idxOff, sv.Values[i:i+1])
+			continue
+		}
+		sv.ClearGroupRow(sg, i, nWidgPerRow)
+		si := sv.RowIdx(i) // slice idx
 		issel := sv.IdxIsSelected(si)
 		val := kit.OnePtrUnderlyingValue(sv.SliceNPVal.Index(si)) // deal with pointer lists
 		var vv ValueView
@@ -771,9 +822,18 @@ func (sv *SliceViewBase) SetChanged() {
 	sv.ToolBar().UpdateActions() // nil safe
 }
 
-// SliceNewAtRow inserts a new blank element at given display row
+// SliceNewAtRow inserts a new blank element at given display row -- if
+// grouped, just after the element in the row above it, if any
 func (sv *SliceViewBase) SliceNewAtRow(row int) {
-	sv.This().(SliceViewer).SliceNewAt(sv.StartIdx + row)
+	idx := sv.PageStart() + sv.StartIdx + row
+	if sv.IsGrouped() {
+		if pi := sv.RowIdx(row - 1); pi >= 0 {
+			idx = pi + 1
+		} else {
+			idx = sv.RowIdx(row) // -1 = end
+		}
+	}
+	sv.This().(SliceViewer).SliceNewAt(idx)
 }
 
 // SliceNewAt inserts a new blank element at given index in the slice -- -1
@@ -860,7 +920,11 @@ func (sv *SliceViewBase) SliceNewAt(idx int) {
 // SliceDeleteAtRow deletes element at given display row
 // if updt is true, then update the grid after
 func (sv *SliceViewBase) SliceDeleteAtRow(row int, updt bool) {
-	sv.This().(SliceViewer).SliceDeleteAt(sv.StartIdx+row, updt)
+	idx := sv.RowIdx(row)
+	if idx < 0 {
+		return
+	}
+	sv.This().(SliceViewer).SliceDeleteAt(idx, updt)
 }
 
 // SliceNewAtSel updates selected rows based on
@@ -873,7 +937,7 @@ func (sv *SliceViewBase) SliceNewAtSel(idx int) {
 		if ix >= idx {
 			ix++
 		}
-		sv.SelectedIdxs[ix] = struct{}{}
+		sv.Selection.Selected[ix] = struct{}{}
 	}
 }
 
@@ -890,7 +954,7 @@ func (sv *SliceViewBase) SliceDeleteAtSel(idx int) {
 		case ix > idx:
 			ix--
 		}
-		sv.SelectedIdxs[ix] = struct{}{}
+		sv.Selection.Selected[ix] = struct{}{}
 	}
 }
 
@@ -1056,6 +1120,22 @@ func (sv *SliceViewBase) NeedsDoubleReRender() bool {
 	return false
 }
 
+// RowLabel returns the label of the element at given slice index, for the
+// type-ahead search: the ElemLabel of a gi.SliceLabeler slice, or the Label
+// of a gi.Labeler element, or else the element value as a string
+func (sv *SliceViewBase) RowLabel(idx int) string {
+	if lblr, ok := sv.Slice.(gi.SliceLabeler); ok {
+		if lbl := lblr.ElemLabel(idx); lbl != "" {
+			return lbl
+		}
+	}
+	val := kit.OnePtrUnderlyingValue(sv.SliceNPVal.Index(idx))
+	if lbl, has := gi.ToLabeler(val.Interface()); has {
+		return lbl
+	}
+	return kit.ToString(val.Interface())
+}
+
 func (sv *SliceViewBase) ConnectEvents2D() {
 	sv.SliceViewBaseEvents()
 }
@@ -1093,7 +1173,7 @@ func (sv *SliceViewBase) IsRowInBounds(row int) bool {
 
 // IsIdxVisible returns true if slice index is currently visible
 func (sv *SliceViewBase) IsIdxVisible(idx int) bool {
-	return sv.IsRowInBounds(idx - sv.StartIdx)
+	return sv.IsRowInBounds(sv.IdxRow(idx))
 }
 
 // RowFirstWidget returns the first widget for given row (could be index or
@@ -1115,7 +1195,7 @@ func (sv *SliceViewBase) RowFirstWidget(row int) (*gi.WidgetBase, bool) {
 // returns that element or nil if not successful -- note: grid must have
 // already rendered for focus to be grabbed!
 func (sv *SliceViewBase) RowGrabFocus(row int) *gi.WidgetBase {
-	if !sv.IsRowInBounds(row) || sv.InFocusGrab { // range check
+	if !sv.IsRowInBounds(row) || sv.RowGroup(row) >= 0 || sv.InFocusGrab { // range check
 		return nil
 	}
 	nWidgPerRow, idxOff := sv.This().(SliceViewer).RowWidgetNs()
@@ -1135,12 +1215,12 @@ func (sv *SliceViewBase) RowGrabFocus(row int) *gi.WidgetBase {
 // returns that element or nil if not successful
 func (sv *SliceViewBase) IdxGrabFocus(idx int) *gi.WidgetBase {
 	sv.ScrollToIdx(idx)
-	return sv.This().(SliceViewer).RowGrabFocus(idx - sv.StartIdx)
+	return sv.This().(SliceViewer).RowGrabFocus(sv.IdxRow(idx))
 }
 
 // IdxPos returns center of window position of index label for idx (ContextMenuPos)
 func (sv *SliceViewBase) IdxPos(idx int) image.Point {
-	row := idx - sv.StartIdx
+	row := sv.IdxRow(idx)
 	if row < 0 {
 		row = 0
 	}
@@ -1175,7 +1255,8 @@ func (sv *SliceViewBase) IdxFromPos(posY int) (int, bool) {
 	if !ok {
 		return -1, false
 	}
-	return row + sv.StartIdx, true
+	idx := sv.RowIdx(row)
+	return idx, idx >= 0
 }
 
 // ScrollToIdxNoUpdt ensures that given slice idx is visible by scrolling display as needed
@@ -1184,18 +1265,24 @@ func (sv *SliceViewBase) ScrollToIdxNoUpdt(idx int) bool {
 	if sv.DispRows == 0 {
 		return false
 	}
-	if idx < sv.StartIdx {
-		sv.StartIdx = idx
+	exp := sv.pageToIdx(idx)
+	exp = sv.expandIdxGroup(idx) || exp
+	d := sv.IdxDisp(idx)
+	if d < sv.StartIdx {
+		sv.StartIdx = d
+		if g := sv.IdxGroup(idx); g >= 0 && sv.Groups[g].Idxs[0] == idx {
+			sv.StartIdx-- // show the header too
+		}
 		sv.StartIdx = ints.MaxInt(0, sv.StartIdx)
 		sv.UpdateScroll()
 		return true
-	} else if idx >= sv.StartIdx+sv.DispRows {
-		sv.StartIdx = idx - (sv.DispRows - 1)
+	} else if d >= sv.StartIdx+sv.DispRows {
+		sv.StartIdx = d - (sv.DispRows - 1)
 		sv.StartIdx = ints.MaxInt(0, sv.StartIdx)
 		sv.UpdateScroll()
 		return true
 	}
-	return false
+	return exp
 }
 
 // ScrollToIdx ensures that given slice idx is visible by scrolling display as needed
@@ -1245,6 +1332,9 @@ func SliceIdxByValue(slc any, fldVal any) (int, bool) {
 // MoveDown moves the selection down to next row, using given select mode
 // (from keyboard modifiers) -- returns newly selected row or -1 if failed
 func (sv *SliceViewBase) MoveDown(selMode mouse.SelectModes) int {
+	if sv.IsGrouped() || sv.Paged {
+		return sv.MoveVis(1, selMode)
+	}
 	if sv.SelectedIdx >= sv.SliceSize-1 {
 		sv.SelectedIdx = sv.SliceSize - 1
 		return -1
@@ -1269,6 +1359,9 @@ func (sv *SliceViewBase) MoveDownAction(selMode mouse.SelectModes) int {
 // MoveUp moves the selection up to previous idx, using given select mode
 // (from keyboard modifiers) -- returns newly selected idx or -1 if failed
 func (sv *SliceViewBase) MoveUp(selMode mouse.SelectModes) int {
+	if sv.IsGrouped() || sv.Paged {
+		return sv.MoveVis(-1, selMode)
+	}
 	if sv.SelectedIdx <= 0 {
 		sv.SelectedIdx = 0
 		return -1
@@ -1292,6 +1385,9 @@ func (sv *SliceViewBase) MoveUpAction(selMode mouse.SelectModes) int {
 // MovePageDown moves the selection down to next page, using given select mode
 // (from keyboard modifiers) -- returns newly selected idx or -1 if failed
 func (sv *SliceViewBase) MovePageDown(selMode mouse.SelectModes) int {
+	if sv.IsGrouped() || sv.Paged {
+		return sv.MoveVis(sv.VisRows, selMode)
+	}
 	if sv.SelectedIdx >= sv.SliceSize-1 {
 		sv.SelectedIdx = sv.SliceSize - 1
 		return -1
@@ -1316,6 +1412,9 @@ func (sv *SliceViewBase) MovePageDownAction(selMode mouse.SelectModes) int {
 // MovePageUp moves the selection up to previous page, using given select mode
 // (from keyboard modifiers) -- returns newly selected idx or -1 if failed
 func (sv *SliceViewBase) MovePageUp(selMode mouse.SelectModes) int {
+	if sv.IsGrouped() || sv.Paged {
+		return sv.MoveVis(-sv.VisRows, selMode)
+	}
 	if sv.SelectedIdx <= 0 {
 		sv.SelectedIdx = 0
 		return -1
@@ -1337,12 +1436,26 @@ func (sv *SliceViewBase) MovePageUpAction(selMode mouse.SelectModes) int {
 	return nidx
 }
 
+// MoveVis moves the selection by given number of visible elements when
+// grouped or paged, skipping group headers and collapsed groups, and
+// staying within the current page, using given select mode (from keyboard
+// modifiers) -- returns newly selected idx or -1 if failed
+func (sv *SliceViewBase) MoveVis(n int, selMode mouse.SelectModes) int {
+	nidx := sv.VisIdxStep(sv.SelectedIdx, n)
+	if nidx < 0 || nidx == sv.SelectedIdx {
+		return -1
+	}
+	sv.SelectedIdx = nidx
+	sv.SelectIdxAction(sv.SelectedIdx, selMode)
+	return sv.SelectedIdx
+}
+
 //////////////////////////////////////////////////////////////////////////////
 //    Selection: user operates on the index labels
 
 // SelectRowWidgets sets the selection state of given row of widgets
 func (sv *SliceViewBase) SelectRowWidgets(row int, sel bool) {
-	if row < 0 {
+	if row < 0 || sv.RowGroup(row) >= 0 {
 		return
 	}
 	wupdt := sv.TopUpdateStart()
@@ -1370,14 +1483,17 @@ func (sv *SliceViewBase) SelectIdxWidgets(idx int, sel bool) bool {
 	if !sv.IsIdxVisible(idx) {
 		return false
 	}
-	sv.This().(SliceViewer).SelectRowWidgets(idx-sv.StartIdx, sel)
+	sv.This().(SliceViewer).SelectRowWidgets(sv.IdxRow(idx), sel)
 	return true
 }
 
 // UpdateSelectRow updates the selection for the given row
 // callback from widgetsig select
 func (sv *SliceViewBase) UpdateSelectRow(row int, sel bool) {
-	idx := row + sv.StartIdx
+	idx := sv.RowIdx(row)
+	if idx < 0 {
+		return
+	}
 	sv.UpdateSelectIdx(idx, sel)
 }
 
@@ -1392,6 +1508,7 @@ func (sv *SliceViewBase) UpdateSelectIdx(idx int, sel bool) {
 			sv.SelectIdx(idx)
 		}
 		sv.WidgetSig.Emit(sv.This(), int64(gi.WidgetSelected), sv.SelectedIdx)
+		sv.EmitSelectionChangedSignal(&sv.Selection)
 	} else {
 		selMode := mouse.SelectOne
 		em := sv.EventMgr2D()
@@ -1404,82 +1521,70 @@ func (sv *SliceViewBase) UpdateSelectIdx(idx int, sel bool) {
 
 // IdxIsSelected returns the selected status of given slice index
 func (sv *SliceViewBase) IdxIsSelected(idx int) bool {
-	if _, ok := sv.SelectedIdxs[idx]; ok {
-		return true
-	}
-	return false
+	return sv.Selection.IsSelected(idx)
 }
 
+// ResetSelectedIdxs clears the selection without any signal, e.g., when
+// the slice changes
 func (sv *SliceViewBase) ResetSelectedIdxs() {
-	sv.SelectedIdxs = make(map[int]struct{})
+	sv.Selection.Reset()
+	sv.SelectedIdxs = sv.Selection.Selected
 }
 
-// SelectedIdxsList returns list of selected indexes,
-// sorted either ascending or descending
+// SelectedIdxsList returns list of selected indexes that are within the
+// slice, sorted either ascending or descending
 func (sv *SliceViewBase) SelectedIdxsList(descendingSort bool) []int {
-	rws := make([]int, len(sv.SelectedIdxs))
-	i := 0
-	for r := range sv.SelectedIdxs {
-		if r >= sv.SliceSize { // double safety check at this point
-			delete(sv.SelectedIdxs, r)
-			rws = rws[:len(rws)-1]
-			continue
+	sl := sv.Selection.List(descendingSort)
+	n := 0
+	for _, r := range sl {
+		if r < sv.SliceSize { // double safety check at this point
+			sl[n] = r
+			n++
 		}
-		rws[i] = r
-		i++
 	}
-	if descendingSort {
-		sort.Slice(rws, func(i, j int) bool {
-			return rws[i] > rws[j]
-		})
-	} else {
-		sort.Slice(rws, func(i, j int) bool {
-			return rws[i] < rws[j]
-		})
-	}
-	return rws
+	return sl[:n]
 }
 
 // SelectIdx selects given idx (if not already selected) -- updates select
 // status of index label
 func (sv *SliceViewBase) SelectIdx(idx int) {
-	sv.SelectedIdxs[idx] = struct{}{}
+	sv.Selection.Add(idx)
 	sv.SelectIdxWidgets(idx, true)
 }
 
 // UnselectIdx unselects given idx (if selected)
 func (sv *SliceViewBase) UnselectIdx(idx int) {
-	if sv.IdxIsSelected(idx) {
-		delete(sv.SelectedIdxs, idx)
-	}
+	sv.Selection.Remove(idx)
 	sv.SelectIdxWidgets(idx, false)
 }
 
 // UnselectAllIdxs unselects all selected idxs
 func (sv *SliceViewBase) UnselectAllIdxs() {
 	wupdt := sv.TopUpdateStart()
-	for r := range sv.SelectedIdxs {
+	for r := range sv.Selection.Selected {
 		sv.SelectIdxWidgets(r, false)
 	}
-	sv.ResetSelectedIdxs()
+	sv.Selection.Clear()
 	sv.TopUpdateEnd(wupdt)
 }
 
-// SelectAllIdxs selects all idxs
+// SelectAllIdxs selects all idxs (unless the Selection Mode is
+// SelectionSingle), and emits a WidgetSelectionChanged signal
 func (sv *SliceViewBase) SelectAllIdxs() {
 	wupdt := sv.TopUpdateStart()
-	sv.UnselectAllIdxs()
-	sv.SelectedIdxs = make(map[int]struct{}, sv.SliceSize)
-	for idx := 0; idx < sv.SliceSize; idx++ {
-		sv.SelectedIdxs[idx] = struct{}{}
+	sv.Selection.SelectAll(sv.SliceSize)
+	for idx := range sv.Selection.Selected {
 		sv.SelectIdxWidgets(idx, true)
 	}
 	sv.TopUpdateEnd(wupdt)
+	sv.EmitSelectionChangedSignal(&sv.Selection)
 }
 
 // SelectIdxAction is called when a select action has been received (e.g., a
-// mouse click) -- translates into selection updates -- gets selection mode
-// from mouse event (ExtendContinuous, ExtendOne)
+// mouse click) -- translates into selection updates according to the
+// Selection Mode -- gets selection mode from mouse event (ExtendContinuous,
+// ExtendOne).  Emits WidgetSelected and WidgetSelectionChanged signals,
+// except for the Quiet modes.
 func (sv *SliceViewBase) SelectIdxAction(idx int, mode mouse.SelectModes) {
 	if mode == mouse.NoSelect {
 		return
@@ -1493,82 +1598,90 @@ func (sv *SliceViewBase) SelectIdxAction(idx int, mode mouse.SelectModes) {
 	wupdt := sv.TopUpdateStart()
 	defer sv.TopUpdateEnd(wupdt)
 
+	sv.SelectedIdx = idx
+	sel := sv.Selection.SelectAction(idx, mode)
+	sc := sv.Selection.PendingChange()
+	for _, r := range sc.Removed {
+		sv.SelectIdxWidgets(r, false)
+	}
+	for _, r := range sc.Added {
+		sv.SelectIdxWidgets(r, true)
+	}
 	switch mode {
-	case mouse.SelectOne:
-		if sv.IdxIsSelected(idx) {
-			if len(sv.SelectedIdxs) > 1 {
-				sv.UnselectAllIdxs()
-			}
-			sv.SelectedIdx = idx
-			sv.SelectIdx(idx)
-			sv.IdxGrabFocus(idx)
-		} else {
-			sv.UnselectAllIdxs()
-			sv.SelectedIdx = idx
-			sv.SelectIdx(idx)
-			sv.IdxGrabFocus(idx)
-		}
-		sv.WidgetSig.Emit(sv.This(), int64(gi.WidgetSelected), sv.SelectedIdx)
-	case mouse.ExtendContinuous:
-		if len(sv.SelectedIdxs) == 0 {
-			sv.SelectedIdx = idx
-			sv.SelectIdx(idx)
+	case mouse.SelectQuiet, mouse.UnselectQuiet:
+		return
+	case mouse.Unselect:
+	default:
+		if sel {
 			sv.IdxGrabFocus(idx)
 			sv.WidgetSig.Emit(sv.This(), int64(gi.WidgetSelected), sv.SelectedIdx)
 		} else {
-			minIdx := -1
-			maxIdx := 0
-			for r := range sv.SelectedIdxs {
-				if minIdx < 0 {
-					minIdx = r
-				} else {
-					minIdx = ints.MinInt(minIdx, r)
-				}
-				maxIdx = ints.MaxInt(maxIdx, r)
-			}
-			cidx := idx
-			sv.SelectedIdx = idx
-			sv.SelectIdx(idx)
-			if idx < minIdx {
-				for cidx < minIdx {
-					r := sv.MoveDown(mouse.SelectQuiet) // just select
-					cidx = r
-				}
-			} else if idx > maxIdx {
-				for cidx > maxIdx {
-					r := sv.MoveUp(mouse.SelectQuiet) // just select
-					cidx = r
-				}
-			}
-			sv.IdxGrabFocus(idx)
-			sv.WidgetSig.Emit(sv.This(), int64(gi.WidgetSelected), sv.SelectedIdx)
-		}
-	case mouse.ExtendOne:
-		if sv.IdxIsSelected(idx) {
-			sv.UnselectIdxAction(idx)
 			sv.WidgetSig.Emit(sv.This(), int64(gi.WidgetSelected), -1) // -1 = unselected
-		} else {
+		}
+	}
+	sv.EmitSelectionChangedSignal(&sv.Selection)
+}
+
+// RubberBandStart starts a rubber band selection at given window position,
+// if it is within the slice grid but not on any row, returning true if started.
+func (sv *SliceViewBase) RubberBandStart(pos image.Point, mode mouse.SelectModes) bool {
+	sg := sv.This().(SliceViewer).SliceGrid()
+	if sg == nil || !sg.PosInWinBBox(pos) {
+		return false
+	}
+	if _, onRow := sv.RowFromPos(pos.Y); onRow {
+		return false
+	}
+	orig := make(map[int]struct{}, sv.Selection.Len())
+	for idx := range sv.Selection.Selected {
+		orig[idx] = struct{}{}
+	}
+	sv.RubberBand.StartDrag(pos, mode, orig)
+	return true
+}
+
+// RubberBandUpdate updates the rubber band selection to given window
+// position, selecting the visible rows that intersect the rectangle.
+func (sv *SliceViewBase) RubberBandUpdate(pos image.Point) {
+	rb := &sv.RubberBand
+	rb.DragTo(pos, sv.ParentWindow())
+	r := rb.Rect()
+	wupdt := sv.TopUpdateStart()
+	defer sv.TopUpdateEnd(wupdt)
+	changed := false
+	for rw := 0; rw < sv.DispRows; rw++ {
+		idx := sv.RowIdx(rw)
+		if idx < 0 || idx >= sv.SliceSize {
+			continue
+		}
+		hit := false
+		if widg, ok := sv.This().(SliceViewer).RowFirstWidget(rw); ok {
+			hit = widg.WinBBox.Min.Y < r.Max.Y && r.Min.Y < widg.WinBBox.Max.Y
+		}
+		sel := rb.Selected(idx, hit)
+		if sel == sv.IdxIsSelected(idx) {
+			continue
+		}
+		changed = true
+		if sel {
 			sv.SelectedIdx = idx
 			sv.SelectIdx(idx)
-			sv.IdxGrabFocus(idx)
-			sv.WidgetSig.Emit(sv.This(), int64(gi.WidgetSelected), sv.SelectedIdx)
+		} else {
+			sv.UnselectIdx(idx)
 		}
-	case mouse.Unselect:
-		sv.SelectedIdx = idx
-		sv.UnselectIdxAction(idx)
-	case mouse.SelectQuiet:
-		sv.SelectedIdx = idx
-		sv.SelectIdx(idx)
-	case mouse.UnselectQuiet:
-		sv.SelectedIdx = idx
-		sv.UnselectIdx(idx)
+	}
+	if changed {
+		sv.WidgetSig.Emit(sv.This(), int64(gi.WidgetSelected), sv.SelectedIdx)
+		sv.EmitSelectionChangedSignal(&sv.Selection)
 	}
 }
 
-// UnselectIdxAction unselects this idx (if selected) -- and emits a signal
+// UnselectIdxAction unselects this idx (if selected) -- and emits a
+// WidgetSelectionChanged signal
 func (sv *SliceViewBase) UnselectIdxAction(idx int) {
 	if sv.IdxIsSelected(idx) {
 		sv.UnselectIdx(idx)
+		sv.EmitSelectionChangedSignal(&sv.Selection)
 	}
 }
 
@@ -1614,7 +1727,7 @@ func (sv *SliceViewBase) MimeDataType() string {
 
 // CopySelToMime copies selected rows to mime data
 func (sv *SliceViewBase) CopySelToMime() mimedata.Mimes {
-	nitms := len(sv.SelectedIdxs)
+	nitms := sv.Selection.Len()
 	if nitms == 0 {
 		return nil
 	}
@@ -1629,7 +1742,7 @@ func (sv *SliceViewBase) CopySelToMime() mimedata.Mimes {
 // Copy copies selected rows to clip.Board, optionally resetting the selection
 // satisfies gi.Clipper interface and can be overridden by subtypes
 func (sv *SliceViewBase) Copy(reset bool) {
-	nitms := len(sv.SelectedIdxs)
+	nitms := sv.Selection.Len()
 	if nitms == 0 {
 		return
 	}
@@ -1653,7 +1766,7 @@ func (sv *SliceViewBase) CopyIdxs(reset bool) {
 
 // DeleteIdxs deletes all selected indexes
 func (sv *SliceViewBase) DeleteIdxs() {
-	if len(sv.SelectedIdxs) == 0 {
+	if sv.Selection.Len() == 0 {
 		return
 	}
 	wupdt := sv.TopUpdateStart()
@@ -1672,7 +1785,7 @@ func (sv *SliceViewBase) DeleteIdxs() {
 // Cut copies selected indexes to clip.Board and deletes selected indexes
 // satisfies gi.Clipper interface and can be overridden by subtypes
 func (sv *SliceViewBase) Cut() {
-	if len(sv.SelectedIdxs) == 0 {
+	if sv.Selection.Len() == 0 {
 		return
 	}
 	wupdt := sv.TopUpdateStart()
@@ -1808,7 +1921,7 @@ func (sv *SliceViewBase) PasteAtIdx(md mimedata.Mimes, idx int) {
 // Duplicate copies selected items and inserts them after current selection --
 // return idx of start of duplicates if successful, else -1
 func (sv *SliceViewBase) Duplicate() int {
-	nitms := len(sv.SelectedIdxs)
+	nitms := sv.Selection.Len()
 	if nitms == 0 {
 		return -1
 	}
@@ -1826,7 +1939,7 @@ func (sv *SliceViewBase) Duplicate() int {
 
 // DragNDropStart starts a drag-n-drop
 func (sv *SliceViewBase) DragNDropStart() {
-	nitms := len(sv.SelectedIdxs)
+	nitms := sv.Selection.Len()
 	if nitms == 0 {
 		return
 	}
@@ -1941,7 +2054,7 @@ func (sv *SliceViewBase) DragNDropSource(de *dnd.Event) {
 // SaveDraggedIdxs saves selectedindexes into dragged indexes
 // taking into account insertion at idx
 func (sv *SliceViewBase) SaveDraggedIdxs(idx int) {
-	sz := len(sv.SelectedIdxs)
+	sz := sv.Selection.Len()
 	if sz == 0 {
 		sv.DraggedIdxs = nil
 		return
@@ -2045,6 +2158,7 @@ func (sv *SliceViewBase) KeyInputNav(kt *key.ChordEvent) {
 	switch kf {
 	case gi.KeyFunCancelSelect:
 		sv.UnselectAllIdxs()
+		sv.EmitSelectionChangedSignal(&sv.Selection)
 		sv.SelectMode = false
 		kt.SetProcessed()
 	case gi.KeyFunMoveDown:
@@ -2066,6 +2180,11 @@ func (sv *SliceViewBase) KeyInputNav(kt *key.ChordEvent) {
 		sv.SelectAllIdxs()
 		sv.SelectMode = false
 		kt.SetProcessed()
+	case gi.KeyFunMoveLeft, gi.KeyFunMoveRight:
+		if g := sv.IdxGroup(sv.SelectedIdx); g >= 0 {
+			sv.SetGroupCollapsed(sv.Groups[g].Name, kf == gi.KeyFunMoveLeft)
+			kt.SetProcessed()
+		}
 	}
 }
 
@@ -2118,10 +2237,40 @@ func (sv *SliceViewBase) KeyInputActive(kt *key.ChordEvent) {
 	}
 }
 
+// TypeAheadKeyInput processes given key event for the type-ahead search of
+// the rows, selecting the next one whose RowLabel matches the query,
+// starting at the selected one and wrapping around
+func (sv *SliceViewBase) TypeAheadKeyInput(kt *key.ChordEvent) {
+	search, next := sv.TypeAhead.KeyInput(kt)
+	if !search || sv.SliceSize == 0 {
+		return
+	}
+	svi := sv.This().(SliceViewer)
+	st := ints.MaxInt(sv.SelectedIdx, 0)
+	if next {
+		st++
+	}
+	found := false
+	for i := 0; i < sv.SliceSize; i++ {
+		idx := (st + i) % sv.SliceSize
+		if sv.TypeAhead.Matches(svi.RowLabel(idx)) {
+			sv.ScrollToIdx(idx)
+			sv.UpdateSelectIdx(idx, true)
+			found = true
+			break
+		}
+	}
+	sv.TypeAhead.ShowPopup(sv.AsWidget(), found)
+}
+
 func (sv *SliceViewBase) KeyInputInactive(kt *key.ChordEvent) {
 	if gi.KeyEventTrace {
 		fmt.Printf("SliceViewBase Inactive KeyInput: %v\n", sv.Path())
 	}
+	sv.TypeAheadKeyInput(kt)
+	if kt.IsProcessed() {
+		return
+	}
 	if sv.InactMultiSel {
 		sv.KeyInputNav(kt)
 		if kt.IsProcessed() {
@@ -2189,6 +2338,33 @@ func (sv *SliceViewBase) SliceViewBaseEvents() {
 			me.SetProcessed()
 		}
 	})
+	if !sv.IsInactive() || sv.InactMultiSel {
+		sv.ConnectEvent(oswin.MouseEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d any) {
+			me := d.(*mouse.Event)
+			svv := recv.Embed(KiT_SliceViewBase).(*SliceViewBase)
+			if me.Button != mouse.Left {
+				return
+			}
+			switch me.Action {
+			case mouse.Press:
+				svv.RubberBandStart(me.Pos(), me.SelectMode())
+			case mouse.Release:
+				if svv.RubberBand.On {
+					svv.RubberBand.Stop(svv.ParentWindow())
+					me.SetProcessed()
+				}
+			}
+		})
+		sv.ConnectEvent(oswin.MouseDragEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d any) {
+			me := d.(*mouse.DragEvent)
+			svv := recv.Embed(KiT_SliceViewBase).(*SliceViewBase)
+			if !svv.RubberBand.On {
+				return
+			}
+			me.SetProcessed()
+			svv.RubberBandUpdate(me.Pos())
+		})
+	}
 	if sv.IsInactive() {
 		if sv.InactKeyNav {
 			sv.ConnectEvent(oswin.KeyChordEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d any) {
@@ -16,12 +16,13 @@ func _() {
 	_ = x[SliceViewDoubleClicked-0]
 	_ = x[SliceViewInserted-1]
 	_ = x[SliceViewDeleted-2]
-	_ = x[SliceViewSignalsN-3]
+	_ = x[SliceViewPageChanged-3]
+	_ = x[SliceViewSignalsN-4]
 }
 
-const _SliceViewSignals_name = "SliceViewDoubleClickedSliceViewInsertedSliceViewDeletedSliceViewSignalsN"
+const _SliceViewSignals_name = "SliceViewDoubleClickedSliceViewInsertedSliceViewDeletedSliceViewPageChangedSliceViewSignalsN"
 
-var _SliceViewSignals_index = [...]uint8{0, 22, 39, 55, 72}
+var _SliceViewSignals_index = [...]uint8{0, 22, 39, 55, 75, 92}
 
 func (i SliceViewSignals) String() string {
 	if i < 0 || i >= SliceViewSignals(len(_SliceViewSignals_index)-1) {
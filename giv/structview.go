@@ -7,6 +7,7 @@ package giv
 import (
 	"encoding/json"
 	"fmt"
+	"image"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -18,15 +19,18 @@ import (
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/units"
 	"github.com/goki/gosl/slbool"
+	"github.com/goki/ki/ints"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
 )
 
 // StructView represents a struct, creating a property editor of the fields --
 // constructs Children widgets to show the field names and editor fields for
 // each field, within an overall frame.
 // Automatically has a toolbar with Struct ToolBar props if defined
-// set prop toolbar = false to turn off
+// set prop toolbar = false to turn off.
+// It is a gi.Outliner of its fields, for navigating long forms.
 type StructView struct {
 	gi.Frame
 	Struct        any               `desc:"the struct that we are a view onto"`
@@ -41,6 +45,11 @@ type StructView struct {
 	HasDefs       bool              `json:"-" xml:"-" inactive:"+" desc:"if true, some fields have default values -- update labels when values change"`
 	HasViewIfs    bool              `json:"-" xml:"-" inactive:"+" desc:"if true, some fields have viewif conditional view tags -- update after.."`
 	TypeFieldTags map[string]string `json:"-" xml:"-" inactive:"+" desc:"extra tags by field name -- from type properties"`
+	OutlineSig    ki.Signal         `json:"-" xml:"-" view:"-" desc:"signal for gi.OutlinePanels showing the fields -- see gi.OutlineSignals for the types"`
+	Validator     *FormValidator    `json:"-" xml:"-" view:"-" desc:"validator of the struct against FormRules that can span multiple fields, from the FormRules of a FormRuler struct, or added with AddFormRule -- nil if none"`
+	ValidSig      ki.Signal         `json:"-" xml:"-" view:"-" desc:"signal emitted when the struct is validated against the Validator rules, including when async checks are done -- data is true if it is valid (see IsValid)"`
+	FormErrs      string            `json:"-" xml:"-" view:"-" desc:"summary of the validation errors, shown in a banner above the fields -- see UpdateFormErrs"`
+	outPos        int               // last OutlinePos, for OutlineMoved
 }
 
 var KiT_StructView = kit.Types.AddType(&StructView{}, StructViewProps)
@@ -53,6 +62,8 @@ func AddNewStructView(parent ki.Ki, name string) *StructView {
 func (sv *StructView) Disconnect() {
 	sv.Frame.Disconnect()
 	sv.ViewSig.DisconnectAll()
+	sv.OutlineSig.DisconnectAll()
+	sv.ValidSig.DisconnectAll()
 }
 
 var StructViewProps = ki.Props{
@@ -61,6 +72,13 @@ var StructViewProps = ki.Props{
 	"color":            &gi.Prefs.Colors.Font,
 	"max-width":        -1,
 	"max-height":       -1,
+	"#form-errs": ki.Props{
+		"color":            "#B3261E",
+		"white-space":      gist.WhiteSpaceNormal,
+		"max-width":        -1,
+		"padding":          units.NewEm(0.25),
+		"background-color": "highlight-10",
+	},
 }
 
 // SetStruct sets the source struct that we are viewing -- rebuilds the
@@ -87,6 +105,11 @@ func (sv *StructView) SetStruct(st any) {
 				}
 			}
 		}
+		sv.Validator = nil
+		sv.FormErrs = ""
+		if fr, ok := st.(FormRuler); ok {
+			sv.FormValidator().AddRules(fr.FormRules()...)
+		}
 		if k, ok := st.(ki.Ki); ok {
 			k.NodeSignal().Connect(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
 				// todo: check for delete??
@@ -97,6 +120,9 @@ func (sv *StructView) SetStruct(st any) {
 		}
 	}
 	sv.Config()
+	if sv.Validator != nil {
+		sv.Validate()
+	}
 	sv.UpdateEnd(updt)
 }
 
@@ -133,10 +159,14 @@ func (sv *StructView) Config() {
 	sv.SetProp("spacing", gi.StdDialogVSpaceUnits)
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "toolbar")
+	if sv.FormErrs != "" {
+		config.Add(gi.KiT_Label, "form-errs")
+	}
 	config.Add(gi.KiT_Frame, "struct-grid")
 	mods, updt := sv.ConfigChildren(config)
 	sv.ConfigStructGrid()
 	sv.ConfigToolbar()
+	sv.ConfigFormErrs()
 	if mods {
 		sv.UpdateEnd(updt)
 	}
@@ -332,6 +362,9 @@ func (sv *StructView) ConfigStructGrid() {
 					svv.ChangeFlag.SetBool(true)
 				}
 				vvv := send.(ValueView).AsValueViewBase()
+				if svv.Validator != nil {
+					svv.ValidateField(vvv.Field.Name)
+				}
 				if !kit.KindIsBasic(kit.NonPtrValue(vvv.Value).Kind()) {
 					if updtr, ok := svv.Struct.(gi.Updater); ok {
 						// fmt.Printf("updating: %v kind: %v\n", updtr, vvv.Value.Kind())
@@ -348,7 +381,13 @@ func (sv *StructView) ConfigStructGrid() {
 			})
 		}
 	}
+	sg.NodeSignal().Connect(sv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig == int64(ki.NodeSignalUpdated) { // includes scrolling of the grid
+			recv.Embed(KiT_StructView).(*StructView).outlineMoved()
+		}
+	})
 	sg.UpdateEnd(updt)
+	sv.OutlineSig.Emit(sv.This(), int64(gi.OutlineChanged), nil)
 }
 
 func (sv *StructView) Style2D() {
@@ -388,6 +427,74 @@ func (sv *StructView) Render2D() {
 	sv.Frame.Render2D()
 }
 
+func (sv *StructView) Move2D(delta image.Point, parBBox image.Rectangle) {
+	sv.Frame.Move2D(delta, parBBox)
+	sv.outlineMoved()
+}
+
+/////////////////////////////////////////////////////////////////////////
+//  gi.Outliner
+
+// OutlineSections returns the fields as sections, in units of field index
+func (sv *StructView) OutlineSections() []gi.OutlineSection {
+	if !sv.IsConfiged() {
+		return nil
+	}
+	sg := sv.StructGrid()
+	secs := make([]gi.OutlineSection, len(sv.FieldViews))
+	for i := range sv.FieldViews {
+		secs[i] = gi.OutlineSection{Title: sg.Child(i * 2).(*gi.Label).Text, Start: i, End: i + 1}
+	}
+	return secs
+}
+
+// OutlinePos returns the index of the first visible field
+func (sv *StructView) OutlinePos() int {
+	if !sv.IsConfiged() {
+		return 0
+	}
+	sg := sv.StructGrid()
+	top := ints.MaxInt(sg.VpBBox.Min.Y, sv.VpBBox.Min.Y)
+	for i := range sv.FieldViews {
+		if sg.Child(i*2).(*gi.Label).ObjBBox.Max.Y > top {
+			return i
+		}
+	}
+	return 0
+}
+
+// OutlineGoTo scrolls the field at given index to the top of the view and
+// focuses its value widget, highlighting it
+func (sv *StructView) OutlineGoTo(idx int) {
+	if !sv.IsConfiged() || idx < 0 || idx >= len(sv.FieldViews) {
+		return
+	}
+	sg := sv.StructGrid()
+	lbl := sg.Child(idx * 2).(*gi.Label)
+	if !sg.ScrollDimToStart(mat32.Y, lbl.ObjBBox.Min.Y) {
+		lbl.ScrollToMe()
+	}
+	if wb := sg.Child(idx*2 + 1).(gi.Node2D).AsWidget(); wb != nil {
+		wb.GrabFocus()
+	}
+}
+
+// OutlineSignal returns the OutlineSig
+func (sv *StructView) OutlineSignal() *ki.Signal {
+	return &sv.OutlineSig
+}
+
+// outlineMoved emits OutlineMoved if the OutlinePos changed
+func (sv *StructView) outlineMoved() {
+	if len(sv.OutlineSig.Cons) == 0 {
+		return
+	}
+	if pos := sv.OutlinePos(); pos != sv.outPos {
+		sv.outPos = pos
+		sv.OutlineSig.Emit(sv.This(), int64(gi.OutlineMoved), nil)
+	}
+}
+
 /////////////////////////////////////////////////////////////////////////
 //  Tag parsing
 
@@ -42,6 +42,7 @@ type TableView struct {
 	SelField   string                `copy:"-" view:"-" json:"-" xml:"-" desc:"current selection field -- initially select value in this field"`
 	SortIdx    int                   `desc:"current sort index"`
 	SortDesc   bool                  `desc:"whether current sort order is descending"`
+	NoSort     bool                  `desc:"if true, clicking on the column headers does not sort the slice -- e.g., when the row order matters, as in TwinTableView"`
 	StruType   reflect.Type          `copy:"-" view:"-" json:"-" xml:"-" desc:"struct type for each row"`
 	VisFields  []reflect.StructField `copy:"-" view:"-" json:"-" xml:"-" desc:"the visible fields"`
 	NVisFields int                   `copy:"-" view:"-" json:"-" xml:"-" desc:"number of visible fields"`
@@ -188,9 +189,13 @@ func (tv *TableView) Config() {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "toolbar")
 	config.Add(gi.KiT_Frame, "frame")
+	if tv.Paged {
+		config.Add(gi.KiT_ToolBar, "pager")
+	}
 	mods, updt := tv.ConfigChildren(config)
 	tv.ConfigSliceGrid()
 	tv.ConfigToolbar()
+	tv.ConfigPager()
 	if mods {
 		tv.SetFullReRender()
 		tv.UpdateEnd(updt)
@@ -273,9 +278,6 @@ func (tv *TableView) ConfigSliceGrid() {
 	tv.CacheVisFields()
 
 	sz := tv.This().(SliceViewer).UpdtSliceSize()
-	if sz == 0 {
-		return
-	}
 
 	nWidgPerRow, idxOff := tv.RowWidgetNs()
 
@@ -296,6 +298,7 @@ func (tv *TableView) ConfigSliceGrid() {
 	sgh.Lay = gi.LayoutHoriz
 	sgh.SetProp("overflow", gist.OverflowHidden) // no scrollbars!
 	sgh.SetProp("spacing", 0)
+	sgh.SetSticky(true) // stays in view when scrolled within an outer layout
 	// sgh.SetStretchMaxWidth()
 
 	gl := tv.GridLayout()
@@ -316,6 +319,12 @@ func (tv *TableView) ConfigSliceGrid() {
 	// this causes everything to get off, especially resizing: not taking it into account presumably:
 	// sgf.SetProp("spacing", gi.StdDialogVSpaceUnits)
 
+	if sz == 0 || tv.Loading {
+		tv.ConfigPlaceholder(sgf)
+		tv.ConfigScroll()
+		return
+	}
+
 	// Configure Header
 	hcfg := kit.TypeAndNameList{}
 	if tv.ShowIndex {
@@ -360,13 +369,19 @@ func (tv *TableView) ConfigSliceGrid() {
 			}
 		}
 		hdr.Data = fli
-		hdr.Tooltip = field.Name + " (click to sort by)"
+		hdr.Tooltip = field.Name
+		if !tv.NoSort {
+			hdr.Tooltip += " (click to sort by)"
+		}
 		dsc := field.Tag.Get("desc")
 		if dsc != "" {
 			hdr.Tooltip += ": " + dsc
 		}
 		hdr.ActionSig.ConnectOnly(tv.This(), func(recv, send ki.Ki, sig int64, data any) {
 			tvv := recv.Embed(KiT_TableView).(*TableView)
+			if tvv.NoSort {
+				return
+			}
 			act := send.(*gi.Action)
 			fldIdx := act.Data.(int)
 			tvv.SortSliceAction(fldIdx)
@@ -438,10 +453,15 @@ func (tv *TableView) LayoutSliceGrid() bool {
 	defer tv.ViewMuUnlock()
 
 	sz := tv.This().(SliceViewer).UpdtSliceSize()
-	if sz == 0 {
-		sg.DeleteChildren(ki.DestroyKids)
+	if sz == 0 || tv.Loading {
+		tv.ConfigPlaceholder(sg)
 		return false
 	}
+	if tv.ClearPlaceholder(sg) && tv.SliceHeader().NumChildren() == 0 {
+		tv.ConfigSliceGrid() // header is not configured while empty
+	}
+
+	tv.UpdateGroups()
 
 	nWidgPerRow, _ := tv.RowWidgetNs()
 	if len(sg.GridData) > 0 && len(sg.GridData[gi.Row]) > 0 {
@@ -454,7 +474,7 @@ func (tv *TableView) LayoutSliceGrid() bool {
 
 	mvp := tv.ViewportSafe()
 	if mvp != nil && mvp.HasFlag(int(gi.VpFlagPrefSizing)) {
-		tv.VisRows = ints.MinInt(gi.LayoutPrefMaxRows, tv.SliceSize)
+		tv.VisRows = ints.MinInt(gi.LayoutPrefMaxRows, tv.DispSize())
 		tv.LayoutHeight = float32(tv.VisRows) * tv.RowHeight
 	} else {
 		sgHt := tv.AvailHeight()
@@ -464,7 +484,7 @@ func (tv *TableView) LayoutSliceGrid() bool {
 		}
 		tv.VisRows = int(mat32.Floor(sgHt / tv.RowHeight))
 	}
-	tv.DispRows = ints.MinInt(tv.SliceSize, tv.VisRows)
+	tv.DispRows = ints.MinInt(tv.DispSize(), tv.VisRows)
 
 	nWidg := nWidgPerRow * tv.DispRows
 
@@ -487,7 +507,7 @@ func (tv *TableView) LayoutHeader() {
 	sgf := tv.SliceGrid()
 	spc := sgh.Spacing.Dots
 	gd := sgf.GridData[gi.Col]
-	if gd == nil {
+	if gd == nil || sgf.Lay != gi.LayoutGrid || sgh.NumChildren() < nfld { // placeholder
 		return
 	}
 	sumwd := float32(0)
@@ -536,11 +556,13 @@ func (tv *TableView) UpdateSliceGrid() {
 	defer tv.ViewMuUnlock()
 
 	sz := tv.This().(SliceViewer).UpdtSliceSize()
-	if sz == 0 {
-		sg.DeleteChildren(ki.DestroyKids)
+	if sz == 0 || tv.Loading {
+		tv.ConfigPlaceholder(sg)
 		return
 	}
-	tv.DispRows = ints.MinInt(tv.SliceSize, tv.VisRows)
+	tv.UpdateGroups()
+	tv.DispRows = ints.MinInt(tv.DispSize(), tv.VisRows)
+	tv.UpdatePager()
 
 	nWidgPerRow, idxOff := tv.RowWidgetNs()
 	nWidg := nWidgPerRow * tv.DispRows
@@ -559,7 +581,12 @@ func (tv *TableView) UpdateSliceGrid() {
 
 	for i := 0; i < tv.DispRows; i++ {
 		ridx := i * nWidgPerRow
-		si := tv.StartIdx + i // slice idx
+		if g := tv.RowGroup(i); g >= 0 {
+			tv.ConfigGroupRow(sg, i, g, nWidgPerRow, idxOff, tv.Values[i*tv.NVisFields:(i+1)*tv.NVisFields])
+			continue
+		}
+		tv.ClearGroupRow(sg, i, nWidgPerRow)
+		si := tv.RowIdx(i) // slice idx
 		issel := tv.IdxIsSelected(si)
 		val := kit.OnePtrUnderlyingValue(tv.SliceNPVal.Index(si)) // deal with pointer lists
 		stru := val.Interface()
@@ -714,6 +741,25 @@ func (tv *TableView) UpdateSliceGrid() {
 	tv.UpdateScroll()
 }
 
+// RowLabel returns the label of the struct at given slice index, for the
+// type-ahead search: its Label if it is a gi.Labeler, or else the value of
+// the sort column (or the first one) as a string
+func (tv *TableView) RowLabel(idx int) string {
+	val := kit.OnePtrUnderlyingValue(tv.SliceNPVal.Index(idx))
+	if lbl, has := gi.ToLabeler(val.Interface()); has {
+		return lbl
+	}
+	if tv.NVisFields == 0 {
+		return ""
+	}
+	fli := 0
+	if tv.SortIdx >= 0 && tv.SortIdx < tv.NVisFields {
+		fli = tv.SortIdx
+	}
+	fval := val.Elem().FieldByIndex(tv.VisFields[fli].Index)
+	return kit.ToString(fval.Interface())
+}
+
 func (tv *TableView) StyleRow(svnp reflect.Value, widg gi.Node2D, idx, fidx int, vv ValueView) {
 	if tv.StyleFunc != nil {
 		tv.StyleFunc(tv, svnp.Interface(), widg, idx, fidx, vv)
@@ -952,7 +998,7 @@ func (tv *TableView) RowFirstVisWidget(row int) (*gi.WidgetBase, bool) {
 // returns that element or nil if not successful -- note: grid must have
 // already rendered for focus to be grabbed!
 func (tv *TableView) RowGrabFocus(row int) *gi.WidgetBase {
-	if !tv.IsRowInBounds(row) || tv.InFocusGrab { // range check
+	if !tv.IsRowInBounds(row) || tv.RowGroup(row) >= 0 || tv.InFocusGrab { // range check
 		return nil
 	}
 	nWidgPerRow, idxOff := tv.RowWidgetNs()
@@ -979,7 +1025,7 @@ func (tv *TableView) RowGrabFocus(row int) *gi.WidgetBase {
 
 // SelectRowWidgets sets the selection state of given row of widgets
 func (tv *TableView) SelectRowWidgets(row int, sel bool) {
-	if row < 0 {
+	if row < 0 || tv.RowGroup(row) >= 0 {
 		return
 	}
 	wupdt := tv.TopUpdateStart()
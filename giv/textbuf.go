@@ -7,6 +7,7 @@ package giv
 import (
 	"bytes"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"log"
 	"os"
@@ -59,13 +60,19 @@ var TextBufMarkupDelayMSec = 1000
 // saving buffers to files.  Unlike GUI Widgets, its methods are generally
 // signaling, without an explicit Action suffix.  Internally, the buffer
 // represents new lines using \n = LF, but saving and loading can deal with
-// Windows/DOS CRLF format.
+// Windows/DOS CRLF format.  Likewise, text is always UTF-8 internally, and
+// other file encodings (UTF-16, Latin-1, BOM) are detected on loading and
+// restored on saving -- see Encoding and LineEnds.
 type TextBuf struct {
 	ki.Node
 	Txt              []byte              `json:"-" xml:"text" desc:"the current value of the entire text being edited -- using []byte slice for greater efficiency"`
 	Autosave         bool                `desc:"if true, auto-save file after changes (in a separate routine)"`
+	Journal          bool                `desc:"if true, record each edit in a journal file, for recovering unsaved changes after a crash -- see JournalReplay"`
 	Opts             textbuf.Opts        `desc:"options for how text editing / viewing works"`
 	Filename         gi.FileName         `json:"-" xml:"-" desc:"filename of file last loaded or saved"`
+	FS               fs.FS               `view:"-" json:"-" xml:"-" desc:"filesystem that files are opened from, if not the OS filesystem -- files in an fs.FS are opened read-only"`
+	Encoding         textbuf.Encodings   `desc:"encoding of the file, as detected when loading, used when saving -- use SetEncoding to change"`
+	LineEnds         textbuf.LineEnds    `desc:"line ending style of the file, as detected when loading, used when saving -- use SetLineEnds to change"`
 	Info             FileInfo            `desc:"full info about file"`
 	PiState          pi.FileStates       `desc:"Pi parsing state info for file"`
 	Hi               HiMarkup            `desc:"syntax highlighting markup parameters (language, style, etc)"`
@@ -85,6 +92,8 @@ type TextBuf struct {
 	MarkupMu         sync.RWMutex        `json:"-" xml:"-" desc:"mutex for updating markup"`
 	MarkupDelayTimer *time.Timer         `json:"-" xml:"-" desc:"markup delay timer"`
 	MarkupDelayMu    sync.Mutex          `json:"-" xml:"-" desc:"mutex for updating markup delay timer"`
+	JournalFile      *os.File            `json:"-" xml:"-" desc:"open edit journal file, if Journal is on"`
+	JournalMu        sync.Mutex          `json:"-" xml:"-" desc:"mutex for writing to the edit journal file"`
 	TextBufSig       ki.Signal           `json:"-" xml:"-" view:"-" desc:"signal for buffer -- see TextBufSignals for the types"`
 	Views            []*TextView         `json:"-" xml:"-" desc:"the TextViews that are currently viewing this buffer"`
 	Undos            textbuf.Undo        `json:"-" xml:"-" desc:"undo manager"`
@@ -113,6 +122,20 @@ var TextBufProps = ki.Props{
 				}},
 			},
 		}},
+		{"SetEncoding", ki.Props{
+			"Args": ki.PropSlice{
+				{"Encoding", ki.Props{
+					"default-field": "Encoding",
+				}},
+			},
+		}},
+		{"SetLineEnds", ki.Props{
+			"Args": ki.PropSlice{
+				{"Line Ends", ki.Props{
+					"default-field": "LineEnds",
+				}},
+			},
+		}},
 	},
 }
 
@@ -173,6 +196,10 @@ const (
 	// opened, user is ok
 	TextBufFileModOk
 
+	// TextBufReadOnly indicates that the file cannot be saved, e.g., because it
+	// is within an archive -- views of the buffer are inactive
+	TextBufReadOnly
+
 	TextBufFlagsN
 )
 
@@ -182,6 +209,17 @@ func (tb *TextBuf) IsChanged() bool {
 	return tb.HasFlag(int(TextBufChanged))
 }
 
+// IsReadOnly indicates if the buffer is read-only, e.g., for a file within an
+// archive -- views of the buffer are inactive
+func (tb *TextBuf) IsReadOnly() bool {
+	return tb.HasFlag(int(TextBufReadOnly))
+}
+
+// SetReadOnly sets the read-only state of the buffer
+func (tb *TextBuf) SetReadOnly(ro bool) {
+	tb.SetFlagState(ro, int(TextBufReadOnly))
+}
+
 // SetChanged marks buffer as changed
 func (tb *TextBuf) SetChanged() {
 	tb.SetFlag(int(TextBufChanged))
@@ -367,11 +405,12 @@ func (tb *TextBuf) New(nlines int) {
 // Stat gets info about the file, including highlighting language
 func (tb *TextBuf) Stat() error {
 	tb.ClearFlag(int(TextBufFileModOk))
-	err := tb.Info.InitFile(string(tb.Filename))
+	err := tb.Info.InitFileFS(tb.FS, string(tb.Filename))
 	if err != nil {
 		return err
 	}
 	tb.ConfigSupported()
+	tb.ConfigEditorConfig(false)
 	return nil
 }
 
@@ -390,11 +429,42 @@ func (tb *TextBuf) ConfigSupported() bool {
 	return false
 }
 
+// TextBufEditorConfig determines whether the .editorconfig files in the
+// directory of a file and above it are applied to TextBufs of the file
+var TextBufEditorConfig = true
+
+// ConfigEditorConfig applies the .editorconfig files for the file, if it
+// is on the OS filesystem and TextBufEditorConfig is on: indentation, max
+// line length (shown as a ruler), and trimming trailing whitespace and
+// inserting a final newline when saving -- called after ConfigSupported
+// so that they override the language options.  If enc, the charset and
+// end_of_line properties also set the Encoding and LineEnds used for saving,
+// which is only done when opening the file, so that changes to them stick.
+func (tb *TextBuf) ConfigEditorConfig(enc bool) {
+	if !TextBufEditorConfig || tb.FS != nil || tb.Filename == "" {
+		return
+	}
+	ec, err := textbuf.EditorConfigFor(string(tb.Filename))
+	if err != nil || len(ec) == 0 {
+		return
+	}
+	ec.ApplyOpts(&tb.Opts)
+	if !enc {
+		return
+	}
+	if e, ok := ec.Encoding(); ok {
+		tb.Encoding = e
+	}
+	if le, ok := ec.LineEnds(); ok {
+		tb.LineEnds = le
+	}
+}
+
 // FileModCheck checks if the underlying file has been modified since last
 // Stat (open, save) -- if haven't yet prompted, user is prompted to ensure
 // that this is OK.  returns true if file was modified
 func (tb *TextBuf) FileModCheck() bool {
-	if tb.HasFlag(int(TextBufFileModOk)) {
+	if tb.HasFlag(int(TextBufFileModOk)) || tb.IsReadOnly() {
 		return false
 	}
 	info, err := os.Stat(string(tb.Filename))
@@ -436,20 +506,35 @@ func (tb *TextBuf) Open(filename gi.FileName) error {
 	tb.InitialMarkup()
 	tb.Refresh()
 	tb.ReMarkup()
+	if tb.Journal && tb.JournalSetAside() {
+		tb.JournalRecoverPrompt()
+	}
 	return nil
 }
 
 // OpenFile just loads a file into the buffer -- doesn't do any markup or
-// notification -- for temp bufs
+// notification -- for temp bufs.  The file encoding and line endings are
+// detected and converted to UTF-8 and LF, and recorded in Encoding and
+// LineEnds for saving.  Files within archives or an fs.FS (see FS) are
+// opened read-only.
 func (tb *TextBuf) OpenFile(filename gi.FileName) error {
-	fp, err := os.Open(string(filename))
+	raw, err := FSReadFile(tb.FS, string(filename)) // can be within an archive
 	if err != nil {
 		return err
 	}
-	tb.Txt, err = ioutil.ReadAll(fp)
-	fp.Close()
+	tb.Encoding = textbuf.DetectEncoding(raw)
+	txt, err := textbuf.DecodeToUTF8(raw, tb.Encoding)
+	if err != nil {
+		log.Printf("giv.TextBuf OpenFile: %v: %v -- reading as Latin1\n", filename, err)
+		tb.Encoding = textbuf.Latin1
+		txt, _ = textbuf.DecodeToUTF8(raw, tb.Encoding)
+	}
+	tb.LineEnds = textbuf.DetectLineEnds(txt)
+	tb.Txt = textbuf.ToLF(txt)
 	tb.Filename = filename
+	tb.SetReadOnly(tb.FS != nil || InArchive(string(filename)))
 	tb.Stat()
+	tb.ConfigEditorConfig(true)
 	tb.BytesToLines()
 	return nil
 }
@@ -490,6 +575,7 @@ func (tb *TextBuf) Revert() bool {
 	}
 	tb.ClearChanged()
 	tb.AutoSaveDelete()
+	tb.JournalDelete()
 	tb.Refresh()
 	tb.ReMarkup()
 	return true
@@ -532,26 +618,71 @@ func (tb *TextBuf) SaveAs(filename gi.FileName) {
 	tb.SaveAsFunc(filename, nil)
 }
 
-// SaveFile writes current buffer to file, with no prompting, etc
+// SaveFile writes current buffer to file, with no prompting, etc, using
+// the current Encoding and LineEnds -- first formats it if the
+// FormatOnSave option is on and there is a formatter for its language
 func (tb *TextBuf) SaveFile(filename gi.FileName) error {
-	err := ioutil.WriteFile(string(filename), tb.Txt, 0644)
+	if tb.SaveEdits() {
+		tb.EditDone()
+	}
+	ob, err := textbuf.EncodeFromUTF8(textbuf.FromLF(tb.Txt, tb.LineEnds), tb.Encoding)
+	if err != nil { // lossy encoding: save anyway, but report it
+		log.Println(err)
+	}
+	err = ioutil.WriteFile(string(filename), ob, 0644)
 	if err != nil {
 		gi.PromptDialog(nil, gi.DlgOpts{Title: "Could not Save to File", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
 		log.Println(err)
 	} else {
+		tb.JournalDelete()
 		tb.Filename = filename
+		tb.FS = nil // saved to the OS filesystem
+		tb.SetReadOnly(false)
 		tb.SetName(string(filename))
 		tb.Stat()
 	}
 	return err
 }
 
+// SetEncoding sets the encoding used for saving the file, marking the
+// buffer as changed so it will be saved
+func (tb *TextBuf) SetEncoding(enc textbuf.Encodings) {
+	if tb.Encoding == enc {
+		return
+	}
+	tb.Encoding = enc
+	tb.SetChanged()
+}
+
+// SetLineEnds sets the line ending style used for saving the file, marking
+// the buffer as changed so it will be saved
+func (tb *TextBuf) SetLineEnds(le textbuf.LineEnds) {
+	if tb.LineEnds == le {
+		return
+	}
+	tb.LineEnds = le
+	tb.SetChanged()
+}
+
+// EncodingStatus returns a short status string describing the file encoding
+// and line endings, e.g., "UTF8 LF", for display in a status bar
+func (tb *TextBuf) EncodingStatus() string {
+	le := "LF"
+	if tb.LineEnds == textbuf.LineEndCRLF {
+		le = "CRLF"
+	}
+	return tb.Encoding.String() + " " + le
+}
+
 // Save saves the current text into current Filename associated with this
 // buffer
 func (tb *TextBuf) Save() error {
 	if tb.Filename == "" {
 		return fmt.Errorf("giv.TextBuf: filename is empty for Save")
 	}
+	if tb.IsReadOnly() {
+		return fmt.Errorf("giv.TextBuf: file is read-only: %v", tb.Filename)
+	}
 	tb.EditDone()
 	info, err := os.Stat(string(tb.Filename))
 	if err == nil && info.ModTime() != time.Time(tb.Info.ModTime) {
@@ -590,6 +721,7 @@ func (tb *TextBuf) Close(afterFun func(canceled bool)) bool {
 					case 1:
 						tb.ClearChanged()
 						tb.AutoSaveDelete()
+						tb.JournalDelete()
 						tb.Close(afterFun)
 					case 2:
 						if afterFun != nil {
@@ -606,6 +738,7 @@ func (tb *TextBuf) Close(afterFun func(canceled bool)) bool {
 					case 0:
 						tb.ClearChanged()
 						tb.AutoSaveDelete()
+						tb.JournalDelete()
 						tb.Close(afterFun)
 					case 1:
 						if afterFun != nil {
@@ -617,9 +750,11 @@ func (tb *TextBuf) Close(afterFun func(canceled bool)) bool {
 		return false // awaiting decisions..
 	}
 	tb.TextBufSig.Emit(tb.This(), int64(TextBufClosed), nil)
+	tb.JournalDelete()
 	// for _, tve := range tb.Views {
 	// 	tve.SetBuf(nil) // automatically disconnects signals, views
 	// }
+	tb.SetReadOnly(false)
 	tb.New(1)
 	tb.Filename = ""
 	tb.ClearChanged()
@@ -795,10 +930,12 @@ func (tb *TextBuf) AppendTextLineMarkup(text []byte, markup []byte, signal bool)
 /////////////////////////////////////////////////////////////////////////////
 //   Views
 
-// AddView adds a viewer of this buffer -- connects our signals to the viewer
+// AddView adds a viewer of this buffer -- connects our signals to the viewer.
+// The buffer is registered as a gi.FindProvider while it has any viewers.
 func (tb *TextBuf) AddView(vw *TextView) {
 	tb.Views = append(tb.Views, vw)
 	tb.TextBufSig.Connect(vw.This(), TextViewBufSigRecv)
+	gi.FindProviders.Add(tb)
 }
 
 // DeleteView removes given viewer from our buffer
@@ -810,6 +947,9 @@ func (tb *TextBuf) DeleteView(vw *TextView) {
 		}
 	}
 	tb.TextBufSig.Disconnect(vw.This())
+	if len(tb.Views) == 0 {
+		gi.FindProviders.Remove(tb)
+	}
 }
 
 // ViewportFromView returns Viewport from textview, if avail
@@ -1099,6 +1239,7 @@ func (tb *TextBuf) DeleteTextImpl(st, ed lex.Pos) *textbuf.Edit {
 		tb.NLines = len(tb.Lines)
 		tb.LinesDeleted(tbe)
 	}
+	tb.JournalEdit(tbe)
 	return tbe
 }
 
@@ -1152,6 +1293,7 @@ func (tb *TextBuf) DeleteTextRectImpl(st, ed lex.Pos) *textbuf.Edit {
 		}
 	}
 	tb.LinesEdited(tbe)
+	tb.JournalEdit(tbe)
 	return tbe
 }
 
@@ -1229,6 +1371,7 @@ func (tb *TextBuf) InsertTextImpl(st lex.Pos, text []byte) *textbuf.Edit {
 		tbe = tb.RegionImpl(st, ed)
 		tb.LinesInserted(tbe)
 	}
+	tb.JournalEdit(tbe)
 	return tbe
 }
 
@@ -1305,6 +1448,7 @@ func (tb *TextBuf) InsertTextRectImpl(tbe *textbuf.Edit) *textbuf.Edit {
 	re.Delete = false
 	re.Reg.TimeNow()
 	tb.LinesEdited(re)
+	tb.JournalEdit(re)
 	return re
 }
 
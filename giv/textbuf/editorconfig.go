@@ -0,0 +1,300 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package textbuf
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EditorConfigFileName is the name of EditorConfig files (see editorconfig.org)
+const EditorConfigFileName = ".editorconfig"
+
+// EditorConfig are the EditorConfig properties that apply to a file, with
+// lower-case names and values (see EditorConfigFor)
+type EditorConfig map[string]string
+
+// EditorConfigSection is a section of an EditorConfig file: the properties
+// for the files matching its glob pattern
+type EditorConfigSection struct {
+	Glob  string            `desc:"glob pattern for the files in the section, relative to the directory of the EditorConfig file"`
+	Props map[string]string `desc:"properties, with lower-case names and values"`
+}
+
+// ParseEditorConfig parses the contents of an EditorConfig file, returning
+// whether it has root = true in its preamble, and its sections, in order
+func ParseEditorConfig(src []byte) (root bool, secs []EditorConfigSection) {
+	var cur *EditorConfigSection
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for sc.Scan() {
+		ln := strings.TrimSpace(sc.Text())
+		if ln == "" || ln[0] == '#' || ln[0] == ';' {
+			continue
+		}
+		if ln[0] == '[' && ln[len(ln)-1] == ']' {
+			secs = append(secs, EditorConfigSection{Glob: ln[1 : len(ln)-1], Props: map[string]string{}})
+			cur = &secs[len(secs)-1]
+			continue
+		}
+		eq := strings.IndexAny(ln, "=:")
+		if eq < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(ln[:eq]))
+		val := strings.ToLower(strings.TrimSpace(ln[eq+1:]))
+		if cur == nil {
+			if key == "root" {
+				root = val == "true"
+			}
+			continue
+		}
+		cur.Props[key] = val
+	}
+	return
+}
+
+// EditorConfigMatch returns true if given glob pattern of an EditorConfig
+// section matches given file path, which is relative to the directory of
+// the EditorConfig file and uses / separators.  A pattern without a / matches
+// the file name in any directory.  Supports *, **, ?, [chars], [!chars],
+// {s1,s2} and {n1..n2} as in the EditorConfig specification.
+func EditorConfigMatch(glob, rel string) bool {
+	if !strings.Contains(glob, "/") {
+		glob = "**/" + glob
+	} else if glob[0] == '/' {
+		glob = glob[1:]
+	}
+	re, ranges := editorConfigRegexp(glob)
+	rx, err := regexp.Compile("^" + re + "$")
+	if err != nil {
+		return false
+	}
+	sm := rx.FindStringSubmatch(rel)
+	if sm == nil {
+		return false
+	}
+	for i, rg := range ranges {
+		n, err := strconv.Atoi(sm[i+1])
+		if err != nil || n < rg[0] || n > rg[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// editorConfigRangeRe matches a {n1..n2} numeric range
+var editorConfigRangeRe = regexp.MustCompile(`^\{(-?[0-9]+)\.\.(-?[0-9]+)\}`)
+
+// editorConfigRegexp converts given EditorConfig glob into a regexp,
+// with a group for each numeric range, returned in order
+func editorConfigRegexp(glob string) (string, [][2]int) {
+	var sb strings.Builder
+	var ranges [][2]int
+	depth := 0 // of {} alternatives
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			}
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' { // **/ also matches no dirs
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			ed := strings.IndexByte(glob[i:], ']')
+			if ed < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			set := glob[i+1 : i+ed]
+			if strings.HasPrefix(set, "!") {
+				set = "^" + set[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(set, `\`, `\\`) + "]")
+			i += ed
+		case '{':
+			if m := editorConfigRangeRe.FindStringSubmatch(glob[i:]); m != nil {
+				n1, _ := strconv.Atoi(m[1])
+				n2, _ := strconv.Atoi(m[2])
+				ranges = append(ranges, [2]int{n1, n2})
+				sb.WriteString("([+-]?[0-9]+)")
+				i += len(m[0]) - 1
+				continue
+			}
+			if !strings.ContainsRune(glob[i:], '}') {
+				sb.WriteString(`\{`)
+				continue
+			}
+			depth++
+			sb.WriteString("(?:")
+		case '}':
+			if depth == 0 {
+				sb.WriteString(`\}`)
+				continue
+			}
+			depth--
+			sb.WriteString(")")
+		case ',':
+			if depth > 0 {
+				sb.WriteString("|")
+			} else {
+				sb.WriteString(",")
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return sb.String(), ranges
+}
+
+// EditorConfigFor returns the EditorConfig properties that apply to the
+// file at given path, from the EditorConfig files in its directory and the
+// ones above it, up to the first one with root = true: properties in files
+// closer to it, and in later sections of a file, take precedence.
+func EditorConfigFor(fpath string) (EditorConfig, error) {
+	afp, err := filepath.Abs(fpath)
+	if err != nil {
+		return nil, err
+	}
+	ec := EditorConfig{}
+	var files []string // closest first
+	var secsByFile [][]EditorConfigSection
+	dir := filepath.Dir(afp)
+	for {
+		fn := filepath.Join(dir, EditorConfigFileName)
+		if src, err := os.ReadFile(fn); err == nil {
+			root, secs := ParseEditorConfig(src)
+			files = append(files, dir)
+			secsByFile = append(secsByFile, secs)
+			if root {
+				break
+			}
+		}
+		pdir := filepath.Dir(dir)
+		if pdir == dir {
+			break
+		}
+		dir = pdir
+	}
+	for fi := len(files) - 1; fi >= 0; fi-- { // farthest first, so closer ones override
+		rel, err := filepath.Rel(files[fi], afp)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, sec := range secsByFile[fi] {
+			if !EditorConfigMatch(sec.Glob, rel) {
+				continue
+			}
+			for k, v := range sec.Props {
+				ec[k] = v
+			}
+		}
+	}
+	return ec, nil
+}
+
+// Int returns the value of given property as a positive int, and false if
+// it is not set, or not a positive int
+func (ec EditorConfig) Int(key string) (int, bool) {
+	n, err := strconv.Atoi(ec[key])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// Bool returns the value of given property as a bool, and false if it is
+// not set to true or false
+func (ec EditorConfig) Bool(key string) (val, ok bool) {
+	switch ec[key] {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+// ApplyOpts applies the indentation and line length properties to given
+// options: indent_style, indent_size, tab_width, and max_line_length, which
+// sets the column of a ruler and shows it, and the save properties:
+// trim_trailing_whitespace and insert_final_newline.
+func (ec EditorConfig) ApplyOpts(op *Opts) {
+	switch ec["indent_style"] {
+	case "tab":
+		op.SpaceIndent = false
+	case "space":
+		op.SpaceIndent = true
+	}
+	isz, hasIsz := ec.Int("indent_size")
+	tw, hasTw := ec.Int("tab_width")
+	switch {
+	case op.SpaceIndent && hasIsz:
+		op.TabSize = isz
+	case hasTw:
+		op.TabSize = tw
+	case hasIsz:
+		op.TabSize = isz
+	}
+	if ml, ok := ec.Int("max_line_length"); ok {
+		op.Rulers = []int{ml}
+		op.ShowRulers = true
+	}
+	if tr, ok := ec.Bool("trim_trailing_whitespace"); ok {
+		op.TrimTrailingSpace = tr
+	}
+	if fn, ok := ec.Bool("insert_final_newline"); ok {
+		op.FinalNewline = fn
+	}
+}
+
+// Encoding returns the encoding for the charset property, and false if it
+// is not set or not supported
+func (ec EditorConfig) Encoding() (Encodings, bool) {
+	switch ec["charset"] {
+	case "utf-8":
+		return UTF8, true
+	case "utf-8-bom":
+		return UTF8BOM, true
+	case "utf-16le":
+		return UTF16LE, true
+	case "utf-16be":
+		return UTF16BE, true
+	case "latin1":
+		return Latin1, true
+	}
+	return UTF8, false
+}
+
+// LineEnds returns the line ending style for the end_of_line property, and
+// false if it is not set or not supported (cr)
+func (ec EditorConfig) LineEnds() (LineEnds, bool) {
+	switch ec["end_of_line"] {
+	case "lf":
+		return LineEndLF, true
+	case "crlf":
+		return LineEndCRLF, true
+	}
+	return LineEndLF, false
+}
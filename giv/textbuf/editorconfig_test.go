@@ -0,0 +1,74 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package textbuf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEditorConfigMatch(t *testing.T) {
+	tests := []struct {
+		glob, rel string
+		match     bool
+	}{
+		{"*", "main.go", true},
+		{"*", "sub/dir/main.go", true},
+		{"*.go", "sub/main.go", true},
+		{"*.go", "main.py", false},
+		{"*.{js,py}", "lib/a.py", true},
+		{"*.{js,py}", "lib/a.go", false},
+		{"Makefile", "sub/Makefile", true},
+		{"lib/*.go", "lib/a.go", true},
+		{"lib/*.go", "lib/sub/a.go", false},
+		{"/lib/*.go", "lib/a.go", true},
+		{"lib/**.go", "lib/sub/a.go", true},
+		{"file[0-9].txt", "file3.txt", true},
+		{"file[!0-9].txt", "file3.txt", false},
+		{"file?.txt", "fileab.txt", false},
+		{"v{1..10}.txt", "v7.txt", true},
+		{"v{1..10}.txt", "v12.txt", false},
+		{"a\\*.txt", "a*.txt", true},
+		{"a\\*.txt", "ab.txt", false},
+	}
+	for _, ts := range tests {
+		if m := EditorConfigMatch(ts.glob, ts.rel); m != ts.match {
+			t.Errorf("glob: %q path: %q: match: %v != %v", ts.glob, ts.rel, m, ts.match)
+		}
+	}
+}
+
+func TestEditorConfigFor(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	top := "root = true\n\n[*]\nindent_style = tab\ncharset = utf-8\n\n# overrides\n[*.py]\nindent_style = space\nindent_size = 4\n"
+	os.WriteFile(filepath.Join(dir, EditorConfigFileName), []byte(top), 0644)
+	os.WriteFile(filepath.Join(sub, EditorConfigFileName), []byte("[*.py]\nIndent_Size = 2\nmax_line_length = 79\n"), 0644)
+
+	ec, err := EditorConfigFor(filepath.Join(sub, "a.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ec["indent_style"] != "space" || ec["indent_size"] != "2" || ec["charset"] != "utf-8" {
+		t.Errorf("wrong properties: %v", ec)
+	}
+	var op Opts
+	ec.ApplyOpts(&op)
+	if !op.SpaceIndent || op.TabSize != 2 || !op.ShowRulers || len(op.Rulers) != 1 || op.Rulers[0] != 79 {
+		t.Errorf("wrong options: %+v", op.EditorPrefs)
+	}
+	if enc, ok := ec.Encoding(); !ok || enc != UTF8 {
+		t.Errorf("wrong encoding: %v", enc)
+	}
+
+	ec, _ = EditorConfigFor(filepath.Join(sub, "a.go"))
+	if ec["indent_style"] != "tab" || ec["indent_size"] != "" {
+		t.Errorf("wrong properties: %v", ec)
+	}
+}
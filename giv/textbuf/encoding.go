@@ -0,0 +1,214 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package textbuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/goki/ki/kit"
+)
+
+// Encodings are the text file encodings that are detected and converted
+// to and from UTF-8, which is always used for the text in memory
+type Encodings int32
+
+const (
+	// UTF8 is UTF-8 without a byte order mark -- the default
+	UTF8 Encodings = iota
+
+	// UTF8BOM is UTF-8 with a byte order mark at the start
+	UTF8BOM
+
+	// UTF16LE is little-endian UTF-16, with a byte order mark
+	UTF16LE
+
+	// UTF16BE is big-endian UTF-16, with a byte order mark
+	UTF16BE
+
+	// Latin1 is ISO-8859-1, where each byte is one rune -- used for
+	// text that is not valid UTF-8 or UTF-16
+	Latin1
+
+	EncodingsN
+)
+
+//go:generate stringer -type=Encodings
+
+var KiT_Encodings = kit.Enums.AddEnum(EncodingsN, kit.NotBitFlag, nil)
+
+func (ev Encodings) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *Encodings) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// LineEnds are the styles of line endings in text files
+type LineEnds int32
+
+const (
+	// LineEndLF is Unix-style LF (\n) line ending -- the default,
+	// and always used for text in memory
+	LineEndLF LineEnds = iota
+
+	// LineEndCRLF is Windows / DOS style CR LF (\r\n) line ending
+	LineEndCRLF
+
+	LineEndsN
+)
+
+//go:generate stringer -type=LineEnds
+
+var KiT_LineEnds = kit.Enums.AddEnum(LineEndsN, kit.NotBitFlag, nil)
+
+func (ev LineEnds) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *LineEnds) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectEncoding returns the encoding of given raw file contents, from a
+// byte order mark if present, and otherwise heuristically: valid UTF-8 is
+// UTF8, text with many zero bytes in alternating positions is UTF-16
+// (as is typical for mostly-ASCII text), and anything else is Latin1.
+func DetectEncoding(b []byte) Encodings {
+	switch {
+	case bytes.HasPrefix(b, bomUTF8):
+		return UTF8BOM
+	case bytes.HasPrefix(b, bomUTF16LE):
+		return UTF16LE
+	case bytes.HasPrefix(b, bomUTF16BE):
+		return UTF16BE
+	}
+	if utf8.Valid(b) {
+		return UTF8
+	}
+	n := len(b)
+	if n >= 2 && n%2 == 0 {
+		even, odd := 0, 0
+		for i := 0; i < n; i += 2 {
+			if b[i] == 0 {
+				even++
+			}
+			if b[i+1] == 0 {
+				odd++
+			}
+		}
+		half := n / 2
+		switch {
+		case odd > half/2 && even < half/10:
+			return UTF16LE
+		case even > half/2 && odd < half/10:
+			return UTF16BE
+		}
+	}
+	return Latin1
+}
+
+// DecodeToUTF8 converts given raw file contents in given encoding to UTF-8,
+// removing any byte order mark.
+func DecodeToUTF8(b []byte, enc Encodings) ([]byte, error) {
+	switch enc {
+	case UTF8:
+		return b, nil
+	case UTF8BOM:
+		return bytes.TrimPrefix(b, bomUTF8), nil
+	case UTF16LE, UTF16BE:
+		var bo binary.ByteOrder = binary.LittleEndian
+		bom := bomUTF16LE
+		if enc == UTF16BE {
+			bo = binary.BigEndian
+			bom = bomUTF16BE
+		}
+		b = bytes.TrimPrefix(b, bom)
+		if len(b)%2 != 0 {
+			return nil, fmt.Errorf("textbuf.DecodeToUTF8: odd number of bytes for %v", enc)
+		}
+		u16 := make([]uint16, len(b)/2)
+		for i := range u16 {
+			u16[i] = bo.Uint16(b[2*i:])
+		}
+		return []byte(string(utf16.Decode(u16))), nil
+	case Latin1:
+		var sb bytes.Buffer
+		sb.Grow(len(b) + len(b)/4)
+		for _, c := range b {
+			sb.WriteRune(rune(c))
+		}
+		return sb.Bytes(), nil
+	}
+	return nil, fmt.Errorf("textbuf.DecodeToUTF8: invalid encoding: %v", enc)
+}
+
+// EncodeFromUTF8 converts given UTF-8 text to given encoding, adding a byte
+// order mark as appropriate.  For Latin1, runes that cannot be represented
+// are replaced with '?', and an error is returned noting that.
+func EncodeFromUTF8(b []byte, enc Encodings) ([]byte, error) {
+	switch enc {
+	case UTF8:
+		return b, nil
+	case UTF8BOM:
+		return append(append([]byte{}, bomUTF8...), b...), nil
+	case UTF16LE, UTF16BE:
+		var bo binary.ByteOrder = binary.LittleEndian
+		bom := bomUTF16LE
+		if enc == UTF16BE {
+			bo = binary.BigEndian
+			bom = bomUTF16BE
+		}
+		u16 := utf16.Encode(bytes.Runes(b))
+		ob := make([]byte, len(bom)+2*len(u16))
+		copy(ob, bom)
+		for i, u := range u16 {
+			bo.PutUint16(ob[len(bom)+2*i:], u)
+		}
+		return ob, nil
+	case Latin1:
+		ob := make([]byte, 0, len(b))
+		nbad := 0
+		for _, r := range string(b) {
+			if r > 0xFF {
+				nbad++
+				r = '?'
+			}
+			ob = append(ob, byte(r))
+		}
+		if nbad > 0 {
+			return ob, fmt.Errorf("textbuf.EncodeFromUTF8: %d characters could not be represented in %v", nbad, enc)
+		}
+		return ob, nil
+	}
+	return nil, fmt.Errorf("textbuf.EncodeFromUTF8: invalid encoding: %v", enc)
+}
+
+// DetectLineEnds returns the line ending style of given UTF-8 text: CRLF
+// if the majority of line endings are CRLF, else LF
+func DetectLineEnds(b []byte) LineEnds {
+	nlf := bytes.Count(b, []byte("\n"))
+	ncrlf := bytes.Count(b, []byte("\r\n"))
+	if ncrlf > 0 && ncrlf*2 >= nlf {
+		return LineEndCRLF
+	}
+	return LineEndLF
+}
+
+// ToLF converts all CRLF line endings in given text to LF
+func ToLF(b []byte) []byte {
+	if !bytes.Contains(b, []byte("\r\n")) {
+		return b
+	}
+	return bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+}
+
+// FromLF converts LF line endings in given text to given style
+func FromLF(b []byte, le LineEnds) []byte {
+	if le != LineEndCRLF {
+		return b
+	}
+	return bytes.ReplaceAll(b, []byte("\n"), []byte("\r\n"))
+}
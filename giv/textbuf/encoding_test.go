@@ -0,0 +1,48 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package textbuf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncoding(t *testing.T) {
+	txt := []byte("héllo wörld\nline two\n")
+	for enc := UTF8; enc < EncodingsN; enc++ {
+		eb, err := EncodeFromUTF8(txt, enc)
+		if err != nil {
+			t.Errorf("%v: encode error: %v", enc, err)
+		}
+		denc := DetectEncoding(eb)
+		if denc != enc {
+			t.Errorf("%v: detected as: %v", enc, denc)
+		}
+		db, err := DecodeToUTF8(eb, denc)
+		if err != nil {
+			t.Errorf("%v: decode error: %v", enc, err)
+		}
+		if !bytes.Equal(db, txt) {
+			t.Errorf("%v: round trip failed: %q", enc, db)
+		}
+	}
+}
+
+func TestLineEnds(t *testing.T) {
+	crlf := []byte("one\r\ntwo\r\nthree\r\n")
+	if le := DetectLineEnds(crlf); le != LineEndCRLF {
+		t.Errorf("line ends detected as: %v", le)
+	}
+	lf := ToLF(crlf)
+	if !bytes.Equal(lf, []byte("one\ntwo\nthree\n")) {
+		t.Errorf("ToLF failed: %q", lf)
+	}
+	if le := DetectLineEnds(lf); le != LineEndLF {
+		t.Errorf("line ends detected as: %v", le)
+	}
+	if !bytes.Equal(FromLF(lf, LineEndCRLF), crlf) {
+		t.Errorf("FromLF failed: %q", FromLF(lf, LineEndCRLF))
+	}
+}
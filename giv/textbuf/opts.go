@@ -14,10 +14,12 @@ import (
 // Opts contains options for TextBufs -- contains everything necessary to
 // conditionalize editing of a given text file
 type Opts struct {
-	gi.EditorPrefs `desc:"editor prefs from gogi prefs"`
-	CommentLn      string `desc:"character(s) that start a single-line comment -- if empty then multi-line comment syntax will be used"`
-	CommentSt      string `desc:"character(s) that start a multi-line comment or one that requires both start and end"`
-	CommentEd      string `desc:"character(s) that end a multi-line comment or one that requires both start and end"`
+	gi.EditorPrefs    `desc:"editor prefs from gogi prefs"`
+	CommentLn         string `desc:"character(s) that start a single-line comment -- if empty then multi-line comment syntax will be used"`
+	CommentSt         string `desc:"character(s) that start a multi-line comment or one that requires both start and end"`
+	CommentEd         string `desc:"character(s) that end a multi-line comment or one that requires both start and end"`
+	TrimTrailingSpace bool   `desc:"remove trailing whitespace from all lines when saving -- set from .editorconfig files"`
+	FinalNewline      bool   `desc:"ensure that the file ends with a newline when saving -- set from .editorconfig files"`
 }
 
 // CommentStrs returns the comment start and end strings, using line-based CommentLn first if set
@@ -39,12 +41,16 @@ func (tb *Opts) IndentChar() indent.Char {
 	return indent.Tab
 }
 
-// ConfigSupported configures options based on the supported language info in GoPi
-// returns true if supported
+// ConfigSupported configures options based on the supported language info in GoPi,
+// and the editor preferences for the language in gi.Prefs.EditorLangs, if any,
+// which override them -- returns true if supported
 func (tb *Opts) ConfigSupported(sup filecat.Supported) bool {
 	if sup == filecat.NoSupport {
 		return false
 	}
+	if elp, has := gi.Prefs.EditorLangs[sup]; has && elp != nil {
+		defer elp.Apply(&tb.EditorPrefs)
+	}
 	lp, ok := pi.StdLangProps[sup]
 	if !ok {
 		return false
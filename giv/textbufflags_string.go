@@ -17,12 +17,13 @@ func _() {
 	_ = x[TextBufMarkingUp-25]
 	_ = x[TextBufChanged-26]
 	_ = x[TextBufFileModOk-27]
-	_ = x[TextBufFlagsN-28]
+	_ = x[TextBufReadOnly-28]
+	_ = x[TextBufFlagsN-29]
 }
 
-const _TextBufFlags_name = "TextBufAutoSavingTextBufMarkingUpTextBufChangedTextBufFileModOkTextBufFlagsN"
+const _TextBufFlags_name = "TextBufAutoSavingTextBufMarkingUpTextBufChangedTextBufFileModOkTextBufReadOnlyTextBufFlagsN"
 
-var _TextBufFlags_index = [...]uint8{0, 17, 33, 47, 63, 76}
+var _TextBufFlags_index = [...]uint8{0, 17, 33, 47, 63, 78, 91}
 
 func (i TextBufFlags) String() string {
 	i -= 24
@@ -0,0 +1,231 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/ki/ki"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//		Journal
+
+// The edit journal records every edit made to the buffer since it was last
+// opened or saved, by appending each textbuf.Edit (as one line of JSON) to a
+// recovery file next to the file being edited.  Unlike AutoSave, which
+// periodically writes the entire text, this records edits as they happen
+// so nothing is lost after a crash.  If a journal is found when opening a
+// file, Open sets it aside, so that new edits go to a new journal, and
+// offers to replay it to recover the unsaved changes.  The journal is
+// deleted when the buffer is saved, reverted, or closed without saving.
+// Set the Journal flag to enable.
+
+// JournalFilename returns the edit journal filename
+func (tb *TextBuf) JournalFilename() string {
+	path, fn := filepath.Split(string(tb.Filename))
+	if fn == "" {
+		fn = "new_file_" + tb.Nm
+	}
+	return filepath.Join(path, "#"+fn+".journal#")
+}
+
+// JournalRecoverFilename returns the filename of the journal found when
+// opening the file, which is set aside for recovery (see JournalSetAside)
+func (tb *TextBuf) JournalRecoverFilename() string {
+	path, fn := filepath.Split(tb.JournalFilename())
+	return filepath.Join(path, "#"+fn[1:len(fn)-1]+".recover#")
+}
+
+// JournalEdit appends given edit to the journal file, if Journal is on --
+// called automatically for each edit -- Must be called under LinesMu.Lock.
+func (tb *TextBuf) JournalEdit(tbe *textbuf.Edit) {
+	if !tb.Journal || tbe == nil {
+		return
+	}
+	tb.JournalMu.Lock()
+	defer tb.JournalMu.Unlock()
+	if tb.JournalFile == nil {
+		jfn := tb.JournalFilename()
+		jf, err := os.OpenFile(jfn, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Printf("giv.TextBuf: Could not open journal file: %v, error: %v\n", jfn, err)
+			tb.Journal = false // don't keep trying
+			return
+		}
+		tb.JournalFile = jf
+	}
+	b, err := json.Marshal(tbe)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	b = append(b, '\n')
+	if _, err := tb.JournalFile.Write(b); err != nil {
+		log.Printf("giv.TextBuf: Could not write to journal file: %v, error: %v\n", tb.JournalFile.Name(), err)
+	}
+}
+
+// JournalClose closes the journal file if it is open, leaving it on disk
+func (tb *TextBuf) JournalClose() {
+	tb.JournalMu.Lock()
+	defer tb.JournalMu.Unlock()
+	if tb.JournalFile != nil {
+		tb.JournalFile.Close()
+		tb.JournalFile = nil
+	}
+}
+
+// JournalDelete closes and deletes any existing journal file -- called
+// when the buffer is saved, reverted, or closed without saving
+func (tb *TextBuf) JournalDelete() {
+	tb.JournalClose()
+	os.Remove(tb.JournalFilename())
+}
+
+// JournalCheck checks if a journal file exists, or one set aside for
+// recovery, indicating that unsaved changes were lost, e.g., due to a
+// crash -- Open calls JournalSetAside automatically if Journal is on, and
+// offers to replay it.
+func (tb *TextBuf) JournalCheck() bool {
+	if _, err := os.Stat(tb.JournalFilename()); err == nil {
+		return true
+	}
+	_, err := os.Stat(tb.JournalRecoverFilename())
+	return err == nil
+}
+
+// JournalSetAside renames an existing journal file to the one set aside for
+// recovery (JournalRecoverFilename), replacing any previous one, so that
+// the edits made before it is replayed or discarded go to a new journal,
+// and are not replayed with it -- returns true if there is a journal set
+// aside for recovery.  Must be called before any edits are journaled.
+func (tb *TextBuf) JournalSetAside() bool {
+	tb.JournalClose()
+	jfn := tb.JournalFilename()
+	rfn := tb.JournalRecoverFilename()
+	if _, err := os.Stat(jfn); err == nil {
+		if err := os.Rename(jfn, rfn); err != nil {
+			log.Printf("giv.TextBuf: Could not set aside journal file: %v, error: %v\n", jfn, err)
+			return false
+		}
+	}
+	_, err := os.Stat(rfn)
+	return err == nil
+}
+
+// JournalRecoverDelete deletes the journal set aside for recovery, if any
+// (see JournalSetAside), discarding its changes
+func (tb *TextBuf) JournalRecoverDelete() {
+	os.Remove(tb.JournalRecoverFilename())
+}
+
+// JournalEdits reads the edits from the journal set aside for recovery (see
+// JournalSetAside), in order
+func (tb *TextBuf) JournalEdits() ([]*textbuf.Edit, error) {
+	return readJournal(tb.JournalRecoverFilename())
+}
+
+// readJournal reads the edits from given journal file, in order
+func readJournal(jfn string) ([]*textbuf.Edit, error) {
+	jf, err := os.Open(jfn)
+	if err != nil {
+		return nil, err
+	}
+	defer jf.Close()
+	var edits []*textbuf.Edit
+	sc := bufio.NewScanner(jf)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	for sc.Scan() {
+		ln := sc.Bytes()
+		if len(ln) == 0 {
+			continue
+		}
+		tbe := &textbuf.Edit{}
+		if err := json.Unmarshal(ln, tbe); err != nil {
+			// a partial last line is expected after a crash
+			log.Printf("giv.TextBuf: journal file: %v: stopping at invalid edit: %v\n", jfn, err)
+			break
+		}
+		edits = append(edits, tbe)
+	}
+	return edits, sc.Err()
+}
+
+// JournalReplay replays the edits from the journal set aside for recovery
+// (see JournalSetAside) onto the contents of the file, which must be the
+// same as when the journal was started, i.e., the file must not have been
+// saved since.  Any edits made since opening the file are reverted first,
+// as the journaled edits apply to the file contents.  The edits are saved
+// to the undo stack, and re-recorded into a new journal, so the buffer is
+// in the same state as before the crash.
+func (tb *TextBuf) JournalReplay() error {
+	edits, err := tb.JournalEdits()
+	tb.JournalRecoverDelete()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+	if tb.IsChanged() {
+		tb.Revert()
+	}
+	tb.JournalDelete() // the new journal, of the reverted edits
+	bufUpdt, winUpdt, autoSave := tb.BatchUpdateStart()
+	defer tb.BatchUpdateEnd(bufUpdt, winUpdt, autoSave)
+	for i, tbe := range edits {
+		var re *textbuf.Edit
+		switch {
+		case tbe.Rect && tbe.Delete:
+			re = tb.DeleteTextRect(tbe.Reg.Start, tbe.Reg.End, false)
+		case tbe.Rect:
+			re = tb.InsertTextRect(tbe, false)
+		case tbe.Delete:
+			re = tb.DeleteText(tbe.Reg.Start, tbe.Reg.End, false)
+		default:
+			re = tb.InsertText(tbe.Reg.Start, tbe.ToBytes(), false)
+		}
+		if re == nil {
+			err = fmt.Errorf("giv.TextBuf JournalReplay: edit %d of %d could not be applied -- file may have changed", i+1, len(edits))
+			log.Println(err)
+			break
+		}
+	}
+	tb.Refresh()
+	tb.ReMarkup()
+	return err
+}
+
+// JournalRecoverPrompt offers to replay the journal set aside for recovery
+// (see JournalSetAside), after the file has been opened, or otherwise
+// deletes it.  It returns before the prompt is answered, and any edits made
+// meanwhile, e.g., in other views of the buffer, are journaled anew, and
+// reverted by recovering the changes.
+func (tb *TextBuf) JournalRecoverPrompt() {
+	vp := tb.ViewportFromView()
+	gi.ChoiceDialog(vp, gi.DlgOpts{Title: "Recover Unsaved Changes?",
+		Prompt: fmt.Sprintf("A journal of unsaved changes was found for file: %v, probably from a crash -- do you want to recover these changes?  Any changes made since opening the file will be replaced by them.", tb.Filename)},
+		[]string{"Recover Changes", "Discard Changes"},
+		tb.This(), func(recv, send ki.Ki, sig int64, data any) {
+			switch sig {
+			case 0:
+				err := tb.JournalReplay()
+				if err != nil {
+					gi.PromptDialog(vp, gi.DlgOpts{Title: "Could not Recover all Changes", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
+				}
+			case 1:
+				tb.JournalRecoverDelete()
+			}
+		})
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/ki/ki"
+	"github.com/goki/pi/lex"
+)
+
+// noIconMgr is an icon manager without icons, for the file info of the
+// buffers, as the svg one can't be imported here
+type noIconMgr struct{}
+
+func (im *noIconMgr) IsValid(iconName string) bool               { return false }
+func (im *noIconMgr) SetIcon(ic *gi.Icon, iconName string) error { return nil }
+func (im *noIconMgr) IconByName(name string) (ki.Ki, error)      { return nil, nil }
+func (im *noIconMgr) IconList(alphaSort bool) []gi.IconName      { return nil }
+
+// prefsApp is an app that only has prefs dirs, for opening the buffers
+// without a driver
+type prefsApp struct {
+	oswin.App
+	dir string
+}
+
+func (app *prefsApp) GoGiPrefsDir() string { return app.dir }
+func (app *prefsApp) AppPrefsDir() string  { return app.dir }
+
+// openJournalBuf returns a new buffer with given file opened, with Journal on
+func openJournalBuf(t *testing.T, fn string) *TextBuf {
+	if gi.TheIconMgr == nil {
+		gi.TheIconMgr = &noIconMgr{}
+	}
+	if oswin.TheApp == nil {
+		oswin.TheApp = &prefsApp{dir: t.TempDir()}
+		t.Cleanup(func() { oswin.TheApp = nil })
+	}
+	tb := &TextBuf{}
+	tb.InitName(tb, "journal-test")
+	tb.Journal = true
+	if err := tb.OpenFile(gi.FileName(fn)); err != nil {
+		t.Fatal(err)
+	}
+	return tb
+}
+
+func TestJournalReplay(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(fn, []byte("one\ntwo\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := "one\n2\nfour\nthree\n"
+
+	// edit, and "crash", leaving the journal
+	tb := openJournalBuf(t, fn)
+	tb.DeleteText(lex.Pos{Ln: 1, Ch: 0}, lex.Pos{Ln: 1, Ch: 3}, false)
+	tb.InsertText(lex.Pos{Ln: 1, Ch: 0}, []byte("2"), false)
+	tb.InsertText(lex.Pos{Ln: 2, Ch: 0}, []byte("four\n"), false)
+	if got := string(tb.Text()); got != want {
+		t.Fatalf("edited text: got %q, want %q", got, want)
+	}
+	tb.JournalClose()
+
+	// reopen, with an edit made before answering the prompt, which must not
+	// go into the journal being recovered
+	tb = openJournalBuf(t, fn)
+	if !tb.JournalSetAside() {
+		t.Fatal("journal not set aside for recovery")
+	}
+	if _, err := os.Stat(tb.JournalFilename()); !os.IsNotExist(err) {
+		t.Errorf("journal still there after being set aside: %v", err)
+	}
+	tb.InsertText(lex.Pos{Ln: 0, Ch: 0}, []byte("zero\n"), false)
+	edits, err := tb.JournalEdits()
+	if err != nil || len(edits) != 3 {
+		t.Fatalf("journal set aside: got %d edits, want 3: %v", len(edits), err)
+	}
+	if err := tb.JournalReplay(); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(tb.Text()); got != want {
+		t.Errorf("recovered text: got %q, want %q", got, want)
+	}
+	if tb.JournalCheck() {
+		// the new journal has the replayed edits, for another crash
+		tb.JournalClose()
+		ntb := openJournalBuf(t, fn)
+		ntb.JournalSetAside()
+		if err := ntb.JournalReplay(); err != nil {
+			t.Fatal(err)
+		}
+		if got := string(ntb.Text()); got != want {
+			t.Errorf("text recovered from the replayed journal: got %q, want %q", got, want)
+		}
+		ntb.JournalDelete()
+	} else {
+		t.Error("replayed edits not journaled")
+	}
+	if _, err := os.Stat(tb.JournalRecoverFilename()); !os.IsNotExist(err) {
+		t.Errorf("journal set aside still there after recovery: %v", err)
+	}
+}
+
+func TestJournalDiscard(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(fn, []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tb := openJournalBuf(t, fn)
+	tb.InsertText(lex.Pos{Ln: 0, Ch: 0}, []byte("x"), false)
+	tb.JournalClose()
+
+	tb = openJournalBuf(t, fn)
+	tb.JournalSetAside()
+	tb.InsertText(lex.Pos{Ln: 0, Ch: 0}, []byte("y"), false)
+	tb.JournalRecoverDelete()
+	tb.JournalClose()
+	if _, err := os.Stat(tb.JournalRecoverFilename()); !os.IsNotExist(err) {
+		t.Errorf("journal set aside still there after discarding it: %v", err)
+	}
+	edits, _ := readJournal(tb.JournalFilename())
+	if len(edits) != 1 || string(edits[0].ToBytes()) != "y" {
+		t.Errorf("new journal: got %v, want the edit made after opening", edits)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"unicode"
+
+	"github.com/goki/pi/filecat"
+	"github.com/goki/pi/lex"
+)
+
+// TextFormatFunc returns the formatted version of given source text, or
+// an error if it cannot be formatted (e.g., due to a syntax error)
+type TextFormatFunc func(src []byte) ([]byte, error)
+
+// TextFormatters are the functions that format text in given language, used
+// by TextBuf FormatText, e.g., when saving with the FormatOnSave editor option
+// -- has gofmt for Go by default, and others can be added.
+var TextFormatters = map[filecat.Supported]TextFormatFunc{
+	filecat.Go: format.Source,
+}
+
+// FormatText formats the text with the TextFormatters function for its
+// language, applying only the lines that changed as edits, so that it can
+// be undone and the views keep their positions -- returns an error if
+// there is no formatter for the language, or it fails.
+func (tb *TextBuf) FormatText() error {
+	fun, has := TextFormatters[tb.Info.Sup]
+	if !has || fun == nil {
+		return fmt.Errorf("giv.TextBuf: no formatter for language: %v", tb.Info.Sup)
+	}
+	src := tb.Text()
+	out, err := fun(src)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(src, out) {
+		return nil
+	}
+	ob := &TextBuf{}
+	ob.InitName(ob, "format-tmp")
+	ob.SetText(out)
+	tb.PatchFromBuf(ob, tb.DiffBufs(ob), true)
+	return nil
+}
+
+// SaveEdits makes the edits that are done when saving, according to the
+// Opts: FormatText if FormatOnSave and there is a formatter for the
+// language, TrimTrailingSpace, and InsertFinalNewline -- returns true if
+// any of them were done, so the text must be updated from the lines.
+func (tb *TextBuf) SaveEdits() bool {
+	did := false
+	if tb.Opts.FormatOnSave && TextFormatters[tb.Info.Sup] != nil {
+		if err := tb.FormatText(); err != nil { // save anyway
+			log.Println(err)
+		}
+		did = true
+	}
+	if tb.Opts.TrimTrailingSpace {
+		tb.TrimTrailingSpace()
+		did = true
+	}
+	if tb.Opts.FinalNewline {
+		tb.InsertFinalNewline()
+		did = true
+	}
+	return did
+}
+
+// TrimTrailingSpace removes the whitespace at the end of all lines
+func (tb *TextBuf) TrimTrailingSpace() {
+	bufUpdt, winUpdt, autoSave := tb.BatchUpdateStart()
+	defer tb.BatchUpdateEnd(bufUpdt, winUpdt, autoSave)
+	nln := tb.NumLines()
+	for ln := 0; ln < nln; ln++ {
+		lr := tb.Line(ln)
+		ed := len(lr)
+		st := ed
+		for st > 0 && unicode.IsSpace(lr[st-1]) {
+			st--
+		}
+		if st < ed {
+			tb.DeleteText(lex.Pos{Ln: ln, Ch: st}, lex.Pos{Ln: ln, Ch: ed}, EditSignal)
+		}
+	}
+}
+
+// InsertFinalNewline adds a newline at the end of the text if it does not
+// end with one (i.e., its last line is not empty)
+func (tb *TextBuf) InsertFinalNewline() {
+	ed := tb.EndPos()
+	if ed.Ch == 0 {
+		return
+	}
+	tb.InsertText(ed, []byte("\n"), EditSignal)
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"image"
+	"sort"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+	"github.com/goki/pi/lex"
+	"github.com/goki/vci"
+)
+
+// TextGutterShapes are the shapes that can be drawn for a mark in a
+// TextView gutter column
+type TextGutterShapes int32
+
+const (
+	// GutterDot draws a filled circle, e.g., for breakpoints
+	GutterDot TextGutterShapes = iota
+
+	// GutterBar draws a bar filling the full width and height of the
+	// column for the line, e.g., for change bars
+	GutterBar
+
+	// GutterThinBar draws a bar of a quarter of the height of the line at its
+	// top, e.g., for marking deleted lines in change bars
+	GutterThinBar
+
+	// GutterText draws the mark Text, e.g., for bookmarks or fold markers
+	GutterText
+
+	TextGutterShapesN
+)
+
+//go:generate stringer -type=TextGutterShapes
+
+var KiT_TextGutterShapes = kit.Enums.AddEnum(TextGutterShapesN, kit.NotBitFlag, nil)
+
+// TextGutterMark is a mark shown for a line in a TextView gutter column
+type TextGutterMark struct {
+	Shape TextGutterShapes `desc:"shape to draw"`
+	Color gist.Color       `desc:"color of the mark"`
+	Text  string           `desc:"text to draw for GutterText shape"`
+}
+
+// TextGutterMarkFunc returns the mark for given line (0-based) in the
+// given view, and false if the line has no mark
+type TextGutterMarkFunc func(tv *TextView, ln int) (TextGutterMark, bool)
+
+// TextGutterClickFunc is called when the user clicks in a gutter column on
+// given line (0-based) in the given view
+type TextGutterClickFunc func(gc *TextGutterCol, tv *TextView, ln int, me *mouse.Event)
+
+// TextGutterCol is one column in the gutter of a TextView, shown to the left
+// of the line numbers, for marks such as breakpoints, bookmarks, version
+// control change bars, or fold markers.  Marks are taken from the Marks map
+// (per line), or from MarkFunc if set.  Add columns to TextView.Gutter --
+// see NewGutterBreakpoints, NewGutterBookmarks, NewGutterChanges.
+type TextGutterCol struct {
+	Name      string                 `desc:"name of the column, for finding it"`
+	Width     float32                `desc:"width of the column, in units of character widths (Ch)"`
+	Marks     map[int]TextGutterMark `desc:"marks for lines (0-based), used if MarkFunc is nil -- use SetMark and DeleteMark"`
+	Mark      TextGutterMark         `desc:"default mark set on lines by ToggleMark, e.g., when clicking"`
+	Toggle    bool                   `desc:"if true, clicking on a line toggles the default Mark on the line, before calling ClickFunc"`
+	MarkFunc  TextGutterMarkFunc     `view:"-" json:"-" xml:"-" desc:"optional function for getting the marks for each line dynamically"`
+	ClickFunc TextGutterClickFunc    `view:"-" json:"-" xml:"-" desc:"optional function called when clicking on a line in this column"`
+}
+
+// LineMark returns the mark for given line in given view, and false if none
+func (gc *TextGutterCol) LineMark(tv *TextView, ln int) (TextGutterMark, bool) {
+	if gc.MarkFunc != nil {
+		return gc.MarkFunc(tv, ln)
+	}
+	mk, has := gc.Marks[ln]
+	return mk, has
+}
+
+// SetMark sets the mark for given line (0-based)
+func (gc *TextGutterCol) SetMark(ln int, mk TextGutterMark) {
+	if gc.Marks == nil {
+		gc.Marks = make(map[int]TextGutterMark)
+	}
+	gc.Marks[ln] = mk
+}
+
+// DeleteMark deletes any mark for given line (0-based)
+func (gc *TextGutterCol) DeleteMark(ln int) {
+	delete(gc.Marks, ln)
+}
+
+// HasMark returns true if given line (0-based) has a mark in the Marks map
+func (gc *TextGutterCol) HasMark(ln int) bool {
+	_, has := gc.Marks[ln]
+	return has
+}
+
+// ToggleMark toggles the default Mark on given line, returning true if
+// the line is now marked
+func (gc *TextGutterCol) ToggleMark(ln int) bool {
+	if gc.HasMark(ln) {
+		gc.DeleteMark(ln)
+		return false
+	}
+	gc.SetMark(ln, gc.Mark)
+	return true
+}
+
+// ClearMarks deletes all marks
+func (gc *TextGutterCol) ClearMarks() {
+	gc.Marks = nil
+}
+
+// MarkedLines returns the lines (0-based) that have marks, in order
+func (gc *TextGutterCol) MarkedLines() []int {
+	var lns []int
+	for ln := range gc.Marks {
+		lns = append(lns, ln)
+	}
+	sort.Ints(lns)
+	return lns
+}
+
+// NewGutterBreakpoints returns a new gutter column for breakpoints, which
+// toggles a red dot on the line when clicked -- set ClickFunc to be notified.
+func NewGutterBreakpoints() *TextGutterCol {
+	gc := &TextGutterCol{Name: "breakpoints", Width: 1.5, Toggle: true}
+	gc.Mark.Shape = GutterDot
+	gc.Mark.Color.SetString("red", nil)
+	return gc
+}
+
+// NewGutterBookmarks returns a new gutter column for bookmarks, which
+// toggles a bookmark marker on the line when clicked.
+func NewGutterBookmarks() *TextGutterCol {
+	gc := &TextGutterCol{Name: "bookmarks", Width: 1.5, Toggle: true}
+	gc.Mark.Shape = GutterText
+	gc.Mark.Text = "»"
+	gc.Mark.Color.SetString("blue", nil)
+	return gc
+}
+
+// NewGutterChanges returns a new gutter column for version control change
+// bars -- use SetChanges to set them from diffs.
+func NewGutterChanges() *TextGutterCol {
+	return &TextGutterCol{Name: "changes", Width: 0.5}
+}
+
+// GutterChangeColors are the colors used for added, modified, and deleted
+// lines in change bars, by SetChanges
+var GutterChangeColors = [3]gist.Color{
+	{R: 0x40, G: 0xb0, B: 0x40, A: 0xff},
+	{R: 0x40, G: 0x80, B: 0xe0, A: 0xff},
+	{R: 0xe0, G: 0x40, B: 0x40, A: 0xff},
+}
+
+// SetChanges sets change bar marks from given diffs, which convert an
+// original version (e.g., the version control HEAD revision) into the
+// current text in the view: inserted lines are marked as added, replaced
+// lines as modified, and deleted lines with a thin bar at the line
+// after the deletion.  See TextBuf.DiffBufs and textbuf.DiffLines.
+func (gc *TextGutterCol) SetChanges(diffs textbuf.Diffs) {
+	gc.ClearMarks()
+	for _, df := range diffs {
+		switch df.Tag {
+		case 'i':
+			for ln := df.J1; ln < df.J2; ln++ {
+				gc.SetMark(ln, TextGutterMark{Shape: GutterBar, Color: GutterChangeColors[0]})
+			}
+		case 'r':
+			for ln := df.J1; ln < df.J2; ln++ {
+				gc.SetMark(ln, TextGutterMark{Shape: GutterBar, Color: GutterChangeColors[1]})
+			}
+		case 'd':
+			gc.SetMark(df.J1, TextGutterMark{Shape: GutterThinBar, Color: GutterChangeColors[2]})
+		}
+	}
+}
+
+// SetChangesFromVCS sets change bar marks from the differences between the
+// current text in given view and the contents of its file at given revision
+// in given version control repository ("" = HEAD).
+func (gc *TextGutterCol) SetChangesFromVCS(tv *TextView, repo vci.Repo, rev string) error {
+	if tv.Buf == nil {
+		return nil
+	}
+	fb, err := repo.FileContents(string(tv.Buf.Filename), rev)
+	if err != nil {
+		return err
+	}
+	astr := textbuf.BytesToLineStrings(fb, false)
+	gc.SetChanges(textbuf.DiffLines(astr, tv.Buf.Strings(false)))
+	tv.UpdateSig()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TextView gutter
+
+// HasGutter returns true if view is showing a gutter: line numbers
+// and / or any Gutter columns
+func (tv *TextView) HasGutter() bool {
+	return tv.HasLineNos() || len(tv.Gutter) > 0
+}
+
+// AddGutterCol adds given column to the gutter, returning it
+func (tv *TextView) AddGutterCol(gc *TextGutterCol) *TextGutterCol {
+	tv.Gutter = append(tv.Gutter, gc)
+	return gc
+}
+
+// GutterColByName returns the gutter column with given name, or nil if not found
+func (tv *TextView) GutterColByName(name string) *TextGutterCol {
+	for _, gc := range tv.Gutter {
+		if gc.Name == name {
+			return gc
+		}
+	}
+	return nil
+}
+
+// GutterColsWidth returns the total width of the Gutter columns, in dots
+func (tv *TextView) GutterColsWidth() float32 {
+	wd := float32(0)
+	for _, gc := range tv.Gutter {
+		wd += gc.Width
+	}
+	return wd * tv.Sty.Font.Face.Metrics.Ch
+}
+
+// GutterColAt returns the gutter column at given x position relative to the
+// start of the view, and nil if not within a column
+func (tv *TextView) GutterColAt(x int) *TextGutterCol {
+	ch := tv.Sty.Font.Face.Metrics.Ch
+	cx := tv.Sty.BoxSpace()
+	fx := float32(x)
+	for _, gc := range tv.Gutter {
+		ex := cx + gc.Width*ch
+		if fx >= cx && fx < ex {
+			return gc
+		}
+		cx = ex
+	}
+	return nil
+}
+
+// GutterClick handles a mouse click at given position relative to the
+// view, if it is within a gutter column, returning true if so.
+func (tv *TextView) GutterClick(pt image.Point, ln int, me *mouse.Event) bool {
+	if ln < 0 || ln >= tv.NLines {
+		return false
+	}
+	gc := tv.GutterColAt(pt.X)
+	if gc == nil {
+		return false
+	}
+	if gc.Toggle {
+		gc.ToggleMark(ln)
+	}
+	if gc.ClickFunc != nil {
+		gc.ClickFunc(gc, tv, ln, me)
+	}
+	tv.RenderLines(ln, ln)
+	return true
+}
+
+// RenderGutterCols renders the marks for the gutter columns for given line,
+// within the given line box -- called within context of RenderLineNo
+func (tv *TextView) RenderGutterCols(ln int, sbox, ebox mat32.Vec2) {
+	if len(tv.Gutter) == 0 {
+		return
+	}
+	rs := &tv.Viewport.Render
+	pc := &rs.Paint
+	sty := &tv.Sty
+	ch := sty.Font.Face.Metrics.Ch
+	x := sbox.X + sty.BoxSpace()
+	lht := mat32.Min(ebox.Y-sbox.Y, tv.LineHeight)
+	for _, gc := range tv.Gutter {
+		wd := gc.Width * ch
+		mk, has := gc.LineMark(tv, ln)
+		if !has {
+			x += wd
+			continue
+		}
+		switch mk.Shape {
+		case GutterDot:
+			r := 0.3 * mat32.Min(wd, lht)
+			pc.FillStyle.SetColor(mk.Color)
+			pc.DrawCircle(rs, x+0.5*wd, sbox.Y+0.5*lht, r)
+			pc.Fill(rs)
+			pc.FillStyle.SetColor(nil)
+		case GutterBar:
+			pc.FillBoxColor(rs, mat32.Vec2{x, sbox.Y}, mat32.Vec2{wd, lht}, mk.Color)
+		case GutterThinBar:
+			pc.FillBoxColor(rs, mat32.Vec2{x, sbox.Y}, mat32.Vec2{wd, 0.25 * lht}, mk.Color)
+		case GutterText:
+			fst := sty.Font
+			fst.BgColor.SetColor(nil)
+			fst.Color = mk.Color
+			tv.GutterRender.SetString(mk.Text, &fst, &sty.UnContext, &sty.Text, true, 0, 0)
+			pos := mat32.Vec2{X: x}
+			pos.Y = tv.CharStartPos(lex.Pos{Ln: ln}).Y + mat32.FromFixed(sty.Font.Face.Face.Metrics().Ascent) - mat32.FromFixed(sty.Font.Face.Face.Metrics().Descent)
+			tv.GutterRender.Render(rs, pos)
+		}
+		x += wd
+	}
+}
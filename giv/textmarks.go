@@ -0,0 +1,158 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+	"github.com/goki/pi/lex"
+)
+
+// PropColor returns the color of given property of the text view,
+// which can be a *gist.Color or a color string relative to the background
+// (e.g., "highlight-40"), using given default string if it is not set --
+// used for the "marks-color" and "ruler-color" properties.
+func (tv *TextView) PropColor(key, def string) gist.Color {
+	var clr gist.Color
+	str := def
+	if pv, ok := tv.PropInherit(key, ki.NoInherit, ki.TypeProps); ok {
+		switch pvv := pv.(type) {
+		case *gist.Color:
+			return *pvv
+		case string:
+			str = pvv
+		}
+	}
+	clr.SetString(str, tv.Sty.Font.BgColor.Color)
+	return clr
+}
+
+// HasMarks returns true if any of the whitespace, end-of-line or wrap
+// marks are on in the Buf Opts
+func (tv *TextView) HasMarks() bool {
+	if tv.Buf == nil {
+		return false
+	}
+	op := &tv.Buf.Opts
+	return op.ShowWhitespace || op.ShowEOL || op.WrapMarks
+}
+
+// RenderRulers renders the vertical ruler lines at the Opts.Rulers
+// columns, from y position sy to ey, if Opts.ShowRulers, in the
+// "ruler-color" -- called before rendering the text of the lines.
+func (tv *TextView) RenderRulers(sy, ey float32) {
+	if tv.Buf == nil || !tv.Buf.Opts.ShowRulers || len(tv.Buf.Opts.Rulers) == 0 {
+		return
+	}
+	rs := tv.Render()
+	pc := &rs.Paint
+	ch := tv.Sty.Font.Face.Metrics.Ch
+	sx := tv.RenderStartPos().X + tv.LineNoOff
+	clr := tv.PropColor("ruler-color", "highlight-15")
+	pc.StrokeStyle.SetColor(&clr)
+	pc.StrokeStyle.Width.SetDot(1)
+	for _, col := range tv.Buf.Opts.Rulers {
+		if col <= 0 {
+			continue
+		}
+		x := mat32.Floor(sx+float32(col)*ch) + 0.5
+		pc.DrawLine(rs, x, sy, x, ey)
+	}
+	pc.Stroke(rs)
+}
+
+// RenderMarks renders the marks that are on in the Opts for given lines,
+// in the "marks-color": middle dots for spaces and arrows for tabs if
+// ShowWhitespace, a return arrow at the end of each line if ShowEOL, and an
+// arrow at the right edge of each row that is continued on the next one by
+// word wrapping if WrapMarks -- called after rendering the text of the lines.
+func (tv *TextView) RenderMarks(stln, edln int) {
+	if !tv.HasMarks() {
+		return
+	}
+	op := &tv.Buf.Opts
+	rs := tv.Render()
+	pc := &rs.Paint
+	ch := tv.Sty.Font.Face.Metrics.Ch
+	lht := tv.LineHeight
+	ex := float32(tv.VpBBox.Max.X) - tv.Sty.BoxSpace() - ch
+	clr := tv.PropColor("marks-color", "highlight-40")
+	pc.StrokeStyle.SetColor(&clr)
+	pc.StrokeStyle.Width.SetDot(1)
+	pc.FillStyle.SetColor(&clr)
+	dotr := mat32.Max(1, 0.08*tv.FontHeight)
+	for ln := stln; ln <= edln && ln < len(tv.Renders); ln++ {
+		spans := tv.Renders[ln].Spans
+		ci := 0
+		for si := range spans {
+			txt := spans[si].Text
+			for i, r := range txt {
+				if !op.ShowWhitespace || (r != ' ' && r != '\t') {
+					continue
+				}
+				pos := tv.CharStartPos(lex.Pos{Ln: ln, Ch: ci + i})
+				y := pos.Y + 0.5*lht
+				if r == ' ' {
+					pc.DrawCircle(rs, pos.X+0.5*ch, y, dotr)
+					pc.Fill(rs)
+					continue
+				}
+				tx := pos.X + ch // tab stop is at the start of the next char
+				if i < len(txt)-1 {
+					tx = tv.CharStartPos(lex.Pos{Ln: ln, Ch: ci + i + 1}).X
+				}
+				tv.DrawMarkArrow(rs, pos.X+0.2*ch, y, tx-0.2*ch, y)
+			}
+			ci += len(txt)
+			if op.WrapMarks && si < len(spans)-1 && len(txt) > 0 {
+				pos := tv.CharStartPos(lex.Pos{Ln: ln, Ch: ci - 1})
+				x := ex + 0.7*ch
+				pc.DrawLine(rs, ex+0.2*ch, pos.Y+0.3*lht, x, pos.Y+0.3*lht)
+				pc.Stroke(rs)
+				tv.DrawMarkArrow(rs, x, pos.Y+0.3*lht, x, pos.Y+0.8*lht)
+			}
+		}
+		if op.ShowEOL && ln < tv.NLines-1 {
+			var x, y float32
+			if ci == 0 {
+				pos := tv.CharStartPos(lex.Pos{Ln: ln})
+				x, y = pos.X, pos.Y
+			} else {
+				y = tv.CharStartPos(lex.Pos{Ln: ln, Ch: ci - 1}).Y
+				x = tv.CharEndPos(lex.Pos{Ln: ln, Ch: ci - 1}).X
+			}
+			x += 0.2 * ch
+			pc.DrawLine(rs, x+0.7*ch, y+0.3*lht, x+0.7*ch, y+0.6*lht)
+			pc.Stroke(rs)
+			tv.DrawMarkArrow(rs, x+0.7*ch, y+0.6*lht, x+0.1*ch, y+0.6*lht)
+		}
+	}
+	pc.FillStyle.SetColor(nil)
+}
+
+// DrawMarkArrow draws a horizontal or vertical arrow line from x1, y1 to
+// x2, y2, with its head at x2, y2, in the current stroke style
+func (tv *TextView) DrawMarkArrow(rs *girl.State, x1, y1, x2, y2 float32) {
+	pc := &rs.Paint
+	hd := 0.2 * tv.Sty.Font.Face.Metrics.Ch
+	pc.DrawLine(rs, x1, y1, x2, y2)
+	switch {
+	case x2 > x1:
+		pc.DrawLine(rs, x2-hd, y2-hd, x2, y2)
+		pc.DrawLine(rs, x2-hd, y2+hd, x2, y2)
+	case x2 < x1:
+		pc.DrawLine(rs, x2+hd, y2-hd, x2, y2)
+		pc.DrawLine(rs, x2+hd, y2+hd, x2, y2)
+	case y2 > y1:
+		pc.DrawLine(rs, x2-hd, y2-hd, x2, y2)
+		pc.DrawLine(rs, x2+hd, y2-hd, x2, y2)
+	default:
+		pc.DrawLine(rs, x2-hd, y2+hd, x2, y2)
+		pc.DrawLine(rs, x2+hd, y2+hd, x2, y2)
+	}
+	pc.Stroke(rs)
+}
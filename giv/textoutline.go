@@ -0,0 +1,144 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"image"
+	"sort"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/ki/ki"
+	"github.com/goki/pi/syms"
+	"github.com/goki/pi/token"
+)
+
+// TextOutlineKinds are the kinds of symbols of the code in a TextView that
+// are shown as sections in a gi.OutlinePanel: types and functions.  The
+// symbols come from the pi parser, and so are only available for
+// languages that it fully supports.
+var TextOutlineKinds = map[token.Tokens]bool{
+	token.NameType:          true,
+	token.NameClass:         true,
+	token.NameStruct:        true,
+	token.NameInterface:     true,
+	token.NameEnum:          true,
+	token.NameArray:         true,
+	token.NameMap:           true,
+	token.NameObject:        true,
+	token.NameFunction:      true,
+	token.NameFunctionMagic: true,
+	token.NameMethod:        true,
+	token.NameConstructor:   true,
+}
+
+// textOutlineSym is a symbol shown as a section, with its nesting level
+type textOutlineSym struct {
+	sy    *syms.Symbol
+	level int
+}
+
+// OutlineSections returns the TextOutlineKinds symbols of the file, from
+// the pi parser, as sections in units of lines: symbols within the
+// region of another one (e.g., methods of a class) are nested in it.
+func (tv *TextView) OutlineSections() []gi.OutlineSection {
+	if tv.Buf == nil {
+		return nil
+	}
+	fs := tv.Buf.PiState.Done()
+	fs.SymsMu.RLock()
+	var osys []textOutlineSym
+	textOutlineSyms(&osys, fs.Syms, string(tv.Buf.Filename), nil, 0)
+	fs.SymsMu.RUnlock()
+	sort.SliceStable(osys, func(i, j int) bool {
+		return osys[i].sy.Region.St.IsLess(osys[j].sy.Region.St)
+	})
+	secs := make([]gi.OutlineSection, len(osys))
+	for i, osy := range osys {
+		sy := osy.sy
+		secs[i] = gi.OutlineSection{Title: sy.Name, Level: osy.level, Start: sy.Region.St.Ln,
+			End: sy.Region.Ed.Ln + 1, Icon: gi.IconName(sy.Kind.IconName())}
+	}
+	return secs
+}
+
+// textOutlineSyms adds the symbols in given map that are in given file, and
+// their children, with the nesting level of those within the region of
+// given parent symbol being one more than its level
+func textOutlineSyms(osys *[]textOutlineSym, sm syms.SymMap, fname string, par *syms.Symbol, level int) {
+	for _, sy := range sm {
+		lev := level
+		if par != nil && par.Region.Contains(sy.Region.St) {
+			lev++
+		}
+		chpar := par
+		if TextOutlineKinds[sy.Kind] && sy.Filename == fname {
+			*osys = append(*osys, textOutlineSym{sy: sy, level: lev})
+			chpar = sy
+		} else {
+			lev = level // scopes such as packages do not nest
+		}
+		if len(sy.Children) > 0 {
+			textOutlineSyms(osys, sy.Children, fname, chpar, lev)
+		}
+	}
+}
+
+// OutlinePos returns the first visible line
+func (tv *TextView) OutlinePos() int {
+	if tv.NLines == 0 || len(tv.Renders) < tv.NLines {
+		return 0
+	}
+	return tv.FirstVisibleLine(0)
+}
+
+// OutlineGoTo moves the cursor to the name of the symbol of the section at
+// given index, scrolling it to the top of the view, and highlights it
+func (tv *TextView) OutlineGoTo(idx int) {
+	secs := tv.OutlineSections()
+	if idx < 0 || idx >= len(secs) {
+		return
+	}
+	st := secs[idx].Start
+	reg := textbuf.NewRegion(st, 0, st, tv.Buf.LineLen(st))
+	if sy := tv.outlineSymAt(st, secs[idx].Title); sy != nil && sy.SelectReg.St.Ln == st {
+		reg = textbuf.NewRegionPos(sy.SelectReg.St, sy.SelectReg.Ed)
+	}
+	tv.SetCursor(reg.Start)
+	tv.ScrollCursorToTop()
+	tv.HighlightRegion(reg)
+}
+
+// outlineSymAt returns the outline symbol with given name starting at
+// given line, or nil if there is none
+func (tv *TextView) outlineSymAt(ln int, name string) *syms.Symbol {
+	fs := tv.Buf.PiState.Done()
+	fs.SymsMu.RLock()
+	defer fs.SymsMu.RUnlock()
+	var osys []textOutlineSym
+	textOutlineSyms(&osys, fs.Syms, string(tv.Buf.Filename), nil, 0)
+	for _, osy := range osys {
+		if osy.sy.Region.St.Ln == ln && osy.sy.Name == name {
+			return osy.sy
+		}
+	}
+	return nil
+}
+
+// OutlineSignal returns the OutlineSig
+func (tv *TextView) OutlineSignal() *ki.Signal {
+	return &tv.OutlineSig
+}
+
+func (tv *TextView) Move2D(delta image.Point, parBBox image.Rectangle) {
+	tv.WidgetBase.Move2D(delta, parBBox)
+	if len(tv.OutlineSig.Cons) == 0 {
+		return
+	}
+	if pos := tv.OutlinePos(); pos != tv.outPos {
+		tv.outPos = pos
+		tv.OutlineSig.Emit(tv.This(), int64(gi.OutlineMoved), nil)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"unicode"
+
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/giv/textbuf"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/pi/filecat"
+	"github.com/goki/pi/lex"
+	"github.com/goki/pi/pi"
+)
+
+// TextPairsDefault are the pairs of delimiters handled by the SmartPairs
+// editor option for languages not in TextLangPairs, as a string of
+// opening and closing runes for each pair
+var TextPairsDefault = `()[]{}""''`
+
+// TextLangPairs are the pairs of delimiters handled by the SmartPairs
+// editor option for specific languages, as a string of opening and
+// closing runes for each pair -- brackets are auto-closed according to
+// the pi AutoBracket logic for the language, and quotes only when not
+// typed just after a letter or digit.
+var TextLangPairs = map[filecat.Supported]string{
+	filecat.Go:        "()[]{}\"\"''``",
+	filecat.Markdown:  "()[]\"\"``",
+	filecat.TeX:       "()[]{}$$",
+	filecat.PlainText: "()[]\"\"",
+	filecat.AnyText:   "()[]\"\"",
+}
+
+// PairClose returns the closing delimiter for given opening one, if it
+// is one of the pairs for the language of the buffer (see TextLangPairs)
+func (tb *TextBuf) PairClose(bra rune) (rune, bool) {
+	prs, has := TextLangPairs[tb.Info.Sup]
+	if !has {
+		prs = TextPairsDefault
+	}
+	rs := []rune(prs)
+	for i := 0; i+1 < len(rs); i += 2 {
+		if rs[i] == bra {
+			return rs[i+1], true
+		}
+	}
+	return 0, false
+}
+
+// SurroundSelection surrounds the selected text with given opening and
+// closing delimiters, keeping the (shifted) text selected
+func (tv *TextView) SurroundSelection(bra, ket rune) {
+	wupdt := tv.TopUpdateStart()
+	defer tv.TopUpdateEnd(wupdt)
+	bufUpdt, winUpdt, autoSave := tv.Buf.BatchUpdateStart()
+	defer tv.Buf.BatchUpdateEnd(bufUpdt, winUpdt, autoSave)
+	reg := tv.SelectReg
+	tv.Buf.InsertText(reg.End, []byte(string(ket)), EditSignal)
+	tv.Buf.InsertText(reg.Start, []byte(string(bra)), EditSignal)
+	reg.Start.Ch++
+	if reg.End.Ln == reg.Start.Ln {
+		reg.End.Ch++
+	}
+	tv.SelectReg = reg
+	tv.SelectStart = reg.Start
+	tv.SetCursorShow(reg.End)
+	tv.SelectReg = reg // SetCursorShow can reset it
+	tv.RenderLines(reg.Start.Ln, reg.End.Ln)
+}
+
+// KeyInputInsertQuote handles input of a quote-like delimiter that is both
+// the opening and closing one of a pair: typing it just before the same
+// one moves past it, and otherwise the closing one is also inserted if at
+// the end of the line or before a space or closing bracket, and not just
+// after a letter or digit
+func (tv *TextView) KeyInputInsertQuote(kt *key.ChordEvent) {
+	pos := tv.CursorPos
+	curLn := tv.Buf.Line(pos.Ln)
+	lnLen := len(curLn)
+	if pos.Ch < lnLen && curLn[pos.Ch] == kt.Rune {
+		pos.Ch++
+		tv.SetCursorShow(pos)
+		return
+	}
+	match := pos.Ch == lnLen || unicode.IsSpace(curLn[pos.Ch])
+	if !match {
+		_, right := lex.BracePair(curLn[pos.Ch])
+		match = right
+	}
+	if match && pos.Ch > 0 {
+		pr := curLn[pos.Ch-1]
+		match = !unicode.IsLetter(pr) && !unicode.IsDigit(pr)
+	}
+	if !match {
+		tv.InsertAtCursor([]byte(string(kt.Rune)))
+		return
+	}
+	tv.InsertAtCursor([]byte(string(kt.Rune) + string(kt.Rune)))
+	pos.Ch++
+	tv.SetCursorShow(pos)
+	tv.lastAutoInsert = kt.Rune
+}
+
+// textBraces are the brackets that are matched by BraceMatch
+var textBraces = map[rune]bool{'{': true, '}': true, '(': true, ')': true, '[': true, ']': true}
+
+// ScopelightBrace highlights the bracket at given position on given line
+// and its match, if found -- returns true if so
+func (tv *TextView) ScopelightBrace(txt []rune, pos lex.Pos) bool {
+	if pos.Ch < 0 || pos.Ch >= len(txt) {
+		return false
+	}
+	r := txt[pos.Ch]
+	if !textBraces[r] {
+		return false
+	}
+	tp, found := tv.Buf.BraceMatch(r, pos)
+	if !found {
+		return false
+	}
+	tv.Scopelights = append(tv.Scopelights, textbuf.NewRegionPos(pos, lex.Pos{pos.Ln, pos.Ch + 1}))
+	tv.Scopelights = append(tv.Scopelights, textbuf.NewRegionPos(tp, lex.Pos{tp.Ln, tp.Ch + 1}))
+	if pos.Ln < tp.Ln {
+		tv.RenderLines(pos.Ln, tp.Ln)
+	} else {
+		tv.RenderLines(tp.Ln, pos.Ln)
+	}
+	return true
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Rainbow brackets
+
+// TextViewRainbowColors are the colors of brackets for successive nesting
+// depths when the RainbowBrackets editor option is on, for light backgrounds
+var TextViewRainbowColors = []gist.Color{
+	{176, 124, 0, 255},
+	{153, 50, 204, 255},
+	{0, 110, 200, 255},
+}
+
+// TextViewRainbowDarkColors are the colors of brackets for successive nesting
+// depths when the RainbowBrackets editor option is on, for dark backgrounds
+var TextViewRainbowDarkColors = []gist.Color{
+	{255, 215, 0, 255},
+	{218, 112, 214, 255},
+	{23, 159, 255, 255},
+}
+
+// RainbowOn returns true if brackets are colored by nesting depth: the
+// RainbowBrackets option is on, and the language of the buffer is
+// supported by pi, which provides the depths
+func (tv *TextView) RainbowOn() bool {
+	if tv.Buf == nil || !tv.Buf.Opts.RainbowBrackets {
+		return false
+	}
+	lp, _ := pi.LangSupport.Props(tv.Buf.PiState.Sup)
+	return lp != nil && lp.Lang != nil
+}
+
+// RainbowLine colors the brackets in the render of given line by their
+// nesting depth, using the HiTags of the buffer -- must be called under
+// the MarkupMu lock, after laying out the line
+func (tv *TextView) RainbowLine(ln int) {
+	if ln >= len(tv.Buf.HiTags) {
+		return
+	}
+	clrs := TextViewRainbowColors
+	if tv.Sty.Font.BgColor.Color.IsDark() {
+		clrs = TextViewRainbowDarkColors
+	}
+	spans := tv.Renders[ln].Spans
+	for _, lx := range tv.Buf.HiTags[ln] {
+		if !lx.Tok.Tok.IsPunctGpLeft() && !lx.Tok.Tok.IsPunctGpRight() {
+			continue
+		}
+		ci := lx.St
+		for si := range spans {
+			if ci < len(spans[si].Render) {
+				spans[si].Render[ci].Color = clrs[lx.Tok.Depth%len(clrs)]
+				break
+			}
+			ci -= len(spans[si].Render)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+// TextSplitView is an editor frame showing two or more TextViews of the
+// same TextBuf, side-by-side (Dim = X) or over / under (Dim = Y), each
+// with its own scrolling, cursor and selection.  Edits made in any view
+// are shown in all of them, via the TextBuf signals.  Use SplitEditor to
+// add a view, and CloseView to remove one.
+type TextSplitView struct {
+	gi.SplitView
+	Buf *TextBuf `json:"-" xml:"-" desc:"the text buffer shown in all views"`
+}
+
+var KiT_TextSplitView = kit.Types.AddType(&TextSplitView{}, TextSplitViewProps)
+
+// AddNewTextSplitView adds a new textsplitview to given parent node, with given name.
+func AddNewTextSplitView(parent ki.Ki, name string) *TextSplitView {
+	return parent.AddNewChild(KiT_TextSplitView, name).(*TextSplitView)
+}
+
+var TextSplitViewProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"handle-size":   gi.SplitViewProps["handle-size"],
+	"max-width":     -1.0,
+	"max-height":    -1.0,
+	"margin":        0,
+	"padding":       0,
+	"CallMethods": ki.PropSlice{
+		{"SplitEditor", ki.Props{
+			"Args": ki.PropSlice{
+				{"Dim", ki.Props{}},
+			},
+		}},
+		{"CloseView", ki.Props{
+			"Args": ki.PropSlice{
+				{"Index", ki.Props{}},
+			},
+		}},
+	},
+}
+
+// SetBuf sets the buffer shown in all views, creating the first view if
+// there are none yet
+func (sv *TextSplitView) SetBuf(buf *TextBuf) {
+	updt := sv.UpdateStart()
+	sv.Buf = buf
+	if sv.NumViews() == 0 {
+		sv.AddView()
+	}
+	for _, tv := range sv.Views() {
+		tv.SetBuf(buf)
+	}
+	sv.UpdateEnd(updt)
+}
+
+// NumViews returns the number of views
+func (sv *TextSplitView) NumViews() int {
+	return len(sv.Kids)
+}
+
+// View returns the TextView at given index, or nil if out of range
+func (sv *TextSplitView) View(idx int) *TextView {
+	if idx < 0 || idx >= len(sv.Kids) {
+		return nil
+	}
+	ly := sv.Kids[idx]
+	if !ly.HasChildren() {
+		return nil
+	}
+	return ly.Child(0).Embed(KiT_TextView).(*TextView)
+}
+
+// Views returns all of the TextViews
+func (sv *TextSplitView) Views() []*TextView {
+	tvs := make([]*TextView, 0, len(sv.Kids))
+	for i := range sv.Kids {
+		if tv := sv.View(i); tv != nil {
+			tvs = append(tvs, tv)
+		}
+	}
+	return tvs
+}
+
+// CurViewIdx returns the index of the view that has (or last had) focus,
+// or 0 if none does
+func (sv *TextSplitView) CurViewIdx() int {
+	for i, tv := range sv.Views() {
+		if tv.HasFocus() {
+			return i
+		}
+	}
+	if sv.Buf != nil && sv.Buf.CurView != nil {
+		for i, tv := range sv.Views() {
+			if tv == sv.Buf.CurView {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// AddView adds a new view of the buffer, in its own scrolling layout,
+// returning it
+func (sv *TextSplitView) AddView() *TextView {
+	updt := sv.UpdateStart()
+	sv.SetFullReRender()
+	nm := fmt.Sprintf("view-%d", len(sv.Kids))
+	for sv.ChildByName(nm+"-lay", 0) != nil {
+		nm += "x"
+	}
+	tv, _ := AddNewTextViewLayout(sv, nm)
+	if sv.Buf != nil {
+		tv.SetBuf(sv.Buf)
+	}
+	sv.Splits = nil
+	sv.UpdateSplits()
+	sv.UpdateEnd(updt)
+	return tv
+}
+
+// SplitEditor adds a new view of the buffer, along given dimension:
+// X = side-by-side, Y = over / under.  All views are arranged along the
+// same dimension.  The new view starts at the same cursor position and
+// scroll location as the current one, and gets the focus.
+func (sv *TextSplitView) SplitEditor(dim mat32.Dims) *TextView {
+	cur := sv.View(sv.CurViewIdx())
+	sv.Dim = dim
+	tv := sv.AddView()
+	if cur != nil && sv.Buf != nil {
+		tv.CursorPos = cur.CursorPos
+		tv.ScrollToCursorOnRender = true
+		tv.ScrollToCursorPos = cur.CursorPos
+	}
+	tv.GrabFocus()
+	return tv
+}
+
+// CloseView closes the view at given index -- the last remaining view
+// cannot be closed.
+func (sv *TextSplitView) CloseView(idx int) bool {
+	if sv.NumViews() <= 1 || idx < 0 || idx >= sv.NumViews() {
+		return false
+	}
+	updt := sv.UpdateStart()
+	sv.SetFullReRender()
+	if tv := sv.View(idx); tv != nil {
+		tv.SetBuf(nil)
+	}
+	sv.DeleteChildAtIndex(idx, ki.DestroyKids)
+	sv.Splits = nil
+	sv.UpdateSplits()
+	sv.UpdateEnd(updt)
+	nxt := sv.View(ints.MinInt(idx, sv.NumViews()-1))
+	if nxt != nil {
+		nxt.GrabFocus()
+	}
+	return true
+}
+
+// CloseCurView closes the current view, if there is more than one
+func (sv *TextSplitView) CloseCurView() bool {
+	return sv.CloseView(sv.CurViewIdx())
+}
+
+// FocusNextView moves the focus to the next view, wrapping around
+func (sv *TextSplitView) FocusNextView() {
+	n := sv.NumViews()
+	if n == 0 {
+		return
+	}
+	if tv := sv.View((sv.CurViewIdx() + 1) % n); tv != nil {
+		tv.GrabFocus()
+	}
+}
+
+// KeyInput handles the split editor keyboard commands: KeyFunSplitView,
+// KeyFunSplitViewVert, KeyFunCloseView and KeyFunNextView.  These are
+// processed at low priority, after the TextViews have had a chance.
+func (sv *TextSplitView) KeyInput(kt *key.ChordEvent) {
+	switch gi.KeyFun(kt.Chord()) {
+	case gi.KeyFunSplitView:
+		kt.SetProcessed()
+		sv.SplitEditor(mat32.X)
+	case gi.KeyFunSplitViewVert:
+		kt.SetProcessed()
+		sv.SplitEditor(mat32.Y)
+	case gi.KeyFunCloseView:
+		kt.SetProcessed()
+		sv.CloseCurView()
+	case gi.KeyFunNextView:
+		kt.SetProcessed()
+		sv.FocusNextView()
+	}
+}
+
+func (sv *TextSplitView) ConnectEvents2D() {
+	sv.SplitView.ConnectEvents2D()
+	sv.ConnectEvent(oswin.KeyChordEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d any) {
+		svv := recv.Embed(KiT_TextSplitView).(*TextSplitView)
+		kt := d.(*key.ChordEvent)
+		svv.KeyInput(kt)
+	})
+}
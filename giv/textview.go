@@ -5,6 +5,8 @@
 package giv
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/draw"
@@ -54,6 +56,8 @@ type TextView struct {
 	LineNoDigs             int                         `json:"-" xml:"-" desc:"number of line number digits needed"`
 	LineNoOff              float32                     `json:"-" xml:"-" desc:"horizontal offset for start of text after line numbers"`
 	LineNoRender           girl.Text                   `json:"-" xml:"-" desc:"render for line numbers"`
+	Gutter                 []*TextGutterCol            `json:"-" xml:"-" desc:"additional gutter columns shown to the left of the line numbers, e.g., for breakpoints, bookmarks, and change bars -- see AddGutterCol"`
+	GutterRender           girl.Text                   `json:"-" xml:"-" view:"-" desc:"render for gutter text marks"`
 	LinesSize              image.Point                 `json:"-" xml:"-" desc:"total size of all lines as rendered"`
 	RenderSz               mat32.Vec2                  `json:"-" xml:"-" desc:"size params to use in render call"`
 	CursorPos              lex.Pos                     `json:"-" xml:"-" desc:"current cursor position"`
@@ -67,11 +71,13 @@ type TextView struct {
 	Highlights             []textbuf.Region            `json:"-" xml:"-" desc:"highlighted regions, e.g., for search results"`
 	Scopelights            []textbuf.Region            `json:"-" xml:"-" desc:"highlighted regions, specific to scope markers"`
 	SelectMode             bool                        `json:"-" xml:"-" desc:"if true, select text as cursor moves"`
+	SelectRect             bool                        `json:"-" xml:"-" desc:"if true, the selection is a rectangular block with SelectReg start and end as its corners, instead of a continuous stream of text -- set by Alt+click / drag or SelectRectModeToggle -- cut, copy, paste, and typing operate on the block"`
 	ForceComplete          bool                        `json:"-" xml:"-" desc:"if true, complete regardless of any disqualifying reasons"`
 	ISearch                ISearch                     `json:"-" xml:"-" desc:"interactive search data"`
 	QReplace               QReplace                    `json:"-" xml:"-" desc:"query replace data"`
 	TextViewSig            ki.Signal                   `json:"-" xml:"-" view:"-" desc:"signal for text view -- see TextViewSignals for the types"`
 	LinkSig                ki.Signal                   `json:"-" xml:"-" view:"-" desc:"signal for clicking on a link -- data is a string of the URL -- if nobody receiving this signal, calls TextLinkHandler then URLHandler"`
+	OutlineSig             ki.Signal                   `json:"-" xml:"-" view:"-" desc:"signal for gi.OutlinePanels showing the symbols of the code -- see gi.OutlineSignals for the types"`
 	StateStyles            [TextViewStatesN]gist.Style `json:"-" xml:"-" desc:"normal style and focus style"`
 	FontHeight             float32                     `json:"-" xml:"-" desc:"font height, cached during styling"`
 	LineHeight             float32                     `json:"-" xml:"-" desc:"line height, cached during styling"`
@@ -82,6 +88,8 @@ type TextView struct {
 	lastRecenter           int
 	lastAutoInsert         rune
 	lastFilename           gi.FileName
+	readOnlyInactive       bool
+	outPos                 int // last OutlinePos, for OutlineMoved
 }
 
 var KiT_TextView = kit.Types.AddType(&TextView{}, TextViewProps)
@@ -105,6 +113,7 @@ func (tv *TextView) Disconnect() {
 	tv.WidgetBase.Disconnect()
 	tv.TextViewSig.DisconnectAll()
 	tv.LinkSig.DisconnectAll()
+	tv.OutlineSig.DisconnectAll()
 }
 
 var TextViewProps = ki.Props{
@@ -121,6 +130,8 @@ var TextViewProps = ki.Props{
 	"tab-size":         4,
 	"color":            &gi.Prefs.Colors.Font,
 	"background-color": &gi.Prefs.Colors.Background,
+	"marks-color":      "highlight-40",
+	"ruler-color":      "highlight-15",
 	TextViewSelectors[TextViewActive]: ki.Props{
 		"background-color": "highlight-10",
 	},
@@ -322,6 +333,15 @@ func (tv *TextView) ResetState() {
 	if tv.Buf == nil || tv.lastFilename != tv.Buf.Filename { // don't reset if reopening..
 		tv.CursorPos = lex.Pos{}
 	}
+	if tv.Buf != nil && tv.Buf.IsReadOnly() {
+		if !tv.IsInactive() {
+			tv.SetInactive()
+			tv.readOnlyInactive = true
+		}
+	} else if tv.readOnlyInactive {
+		tv.ClearInactive()
+		tv.readOnlyInactive = false
+	}
 	if tv.Buf != nil {
 		tv.Buf.SetInactive(tv.IsInactive())
 	}
@@ -353,6 +373,26 @@ func (tv *TextView) SetBuf(buf *TextBuf) {
 	tv.SetCursorShow(tv.CursorPos)
 }
 
+// AutomationValue returns the text of the buffer, for the widget tree of
+// the automation endpoint (gi.AutomationValuer)
+func (tv *TextView) AutomationValue() string {
+	if tv.Buf == nil {
+		return ""
+	}
+	return strings.Join(tv.Buf.Strings(false), "\n")
+}
+
+// SetAutomationValue sets the text of the buffer, and finishes editing, for
+// the set-text command of the automation endpoint (gi.AutomationValuer)
+func (tv *TextView) SetAutomationValue(val string) error {
+	if tv.Buf == nil {
+		return errors.New("text view has no buffer")
+	}
+	tv.Buf.SetText([]byte(val))
+	tv.Buf.EditDone()
+	return nil
+}
+
 // LinesInserted inserts new lines of text and reformats them
 func (tv *TextView) LinesInserted(tbe *textbuf.Edit) {
 	stln := tbe.Reg.Start.Ln + 1
@@ -407,10 +447,11 @@ func TextViewBufSigRecv(rvwki ki.Ki, sbufki ki.Ki, sig int64, data any) {
 		tv.Refresh()
 		tv.SetCursorShow(tv.CursorPos)
 	case TextBufInsert:
+		tbe := data.(*textbuf.Edit)
+		tv.AdjustForOtherViewEdit(tbe)
 		if tv.Renders == nil || !tv.This().(gi.Node2D).IsVisible() {
 			return
 		}
-		tbe := data.(*textbuf.Edit)
 		// fmt.Printf("tv %v got %v\n", tv.Nm, tbe.Reg.Start)
 		if tbe.Reg.Start.Ln != tbe.Reg.End.Ln {
 			// fmt.Printf("tv %v lines insert %v - %v\n", tv.Nm, tbe.Reg.Start, tbe.Reg.End)
@@ -426,10 +467,11 @@ func TextViewBufSigRecv(rvwki ki.Ki, sbufki ki.Ki, sig int64, data any) {
 			}
 		}
 	case TextBufDelete:
+		tbe := data.(*textbuf.Edit)
+		tv.AdjustForOtherViewEdit(tbe)
 		if tv.Renders == nil || !tv.This().(gi.Node2D).IsVisible() {
 			return
 		}
-		tbe := data.(*textbuf.Edit)
 		if tbe.Reg.Start.Ln != tbe.Reg.End.Ln {
 			tv.LinesDeleted(tbe)
 		} else {
@@ -447,6 +489,24 @@ func TextViewBufSigRecv(rvwki ki.Ki, sbufki ki.Ki, sig int64, data any) {
 	}
 }
 
+// AdjustForOtherViewEdit adjusts the cursor, selection and highlights of
+// this view for given edit, if it was made in another view of the same
+// buffer (i.e., this view does not have the focus), so that they stay
+// on the same text, e.g., in a TextSplitView.
+func (tv *TextView) AdjustForOtherViewEdit(tbe *textbuf.Edit) {
+	if tv.HasFocus() || tv.Buf == nil || len(tv.Buf.Views) < 2 {
+		return
+	}
+	tv.CursorPos = tbe.AdjustPos(tv.CursorPos, textbuf.AdjustPosDelStart)
+	tv.SelectStart = tbe.AdjustPos(tv.SelectStart, textbuf.AdjustPosDelStart)
+	if !tv.SelectReg.IsNil() {
+		tv.SelectReg = tbe.AdjustReg(tv.SelectReg)
+	}
+	for i, hr := range tv.Highlights {
+		tv.Highlights[i] = tbe.AdjustReg(hr)
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 //  Text formatting and rendering
 
@@ -555,11 +615,15 @@ func (tv *TextView) LayoutAllLines(inLayout bool) bool {
 	off := float32(0)
 	mxwd := sz.X // always start with our render size
 
+	rainbow := tv.RainbowOn()
 	tv.Buf.MarkupMu.RLock()
 	tv.HasLinks = false
 	for ln := 0; ln < nln; ln++ {
 		tv.Renders[ln].SetHTMLPre(tv.Buf.Markup[ln], &fst, &sty.Text, &sty.UnContext, tv.CSS)
 		tv.Renders[ln].LayoutStdLR(&sty.Text, &sty.Font, &sty.UnContext, sz)
+		if rainbow {
+			tv.RainbowLine(ln)
+		}
 		if !tv.HasLinks && len(tv.Renders[ln].Links) > 0 {
 			tv.HasLinks = true
 		}
@@ -647,11 +711,15 @@ func (tv *TextView) LayoutLines(st, ed int, isDel bool) bool {
 	mxwd := float32(tv.LinesSize.X)
 	rerend := false
 
+	rainbow := tv.RainbowOn()
 	tv.Buf.MarkupMu.RLock()
 	for ln := st; ln <= ed; ln++ {
 		curspans := len(tv.Renders[ln].Spans)
 		tv.Renders[ln].SetHTMLPre(tv.Buf.Markup[ln], &fst, &sty.Text, &sty.UnContext, tv.CSS)
 		tv.Renders[ln].LayoutStdLR(&sty.Text, &sty.Font, &sty.UnContext, tv.RenderSz)
+		if rainbow {
+			tv.RainbowLine(ln)
+		}
 		if !tv.HasLinks && len(tv.Renders[ln].Links) > 0 {
 			tv.HasLinks = true
 		}
@@ -732,7 +800,7 @@ func (tv *TextView) SetCursor(pos lex.Pos) {
 	cpln := tv.CursorPos.Ln
 	tv.ClearScopelights()
 	tv.CursorPos = tv.Buf.ValidPos(pos)
-	if cpln != tv.CursorPos.Ln && tv.HasLineNos() { // update cursor position highlight
+	if cpln != tv.CursorPos.Ln && tv.HasGutter() { // update cursor position highlight
 		rs := tv.Render()
 		rs.PushBounds(tv.VpBBox)
 		rs.Lock()
@@ -744,19 +812,11 @@ func (tv *TextView) SetCursor(pos lex.Pos) {
 	tv.Buf.MarkupLine(tv.CursorPos.Ln)
 	tv.CursorMovedSig()
 	txt := tv.Buf.Line(tv.CursorPos.Ln)
-	ch := tv.CursorPos.Ch
-	if ch < len(txt) {
-		r := txt[ch]
-		if r == '{' || r == '}' || r == '(' || r == ')' || r == '[' || r == ']' {
-			tp, found := tv.Buf.BraceMatch(txt[ch], tv.CursorPos)
-			if found {
-				tv.Scopelights = append(tv.Scopelights, textbuf.NewRegionPos(tv.CursorPos, lex.Pos{tv.CursorPos.Ln, tv.CursorPos.Ch + 1}))
-				tv.Scopelights = append(tv.Scopelights, textbuf.NewRegionPos(tp, lex.Pos{tp.Ln, tp.Ch + 1}))
-				if tv.CursorPos.Ln < tp.Ln {
-					tv.RenderLines(tv.CursorPos.Ln, tp.Ln)
-				} else {
-					tv.RenderLines(tp.Ln, tv.CursorPos.Ln)
-				}
+	if !tv.ScopelightBrace(txt, tv.CursorPos) { // also just after a closing one
+		ch := tv.CursorPos.Ch - 1
+		if ch >= 0 && ch < len(txt) {
+			if _, right := lex.BracePair(txt[ch]); right {
+				tv.ScopelightBrace(txt, lex.Pos{Ln: tv.CursorPos.Ln, Ch: ch})
 			}
 		}
 	}
@@ -1271,6 +1331,10 @@ func (tv *TextView) CursorBackspace(steps int) {
 	defer tv.TopUpdateEnd(wupdt)
 	tv.ValidateCursor()
 	org := tv.CursorPos
+	if tv.SelectRect && tv.HasSelection() {
+		tv.DeleteRectCols(true)
+		return
+	}
 	if tv.HasSelection() {
 		org = tv.SelectReg.Start
 		tv.DeleteSelection()
@@ -1289,6 +1353,10 @@ func (tv *TextView) CursorDelete(steps int) {
 	wupdt := tv.TopUpdateStart()
 	defer tv.TopUpdateEnd(wupdt)
 	tv.ValidateCursor()
+	if tv.SelectRect && tv.HasSelection() {
+		tv.DeleteRectCols(false)
+		return
+	}
 	if tv.HasSelection() {
 		tv.DeleteSelection()
 		return
@@ -2271,6 +2339,7 @@ func (tv *TextView) WordAt() (reg textbuf.Region) {
 // SelectReset resets the selection
 func (tv *TextView) SelectReset() {
 	tv.SelectMode = false
+	tv.SelectRect = false
 	if !tv.HasSelection() {
 		return
 	}
@@ -2369,6 +2438,9 @@ func (tv *TextView) Cut() *textbuf.Edit {
 	if !tv.HasSelection() {
 		return nil
 	}
+	if tv.SelectRect {
+		return tv.CutRect()
+	}
 	wupdt := tv.TopUpdateStart()
 	defer tv.TopUpdateEnd(wupdt)
 	org := tv.SelectReg.Start
@@ -2394,6 +2466,9 @@ func (tv *TextView) DeleteSelection() *textbuf.Edit {
 // Copy copies any selected text to the clipboard, and returns that text,
 // optionally resetting the current selection
 func (tv *TextView) Copy(reset bool) *textbuf.Edit {
+	if tv.SelectRect && tv.HasSelection() {
+		return tv.CopyRect(reset)
+	}
 	tbe := tv.Selection()
 	if tbe == nil {
 		return nil
@@ -2416,7 +2491,12 @@ func (tv *TextView) Paste() {
 	defer tv.TopUpdateEnd(wupdt)
 	data := oswin.TheApp.ClipBoard(tv.ParentWindow().OSWin).Read([]string{filecat.TextPlain})
 	if data != nil {
-		tv.InsertAtCursor(data.TypeData(filecat.TextPlain))
+		txt := data.TypeData(filecat.TextPlain)
+		if TextViewClipRect != nil && bytes.Equal(txt, TextViewClipRect.ToBytes()) {
+			tv.PasteRect()
+			return
+		}
+		tv.InsertAtCursor(txt)
 		tv.SavePosHistory(tv.CursorPos)
 	}
 }
@@ -2425,6 +2505,10 @@ func (tv *TextView) Paste() {
 func (tv *TextView) InsertAtCursor(txt []byte) {
 	wupdt := tv.TopUpdateStart()
 	defer tv.TopUpdateEnd(wupdt)
+	if tv.SelectRect && tv.HasSelection() && !bytes.ContainsRune(txt, '\n') {
+		tv.InsertRectCols(txt)
+		return
+	}
 	if tv.HasSelection() {
 		tbe := tv.DeleteSelection()
 		tv.CursorPos = tbe.AdjustPos(tv.CursorPos, textbuf.AdjustPosDelStart) // move to start if in reg
@@ -2449,6 +2533,110 @@ func (tv *TextView) InsertAtCursor(txt []byte) {
 // rect information is in a special format.
 var TextViewClipRect *textbuf.Edit
 
+// RectSelReg returns the current selection as a rectangle, with Start the
+// upper left and End the lower right corner, regardless of the direction
+// in which it was selected
+func (tv *TextView) RectSelReg() textbuf.Region {
+	reg := tv.SelectReg
+	if reg.Start.Ch > reg.End.Ch {
+		reg.Start.Ch, reg.End.Ch = reg.End.Ch, reg.Start.Ch
+	}
+	return reg
+}
+
+// SelectRectModeToggle toggles rectangular (block) selection mode, in which
+// the selection is the rectangle between the selection start and the cursor
+func (tv *TextView) SelectRectModeToggle() {
+	if tv.SelectRect {
+		tv.SelectReset()
+		return
+	}
+	tv.SelectRect = true
+	if !tv.HasSelection() {
+		tv.SelectMode = true
+		tv.SelectStart = tv.CursorPos
+		tv.SelectRegUpdate(tv.CursorPos)
+	}
+	tv.RenderSelectLines()
+}
+
+// RenderSelectRect renders the rectangular selection region --
+// called within context of RenderSelect
+func (tv *TextView) RenderSelectRect() {
+	reg := tv.RectSelReg()
+	for ln := reg.Start.Ln; ln <= reg.End.Ln; ln++ {
+		ll := tv.Buf.LineLen(ln)
+		if ll < reg.Start.Ch || reg.Start.Ch == reg.End.Ch {
+			continue
+		}
+		lr := textbuf.NewRegion(ln, reg.Start.Ch, ln, ints.MinInt(reg.End.Ch, ll))
+		tv.RenderRegionBox(lr, TextViewSel)
+	}
+}
+
+// SetRectCols sets the rectangular selection to a zero-width column at
+// given character position, on the lines of given region, with the cursor
+// on the last line, as used after typing into a block selection
+func (tv *TextView) SetRectCols(reg textbuf.Region, ch int) {
+	tv.SelectRect = true
+	tv.SelectMode = false
+	tv.SelectStart = lex.Pos{Ln: reg.Start.Ln, Ch: ch}
+	tv.SelectReg = textbuf.NewRegion(reg.Start.Ln, ch, reg.End.Ln, ch)
+	tv.SetCursorShow(lex.Pos{Ln: reg.End.Ln, Ch: ch})
+	tv.SetCursorCol(tv.CursorPos)
+	tv.RenderSelectLines()
+}
+
+// InsertRectCols inserts given text (which must not contain newlines) on
+// every line of the rectangular selection, at its left column, replacing
+// any text in the rectangle, e.g., for typing into a block selection.
+// Lines that are shorter than the column are padded with spaces.
+func (tv *TextView) InsertRectCols(txt []byte) {
+	reg := tv.RectSelReg()
+	bufUpdt, winUpdt, autoSave := tv.Buf.BatchUpdateStart()
+	defer tv.Buf.BatchUpdateEnd(bufUpdt, winUpdt, autoSave)
+	if reg.End.Ch > reg.Start.Ch {
+		tv.Buf.DeleteTextRect(reg.Start, reg.End, EditSignal)
+	}
+	for ln := reg.Start.Ln; ln <= reg.End.Ln; ln++ {
+		ins := txt
+		if ll := tv.Buf.LineLen(ln); ll < reg.Start.Ch {
+			ins = append(bytes.Repeat([]byte(" "), reg.Start.Ch-ll), txt...)
+			tv.Buf.InsertText(lex.Pos{Ln: ln, Ch: ll}, ins, EditSignal)
+			continue
+		}
+		tv.Buf.InsertText(lex.Pos{Ln: ln, Ch: reg.Start.Ch}, ins, EditSignal)
+	}
+	tv.SetRectCols(reg, reg.Start.Ch+len([]rune(string(txt))))
+}
+
+// DeleteRectCols deletes the text in the rectangular selection if it has a
+// width, and otherwise deletes one character before (back = true, e.g.,
+// Backspace) or after (Delete) its column, on every line.
+func (tv *TextView) DeleteRectCols(back bool) {
+	reg := tv.RectSelReg()
+	if reg.End.Ch > reg.Start.Ch {
+		tv.Buf.DeleteTextRect(reg.Start, reg.End, EditSignal)
+		tv.SetRectCols(reg, reg.Start.Ch)
+		return
+	}
+	ch := reg.Start.Ch
+	if back {
+		if ch == 0 {
+			return
+		}
+		ch--
+	}
+	bufUpdt, winUpdt, autoSave := tv.Buf.BatchUpdateStart()
+	defer tv.Buf.BatchUpdateEnd(bufUpdt, winUpdt, autoSave)
+	for ln := reg.Start.Ln; ln <= reg.End.Ln; ln++ {
+		if tv.Buf.LineLen(ln) > ch {
+			tv.Buf.DeleteText(lex.Pos{Ln: ln, Ch: ch}, lex.Pos{Ln: ln, Ch: ch + 1}, EditSignal)
+		}
+	}
+	tv.SetRectCols(reg, ch)
+}
+
 // CutRect cuts rectangle defined by selected text (upper left to lower right)
 // and adds it to the clipboard, also returns cut text.
 func (tv *TextView) CutRect() *textbuf.Edit {
@@ -2457,13 +2645,16 @@ func (tv *TextView) CutRect() *textbuf.Edit {
 	}
 	wupdt := tv.TopUpdateStart()
 	defer tv.TopUpdateEnd(wupdt)
-	npos := lex.Pos{Ln: tv.SelectReg.End.Ln, Ch: tv.SelectReg.Start.Ch}
-	cut := tv.Buf.DeleteTextRect(tv.SelectReg.Start, tv.SelectReg.End, EditSignal)
+	reg := tv.RectSelReg()
+	npos := lex.Pos{Ln: reg.End.Ln, Ch: reg.Start.Ch}
+	cut := tv.Buf.DeleteTextRect(reg.Start, reg.End, EditSignal)
 	if cut != nil {
 		cb := cut.ToBytes()
 		oswin.TheApp.ClipBoard(tv.ParentWindow().OSWin).Write(mimedata.NewTextBytes(cb))
+		TextViewClipHistAdd(cb)
 		TextViewClipRect = cut
 	}
+	tv.SelectReset()
 	tv.SetCursorShow(npos)
 	tv.SavePosHistory(tv.CursorPos)
 	return cut
@@ -2472,7 +2663,8 @@ func (tv *TextView) CutRect() *textbuf.Edit {
 // CopyRect copies any selected text to the clipboard, and returns that text,
 // optionally resetting the current selection
 func (tv *TextView) CopyRect(reset bool) *textbuf.Edit {
-	tbe := tv.Buf.RegionRect(tv.SelectReg.Start, tv.SelectReg.End)
+	reg := tv.RectSelReg()
+	tbe := tv.Buf.RegionRect(reg.Start, reg.End)
 	if tbe == nil {
 		return nil
 	}
@@ -2480,6 +2672,7 @@ func (tv *TextView) CopyRect(reset bool) *textbuf.Edit {
 	defer tv.TopUpdateEnd(wupdt)
 	cb := tbe.ToBytes()
 	oswin.TheApp.ClipBoard(tv.ParentWindow().OSWin).Write(mimedata.NewTextBytes(cb))
+	TextViewClipHistAdd(cb)
 	TextViewClipRect = tbe
 	if reset {
 		tv.SelectReset()
@@ -2495,6 +2688,14 @@ func (tv *TextView) PasteRect() {
 	}
 	wupdt := tv.TopUpdateStart()
 	defer tv.TopUpdateEnd(wupdt)
+	if tv.SelectRect && tv.HasSelection() {
+		reg := tv.RectSelReg()
+		if reg.End.Ch > reg.Start.Ch {
+			tv.Buf.DeleteTextRect(reg.Start, reg.End, EditSignal)
+		}
+		tv.SelectReset()
+		tv.CursorPos = reg.Start
+	}
 	ce := TextViewClipRect.Clone()
 	nl := ce.Reg.End.Ln - ce.Reg.Start.Ln
 	nch := ce.Reg.End.Ch - ce.Reg.Start.Ch
@@ -2555,6 +2756,11 @@ func (tv *TextView) MakeContextMenu(m *gi.Menu) {
 				txf.Paste()
 			})
 		ac.SetInactiveState(oswin.TheApp.ClipBoard(tv.ParentWindow().OSWin).IsEmpty())
+		m.AddAction(gi.ActOpts{Label: "Block Select", Tooltip: "toggle rectangular block selection mode -- also Alt+click / drag"},
+			tv.This(), func(recv, send ki.Ki, sig int64, data any) {
+				txf := recv.Embed(KiT_TextView).(*TextView)
+				txf.SelectRectModeToggle()
+			})
 	} else {
 		ac = m.AddAction(gi.ActOpts{Label: "Clear"},
 			tv.This(), func(recv, send ki.Ki, sig int64, data any) {
@@ -3060,6 +3266,13 @@ var TextViewBlinkMu sync.Mutex
 // only one of which can be active at at a time
 var TextViewBlinker *time.Ticker
 
+// textViewBlinkMSec is the gi.CursorBlinkMSec that TextViewBlinker ticks at
+var textViewBlinkMSec int
+
+// textViewBlinkStopped is set when TextViewBlinker is stopped because the
+// window is idle -- it is restarted when the window wakes up
+var textViewBlinkStopped bool
+
 // BlinkingTextView is the text field that is blinking
 var BlinkingTextView *TextView
 
@@ -3102,12 +3315,33 @@ func TextViewBlink() {
 			TextViewBlinkMu.Unlock()
 			continue
 		}
+		if win.IsIdle() { // leave cursor on, and stop ticking until woken up
+			if !tv.BlinkOn {
+				tv.BlinkOn = true
+				tv.RenderCursor(true)
+			}
+			TextViewBlinker.Stop()
+			textViewBlinkStopped = true
+			TextViewBlinkMu.Unlock()
+			continue
+		}
 		tv.BlinkOn = !tv.BlinkOn
 		tv.RenderCursor(tv.BlinkOn)
 		TextViewBlinkMu.Unlock()
 	}
 }
 
+// restartTextViewBlink restarts TextViewBlinker if it was stopped while
+// the window was idle
+func restartTextViewBlink() {
+	TextViewBlinkMu.Lock()
+	if TextViewBlinker != nil && textViewBlinkStopped && gi.CursorBlinkMSec > 0 {
+		TextViewBlinker.Reset(time.Duration(gi.CursorBlinkMSec) * time.Millisecond)
+		textViewBlinkStopped = false
+	}
+	TextViewBlinkMu.Unlock()
+}
+
 // StartCursor starts the cursor blinking and renders it
 func (tv *TextView) StartCursor() {
 	if tv == nil || tv.This() == nil {
@@ -3125,7 +3359,12 @@ func (tv *TextView) StartCursor() {
 	if TextViewBlinker == nil {
 		TextViewBlinker = time.NewTicker(time.Duration(gi.CursorBlinkMSec) * time.Millisecond)
 		go TextViewBlink()
+		gi.AddIdleWakeFunc(restartTextViewBlink)
+	} else if textViewBlinkMSec != gi.CursorBlinkMSec || textViewBlinkStopped { // prefs changed or stopped when idle
+		TextViewBlinker.Reset(time.Duration(gi.CursorBlinkMSec) * time.Millisecond)
 	}
+	textViewBlinkMSec = gi.CursorBlinkMSec
+	textViewBlinkStopped = false
 	tv.BlinkOn = true
 	win := tv.ParentWindow()
 	if win != nil && !win.IsResizing() {
@@ -3294,6 +3533,10 @@ func (tv *TextView) RenderSelect() {
 	if !tv.HasSelection() {
 		return
 	}
+	if tv.SelectRect {
+		tv.RenderSelectRect()
+		return
+	}
 	tv.RenderRegionBox(tv.SelectReg, TextViewSel)
 }
 
@@ -3472,6 +3715,12 @@ func (tv *TextView) VisSizes() {
 		tv.ClearFlag(int(TextViewHasLineNos))
 		tv.LineNoOff = 0
 	}
+	if len(tv.Gutter) > 0 {
+		if tv.LineNoOff == 0 {
+			tv.LineNoOff = sty.Font.Face.Metrics.Ch + spc
+		}
+		tv.LineNoOff += tv.GutterColsWidth()
+	}
 	tv.RenderSize()
 }
 
@@ -3529,7 +3778,7 @@ func (tv *TextView) RenderAllLinesInBounds() {
 		return
 	}
 
-	if tv.HasLineNos() {
+	if tv.HasGutter() {
 		tv.RenderLineNosBoxAll()
 		for ln := stln; ln <= edln; ln++ {
 			tv.RenderLineNo(ln, false, false) // don't re-render std fill boxes, no separate vp upload
@@ -3540,13 +3789,14 @@ func (tv *TextView) RenderAllLinesInBounds() {
 	tv.RenderHighlights(stln, edln)
 	tv.RenderScopelights(stln, edln)
 	tv.RenderSelect()
-	if tv.HasLineNos() {
+	if tv.HasGutter() {
 		tbb := tv.VpBBox
 		tbb.Min.X += int(tv.LineNoOff)
 		rs.Unlock()
 		rs.PushBounds(tbb)
 		rs.Lock()
 	}
+	tv.RenderRulers(float32(tv.VpBBox.Min.Y), float32(tv.VpBBox.Max.Y))
 	for ln := stln; ln <= edln; ln++ {
 		lst := pos.Y + tv.Offs[ln]
 		lp := pos
@@ -3554,15 +3804,16 @@ func (tv *TextView) RenderAllLinesInBounds() {
 		lp.X += tv.LineNoOff
 		tv.Renders[ln].Render(rs, lp) // not top pos -- already has baseline offset
 	}
+	tv.RenderMarks(stln, edln)
 	rs.Unlock()
-	if tv.HasLineNos() {
+	if tv.HasGutter() {
 		rs.PopBounds()
 	}
 }
 
 // RenderLineNosBoxAll renders the background for the line numbers in a darker shade
 func (tv *TextView) RenderLineNosBoxAll() {
-	if !tv.HasLineNos() {
+	if !tv.HasGutter() {
 		return
 	}
 	rs := tv.Render()
@@ -3578,7 +3829,7 @@ func (tv *TextView) RenderLineNosBoxAll() {
 
 // RenderLineNosBox renders the background for the line numbers in given range, in a darker shade
 func (tv *TextView) RenderLineNosBox(st, ed int) {
-	if !tv.HasLineNos() {
+	if !tv.HasGutter() {
 		return
 	}
 	rs := tv.Render()
@@ -3600,7 +3851,7 @@ func (tv *TextView) RenderLineNosBox(st, ed int) {
 // and if vpUpload is true it uploads the rendered region to viewport directly
 // (only if totally separate from other updates)
 func (tv *TextView) RenderLineNo(ln int, defFill bool, vpUpload bool) {
-	if !tv.HasLineNos() || tv.Buf == nil {
+	if !tv.HasGutter() || tv.Buf == nil {
 		return
 	}
 
@@ -3639,17 +3890,20 @@ func (tv *TextView) RenderLineNo(ln int, defFill bool, vpUpload bool) {
 		pc.FillBoxColor(rs, sbox, bsz, bgclr)
 	}
 
-	fst.BgColor.SetColor(nil)
-	lfmt := fmt.Sprintf("%d", tv.LineNoDigs)
-	lfmt = "%" + lfmt + "d"
-	lnstr := fmt.Sprintf(lfmt, ln+1)
-	tv.LineNoRender.SetString(lnstr, &fst, &sty.UnContext, &sty.Text, true, 0, 0)
-	pos := mat32.Vec2{}
-	lst := tv.CharStartPos(lex.Pos{Ln: ln}).Y // note: charstart pos includes descent
-	pos.Y = lst + mat32.FromFixed(sty.Font.Face.Face.Metrics().Ascent) - +mat32.FromFixed(sty.Font.Face.Face.Metrics().Descent)
-	pos.X = float32(tv.VpBBox.Min.X) + spc
-
-	tv.LineNoRender.Render(rs, pos)
+	tv.RenderGutterCols(ln, sbox, ebox)
+	if tv.HasLineNos() {
+		fst.BgColor.SetColor(nil)
+		lfmt := fmt.Sprintf("%d", tv.LineNoDigs)
+		lfmt = "%" + lfmt + "d"
+		lnstr := fmt.Sprintf(lfmt, ln+1)
+		tv.LineNoRender.SetString(lnstr, &fst, &sty.UnContext, &sty.Text, true, 0, 0)
+		pos := mat32.Vec2{}
+		lst := tv.CharStartPos(lex.Pos{Ln: ln}).Y // note: charstart pos includes descent
+		pos.Y = lst + mat32.FromFixed(sty.Font.Face.Face.Metrics().Ascent) - +mat32.FromFixed(sty.Font.Face.Face.Metrics().Descent)
+		pos.X = float32(tv.VpBBox.Min.X) + spc + tv.GutterColsWidth()
+
+		tv.LineNoRender.Render(rs, pos)
+	}
 	// todo: need an SvgRender interface that just takes an svg file or object
 	// and renders it to a given bitmap, and then just keep that around.
 	// if icnm, ok := tv.Buf.LineIcons[ln]; ok {
@@ -3749,7 +4003,7 @@ func (tv *TextView) RenderLines(st, ed int) bool {
 		tv.RenderSelect()
 		tv.RenderLineNosBox(visSt, visEd)
 
-		if tv.HasLineNos() {
+		if tv.HasGutter() {
 			for ln := visSt; ln <= visEd; ln++ {
 				tv.RenderLineNo(ln, true, false)
 			}
@@ -3759,6 +4013,7 @@ func (tv *TextView) RenderLines(st, ed int) bool {
 			rs.PushBounds(tbb)
 			rs.Lock()
 		}
+		tv.RenderRulers(boxMin.Y, boxMax.Y)
 		for ln := visSt; ln <= visEd; ln++ {
 			lst := pos.Y + tv.Offs[ln]
 			lp := pos
@@ -3766,8 +4021,9 @@ func (tv *TextView) RenderLines(st, ed int) bool {
 			lp.X += tv.LineNoOff
 			tv.Renders[ln].Render(rs, lp) // not top pos -- already has baseline offset
 		}
+		tv.RenderMarks(visSt, visEd)
 		rs.Unlock()
-		if tv.HasLineNos() {
+		if tv.HasGutter() {
 			rs.PopBounds()
 		}
 
@@ -4439,7 +4695,15 @@ func (tv *TextView) KeyInputInsertRune(kt *key.ChordEvent) {
 		tv.CancelComplete()
 		tv.QReplaceKeyInput(kt)
 	} else {
-		if kt.Rune == '{' || kt.Rune == '(' || kt.Rune == '[' {
+		ket, isPair := tv.Buf.PairClose(kt.Rune)
+		isPair = isPair && tv.Buf.Opts.SmartPairs
+		if isPair && tv.HasSelection() && !tv.SelectRect {
+			tv.CancelComplete()
+			tv.lastAutoInsert = 0
+			tv.SurroundSelection(kt.Rune, ket)
+			return
+		}
+		if isPair && ket != kt.Rune && (kt.Rune == '{' || kt.Rune == '(' || kt.Rune == '[') {
 			tv.KeyInputInsertBra(kt)
 		} else if kt.Rune == '}' && tv.Buf.Opts.AutoIndent && tv.CursorPos.Ch == tv.Buf.LineLen(tv.CursorPos.Ln) {
 			tv.CancelComplete()
@@ -4455,6 +4719,10 @@ func (tv *TextView) KeyInputInsertRune(kt *key.ChordEvent) {
 			tv.CursorPos.Ch++
 			tv.SetCursorShow(tv.CursorPos)
 			tv.lastAutoInsert = 0
+		} else if isPair && ket == kt.Rune {
+			tv.CancelComplete()
+			tv.lastAutoInsert = 0
+			tv.KeyInputInsertQuote(kt)
 		} else {
 			tv.lastAutoInsert = 0
 			tv.InsertAtCursor([]byte(string(kt.Rune)))
@@ -4557,7 +4825,19 @@ func (tv *TextView) MouseEvent(me *mouse.Event) {
 	case mouse.Left:
 		if me.Action == mouse.Press {
 			me.SetProcessed()
-			if _, got := tv.OpenLinkAt(newPos); got {
+			if tv.GutterClick(pt, newPos.Ln, me) {
+			} else if _, got := tv.OpenLinkAt(newPos); got {
+			} else if key.HasAnyModifierBits(me.Modifiers, key.Alt) {
+				if !tv.SelectRect || !tv.HasSelection() {
+					tv.SelectReset()
+					tv.SelectRect = true
+					tv.SelectMode = true
+					tv.SelectStart = tv.CursorPos
+				}
+				tv.SetCursor(newPos)
+				tv.SelectRegUpdate(tv.CursorPos)
+				tv.RenderSelectLines()
+				tv.RenderCursor(true)
 			} else {
 				tv.SetCursorFromMouse(pt, newPos, me.SelectMode())
 				tv.SavePosHistory(tv.CursorPos)
@@ -4791,6 +5071,7 @@ func (tv *TextView) Render2D() {
 		if tv.NeedsRefresh() {
 			tv.ClearNeedsRefresh()
 		}
+		tv.OutlineSig.Emit(tv.This(), int64(gi.OutlineChanged), nil) // e.g., reparsed
 	}
 
 	tv.VisSizes()
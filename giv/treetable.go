@@ -0,0 +1,442 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"image"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TreeTable
+
+// TreeTableCol is a data column shown for each node of a TreeTable, after
+// the tree column
+type TreeTableCol struct {
+	Name    string              `desc:"name of the column, shown in the header, and passed to TreeTableValuer nodes"`
+	Width   units.Value         `desc:"width of the column"`
+	Align   gist.Align          `desc:"horizontal alignment of the values in the column"`
+	Tooltip string              `desc:"tooltip for the column header"`
+	Value   func(ki.Ki) any     `view:"-" json:"-" xml:"-" desc:"function returning the value of the column for given source node, for nodes that are not TreeTableValuer (or do not have a value for the column) -- can be nil"`
+	Format  func(any) string    `view:"-" json:"-" xml:"-" desc:"optional function formatting the values for display -- the default is TreeTableValueString"`
+	Less    func(a, b any) bool `view:"-" json:"-" xml:"-" desc:"optional function comparing the values for sorting -- the default is TreeTableLess"`
+}
+
+// TreeTableValuer is an optional interface for the source nodes of a
+// TreeTable, providing the values of the columns for the node, which take
+// precedence over the Value function of the column.  It returns false if
+// the node does not have a value for the column.
+type TreeTableValuer interface {
+	TreeTableValue(col string) (any, bool)
+}
+
+// TreeTable shows a tree of Ki nodes as in TreeView, in the first column,
+// with additional data columns for each node, e.g., the size and
+// modification time of files, or the time spent in functions in a
+// profiler.  The values of the columns come from the nodes, if they are
+// TreeTableValuer, or from the Value function of the columns.  Clicking on
+// a column header sorts by that column, with the children of each node
+// sorted among themselves, so the hierarchy is preserved -- the source
+// tree is not changed.
+type TreeTable struct {
+	gi.Layout
+	Cols     []TreeTableCol `desc:"the data columns, after the tree column"`
+	TreeName string         `desc:"header of the tree column -- defaults to Name"`
+	SortIdx  int            `desc:"column the children of each node are sorted by: 0 is the tree column, 1.. are the Cols, and -1 is the order of the source tree"`
+	SortDesc bool           `desc:"whether the sort order is descending"`
+}
+
+var KiT_TreeTable = kit.Types.AddType(&TreeTable{}, TreeTableProps)
+
+// AddNewTreeTable adds a new tree table to given parent node, with given name.
+func AddNewTreeTable(parent ki.Ki, name string) *TreeTable {
+	tt := parent.AddNewChild(KiT_TreeTable, name).(*TreeTable)
+	tt.SortIdx = -1
+	return tt
+}
+
+// TreeTableProps are style properties for TreeTable
+var TreeTableProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+	"max-width":     -1,
+	"max-height":    -1,
+}
+
+// AddCol adds a data column with given name, width in Ch units, and value
+// function (can be nil if the nodes are TreeTableValuer), returning it for
+// further settings
+func (tt *TreeTable) AddCol(name string, width float32, value func(ki.Ki) any) *TreeTableCol {
+	tt.Cols = append(tt.Cols, TreeTableCol{Name: name, Width: units.NewCh(width), Value: value})
+	return &tt.Cols[len(tt.Cols)-1]
+}
+
+// SetRootNode configures the table, and sets the root of the tree shown
+func (tt *TreeTable) SetRootNode(root ki.Ki) {
+	updt := tt.UpdateStart()
+	tt.Config()
+	tt.Tree().SetRootNode(root)
+	tt.SortTree(true)
+	tt.UpdateEnd(updt)
+}
+
+// Config configures the header and tree -- call after changing the Cols
+func (tt *TreeTable) Config() {
+	tt.Lay = gi.LayoutVert
+	tt.SetProp("spacing", 0)
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "header")
+	config.Add(gi.KiT_Frame, "tree-frame")
+	mods, updt := tt.ConfigChildren(config)
+	if mods {
+		tf := tt.TreeFrame()
+		tf.Lay = gi.LayoutVert
+		tf.SetStretchMax()
+		tf.SetProp("overflow", gist.OverflowAuto)
+		AddNewTreeTableView(tf, "tree")
+	} else {
+		updt = tt.UpdateStart()
+	}
+	tt.ConfigHeader()
+	tt.UpdateEnd(updt)
+}
+
+// Header returns the header toolbar
+func (tt *TreeTable) Header() *gi.ToolBar {
+	return tt.ChildByName("header", 0).(*gi.ToolBar)
+}
+
+// TreeFrame returns the frame containing the tree
+func (tt *TreeTable) TreeFrame() *gi.Frame {
+	return tt.ChildByName("tree-frame", 1).(*gi.Frame)
+}
+
+// Tree returns the root tree view, whose TreeViewSig has the selection
+// and other signals
+func (tt *TreeTable) Tree() *TreeTableView {
+	return tt.TreeFrame().ChildByName("tree", 0).(*TreeTableView)
+}
+
+// ConfigHeader configures the header actions for the columns
+func (tt *TreeTable) ConfigHeader() {
+	hdr := tt.Header()
+	hdr.SetStretchMaxWidth()
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Action, "head-tree")
+	for i := range tt.Cols {
+		config.Add(gi.KiT_Action, "head-"+strconv.Itoa(i))
+	}
+	config.Add(gi.KiT_Space, "sb-space")
+	hdr.ConfigChildren(config)
+	nm := tt.TreeName
+	if nm == "" {
+		nm = "Name"
+	}
+	for i := 0; i <= len(tt.Cols); i++ {
+		act := hdr.Child(i).(*gi.Action)
+		if i == 0 {
+			act.SetText(nm)
+			act.SetStretchMaxWidth()
+			act.Tooltip = nm + " (click to sort by)"
+		} else {
+			col := &tt.Cols[i-1]
+			act.SetText(col.Name)
+			act.SetMinPrefWidth(col.Width)
+			act.SetProp("max-width", col.Width)
+			act.Tooltip = col.Name + " (click to sort by)"
+			if col.Tooltip != "" {
+				act.Tooltip += ": " + col.Tooltip
+			}
+		}
+		switch {
+		case i != tt.SortIdx:
+			act.SetIcon("none")
+		case tt.SortDesc:
+			act.SetIcon("wedge-down")
+		default:
+			act.SetIcon("wedge-up")
+		}
+		act.Data = i
+		act.ActionSig.ConnectOnly(tt.This(), func(recv, send ki.Ki, sig int64, data any) {
+			ttv := recv.Embed(KiT_TreeTable).(*TreeTable)
+			ttv.SortByAction(send.(*gi.Action).Data.(int))
+		})
+	}
+}
+
+// SortByAction sorts by given column (0 = tree column), toggling between
+// ascending and descending if already sorting by it
+func (tt *TreeTable) SortByAction(idx int) {
+	if idx == tt.SortIdx {
+		tt.SortDesc = !tt.SortDesc
+	} else {
+		tt.SortDesc = false
+	}
+	tt.SortBy(idx, tt.SortDesc)
+}
+
+// SortBy sorts the children of each node by given column (0 = tree column,
+// 1.. = Cols, -1 = the order of the source tree), in descending order if
+// desc is true
+func (tt *TreeTable) SortBy(idx int, desc bool) {
+	wupdt := tt.TopUpdateStart()
+	defer tt.TopUpdateEnd(wupdt)
+	updt := tt.UpdateStart()
+	tt.SortIdx = idx
+	tt.SortDesc = desc
+	tt.ConfigHeader()
+	tr := tt.Tree()
+	if idx < 0 {
+		tr.ReSync() // back to source order
+	} else {
+		tt.SortTree(true)
+	}
+	tt.Header().SetFullReRender()
+	tt.UpdateEnd(updt)
+}
+
+// NodeValue returns the value of given column (0 = tree column) for given
+// source node
+func (tt *TreeTable) NodeValue(sn ki.Ki, idx int) any {
+	if idx == 0 {
+		if lbl, has := gi.ToLabeler(sn); has {
+			return lbl
+		}
+		return sn.Name()
+	}
+	col := &tt.Cols[idx-1]
+	if tv, ok := sn.(TreeTableValuer); ok {
+		if v, has := tv.TreeTableValue(col.Name); has {
+			return v
+		}
+	}
+	if col.Value != nil {
+		return col.Value(sn)
+	}
+	return nil
+}
+
+// NodeValueString returns the display string of given column (1.. = Cols)
+// for given source node
+func (tt *TreeTable) NodeValueString(sn ki.Ki, idx int) string {
+	v := tt.NodeValue(sn, idx)
+	if idx > 0 && tt.Cols[idx-1].Format != nil {
+		return tt.Cols[idx-1].Format(v)
+	}
+	return TreeTableValueString(v)
+}
+
+// SortTree sorts the children of each open node of the tree by the
+// current sort column, if not already sorted -- if force is true, closed
+// nodes are sorted too.  The children of closed nodes are otherwise sorted
+// when they are opened, and changes in the values are picked up at the
+// next layout.
+func (tt *TreeTable) SortTree(force bool) {
+	if tt.SortIdx < 0 || tt.SortIdx > len(tt.Cols) || !tt.HasChildren() {
+		return
+	}
+	less := TreeTableLess
+	if tt.SortIdx > 0 && tt.Cols[tt.SortIdx-1].Less != nil {
+		less = tt.Cols[tt.SortIdx-1].Less
+	}
+	tr := tt.Tree()
+	if tr.SrcNode == nil {
+		return
+	}
+	sorted := false
+	tr.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		tv := k.Embed(KiT_TreeView).(*TreeView)
+		if tv.IsClosed() && !force {
+			return ki.Break // don't go into kids
+		}
+		kids := *tv.Children()
+		if len(kids) < 2 {
+			return ki.Continue
+		}
+		vals := make(map[ki.Ki]any, len(kids))
+		for _, kid := range kids {
+			if kv := kid.Embed(KiT_TreeView).(*TreeView); kv.SrcNode != nil {
+				vals[kid] = tt.NodeValue(kv.SrcNode, tt.SortIdx)
+			}
+		}
+		lessIdx := func(i, j int) bool {
+			if tt.SortDesc {
+				return less(vals[kids[j]], vals[kids[i]])
+			}
+			return less(vals[kids[i]], vals[kids[j]])
+		}
+		if !sort.SliceIsSorted(kids, lessIdx) {
+			sort.SliceStable(kids, lessIdx)
+			sorted = true
+		}
+		return ki.Continue
+	})
+	if sorted {
+		tr.SetFullReRender()
+	}
+}
+
+func (tt *TreeTable) Layout2D(parBBox image.Rectangle, iter int) bool {
+	if tt.HasChildren() {
+		tt.SortTree(false) // sort any newly opened or updated nodes
+	}
+	return tt.Layout.Layout2D(parBBox, iter)
+}
+
+func (tt *TreeTable) Style2D() {
+	if tt.HasChildren() {
+		// keep the header columns aligned with the tree columns when the
+		// tree has a scrollbar, as of the last layout
+		tf := tt.TreeFrame()
+		sbw := units.NewPx(0)
+		if tf.HasScroll[mat32.Y] {
+			sbw = tf.Sty.Layout.ScrollBarWidth
+		}
+		sp := tt.Header().ChildByName("sb-space", len(tt.Cols)+1).(*gi.Space)
+		sp.SetFixedWidth(sbw)
+	}
+	tt.Layout.Style2D()
+}
+
+// TreeTableValueString returns the display string for given column value:
+// time.Time values are formatted with date and time, and otherwise
+// kit.ToString is used (which uses the String method if available)
+func TreeTableValueString(v any) string {
+	if kit.IfaceIsNil(v) {
+		return ""
+	}
+	if tm, ok := v.(time.Time); ok {
+		return tm.Format("2006-01-02 15:04:05")
+	}
+	return kit.ToString(v)
+}
+
+// TreeTableLess is the default comparison of column values for sorting:
+// strings are compared case-insensitively, times by time, values with an
+// Int method (e.g., FileSize, FileTime) or that are numbers numerically,
+// and otherwise their strings are compared.  Missing (nil) values sort last.
+func TreeTableLess(a, b any) bool {
+	switch {
+	case kit.IfaceIsNil(a):
+		return false
+	case kit.IfaceIsNil(b):
+		return true
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.ToLower(av) < strings.ToLower(bv)
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return av.Before(bv)
+		}
+	}
+	if ai, ok := a.(ints.Inter); ok {
+		if bi, ok := b.(ints.Inter); ok {
+			return ai.Int() < bi.Int()
+		}
+	}
+	af, aok := kit.ToFloat(a)
+	bf, bok := kit.ToFloat(b)
+	if aok && bok {
+		return af < bf
+	}
+	return kit.ToString(a) < kit.ToString(b)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TreeTableView
+
+// TreeTableView is the TreeView used in a TreeTable, which shows the
+// values of the data columns after the label of each node
+type TreeTableView struct {
+	TreeView
+}
+
+var KiT_TreeTableView = kit.Types.AddType(&TreeTableView{}, nil)
+
+// AddNewTreeTableView adds a new tree table view to given parent node, with
+// given name.
+func AddNewTreeTableView(parent ki.Ki, name string) *TreeTableView {
+	tv := parent.AddNewChild(KiT_TreeTableView, name).(*TreeTableView)
+	tv.OpenDepth = 4
+	return tv
+}
+
+// TreeTableViewProps are the TreeViewProps, set in init
+var TreeTableViewProps = ki.Props{}
+
+func init() {
+	for k, v := range TreeViewProps {
+		TreeTableViewProps[k] = v
+	}
+	kit.Types.SetProps(KiT_TreeTableView, TreeTableViewProps)
+}
+
+// TreeTable returns the TreeTable that this view is in, or nil
+func (tv *TreeTableView) TreeTable() *TreeTable {
+	if tv.RootView == nil || tv.RootView.This() == nil {
+		return nil
+	}
+	tt := tv.RootView.ParentByType(KiT_TreeTable, ki.Embeds)
+	if tt == nil {
+		return nil
+	}
+	return tt.Embed(KiT_TreeTable).(*TreeTable)
+}
+
+// AddColumnParts adds a stretch after the label, so the columns are at the
+// right, aligned across all nodes, and a label for each column
+func (tv *TreeTableView) AddColumnParts(config *kit.TypeAndNameList) {
+	tt := tv.TreeTable()
+	if tt == nil {
+		return
+	}
+	config.Add(gi.KiT_Stretch, "col-stretch")
+	for i := range tt.Cols {
+		config.Add(gi.KiT_Label, "col-"+strconv.Itoa(i))
+	}
+}
+
+// UpdateColumnParts sets the column labels to the values for the node
+func (tv *TreeTableView) UpdateColumnParts(mods bool) {
+	tt := tv.TreeTable()
+	if tt == nil || tv.SrcNode == nil {
+		return
+	}
+	for i := range tt.Cols {
+		lbl, ok := tv.Parts.ChildByName("col-"+strconv.Itoa(i), 0).(*gi.Label)
+		if !ok {
+			continue
+		}
+		col := &tt.Cols[i]
+		if mods {
+			lbl.SetMinPrefWidth(col.Width)
+			lbl.SetProp("max-width", col.Width)
+			lbl.SetProp("text-align", col.Align)
+			lbl.SetProp("margin", units.NewPx(0))
+			lbl.SetProp("padding", units.NewPx(0))
+			tv.Sty.Font.CopyNonDefaultProps(lbl.This())
+		}
+		txt := tt.NodeValueString(tv.SrcNode, i+1)
+		if lbl.Text != txt {
+			lbl.SetText(txt)
+		}
+		if mods {
+			tv.StylePart(gi.Node2D(lbl))
+		}
+	}
+}
@@ -52,6 +52,8 @@ type TreeView struct {
 	WidgetSize       mat32.Vec2                  `desc:"just the size of our widget -- our alloc includes all of our children, but we only draw us"`
 	Icon             gi.IconName                 `json:"-" xml:"icon" view:"show-name" desc:"optional icon, displayed to the the left of the text label"`
 	RootView         *TreeView                   `json:"-" xml:"-" desc:"cached root of the view"`
+	TypeAhead        gi.TypeAhead                `copy:"-" json:"-" xml:"-" view:"-" desc:"state of the type-ahead search of the visible nodes, on the root of the view -- see TypeAheadKeyInput"`
+	Selection        gi.SelectionModel           `copy:"-" json:"-" xml:"-" desc:"selection of the view, by ViewIdx, with the selection policy Mode -- on the root of the view, which sends the changes as WidgetSig WidgetSelectionChanged signals"`
 }
 
 var KiT_TreeView = kit.Types.AddType(&TreeView{}, nil)
@@ -88,8 +90,10 @@ func (tv *TreeView) SetRootNode(sk ki.Ki) {
 		sk.NodeSignal().Connect(tv.This(), SrcNodeSignalFunc) // we recv signals from source
 	}
 	tv.RootView = tv
+	tv.RegisterFind()
 	tvIdx := 0
 	tv.SyncToSrc(&tvIdx, true, 0)
+	tv.SyncSelection()
 	tv.UpdateEnd(updt)
 }
 
@@ -113,6 +117,7 @@ func (tv *TreeView) ReSync() {
 	tv.SetFullReRender() //
 	tvIdx := tv.ViewIdx
 	tv.SyncToSrc(&tvIdx, false, 0)
+	tv.SyncSelection()
 	tv.UpdateSig()
 }
 
@@ -217,6 +222,7 @@ func SrcNodeSignalFunc(tvki, send ki.Ki, sig int64, data any) {
 				fmt.Printf("treeview: structupdate for node, idx: %v  %v", tvIdx, tv.Path())
 			}
 			tv.SyncToSrc(&tvIdx, false, 0)
+			tv.SyncSelection()
 		} else {
 			tv.UpdateSig()
 		}
@@ -482,6 +488,27 @@ func (tv *TreeView) SetSelectedViews(sl []*TreeView) {
 	}
 }
 
+// SyncSelection updates the Selection of the RootView to the ViewIdx of the
+// selected views, after they have been renumbered, without any signal
+func (tv *TreeView) SyncSelection() {
+	if tv.RootView == nil {
+		return
+	}
+	sm := &tv.RootView.Selection
+	sm.Reset()
+	for _, v := range tv.SelectedViews() {
+		sm.Selected[v.ViewIdx] = struct{}{}
+	}
+}
+
+// EmitSelectionChanged emits the WidgetSelectionChanged signal from the
+// RootView, with the changes to its Selection, if any
+func (tv *TreeView) EmitSelectionChanged() {
+	if tv.RootView != nil {
+		tv.RootView.EmitSelectionChangedSignal(&tv.RootView.Selection)
+	}
+}
+
 // SelectedSrcNodes returns a slice of the currently-selected source nodes
 // in the entire tree view
 func (tv *TreeView) SelectedSrcNodes() ki.Slice {
@@ -501,6 +528,9 @@ func (tv *TreeView) Select() {
 		sl := tv.SelectedViews()
 		sl = append(sl, tv)
 		tv.SetSelectedViews(sl)
+		if tv.RootView != nil {
+			tv.RootView.Selection.Add(tv.ViewIdx)
+		}
 		tv.UpdateSig()
 	}
 }
@@ -519,6 +549,9 @@ func (tv *TreeView) Unselect() {
 			}
 		}
 		tv.SetSelectedViews(sl)
+		if tv.RootView != nil {
+			tv.RootView.Selection.Remove(tv.ViewIdx)
+		}
 		tv.UpdateSig()
 	}
 }
@@ -533,15 +566,17 @@ func (tv *TreeView) UnselectAll() {
 	tv.SetSelectedViews(nil) // clear in advance
 	for _, v := range sl {
 		v.ClearSelected()
+		tv.RootView.Selection.Remove(v.ViewIdx)
 		v.UpdateSig()
 	}
 	tv.TopUpdateEnd(wupdt)
 	tv.RootView.TreeViewSig.Emit(tv.RootView.This(), int64(TreeViewAllUnselected), tv.This())
 }
 
-// SelectAll all items in view
+// SelectAll all items in view, unless the Selection Mode of the RootView
+// is SelectionSingle
 func (tv *TreeView) SelectAll() {
-	if tv.Viewport == nil {
+	if tv.Viewport == nil || tv.RootView.Selection.Mode == gi.SelectionSingle {
 		return
 	}
 	wupdt := tv.TopUpdateStart()
@@ -553,14 +588,27 @@ func (tv *TreeView) SelectAll() {
 	}
 	tv.TopUpdateEnd(wupdt)
 	tv.RootView.TreeViewSig.Emit(tv.RootView.This(), int64(TreeViewAllSelected), tv.This())
+	tv.EmitSelectionChanged()
 }
 
 // SelectUpdate updates selection to include this node, using selectmode
-// from mouse event (ExtendContinuous, ExtendOne).  Returns true if this node selected
+// from mouse event (ExtendContinuous, ExtendOne), as allowed by the
+// Selection Mode of the RootView.  Returns true if this node selected
 func (tv *TreeView) SelectUpdate(mode mouse.SelectModes) bool {
 	if mode == mouse.NoSelect {
 		return false
 	}
+	sm := &tv.RootView.Selection
+	switch {
+	case sm.Mode == gi.SelectionSingle && mode != mouse.Unselect && mode != mouse.UnselectQuiet:
+		mode = mouse.SelectOne
+	case sm.Mode == gi.SelectionContiguous && mode == mouse.ExtendOne:
+		mode = mouse.ExtendContinuous
+	}
+	sm.Current = tv.ViewIdx
+	if mode == mouse.SelectOne || mode == mouse.ExtendOne {
+		sm.Anchor = tv.ViewIdx
+	}
 	wupdt := tv.TopUpdateStart()
 	sel := false
 	switch mode {
@@ -638,6 +686,9 @@ func (tv *TreeView) SelectAction(mode mouse.SelectModes) bool {
 	if sel {
 		tv.RootView.TreeViewSig.Emit(tv.RootView.This(), int64(TreeViewSelected), tv.This())
 	}
+	if mode != mouse.SelectQuiet && mode != mouse.UnselectQuiet {
+		tv.EmitSelectionChanged()
+	}
 	return sel
 }
 
@@ -646,6 +697,7 @@ func (tv *TreeView) UnselectAction() {
 	if tv.IsSelected() {
 		tv.Unselect()
 		tv.RootView.TreeViewSig.Emit(tv.RootView.This(), int64(TreeViewUnselected), tv.This())
+		tv.EmitSelectionChanged()
 	}
 }
 
@@ -1654,6 +1706,10 @@ func (tv *TreeView) KeyInput(kt *key.ChordEvent) {
 	if gi.KeyEventTrace {
 		fmt.Printf("TreeView KeyInput: %v\n", tv.Path())
 	}
+	tv.TypeAheadKeyInput(kt)
+	if kt.IsProcessed() {
+		return
+	}
 	kf := gi.KeyFun(kt.Chord())
 	selMode := mouse.SelectModeBits(kt.Modifiers)
 
@@ -1667,6 +1723,7 @@ func (tv *TreeView) KeyInput(kt *key.ChordEvent) {
 	switch kf {
 	case gi.KeyFunCancelSelect:
 		tv.UnselectAll()
+		tv.EmitSelectionChanged()
 		tv.SetSelectMode(false)
 		kt.SetProcessed()
 	case gi.KeyFunMoveRight:
@@ -1730,6 +1787,56 @@ func (tv *TreeView) KeyInput(kt *key.ChordEvent) {
 	}
 }
 
+// TypeAheadKeyInput does the type-ahead search of the visible nodes of the
+// tree for given key event (see gi.TypeAhead), selecting the next node
+// after this one whose label starts with the typed query
+func (tv *TreeView) TypeAheadKeyInput(kt *key.ChordEvent) {
+	rt := tv.RootView
+	if rt == nil {
+		return
+	}
+	ta := &rt.TypeAhead
+	search, next := ta.KeyInput(kt)
+	if !search {
+		return
+	}
+	var vis []*TreeView
+	cur := 0
+	rt.FuncDownMeFirst(0, nil, func(k ki.Ki, level int, d any) bool {
+		tvki := k.Embed(KiT_TreeView)
+		if tvki == nil {
+			return ki.Break
+		}
+		tvn := tvki.(*TreeView)
+		if tvn == tv {
+			cur = len(vis)
+		}
+		vis = append(vis, tvn)
+		return !tvn.IsClosed() // only visible nodes
+	})
+	if next {
+		cur++
+	}
+	var fnd *TreeView
+	for i := range vis {
+		tvn := vis[(cur+i)%len(vis)]
+		if ta.Matches(tvn.Label()) {
+			fnd = tvn
+			break
+		}
+	}
+	if fnd != nil && fnd != tv {
+		fnd.SelectAction(mouse.SelectOne)
+		fnd.GrabFocus()
+		fnd.ScrollToMe()
+	}
+	at := rt.AsWidget()
+	if ly := rt.ParentScrollLayout(); ly != nil {
+		at = ly.AsWidget()
+	}
+	ta.ShowPopup(at, fnd != nil)
+}
+
 func (tv *TreeView) TreeViewEvents() {
 	tv.ConnectEvent(oswin.KeyChordEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d any) {
 		tvv := recv.Embed(KiT_TreeView).(*TreeView)
@@ -1843,6 +1950,18 @@ func (tv *TreeView) LabelPart() (*gi.Label, bool) {
 	return nil, false
 }
 
+// TreeViewColumner is an optional interface for types embedding TreeView
+// that show additional parts after the label, e.g., the columns of a
+// TreeTable
+type TreeViewColumner interface {
+	// AddColumnParts adds the column parts to the parts config
+	AddColumnParts(config *kit.TypeAndNameList)
+
+	// UpdateColumnParts updates the column parts after they are configured
+	// -- mods is true if the parts were just (re)made
+	UpdateColumnParts(mods bool)
+}
+
 func (tv *TreeView) ConfigParts() {
 	tv.Parts.Lay = gi.LayoutHoriz
 	tv.Parts.Sty.Template = "giv.TreeView.Parts"
@@ -1854,6 +1973,10 @@ func (tv *TreeView) ConfigParts() {
 		config.Add(gi.KiT_Icon, "icon")
 	}
 	config.Add(gi.KiT_Label, "label")
+	tc, hasCols := tv.This().(TreeViewColumner)
+	if hasCols {
+		tc.AddColumnParts(&config)
+	}
 	mods, updt := tv.Parts.ConfigChildren(config)
 	if tv.HasChildren() {
 		if wb, ok := tv.BranchPart(); ok {
@@ -1910,6 +2033,9 @@ func (tv *TreeView) ConfigParts() {
 			tv.StylePart(gi.Node2D(lbl))
 		}
 	}
+	if hasCols {
+		tc.UpdateColumnParts(mods)
+	}
 	tv.Parts.UpdateEnd(updt)
 }
 
@@ -1933,6 +2059,9 @@ func (tv *TreeView) ConfigPartsIfNeeded() {
 			wb.SetChecked(!tv.IsClosed())
 		}
 	}
+	if tc, ok := tv.This().(TreeViewColumner); ok {
+		tc.UpdateColumnParts(false)
+	}
 }
 
 var TreeViewProps = ki.Props{
@@ -0,0 +1,275 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"log"
+	"reflect"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
+)
+
+///////////////////////////////////////////////////////////////////
+// TwinTableViews
+
+// TwinTableRow is one aligned row of a TwinTableViews, with the indexes of
+// the elements in slices A and B -- -1 if missing in that slice
+type TwinTableRow struct {
+	A int
+	B int
+}
+
+// TwinTableViews presents two slices of the same struct type side-by-side
+// in TableViews in a SplitView, for comparing them (e.g., configurations,
+// or before / after versions of data).  The rows are aligned by the value
+// of the KeyField (or by index if empty), with an empty row shown where an
+// element is missing in one slice, and the cells that differ highlighted.
+// The views scroll in sync with each other.  The views show aligned copies
+// of the slices, so they are inactive -- call Update after the slices change.
+type TwinTableViews struct {
+	gi.SplitView
+	SliceA   any            `desc:"the A slice -- pointer to a slice of structs"`
+	SliceB   any            `desc:"the B slice -- pointer to a slice of structs of the same type"`
+	KeyField string         `desc:"name of the field whose value aligns the rows of the two slices -- if empty, rows are aligned by index"`
+	Rows     []TwinTableRow `json:"-" xml:"-" desc:"the aligned rows"`
+	AlignA   any            `json:"-" xml:"-" desc:"the aligned copy of slice A that is viewed"`
+	AlignB   any            `json:"-" xml:"-" desc:"the aligned copy of slice B that is viewed"`
+}
+
+var KiT_TwinTableViews = kit.Types.AddType(&TwinTableViews{}, TwinTableViewsProps)
+
+// AddNewTwinTableViews adds a new twin table views to given parent node,
+// with given name.
+func AddNewTwinTableViews(parent ki.Ki, name string) *TwinTableViews {
+	return parent.AddNewChild(KiT_TwinTableViews, name).(*TwinTableViews)
+}
+
+// TwinTableViewsProps are style properties for TwinTableViews
+var TwinTableViewsProps = ki.Props{
+	"EnumType:Flag":    gi.KiT_NodeFlags,
+	"max-width":        -1,
+	"max-height":       -1,
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+}
+
+// SetSlices sets the two slices to compare, which must be pointers to
+// slices of the same struct type (or pointers to structs), aligned by given
+// key field (empty for by index), and updates the views
+func (tv *TwinTableViews) SetSlices(sla, slb any, keyField string) {
+	tv.SliceA = sla
+	tv.SliceB = slb
+	tv.KeyField = keyField
+	tv.Update()
+}
+
+// Update re-aligns the slices and updates the views
+func (tv *TwinTableViews) Update() {
+	if kit.IfaceIsNil(tv.SliceA) || kit.IfaceIsNil(tv.SliceB) {
+		return
+	}
+	if reflect.TypeOf(tv.SliceA) != reflect.TypeOf(tv.SliceB) {
+		log.Printf("giv.TwinTableViews: slices must be of the same type: %T != %T\n", tv.SliceA, tv.SliceB)
+		return
+	}
+	tv.AlignRows()
+	tv.ConfigTables()
+}
+
+// TwinTableKey returns the string value of the given field of the struct
+// in given slice element (struct or pointer to struct), used for aligning
+func TwinTableKey(el reflect.Value, keyField string) string {
+	stru := kit.NonPtrValue(el)
+	fv := stru.FieldByName(keyField)
+	if !fv.IsValid() {
+		return ""
+	}
+	return kit.ToString(fv.Interface())
+}
+
+// AlignRows computes the aligned Rows from the slices, and makes the
+// aligned copies of the slices.  With a KeyField, the rows are in the order
+// of slice A, with the elements only in slice B inserted before the
+// next matching element.
+func (tv *TwinTableViews) AlignRows() {
+	sa := kit.NonPtrValue(reflect.ValueOf(tv.SliceA))
+	sb := kit.NonPtrValue(reflect.ValueOf(tv.SliceB))
+	na, nb := sa.Len(), sb.Len()
+	tv.Rows = tv.Rows[:0]
+	if tv.KeyField == "" {
+		for i := 0; i < na || i < nb; i++ {
+			r := TwinTableRow{-1, -1}
+			if i < na {
+				r.A = i
+			}
+			if i < nb {
+				r.B = i
+			}
+			tv.Rows = append(tv.Rows, r)
+		}
+	} else {
+		bidx := make(map[string]int, nb)
+		for i := nb - 1; i >= 0; i-- { // first one wins for duplicate keys
+			bidx[TwinTableKey(sb.Index(i), tv.KeyField)] = i
+		}
+		akeys := make(map[string]bool, na)
+		for i := 0; i < na; i++ {
+			akeys[TwinTableKey(sa.Index(i), tv.KeyField)] = true
+		}
+		used := make([]bool, nb)
+		bi := 0
+		for ai := 0; ai < na; ai++ {
+			j, has := bidx[TwinTableKey(sa.Index(ai), tv.KeyField)]
+			if !has || used[j] {
+				tv.Rows = append(tv.Rows, TwinTableRow{ai, -1})
+				continue
+			}
+			for ; bi < j; bi++ { // B elements before this one that are only in B
+				if !used[bi] && !akeys[TwinTableKey(sb.Index(bi), tv.KeyField)] {
+					used[bi] = true
+					tv.Rows = append(tv.Rows, TwinTableRow{-1, bi})
+				}
+			}
+			used[j] = true
+			tv.Rows = append(tv.Rows, TwinTableRow{ai, j})
+		}
+		for j := 0; j < nb; j++ {
+			if !used[j] {
+				tv.Rows = append(tv.Rows, TwinTableRow{-1, j})
+			}
+		}
+	}
+	tv.AlignA = twinAlignSlice(sa, tv.Rows, true)
+	tv.AlignB = twinAlignSlice(sb, tv.Rows, false)
+}
+
+// twinAlignSlice returns a pointer to a new slice with the elements of
+// given slice in the aligned row order, and zero values for missing rows
+func twinAlignSlice(sl reflect.Value, rows []TwinTableRow, isA bool) any {
+	styp := sl.Type()
+	etyp := styp.Elem()
+	asl := reflect.MakeSlice(styp, len(rows), len(rows))
+	for i, r := range rows {
+		idx := r.B
+		if isA {
+			idx = r.A
+		}
+		switch {
+		case idx >= 0:
+			asl.Index(i).Set(sl.Index(idx))
+		case etyp.Kind() == reflect.Ptr:
+			asl.Index(i).Set(reflect.New(etyp.Elem()))
+		}
+	}
+	ptr := reflect.New(styp)
+	ptr.Elem().Set(asl)
+	return ptr.Interface()
+}
+
+// CellDiffers returns true if the value of given field differs between the
+// two slices in given aligned row, or the row is missing in one of them
+func (tv *TwinTableViews) CellDiffers(row int, field string) bool {
+	if row < 0 || row >= len(tv.Rows) {
+		return false
+	}
+	r := tv.Rows[row]
+	if r.A < 0 || r.B < 0 {
+		return true
+	}
+	sa := kit.NonPtrValue(reflect.ValueOf(tv.SliceA))
+	sb := kit.NonPtrValue(reflect.ValueOf(tv.SliceB))
+	fa := kit.NonPtrValue(sa.Index(r.A)).FieldByName(field)
+	fb := kit.NonPtrValue(sb.Index(r.B)).FieldByName(field)
+	if !fa.IsValid() || !fb.IsValid() {
+		return false
+	}
+	return !reflect.DeepEqual(fa.Interface(), fb.Interface())
+}
+
+// ConfigTables configures the two table views to show the aligned slices
+func (tv *TwinTableViews) ConfigTables() {
+	tv.Dim = mat32.X
+	tv.SetStretchMax()
+	config := kit.TypeAndNameList{}
+	config.Add(KiT_TableView, "table-a")
+	config.Add(KiT_TableView, "table-b")
+	mods, updt := tv.ConfigChildren(config)
+	av, bv := tv.TableViews()
+	if !mods {
+		updt = tv.UpdateStart()
+	} else {
+		for _, v := range []*TableView{av, bv} {
+			v.SetStretchMax()
+			v.SetInactive()
+			v.NoSort = true
+			v.StyleFunc = tv.StyleCell
+		}
+	}
+	av.SetSlice(tv.AlignA)
+	bv.SetSlice(tv.AlignB)
+	if mods {
+		// sync scrolling
+		tv.SyncScroll(av, bv)
+		tv.SyncScroll(bv, av)
+	}
+	tv.UpdateEnd(updt)
+}
+
+// SyncScroll makes the to view scroll along with the from view
+func (tv *TwinTableViews) SyncScroll(from, to *TableView) {
+	from.ScrollBar().SliderSig.Connect(tv.This(), func(recv, send ki.Ki, sig int64, data any) {
+		if sig != int64(gi.SliderValueChanged) || to.StartIdx == from.StartIdx {
+			return
+		}
+		wupdt := to.TopUpdateStart()
+		to.StartIdx = from.StartIdx
+		to.UpdateSliceGrid()
+		to.ViewportSafe().ReRender2DNode(to.This().(gi.Node2D))
+		to.TopUpdateEnd(wupdt)
+	})
+}
+
+// StyleCell is the TableView StyleFunc that highlights the cells that
+// differ between the slices, and the rows missing in one of them
+func (tv *TwinTableViews) StyleCell(tbv *TableView, slice any, widg gi.Node2D, row, col int, vv ValueView) {
+	if col < 0 || col >= len(tbv.VisFields) {
+		return
+	}
+	wi := widg.AsNode2D()
+	missing := false
+	if row >= 0 && row < len(tv.Rows) {
+		r := tv.Rows[row]
+		missing = (tbv.Nm == "table-a" && r.A < 0) || (tbv.Nm == "table-b" && r.B < 0)
+	}
+	var clr any
+	switch {
+	case missing:
+		clr = &gi.Prefs.Colors.Control
+	case tv.CellDiffers(row, tbv.VisFields[col].Name):
+		clr = &gi.Prefs.Colors.Highlight
+	}
+	if clr != nil {
+		if _, err := wi.PropTry("background-color"); err != nil {
+			wi.SetFullReRender()
+		}
+		wi.SetProp("background-color", clr)
+		return
+	}
+	// widgets are re-used for other rows when scrolling
+	if _, err := wi.PropTry("background-color"); err == nil {
+		wi.SetFullReRender()
+	}
+	wi.DeleteProp("background-color")
+}
+
+// TableViews returns the two table views
+func (tv *TwinTableViews) TableViews() (*TableView, *TableView) {
+	a := tv.Child(0).(*TableView)
+	b := tv.Child(1).(*TableView)
+	return a, b
+}
@@ -7,10 +7,17 @@ package giv
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/netip"
+	"net/url"
+	"os/exec"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/c2h5oh/datasize"
+
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/gist"
 	"github.com/goki/gi/histyle"
@@ -57,6 +64,11 @@ func init() {
 		ki.InitNode(vv)
 		return vv
 	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(gist.ColorSpec{})), func() ValueView {
+		vv := &ColorSpecValueView{}
+		ki.InitNode(vv)
+		return vv
+	})
 	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(time.Time{})), func() ValueView {
 		vv := &TimeValueView{}
 		ki.InitNode(vv)
@@ -67,6 +79,41 @@ func init() {
 		ki.InitNode(vv)
 		return vv
 	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(time.Duration(0))), func() ValueView {
+		vv := &QuantityValueView{Kind: DurationQuantity}
+		ki.InitNode(vv)
+		return vv
+	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(datasize.ByteSize(0))), func() ValueView {
+		vv := &QuantityValueView{Kind: ByteSizeQuantity}
+		ki.InitNode(vv)
+		return vv
+	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(net.IP{})), func() ValueView {
+		vv := &NetValueView{Kind: NetIP}
+		ki.InitNode(vv)
+		return vv
+	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(net.IPNet{})), func() ValueView {
+		vv := &NetValueView{Kind: NetCIDR}
+		ki.InitNode(vv)
+		return vv
+	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(netip.Addr{})), func() ValueView {
+		vv := &NetValueView{Kind: NetIP}
+		ki.InitNode(vv)
+		return vv
+	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(netip.Prefix{})), func() ValueView {
+		vv := &NetValueView{Kind: NetCIDR}
+		ki.InitNode(vv)
+		return vv
+	})
+	ValueViewMapAdd(kit.LongTypeName(reflect.TypeOf(url.URL{})), func() ValueView {
+		vv := &NetValueView{Kind: NetURL}
+		ki.InitNode(vv)
+		return vv
+	})
 }
 
 // MapInlineLen is the number of map elements at or below which an inline
@@ -207,6 +254,24 @@ func ToValueView(it any, tags string) ValueView {
 				forceInline = true
 			case "no-inline":
 				forceNoInline = true
+			case "expr":
+				if nptyp.Kind() == reflect.String {
+					vv := &ExprValueView{}
+					ki.InitNode(vv)
+					return vv
+				}
+			case "ip", "cidr", "url", "email":
+				if nptyp.Kind() == reflect.String {
+					vv := &NetValueView{Kind: map[string]NetKinds{"ip": NetIP, "cidr": NetCIDR, "url": NetURL, "email": NetEmail}[vwtag]}
+					ki.InitNode(vv)
+					return vv
+				}
+			case "bytes":
+				if nk := nptyp.Kind(); nk >= reflect.Int && nk <= reflect.Uint64 {
+					vv := &QuantityValueView{Kind: ByteSizeQuantity}
+					ki.InitNode(vv)
+					return vv
+				}
 			}
 		}
 	}
@@ -635,6 +700,30 @@ func (vv *ValueViewBase) Val() reflect.Value {
 	return vv.Value
 }
 
+// StringsChooser pops up a menu of given strings, for ValueViews that
+// choose their value from a list, in the Activate method: the chosen
+// string is set as the value, the widget is updated, and dlgFunc is
+// called (if non-nil) as for an accepted dialog.  If inactive is non-nil,
+// strings for which it returns true are shown as inactive and cannot be
+// chosen.  The popup is located at the Widget if set, else the viewport.
+func (vv *ValueViewBase) StringsChooser(vp *gi.Viewport2D, strs []string, inactive func(idx int, str string) bool, dlgRecv ki.Ki, dlgFunc ki.RecvFunc) {
+	cur := kit.ToString(vv.Value.Interface())
+	var recv gi.Node2D
+	if vv.Widget != nil {
+		recv = vv.Widget
+	} else {
+		recv = vp.This().(gi.Node2D)
+	}
+	gi.StringsChooserPopupInactive(strs, cur, inactive, recv, func(recv, send ki.Ki, sig int64, data any) {
+		ac := send.(*gi.Action)
+		vv.SetValue(ac.Text)
+		vv.This().(ValueView).UpdateWidget()
+		if dlgRecv != nil && dlgFunc != nil {
+			dlgFunc(dlgRecv, send, int64(gi.DialogAccepted), data)
+		}
+	})
+}
+
 func (vv *ValueViewBase) SetValue(val any) bool {
 	if vv.This().(ValueView).IsInactive() {
 		return false
@@ -982,6 +1071,22 @@ func (vi *ViewIFace) PrefsDetView(prefs *gi.PrefsDetailed) {
 	PrefsDetView(prefs)
 }
 
+func (vi *ViewIFace) ColorSchemesView(prefs *gi.Preferences) {
+	ColorSchemesView(prefs)
+}
+
+func (vi *ViewIFace) LogConsole() {
+	LogConsoleView()
+}
+
+func (vi *ViewIFace) FindAll() {
+	FindAllView()
+}
+
+func (vi *ViewIFace) HelpView(uri string) {
+	HelpViewOpen(uri)
+}
+
 func (vi *ViewIFace) HiStylesView(std bool) {
 	if std {
 		HiStylesView(&histyle.StdStyles)
@@ -1044,6 +1149,41 @@ func IsVersCtrlSystem(str string) bool {
 	return false
 }
 
+var (
+	versCtrlInstalled   map[string]bool
+	versCtrlInstalledMu sync.Mutex
+)
+
+// VersCtrlInstalled returns true if the command for the given version
+// control system (e.g., "git") is installed, i.e., found on the PATH --
+// the result is cached after the first check.
+func VersCtrlInstalled(vc string) bool {
+	vcl := strings.ToLower(vc)
+	versCtrlInstalledMu.Lock()
+	defer versCtrlInstalledMu.Unlock()
+	if inst, has := versCtrlInstalled[vcl]; has {
+		return inst
+	}
+	if versCtrlInstalled == nil {
+		versCtrlInstalled = make(map[string]bool)
+	}
+	_, err := exec.LookPath(vcl)
+	inst := err == nil
+	versCtrlInstalled[vcl] = inst
+	return inst
+}
+
+// VersCtrlSystemsInstalled returns the VersCtrlSystems that are installed
+func VersCtrlSystemsInstalled() []string {
+	var vcs []string
+	for _, vc := range VersCtrlSystems {
+		if VersCtrlInstalled(vc) {
+			vcs = append(vcs, vc)
+		}
+	}
+	return vcs
+}
+
 // VersCtrlName is the name of a version control system
 type VersCtrlName string
 
@@ -1066,7 +1206,7 @@ func (kn VersCtrlName) ValueView() ValueView {
 }
 
 // VersCtrlValueView presents an action for displaying an VersCtrlName and selecting
-// from StringPopup
+// from a StringsChooser popup, where systems that are not installed are inactive
 type VersCtrlValueView struct {
 	ValueViewBase
 }
@@ -1084,8 +1224,16 @@ func (vv *VersCtrlValueView) UpdateWidget() {
 	}
 	ac := vv.Widget.(*gi.Action)
 	txt := kit.ToString(vv.Value.Interface())
-	if txt == "" {
+	switch {
+	case txt == "":
 		txt = "(none)"
+		ac.Tooltip = ""
+	case !IsVersCtrlSystem(txt):
+		ac.Tooltip = fmt.Sprintf("%v is not a supported version control system", txt)
+	case !VersCtrlInstalled(txt):
+		ac.Tooltip = fmt.Sprintf("%v is not installed -- the %v command was not found on the PATH", txt, txt)
+	default:
+		ac.Tooltip = ""
 	}
 	ac.SetText(txt)
 }
@@ -1110,19 +1258,7 @@ func (vv *VersCtrlValueView) Activate(vp *gi.Viewport2D, dlgRecv ki.Ki, dlgFunc
 	if vv.IsInactive() {
 		return
 	}
-	cur := kit.ToString(vv.Value.Interface())
-	var recv gi.Node2D
-	if vv.Widget != nil {
-		recv = vv.Widget
-	} else {
-		recv = vp.This().(gi.Node2D)
-	}
-	gi.StringsChooserPopup(VersCtrlSystems, cur, recv, func(recv, send ki.Ki, sig int64, data any) {
-		ac := send.(*gi.Action)
-		vv.SetValue(ac.Text)
-		vv.UpdateWidget()
-		if dlgRecv != nil && dlgFunc != nil {
-			dlgFunc(dlgRecv, send, int64(gi.DialogAccepted), data)
-		}
-	})
+	vv.StringsChooser(vp, VersCtrlSystems, func(idx int, str string) bool {
+		return !VersCtrlInstalled(str)
+	}, dlgRecv, dlgFunc)
 }
@@ -97,6 +97,12 @@ type App interface {
 	// FontPaths returns the default system font paths.
 	FontPaths() []string
 
+	// TextScale returns the scaling factor for the size of text set in the
+	// operating system, e.g., in its accessibility settings, or 1 if there
+	// is none.  On Linux this is the text scaling factor of the GNOME
+	// desktop, via the gsettings command, if available.
+	TextScale() float32
+
 	// About is an informative message about the app.  Can use HTML
 	// formatting, including links.
 	About() string
@@ -109,6 +115,11 @@ type App interface {
 	// xdg-open command.
 	OpenURL(url string)
 
+	// Beep plays the system alert sound (the bell).  On Linux this uses
+	// the bell of the desktop sound theme, via the canberra-gtk-play
+	// command, if available, and otherwise the terminal bell.
+	Beep()
+
 	// OpenFiles returns file names that have been set to be open at startup.
 	OpenFiles() []string
 
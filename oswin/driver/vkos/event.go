@@ -7,6 +7,7 @@ package vkos
 import (
 	"image"
 	"time"
+	"unicode"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/goki/gi/oswin"
@@ -24,6 +25,7 @@ var (
 	lastMouseAction    mouse.Actions
 	lastMods           int32
 	lastKey            key.Codes
+	lastKeyRepeat      bool
 )
 
 func glfwMods(mod glfw.ModifierKey) int32 {
@@ -47,6 +49,10 @@ func glfwMods(mod glfw.ModifierKey) int32 {
 func (w *windowImpl) keyEvent(gw *glfw.Window, ky glfw.Key, scancode int, action glfw.Action, mod glfw.ModifierKey) {
 	em := glfwMods(mod)
 	lastMods = em
+	lastKeyRepeat = action == glfw.Repeat
+	if lastKeyRepeat && !key.Repeat {
+		return
+	}
 	ec := glfwKeyCode(ky)
 	lastKey = ec
 	rn, mapped := key.CodeRuneMap[ec]
@@ -99,6 +105,9 @@ func (w *windowImpl) keyEvent(gw *glfw.Window, ky glfw.Key, scancode int, action
 
 // char input
 func (w *windowImpl) charEvent(gw *glfw.Window, char rune, mods glfw.ModifierKey) {
+	if lastKeyRepeat && !key.Repeat { // follows the repeat key event
+		return
+	}
 	em := glfwMods(mods)
 	act := key.Press
 	che := &key.ChordEvent{
@@ -121,6 +130,69 @@ func (w *windowImpl) charEvent(gw *glfw.Window, char rune, mods glfw.ModifierKey
 	}
 	fw.Send(che)
 	glfw.PostEmptyEvent()
+	if lastKey >= key.CodeA && lastKey <= key.CodeZ && !key.HasAnyModifierBits(em, key.Control, key.Alt, key.Meta) {
+		if lr, _ := key.CurLayout().CodeRune(lastKey); lr != unicode.ToUpper(char) { // layout may have changed
+			theApp.updateKeyLayout(false)
+		}
+	}
+}
+
+// glfwLayoutKeys are the printable keys of the main keyboard, whose
+// characters depend on the keyboard layout
+var glfwLayoutKeys = []glfw.Key{
+	glfw.KeyA, glfw.KeyB, glfw.KeyC, glfw.KeyD, glfw.KeyE, glfw.KeyF, glfw.KeyG, glfw.KeyH, glfw.KeyI,
+	glfw.KeyJ, glfw.KeyK, glfw.KeyL, glfw.KeyM, glfw.KeyN, glfw.KeyO, glfw.KeyP, glfw.KeyQ, glfw.KeyR,
+	glfw.KeyS, glfw.KeyT, glfw.KeyU, glfw.KeyV, glfw.KeyW, glfw.KeyX, glfw.KeyY, glfw.KeyZ,
+	glfw.KeyMinus, glfw.KeyEqual, glfw.KeyLeftBracket, glfw.KeyRightBracket, glfw.KeyBackslash,
+	glfw.KeySemicolon, glfw.KeyApostrophe, glfw.KeyGraveAccent, glfw.KeyComma, glfw.KeyPeriod, glfw.KeySlash,
+}
+
+// keyLayoutRunes returns the characters typed by the printable keys in
+// the current keyboard layout that differ from the US layout, for
+// key.Layout Runes -- must be called on the main thread
+func keyLayoutRunes() map[key.Codes]rune {
+	var lrs map[key.Codes]rune
+	for _, gk := range glfwLayoutKeys {
+		rs := []rune(glfw.GetKeyName(gk, 0))
+		if len(rs) != 1 {
+			continue
+		}
+		ec := glfwKeyCode(gk)
+		lr := unicode.ToUpper(rs[0])
+		if lr == key.CodeRuneMap[ec] {
+			continue
+		}
+		if lrs == nil {
+			lrs = make(map[key.Codes]rune)
+		}
+		lrs[ec] = lr
+	}
+	return lrs
+}
+
+// updateKeyLayout updates the current keyboard layout, and sends a
+// key.LayoutEvent to all windows if it has changed -- the name of the
+// layout is only looked up again if getName is true, or the keys have
+// changed -- must be called on the main thread
+func (app *appImpl) updateKeyLayout(getName bool) {
+	cur := key.CurLayout()
+	ly := &key.Layout{Name: cur.Name, Runes: keyLayoutRunes()}
+	if !getName && ly.Equals(cur) {
+		return
+	}
+	ly.Name = keyLayoutName()
+	if ly.Equals(cur) {
+		return
+	}
+	key.SetCurLayout(ly)
+	app.mu.Lock()
+	for _, w := range app.winlist {
+		ev := &key.LayoutEvent{Layout: ly}
+		ev.Init()
+		w.Send(ev)
+	}
+	app.mu.Unlock()
+	glfw.PostEmptyEvent()
 }
 
 func (w *windowImpl) mouseButtonEvent(gw *glfw.Window, button glfw.MouseButton, action glfw.Action, mod glfw.ModifierKey) {
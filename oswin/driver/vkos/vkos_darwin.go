@@ -79,10 +79,28 @@ func (app *appImpl) OpenURL(url string) {
 	cmd.Run()
 }
 
+func (app *appImpl) Beep() {
+	C.NSBeep()
+}
+
 func (app *appImpl) FontPaths() []string {
 	return []string{"/System/Library/Fonts", "/Library/Fonts"}
 }
 
+func (app *appImpl) TextScale() float32 {
+	return 1
+}
+
+// keyLayoutName returns the name of the current keyboard layout, e.g.,
+// French, from the input source of the HIToolbox preferences
+func keyLayoutName() string {
+	out, err := exec.Command("defaults", "read", "com.apple.HIToolbox", "AppleCurrentKeyboardLayoutInputSourceID").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "com.apple.keylayout.")
+}
+
 func (app *appImpl) PrefsDir() string {
 	usr, err := user.Current()
 	if err != nil {
@@ -11,7 +11,10 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"unsafe"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -32,10 +35,45 @@ func (app *appImpl) OpenURL(url string) {
 	cmd.Run()
 }
 
+var procMessageBeep = syscall.NewLazyDLL("user32.dll").NewProc("MessageBeep")
+
+func (app *appImpl) Beep() {
+	procMessageBeep.Call(0) // MB_OK: default system sound
+}
+
 func (app *appImpl) FontPaths() []string {
 	return []string{"C:\\Windows\\Fonts"}
 }
 
+func (app *appImpl) TextScale() float32 {
+	// the "Make text bigger" accessibility setting, in percent
+	out, err := exec.Command("reg", "query", `HKCU\Software\Microsoft\Accessibility`, "/v", "TextScaleFactor").Output()
+	if err != nil {
+		return 1
+	}
+	fs := strings.Fields(string(out))
+	if len(fs) == 0 {
+		return 1
+	}
+	pct, err := strconv.ParseInt(fs[len(fs)-1], 0, 64)
+	if err != nil || pct <= 0 {
+		return 1
+	}
+	return float32(pct) / 100
+}
+
+var procGetKeyboardLayoutName = syscall.NewLazyDLL("user32.dll").NewProc("GetKeyboardLayoutNameW")
+
+// keyLayoutName returns the name of the current keyboard layout, which is
+// its identifier, e.g., 0000040C for French
+func keyLayoutName() string {
+	buf := make([]uint16, 9) // KL_NAMELENGTH
+	if r, _, _ := procGetKeyboardLayoutName.Call(uintptr(unsafe.Pointer(&buf[0]))); r == 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
+
 func (app *appImpl) PrefsDir() string {
 	// todo: could use a more official windows protocol to get this stuff..
 	// https://msdn.microsoft.com/en-us/library/bb762188%28VS.85%29.aspx
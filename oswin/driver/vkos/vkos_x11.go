@@ -8,9 +8,12 @@ package vkos
 
 import (
 	"log"
+	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -43,10 +46,58 @@ func (app *appImpl) OpenURL(url string) {
 	cmd.Run()
 }
 
+func (app *appImpl) Beep() {
+	// glfw has no bell, so use the desktop sound theme
+	cmd := exec.Command("canberra-gtk-play", "--id=bell")
+	if err := cmd.Start(); err != nil {
+		os.Stdout.Write([]byte("\a"))
+		return
+	}
+	go cmd.Wait()
+}
+
 func (app *appImpl) FontPaths() []string {
 	return []string{"/usr/share/fonts/truetype"}
 }
 
+func (app *appImpl) TextScale() float32 {
+	out, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "text-scaling-factor").Output()
+	if err != nil {
+		return 1
+	}
+	sc, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 32)
+	if err != nil || sc <= 0 {
+		return 1
+	}
+	return float32(sc)
+}
+
+// keyLayoutName returns the name of the current keyboard layout, e.g.,
+// us or us(dvorak), from setxkbmap
+func keyLayoutName() string {
+	out, err := exec.Command("setxkbmap", "-query").Output()
+	if err != nil {
+		return ""
+	}
+	var layout, variant string
+	for _, ln := range strings.Split(string(out), "\n") {
+		fs := strings.Fields(ln)
+		if len(fs) != 2 {
+			continue
+		}
+		switch fs[0] {
+		case "layout:":
+			layout = fs[1]
+		case "variant:":
+			variant = fs[1]
+		}
+	}
+	if variant != "" {
+		return layout + "(" + variant + ")"
+	}
+	return layout
+}
+
 func (app *appImpl) PrefsDir() string {
 	usr, err := user.Current()
 	if err != nil {
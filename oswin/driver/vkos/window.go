@@ -565,6 +565,7 @@ func (w *windowImpl) focus(gw *glfw.Window, focused bool) {
 		}
 		bitflag.ClearAtomic(&w.Flag, int(oswin.Minimized))
 		bitflag.SetAtomic(&w.Flag, int(oswin.Focus))
+		theApp.updateKeyLayout(true)
 		w.sendWindowEvent(window.Focus)
 	} else {
 		// fmt.Printf("unfoc win: %v, foc: %v\n", w.Nm, bitflag.HasAtomic(&w.Flag, int(oswin.Focus)))
@@ -82,6 +82,11 @@ const (
 	// suitable for translation into keyboard commands, emacs-style etc
 	KeyChordEvent
 
+	// KeyLayoutEvent is sent to all windows when the keyboard layout has
+	// changed, e.g., from US to French AZERTY or Dvorak, so keymaps and
+	// the shortcuts shown in menus can be updated
+	KeyLayoutEvent
+
 	// TouchEvent is a generic touch-based event
 	TouchEvent
 
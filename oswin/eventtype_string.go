@@ -21,26 +21,27 @@ func _() {
 	_ = x[MouseHoverEvent-5]
 	_ = x[KeyEvent-6]
 	_ = x[KeyChordEvent-7]
-	_ = x[TouchEvent-8]
-	_ = x[MagnifyEvent-9]
-	_ = x[RotateEvent-10]
-	_ = x[WindowEvent-11]
-	_ = x[WindowResizeEvent-12]
-	_ = x[WindowPaintEvent-13]
-	_ = x[WindowShowEvent-14]
-	_ = x[WindowFocusEvent-15]
-	_ = x[DNDEvent-16]
-	_ = x[DNDMoveEvent-17]
-	_ = x[DNDFocusEvent-18]
-	_ = x[OSEvent-19]
-	_ = x[OSOpenFilesEvent-20]
-	_ = x[CustomEventType-21]
-	_ = x[EventTypeN-22]
+	_ = x[KeyLayoutEvent-8]
+	_ = x[TouchEvent-9]
+	_ = x[MagnifyEvent-10]
+	_ = x[RotateEvent-11]
+	_ = x[WindowEvent-12]
+	_ = x[WindowResizeEvent-13]
+	_ = x[WindowPaintEvent-14]
+	_ = x[WindowShowEvent-15]
+	_ = x[WindowFocusEvent-16]
+	_ = x[DNDEvent-17]
+	_ = x[DNDMoveEvent-18]
+	_ = x[DNDFocusEvent-19]
+	_ = x[OSEvent-20]
+	_ = x[OSOpenFilesEvent-21]
+	_ = x[CustomEventType-22]
+	_ = x[EventTypeN-23]
 }
 
-const _EventType_name = "MouseEventMouseMoveEventMouseDragEventMouseScrollEventMouseFocusEventMouseHoverEventKeyEventKeyChordEventTouchEventMagnifyEventRotateEventWindowEventWindowResizeEventWindowPaintEventWindowShowEventWindowFocusEventDNDEventDNDMoveEventDNDFocusEventOSEventOSOpenFilesEventCustomEventTypeEventTypeN"
+const _EventType_name = "MouseEventMouseMoveEventMouseDragEventMouseScrollEventMouseFocusEventMouseHoverEventKeyEventKeyChordEventKeyLayoutEventTouchEventMagnifyEventRotateEventWindowEventWindowResizeEventWindowPaintEventWindowShowEventWindowFocusEventDNDEventDNDMoveEventDNDFocusEventOSEventOSOpenFilesEventCustomEventTypeEventTypeN"
 
-var _EventType_index = [...]uint16{0, 10, 24, 38, 54, 69, 84, 92, 105, 115, 127, 138, 149, 166, 182, 197, 213, 221, 233, 246, 253, 269, 284, 294}
+var _EventType_index = [...]uint16{0, 10, 24, 38, 54, 69, 84, 92, 105, 119, 129, 141, 152, 163, 180, 196, 211, 227, 235, 247, 260, 267, 283, 298, 308}
 
 func (i EventType) String() string {
 	if i < 0 || i >= EventType(len(_EventType_index)-1) {
@@ -162,9 +162,25 @@ func (ch Chord) Decode() (r rune, mods int32, err error) {
 	return
 }
 
-// Shortcut transforms chord string into short form suitable for display to users
+// Shortcut transforms chord string into short form suitable for display to
+// users, with the character of the key in the current keyboard layout (see
+// LayoutShortcut)
 func (ch Chord) Shortcut() string {
-	cs := strings.Replace(string(ch), "Control+", "^", -1) // ⌃ doesn't look as good
+	return ch.LayoutShortcut(CurLayout())
+}
+
+// LayoutShortcut transforms chord string into short form suitable for
+// display to users, with the character typed by the key in given keyboard
+// layout -- the chords of modified keys are for the key positions of the
+// standard US layout, e.g., Control+Z is shown as ^W in French AZERTY.
+func (ch Chord) LayoutShortcut(ly *Layout) string {
+	cs := string(ch)
+	if mods, kc := ModsFmString(cs); mods != 0 {
+		if rs := []rune(kc); len(rs) == 1 {
+			cs = ModsString(mods) + string(ly.KeyRune(rs[0]))
+		}
+	}
+	cs = strings.Replace(cs, "Control+", "^", -1) // ⌃ doesn't look as good
 	switch oswin.TheApp.Platform() {
 	case oswin.MacOS:
 		cs = strings.Replace(cs, "Shift+", "⇧", -1)
@@ -200,6 +216,11 @@ func CodeIsModifier(c Codes) bool {
 	return false
 }
 
+// Repeat is whether holding down a key repeats it, by sending repeated
+// Press events -- if false, only the first press is sent.
+// This is also in gi.Prefs and updated from there
+var Repeat = true
+
 // Actions is the action taken on the key
 type Actions int32
 
@@ -0,0 +1,135 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package key
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"unicode"
+
+	"github.com/goki/gi/oswin"
+)
+
+// Layout is a keyboard layout, e.g., US, French AZERTY or Dvorak, which
+// determines the characters typed by the physical keys.  Key Codes are
+// named for the keys of the standard US layout, and so are the chords of
+// keys pressed with Control, Meta or Alt (e.g., Control+Z is the key
+// typing Z on a US keyboard, which types W on a French one, wherever the
+// Z key is), so keymaps work by key position -- the Layout is used to
+// show the characters of the keys actually pressed, e.g., in menus.
+type Layout struct {
+	Name  string         `desc:"name of the layout as given by the OS, e.g., us, fr or us(dvorak) -- empty if not known"`
+	Runes map[Codes]rune `desc:"upper-case character typed by each printable key of the main keyboard (not the keypad) whose character differs from that in the standard US layout (CodeRuneMap) -- nil for the US layout"`
+}
+
+// USLayout is the standard US keyboard layout, which is the current
+// layout until the oswin driver sets it
+var USLayout = &Layout{Name: "us"}
+
+var (
+	curLayout   = USLayout
+	curLayoutMu sync.Mutex
+)
+
+// CurLayout returns the current keyboard layout
+func CurLayout() *Layout {
+	curLayoutMu.Lock()
+	defer curLayoutMu.Unlock()
+	return curLayout
+}
+
+// SetCurLayout sets the current keyboard layout -- called by the oswin
+// driver, which then sends a LayoutEvent to all windows
+func SetCurLayout(ly *Layout) {
+	curLayoutMu.Lock()
+	curLayout = ly
+	curLayoutMu.Unlock()
+}
+
+// CodeRune returns the upper-case character typed by given key in this
+// layout, and false if it is not a printable key
+func (ly *Layout) CodeRune(c Codes) (rune, bool) {
+	if ly != nil {
+		if r, ok := ly.Runes[c]; ok {
+			return r, true
+		}
+	}
+	r, ok := CodeRuneMap[c]
+	return r, ok
+}
+
+// KeyRune returns the upper-case character typed in this layout by the
+// key that types given character in the standard US layout, as in the
+// chords of modified keys -- digits are kept as is, as they are also
+// printed on their keys in all layouts (e.g., 1 and & in AZERTY)
+func (ly *Layout) KeyRune(r rune) rune {
+	ur := unicode.ToUpper(r)
+	if ly == nil || len(ly.Runes) == 0 || unicode.IsDigit(ur) {
+		return ur
+	}
+	for c, lr := range ly.Runes {
+		if CodeRuneMap[c] == ur && c < CodeKeypadSlash {
+			return lr
+		}
+	}
+	return ur
+}
+
+// Equals returns true if this layout is the same as given one
+func (ly *Layout) Equals(ol *Layout) bool {
+	if ly == nil || ol == nil {
+		return ly == ol
+	}
+	if ly.Name != ol.Name || len(ly.Runes) != len(ol.Runes) {
+		return false
+	}
+	for c, r := range ly.Runes {
+		if or, ok := ol.Runes[c]; !ok || or != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (ly *Layout) String() string {
+	if ly.Name == "" {
+		return "unknown"
+	}
+	return ly.Name
+}
+
+// key.LayoutEvent is sent to all windows when the keyboard layout has
+// changed, with the new current layout (also given by CurLayout), so
+// that keymaps, and the shortcuts shown in menus, can be updated
+type LayoutEvent struct {
+	oswin.EventBase
+
+	// Layout is the new keyboard layout
+	Layout *Layout
+}
+
+func (ev *LayoutEvent) Type() oswin.EventType {
+	return oswin.KeyLayoutEvent
+}
+
+func (ev *LayoutEvent) HasPos() bool {
+	return false
+}
+
+func (ev *LayoutEvent) Pos() image.Point {
+	return image.ZP
+}
+
+func (ev *LayoutEvent) OnFocus() bool {
+	return false
+}
+
+func (ev *LayoutEvent) String() string {
+	return fmt.Sprintf("Type: %v  Layout: %v  Time: %v", ev.Type(), ev.Layout, ev.Time())
+}
+
+// check for interface implementation
+var _ oswin.Event = &LayoutEvent{}
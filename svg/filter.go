@@ -5,9 +5,17 @@
 package svg
 
 import (
+	"image/color"
+	"log"
+	"strconv"
+	"strings"
+
 	"github.com/goki/gi/gi"
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
+	"github.com/goki/mat32"
 )
 
 // Filter represents SVG filter* elements
@@ -30,3 +38,101 @@ func (g *Filter) CopyFieldsFrom(frm any) {
 	g.NodeBase.CopyFieldsFrom(&fr.NodeBase)
 	g.FilterType = fr.FilterType
 }
+
+// FilterEffects returns the filter effects for the fe* filter primitive
+// children of this filter element, with lengths in user units multiplied
+// by given scale to convert them to dots.  The primitives are applied in
+// sequence -- feGaussianBlur, feDropShadow and feColorMatrix are supported.
+func (g *Filter) FilterEffects(scale float32) girl.Filters {
+	var fs girl.Filters
+	for _, kid := range g.Kids {
+		fe, ok := kid.(*Filter)
+		if !ok {
+			continue
+		}
+		switch fe.FilterType {
+		case "feGaussianBlur":
+			sd := filterFloats(fe.Prop("stdDeviation"))
+			if len(sd) > 0 {
+				fs = append(fs, girl.BlurFilter{StdDev: sd[0] * scale})
+			}
+		case "feDropShadow":
+			df := girl.DropShadowFilter{Offset: mat32.Vec2{2, 2}.MulScalar(scale), StdDev: 2 * scale}
+			if v := filterFloats(fe.Prop("dx")); len(v) > 0 {
+				df.Offset.X = v[0] * scale
+			}
+			if v := filterFloats(fe.Prop("dy")); len(v) > 0 {
+				df.Offset.Y = v[0] * scale
+			}
+			if v := filterFloats(fe.Prop("stdDeviation")); len(v) > 0 {
+				df.StdDev = v[0] * scale
+			}
+			clr := gist.Color{0, 0, 0, 255}
+			if cs, ok := fe.Prop("flood-color").(string); ok {
+				clr.SetString(cs, nil)
+			}
+			if v := filterFloats(fe.Prop("flood-opacity")); len(v) > 0 {
+				clr.A = uint8(mat32.Clamp(v[0], 0, 1) * float32(clr.A))
+			}
+			df.Color = color.RGBAModel.Convert(clr).(color.RGBA)
+			fs = append(fs, df)
+		case "feColorMatrix":
+			vals := filterFloats(fe.Prop("values"))
+			switch kit.ToString(fe.Prop("type")) {
+			case "saturate":
+				if len(vals) > 0 {
+					fs = append(fs, girl.SaturateFilter(vals[0]))
+				}
+			case "", "matrix":
+				if len(vals) == 20 {
+					cf := girl.ColorMatrixFilter{}
+					copy(cf.Matrix[:], vals)
+					fs = append(fs, cf)
+				}
+			}
+		}
+	}
+	return fs
+}
+
+// filterFloats returns the numbers in given filter attribute value
+func filterFloats(val any) []float32 {
+	if val == nil {
+		return nil
+	}
+	var fv []float32
+	for _, f := range strings.Fields(strings.ReplaceAll(kit.ToString(val), ",", " ")) {
+		if v, err := strconv.ParseFloat(f, 32); err == nil {
+			fv = append(fv, float32(v))
+		}
+	}
+	return fv
+}
+
+// FilterEffects returns the filter effects for the filter property of this
+// node, which is either a url(#id) reference to a filter element, or CSS
+// filter functions (see girl.ParseFilters), or nil if none
+func (g *NodeBase) FilterEffects() girl.Filters {
+	fp, ok := g.Prop("filter").(string)
+	if !ok || fp == "" || fp == "none" {
+		return nil
+	}
+	if !strings.HasPrefix(fp, "url(") {
+		fs, err := girl.ParseFilters(fp, &g.Pnt.UnContext)
+		if err != nil {
+			log.Printf("svg.FilterEffects: %v: %v\n", g.Path(), err)
+		}
+		return fs
+	}
+	fn := NodeFindURL(g.This().(gi.Node2D), NameFromURL(fp))
+	flt, ok := fn.(*Filter)
+	if !ok || flt == nil {
+		return nil
+	}
+	scale := float32(1)
+	if rs := g.Render(); rs != nil {
+		scx, scy := rs.XForm.ExtractScale()
+		scale = 0.5 * (mat32.Abs(scx) + mat32.Abs(scy))
+	}
+	return flt.FilterEffects(scale)
+}
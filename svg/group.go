@@ -8,6 +8,8 @@ import (
 	"image"
 
 	"github.com/goki/gi/gi"
+	"github.com/goki/gi/girl"
+	"github.com/goki/gi/gist"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
 	"github.com/goki/mat32"
@@ -17,6 +19,7 @@ import (
 // locus for properties etc
 type Group struct {
 	NodeBase
+	FilterCache girl.FilterCache `copy:"-" view:"-" json:"-" xml:"-" desc:"cache of the filtered rendering of the group, if it has a filter, so the filter is only recomputed when the rendering changes"`
 }
 
 var KiT_Group = kit.Types.AddType(&Group{}, ki.Props{"EnumType:Flag": gi.KiT_NodeFlags})
@@ -67,7 +70,19 @@ func (g *Group) Render2D() {
 	}
 	rs.PushXFormLock(pc.XForm)
 
+	fs := g.FilterEffects()
+	layer := len(fs) > 0 || pc.FontStyle.Opacity < 1 || pc.Blend != gist.BlendNormal
+	if layer { // children are filtered and composited as a whole
+		rs.Lock()
+		rs.PushFilterLayer(rs.Bounds, fs, &g.FilterCache)
+		rs.Unlock()
+	}
 	g.Render2DChildren()
+	if layer {
+		rs.Lock()
+		rs.PopLayer(pc.FontStyle.Opacity, pc.Blend)
+		rs.Unlock()
+	}
 	g.ComputeBBoxSVG() // must come after render
 
 	rs.PopXFormLock()
@@ -10,14 +10,17 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gist"
 	"github.com/goki/gi/icons"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
@@ -43,9 +46,10 @@ func init() {
 // original source icon and then can be customized from there.
 type Icon struct {
 	SVG
-	Filename string      `desc:"file name with full path for icon if loaded from file"`
-	Rendered bool        `copy:"-" json:"-" xml:"-" desc:"we have already rendered at RenderedSize -- doesn't re-render at same size -- if the paint params change, set this to false to re-render"`
-	RendSize image.Point `copy:"-" json:"-" xml:"-" desc:"size at which we previously rendered"`
+	Filename string        `desc:"file name with full path for icon if loaded from file"`
+	Rendered bool          `copy:"-" json:"-" xml:"-" desc:"we have already rendered at RenderedSize -- doesn't re-render at same size -- if the paint params change, set this to false to re-render"`
+	RendSize image.Point   `copy:"-" json:"-" xml:"-" desc:"size at which we previously rendered"`
+	RendTint gi.TintParams `copy:"-" json:"-" xml:"-" desc:"tint parameters with which we previously rendered"`
 }
 
 var KiT_Icon = kit.Types.AddType(&Icon{}, IconProps)
@@ -108,6 +112,9 @@ func (ic *Icon) NeedsReRender() bool {
 	if ic.NeedsFullReRender() || !ic.Rendered || ic.RendSize != ic.Geom.Size {
 		return true
 	}
+	if ic.RendTint != gi.TintParamsFor(&ic.Node2DBase) {
+		return true
+	}
 	return false
 }
 
@@ -117,7 +124,16 @@ func (ic *Icon) Render2D() {
 		return
 	}
 	if ic.NeedsReRender() {
-		if ic.PushBounds() {
+		key := ic.CacheKey()
+		if img, ok := gi.IconCache.Get(key); ok && key != "" {
+			ic.BBoxMu.RLock()
+			sz := ic.Geom.Size
+			ic.BBoxMu.RUnlock()
+			draw.Draw(ic.Pixels, image.Rectangle{Max: sz}, img, img.Bounds().Min, draw.Src)
+			ic.RendTint = gi.TintParamsFor(&ic.Node2DBase)
+			ic.Rendered = true
+			ic.RendSize = sz
+		} else if ic.PushBounds() {
 			rs := &ic.Render
 			if ic.Fill {
 				ic.FillViewport()
@@ -126,14 +142,48 @@ func (ic *Icon) Render2D() {
 			rs.PushXFormLock(ic.Pnt.XForm)
 			ic.Render2DChildren() // we must do children first, then us!
 			rs.PopXFormLock()
+			ic.RendTint = gi.TintParamsFor(&ic.Node2DBase)
+			if !ic.RendTint.IsNil() {
+				gi.TintImage(ic.Pixels, ic.Pixels.Bounds(), ic.RendTint)
+			}
 			ic.Rendered = true
 			ic.RendSize = ic.Geom.Size
+			if key != "" {
+				gi.IconCache.Add(key, ic.Pixels, image.Rectangle{Max: ic.Geom.Size})
+			}
 			ic.PopBounds()
 		}
 	}
 	ic.RenderViewport2D() // update our parent image
 }
 
+// CacheKey returns the key for the rendering of this icon in gi.IconCache,
+// from the name of the gi.Icon it is in, its size and the paint parameters
+// that it is rendered with -- returns "" if it is not cached there, e.g.,
+// for gradient colors
+func (ic *Icon) CacheKey() string {
+	if !gi.IconCacheOn || ic.Par == nil {
+		return ""
+	}
+	gic, ok := ic.Par.(*gi.Icon)
+	if !ok || gic.IconNm == "" {
+		return ""
+	}
+	pc := &ic.Pnt
+	if pc.FillStyle.Color.Source != gist.SolidColor || pc.StrokeStyle.Color.Source != gist.SolidColor {
+		return ""
+	}
+	ic.StyMu.RLock()
+	bg := ic.Sty.Font.BgColor
+	ic.StyMu.RUnlock()
+	if bg.Source != gist.SolidColor {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%v:%v:%v:%v:%v:%g:%g:%v", gic.IconNm, gi.IconsGen, ic.Geom.Size, bg.Color,
+		pc.FillStyle.Color.Color, pc.FillStyle.Opacity, pc.StrokeStyle.Color.Color, pc.StrokeStyle.Opacity,
+		pc.StrokeStyle.Width.Dots, gi.TintParamsFor(&ic.Node2DBase))
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////
 // IconMgr
 
@@ -149,13 +199,20 @@ func (im *IconMgr) IsValid(iconName string) bool {
 	if gi.IconName(iconName).IsNil() {
 		return false
 	}
-	if _, ok := CurIconSet[iconName]; ok {
-		return true
-	}
-	if _, ok := DefaultIconSet[iconName]; ok {
-		return true
+	_, ok := lookupIcon(iconName)
+	return ok
+}
+
+// lookupIcon returns the icon of given name in CurIconSet, falling back
+// to DefaultIconSet, and false if not found in either
+func lookupIcon(name string) (*Icon, bool) {
+	IconSetsMu.RLock()
+	ic, ok := CurIconSet[name]
+	IconSetsMu.RUnlock()
+	if !ok {
+		ic, ok = DefaultIconSet[name]
 	}
-	return false
+	return ic, ok
 }
 
 // IconByName is main function to get icon by name -- looks in CurIconSet and
@@ -165,19 +222,40 @@ func (im *IconMgr) IconByName(name string) (ki.Ki, error) {
 	if gi.IconName(name).IsNil() {
 		return nil, nil
 	}
-	if !im.IsValid(name) {
+	ic, ok := lookupIcon(name)
+	if !ok {
 		err := fmt.Errorf("svg.IconMgr.IconByName -- icon name not found in CurIconSet or DefaultIconSet: %v\n", name)
 		return nil, err
 	}
-	ic, ok := CurIconSet[name]
-	if !ok {
-		ic = DefaultIconSet[name] // guaranteed above to exist
-	}
+	im.openIcon(ic)
+	return ic.This(), nil
+}
+
+// iconOpenMu protects the opening of icons, which can happen concurrently
+// with PreloadIcons
+var iconOpenMu sync.Mutex
+
+// openIcon opens the SVG file of given icon if it has not been opened yet
+// and IconAutoOpen is set
+func (im *IconMgr) openIcon(ic *Icon) {
+	iconOpenMu.Lock()
+	defer iconOpenMu.Unlock()
 	if ic.Filename != "" && !ic.HasChildren() && IconAutoOpen && ic.Filename != "blank.svg" {
 		ic.OpenXML(gi.FileName(ic.Filename))
 		ki.UniquifyNamesAll(ic.This())
 	}
-	return ic.This(), nil
+}
+
+// PreloadIcons opens the SVG files of all the icons in CurIconSet, which
+// are otherwise opened on demand when first used -- gi calls this in a
+// background goroutine at startup (see gi.PreloadOn), so that the first
+// render of each window does not have to parse all of its icons.
+func (im *IconMgr) PreloadIcons() {
+	for _, nm := range im.IconList(false) {
+		if ic, ok := lookupIcon(string(nm)); ok {
+			im.openIcon(ic)
+		}
+	}
 }
 
 // SetIcon sets the icon by name into given Icon wrapper, returning error
@@ -207,6 +285,8 @@ func (im *IconMgr) SetIcon(ic *gi.Icon, iconName string) error {
 // IconList returns the current list of all available icons,
 // optionally sorted in alphabetical order.
 func (im *IconMgr) IconList(alphaSort bool) []gi.IconName {
+	IconSetsMu.RLock()
+	defer IconSetsMu.RUnlock()
 	return CurIconSet.IconList(alphaSort)
 }
 
@@ -220,7 +300,7 @@ type IconSet map[string]*Icon
 var DefaultIconSet IconSet
 
 // CurIconSet is the current icon set -- defaults to default but can be
-// changed to whatever you want
+// changed to whatever you want -- access must be protected by IconSetsMu
 var CurIconSet IconSet
 
 // OpenIconsFromPath scans for .svg icon files in given path, adding them to
@@ -315,8 +395,8 @@ func (iset *IconSet) IconList(alphaSort bool) []gi.IconName {
 	il := make([]gi.IconName, len(*iset)+1)
 	il[0] = gi.IconName("none")
 	idx := 1
-	for _, ic := range *iset {
-		il[idx] = gi.IconName(ic.Nm)
+	for nm := range *iset { // names can include a namespace, unlike ic.Nm
+		il[idx] = gi.IconName(nm)
 		idx++
 	}
 	if alphaSort {
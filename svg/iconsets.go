@@ -0,0 +1,166 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+// Registered icon sets
+
+// IconSets are the additional icon sets registered at runtime via
+// AddIconSet, AddIconsFromDir, or AddIconsFromFS, keyed by namespace.
+// The icons in each set are available in CurIconSet as "namespace/name",
+// e.g., "myapp/logo", so they never conflict with the default icons or
+// those of other sets.  Access must be protected by IconSetsMu.
+var IconSets = map[string]IconSet{}
+
+// IconSetsMu is a mutex protecting IconSets and CurIconSet, which can be
+// updated from any goroutine by AddIconSet and RemoveIconSet, while icons
+// are looked up by the render and preload goroutines
+var IconSetsMu sync.RWMutex
+
+// AddIconSet registers given icon set under given namespace, adding its
+// icons to CurIconSet as "namespace/name" -- replaces any existing set
+// registered under the same namespace.  Calls gi.IconsChanged so that
+// icons already in use are reloaded and the new icons are available in
+// the IconChooserDialog.
+func AddIconSet(ns string, iset IconSet) error {
+	if ns == "" || strings.Contains(ns, "/") {
+		return fmt.Errorf("svg.AddIconSet: namespace must be non-empty and not contain a '/': %q", ns)
+	}
+	IconSetsMu.Lock()
+	removeIconSet(ns)
+	IconSets[ns] = iset
+	for nm, ic := range iset {
+		CurIconSet[ns+"/"+nm] = ic
+	}
+	IconSetsMu.Unlock()
+	gi.IconsChanged()
+	return nil
+}
+
+// AddIconsFromDir registers all the .svg icon files in given directory
+// (including subdirectories, as for OpenIconsFromPath) as an icon set
+// under given namespace -- see AddIconSet.  The files are only loaded
+// when each icon is first used.
+func AddIconsFromDir(ns, dir string) error {
+	iset := make(IconSet)
+	if err := iset.OpenIconsFromPath(dir); err != nil {
+		return err
+	}
+	return AddIconSet(ns, iset)
+}
+
+// AddIconsFromFS registers all the .svg icon files in given directory of
+// given file system (e.g., an embed.FS), including subdirectories, as an
+// icon set under given namespace -- see AddIconSet.
+func AddIconsFromFS(ns string, fsys fs.FS, dir string) error {
+	iset := make(IconSet)
+	if err := iset.OpenIconsFromFS(fsys, dir); err != nil {
+		return err
+	}
+	return AddIconSet(ns, iset)
+}
+
+// RemoveIconSet removes the icon set registered under given namespace,
+// and calls gi.IconsChanged
+func RemoveIconSet(ns string) {
+	IconSetsMu.Lock()
+	had := removeIconSet(ns)
+	IconSetsMu.Unlock()
+	if had {
+		gi.IconsChanged()
+	}
+}
+
+// removeIconSet removes the icon set of given namespace from IconSets and
+// CurIconSet, returning true if it was registered -- IconSetsMu must be
+// locked
+func removeIconSet(ns string) bool {
+	iset, has := IconSets[ns]
+	if !has {
+		return false
+	}
+	for nm := range iset {
+		delete(CurIconSet, ns+"/"+nm)
+	}
+	delete(IconSets, ns)
+	return true
+}
+
+// IconSetNames returns the sorted list of namespaces of the registered
+// icon sets
+func IconSetNames() []string {
+	IconSetsMu.RLock()
+	defer IconSetsMu.RUnlock()
+	nms := make([]string, 0, len(IconSets))
+	for ns := range IconSets {
+		nms = append(nms, ns)
+	}
+	sort.Strings(nms)
+	return nms
+}
+
+// OpenIconsFromFS loads all the .svg icon files in given directory of given
+// file system (e.g., an embed.FS), including subdirectories, into the icon
+// set -- icons in subdirectories are named with the subdirectory path as a
+// prefix, separated by "-", as in OpenIconsFromPath.
+func (iset *IconSet) OpenIconsFromFS(fsys fs.FS, dir string) error {
+	ext := ".svg"
+	if dir == "" {
+		dir = "."
+	}
+	var lasterr error
+	err := fs.WalkDir(fsys, dir, func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			log.Printf("svg.IconSet: error accessing path %q: %v\n", p, err)
+			return err
+		}
+		if de.IsDir() || path.Ext(p) != ext {
+			return nil
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, dir), "/")
+		if dir == "." {
+			rel = p
+		}
+		nm := strings.ToLower(strings.ReplaceAll(strings.TrimSuffix(rel, ext), "/", "-"))
+		ic := Icon{}
+		ic.InitName(&ic, nm)
+		ic.Filename = path.Base(p)
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			lasterr = err
+			log.Println(err)
+			return nil
+		}
+		err = ic.ReadXML(bytes.NewBuffer(b))
+		if err != nil && err != io.EOF {
+			lasterr = err
+			log.Println(err)
+			return nil
+		}
+		ki.UniquifyNamesAll(ic.This())
+		(*iset)[nm] = &ic
+		return nil
+	})
+	if err != nil {
+		log.Printf("svg.IconSet: error walking the path %q: %v\n", dir, err)
+		return err
+	}
+	return lasterr
+}
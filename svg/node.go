@@ -310,6 +310,10 @@ func StyleSVG(gii gi.Node2D) {
 	pp := g.ParentPaint()
 	if pp != nil {
 		pc.CopyStyleFrom(pp)
+		pc.Blend = gist.BlendNormal // not inherited
+		if _, isgp := g.Par.(*Group); isgp {
+			pc.FontStyle.Opacity = 1 // group opacity is applied by compositing
+		}
 		pc.SetStyleProps(pp, *gii.Properties(), ctxt)
 	} else {
 		pc.SetStyleProps(nil, *gii.Properties(), ctxt)
@@ -0,0 +1,462 @@
+// Copyright (c) 2023, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//   Scene coordinates and transforms
+
+// Scene coordinates are the user coordinates of the SVG drawing (i.e., of
+// its ViewBox), before the SVG's own normalizing transform and any
+// ZoomXForm are applied to map them into the window.
+
+// SceneXForm returns the full compounded 2D transform matrix from the
+// local coordinates of this node to scene coordinates, for all of the
+// parents of this node, and our own xform too if self is true.
+// Unlike ParXForm, this does not include the transform of the SVG itself.
+func (g *NodeBase) SceneXForm(self bool) mat32.Mat2 {
+	xf := mat32.Identity2D()
+	if self {
+		xf = g.Pnt.XForm
+	}
+	nb := g
+	for nb.Par != nil && !ki.TypeEmbeds(nb.Par, KiT_SVG) {
+		psvg, ok := nb.Par.(NodeSVG)
+		if !ok {
+			break
+		}
+		nb = psvg.AsSVGNode()
+		xf = xf.Mul(nb.Pnt.XForm)
+	}
+	return xf
+}
+
+// SceneBBox returns the bounding box of the node in scene coordinates
+func (g *NodeBase) SceneBBox() mat32.Box2 {
+	return g.This().(NodeSVG).SVGLocalBBox().MulMat2(g.SceneXForm(true))
+}
+
+// SetXForm sets the transform of this node to given matrix, updating the
+// "transform" property so that it persists through styling and saving
+func (g *NodeBase) SetXForm(xf mat32.Mat2) {
+	g.Pnt.XForm = xf
+	g.SetProp("transform", xf.String())
+}
+
+// ComposeXForm composes given transform, in the coordinates of our parent,
+// onto the current transform of this node, i.e., it is applied after the
+// current transform.  Unlike ApplyXForm, the geometry of the node is not
+// changed -- only its "transform" property.
+func (g *NodeBase) ComposeXForm(xf mat32.Mat2) {
+	g.SetXForm(g.Pnt.XForm.Mul(xf))
+}
+
+// ComposeSceneXForm composes given transform, in scene coordinates,
+// onto the current transform of this node, converting it into the
+// coordinates of our parent, e.g., for moving, scaling or rotating a
+// selection of nodes in different groups by the same amount.
+func (g *NodeBase) ComposeSceneXForm(xf mat32.Mat2) {
+	pxf := g.SceneXForm(false)
+	g.ComposeXForm(pxf.Mul(xf).Mul(pxf.Inverse()))
+}
+
+// SceneToWinXForm returns the transform from scene coordinates to window
+// coordinates, as of the last render
+func (sv *SVG) SceneToWinXForm() mat32.Mat2 {
+	xf := sv.Pnt.XForm
+	if sv.ZoomXForm != (mat32.Mat2{}) {
+		xf = xf.Mul(sv.ZoomXForm)
+	}
+	sv.BBoxMu.RLock()
+	wmin := mat32.NewVec2FmPoint(sv.WinBBox.Min)
+	sv.BBoxMu.RUnlock()
+	return xf.Mul(mat32.Translate2D(wmin.X, wmin.Y))
+}
+
+// WinToScene returns the scene coordinates of given window position,
+// e.g., from a mouse event
+func (sv *SVG) WinToScene(pt image.Point) mat32.Vec2 {
+	return sv.SceneToWinXForm().Inverse().MulVec2AsPt(mat32.NewVec2FmPoint(pt))
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//   Hit-testing
+
+// NodesAtPoint returns all the rendering (non-group) nodes whose window
+// bounding box as of the last render contains given window position,
+// in rendering order, i.e., from the bottom to the top.  Defs are not
+// included.
+func (sv *SVG) NodesAtPoint(pt image.Point) []NodeSVG {
+	var nodes []NodeSVG
+	sv.FuncDownMeFirst(0, sv.This(), func(k ki.Ki, level int, d any) bool {
+		if k == sv.This() {
+			return ki.Continue
+		}
+		sn, ok := k.(NodeSVG)
+		if !ok {
+			return ki.Break // not an svg node: skip subtree
+		}
+		if _, isgp := k.(*Group); isgp {
+			return ki.Continue
+		}
+		if sn.AsNode2D().PosInWinBBox(pt) {
+			nodes = append(nodes, sn)
+		}
+		return ki.Continue
+	})
+	return nodes
+}
+
+// NodeAtPoint returns the topmost rendering node whose window bounding box
+// contains given window position, or nil if none -- see NodesAtPoint
+func (sv *SVG) NodeAtPoint(pt image.Point) NodeSVG {
+	nodes := sv.NodesAtPoint(pt)
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[len(nodes)-1]
+}
+
+// TopLevelNode returns the ancestor of given node that is a direct child of
+// the SVG (which may be the node itself), e.g., for selecting whole groups
+// when clicking on one of their elements.  Returns nil if the node is not
+// within this SVG.
+func (sv *SVG) TopLevelNode(n NodeSVG) NodeSVG {
+	var k ki.Ki = n
+	for k != nil {
+		par := k.Parent()
+		if par == sv.This() {
+			return k.(NodeSVG)
+		}
+		k = par
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//   Selection
+
+// SelectionSpriteName is the prefix of the names of the sprites used for
+// drawing the selection box and handles -- only one SVG selection can be
+// shown in a window at a time.
+var SelectionSpriteName = "svg.Selection"
+
+// SelectionHandleSize is the size in dots of the selection handles
+var SelectionHandleSize = 8
+
+// SelHandles are the handles drawn around a Selection, for resizing it
+type SelHandles int32
+
+const (
+	SelHandleTopLeft SelHandles = iota
+	SelHandleTop
+	SelHandleTopRight
+	SelHandleRight
+	SelHandleBottomRight
+	SelHandleBottom
+	SelHandleBottomLeft
+	SelHandleLeft
+
+	SelHandlesN
+)
+
+//go:generate stringer -type=SelHandles
+
+// Selection is a set of selected nodes in an SVG, which is shown in the
+// window overlay as a box around the selected nodes with handles at its
+// corners and edges -- see SVG.Select and related methods, which update
+// the display.
+type Selection struct {
+	Nodes []NodeSVG `desc:"the selected nodes, in the order they were selected"`
+	Shown bool      `desc:"the selection sprites are currently shown"`
+}
+
+// Len returns the number of selected nodes
+func (sl *Selection) Len() int {
+	return len(sl.Nodes)
+}
+
+// Index returns the index of given node in the selection, or -1 if not selected
+func (sl *Selection) Index(n NodeSVG) int {
+	for i, sn := range sl.Nodes {
+		if sn == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// Has returns true if given node is selected
+func (sl *Selection) Has(n NodeSVG) bool {
+	return sl.Index(n) >= 0
+}
+
+// Add adds given nodes to the selection, if not already selected
+func (sl *Selection) Add(nodes ...NodeSVG) {
+	for _, n := range nodes {
+		if n != nil && !sl.Has(n) {
+			sl.Nodes = append(sl.Nodes, n)
+		}
+	}
+}
+
+// Remove removes given nodes from the selection
+func (sl *Selection) Remove(nodes ...NodeSVG) {
+	for _, n := range nodes {
+		if i := sl.Index(n); i >= 0 {
+			sl.Nodes = append(sl.Nodes[:i], sl.Nodes[i+1:]...)
+		}
+	}
+}
+
+// Toggle selects given node if it is not selected, and unselects it otherwise
+func (sl *Selection) Toggle(n NodeSVG) {
+	if sl.Has(n) {
+		sl.Remove(n)
+	} else {
+		sl.Add(n)
+	}
+}
+
+// Set replaces the selection with given nodes
+func (sl *Selection) Set(nodes ...NodeSVG) {
+	sl.Nodes = nil
+	sl.Add(nodes...)
+}
+
+// Clear unselects all nodes
+func (sl *Selection) Clear() {
+	sl.Nodes = nil
+}
+
+// Prune removes any selected nodes that have been deleted
+func (sl *Selection) Prune() {
+	for i := len(sl.Nodes) - 1; i >= 0; i-- {
+		n := sl.Nodes[i]
+		if n.This() == nil || n.IsDeleted() || n.IsDestroyed() {
+			sl.Nodes = append(sl.Nodes[:i], sl.Nodes[i+1:]...)
+		}
+	}
+}
+
+// SceneBBox returns the union of the bounding boxes of the selected nodes,
+// in scene coordinates
+func (sl *Selection) SceneBBox() mat32.Box2 {
+	bb := mat32.NewEmptyBox2()
+	for _, n := range sl.Nodes {
+		bb.ExpandByBox(n.AsSVGNode().SceneBBox())
+	}
+	return bb
+}
+
+// WinBBox returns the union of the window bounding boxes of the selected
+// nodes, as of the last render
+func (sl *Selection) WinBBox() image.Rectangle {
+	var bb image.Rectangle
+	for _, n := range sl.Nodes {
+		nb := n.AsNode2D()
+		nb.BBoxMu.RLock()
+		bb = bb.Union(nb.WinBBox)
+		nb.BBoxMu.RUnlock()
+	}
+	return bb
+}
+
+// ComposeSceneXForm composes given transform, in scene coordinates, onto
+// the transforms of all the selected nodes -- see NodeBase.ComposeSceneXForm
+func (sl *Selection) ComposeSceneXForm(xf mat32.Mat2) {
+	for _, n := range sl.Nodes {
+		n.AsSVGNode().ComposeSceneXForm(xf)
+	}
+}
+
+// HandleRect returns the window rectangle of given handle for a selection
+// box of given window bounding box
+func (sl *Selection) HandleRect(h SelHandles, bb image.Rectangle) image.Rectangle {
+	mid := bb.Min.Add(bb.Max).Div(2)
+	var c image.Point
+	switch h {
+	case SelHandleTopLeft:
+		c = bb.Min
+	case SelHandleTop:
+		c = image.Point{mid.X, bb.Min.Y}
+	case SelHandleTopRight:
+		c = image.Point{bb.Max.X, bb.Min.Y}
+	case SelHandleRight:
+		c = image.Point{bb.Max.X, mid.Y}
+	case SelHandleBottomRight:
+		c = bb.Max
+	case SelHandleBottom:
+		c = image.Point{mid.X, bb.Max.Y}
+	case SelHandleBottomLeft:
+		c = image.Point{bb.Min.X, bb.Max.Y}
+	case SelHandleLeft:
+		c = image.Point{bb.Min.X, mid.Y}
+	}
+	hsz := SelectionHandleSize / 2
+	return image.Rect(c.X-hsz, c.Y-hsz, c.X-hsz+SelectionHandleSize, c.Y-hsz+SelectionHandleSize)
+}
+
+// HandleAt returns the handle of the selection box at given window
+// position, and false if there is none there
+func (sl *Selection) HandleAt(pt image.Point) (SelHandles, bool) {
+	if sl.Len() == 0 {
+		return SelHandlesN, false
+	}
+	bb := sl.WinBBox()
+	for h := SelHandleTopLeft; h < SelHandlesN; h++ {
+		if pt.In(sl.HandleRect(h, bb)) {
+			return h, true
+		}
+	}
+	return SelHandlesN, false
+}
+
+// SelectionSpriteNameFor returns the sprite name of given handle, or of
+// the selection box for SelHandlesN
+func SelectionSpriteNameFor(h SelHandles) string {
+	if h == SelHandlesN {
+		return SelectionSpriteName + "-box"
+	}
+	return SelectionSpriteName + "-" + h.String()
+}
+
+// Render renders the selection box and handles as sprites in given window,
+// or removes them if nothing is selected
+func (sl *Selection) Render(win *gi.Window) {
+	if win == nil {
+		return
+	}
+	sl.Prune()
+	if sl.Len() == 0 {
+		sl.ClearSprites(win)
+		return
+	}
+	bb := sl.WinBBox()
+	bc := gi.Prefs.Colors.Select.Darker(40)
+	for h := SelHandleTopLeft; h <= SelHandlesN; h++ {
+		r := bb
+		if h < SelHandlesN {
+			r = sl.HandleRect(h, bb)
+		}
+		sz := r.Size()
+		if sz.X < 1 {
+			sz.X = 1
+		}
+		if sz.Y < 1 {
+			sz.Y = 1
+		}
+		nm := SelectionSpriteNameFor(h)
+		sp, ok := win.SpriteByName(nm)
+		if !ok {
+			sp = gi.NewSprite(nm, sz, r.Min)
+			win.AddSprite(sp)
+			win.ActivateSprite(nm)
+		}
+		sp.SetSize(sz)
+		sp.Geom.Pos = r.Min
+		ibox := sp.Pixels.Bounds()
+		draw.Draw(sp.Pixels, ibox, &image.Uniform{bc}, image.ZP, draw.Src)
+		ibox = ibox.Inset(1)
+		if !ibox.Empty() {
+			fc := color.Color(color.White)
+			if h == SelHandlesN {
+				fc = color.Transparent // box is just an outline
+			}
+			draw.Draw(sp.Pixels, ibox, &image.Uniform{fc}, image.ZP, draw.Src)
+		}
+	}
+	sl.Shown = true
+	win.Sprites.Modified = true
+}
+
+// ClearSprites removes the selection sprites from given window
+func (sl *Selection) ClearSprites(win *gi.Window) {
+	if !sl.Shown || win == nil {
+		return
+	}
+	sl.Shown = false
+	for h := SelHandleTopLeft; h <= SelHandlesN; h++ {
+		win.DeleteSprite(SelectionSpriteNameFor(h))
+	}
+	win.Sprites.Modified = true
+}
+
+// UpdateSelection renders the current Selection in the window overlay
+func (sv *SVG) UpdateSelection() {
+	win := sv.ParentWindow()
+	if win == nil {
+		return
+	}
+	sv.Selection.Render(win)
+	win.UpdateSig()
+}
+
+// Select replaces the selection with given nodes, and updates the display
+func (sv *SVG) Select(nodes ...NodeSVG) {
+	sv.Selection.Set(nodes...)
+	sv.UpdateSelection()
+}
+
+// AddSelect adds given nodes to the selection, and updates the display
+func (sv *SVG) AddSelect(nodes ...NodeSVG) {
+	sv.Selection.Add(nodes...)
+	sv.UpdateSelection()
+}
+
+// ToggleSelect toggles the selection of given node, and updates the display
+func (sv *SVG) ToggleSelect(n NodeSVG) {
+	sv.Selection.Toggle(n)
+	sv.UpdateSelection()
+}
+
+// ClearSelect unselects all nodes, and updates the display
+func (sv *SVG) ClearSelect() {
+	sv.Selection.Clear()
+	sv.UpdateSelection()
+}
+
+// SelectAtPoint selects the topmost node at given window position, as a
+// top-level node if toplevel is true (see TopLevelNode), replacing the
+// selection, or toggling the node if toggle is true.  Returns the node,
+// or nil if there is none there, in which case the selection is cleared
+// unless toggling.
+func (sv *SVG) SelectAtPoint(pt image.Point, toplevel, toggle bool) NodeSVG {
+	n := sv.NodeAtPoint(pt)
+	if n != nil && toplevel {
+		n = sv.TopLevelNode(n)
+	}
+	switch {
+	case n == nil && toggle:
+	case n == nil:
+		sv.ClearSelect()
+	case toggle:
+		sv.ToggleSelect(n)
+	default:
+		sv.Select(n)
+	}
+	return n
+}
+
+// XFormSelection composes given transform, in scene coordinates, onto the
+// transforms of all the selected nodes, and re-renders
+func (sv *SVG) XFormSelection(xf mat32.Mat2) {
+	if sv.Selection.Len() == 0 {
+		return
+	}
+	updt := sv.UpdateStart()
+	sv.Selection.ComposeSceneXForm(xf)
+	sv.SetFullReRender()
+	sv.UpdateEnd(updt)
+}
@@ -40,6 +40,8 @@ type SVG struct {
 	Desc       string           `xml:"desc" desc:"the description of the svg"`
 	DefIdxs    map[string]int   `view:"-" json:"-" xml:"-" desc:"map of def names to index -- uses starting index to find element -- always updated after each search"`
 	UniqueIds  map[int]struct{} `view:"-" json:"-" xml:"-" desc:"map of unique numeric ids for all elements -- used for allocating new unique id numbers, appended to end of elements -- see NewUniqueId, GatherIds"`
+	ZoomXForm  mat32.Mat2       `view:"-" json:"-" xml:"-" desc:"transform applied on top of the drawing transform, set by a gi.ZoomPanArea -- none if zero"`
+	Selection  Selection        `copy:"-" view:"-" json:"-" xml:"-" desc:"the currently selected nodes, shown with a box and handles in the window overlay"`
 }
 
 var KiT_SVG = kit.Types.AddType(&SVG{}, SVGProps)
@@ -105,6 +107,20 @@ func (sv *SVG) SetDPIXForm() {
 	pc.XForm = mat32.Scale2D(dpisc, dpisc)
 }
 
+// SetZoomXForm sets the ZoomXForm, for the gi.Zoomable interface
+func (sv *SVG) SetZoomXForm(xf mat32.Mat2) {
+	sv.ZoomXForm = xf
+}
+
+// ZoomContentSize returns the size of the ViewBox, or of the viewport if
+// not set, for the gi.Zoomable interface
+func (sv *SVG) ZoomContentSize() mat32.Vec2 {
+	if sv.ViewBox.Size != mat32.Vec2Zero {
+		return sv.ViewBox.Size
+	}
+	return mat32.NewVec2FmPoint(sv.Geom.Size)
+}
+
 func (sv *SVG) Init2D() {
 	sv.Viewport2D.Init2D()
 	sv.SetFlag(int(gi.VpFlagSVG)) // we are an svg type
@@ -226,12 +242,19 @@ func (sv *SVG) Render2D() {
 		if sv.Norm {
 			sv.SetNormXForm()
 		}
-		rs.PushXForm(sv.Pnt.XForm)
+		xf := sv.Pnt.XForm
+		if sv.ZoomXForm != (mat32.Mat2{}) {
+			xf = xf.Mul(sv.ZoomXForm)
+		}
+		rs.PushXForm(xf)
 		sv.Render2DChildren() // we must do children first, then us!
 		sv.PopBounds()
 		rs.PopXForm()
 		sv.RenderViewport2D() // update our parent image
 		sv.ClearFlag(int(Rendering))
+		if sv.Selection.Len() > 0 || sv.Selection.Shown { // follow the nodes
+			sv.Selection.Render(sv.ParentWindow())
+		}
 	}
 }
 
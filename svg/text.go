@@ -112,8 +112,15 @@ func (g *Text) TextBBox() mat32.Box2 {
 	sr.Render[0].Face = pc.FontStyle.Face.Face // upscale
 
 	pos := g.Pos
+	vert := pc.TextStyle.IsVertical()
 
-	if gist.IsAlignMiddle(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorMiddle {
+	if vert { // column centered on the position, anchored along it
+		if gist.IsAlignMiddle(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorMiddle {
+			pos.Y -= g.TextRender.Size.Y * .5
+		} else if gist.IsAlignEnd(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorEnd {
+			pos.Y -= g.TextRender.Size.Y
+		}
+	} else if gist.IsAlignMiddle(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorMiddle {
 		pos.X -= g.TextRender.Size.X * .5
 	} else if gist.IsAlignEnd(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorEnd {
 		pos.X -= g.TextRender.Size.X
@@ -162,7 +169,11 @@ func (g *Text) TextBBox() mat32.Box2 {
 	}
 	bb := mat32.Box2{}
 	bb.Min = pos
-	bb.Min.Y -= maxh * .8 // baseline adjust
+	if vert {
+		bb.Min.X -= g.TextRender.Size.X * .5
+	} else {
+		bb.Min.Y -= maxh * .8 // baseline adjust
+	}
 	bb.Max = bb.Min.Add(g.TextRender.Size)
 	return bb
 }
@@ -193,7 +204,14 @@ func (g *Text) RenderText() {
 	// todo: align styling only affects multi-line text and is about how tspan is arranged within
 	// the overall text block.
 
-	if gist.IsAlignMiddle(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorMiddle {
+	vert := pc.TextStyle.IsVertical()
+	if vert { // column centered on the position, anchored along it
+		if gist.IsAlignMiddle(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorMiddle {
+			pos.Y -= g.TextRender.Size.Y * .5
+		} else if gist.IsAlignEnd(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorEnd {
+			pos.Y -= g.TextRender.Size.Y
+		}
+	} else if gist.IsAlignMiddle(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorMiddle {
 		pos.X -= g.TextRender.Size.X * .5
 	} else if gist.IsAlignEnd(pc.TextStyle.Align) || pc.TextStyle.Anchor == gist.AnchorEnd {
 		pos.X -= g.TextRender.Size.X
@@ -251,12 +269,22 @@ func (g *Text) RenderText() {
 		sz.SetMax(mxp)
 		maxh = mat32.Max(maxh, sr.Render[i].Size.Y)
 	}
-	g.TextRender.Size = sz
 	g.LastPos = pos
 	g.LastBBox.Min = pos
-	g.LastBBox.Min.Y -= maxh * .8 // baseline adjust
+	if vert {
+		g.LastBBox.Min.X -= g.TextRender.Size.X * .5
+	} else {
+		g.TextRender.Size = sz
+		g.LastBBox.Min.Y -= maxh * .8 // baseline adjust
+	}
 	g.LastBBox.Max = g.LastBBox.Min.Add(g.TextRender.Size)
-	g.TextRender.Render(rs, pos)
+	stroke := pc.StrokeStyle.On && !pc.StrokeStyle.Color.IsNil()
+	if !pc.FillStyle.Color.IsNil() || !stroke { // fill="none" for outlined text
+		g.TextRender.Render(rs, pos)
+	}
+	if stroke {
+		g.TextRender.RenderStroke(rs, pc, pos)
+	}
 	g.ComputeBBoxSVG()
 }
 
@@ -50,10 +50,12 @@ const (
 	// Dp = density-independent pixels -- 1dp = 1/160th of 1in
 	Dp
 
-	// Pct = percentage of surrounding contextual element
+	// Pct = percentage of surrounding contextual element -- for sizes, this
+	// is the allocated size of the parent, resolved during layout
 	Pct
 
-	// Rem = font size of the root element -- defaults to 12pt scaled by DPI factor
+	// Rem = font size of the root element (the window's top viewport) --
+	// defaults to 12pt scaled by DPI factor
 	Rem
 
 	// Em = font size of the element -- fallback to 12pt by default
@@ -213,7 +215,7 @@ func (uc *Context) ToDotsFactor(un Units) float32 {
 	}
 	switch un {
 	case Pct:
-		return 0.01 * uc.ElW // todo: height should be in terms of Elh.. but width is much more common
+		return 0.01 * uc.ElW // use ToDotsFactorY for vertical values
 	case Em:
 		return uc.FontEm
 	case Ex:
@@ -227,9 +229,9 @@ func (uc *Context) ToDotsFactor(un Units) float32 {
 	case Vh:
 		return 0.01 * uc.VpH
 	case Vmin:
-		return kit.Min32(uc.VpW, uc.VpH)
+		return 0.01 * kit.Min32(uc.VpW, uc.VpH)
 	case Vmax:
-		return kit.Max32(uc.VpW, uc.VpH)
+		return 0.01 * kit.Max32(uc.VpW, uc.VpH)
 	case Cm:
 		return uc.DPI / CmPerInch
 	case Mm:
@@ -252,11 +254,30 @@ func (uc *Context) ToDotsFactor(un Units) float32 {
 	return uc.DPI
 }
 
+// ToDotsFactorY returns factor needed to convert given unit into raw pixels
+// (dots in DPI) for a vertical value, where Pct is relative to the height of
+// the contextual element (ElH) instead of the width
+func (uc *Context) ToDotsFactorY(un Units) float32 {
+	if un == Pct {
+		if uc.DPI == 0 {
+			uc.Defaults()
+		}
+		return 0.01 * uc.ElH
+	}
+	return uc.ToDotsFactor(un)
+}
+
 // ToDots converts value in given units into raw display pixels (dots in DPI)
 func (uc *Context) ToDots(val float32, un Units) float32 {
 	return val * uc.ToDotsFactor(un)
 }
 
+// ToDotsY converts vertical value in given units into raw display pixels
+// (dots in DPI) -- see ToDotsFactorY
+func (uc *Context) ToDotsY(val float32, un Units) float32 {
+	return val * uc.ToDotsFactorY(un)
+}
+
 // PxToDots just converts a value from pixels to dots
 func (uc *Context) PxToDots(val float32) float32 {
 	return val * uc.ToDotsFactor(Px)
@@ -318,6 +339,21 @@ func NewPct(val float32) Value {
 	return Value{val, Pct, 0.0}
 }
 
+// NewRem creates a new Rem value
+func NewRem(val float32) Value {
+	return Value{val, Rem, 0.0}
+}
+
+// NewVw creates a new Vw value
+func NewVw(val float32) Value {
+	return Value{val, Vw, 0.0}
+}
+
+// NewVh creates a new Vh value
+func NewVh(val float32) Value {
+	return Value{val, Vh, 0.0}
+}
+
 // NewDp creates a new Dp value
 func NewDp(val float32) Value {
 	return Value{val, Dp, 0.0}
@@ -370,6 +406,24 @@ func (v *Value) SetPct(val float32) {
 	v.Un = Pct
 }
 
+// SetRem sets value in Rem
+func (v *Value) SetRem(val float32) {
+	v.Val = val
+	v.Un = Rem
+}
+
+// SetVw sets value in Vw
+func (v *Value) SetVw(val float32) {
+	v.Val = val
+	v.Un = Vw
+}
+
+// SetVh sets value in Vh
+func (v *Value) SetVh(val float32) {
+	v.Val = val
+	v.Un = Vh
+}
+
 // SetDp sets value in Dp
 func (v *Value) SetDp(val float32) {
 	v.Val = val
@@ -390,6 +444,14 @@ func (v *Value) ToDots(ctxt *Context) float32 {
 	return v.Dots
 }
 
+// ToDotsY converts vertical value to raw display pixels (dots as in DPI),
+// setting also the Dots field -- Pct is relative to the height of the
+// contextual element, instead of the width as in ToDots
+func (v *Value) ToDotsY(ctxt *Context) float32 {
+	v.Dots = ctxt.ToDotsY(v.Val, v.Un)
+	return v.Dots
+}
+
 // ToDotsFixed converts value to raw display pixels (dots in DPI) in
 // fixed-point 26.6 format for rendering
 func (v *Value) ToDotsFixed(ctxt *Context) fixed.Int26_6 {
@@ -31,3 +31,32 @@ func TestValCvt(t *testing.T) {
 		t.Errorf("strings don't match: %v != %v\n", s1, s2)
 	}
 }
+
+func TestRelUnits(t *testing.T) {
+	var ctxt Context
+	ctxt.Defaults()
+	ctxt.SetSizes(1000, 500, 200, 100)
+	if d := ctxt.ToDots(50, Pct); d != 100 {
+		t.Errorf("pct width: %v != 100", d)
+	}
+	pv := NewPct(50)
+	if d := pv.ToDotsY(&ctxt); d != 50 {
+		t.Errorf("pct height: %v != 50", d)
+	}
+	if d := ctxt.ToDots(10, Vw); d != 100 {
+		t.Errorf("vw: %v != 100", d)
+	}
+	if d := ctxt.ToDots(10, Vh); d != 50 {
+		t.Errorf("vh: %v != 50", d)
+	}
+	if d := ctxt.ToDots(10, Vmin); d != 50 {
+		t.Errorf("vmin: %v != 50", d)
+	}
+	rv := StringToValue("2rem")
+	if rv.Un != Rem || rv.Val != 2 {
+		t.Errorf("rem parse: %v", rv)
+	}
+	if d := rv.ToDots(&ctxt); d != 2*ctxt.FontRem {
+		t.Errorf("rem: %v != %v", d, 2*ctxt.FontRem)
+	}
+}